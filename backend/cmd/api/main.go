@@ -0,0 +1,668 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/gofiber/swagger"
+	_ "github.com/repoareta/pedeve-dms-app/backend/docs" // docs is generated by Swag CLI
+	"github.com/repoareta/pedeve-dms-app/backend/internal/delivery/http"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/audit"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/config"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/encryption"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/kvstore"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/observability"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/seed"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/middleware"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/usecase"
+	"go.uber.org/zap"
+)
+
+// @title           Pedeve App Backend API
+// @version         1.0
+// @description     Pedeve App Backend API dengan struktur Clean Architecture
+// @termsOfService  http://swagger.io/terms/
+
+// @contact.name   API Support
+// @contact.url    http://www.swagger.io/support
+// @contact.email  support@swagger.io
+
+// @license.name  Apache 2.0
+// @license.url   http://www.apache.org/licenses/LICENSE-2.0.html
+
+// @host      localhost:8080
+// @BasePath  /api/v1
+
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+// @description                 Type "Bearer" followed by a space and JWT token. Atau menggunakan httpOnly cookie (auth_token) untuk keamanan yang lebih baik.
+
+// @note                        Catatan Teknis API:
+// @note                        1. Framework: Menggunakan Fiber v2 dengan performa tinggi (fasthttp)
+// @note                        2. Architecture: Clean Architecture dengan struktur cmd/, internal/
+// @note                        3. Authentication: JWT token dengan httpOnly cookie untuk mencegah XSS attacks
+// @note                        4. Rate Limiting: General API (100 req/s, burst: 50), Auth endpoints (5 req/min, burst: 5)
+// @note                        5. Security Headers: X-Content-Type-Options, X-XSS-Protection, CSP, HSTS
+// @note                        6. CSRF Protection: Double-submit cookie pattern untuk state-changing requests
+// @note                        7. Audit Logging: Semua aksi user dan error teknis dicatat dalam audit log
+// @note                        8. 2FA Support: TOTP-based 2FA dengan backup codes untuk recovery
+// @note                        9. Error Handling: Centralized error logging dengan stack trace untuk debugging
+// @note                        10. Database: PostgreSQL/SQLite dengan GORM ORM, migrasi schema berversi (cmd/migrate)
+// @note                        11. CORS: Configured untuk localhost:5173 (Vite dev) dan localhost:3000
+func main() {
+	// Inisialisasi zap logger
+	logger.InitLogger()
+	defer logger.Sync()
+	zapLog := logger.GetLogger()
+
+	// Inisialisasi tracing OpenTelemetry (aktif hanya jika OTEL_EXPORTER_OTLP_ENDPOINT diset)
+	shutdownTracing, err := observability.InitTracing(context.Background())
+	if err != nil {
+		zapLog.Warn("Failed to initialize OpenTelemetry tracing, continuing without tracing", zap.Error(err))
+	}
+
+	// Inisialisasi database
+	database.InitDB()
+
+	// Load configuration (from Vault or environment)
+	if _, err := config.LoadConfig(); err != nil {
+		zapLog.Warn("Failed to load config, using defaults", zap.Error(err))
+	}
+
+	// Inisialisasi encryption
+	if err := encryption.InitEncryption(); err != nil {
+		zapLog.Fatal("Failed to initialize encryption", zap.Error(err))
+	}
+
+	// Inisialisasi key-value store bersama (Redis jika REDIS_URL diset) untuk rate limit, CSRF, dan cache
+	if err := kvstore.Init(); err != nil {
+		zapLog.Fatal("Failed to initialize key-value store", zap.Error(err))
+	}
+
+	// Inisialisasi rate limiters dari config
+	middleware.InitRateLimiters()
+
+	// Inisialisasi audit logger
+	audit.InitAuditLogger()
+
+	// Mulai cleanup audit logs (retention policy)
+	usecase.StartAuditLogCleanup()
+
+	// Mulai cleanup notifikasi lama (retention policy: 90 hari default)
+	usecase.StartNotificationCleanup()
+
+	// Mulai scheduler untuk check expiring documents dan director terms (sekali sehari)
+	usecase.StartNotificationScheduler()
+
+	// Pulihkan job import financial report yang terhenti karena restart
+	financialImportUseCase := usecase.NewFinancialImportUseCase()
+	if recovered, err := financialImportUseCase.RecoverInterruptedJobs(); err != nil {
+		zapLog.Warn("Failed to recover interrupted financial import jobs", zap.Error(err))
+	} else if recovered > 0 {
+		zapLog.Info("Recovered interrupted financial import jobs", zap.Int64("count", recovered))
+	}
+
+	// Pulihkan job export data company yang terhenti karena restart, lalu mulai cleanup arsip kedaluwarsa
+	companyExportUseCase := usecase.NewCompanyExportUseCase()
+	if recovered, err := companyExportUseCase.RecoverInterruptedJobs(); err != nil {
+		zapLog.Warn("Failed to recover interrupted company export jobs", zap.Error(err))
+	} else if recovered > 0 {
+		zapLog.Info("Recovered interrupted company export jobs", zap.Int64("count", recovered))
+	}
+	usecase.StartCompanyExportCleanup()
+
+	// Mulai evaluasi retensi dokumen harian (arsipkan dokumen yang masa retensinya habis untuk direview)
+	usecase.StartDocumentDispositionScheduler()
+
+	// Mulai purge otomatis recycle bin dokumen (DOCUMENT_TRASH_RETENTION_DAYS, default 30 hari)
+	usecase.StartDocumentTrashPurge()
+
+	// Seed roles, superadmin, and default administrator user
+	seed.SeedAll()
+
+	// Setup Fiber app
+	app := fiber.New(fiber.Config{
+		BodyLimit: 200 * 1024 * 1024, // 200MB untuk upload dokumen (mengakomodir file dokumen besar seperti PDF)
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			// Custom error handler untuk logging error ke audit log
+			code := fiber.StatusInternalServerError
+			if e, ok := err.(*fiber.Error); ok {
+				code = e.Code
+			}
+
+			// Handle body limit exceeded dengan pesan yang lebih jelas
+			if code == fiber.StatusRequestEntityTooLarge || strings.Contains(err.Error(), "request entity too large") {
+				return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+					"error":   "file_too_large",
+					"message": "File terlalu besar. Batasan: File gambar maksimal 10MB, file dokumen (.docx, .xlsx, .xls, .pptx, .ppt, .pdf) maksimal 200MB.",
+				})
+			}
+
+			// Log error ke audit log (dengan detail lengkap untuk debugging)
+			details := map[string]interface{}{
+				"status_code": code,
+				"error":       err.Error(),
+			}
+			middleware.LogTechnicalError(err, c, details)
+
+			// Sanitize error message untuk production
+			// Di production, jangan expose error detail ke client
+			env := os.Getenv("ENV")
+			isProduction := env == "production" || env == "prod"
+
+			var errorMessage string
+			if isProduction {
+				// Di production, gunakan generic message
+				errorMessage = "An unexpected error occurred. Please try again later."
+			} else {
+				// Di development, tampilkan error detail untuk debugging
+				errorMessage = err.Error()
+			}
+
+			return c.Status(code).JSON(fiber.Map{
+				"error":   "internal_error",
+				"message": errorMessage,
+			})
+		},
+	})
+
+	// Middleware global
+	app.Use(middleware.ObservabilityMiddleware)     // Metric RED per route dan span server (paling luar agar panic yang di-recover tetap tercatat)
+	app.Use(middleware.RecoverMiddleware)           // Custom recover middleware dengan error logging (harus di awal)
+	app.Use(middleware.ZapLoggerMiddleware(zapLog)) // Zap logger middleware untuk HTTP requests
+	app.Use(requestid.New())                        // Request ID middleware
+	app.Use(middleware.SecurityHeadersMiddleware)   // Header keamanan
+	app.Use(middleware.ErrorHandlerMiddleware)      // Log error teknis ke audit log
+
+	// Rate limiting: Hanya diterapkan di endpoint tertentu, bukan global
+	// - Auth endpoints: AuthRateLimitMiddleware (20 req/min untuk prevent brute force)
+	// - Sensitive operations: StrictRateLimitMiddleware (2000 req/min, burst 1000 untuk file management)
+	//   * Mendukung batch upload 20-30 file sekaligus
+	//   * Cukup longgar untuk aktivitas upload/delete yang intensif
+	//   * IP-based rate limiting (fleksibel untuk concurrent user <10)
+	// - GET endpoints: Tidak perlu rate limit (atau sangat longgar jika diperlukan)
+	//
+	// Rate limiting aktif di semua environment (development & production) untuk testing
+	// Bisa disable sepenuhnya dengan DISABLE_RATE_LIMIT=true jika diperlukan
+	env := os.Getenv("ENV")
+	disableRateLimit := os.Getenv("DISABLE_RATE_LIMIT") == "true"
+
+	if disableRateLimit {
+		zapLog.Info("Rate limiting completely disabled (DISABLE_RATE_LIMIT=true)")
+	} else {
+		zapLog.Info("Rate limiting enabled (applied only to specific endpoints)",
+			zap.String("env", env),
+			zap.String("note", "Active in all environments for testing"),
+		)
+	}
+
+	// CORS dengan peningkatan keamanan
+	// Ambil CORS origin dari environment variable, fallback ke localhost untuk development
+	corsOrigin := os.Getenv("CORS_ORIGIN")
+	if corsOrigin == "" {
+		corsOrigin = "http://localhost:5173,http://localhost:3000"
+		zapLog.Info("CORS origin not set, using default localhost origins")
+	} else {
+		zapLog.Info("CORS origin configured", zap.String("origin", corsOrigin))
+	}
+
+	// Split comma-separated origins jadi slice untuk Fiber CORS
+	// Fiber CORS AllowOrigins terima comma-separated string atau slice of strings
+	// Pakai comma-separated string didukung dan akan di-parse dengan benar
+	app.Use(cors.New(cors.Config{
+		AllowOrigins:     corsOrigin, // Comma-separated string didukung oleh Fiber CORS
+		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS,PATCH",
+		AllowHeaders:     "Accept,Authorization,Content-Type,X-CSRF-Token,X-Requested-With",
+		ExposeHeaders:    "Link,X-Total-Count",
+		AllowCredentials: true,
+		MaxAge:           300,
+	}))
+
+	// Static file server untuk uploads
+	app.Static("/uploads", "./uploads")
+
+	// Routes
+	app.Get("/", indexHandler)
+	app.Get("/health", http.LivenessHandler)        // Alias liveness (kompatibilitas)
+	app.Get("/health/live", http.LivenessHandler)   // Liveness probe: proses hidup
+	app.Get("/health/ready", http.ReadinessHandler) // Readiness probe: DB, migrasi, storage, kvstore, encryption, secret manager, scheduler
+	app.Get("/metrics", http.MetricsHandler)        // Prometheus metrics (opsional dilindungi METRICS_TOKEN)
+
+	// API v1
+	api := app.Group("/api/v1")
+	api.Get("/", apiInfoHandler)
+
+	// Endpoint token CSRF (public, tidak perlu auth)
+	api.Get("/csrf-token", http.GetCSRFTokenHandler)
+
+	// Route autentikasi (public) - dengan rate limiting (bypass di development)
+	// AuthRateLimitMiddleware akan otomatis bypass di development (lihat rate_limit.go)
+	authPublic := api.Group("/auth", middleware.AuthRateLimitMiddleware)
+	authPublic.Post("/login", http.Login)
+
+	// Unduh arsip export data company lewat link sementara (public: token di URL adalah otorisasinya)
+	companyExportHandler := http.NewCompanyExportHandler(companyExportUseCase, usecase.NewCompanyUseCase())
+	api.Get("/company-exports/download/:token", middleware.StrictRateLimitMiddleware, companyExportHandler.DownloadCompanyExport)
+
+	// Route yang dilindungi (memerlukan JWT)
+	protected := api.Group("", middleware.JWTAuthMiddleware, middleware.CSRFMiddleware)
+
+	// Route untuk operasi sensitif (upload, delete) dengan rate limiting lebih ketat
+	// Rate limit hanya diterapkan di production (lihat rate_limit.go)
+	sensitiveOps := api.Group("", middleware.JWTAuthMiddleware, middleware.CSRFMiddleware, middleware.StrictRateLimitMiddleware)
+
+	// Endpoint profil dan logout user
+	protected.Get("/auth/profile", http.GetProfile)
+	protected.Put("/auth/profile/email", http.UpdateProfileEmail)
+	protected.Put("/auth/profile/password", http.ChangePassword)
+	protected.Post("/auth/logout", http.Logout)
+
+	// Route 2FA (Two-Factor Authentication)
+	protected.Post("/auth/2fa/generate", http.Generate2FASecret)
+	protected.Post("/auth/2fa/verify", http.Verify2FA)
+	protected.Post("/auth/2fa/disable", http.Disable2FA)
+	protected.Get("/auth/2fa/status", http.Get2FAStatus)
+
+	// Route detail health check (superadmin/administrator)
+	protected.Get("/health/details", http.GetHealthDetailsHandler)
+
+	// Route audit logs
+	protected.Get("/audit-logs", http.GetAuditLogsHandler)
+	protected.Get("/audit-logs/stats", http.GetAuditLogStatsHandler)
+	protected.Get("/audit-logs/sinks/health", http.GetAuditSinkHealthHandler)                   // Status pengiriman ke SIEM (lag, DLQ)
+	protected.Post("/audit-logs/sinks/dead-letters/replay", http.ReplayAuditDeadLettersHandler) // Kirim ulang event yang gagal
+	protected.Get("/user-activity-logs", http.GetUserActivityLogsHandler)                       // Permanent logs untuk data penting
+	protected.Get("/user-activity-logs/entity/:resource/:id", http.GetEntityHistoryHandler)     // Riwayat perubahan per field untuk satu entity
+
+	// Route documents (dilindungi)
+	// Catatan: Route yang lebih spesifik harus didefinisikan sebelum route dengan parameter
+	documentHandler := http.NewDocumentHandler(usecase.NewDocumentUseCase())
+	protected.Get("/documents/folders", documentHandler.ListFolders)
+	protected.Post("/documents/folders", documentHandler.CreateFolder)
+	protected.Put("/documents/folders/:id", documentHandler.UpdateFolder)
+	// Delete operations - sensitive, use strict rate limit
+	sensitiveOps.Delete("/documents/folders/:id", documentHandler.DeleteFolder)
+	// Upload documents - sensitive operation, use strict rate limit (IP-based)
+	sensitiveOps.Post("/documents/upload", documentHandler.UploadDocument)
+	protected.Get("/documents", documentHandler.ListDocuments)
+	protected.Get("/documents/summary", documentHandler.DocumentSummary) // ringkasan storage, pastikan sebelum :id
+	// Recycle bin dokumen/folder - pastikan sebelum :id
+	documentTrashHandler := http.NewDocumentTrashHandler(usecase.NewDocumentTrashUseCase(), usecase.NewCompanyUseCase())
+	protected.Get("/documents/trash", documentTrashHandler.ListTrash)
+	sensitiveOps.Post("/documents/trash/:type/:id/restore", documentTrashHandler.RestoreTrashItem)
+	sensitiveOps.Delete("/documents/trash/:type/:id", documentTrashHandler.PurgeTrashItem)
+	protected.Get("/documents/:id", documentHandler.GetDocument)
+	protected.Put("/documents/:id", documentHandler.UpdateDocument)
+	sensitiveOps.Delete("/documents/:id", documentHandler.DeleteDocument)
+
+	// Route document types (dilindungi)
+	documentTypeHandler := http.NewDocumentTypeHandler(usecase.NewDocumentTypeUseCase())
+	protected.Get("/document-types", documentTypeHandler.GetAllDocumentTypes)
+	protected.Post("/document-types", documentTypeHandler.CreateDocumentType)
+	protected.Put("/document-types/:id", documentTypeHandler.UpdateDocumentType)
+	sensitiveOps.Delete("/document-types/:id", documentTypeHandler.DeleteDocumentType)
+
+	// Retensi dokumen, legal hold, dan disposisi (superadmin/administrator)
+	documentRetentionHandler := http.NewDocumentRetentionHandler(usecase.NewDocumentRetentionUseCase())
+	sensitiveOps.Put("/document-types/:id/retention", documentRetentionHandler.SetRetentionSchedule)
+	protected.Get("/legal-holds", documentRetentionHandler.ListLegalHolds)
+	sensitiveOps.Post("/legal-holds", documentRetentionHandler.CreateLegalHold)
+	sensitiveOps.Post("/legal-holds/:id/release", documentRetentionHandler.ReleaseLegalHold)
+	protected.Get("/document-dispositions", documentRetentionHandler.ListDispositions)
+	protected.Get("/document-dispositions/report", documentRetentionHandler.DownloadDispositionReport)
+	sensitiveOps.Post("/document-dispositions/run", documentRetentionHandler.RunDisposition)
+	sensitiveOps.Post("/document-dispositions/:id/approve", documentRetentionHandler.ApproveDisposition)
+	sensitiveOps.Post("/document-dispositions/:id/retain", documentRetentionHandler.RetainDisposition)
+
+	// Shareholder Types routes
+	shareholderTypeHandler := http.NewShareholderTypeHandler(usecase.NewShareholderTypeUseCase())
+	protected.Get("/shareholder-types", shareholderTypeHandler.GetAllShareholderTypes)
+	protected.Post("/shareholder-types", shareholderTypeHandler.CreateShareholderType)
+	protected.Put("/shareholder-types/:id", shareholderTypeHandler.UpdateShareholderType)
+	sensitiveOps.Delete("/shareholder-types/:id", shareholderTypeHandler.DeleteShareholderType)
+
+	// Director Positions routes
+	directorPositionHandler := http.NewDirectorPositionHandler(usecase.NewDirectorPositionUseCase())
+	protected.Get("/director-positions", directorPositionHandler.GetAllDirectorPositions)
+	protected.Post("/director-positions", directorPositionHandler.CreateDirectorPosition)
+	protected.Put("/director-positions/:id", directorPositionHandler.UpdateDirectorPosition)
+	sensitiveOps.Delete("/director-positions/:id", directorPositionHandler.DeleteDirectorPosition)
+
+	// Route Notifications (dilindungi)
+	notificationHandler := http.NewNotificationHandler(usecase.NewNotificationUseCase())
+	protected.Get("/notifications", notificationHandler.GetNotifications)
+	protected.Get("/notifications/inbox", notificationHandler.GetNotificationsWithFilters)
+	protected.Get("/notifications/stream", notificationHandler.StreamNotifications)
+	protected.Get("/notifications/unread-count", notificationHandler.GetUnreadCount)
+	protected.Put("/notifications/:id/read", notificationHandler.MarkAsRead)
+	protected.Put("/notifications/read-all", notificationHandler.MarkAllAsRead)
+	sensitiveOps.Delete("/notifications/delete-all", notificationHandler.DeleteAllNotifications)
+
+	// Route Notification Settings (dilindungi)
+	notificationSettingsHandler := http.NewNotificationSettingsHandler(usecase.NewNotificationSettingsUseCase())
+	protected.Get("/notification-settings", notificationSettingsHandler.GetSettings)
+	protected.Put("/notification-settings", notificationSettingsHandler.UpdateSettings)
+
+	// Route notification preferences (override user & default company yang dipakai scheduler)
+	notificationPreferenceHandler := http.NewNotificationPreferenceHandler(usecase.NewNotificationPreferenceUseCase(), usecase.NewCompanyUseCase())
+	protected.Get("/notification-preferences", notificationPreferenceHandler.GetMyPreferences)
+	protected.Put("/notification-preferences", notificationPreferenceHandler.UpdateMyPreferences)
+	protected.Get("/companies/:id/notification-preferences", notificationPreferenceHandler.GetCompanyPreferences)
+	protected.Put("/companies/:id/notification-preferences", notificationPreferenceHandler.UpdateCompanyPreferences)
+
+	// Notification escalation routes (eskalasi notifikasi expired ke admin company dan parent company)
+	notificationEscalationHandler := http.NewNotificationEscalationHandler(usecase.NewNotificationEscalationUseCase(), usecase.NewCompanyUseCase())
+	protected.Get("/notification-escalations", notificationEscalationHandler.ListEscalations)
+	protected.Get("/notification-escalations/:id", notificationEscalationHandler.GetEscalation)
+	protected.Post("/notification-escalations/:id/resolve", notificationEscalationHandler.ResolveEscalation)
+
+	// Director term routes (riwayat masa jabatan, perpanjangan, jabatan wajib, susunan pengurus)
+	directorTermHandler := http.NewDirectorTermHandler(usecase.NewDirectorTermUseCase(), usecase.NewCompanyUseCase())
+	protected.Get("/directors/:id/terms", directorTermHandler.GetDirectorTerms)
+	protected.Post("/directors/:id/renew", directorTermHandler.RenewDirectorTerm)
+	protected.Put("/director-terms/:id/resolution", directorTermHandler.LinkTermResolution)
+	protected.Get("/companies/:id/position-requirements", directorTermHandler.GetPositionRequirements)
+	protected.Put("/companies/:id/position-requirements", directorTermHandler.UpdatePositionRequirements)
+	protected.Get("/companies/:id/director-vacancies", directorTermHandler.GetDirectorVacancies)
+	protected.Get("/companies/:id/board-composition", directorTermHandler.GetBoardComposition)
+
+	// Route Upload (dilindungi) - sensitive operation
+	sensitiveOps.Post("/upload/logo", http.UploadLogo)
+
+	// Route File Serving (DILINDUNGI - memerlukan authentication)
+	// Format: /api/v1/files/logos/filename.png atau /api/v1/files/documents/filename.pdf
+	// Catatan: File documents bersifat rahasia, harus dilindungi dengan authentication
+	// Frontend harus mengirim cookie (credentials) untuk mengakses file
+	protected.Get("/files/*", http.ServeFile)
+
+	// Route Company Management (dilindungi)
+	companyHandler := http.NewCompanyHandler(usecase.NewCompanyUseCase())
+	protected.Post("/companies", companyHandler.CreateCompany)
+	protected.Post("/companies/full", companyHandler.CreateCompanyFull)
+	companyImportHandler := http.NewCompanyImportHandler(usecase.NewCompanyImportUseCase(), usecase.NewCompanyUseCase())
+	protected.Get("/companies/import/template", companyImportHandler.DownloadImportTemplate) // Template import company (xlsx/csv)
+	sensitiveOps.Post("/companies/import", companyImportHandler.ImportCompanies)             // Import company bulk (dry_run untuk preview)
+	ownershipGraphHandler := http.NewOwnershipGraphHandler(usecase.NewOwnershipGraphUseCase(), usecase.NewCompanyUseCase())
+	protected.Get("/companies/ownership-graph", ownershipGraphHandler.GetOwnershipGraph)             // Graph kepemilikan grup (json/dot)
+	protected.Get("/companies/:id/effective-ownership", ownershipGraphHandler.GetEffectiveOwnership) // Kepemilikan efektif (look-through)
+	companyRestructuringHandler := http.NewCompanyRestructuringHandler(usecase.NewCompanyRestructuringUseCase(), usecase.NewCompanyUseCase())
+	sensitiveOps.Post("/companies/:id/move", companyRestructuringHandler.MoveCompany)                        // Pindah subtree ke induk baru (preview=true untuk preview)
+	sensitiveOps.Post("/companies/:id/merge", companyRestructuringHandler.MergeCompany)                      // Merge ke company penerima
+	sensitiveOps.Post("/companies/:id/dissolve", companyRestructuringHandler.DissolveCompany)                // Pembubaran company (soft, read-only)
+	protected.Get("/companies/:id/restructuring-events", companyRestructuringHandler.GetRestructuringEvents) // Riwayat restrukturisasi
+	protected.Get("/companies", companyHandler.GetAllCompanies)
+	protected.Get("/companies/:id/users", companyHandler.GetCompanyUsers)
+	protected.Get("/companies/:id/ancestors", companyHandler.GetCompanyAncestors)
+	protected.Get("/companies/:id/children", companyHandler.GetCompanyChildren)
+	// CRITICAL: More specific routes with path segments MUST come before general routes with :id parameter
+	// Fiber matches routes in order, so /companies/:id/status and /companies/:id/full must be before /companies/:id
+	protected.Put("/companies/:id/status", companyHandler.UpdateCompanyStatus) // Update company status (activate/deactivate)
+	protected.Put("/companies/:id/full", companyHandler.UpdateCompanyFull)     // Update company with full data
+	protected.Get("/companies/:id", companyHandler.GetCompany)                 // Get company by ID
+	protected.Put("/companies/:id", companyHandler.UpdateCompany)              // Update company
+	sensitiveOps.Delete("/companies/:id", companyHandler.DeleteCompany)
+
+	// Route User Management (dilindungi)
+	userManagementHandler := http.NewUserManagementHandler(usecase.NewUserManagementUseCase())
+	protected.Post("/users", userManagementHandler.CreateUser)
+	protected.Get("/users", userManagementHandler.GetAllUsers)
+	// Route spesifik harus didefinisikan sebelum route dengan parameter umum
+	protected.Patch("/users/:id/toggle-status", userManagementHandler.ToggleUserStatus)
+	protected.Post("/users/:id/reset-password", userManagementHandler.ResetUserPassword)
+	protected.Post("/users/:id/assign-company", userManagementHandler.AssignUserToCompany)
+	protected.Post("/users/:id/unassign-company", userManagementHandler.UnassignUserFromCompany)
+	protected.Get("/users/me/companies", userManagementHandler.GetMyCompanies) // Get all companies assigned to current user
+	protected.Get("/users/:id", userManagementHandler.GetUser)
+	protected.Put("/users/:id", userManagementHandler.UpdateUser)
+	sensitiveOps.Delete("/users/:id", userManagementHandler.DeleteUser)
+
+	// Route Role Management (dilindungi)
+	roleManagementHandler := http.NewRoleManagementHandler(usecase.NewRoleManagementUseCase())
+	protected.Post("/roles", roleManagementHandler.CreateRole)
+	protected.Get("/roles", roleManagementHandler.GetAllRoles)
+	protected.Get("/roles/:id", roleManagementHandler.GetRole)
+	protected.Put("/roles/:id", roleManagementHandler.UpdateRole)
+	sensitiveOps.Delete("/roles/:id", roleManagementHandler.DeleteRole)
+	// Route Role Permissions (dilindungi)
+	protected.Get("/roles/:id/permissions", roleManagementHandler.GetRolePermissions)
+	protected.Post("/roles/:id/permissions", roleManagementHandler.AssignPermissionToRole)
+	protected.Delete("/roles/:id/permissions", roleManagementHandler.RevokePermissionFromRole)
+
+	// Route Report Management (dilindungi)
+	// Catatan: Route yang lebih spesifik harus didefinisikan sebelum route dengan parameter
+	reportHandler := http.NewReportHandler(usecase.NewReportUseCase())
+	// Route spesifik harus didefinisikan sebelum route generic
+	protected.Get("/reports/template", reportHandler.DownloadTemplate)
+	protected.Post("/reports/validate", reportHandler.ValidateExcelFile)
+	sensitiveOps.Post("/reports/upload", reportHandler.UploadReports)
+	protected.Get("/reports/export/excel", reportHandler.ExportReportsExcel)
+	protected.Get("/reports/export/pdf", reportHandler.ExportReportsPDF)
+	protected.Get("/reports/company/:company_id", reportHandler.GetReportsByCompany)
+	// Route generic setelah route spesifik
+	protected.Post("/reports", reportHandler.CreateReport)
+	protected.Get("/reports", reportHandler.GetAllReports)
+	protected.Get("/reports/:id", reportHandler.GetReport)
+	protected.Put("/reports/:id", reportHandler.UpdateReport)
+	sensitiveOps.Delete("/reports/:id", reportHandler.DeleteReport)
+
+	// Financial Report routes (RKAP & Realisasi)
+	// NOTE: Routes yang lebih spesifik harus diletakkan SEBELUM routes yang lebih umum (dengan :param)
+	financialReportHandler := http.NewFinancialReportHandler(usecase.NewFinancialReportUseCase())
+
+	// Bulk upload endpoints (harus sebelum /financial-reports/:id karena lebih spesifik)
+	protected.Get("/financial-reports/bulk-upload/template", financialReportHandler.GenerateBulkUploadTemplate) // Download bulk upload template
+	sensitiveOps.Post("/financial-reports/bulk-upload/validate", financialReportHandler.ValidateBulkExcelFile)  // Validate bulk upload Excel file
+	sensitiveOps.Post("/financial-reports/bulk-upload", financialReportHandler.UploadBulkFinancialReports)      // Upload bulk financial reports
+
+	// Financial import jobs (upload ke staging, preview diff per baris, commit sebagian, error report)
+	financialImportHandler := http.NewFinancialImportHandler(financialImportUseCase, usecase.NewCompanyUseCase())
+	sensitiveOps.Post("/financial-import-jobs", financialImportHandler.CreateImportJob)
+	protected.Get("/financial-import-jobs", financialImportHandler.ListImportJobs)
+	protected.Get("/financial-import-jobs/:id", financialImportHandler.GetImportJob)
+	protected.Get("/financial-import-jobs/:id/stream", financialImportHandler.StreamImportJob)
+	protected.Get("/financial-import-jobs/:id/rows", financialImportHandler.GetImportJobRows)
+	sensitiveOps.Post("/financial-import-jobs/:id/commit", financialImportHandler.CommitImportJob)
+	protected.Get("/financial-import-jobs/:id/error-report", financialImportHandler.DownloadImportErrorReport)
+	protected.Get("/financial-reports/taxonomy", financialImportHandler.GetFinancialTaxonomy)                  // Taksonomi konsep untuk format CSV/JSON
+	protected.Get("/companies/:id/financial-account-mappings", financialImportHandler.GetAccountMappings)      // Mapping kode akun company ke taksonomi
+	sensitiveOps.Put("/companies/:id/financial-account-mappings", financialImportHandler.SetAccountMappings) // Ganti mapping kode akun company
+
+	// Export data company (data portability): arsip zip async + link unduh sementara
+	sensitiveOps.Post("/companies/:id/exports", companyExportHandler.CreateCompanyExport)
+	protected.Get("/company-exports", companyExportHandler.ListCompanyExports)
+	protected.Get("/company-exports/:id", companyExportHandler.GetCompanyExport)
+	sensitiveOps.Post("/company-exports/:id/download-link", companyExportHandler.CreateDownloadLink)
+
+	// Other specific routes (harus sebelum /financial-reports/:id)
+	protected.Get("/financial-reports/company/:company_id", financialReportHandler.GetFinancialReportsByCompanyID) // Get all financial reports for a company
+	protected.Get("/financial-reports/compare", financialReportHandler.GetComparison)                              // Get comparison RKAP vs Realisasi YTD
+	protected.Get("/financial-reports/rkap-years/:company_id", financialReportHandler.GetRKAPYearsByCompanyID)     // Get RKAP years for a company
+
+	// General CRUD routes (dengan :id parameter - harus di akhir)
+	protected.Post("/financial-reports", financialReportHandler.CreateFinancialReport)          // Create financial report
+	protected.Get("/financial-reports/:id", financialReportHandler.GetFinancialReportByID)      // Get financial report by ID
+	protected.Put("/financial-reports/:id", financialReportHandler.UpdateFinancialReport)       // Update financial report
+	sensitiveOps.Delete("/financial-reports/:id", financialReportHandler.DeleteFinancialReport) // Delete financial report
+
+	protected.Get("/companies/:company_id/performance/export/excel", financialReportHandler.ExportPerformanceExcel) // Export performance Excel
+	protected.Get("/companies/:company_id/performance/export/pdf", financialReportHandler.ExportPerformancePDF)     // Export performance PDF
+	protected.Get("/companies/:company_id/financial-reports/export", financialReportHandler.ExportFinancialReports) // Export realisasi (xlsx, csv, json)
+
+	// RKAP Perubahan (versi RKAP dengan tanggal berlaku dan approval)
+	protected.Get("/companies/:company_id/rkap/:year/versions", financialReportHandler.GetRKAPVersions)       // Daftar versi RKAP
+	protected.Post("/companies/:company_id/rkap/:year/revisions", financialReportHandler.CreateRKAPRevision) // Buat revisi draft
+	protected.Get("/companies/:company_id/rkap/:year/diff", financialReportHandler.DiffRKAPVersions)         // Diff baris per baris antar versi
+	sensitiveOps.Post("/rkap-revisions/:id/approve", financialReportHandler.ApproveRKAPRevision)             // Approve revisi draft
+	sensitiveOps.Post("/rkap-revisions/:id/reject", financialReportHandler.RejectRKAPRevision)               // Reject revisi draft
+
+	// Granularitas periode (bulanan, kuartal, semester, tahunan audited) dan phasing RKAP
+	protected.Get("/financial-reports/:id/phasing", financialReportHandler.GetRKAPPhasing)                                    // Bobot phasing RKAP
+	protected.Put("/financial-reports/:id/phasing", financialReportHandler.SetRKAPPhasing)                                    // Ganti bobot phasing RKAP
+	protected.Get("/companies/:company_id/financial-reports/period-comparison", financialReportHandler.GetPeriodComparison) // Realisasi periode vs RKAP phased
+
+	// Financial analytics routes (trend, growth, LTM, forecast vs RKAP)
+	financialAnalyticsHandler := http.NewFinancialAnalyticsHandler(usecase.NewFinancialAnalyticsUseCase(), usecase.NewCompanyUseCase())
+	protected.Get("/companies/:company_id/financial-analytics/trend", financialAnalyticsHandler.GetTrend)
+	protected.Get("/companies/:company_id/financial-analytics/forecast", financialAnalyticsHandler.GetForecast)
+	protected.Get("/companies/:company_id/financial-analytics/export", financialAnalyticsHandler.ExportAnalytics)
+
+	// Benchmark KPI lintas company (ranking, percentile, pencapaian vs RKAP)
+	financialBenchmarkHandler := http.NewFinancialBenchmarkHandler(usecase.NewFinancialBenchmarkUseCase(), usecase.NewCompanyUseCase())
+	protected.Get("/financial-benchmarks", financialBenchmarkHandler.GetBenchmark)
+	protected.Get("/financial-benchmarks/export", financialBenchmarkHandler.ExportBenchmark)
+
+	// Route Permission Management (dilindungi)
+	permissionManagementHandler := http.NewPermissionManagementHandler(usecase.NewPermissionManagementUseCase())
+	protected.Post("/permissions", permissionManagementHandler.CreatePermission)
+	protected.Get("/permissions", permissionManagementHandler.GetAllPermissions)
+	protected.Get("/permissions/:id", permissionManagementHandler.GetPermission)
+	protected.Put("/permissions/:id", permissionManagementHandler.UpdatePermission)
+	sensitiveOps.Delete("/permissions/:id", permissionManagementHandler.DeletePermission)
+
+	// Route Development (hanya superadmin)
+	developmentHandler := http.NewDevelopmentHandler(usecase.NewDevelopmentUseCase())
+	// Individual endpoints (kept for backward compatibility)
+	protected.Post("/development/reset-subsidiary", developmentHandler.ResetSubsidiaryData)
+	protected.Post("/development/run-subsidiary-seeder", developmentHandler.RunSubsidiarySeeder)
+	protected.Get("/development/check-seeder-status", developmentHandler.CheckSeederDataExists)
+	protected.Post("/development/reset-reports", developmentHandler.ResetReportData)
+	protected.Post("/development/run-report-seeder", developmentHandler.RunReportSeeder)
+	protected.Get("/development/check-report-status", developmentHandler.CheckReportDataExists)
+	// Combined endpoints (recommended)
+	protected.Post("/development/run-all-seeders", developmentHandler.RunAllSeeders)
+	protected.Post("/development/reset-all-seeded-data", developmentHandler.ResetAllSeededData)
+	protected.Get("/development/check-all-seeder-status", developmentHandler.CheckAllSeederStatus)
+	protected.Post("/development/reset-all-financial-reports", developmentHandler.ResetAllFinancialReports)
+	protected.Post("/development/create-test-notification", developmentHandler.CreateTestNotification)
+	protected.Post("/development/create-test-notifications", developmentHandler.CreateTestNotifications)
+	protected.Post("/development/check-expiring-documents", developmentHandler.CheckExpiringDocuments)
+	protected.Post("/development/check-expiring-director-terms", developmentHandler.CheckExpiringDirectorTerms)
+	protected.Post("/development/check-all-expiring-notifications", developmentHandler.CheckAllExpiringNotifications)
+	protected.Post("/development/create-notification-for-document", developmentHandler.CreateNotificationForDocument)
+
+	// Route SonarQube (hanya superadmin/admin)
+	// Feature flag: ENABLE_SONARQUBE_MONITOR (default: true untuk local, false untuk production/development server)
+	enableSonarQubeMonitor := os.Getenv("ENABLE_SONARQUBE_MONITOR")
+	// Default: enable di local development (ENV != "production"), disable di production/development server
+	// Set ENABLE_SONARQUBE_MONITOR=true untuk force enable, false untuk force disable
+	shouldEnableSonarQube := true
+	if enableSonarQubeMonitor != "" {
+		shouldEnableSonarQube = enableSonarQubeMonitor == "true"
+	} else if env == "production" {
+		// Default: disable di production (bisa di-override dengan env var)
+		shouldEnableSonarQube = false
+	}
+
+	// SonarQube handler (dibuat terlepas dari enable/disable untuk status endpoint)
+	sonarqubeHandler := http.NewSonarQubeHandler()
+
+	// Status endpoint (public untuk frontend check, tidak perlu auth)
+	api.Get("/sonarqube/status", sonarqubeHandler.GetStatus)
+
+	if shouldEnableSonarQube {
+		protected.Get("/sonarqube/issues", sonarqubeHandler.GetIssues)
+		protected.Get("/sonarqube/issues/export", sonarqubeHandler.ExportIssues)
+		protected.Get("/sonarqube/quality", sonarqubeHandler.GetSoftwareQualityMetrics)
+		zapLog.Info("SonarQube Monitor enabled",
+			zap.String("env", env),
+			zap.String("enable_flag", enableSonarQubeMonitor),
+		)
+	} else {
+		zapLog.Info("SonarQube Monitor disabled",
+			zap.String("env", env),
+			zap.String("enable_flag", enableSonarQubeMonitor),
+			zap.String("reason", "ENABLE_SONARQUBE_MONITOR not set to 'true' or running in production"),
+		)
+	}
+
+	// Swagger documentation dengan auto-reload
+	app.Get("/swagger/*", swagger.HandlerDefault)
+
+	// Swagger regeneration endpoint (untuk development)
+	protected.Post("/swagger/regenerate", http.RegenerateSwagger)
+
+	// Swagger JSON/YAML dengan no-cache headers untuk auto-reload
+	app.Get("/swagger.json", http.GetSwaggerJSON)
+	app.Get("/swagger.yaml", http.GetSwaggerYAML)
+
+	// Start server
+	// Listen on 0.0.0.0 to accept connections from all interfaces (not just localhost)
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+	listenAddr := "0.0.0.0:" + port
+	zapLog.Info("Server starting",
+		zap.String("listen_addr", listenAddr),
+		zap.String("port", port),
+		zap.String("swagger", "http://localhost:"+port+"/swagger/index.html"),
+	)
+
+	// Graceful shutdown saat SIGINT/SIGTERM (Cloud Run mengirim SIGTERM sebelum instance dimatikan)
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+		<-quit
+		zapLog.Info("Shutting down server")
+		if err := app.ShutdownWithTimeout(10 * time.Second); err != nil {
+			zapLog.Warn("Server shutdown error", zap.Error(err))
+		}
+	}()
+
+	if err := app.Listen(listenAddr); err != nil {
+		zapLog.Fatal("Failed to start server", zap.Error(err))
+	}
+
+	// Kirim sisa audit event ke sink eksternal, flush span yang masih di buffer dan tutup koneksi shared store
+	audit.CloseSinks(10 * time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		zapLog.Warn("Failed to flush traces", zap.Error(err))
+	}
+	if err := kvstore.Close(); err != nil {
+		zapLog.Warn("Failed to close key-value store", zap.Error(err))
+	}
+}
+
+// indexHandler returns basic API information
+func indexHandler(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"message": "Pedeve App Backend API",
+		"version": "1.0.0",
+		"docs":    "/swagger/index.html",
+	})
+}
+
+// apiInfoHandler returns API information
+// @Summary      Informasi API
+// @Description  Mengembalikan informasi tentang API termasuk versi dan daftar endpoint. Endpoint ini public dan tidak memerlukan authentication.
+// @Tags         General
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}  "Informasi API berhasil diambil. Response berisi api, version, dan endpoints"
+// @Router       /api/v1 [get]
+// @note         Catatan Teknis:
+// @note         1. Public Endpoint: Endpoint ini tidak memerlukan authentication
+// @note         2. API Version: Mengembalikan versi API saat ini
+// @note         3. Endpoints: Mengembalikan daftar endpoint yang tersedia
+func apiInfoHandler(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"api":     "Pedeve App Backend API",
+		"version": "1.0.0",
+		"endpoints": fiber.Map{
+			"documents": "/api/v1/documents",
+			"swagger":   "/swagger/index.html",
+		},
+	})
+}
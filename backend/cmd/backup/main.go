@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/backup"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/storage"
+)
+
+// Backup dan restore lengkap DMS: semua tabel + file dokumen dan logo di storage dalam satu arsip
+// (lihat package internal/infrastructure/backup untuk format arsip).
+//
+// Usage (dari folder backend):
+//
+//	go run ./cmd/backup create -out dms-backup.tar.gz [-encrypt]
+//	go run ./cmd/backup verify -in dms-backup.tar.gz
+//	go run ./cmd/backup restore -in dms-backup.tar.gz
+//
+// Restore ke instance baru:
+//
+//	go run ./cmd/migrate up -to <schema_version dari verify>
+//	go run ./cmd/backup restore -in dms-backup.tar.gz
+//	go run ./cmd/migrate up
+//
+// Environment:
+//
+//	DATABASE_URL          PostgreSQL; jika kosong memakai SQLite dms.db (development)
+//	GCP_STORAGE_*         storage tujuan/sumber file, sama dengan API (fallback UPLOAD_BASE_PATH lokal)
+//	BACKUP_PASSPHRASE     passphrase arsip terenkripsi (atau gunakan -passphrase-file)
+//	ENCRYPTION_KEY        harus sama dengan instance sumber (kolom terenkripsi disalin apa adanya)
+const usage = `Usage: backup <command> [flags]
+
+Commands:
+  create -out FILE [-encrypt]   Back up all tables and referenced files into FILE
+  verify -in FILE               Check archive integrity against its manifest
+  restore -in FILE              Restore FILE into an empty, migrated database
+
+Flags for all commands:
+  -passphrase-file FILE         Read the archive passphrase from FILE (default: $BACKUP_PASSPHRASE)
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	command, args := os.Args[1], os.Args[2:]
+	switch command {
+	case "create":
+		runCreate(args)
+	case "verify":
+		runVerify(args)
+	case "restore":
+		runRestore(args)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
+	}
+}
+
+// readPassphrase membaca passphrase dari file (jika diisi) atau BACKUP_PASSPHRASE
+func readPassphrase(passphraseFile string) string {
+	if passphraseFile == "" {
+		return os.Getenv("BACKUP_PASSPHRASE")
+	}
+	data, err := os.ReadFile(passphraseFile)
+	if err != nil {
+		log.Fatalf("❌ Failed to read passphrase file: %v", err)
+	}
+	return strings.TrimRight(string(data), "\r\n")
+}
+
+func progress(format string, args ...interface{}) {
+	fmt.Printf("   "+format+"\n", args...)
+}
+
+// connect membuka database dan storage tanpa verifikasi schema (restore berjalan pada versi schema backup)
+func connect() storage.StorageManager {
+	logger.InitLogger()
+	database.Connect()
+	manager, err := storage.GetStorageManager()
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize storage: %v", err)
+	}
+	return manager
+}
+
+func closeStorage(manager storage.StorageManager) {
+	if closer, ok := manager.(interface{ Close() error }); ok {
+		_ = closer.Close()
+	}
+}
+
+func runCreate(args []string) {
+	fs := flag.NewFlagSet("create", flag.ExitOnError)
+	out := fs.String("out", "", "output archive path")
+	encrypt := fs.Bool("encrypt", false, "encrypt the archive with the passphrase")
+	passphraseFile := fs.String("passphrase-file", "", "file containing the archive passphrase")
+	_ = fs.Parse(args)
+	if *out == "" {
+		log.Fatalf("❌ -out is required")
+	}
+
+	opts := backup.Options{Progress: progress}
+	if *encrypt {
+		opts.Passphrase = readPassphrase(*passphraseFile)
+		if opts.Passphrase == "" {
+			log.Fatalf("❌ -encrypt requires BACKUP_PASSPHRASE or -passphrase-file")
+		}
+	}
+
+	manager := connect()
+	defer closeStorage(manager)
+	defer logger.Sync()
+
+	// Tulis ke file sementara supaya arsip yang gagal dibuat tidak terlihat seperti backup valid
+	tmpPath := *out + ".partial"
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		log.Fatalf("❌ Failed to create %s: %v", tmpPath, err)
+	}
+
+	fmt.Println("📦 Creating backup...")
+	manifest, err := backup.Create(context.Background(), database.GetDB(), manager, file, opts)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		log.Fatalf("❌ Backup failed: %v", err)
+	}
+	if err := os.Rename(tmpPath, *out); err != nil {
+		log.Fatalf("❌ Failed to move archive to %s: %v", *out, err)
+	}
+
+	printSummary(manifest)
+	fmt.Printf("✅ Backup written to %s (encrypted: %t)\n", *out, opts.Passphrase != "")
+	if len(manifest.MissingBlobs) > 0 {
+		fmt.Printf("⚠️  %d referenced files were missing from storage (listed in manifest.missing_blobs)\n", len(manifest.MissingBlobs))
+	}
+}
+
+func runVerify(args []string) {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	in := fs.String("in", "", "archive path")
+	passphraseFile := fs.String("passphrase-file", "", "file containing the archive passphrase")
+	_ = fs.Parse(args)
+	if *in == "" {
+		log.Fatalf("❌ -in is required")
+	}
+
+	file, err := os.Open(*in)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	defer file.Close()
+
+	fmt.Println("🔍 Verifying backup...")
+	manifest, err := backup.Verify(file, backup.Options{Passphrase: readPassphrase(*passphraseFile)})
+	if err != nil {
+		log.Fatalf("❌ Verification failed: %v", err)
+	}
+	printSummary(manifest)
+	fmt.Println("✅ Archive is valid")
+}
+
+func runRestore(args []string) {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	in := fs.String("in", "", "archive path")
+	passphraseFile := fs.String("passphrase-file", "", "file containing the archive passphrase")
+	_ = fs.Parse(args)
+	if *in == "" {
+		log.Fatalf("❌ -in is required")
+	}
+
+	manager := connect()
+	defer closeStorage(manager)
+	defer logger.Sync()
+
+	open := func() (io.ReadCloser, error) {
+		return os.Open(*in)
+	}
+	fmt.Println("♻️  Restoring backup...")
+	opts := backup.Options{Passphrase: readPassphrase(*passphraseFile), Progress: progress}
+	manifest, err := backup.Restore(context.Background(), database.GetDB(), manager, open, opts)
+	if err != nil {
+		log.Fatalf("❌ Restore failed: %v", err)
+	}
+	printSummary(manifest)
+	fmt.Println("✅ Restore completed. Run `migrate up` if this binary has newer migrations, then start the API.")
+}
+
+func printSummary(manifest *backup.Manifest) {
+	var rows, blobBytes int64
+	for _, table := range manifest.Tables {
+		rows += table.Rows
+	}
+	for _, blob := range manifest.Blobs {
+		blobBytes += blob.Size
+	}
+	fmt.Println()
+	fmt.Printf("Created at      : %s\n", manifest.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+	fmt.Printf("Schema version  : %d\n", manifest.SchemaVersion)
+	fmt.Printf("Source          : %s database, %s storage\n", manifest.SourceDialect, manifest.StorageBackend)
+	fmt.Printf("Tables          : %d (%d rows)\n", len(manifest.Tables), rows)
+	fmt.Printf("Files           : %d (%d bytes)\n", len(manifest.Blobs), blobBytes)
+	if len(manifest.MissingBlobs) > 0 {
+		fmt.Printf("Missing files   : %d\n", len(manifest.MissingBlobs))
+	}
+	fmt.Println()
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/usecase"
+)
+
+// Memindahkan tabel reports (modul report lama) ke realisasi bulanan financial_reports.
+//
+// Environment:
+//
+//	DATABASE_URL     wajib
+//	DRY_RUN=true     hanya menampilkan hasil tanpa menyimpan perubahan
+//	ON_CONFLICT      skip (default) atau legacy (nilai reports lama menimpa Revenue/Opex/NPAT/Dividend realisasi)
+//	CONFLICT_REPORT  path file CSV laporan konflik (default: legacy_report_conflicts.csv)
+func main() {
+	// DATABASE_URL must be set via environment variable for security
+	// Never hardcode database credentials in source code
+	if os.Getenv("DATABASE_URL") == "" {
+		fmt.Fprintf(os.Stderr, "❌ DATABASE_URL environment variable is required. Please set it before running this command.\n")
+		os.Exit(1)
+	}
+
+	dryRun := strings.EqualFold(os.Getenv("DRY_RUN"), "true")
+	onConflict := os.Getenv("ON_CONFLICT")
+	reportPath := os.Getenv("CONFLICT_REPORT")
+	if reportPath == "" {
+		reportPath = "legacy_report_conflicts.csv"
+	}
+
+	fmt.Println("🔄 Migrating legacy reports to financial_reports")
+	if dryRun {
+		fmt.Println("   (dry run, tidak ada perubahan yang disimpan)")
+	}
+	fmt.Println()
+
+	// Init logger
+	logger.InitLogger()
+	defer logger.Sync()
+
+	// Init database (schema harus sudah di-migrate, lihat cmd/migrate)
+	database.InitDB()
+
+	result, err := usecase.NewLegacyReportMigrationUseCase().MigrateLegacyReports(onConflict, dryRun)
+	if err != nil {
+		log.Fatalf("❌ Migration failed: %v", err)
+	}
+
+	fmt.Printf("   Total reports lama : %d\n", result.Total)
+	fmt.Printf("   ✅ Created         : %d\n", result.Created)
+	fmt.Printf("   🔀 Merged          : %d\n", result.Merged)
+	fmt.Printf("   ⏭️  Unchanged       : %d\n", result.Unchanged)
+	fmt.Printf("   ⚠️  Conflicts       : %d\n", len(result.Conflicts))
+	fmt.Println()
+
+	if len(result.Conflicts) == 0 {
+		fmt.Println("🎉 Legacy reports migrated without conflicts")
+		return
+	}
+
+	if err := writeConflictReport(reportPath, result.Conflicts); err != nil {
+		log.Fatalf("❌ Failed to write conflict report: %v", err)
+	}
+	fmt.Printf("📄 Conflict report written to %s\n", reportPath)
+	if onConflict != usecase.LegacyReportConflictLegacy {
+		fmt.Println("   Periksa value_mismatch lalu jalankan ulang dengan ON_CONFLICT=legacy jika nilai reports lama yang benar")
+	}
+}
+
+// writeConflictReport menulis satu baris per field yang berbeda (atau satu baris per konflik tanpa field)
+func writeConflictReport(path string, conflicts []usecase.LegacyReportConflict) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write([]string{"report_id", "company_id", "period", "reason", "financial_report_id", "field", "financial_value", "legacy_value", "resolved"}); err != nil {
+		return err
+	}
+
+	for _, conflict := range conflicts {
+		base := []string{conflict.ReportID, conflict.CompanyID, conflict.Period, conflict.Reason, conflict.FinancialReportID}
+		resolved := fmt.Sprint(conflict.Resolved)
+		if len(conflict.Fields) == 0 {
+			if err := writer.Write(append(base, "", "", "", resolved)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		fields := make([]string, 0, len(conflict.Fields))
+		for field := range conflict.Fields {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			change := conflict.Fields[field]
+			row := append(append([]string{}, base...), field, fmt.Sprint(change.Old), fmt.Sprint(change.New), resolved)
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
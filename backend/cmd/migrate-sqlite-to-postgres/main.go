@@ -8,6 +8,8 @@ import (
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
 	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -103,6 +105,16 @@ func main() {
 	fmt.Printf("   ✅ Migrated %d companies\n", count)
 	fmt.Println()
 
+	// Backfill company_hierarchy di InitDB berjalan saat companies masih kosong,
+	// jadi closure table dibangun ulang dari parent_id setelah companies tersalin
+	fmt.Println("   🔄 Rebuilding company_hierarchy...")
+	hierarchy, err := rebuildCompanyHierarchy(postgresDB)
+	if err != nil {
+		log.Fatalf("❌ Failed to rebuild company_hierarchy: %v", err)
+	}
+	fmt.Printf("   ✅ Rebuilt %d company_hierarchy paths (%d level fixes, %d orphans)\n", hierarchy.Paths, len(hierarchy.LevelFixes), len(hierarchy.Orphans))
+	fmt.Println()
+
 	// 5. Users (depends on roles and companies)
 	fmt.Println("5️⃣  Migrating users...")
 	userColumns := []string{
@@ -144,11 +156,32 @@ func main() {
 	fmt.Printf("   - Permissions: %d\n", getCount(postgresSQL, "permissions"))
 	fmt.Printf("   - Role Permissions: %d\n", getCount(postgresSQL, "role_permissions"))
 	fmt.Printf("   - Companies: %d\n", getCount(postgresSQL, "companies"))
+	fmt.Printf("   - Company Hierarchy: %d\n", getCount(postgresSQL, "company_hierarchy"))
 	fmt.Printf("   - Users: %d\n", getCount(postgresSQL, "users"))
 	fmt.Printf("   - Two Factor Auths: %d\n", getCount(postgresSQL, "two_factor_auths"))
 	fmt.Printf("   - Audit Logs: %d\n", getCount(postgresSQL, "audit_logs"))
 }
 
+// rebuildCompanyHierarchy membangun ulang closure table company_hierarchy dan memastikan
+// setiap company minimal punya path ke dirinya sendiri
+func rebuildCompanyHierarchy(db *gorm.DB) (*repository.CompanyHierarchyRebuildResult, error) {
+	result, err := repository.RebuildCompanyHierarchy(db, false)
+	if err != nil {
+		return nil, err
+	}
+	var companies, paths int64
+	if err := db.Model(&domain.CompanyModel{}).Count(&companies).Error; err != nil {
+		return nil, fmt.Errorf("failed to count companies: %w", err)
+	}
+	if err := db.Model(&domain.CompanyHierarchyModel{}).Count(&paths).Error; err != nil {
+		return nil, fmt.Errorf("failed to count company hierarchy: %w", err)
+	}
+	if paths < companies {
+		return nil, fmt.Errorf("company_hierarchy has %d paths for %d companies", paths, companies)
+	}
+	return result, nil
+}
+
 func migrateTable(sqliteDB, postgresDB *sql.DB, tableName string, columns []string) error {
 	// Cek apakah tabel ada di SQLite
 	var count int
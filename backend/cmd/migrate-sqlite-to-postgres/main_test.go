@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"github.com/repoareta/pedeve-dms-app/backend/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRebuildCompanyHierarchy tests closure table terisi untuk companies yang disalin tanpa lewat repository
+func TestRebuildCompanyHierarchy(t *testing.T) {
+	db := helpers.SetupTestDB(t)
+	defer helpers.CleanupTestDB(t, db)
+
+	holding := &domain.CompanyModel{ID: uuid.GenerateUUID(), Name: "Holding", Code: "HLD", IsActive: true}
+	require.NoError(t, db.Create(holding).Error)
+	child := &domain.CompanyModel{ID: uuid.GenerateUUID(), Name: "Anak", Code: "ANK", ParentID: &holding.ID, IsActive: true}
+	require.NoError(t, db.Create(child).Error)
+
+	var paths int64
+	require.NoError(t, db.Model(&domain.CompanyHierarchyModel{}).Count(&paths).Error)
+	require.Zero(t, paths)
+
+	result, err := rebuildCompanyHierarchy(db)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Paths)
+
+	var companies int64
+	require.NoError(t, db.Model(&domain.CompanyModel{}).Count(&companies).Error)
+	require.NoError(t, db.Model(&domain.CompanyHierarchyModel{}).Count(&paths).Error)
+	assert.GreaterOrEqual(t, paths, companies)
+
+	var stored domain.CompanyModel
+	require.NoError(t, db.First(&stored, "id = ?", child.ID).Error)
+	assert.Equal(t, 1, stored.Level)
+}
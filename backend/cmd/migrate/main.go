@@ -0,0 +1,235 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database/migrations"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+)
+
+// Migrasi schema database berversi (lihat package internal/infrastructure/database/migrations).
+// Menggantikan AutoMigrate saat startup dan cmd/create-schema (gunakan `migrate up`).
+//
+// Usage (dari folder backend):
+//
+//	go run ./cmd/migrate up [-to VERSION]   jalankan migrasi pending (sampai VERSION jika diisi)
+//	go run ./cmd/migrate down [-steps N]    rollback N migrasi terakhir (default 1)
+//	go run ./cmd/migrate status             tampilkan versi schema dan migrasi pending
+//	go run ./cmd/migrate create NAME        buat file migrasi baru
+//
+// Environment:
+//
+//	DATABASE_URL   PostgreSQL; jika kosong memakai SQLite dms.db (development)
+const usage = `Usage: migrate <command> [flags]
+
+Commands:
+  up [-to VERSION]      Apply pending migrations (up to VERSION if given)
+  down [-steps N]       Roll back the last N migrations (default 1)
+  status                Show schema version and pending migrations
+  create NAME [-dir D]  Create a new migration file
+`
+
+const defaultMigrationsDir = "internal/infrastructure/database/migrations"
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	command, args := os.Args[1], os.Args[2:]
+	switch command {
+	case "up":
+		runUp(args)
+	case "down":
+		runDown(args)
+	case "status":
+		runStatus()
+	case "create":
+		runCreate(args)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
+	}
+}
+
+// connect membuka koneksi tanpa verifikasi schema (InitDB akan menolak schema yang belum di-migrate)
+func connect() {
+	logger.InitLogger()
+	database.Connect()
+}
+
+func runUp(args []string) {
+	fs := flag.NewFlagSet("up", flag.ExitOnError)
+	target := fs.Int64("to", 0, "target version (default: latest)")
+	_ = fs.Parse(args)
+
+	connect()
+	defer logger.Sync()
+
+	ran, err := migrations.Up(database.GetDB(), *target)
+	for _, m := range ran {
+		fmt.Printf("✅ Applied %d_%s\n", m.Version, m.Name)
+	}
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	if len(ran) == 0 {
+		fmt.Println("✅ Schema already up to date")
+	}
+	printVersion()
+}
+
+func runDown(args []string) {
+	fs := flag.NewFlagSet("down", flag.ExitOnError)
+	steps := fs.Int("steps", 1, "number of migrations to roll back")
+	_ = fs.Parse(args)
+	if *steps < 1 {
+		log.Fatalf("❌ -steps must be at least 1")
+	}
+
+	connect()
+	defer logger.Sync()
+
+	ran, err := migrations.Down(database.GetDB(), *steps)
+	for _, m := range ran {
+		fmt.Printf("↩️  Rolled back %d_%s\n", m.Version, m.Name)
+	}
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	if len(ran) == 0 {
+		fmt.Println("Nothing to roll back")
+	}
+	printVersion()
+}
+
+func runStatus() {
+	connect()
+	defer logger.Sync()
+
+	status, err := migrations.GetStatus(database.GetDB())
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	applied := make(map[int64]migrations.SchemaMigration, len(status.Applied))
+	for _, row := range status.Applied {
+		applied[row.Version] = row
+	}
+
+	fmt.Printf("%-16s %-40s %s\n", "VERSION", "NAME", "APPLIED AT")
+	for _, m := range migrations.All() {
+		appliedAt := "pending"
+		if row, ok := applied[m.Version]; ok {
+			appliedAt = row.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Printf("%-16d %-40s %s\n", m.Version, m.Name, appliedAt)
+	}
+	for _, row := range status.Unknown {
+		fmt.Printf("%-16d %-40s %s (unknown to this binary)\n", row.Version, row.Name, row.AppliedAt.Format(time.RFC3339))
+	}
+
+	fmt.Println()
+	fmt.Printf("Current version : %d\n", status.Current)
+	fmt.Printf("Latest version  : %d\n", status.Latest)
+	if err := status.Err(); err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("✅ Schema up to date")
+}
+
+func printVersion() {
+	status, err := migrations.GetStatus(database.GetDB())
+	if err != nil {
+		return
+	}
+	fmt.Printf("Schema version: %d (latest %d)\n", status.Current, status.Latest)
+}
+
+var nonIdentifier = regexp.MustCompile(`[^a-z0-9]+`)
+
+func runCreate(args []string) {
+	fs := flag.NewFlagSet("create", flag.ExitOnError)
+	dir := fs.String("dir", defaultMigrationsDir, "migrations package directory")
+	// Nama boleh ditulis sebelum flag (create add_index -dir ...)
+	var name string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	_ = fs.Parse(args)
+	if name == "" {
+		name = fs.Arg(0)
+	}
+
+	name = strings.Trim(nonIdentifier.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		log.Fatalf("❌ Migration name is required, e.g. migrate create add_documents_retention")
+	}
+	if info, err := os.Stat(*dir); err != nil || !info.IsDir() {
+		log.Fatalf("❌ Migrations directory %s not found (run from the backend folder or pass -dir)", *dir)
+	}
+
+	version := time.Now().UTC().Format("20060102150405")
+	funcName := camelCase(name)
+	source := fmt.Sprintf(migrationTemplate, version, name, funcName, funcName, funcName, funcName)
+	formatted, err := format.Source([]byte(source))
+	if err != nil {
+		log.Fatalf("❌ Failed to format migration: %v", err)
+	}
+
+	path := filepath.Join(*dir, fmt.Sprintf("%s_%s.go", version, name))
+	if err := os.WriteFile(path, formatted, 0644); err != nil {
+		log.Fatalf("❌ Failed to write migration: %v", err)
+	}
+	fmt.Printf("✅ Created %s\n", path)
+}
+
+// camelCase: add_documents_retention -> addDocumentsRetention (prefix "m" jika diawali angka)
+func camelCase(name string) string {
+	parts := strings.Split(name, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	result := strings.Join(parts, "")
+	if result[0] >= '0' && result[0] <= '9' {
+		result = "m" + result
+	}
+	return result
+}
+
+const migrationTemplate = `package migrations
+
+import "gorm.io/gorm"
+
+// TODO: jelaskan perubahan schema. Gunakan struct lokal atau SQL eksplisit per dialect (isPostgres),
+// bukan AutoMigrate model domain, supaya migrasi ini tidak berubah saat model berubah.
+func init() {
+	register(Migration{
+		Version: %s,
+		Name:    %q,
+		Up:      %sUp,
+		Down:    %sDown,
+	})
+}
+
+func %sUp(tx *gorm.DB) error {
+	return nil
+}
+
+func %sDown(tx *gorm.DB) error {
+	return nil
+}
+`
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+)
+
+// Membangun ulang closure table hierarki company (company_hierarchy) dari parent_id dan memperbaiki level.
+// Menggantikan cmd/fix-company-levels; berlaku untuk PostgreSQL maupun SQLite dan mencakup company nonaktif.
+//
+// Environment:
+//
+//	DATABASE_URL   wajib
+//	DRY_RUN=true   hanya menampilkan hasil tanpa menyimpan perubahan
+func main() {
+	// DATABASE_URL must be set via environment variable for security
+	// Never hardcode database credentials in source code
+	if os.Getenv("DATABASE_URL") == "" {
+		fmt.Fprintf(os.Stderr, "❌ DATABASE_URL environment variable is required. Please set it before running this command.\n")
+		os.Exit(1)
+	}
+
+	dryRun := strings.EqualFold(os.Getenv("DRY_RUN"), "true")
+
+	fmt.Println("🔧 Rebuilding company hierarchy")
+	if dryRun {
+		fmt.Println("   (dry run, tidak ada perubahan yang disimpan)")
+	}
+	fmt.Println()
+
+	// Init logger
+	logger.InitLogger()
+	defer logger.Sync()
+
+	// Init database (schema harus sudah di-migrate, lihat cmd/migrate)
+	database.InitDB()
+
+	result, err := repository.NewCompanyRepository().RebuildHierarchy(dryRun)
+	if err != nil {
+		log.Fatalf("❌ Rebuild failed: %v", err)
+	}
+
+	fmt.Printf("   Companies         : %d\n", result.Companies)
+	fmt.Printf("   Paths (sebelum)   : %d\n", result.PathsBefore)
+	fmt.Printf("   Paths (sesudah)   : %d\n", result.Paths)
+	fmt.Printf("   📝 Level diperbaiki: %d\n", len(result.LevelFixes))
+	for _, fix := range result.LevelFixes {
+		fmt.Printf("      %-19s | %-30s | %d -> %d\n", fix.Code, fix.Name, fix.OldLevel, fix.NewLevel)
+	}
+	if len(result.Orphans) > 0 {
+		fmt.Printf("   ⚠️  Parent tidak ditemukan (diperlakukan sebagai root): %s\n", strings.Join(result.Orphans, ", "))
+	}
+	if len(result.CycleCodes) > 0 {
+		fmt.Printf("   ⚠️  Siklus parent_id: %s\n", strings.Join(result.CycleCodes, ", "))
+		fmt.Println("      Perbaiki parent_id company tersebut lalu jalankan ulang command ini")
+	}
+
+	fmt.Println()
+	if dryRun {
+		fmt.Println("🔍 Dry run selesai")
+		return
+	}
+	fmt.Println("🎉 Company hierarchy rebuilt successfully!")
+}
@@ -42,6 +42,7 @@ func main() {
 
 	// Initialize repositories
 	reportRepo := repository.NewReportRepository()
+	financialReportRepo := repository.NewFinancialReportRepository()
 	companyRepo := repository.NewCompanyRepository()
 	userRepo := repository.NewUserRepository()
 
@@ -147,7 +148,10 @@ func main() {
 				Remark:         nil, // Optional, can be null
 			}
 
-			if err := reportRepo.Create(report); err != nil {
+			// Ditandai sebagai data seeder supaya reset-reports tidak menghapus realisasi user
+			financial := &domain.FinancialReportModel{ID: report.ID, Source: domain.FinancialReportSourceSeeder}
+			repository.ApplyReportToFinancialReport(report, financial)
+			if err := financialReportRepo.Create(financial); err != nil {
 				fmt.Printf("   ❌ Failed to create report for period %s: %v\n", period, err)
 				continue
 			}
@@ -11,6 +11,7 @@ import (
 	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/usecase"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/utils"
 	"go.uber.org/zap"
 )
 
@@ -355,3 +356,190 @@ func GetUserActivityLogsHandler(c *fiber.Ctx) error {
 		"totalPages": (total + int64(pageSize) - 1) / int64(pageSize),
 	})
 }
+
+// GetEntityHistoryHandler menangani request GET untuk riwayat perubahan satu entity
+// @Summary      Ambil Riwayat Perubahan Entity
+// @Description  Mengambil riwayat lengkap satu entity (report, financial_report, document, company, shareholder, director, user, role) dari user activity logs. Response berisi timeline aksi beserta diff per field, dan riwayat per field (nilai lama → nilai baru). Field sensitif (KTP, NPWP, identity_number) sudah di-mask saat dicatat.
+// @Tags         Audit
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        resource  path      string  true  "Jenis resource (contoh: company, financial_report, director)"
+// @Param        id        path      string  true  "ID entity"
+// @Success      200       {object}  domain.EntityHistoryResponse  "Riwayat entity berhasil diambil"
+// @Failure      400       {object}  domain.ErrorResponse  "Resource tidak didukung"
+// @Failure      401       {object}  domain.ErrorResponse  "Token tidak valid atau user tidak terautentikasi"
+// @Failure      403       {object}  domain.ErrorResponse  "Entity bukan milik company yang bisa diakses user"
+// @Failure      500       {object}  domain.ErrorResponse  "Gagal mengambil riwayat entity"
+// @Router       /api/v1/user-activity-logs/entity/{resource}/{id} [get]
+// @note         Catatan Teknis:
+// @note         1. Authentication: Memerlukan JWT token valid dalam httpOnly cookie (auth_token) atau Authorization header
+// @note         2. Authorization: User reguler hanya melihat perubahan yang mereka lakukan sendiri, admin melihat semua perubahan kecuali milik superadmin
+// @note            Selain superadmin/administrator, entity harus milik company user atau turunannya (role dan entity yang sudah dihapus permanen hanya untuk superadmin/administrator)
+// @note         3. Resources: Hanya resource permanent (tersimpan di user_activity_logs) yang didukung
+// @note         4. Urutan: Timeline dan riwayat per field diurutkan dari perubahan paling lama
+func GetEntityHistoryHandler(c *fiber.Ctx) error {
+	userIDVal := c.Locals("userID")
+	if userIDVal == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(domain.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User context not found",
+		})
+	}
+	currentUserID := userIDVal.(string)
+
+	resource := c.Params("resource")
+	resourceID := c.Params("id")
+	if !repository.IsPermanentResource(resource) {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_resource",
+			Message: "Resource tidak didukung untuk riwayat perubahan",
+		})
+	}
+
+	roleName, _ := c.Locals("roleName").(string)
+	roleLower := strings.ToLower(strings.TrimSpace(roleName))
+	isSuperadmin := roleLower == "superadmin"
+	isAdminLike := roleLower == "admin" || roleLower == "manager" || roleLower == "staff" || roleLower == "administrator" || isSuperadmin
+
+	// Selain superadmin/administrator, entity harus milik company yang bisa diakses user
+	if !utils.IsSuperAdminLike(roleName) {
+		companyID, err := repository.GetEntityCompanyID(resource, resourceID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+				Error:   "internal_error",
+				Message: "Failed to get entity history",
+			})
+		}
+		if companyID == "" || !canAccessCompany(c, usecase.NewCompanyUseCase(), companyID, false) {
+			return forbiddenCompany(c)
+		}
+	}
+
+	filter := usecase.EntityHistoryFilter{HideSuperadminLogs: !isSuperadmin}
+	if !isAdminLike {
+		filter.UserID = currentUserID
+	}
+
+	history, err := usecase.GetEntityHistory(resource, resourceID, filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to get entity history",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(history)
+}
+
+// GetAuditSinkHealthHandler menangani request GET untuk status pengiriman audit event ke sink eksternal (SIEM)
+// @Summary      Status Audit Sink
+// @Description  Mengambil status setiap audit sink (syslog, webhook, file): panjang antrian, jumlah terkirim, retry, dead-letter, lag (umur event tertua yang belum terkirim), dan error terakhir. Hanya untuk superadmin/administrator.
+// @Tags         Audit
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}  "Status sink berhasil diambil. Response berisi enabled, healthy, sinks (array), dan pending_dead_letters"
+// @Failure      401  {object}  domain.ErrorResponse  "Token tidak valid atau user tidak terautentikasi"
+// @Failure      403  {object}  domain.ErrorResponse  "Hanya superadmin/administrator"
+// @Router       /api/v1/audit-logs/sinks/health [get]
+// @note         Catatan Teknis:
+// @note         1. Konfigurasi: Sink diaktifkan lewat AUDIT_SINKS (syslog, webhook, file), kosong = nonaktif
+// @note         2. Delivery: Event dikirim async dengan buffer per sink, retry exponential backoff, lalu masuk dead-letter queue
+// @note         3. Lag: lag_seconds adalah umur event yang sedang dikirim (0 jika antrian kosong)
+// @note         4. Dead Letter: pending_dead_letters dihitung dari tabel audit_sink_dead_letters yang belum di-replay
+func GetAuditSinkHealthHandler(c *fiber.Ctx) error {
+	roleName, _ := c.Locals("roleName").(string)
+	if !utils.IsSuperAdminLike(roleName) {
+		return c.Status(fiber.StatusForbidden).JSON(domain.ErrorResponse{
+			Error:   "forbidden",
+			Message: "Only superadmin or administrator can view audit sink health",
+		})
+	}
+
+	sinks := audit.GetSinkHealth()
+	healthy := true
+	for _, s := range sinks {
+		if !s.Healthy {
+			healthy = false
+		}
+	}
+
+	pending := map[string]int64{}
+	if audit.SinksEnabled() {
+		type sinkCount struct {
+			Sink  string
+			Total int64
+		}
+		var counts []sinkCount
+		if err := database.GetDB().Model(&domain.AuditSinkDeadLetter{}).
+			Select("sink, COUNT(*) AS total").
+			Where("replayed_at IS NULL").
+			Group("sink").
+			Scan(&counts).Error; err != nil {
+			logger.GetLogger().Warn("Failed to count audit sink dead letters", zap.Error(err))
+		}
+		for _, sc := range counts {
+			pending[sc.Sink] = sc.Total
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"enabled":              audit.SinksEnabled(),
+		"healthy":              healthy,
+		"sinks":                sinks,
+		"pending_dead_letters": pending,
+	})
+}
+
+// ReplayAuditDeadLettersHandler menangani request POST untuk mengirim ulang audit event di dead-letter queue
+// @Summary      Replay Dead-Letter Audit Events
+// @Description  Mengirim ulang audit event yang gagal dikirim ke sink eksternal. Hanya untuk superadmin/administrator.
+// @Tags         Audit
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        sink   query     string  false  "Nama sink (syslog, webhook, file). Kosong = semua sink"
+// @Param        limit  query     int     false  "Jumlah maksimal event yang di-replay (default: 500, maksimal: 5000)"
+// @Success      200    {object}  map[string]interface{}  "Replay selesai. Response berisi replayed"
+// @Failure      400    {object}  domain.ErrorResponse  "Audit sink tidak aktif"
+// @Failure      401    {object}  domain.ErrorResponse  "Token tidak valid atau user tidak terautentikasi"
+// @Failure      403    {object}  domain.ErrorResponse  "Hanya superadmin/administrator"
+// @Router       /api/v1/audit-logs/sinks/dead-letters/replay [post]
+// @note         Catatan Teknis:
+// @note         1. CSRF Protection: Memerlukan CSRF token
+// @note         2. Replay: Event yang berhasil dikirim ditandai replayed_at, event yang gagal tetap di antrian dengan attempts bertambah
+func ReplayAuditDeadLettersHandler(c *fiber.Ctx) error {
+	roleName, _ := c.Locals("roleName").(string)
+	if !utils.IsSuperAdminLike(roleName) {
+		return c.Status(fiber.StatusForbidden).JSON(domain.ErrorResponse{
+			Error:   "forbidden",
+			Message: "Only superadmin or administrator can replay audit dead letters",
+		})
+	}
+	if !audit.SinksEnabled() {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "sinks_disabled",
+			Message: "Audit sinks are not enabled",
+		})
+	}
+
+	limit := 500
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 5000 {
+			limit = l
+		}
+	}
+
+	replayed, err := audit.ReplayDeadLetters(c.Query("sink"), limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to replay audit dead letters",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"replayed": replayed,
+	})
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"github.com/repoareta/pedeve-dms-app/backend/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetEntityHistoryHandler_CompanyScope tests admin company hanya bisa melihat riwayat entity company-nya (dan turunannya)
+func TestGetEntityHistoryHandler_CompanyScope(t *testing.T) {
+	db := helpers.SetupTestDB(t)
+	defer helpers.CleanupTestDB(t, db)
+	previous := database.DB
+	database.DB = db
+	defer func() { database.DB = previous }()
+
+	ownCompany := createTestCompanyForHandler(t, db)
+	otherCompany := createTestCompanyForHandler(t, db)
+	ownDirector := &domain.DirectorModel{ID: uuid.GenerateUUID(), CompanyID: ownCompany.ID, Position: "Direktur", FullName: "A"}
+	otherDirector := &domain.DirectorModel{ID: uuid.GenerateUUID(), CompanyID: otherCompany.ID, Position: "Direktur", FullName: "B"}
+	require.NoError(t, db.Create(ownDirector).Error)
+	require.NoError(t, db.Create(otherDirector).Error)
+	ownFolder := &domain.DocumentFolderModel{ID: uuid.GenerateUUID(), Name: "Legal", CompanyID: &ownCompany.ID}
+	otherFolder := &domain.DocumentFolderModel{ID: uuid.GenerateUUID(), Name: "Legal", CompanyID: &otherCompany.ID}
+	require.NoError(t, db.Create(ownFolder).Error)
+	require.NoError(t, db.Create(otherFolder).Error)
+
+	request := func(roleName, path string) int {
+		app := fiber.New()
+		app.Get("/history/:resource/:id", func(c *fiber.Ctx) error {
+			c.Locals("userID", "user-1")
+			c.Locals("roleName", roleName)
+			c.Locals("companyID", &ownCompany.ID)
+			return c.Next()
+		}, GetEntityHistoryHandler)
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	for _, role := range []string{"admin", "manager", "staff"} {
+		assert.Equal(t, fiber.StatusOK, request(role, "/history/director/"+ownDirector.ID), role)
+		assert.Equal(t, fiber.StatusOK, request(role, "/history/company/"+ownCompany.ID), role)
+		assert.Equal(t, fiber.StatusForbidden, request(role, "/history/director/"+otherDirector.ID), role)
+		assert.Equal(t, fiber.StatusForbidden, request(role, "/history/company/"+otherCompany.ID), role)
+		assert.Equal(t, fiber.StatusOK, request(role, "/history/folder/"+ownFolder.ID), role)
+		assert.Equal(t, fiber.StatusForbidden, request(role, "/history/folder/"+otherFolder.ID), role)
+		assert.Equal(t, fiber.StatusForbidden, request(role, "/history/role/"+uuid.GenerateUUID()), role)
+	}
+	assert.Equal(t, fiber.StatusOK, request("superadmin", "/history/director/"+otherDirector.ID))
+	assert.Equal(t, fiber.StatusOK, request("administrator", "/history/role/"+uuid.GenerateUUID()))
+}
@@ -0,0 +1,265 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/audit"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/usecase"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// companyExportDownloadPath adalah path publik unduh arsip export; token di URL adalah otorisasinya
+const companyExportDownloadPath = "/api/v1/company-exports/download/"
+
+// CompanyExportHandler handles export data company (data portability) ke arsip zip
+type CompanyExportHandler struct {
+	exportUC  usecase.CompanyExportUseCase
+	companyUC usecase.CompanyUseCase
+}
+
+// NewCompanyExportHandler creates a new company export handler
+func NewCompanyExportHandler(exportUC usecase.CompanyExportUseCase, companyUC usecase.CompanyUseCase) *CompanyExportHandler {
+	return &CompanyExportHandler{
+		exportUC:  exportUC,
+		companyUC: companyUC,
+	}
+}
+
+// CreateCompanyExport godoc
+// @Summary      Export data company
+// @Description  Membuat arsip zip berisi seluruh data company (profil, pemegang saham, pengurus, bidang usaha, folder dan dokumen beserta file-nya, laporan keuangan, laporan lama, assignment user, audit/activity log) di background. Response berisi job ID untuk polling progress.
+// @Tags         Companies
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                             true   "Company ID"
+// @Param        request  body      domain.CreateCompanyExportRequest  false  "Opsi export"
+// @Success      202      {object}  domain.CompanyExportJobModel
+// @Failure      400      {object}  domain.ErrorResponse
+// @Failure      403      {object}  domain.ErrorResponse
+// @Failure      404      {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/{id}/exports [post]
+// @note         Catatan Teknis:
+// @note         1. Hanya superadmin/administrator atau admin company (termasuk admin induk) yang bisa membuat export
+// @note         2. include_descendants=true menyertakan seluruh anak perusahaan, termasuk yang nonaktif atau dibubarkan
+// @note         3. Arsip berisi manifest.json, index.xlsx, dan folder companies/<kode>/ berisi JSON dan file asli
+// @note         4. Arsip disimpan COMPANY_EXPORT_RETENTION_DAYS hari (default 7) lalu dihapus otomatis
+func (h *CompanyExportHandler) CreateCompanyExport(c *fiber.Ctx) error {
+	companyID := c.Params("id")
+	if !canAccessCompany(c, h.companyUC, companyID, true) {
+		return forbiddenCompany(c)
+	}
+
+	var req domain.CreateCompanyExportRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+				Error:   "invalid_request",
+				Message: "Invalid request body: " + err.Error(),
+			})
+		}
+	}
+
+	job, err := h.exportUC.CreateExport(companyID, req.IncludeDescendants, companyExportActor(c))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(domain.ErrorResponse{
+				Error:   "not_found",
+				Message: "Company not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "export_failed",
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(job)
+}
+
+// ListCompanyExports godoc
+// @Summary      List job export data company
+// @Description  Mengambil daftar job export. Superadmin/administrator melihat semua job, user lain hanya job miliknya.
+// @Tags         Companies
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        company_id  query     string  false  "Filter company ID"
+// @Param        status      query     string  false  "Filter status (processing, completed, failed, expired)"
+// @Param        page        query     int     false  "Page number (default: 1)"
+// @Param        page_size   query     int     false  "Page size (default: 20)"
+// @Success      200         {object}  map[string]interface{}
+// @Failure      500         {object}  domain.ErrorResponse
+// @Router       /api/v1/company-exports [get]
+func (h *CompanyExportHandler) ListCompanyExports(c *fiber.Ctx) error {
+	filter := repository.CompanyExportJobFilter{
+		CompanyID: c.Query("company_id"),
+		Status:    c.Query("status"),
+	}
+	roleName, _ := c.Locals("roleName").(string)
+	if !utils.IsSuperAdminLike(roleName) {
+		filter.CreatedBy, _ = c.Locals("userID").(string)
+	}
+
+	page, pageSize := parsePagination(c)
+	filter.Limit = pageSize
+	filter.Offset = (page - 1) * pageSize
+
+	jobs, total, err := h.exportUC.ListJobs(filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to fetch export jobs: " + err.Error(),
+		})
+	}
+
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+	return c.JSON(fiber.Map{
+		"data":        jobs,
+		"total":       total,
+		"page":        page,
+		"page_size":   pageSize,
+		"total_pages": totalPages,
+	})
+}
+
+// GetCompanyExport godoc
+// @Summary      Get job export data company
+// @Description  Mengambil status dan progress job export (processed_steps/total_steps, current_step)
+// @Tags         Companies
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Export job ID"
+// @Success      200  {object}  domain.CompanyExportJobModel
+// @Failure      403  {object}  domain.ErrorResponse
+// @Failure      404  {object}  domain.ErrorResponse
+// @Router       /api/v1/company-exports/{id} [get]
+func (h *CompanyExportHandler) GetCompanyExport(c *fiber.Ctx) error {
+	job, status, errResp := h.loadAccessibleJob(c)
+	if errResp != nil {
+		return c.Status(status).JSON(errResp)
+	}
+	return c.JSON(job)
+}
+
+// CreateDownloadLink godoc
+// @Summary      Buat link unduh arsip export
+// @Description  Menerbitkan link unduh sementara untuk arsip export yang sudah selesai. Link bisa dibuka tanpa login sampai expires_at.
+// @Tags         Companies
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Export job ID"
+// @Success      200  {object}  domain.CompanyExportDownloadLink
+// @Failure      403  {object}  domain.ErrorResponse
+// @Failure      404  {object}  domain.ErrorResponse
+// @Failure      409  {object}  domain.ErrorResponse
+// @Router       /api/v1/company-exports/{id}/download-link [post]
+// @note         Catatan Teknis:
+// @note         1. Link berlaku COMPANY_EXPORT_LINK_TTL_MINUTES menit (default 60) dan tidak pernah melewati expires_at arsip
+// @note         2. Setiap pembuatan link dan setiap unduhan dicatat di audit log
+func (h *CompanyExportHandler) CreateDownloadLink(c *fiber.Ctx) error {
+	job, status, errResp := h.loadAccessibleJob(c)
+	if errResp != nil {
+		return c.Status(status).JSON(errResp)
+	}
+
+	token, expiresAt, err := h.exportUC.CreateDownloadLink(job.ID, companyExportActor(c))
+	if err != nil {
+		if errors.Is(err, usecase.ErrCompanyExportNotReady) {
+			return c.Status(fiber.StatusConflict).JSON(domain.ErrorResponse{
+				Error:   "export_not_ready",
+				Message: "Arsip export masih diproses, gagal, atau sudah kedaluwarsa",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to create download link: " + err.Error(),
+		})
+	}
+
+	return c.JSON(domain.CompanyExportDownloadLink{
+		URL:       companyExportDownloadPath + token,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// DownloadCompanyExport godoc
+// @Summary      Unduh arsip export data company
+// @Description  Mengunduh arsip zip export melalui link sementara dari endpoint download-link. Endpoint ini tidak memerlukan login.
+// @Tags         Companies
+// @Produce      application/zip
+// @Param        token  path      string  true  "Token link unduh"
+// @Success      200    {file}    application/zip
+// @Failure      404    {object}  domain.ErrorResponse
+// @Failure      410    {object}  domain.ErrorResponse
+// @Router       /api/v1/company-exports/download/{token} [get]
+func (h *CompanyExportHandler) DownloadCompanyExport(c *fiber.Ctx) error {
+	job, err := h.exportUC.ResolveDownloadLink(c.Params("token"))
+	if err == nil {
+		var content []byte
+		content, err = h.exportUC.ReadArchive(job)
+		if err == nil {
+			audit.LogAction("", "", audit.ActionDownloadCompanyExport, audit.ResourceCompany, job.CompanyID, getClientIP(c), c.Get("User-Agent", ""), "success", map[string]interface{}{
+				"export_id":  job.ID,
+				"created_by": job.CreatedBy,
+			})
+			c.Set("Content-Type", "application/zip")
+			c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=company_export_%s.zip", job.ID[:8]))
+			c.Set("Cache-Control", "no-store")
+			return c.Send(content)
+		}
+	}
+
+	switch {
+	case errors.Is(err, usecase.ErrCompanyExportLinkInvalid):
+		return c.Status(fiber.StatusNotFound).JSON(domain.ErrorResponse{
+			Error:   "not_found",
+			Message: "Link unduh tidak valid atau sudah kedaluwarsa",
+		})
+	case errors.Is(err, usecase.ErrCompanyExportNotReady):
+		return c.Status(fiber.StatusGone).JSON(domain.ErrorResponse{
+			Error:   "export_expired",
+			Message: "Arsip export sudah tidak tersedia",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+		Error:   "internal_error",
+		Message: "Failed to read export archive: " + err.Error(),
+	})
+}
+
+// loadAccessibleJob mengambil job dan memastikan user adalah pembuat job (atau superadmin/administrator)
+func (h *CompanyExportHandler) loadAccessibleJob(c *fiber.Ctx) (*domain.CompanyExportJobModel, int, *domain.ErrorResponse) {
+	job, err := h.exportUC.GetJob(c.Params("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.StatusNotFound, &domain.ErrorResponse{Error: "not_found", Message: "Export job not found"}
+		}
+		return nil, fiber.StatusInternalServerError, &domain.ErrorResponse{Error: "internal_error", Message: "Failed to fetch export job: " + err.Error()}
+	}
+
+	roleName, _ := c.Locals("roleName").(string)
+	userID, _ := c.Locals("userID").(string)
+	if !utils.IsSuperAdminLike(roleName) && job.CreatedBy != userID {
+		return nil, fiber.StatusForbidden, &domain.ErrorResponse{Error: "forbidden", Message: "You don't have access to this export job"}
+	}
+	return job, fiber.StatusOK, nil
+}
+
+func companyExportActor(c *fiber.Ctx) usecase.CompanyExportActor {
+	userID, _ := c.Locals("userID").(string)
+	username, _ := c.Locals("username").(string)
+	return usecase.CompanyExportActor{
+		UserID:    userID,
+		Username:  username,
+		IPAddress: getClientIP(c),
+		UserAgent: c.Get("User-Agent", ""),
+	}
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/audit"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestApplyLegacyCompanyChangeKeys tests key details.changes update company tetap sama dengan format sebelum diff generik
+func TestApplyLegacyCompanyChangeKeys(t *testing.T) {
+	holding := "holding-1"
+	oldCompany := &domain.CompanyModel{
+		ID:   "c1",
+		Name: "PT Lama",
+		Shareholders: []domain.ShareholderModel{
+			{ID: "s1", Type: "Badan Hukum", Name: "Holding", OwnershipPercent: 100},
+		},
+		BusinessFields: []domain.BusinessFieldModel{{ID: "b1", IsMain: true, MainBusinessActivity: "Perdagangan"}},
+	}
+	newCompany := &domain.CompanyModel{
+		ID:                  "c1",
+		Name:                "PT Baru",
+		MainParentCompanyID: &holding,
+		Shareholders: []domain.ShareholderModel{
+			{ID: "s2", Type: "Badan Hukum", Name: "Holding", OwnershipPercent: 60},
+		},
+		BusinessFields: []domain.BusinessFieldModel{{ID: "b2", IsMain: true, MainBusinessActivity: "Jasa"}},
+	}
+
+	changes := make(map[string]interface{})
+	for field, change := range audit.DiffModels(oldCompany, newCompany) {
+		changes[field] = change
+	}
+	audit.FlattenCollectionChanges("shareholder", audit.DiffCollection(oldCompany.Shareholders, newCompany.Shareholders, usecase.ShareholderKey), changes)
+	for field, change := range audit.DiffModels(mainBusinessField(oldCompany), mainBusinessField(newCompany)) {
+		changes["business_"+field] = change
+	}
+	// Perubahan shareholder_company_id pada item yang sama (dicatat dengan key lama shareholder_<index>_company_id)
+	changes["shareholder_1_shareholder_company_id"] = audit.FieldChange{Old: holding, New: nil}
+	applyLegacyCompanyChangeKeys(changes)
+
+	assert.Equal(t, audit.FieldChange{Old: "PT Lama", New: "PT Baru"}, changes["name"])
+	assert.Equal(t, audit.FieldChange{Old: "", New: holding}, changes["main_parent_company"])
+	assert.Equal(t, audit.FieldChange{Old: float64(100), New: float64(60)}, changes["shareholder_0_ownership_percent"])
+	assert.Equal(t, audit.FieldChange{Old: holding, New: ""}, changes["shareholder_1_company_id"])
+	assert.Equal(t, audit.FieldChange{Old: "Perdagangan", New: "Jasa"}, changes["business_main_activity"])
+	assert.NotContains(t, changes, "shareholder_1_shareholder_company_id")
+	assert.NotContains(t, changes, "business_main_business_activity")
+}
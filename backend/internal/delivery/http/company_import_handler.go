@@ -0,0 +1,159 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/usecase"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/utils"
+)
+
+// CompanyImportHandler handles onboarding company secara bulk dari Excel/CSV
+type CompanyImportHandler struct {
+	importUC  usecase.CompanyImportUseCase
+	companyUC usecase.CompanyUseCase
+}
+
+// NewCompanyImportHandler creates a new company import handler
+func NewCompanyImportHandler(importUC usecase.CompanyImportUseCase, companyUC usecase.CompanyUseCase) *CompanyImportHandler {
+	return &CompanyImportHandler{
+		importUC:  importUC,
+		companyUC: companyUC,
+	}
+}
+
+// DownloadImportTemplate godoc
+// @Summary      Download template import company
+// @Description  Template kosong untuk import company bulk. Excel berisi sheet Perusahaan, Pemegang Saham, Bidang Usaha, Pengurus, dan Petunjuk. CSV berisi satu baris header per sheet dengan nama sheet di kolom pertama.
+// @Tags         Company Management
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security     BearerAuth
+// @Param        format  query     string  false  "xlsx (default) atau csv"
+// @Success      200     {file}    file
+// @Failure      400     {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/import/template [get]
+func (h *CompanyImportHandler) DownloadImportTemplate(c *fiber.Ctx) error {
+	format := strings.ToLower(c.Query("format", "xlsx"))
+	data, err := h.importUC.GenerateTemplate(format)
+	if errors.Is(err, usecase.ErrCompanyImportFormatUnsupported) {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_file_format",
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "template_failed",
+			Message: "Failed to generate template: " + err.Error(),
+		})
+	}
+
+	contentType := "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	if format == "csv" {
+		contentType = "text/csv; charset=utf-8"
+	}
+	c.Set("Content-Type", contentType)
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=template_import_perusahaan.%s", format))
+	return c.Send(data)
+}
+
+// ImportCompanies godoc
+// @Summary      Import company bulk
+// @Description  Upload template import company (Excel atau CSV). dry_run=true hanya memvalidasi dan mengembalikan preview; tanpa dry_run semua company dibuat dalam satu transaksi jika tidak ada error.
+// @Tags         Company Management
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        file     formData  file    true   "File .xlsx atau .csv sesuai template"
+// @Param        format   formData  string  false  "xlsx atau csv (default: dari ekstensi file)"
+// @Param        dry_run  query     bool    false  "true = preview tanpa menyimpan (default: false)"
+// @Success      200      {object}  domain.CompanyImportResult  "Preview (dry_run)"
+// @Success      201      {object}  domain.CompanyImportResult  "Semua company berhasil dibuat"
+// @Failure      400      {object}  domain.CompanyImportResult  "Ada error validasi, tidak ada company yang dibuat"
+// @Failure      403      {object}  domain.ErrorResponse
+// @Failure      500      {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/import [post]
+// @note         Catatan Teknis:
+// @note         1. Validasi sama dengan CreateCompanyFull: kode unik, induk ada, format NPWP (15/16 digit) dan NIB (13 digit), total kepemilikan maksimal 100%
+// @note         2. Kode Induk boleh merujuk company yang sudah ada atau baris lain di file; company dibuat berurutan induk lebih dulu
+// @note         3. User non-superadmin hanya bisa membuat company di bawah company-nya atau turunannya; baris tanpa Kode Induk ditempatkan di bawah company user
+// @note         4. Commit bersifat all-or-nothing: satu company gagal, seluruh import dibatalkan
+func (h *CompanyImportHandler) ImportCompanies(c *fiber.Ctx) error {
+	dryRun := false
+	if value := c.Query("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+				Error:   "invalid_request",
+				Message: "dry_run must be true or false",
+			})
+		}
+		dryRun = parsed
+	}
+
+	fileName, fileData, status, errResp := readUploadedFile(c)
+	if errResp != nil {
+		return c.Status(status).JSON(errResp)
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	username, _ := c.Locals("username").(string)
+	options := usecase.CompanyImportOptions{
+		DryRun:    dryRun,
+		UserID:    userID,
+		Username:  username,
+		IPAddress: getClientIP(c),
+		UserAgent: c.Get("User-Agent"),
+	}
+
+	// Non-superadmin: induk dibatasi ke company user dan turunannya (sama dengan CreateCompanyFull)
+	roleName, _ := c.Locals("roleName").(string)
+	if !utils.IsSuperAdminLike(roleName) {
+		allowed, status, errResp := accessibleCompanyCodes(c, h.companyUC)
+		if errResp != nil {
+			return c.Status(status).JSON(errResp)
+		}
+		userCompany, err := h.companyUC.GetCompanyByID(localCompanyID(c))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+				Error:   "internal_error",
+				Message: "Failed to get user company",
+			})
+		}
+		options.AllowedParentCodes = allowed
+		options.DefaultParentCode = userCompany.Code
+	}
+
+	result, err := h.importUC.Import(fileName, c.FormValue("format"), fileData, options)
+	if errors.Is(err, usecase.ErrCompanyImportFormatUnsupported) {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_file_format",
+			Message: err.Error(),
+		})
+	}
+	if errors.Is(err, usecase.ErrInvalidCompanyImportFile) {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_import_file",
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "import_failed",
+			Message: err.Error(),
+		})
+	}
+
+	switch {
+	case result.Committed:
+		return c.Status(fiber.StatusCreated).JSON(result)
+	case !result.DryRun:
+		return c.Status(fiber.StatusBadRequest).JSON(result)
+	default:
+		return c.JSON(result)
+	}
+}
@@ -0,0 +1,223 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/usecase"
+	"gorm.io/gorm"
+)
+
+// CompanyRestructuringHandler handles aksi restrukturisasi company (pindah subtree, merge, pembubaran)
+type CompanyRestructuringHandler struct {
+	restructuringUC usecase.CompanyRestructuringUseCase
+	companyUC       usecase.CompanyUseCase
+}
+
+// NewCompanyRestructuringHandler creates a new company restructuring handler
+func NewCompanyRestructuringHandler(restructuringUC usecase.CompanyRestructuringUseCase, companyUC usecase.CompanyUseCase) *CompanyRestructuringHandler {
+	return &CompanyRestructuringHandler{
+		restructuringUC: restructuringUC,
+		companyUC:       companyUC,
+	}
+}
+
+// MoveCompany godoc
+// @Summary      Move company subtree
+// @Description  Memindahkan company beserta seluruh anak perusahaannya ke induk baru. Level seluruh subtree dihitung ulang
+// @Tags         Company Management
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                     true   "Company ID"
+// @Param        preview  query     bool                       false  "true untuk melihat dampak tanpa menyimpan"
+// @Param        request  body      domain.MoveCompanyRequest  true   "Induk baru, tanggal efektif, dan alasan"
+// @Success      200      {object}  domain.CompanyRestructuringResult
+// @Failure      400      {object}  domain.ErrorResponse
+// @Failure      403      {object}  domain.ErrorResponse
+// @Failure      404      {object}  domain.ErrorResponse
+// @Failure      409      {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/{id}/move [post]
+// @note         Catatan Teknis:
+// @note         1. Induk baru tidak boleh company itu sendiri atau turunannya (mencegah siklus hierarki)
+// @note         2. Authorization: superadmin/administrator, atau admin yang mengelola company dan induk baru
+func (h *CompanyRestructuringHandler) MoveCompany(c *fiber.Ctx) error {
+	var req domain.MoveCompanyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return invalidRestructuringBody(c, err)
+	}
+	companyID := c.Params("id")
+	if !canAccessCompany(c, h.companyUC, companyID, true) || (req.NewParentID != "" && !canAccessCompany(c, h.companyUC, req.NewParentID, true)) {
+		return forbiddenCompany(c)
+	}
+
+	options, errResp := restructuringOptions(c)
+	if errResp != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errResp)
+	}
+	result, err := h.restructuringUC.MoveCompany(companyID, &req, options)
+	if err != nil {
+		return restructuringError(c, err)
+	}
+	return c.JSON(result)
+}
+
+// MergeCompany godoc
+// @Summary      Merge company into another company
+// @Description  Menggabungkan company ke company penerima: anak perusahaan, folder dan dokumen, laporan keuangan, user, assignment, dan kepemilikan saham dipindah, lalu company asal dibubarkan
+// @Tags         Company Management
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                      true   "Company ID yang di-merge (company asal)"
+// @Param        preview  query     bool                        false  "true untuk melihat dampak tanpa menyimpan"
+// @Param        request  body      domain.MergeCompanyRequest  true   "Company penerima, tanggal efektif, dan alasan"
+// @Success      200      {object}  domain.CompanyRestructuringResult
+// @Failure      400      {object}  domain.ErrorResponse
+// @Failure      403      {object}  domain.ErrorResponse
+// @Failure      404      {object}  domain.ErrorResponse
+// @Failure      409      {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/{id}/merge [post]
+// @note         Catatan Teknis:
+// @note         1. Semua perubahan dalam satu transaksi; company penerima tidak boleh turunan company asal
+// @note         2. Laporan keuangan dengan periode yang sudah ada di penerima tetap di company asal (financial_report_conflicts)
+// @note         3. Assignment user yang sudah ada di penerima dinonaktifkan, bukan dipindah
+func (h *CompanyRestructuringHandler) MergeCompany(c *fiber.Ctx) error {
+	var req domain.MergeCompanyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return invalidRestructuringBody(c, err)
+	}
+	companyID := c.Params("id")
+	if !canAccessCompany(c, h.companyUC, companyID, true) || (req.TargetCompanyID != "" && !canAccessCompany(c, h.companyUC, req.TargetCompanyID, true)) {
+		return forbiddenCompany(c)
+	}
+
+	options, errResp := restructuringOptions(c)
+	if errResp != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errResp)
+	}
+	result, err := h.restructuringUC.MergeCompany(companyID, &req, options)
+	if err != nil {
+		return restructuringError(c, err)
+	}
+	return c.JSON(result)
+}
+
+// DissolveCompany godoc
+// @Summary      Dissolve company
+// @Description  Membubarkan company (soft): status Dibubarkan dengan tanggal efektif, data dan riwayat tetap tersimpan tetapi hanya bisa dibaca
+// @Tags         Company Management
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                         true   "Company ID"
+// @Param        preview  query     bool                           false  "true untuk melihat dampak tanpa menyimpan"
+// @Param        request  body      domain.DissolveCompanyRequest  true   "Tanggal efektif dan alasan"
+// @Success      200      {object}  domain.CompanyRestructuringResult
+// @Failure      400      {object}  domain.ErrorResponse
+// @Failure      403      {object}  domain.ErrorResponse
+// @Failure      404      {object}  domain.ErrorResponse
+// @Failure      409      {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/{id}/dissolve [post]
+// @note         Catatan Teknis:
+// @note         1. Company yang masih memiliki anak perusahaan aktif tidak bisa dibubarkan (409)
+// @note         2. Setelah dibubarkan, update profil, status, dan laporan keuangan company ditolak
+func (h *CompanyRestructuringHandler) DissolveCompany(c *fiber.Ctx) error {
+	var req domain.DissolveCompanyRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return invalidRestructuringBody(c, err)
+		}
+	}
+	companyID := c.Params("id")
+	if !canAccessCompany(c, h.companyUC, companyID, true) {
+		return forbiddenCompany(c)
+	}
+
+	options, errResp := restructuringOptions(c)
+	if errResp != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errResp)
+	}
+	result, err := h.restructuringUC.DissolveCompany(companyID, &req, options)
+	if err != nil {
+		return restructuringError(c, err)
+	}
+	return c.JSON(result)
+}
+
+// GetRestructuringEvents godoc
+// @Summary      List company restructuring events
+// @Description  Riwayat restrukturisasi yang melibatkan company (sebagai company yang dipindah/di-merge/dibubarkan, induk lama, induk baru, atau penerima merger)
+// @Tags         Company Management
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Company ID"
+// @Success      200  {array}   domain.CompanyRestructuringEventModel
+// @Failure      403  {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/{id}/restructuring-events [get]
+func (h *CompanyRestructuringHandler) GetRestructuringEvents(c *fiber.Ctx) error {
+	companyID := c.Params("id")
+	if !canAccessCompany(c, h.companyUC, companyID, false) {
+		return forbiddenCompany(c)
+	}
+
+	events, err := h.restructuringUC.GetRestructuringEvents(companyID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+	}
+	return c.JSON(events)
+}
+
+// restructuringOptions membaca query preview dan identitas user untuk audit
+func restructuringOptions(c *fiber.Ctx) (usecase.CompanyRestructuringOptions, *domain.ErrorResponse) {
+	userID, _ := c.Locals("userID").(string)
+	username, _ := c.Locals("username").(string)
+	options := usecase.CompanyRestructuringOptions{
+		UserID:    userID,
+		Username:  username,
+		IPAddress: getClientIP(c),
+		UserAgent: c.Get("User-Agent"),
+	}
+	if value := c.Query("preview"); value != "" {
+		preview, err := strconv.ParseBool(value)
+		if err != nil {
+			return options, &domain.ErrorResponse{
+				Error:   "invalid_request",
+				Message: "preview must be true or false",
+			}
+		}
+		options.Preview = preview
+	}
+	return options, nil
+}
+
+func invalidRestructuringBody(c *fiber.Ctx, err error) error {
+	return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+		Error:   "invalid_request",
+		Message: "Invalid request body: " + err.Error(),
+	})
+}
+
+func restructuringError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(domain.ErrorResponse{
+			Error:   "not_found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrCompanyDissolved), errors.Is(err, usecase.ErrCompanyHasActiveChildren):
+		return c.Status(fiber.StatusConflict).JSON(domain.ErrorResponse{
+			Error:   "conflict",
+			Message: err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+		Error:   "restructuring_failed",
+		Message: err.Error(),
+	})
+}
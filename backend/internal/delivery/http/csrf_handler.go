@@ -19,7 +19,7 @@ import (
 // @Router       /api/v1/csrf-token [get]
 // @note         Catatan Teknis:
 // @note         1. Public Endpoint: Endpoint ini tidak memerlukan authentication
-// @note         2. CSRF Token: Token CSRF berlaku selama 24 jam dan disimpan dalam memory (bisa diganti dengan Redis untuk production)
+// @note         2. CSRF Token: Token CSRF berlaku selama 24 jam dan disimpan di kvstore (Redis jika REDIS_URL diset, selain itu memory per instance)
 // @note         3. Double-Submit Cookie: Token dikembalikan dalam response body dan juga disimpan dalam cookie untuk keamanan
 // @note         4. Usage: Token harus dikirim dalam header X-CSRF-Token untuk request POST, PUT, DELETE, PATCH
 // @note         5. Cookie: Cookie csrf_token menggunakan SameSite: Lax untuk development, Strict untuk production
@@ -32,8 +32,13 @@ func GetCSRFTokenHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	// Simpan token
-	middleware.StoreCSRFToken(token)
+	// Simpan token di store bersama (memory atau Redis)
+	if err := middleware.StoreCSRFToken(token); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to store CSRF token",
+		})
+	}
 
 	// Set cookie dengan CSRF token (opsional, untuk double submit cookie pattern)
 	isHTTPS := os.Getenv("ENV") == "production" || 
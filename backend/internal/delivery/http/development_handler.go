@@ -228,7 +228,7 @@ func (h *DevelopmentHandler) CheckSeederDataExists(c *fiber.Ctx) error {
 
 // ResetReportData handles resetting all report data
 // @Summary      Reset Data Reports
-// @Description  Menghapus semua data reports (hanya superadmin)
+// @Description  Menghapus data reports hasil report seeder (hanya superadmin). Realisasi yang diinput atau diimport user tidak ikut terhapus.
 // @Tags         Development
 // @Accept       json
 // @Produce      json
@@ -0,0 +1,372 @@
+package http
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/audit"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/usecase"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// DirectorTermHandler handles director term lifecycle HTTP requests (riwayat masa jabatan, perpanjangan, kekosongan jabatan)
+type DirectorTermHandler struct {
+	termUC    usecase.DirectorTermUseCase
+	companyUC usecase.CompanyUseCase
+}
+
+// NewDirectorTermHandler creates a new director term handler
+func NewDirectorTermHandler(termUC usecase.DirectorTermUseCase, companyUC usecase.CompanyUseCase) *DirectorTermHandler {
+	return &DirectorTermHandler{
+		termUC:    termUC,
+		companyUC: companyUC,
+	}
+}
+
+// GetDirectorTerms godoc
+// @Summary      Get director term history
+// @Description  Mengambil riwayat masa jabatan pengurus (pengangkatan dan pengangkatan kembali) beserta dokumen SK/RUPS
+// @Tags         Director Terms
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Director ID"
+// @Success      200  {array}   domain.DirectorTermModel
+// @Failure      403  {object}  domain.ErrorResponse
+// @Failure      404  {object}  domain.ErrorResponse
+// @Router       /api/v1/directors/{id}/terms [get]
+// @Security     BearerAuth
+// @note         Catatan Teknis:
+// @note         1. Data Lama: Pengurus tanpa riwayat otomatis dibuatkan masa jabatan pertama dari start_date/end_date
+func (h *DirectorTermHandler) GetDirectorTerms(c *fiber.Ctx) error {
+	director, err := h.termUC.GetDirector(c.Params("id"))
+	if err != nil {
+		return notFoundOrError(c, err, "Director not found")
+	}
+	if !h.canAccessCompany(c, director.CompanyID, false) {
+		return forbiddenCompany(c)
+	}
+
+	terms, err := h.termUC.GetTerms(director.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to get director terms: " + err.Error(),
+		})
+	}
+	return c.JSON(terms)
+}
+
+// RenewDirectorTerm godoc
+// @Summary      Renew director term
+// @Description  Menutup masa jabatan aktif dan membuka masa jabatan baru (pengangkatan kembali), opsional dengan dokumen SK/RUPS
+// @Tags         Director Terms
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                           true  "Director ID"
+// @Param        payload  body      domain.RenewDirectorTermRequest  true  "Data masa jabatan baru"
+// @Success      201      {object}  domain.DirectorTermModel
+// @Failure      400      {object}  domain.ErrorResponse
+// @Failure      403      {object}  domain.ErrorResponse
+// @Failure      404      {object}  domain.ErrorResponse
+// @Router       /api/v1/directors/{id}/renew [post]
+// @Security     BearerAuth
+// @note         Catatan Teknis:
+// @note         1. Authorization: Superadmin/administrator, atau admin untuk company sendiri dan turunannya
+// @note         2. Tanggal: start_date default sehari setelah akhir masa jabatan aktif, masa jabatan lama dipotong jika tumpang tindih
+// @note         3. Dokumen SK/RUPS: resolution_document_id harus dokumen dengan director_id pengurus yang sama
+// @note         4. Eskalasi: Rantai eskalasi notifikasi masa jabatan yang masih terbuka otomatis ditutup
+func (h *DirectorTermHandler) RenewDirectorTerm(c *fiber.Ctx) error {
+	director, err := h.termUC.GetDirector(c.Params("id"))
+	if err != nil {
+		return notFoundOrError(c, err, "Director not found")
+	}
+	if !h.canAccessCompany(c, director.CompanyID, true) {
+		return forbiddenCompany(c)
+	}
+
+	var req domain.RenewDirectorTermRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	username, _ := c.Locals("username").(string)
+
+	term, err := h.termUC.RenewTerm(director.ID, &req, userID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "renew_failed",
+			Message: err.Error(),
+		})
+	}
+
+	audit.LogAction(userID, username, audit.ActionRenewDirectorTerm, audit.ResourceDirector, director.ID, getClientIP(c), c.Get("User-Agent"), audit.StatusSuccess, map[string]interface{}{
+		"term_id":                term.ID,
+		"term_number":            term.TermNumber,
+		"company_id":             term.CompanyID,
+		"position":               term.Position,
+		"start_date":             term.StartDate,
+		"end_date":               term.EndDate,
+		"resolution_document_id": term.ResolutionDocumentID,
+	})
+
+	return c.Status(fiber.StatusCreated).JSON(term)
+}
+
+// LinkTermResolution godoc
+// @Summary      Link SK/RUPS document to director term
+// @Description  Menautkan dokumen SK/RUPS ke masa jabatan pengurus
+// @Tags         Director Terms
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                            true  "Director Term ID"
+// @Param        payload  body      domain.LinkTermResolutionRequest  true  "Dokumen SK/RUPS"
+// @Success      200      {object}  domain.DirectorTermModel
+// @Failure      400      {object}  domain.ErrorResponse
+// @Failure      403      {object}  domain.ErrorResponse
+// @Failure      404      {object}  domain.ErrorResponse
+// @Router       /api/v1/director-terms/{id}/resolution [put]
+// @Security     BearerAuth
+func (h *DirectorTermHandler) LinkTermResolution(c *fiber.Ctx) error {
+	term, err := h.termUC.GetTerm(c.Params("id"))
+	if err != nil {
+		return notFoundOrError(c, err, "Director term not found")
+	}
+	if !h.canAccessCompany(c, term.CompanyID, true) {
+		return forbiddenCompany(c)
+	}
+
+	var req domain.LinkTermResolutionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	username, _ := c.Locals("username").(string)
+
+	updated, err := h.termUC.LinkResolution(term.ID, &req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "link_failed",
+			Message: err.Error(),
+		})
+	}
+
+	audit.LogAction(userID, username, audit.ActionLinkTermResolution, audit.ResourceDirector, updated.DirectorID, getClientIP(c), c.Get("User-Agent"), audit.StatusSuccess, map[string]interface{}{
+		"term_id":                updated.ID,
+		"resolution_document_id": updated.ResolutionDocumentID,
+		"resolution_number":      updated.ResolutionNumber,
+	})
+
+	return c.JSON(updated)
+}
+
+// GetPositionRequirements godoc
+// @Summary      Get required director positions
+// @Description  Mengambil daftar jabatan pengurus yang wajib terisi di company (dasar deteksi kekosongan jabatan)
+// @Tags         Director Terms
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Company ID"
+// @Success      200  {array}   domain.CompanyPositionRequirementModel
+// @Failure      403  {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/{id}/position-requirements [get]
+// @Security     BearerAuth
+func (h *DirectorTermHandler) GetPositionRequirements(c *fiber.Ctx) error {
+	companyID := c.Params("id")
+	if !h.canAccessCompany(c, companyID, false) {
+		return forbiddenCompany(c)
+	}
+
+	requirements, err := h.termUC.GetPositionRequirements(companyID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to get position requirements: " + err.Error(),
+		})
+	}
+	return c.JSON(requirements)
+}
+
+// UpdatePositionRequirements godoc
+// @Summary      Update required director positions
+// @Description  Mengganti daftar jabatan pengurus wajib untuk company (posisi dari master jabatan pengurus)
+// @Tags         Director Terms
+// @Accept       json
+// @Produce      json
+// @Param        id            path      string                               true  "Company ID"
+// @Param        requirements  body      []domain.PositionRequirementRequest  true  "Daftar jabatan wajib"
+// @Success      200           {array}   domain.CompanyPositionRequirementModel
+// @Failure      400           {object}  domain.ErrorResponse
+// @Failure      403           {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/{id}/position-requirements [put]
+// @Security     BearerAuth
+// @note         Catatan Teknis:
+// @note         1. Authorization: Superadmin/administrator, atau admin untuk company sendiri dan turunannya
+// @note         2. Replace: Daftar yang dikirim menggantikan seluruh daftar sebelumnya, kirim array kosong untuk menghapus semua
+func (h *DirectorTermHandler) UpdatePositionRequirements(c *fiber.Ctx) error {
+	companyID := c.Params("id")
+	if !h.canAccessCompany(c, companyID, true) {
+		return forbiddenCompany(c)
+	}
+
+	var reqs []domain.PositionRequirementRequest
+	if err := c.BodyParser(&reqs); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	username, _ := c.Locals("username").(string)
+
+	requirements, err := h.termUC.SetPositionRequirements(companyID, reqs, userID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "update_failed",
+			Message: err.Error(),
+		})
+	}
+
+	audit.LogAction(userID, username, audit.ActionUpdatePositionRequirements, audit.ResourceCompany, companyID, getClientIP(c), c.Get("User-Agent"), audit.StatusSuccess, map[string]interface{}{
+		"requirements": reqs,
+	})
+
+	return c.JSON(requirements)
+}
+
+// GetDirectorVacancies godoc
+// @Summary      Detect director vacancies
+// @Description  Mendeteksi jabatan wajib yang kosong atau kurang terisi pada tanggal tertentu
+// @Tags         Director Terms
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string  true   "Company ID"
+// @Param        date  query     string  false  "Tanggal acuan YYYY-MM-DD (default: hari ini)"
+// @Success      200   {array}   domain.PositionVacancy
+// @Failure      400   {object}  domain.ErrorResponse
+// @Failure      403   {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/{id}/director-vacancies [get]
+// @Security     BearerAuth
+func (h *DirectorTermHandler) GetDirectorVacancies(c *fiber.Ctx) error {
+	companyID := c.Params("id")
+	if !h.canAccessCompany(c, companyID, false) {
+		return forbiddenCompany(c)
+	}
+	asOf, ok := parseAsOfDate(c)
+	if !ok {
+		return invalidAsOfDate(c)
+	}
+
+	vacancies, err := h.termUC.DetectVacancies(companyID, asOf)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to detect vacancies: " + err.Error(),
+		})
+	}
+	return c.JSON(vacancies)
+}
+
+// GetBoardComposition godoc
+// @Summary      Board composition report
+// @Description  Laporan susunan pengurus company pada tanggal tertentu: anggota aktif, jumlah per jabatan, dan kekosongan jabatan wajib
+// @Tags         Director Terms
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string  true   "Company ID"
+// @Param        date  query     string  false  "Tanggal acuan YYYY-MM-DD (default: hari ini)"
+// @Success      200   {object}  domain.BoardCompositionReport
+// @Failure      400   {object}  domain.ErrorResponse
+// @Failure      403   {object}  domain.ErrorResponse
+// @Failure      404   {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/{id}/board-composition [get]
+// @Security     BearerAuth
+// @note         Catatan Teknis:
+// @note         1. Historis: Tanggal lampau memakai riwayat masa jabatan, termasuk pengurus yang sudah dihapus
+func (h *DirectorTermHandler) GetBoardComposition(c *fiber.Ctx) error {
+	companyID := c.Params("id")
+	if !h.canAccessCompany(c, companyID, false) {
+		return forbiddenCompany(c)
+	}
+	asOf, ok := parseAsOfDate(c)
+	if !ok {
+		return invalidAsOfDate(c)
+	}
+
+	report, err := h.termUC.GetBoardComposition(companyID, asOf)
+	if err != nil {
+		return notFoundOrError(c, err, "Company not found")
+	}
+	return c.JSON(report)
+}
+
+// canAccessCompany mengecek akses requester ke company; manage=true hanya untuk superadmin/administrator dan admin
+func (h *DirectorTermHandler) canAccessCompany(c *fiber.Ctx, companyID string, manage bool) bool {
+	return canAccessCompany(c, h.companyUC, companyID, manage)
+}
+
+// canAccessCompany dipakai bersama handler lain yang butuh cek akses company yang sama
+func canAccessCompany(c *fiber.Ctx, companyUC usecase.CompanyUseCase, companyID string, manage bool) bool {
+	roleName, _ := c.Locals("roleName").(string)
+	if utils.IsSuperAdminLike(roleName) {
+		return true
+	}
+	if manage && strings.ToLower(roleName) != "admin" {
+		return false
+	}
+	userCompanyID := localCompanyID(c)
+	if userCompanyID == "" {
+		return false
+	}
+	hasAccess, err := companyUC.ValidateCompanyAccess(userCompanyID, companyID)
+	return err == nil && hasAccess
+}
+
+// parseAsOfDate membaca query date (YYYY-MM-DD), default hari ini
+func parseAsOfDate(c *fiber.Ctx) (time.Time, bool) {
+	if dateStr := c.Query("date"); dateStr != "" {
+		asOf, err := time.Parse("2006-01-02", dateStr)
+		return asOf, err == nil
+	}
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), true
+}
+
+func invalidAsOfDate(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+		Error:   "invalid_date",
+		Message: "date must be in YYYY-MM-DD format",
+	})
+}
+
+func forbiddenCompany(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusForbidden).JSON(domain.ErrorResponse{
+		Error:   "forbidden",
+		Message: "You don't have access to this company",
+	})
+}
+
+func notFoundOrError(c *fiber.Ctx, err error, notFoundMessage string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(domain.ErrorResponse{
+			Error:   "not_found",
+			Message: notFoundMessage,
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+		Error:   "internal_error",
+		Message: err.Error(),
+	})
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -139,7 +140,7 @@ func (h *DocumentHandler) CreateFolder(c *fiber.Ctx) error {
 		})
 	}
 	username, _ := c.Locals("username").(string)
-	audit.LogAction(userIDStr, username, audit.ActionCreateDoc, audit.ResourceDocument, folder.ID, getClientIP(c), c.Get("User-Agent"), audit.StatusSuccess, map[string]interface{}{
+	audit.LogAction(userIDStr, username, audit.ActionCreateDoc, audit.ResourceFolder, folder.ID, getClientIP(c), c.Get("User-Agent"), audit.StatusSuccess, map[string]interface{}{
 		"operation":  "create_folder",
 		"name":       payload.Name,
 		"parent_id":  payload.ParentID,
@@ -196,6 +197,8 @@ func (h *DocumentHandler) UpdateFolder(c *fiber.Ctx) error {
 		})
 	}
 
+	oldFolder, _ := h.docUseCase.GetFolderByID(id)
+
 	folder, err := h.docUseCase.UpdateFolderName(id, payload.Name, userCompanyID, roleName)
 	if err != nil {
 		status := fiber.StatusBadRequest
@@ -209,7 +212,7 @@ func (h *DocumentHandler) UpdateFolder(c *fiber.Ctx) error {
 	}
 
 	username, _ := c.Locals("username").(string)
-	audit.LogAction(userIDStr, username, audit.ActionUpdateDoc, audit.ResourceDocument, id, getClientIP(c), c.Get("User-Agent"), audit.StatusSuccess, map[string]interface{}{
+	audit.LogChanges(userIDStr, username, audit.ActionUpdateDoc, audit.ResourceFolder, id, getClientIP(c), c.Get("User-Agent"), audit.DiffModels(oldFolder, folder), map[string]interface{}{
 		"operation": "rename_folder",
 		"name":      payload.Name,
 	})
@@ -219,7 +222,7 @@ func (h *DocumentHandler) UpdateFolder(c *fiber.Ctx) error {
 
 // DeleteFolder handles deleting a folder and all its documents
 // @Summary      Hapus Folder
-// @Description  Menghapus folder beserta semua dokumen di dalamnya. Hanya owner folder atau superadmin/administrator yang dapat menghapus folder.
+// @Description  Memindahkan folder beserta semua sub folder dan dokumen di dalamnya ke recycle bin. Hanya superadmin/administrator yang dapat menghapus folder. Folder dapat di-restore dari recycle bin sampai di-purge (manual atau otomatis setelah DOCUMENT_TRASH_RETENTION_DAYS).
 // @Tags         Documents
 // @Accept       json
 // @Produce      json
@@ -229,6 +232,7 @@ func (h *DocumentHandler) UpdateFolder(c *fiber.Ctx) error {
 // @Failure      400  {object}  domain.ErrorResponse  "Invalid request"
 // @Failure      401  {object}  domain.ErrorResponse  "Unauthorized"
 // @Failure      403  {object}  domain.ErrorResponse  "Forbidden"
+// @Failure      409  {object}  domain.ErrorResponse  "Folder atau isinya berada di bawah legal hold"
 // @Router       /api/v1/documents/folders/{id} [delete]
 func (h *DocumentHandler) DeleteFolder(c *fiber.Ctx) error {
 	id := c.Params("id")
@@ -261,7 +265,19 @@ func (h *DocumentHandler) DeleteFolder(c *fiber.Ctx) error {
 	}
 	// Superadmin bisa pass nil untuk userCompanyID dan tetap bisa delete
 
-	if err := h.docUseCase.DeleteFolder(id, userCompanyID, roleName); err != nil {
+	if err := h.docUseCase.DeleteFolder(id, userCompanyID, roleName, userIDStr); err != nil {
+		if errors.Is(err, usecase.ErrLegalHoldActive) {
+			username, _ := c.Locals("username").(string)
+			audit.LogAction(userIDStr, username, audit.ActionDeleteDoc, audit.ResourceFolder, id, getClientIP(c), c.Get("User-Agent"), audit.StatusFailure, map[string]interface{}{
+				"operation": "delete_folder",
+				"reason":    "legal_hold",
+				"error":     err.Error(),
+			})
+			return c.Status(fiber.StatusConflict).JSON(domain.ErrorResponse{
+				Error:   "legal_hold",
+				Message: err.Error(),
+			})
+		}
 		status := fiber.StatusBadRequest
 		if strings.Contains(err.Error(), "forbidden") {
 			status = fiber.StatusForbidden
@@ -273,12 +289,13 @@ func (h *DocumentHandler) DeleteFolder(c *fiber.Ctx) error {
 	}
 
 	username, _ := c.Locals("username").(string)
-	audit.LogAction(userIDStr, username, audit.ActionDeleteDoc, audit.ResourceDocument, id, getClientIP(c), c.Get("User-Agent"), audit.StatusSuccess, map[string]interface{}{
+	audit.LogAction(userIDStr, username, audit.ActionDeleteDoc, audit.ResourceFolder, id, getClientIP(c), c.Get("User-Agent"), audit.StatusSuccess, map[string]interface{}{
 		"operation": "delete_folder",
+		"trash":     true,
 	})
 
 	return c.JSON(fiber.Map{
-		"message": "Folder dan seluruh file di dalamnya telah dihapus",
+		"message": "Folder dan seluruh file di dalamnya telah dipindahkan ke recycle bin",
 	})
 }
 
@@ -750,6 +767,7 @@ func (h *DocumentHandler) UploadDocument(c *fiber.Ctx) error {
 		Status:      status,
 		UploaderID:  uploaderID,
 		Metadata:    metaMap,
+		Context:     c.UserContext(),
 	})
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
@@ -937,6 +955,7 @@ func (h *DocumentHandler) UpdateDocument(c *fiber.Ctx) error {
 			FileContentType: ftype,
 			FileData:        data,
 			FileSize:        fsize,
+			UpdatedBy:       userIDStr,
 		})
 		if err != nil {
 			return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
@@ -945,7 +964,7 @@ func (h *DocumentHandler) UpdateDocument(c *fiber.Ctx) error {
 			})
 		}
 		username, _ := c.Locals("username").(string)
-		audit.LogAction(userIDStr, username, audit.ActionUpdateDoc, audit.ResourceDocument, id, getClientIP(c), c.Get("User-Agent"), audit.StatusSuccess, map[string]interface{}{
+		audit.LogChanges(userIDStr, username, audit.ActionUpdateDoc, audit.ResourceDocument, id, getClientIP(c), c.Get("User-Agent"), audit.DiffModels(existingDoc, doc), map[string]interface{}{
 			"operation":     "update_document",
 			"folder_id":     folderPtr,
 			"title":         titlePtr,
@@ -1004,6 +1023,7 @@ func (h *DocumentHandler) UpdateDocument(c *fiber.Ctx) error {
 		Title:      payload.Title,
 		Status:     payload.Status,
 		Metadata:   payload.Metadata,
+		UpdatedBy:  userIDStr,
 	})
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
@@ -1013,7 +1033,7 @@ func (h *DocumentHandler) UpdateDocument(c *fiber.Ctx) error {
 	}
 
 	username, _ := c.Locals("username").(string)
-	audit.LogAction(userIDStr, username, audit.ActionUpdateDoc, audit.ResourceDocument, id, getClientIP(c), c.Get("User-Agent"), audit.StatusSuccess, map[string]interface{}{
+	audit.LogChanges(userIDStr, username, audit.ActionUpdateDoc, audit.ResourceDocument, id, getClientIP(c), c.Get("User-Agent"), audit.DiffModels(existingDoc, doc), map[string]interface{}{
 		"operation": "update_document",
 		"folder_id": payload.FolderID,
 		"title":     payload.Title,
@@ -1025,7 +1045,7 @@ func (h *DocumentHandler) UpdateDocument(c *fiber.Ctx) error {
 
 // DeleteDocument handles deleting a document
 // @Summary      Hapus Document
-// @Description  Menghapus dokumen. Hanya owner dokumen atau superadmin/administrator yang dapat menghapus dokumen.
+// @Description  Memindahkan dokumen ke recycle bin. Hanya owner dokumen atau superadmin/administrator yang dapat menghapus dokumen. File di storage baru dihapus saat dokumen di-purge dari recycle bin.
 // @Tags         Documents
 // @Accept       json
 // @Produce      json
@@ -1035,6 +1055,7 @@ func (h *DocumentHandler) UpdateDocument(c *fiber.Ctx) error {
 // @Failure      401  {object}  domain.ErrorResponse  "Unauthorized"
 // @Failure      403  {object}  domain.ErrorResponse  "Forbidden"
 // @Failure      404  {object}  domain.ErrorResponse  "Document tidak ditemukan"
+// @Failure      409  {object}  domain.ErrorResponse  "Dokumen berada di bawah legal hold"
 // @Failure      500  {object}  domain.ErrorResponse  "Internal server error"
 // @Router       /api/v1/documents/{id} [delete]
 func (h *DocumentHandler) DeleteDocument(c *fiber.Ctx) error {
@@ -1103,7 +1124,26 @@ func (h *DocumentHandler) DeleteDocument(c *fiber.Ctx) error {
 	}
 
 	// Delete document (superadmin dapat menghapus semua dokumen)
-	if err := h.docUseCase.DeleteDocument(id); err != nil {
+	if err := h.docUseCase.DeleteDocument(id, userIDStr); err != nil {
+		if errors.Is(err, usecase.ErrLegalHoldActive) {
+			username, _ := c.Locals("username").(string)
+			audit.LogAction(userIDStr, username, audit.ActionDeleteDoc, audit.ResourceDocument, id, getClientIP(c), c.Get("User-Agent"), audit.StatusFailure, map[string]interface{}{
+				"operation":     "delete_document",
+				"document_name": existingDoc.Name,
+				"reason":        "legal_hold",
+				"error":         err.Error(),
+			})
+			return c.Status(fiber.StatusConflict).JSON(domain.ErrorResponse{
+				Error:   "legal_hold",
+				Message: err.Error(),
+			})
+		}
+		if errors.Is(err, usecase.ErrCompanyDissolved) {
+			return c.Status(fiber.StatusConflict).JSON(domain.ErrorResponse{
+				Error:   "conflict",
+				Message: err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
 			Error:   "delete_failed",
 			Message: err.Error(),
@@ -1116,6 +1156,7 @@ func (h *DocumentHandler) DeleteDocument(c *fiber.Ctx) error {
 		"operation":     "delete_document",
 		"file_name":     existingDoc.FileName,
 		"document_name": existingDoc.Name,
+		"trash":         true,
 	}
 
 	// Tambahkan informasi folder jika ada
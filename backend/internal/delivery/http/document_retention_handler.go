@@ -0,0 +1,448 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/usecase"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// DocumentRetentionHandler handles jadwal retensi, legal hold, dan disposisi dokumen
+type DocumentRetentionHandler struct {
+	retentionUC usecase.DocumentRetentionUseCase
+}
+
+// NewDocumentRetentionHandler creates a new document retention handler
+func NewDocumentRetentionHandler(retentionUC usecase.DocumentRetentionUseCase) *DocumentRetentionHandler {
+	return &DocumentRetentionHandler{
+		retentionUC: retentionUC,
+	}
+}
+
+// SetRetentionSchedule godoc
+// @Summary      Atur jadwal retensi jenis dokumen
+// @Description  Mengatur masa retensi dokumen per jenis dokumen. Setelah masa retensi habis, dokumen diarsipkan dan menunggu review disposisi.
+// @Tags         Document Retention
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                                 true  "Document Type ID"
+// @Param        request  body      domain.UpdateRetentionScheduleRequest  true  "Jadwal retensi"
+// @Success      200      {object}  domain.DocumentTypeModel
+// @Failure      400      {object}  domain.ErrorResponse
+// @Failure      403      {object}  domain.ErrorResponse
+// @Failure      404      {object}  domain.ErrorResponse
+// @Router       /api/v1/document-types/{id}/retention [put]
+// @note         Catatan Teknis:
+// @note         1. Hanya superadmin/administrator
+// @note         2. retention_days null = dokumen disimpan permanen
+// @note         3. retention_basis: created_at (tanggal upload, default) atau expiry_date (metadata expired_date/expiry_date dokumen)
+func (h *DocumentRetentionHandler) SetRetentionSchedule(c *fiber.Ctx) error {
+	if !isRetentionAdmin(c) {
+		return retentionForbidden(c)
+	}
+
+	var req domain.UpdateRetentionScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	docType, err := h.retentionUC.SetRetentionSchedule(c.Params("id"), req.RetentionDays, req.RetentionBasis, retentionActor(c))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return notFoundOrError(c, err, "Document type not found")
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "update_failed",
+			Message: err.Error(),
+		})
+	}
+	return c.JSON(docType)
+}
+
+// ListLegalHolds godoc
+// @Summary      List legal hold
+// @Description  Mengambil daftar legal hold (aktif maupun yang sudah dilepas)
+// @Tags         Document Retention
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        resource_type  query     string  false  "Filter jenis resource (document, folder, company)"
+// @Param        resource_id    query     string  false  "Filter resource ID"
+// @Param        active         query     bool    false  "Hanya hold yang masih aktif"
+// @Param        page           query     int     false  "Page number (default: 1)"
+// @Param        page_size      query     int     false  "Page size (default: 20)"
+// @Success      200            {object}  map[string]interface{}
+// @Failure      403            {object}  domain.ErrorResponse
+// @Router       /api/v1/legal-holds [get]
+func (h *DocumentRetentionHandler) ListLegalHolds(c *fiber.Ctx) error {
+	if !isRetentionAdmin(c) {
+		return retentionForbidden(c)
+	}
+
+	page, pageSize := parsePagination(c)
+	holds, total, err := h.retentionUC.ListLegalHolds(repository.LegalHoldFilter{
+		ResourceType: c.Query("resource_type"),
+		ResourceID:   c.Query("resource_id"),
+		ActiveOnly:   c.QueryBool("active", false),
+		Limit:        pageSize,
+		Offset:       (page - 1) * pageSize,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to fetch legal holds: " + err.Error(),
+		})
+	}
+	return c.JSON(paginatedResponse(holds, total, page, pageSize))
+}
+
+// CreateLegalHold godoc
+// @Summary      Pasang legal hold
+// @Description  Memasang legal hold pada dokumen, folder (beserta sub folder dan dokumennya), atau company (seluruh dokumennya). Selama hold aktif, dokumen tidak bisa dihapus maupun didisposisi.
+// @Tags         Document Retention
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      domain.CreateLegalHoldRequest  true  "Legal hold"
+// @Success      201      {object}  domain.LegalHoldModel
+// @Failure      400      {object}  domain.ErrorResponse
+// @Failure      403      {object}  domain.ErrorResponse
+// @Failure      404      {object}  domain.ErrorResponse
+// @Router       /api/v1/legal-holds [post]
+func (h *DocumentRetentionHandler) CreateLegalHold(c *fiber.Ctx) error {
+	if !isRetentionAdmin(c) {
+		return retentionForbidden(c)
+	}
+
+	var req domain.CreateLegalHoldRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	hold, err := h.retentionUC.PlaceLegalHold(req, retentionActor(c))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return notFoundOrError(c, err, "Resource not found")
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusCreated).JSON(hold)
+}
+
+// ReleaseLegalHold godoc
+// @Summary      Lepas legal hold
+// @Description  Melepas legal hold. Riwayat hold tetap disimpan untuk laporan disposisi.
+// @Tags         Document Retention
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                          true   "Legal hold ID"
+// @Param        request  body      domain.ReleaseLegalHoldRequest  false  "Catatan pelepasan"
+// @Success      200      {object}  domain.LegalHoldModel
+// @Failure      400      {object}  domain.ErrorResponse
+// @Failure      403      {object}  domain.ErrorResponse
+// @Failure      404      {object}  domain.ErrorResponse
+// @Router       /api/v1/legal-holds/{id}/release [post]
+func (h *DocumentRetentionHandler) ReleaseLegalHold(c *fiber.Ctx) error {
+	if !isRetentionAdmin(c) {
+		return retentionForbidden(c)
+	}
+
+	var req domain.ReleaseLegalHoldRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+				Error:   "invalid_request",
+				Message: "Invalid request body: " + err.Error(),
+			})
+		}
+	}
+
+	hold, err := h.retentionUC.ReleaseLegalHold(c.Params("id"), req.Note, retentionActor(c))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return notFoundOrError(c, err, "Legal hold not found")
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "release_failed",
+			Message: err.Error(),
+		})
+	}
+	return c.JSON(hold)
+}
+
+// ListDispositions godoc
+// @Summary      List disposisi dokumen
+// @Description  Mengambil daftar disposisi dokumen (dokumen yang masa retensinya habis) beserta keputusan reviewer
+// @Tags         Document Retention
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        status      query     string  false  "Filter status (pending_review, deleted, retained)"
+// @Param        company_id  query     string  false  "Filter company ID"
+// @Param        from        query     string  false  "Diarsipkan sejak tanggal (YYYY-MM-DD)"
+// @Param        to          query     string  false  "Diarsipkan sampai tanggal (YYYY-MM-DD)"
+// @Param        page        query     int     false  "Page number (default: 1)"
+// @Param        page_size   query     int     false  "Page size (default: 20)"
+// @Success      200         {object}  map[string]interface{}
+// @Failure      400         {object}  domain.ErrorResponse
+// @Failure      403         {object}  domain.ErrorResponse
+// @Router       /api/v1/document-dispositions [get]
+func (h *DocumentRetentionHandler) ListDispositions(c *fiber.Ctx) error {
+	if !isRetentionAdmin(c) {
+		return retentionForbidden(c)
+	}
+
+	filter, err := dispositionFilterFromQuery(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+	}
+	page, pageSize := parsePagination(c)
+	filter.Limit = pageSize
+	filter.Offset = (page - 1) * pageSize
+
+	dispositions, total, err := h.retentionUC.ListDispositions(filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to fetch dispositions: " + err.Error(),
+		})
+	}
+	return c.JSON(paginatedResponse(dispositions, total, page, pageSize))
+}
+
+// RunDisposition godoc
+// @Summary      Jalankan evaluasi retensi
+// @Description  Menjalankan evaluasi retensi sekarang (biasanya dijalankan scheduler harian): dokumen yang masa retensinya habis diarsipkan dan menunggu review
+// @Tags         Document Retention
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  usecase.DispositionRunResult
+// @Failure      403  {object}  domain.ErrorResponse
+// @Router       /api/v1/document-dispositions/run [post]
+func (h *DocumentRetentionHandler) RunDisposition(c *fiber.Ctx) error {
+	if !isRetentionAdmin(c) {
+		return retentionForbidden(c)
+	}
+
+	result, err := h.retentionUC.RunDisposition()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to run disposition: " + err.Error(),
+		})
+	}
+	return c.JSON(result)
+}
+
+// ApproveDisposition godoc
+// @Summary      Setujui disposisi dokumen
+// @Description  Menyetujui disposisi: dokumen dan file-nya dihapus permanen. Catatan disposisi tetap disimpan untuk auditor.
+// @Tags         Document Retention
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                           true   "Disposition ID"
+// @Param        request  body      domain.ReviewDispositionRequest  false  "Catatan reviewer"
+// @Success      200      {object}  domain.DocumentDispositionModel
+// @Failure      403      {object}  domain.ErrorResponse
+// @Failure      404      {object}  domain.ErrorResponse
+// @Failure      409      {object}  domain.ErrorResponse
+// @Router       /api/v1/document-dispositions/{id}/approve [post]
+// @note         Catatan Teknis:
+// @note         1. Ditolak (409) jika dokumen berada di bawah legal hold atau disposisi sudah direview
+func (h *DocumentRetentionHandler) ApproveDisposition(c *fiber.Ctx) error {
+	if !isRetentionAdmin(c) {
+		return retentionForbidden(c)
+	}
+
+	var req domain.ReviewDispositionRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+				Error:   "invalid_request",
+				Message: "Invalid request body: " + err.Error(),
+			})
+		}
+	}
+
+	disposition, err := h.retentionUC.ApproveDisposition(c.Params("id"), req.Note, retentionActor(c))
+	if err != nil {
+		return dispositionError(c, err)
+	}
+	return c.JSON(disposition)
+}
+
+// RetainDisposition godoc
+// @Summary      Pertahankan dokumen
+// @Description  Menolak disposisi: dokumen dikembalikan ke status sebelumnya dan tidak dievaluasi ulang sampai retain_days berlalu
+// @Tags         Document Retention
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                           true  "Disposition ID"
+// @Param        request  body      domain.ReviewDispositionRequest  true  "Alasan dan lama dokumen dipertahankan"
+// @Success      200      {object}  domain.DocumentDispositionModel
+// @Failure      400      {object}  domain.ErrorResponse
+// @Failure      403      {object}  domain.ErrorResponse
+// @Failure      404      {object}  domain.ErrorResponse
+// @Failure      409      {object}  domain.ErrorResponse
+// @Router       /api/v1/document-dispositions/{id}/retain [post]
+func (h *DocumentRetentionHandler) RetainDisposition(c *fiber.Ctx) error {
+	if !isRetentionAdmin(c) {
+		return retentionForbidden(c)
+	}
+
+	var req domain.ReviewDispositionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	disposition, err := h.retentionUC.RetainDisposition(c.Params("id"), req.Note, req.RetainDays, retentionActor(c))
+	if err != nil {
+		return dispositionError(c, err)
+	}
+	return c.JSON(disposition)
+}
+
+// DownloadDispositionReport godoc
+// @Summary      Unduh laporan disposisi
+// @Description  Laporan disposisi untuk auditor (xlsx): ringkasan, seluruh disposisi beserta keputusan reviewer, dan riwayat legal hold pada periode
+// @Tags         Document Retention
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security     BearerAuth
+// @Param        status      query     string  false  "Filter status (pending_review, deleted, retained)"
+// @Param        company_id  query     string  false  "Filter company ID"
+// @Param        from        query     string  false  "Sejak tanggal (YYYY-MM-DD)"
+// @Param        to          query     string  false  "Sampai tanggal (YYYY-MM-DD)"
+// @Success      200         {file}    file
+// @Failure      400         {object}  domain.ErrorResponse
+// @Failure      403         {object}  domain.ErrorResponse
+// @Router       /api/v1/document-dispositions/report [get]
+func (h *DocumentRetentionHandler) DownloadDispositionReport(c *fiber.Ctx) error {
+	if !isRetentionAdmin(c) {
+		return retentionForbidden(c)
+	}
+
+	filter, err := dispositionFilterFromQuery(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+	}
+
+	content, err := h.retentionUC.BuildDispositionReport(filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to build disposition report: " + err.Error(),
+		})
+	}
+
+	c.Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=disposition_report_%s.xlsx", time.Now().Format("20060102")))
+	return c.Send(content)
+}
+
+// isRetentionAdmin: pengelolaan retensi, legal hold, dan disposisi hanya untuk superadmin/administrator
+func isRetentionAdmin(c *fiber.Ctx) bool {
+	roleName, _ := c.Locals("roleName").(string)
+	return utils.IsSuperAdminLike(roleName)
+}
+
+func retentionForbidden(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusForbidden).JSON(domain.ErrorResponse{
+		Error:   "forbidden",
+		Message: "Hanya superadmin dan administrator yang dapat mengelola retensi dokumen dan legal hold",
+	})
+}
+
+func retentionActor(c *fiber.Ctx) usecase.DocumentRetentionActor {
+	userID, _ := c.Locals("userID").(string)
+	username, _ := c.Locals("username").(string)
+	return usecase.DocumentRetentionActor{
+		UserID:    userID,
+		Username:  username,
+		IPAddress: getClientIP(c),
+		UserAgent: c.Get("User-Agent", ""),
+	}
+}
+
+func dispositionError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return notFoundOrError(c, err, "Disposition not found")
+	case errors.Is(err, usecase.ErrLegalHoldActive):
+		return c.Status(fiber.StatusConflict).JSON(domain.ErrorResponse{
+			Error:   "legal_hold",
+			Message: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrDispositionReviewed):
+		return c.Status(fiber.StatusConflict).JSON(domain.ErrorResponse{
+			Error:   "already_reviewed",
+			Message: err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+		Error:   "review_failed",
+		Message: err.Error(),
+	})
+}
+
+// dispositionFilterFromQuery membaca filter status, company_id, dan rentang tanggal (from/to inklusif)
+func dispositionFilterFromQuery(c *fiber.Ctx) (repository.DocumentDispositionFilter, error) {
+	filter := repository.DocumentDispositionFilter{
+		Status:    c.Query("status"),
+		CompanyID: c.Query("company_id"),
+	}
+	if from := c.Query("from"); from != "" {
+		parsed, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return filter, errors.New("format from tidak valid, gunakan YYYY-MM-DD")
+		}
+		filter.Since = &parsed
+	}
+	if to := c.Query("to"); to != "" {
+		parsed, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return filter, errors.New("format to tidak valid, gunakan YYYY-MM-DD")
+		}
+		endOfDay := parsed.Add(24*time.Hour - time.Nanosecond)
+		filter.Until = &endOfDay
+	}
+	return filter, nil
+}
+
+func paginatedResponse(data interface{}, total int64, page, pageSize int) fiber.Map {
+	return fiber.Map{
+		"data":        data,
+		"total":       total,
+		"page":        page,
+		"page_size":   pageSize,
+		"total_pages": int((total + int64(pageSize) - 1) / int64(pageSize)),
+	}
+}
@@ -0,0 +1,189 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/usecase"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/utils"
+)
+
+// DocumentTrashHandler handles recycle bin dokumen dan folder
+type DocumentTrashHandler struct {
+	trashUC   usecase.DocumentTrashUseCase
+	companyUC usecase.CompanyUseCase
+}
+
+// NewDocumentTrashHandler creates a new document trash handler
+func NewDocumentTrashHandler(trashUC usecase.DocumentTrashUseCase, companyUC usecase.CompanyUseCase) *DocumentTrashHandler {
+	return &DocumentTrashHandler{
+		trashUC:   trashUC,
+		companyUC: companyUC,
+	}
+}
+
+// ListTrash godoc
+// @Summary      List recycle bin dokumen
+// @Description  Mengambil daftar folder dan dokumen yang dihapus dan masih bisa di-restore. Isi folder yang ikut terhapus tidak ditampilkan terpisah, hanya jumlahnya (folder_count, document_count, total_size).
+// @Tags         Documents
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        company_id  query     string  false  "Filter company ID (default: company user; superadmin tanpa filter melihat semua)"
+// @Success      200         {array}   domain.DocumentTrashItem
+// @Failure      403         {object}  domain.ErrorResponse
+// @Failure      500         {object}  domain.ErrorResponse
+// @Router       /api/v1/documents/trash [get]
+// @note         Catatan Teknis:
+// @note         1. Superadmin/administrator melihat recycle bin semua company, user lain hanya company yang bisa diakses
+// @note         2. purge_at adalah waktu item dihapus permanen otomatis (DOCUMENT_TRASH_RETENTION_DAYS, default 30 hari)
+func (h *DocumentTrashHandler) ListTrash(c *fiber.Ctx) error {
+	roleName, _ := c.Locals("roleName").(string)
+	var companyID *string
+	if id := c.Query("company_id"); id != "" {
+		if !canAccessCompany(c, h.companyUC, id, false) {
+			return forbiddenCompany(c)
+		}
+		companyID = &id
+	} else if !utils.IsSuperAdminLike(roleName) {
+		userCompanyID := localCompanyID(c)
+		if userCompanyID == "" {
+			return forbiddenCompany(c)
+		}
+		companyID = &userCompanyID
+	}
+
+	items, err := h.trashUC.ListTrash(companyID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to fetch recycle bin: " + err.Error(),
+		})
+	}
+	return c.JSON(items)
+}
+
+// RestoreTrashItem godoc
+// @Summary      Restore item recycle bin
+// @Description  Mengembalikan folder (beserta seluruh sub folder dan dokumen yang ikut terhapus) atau dokumen dari recycle bin ke lokasi asalnya.
+// @Tags         Documents
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type  path      string  true  "Tipe item (folder atau document)"
+// @Param        id    path      string  true  "Folder ID atau Document ID"
+// @Success      200   {object}  domain.DocumentTrashItem
+// @Failure      400   {object}  domain.ErrorResponse
+// @Failure      403   {object}  domain.ErrorResponse
+// @Failure      404   {object}  domain.ErrorResponse
+// @Failure      409   {object}  domain.ErrorResponse  "Folder induk masih berada di recycle bin"
+// @Router       /api/v1/documents/trash/{type}/{id}/restore [post]
+// @note         Catatan Teknis:
+// @note         1. Hanya superadmin/administrator atau admin company pemilik item yang bisa me-restore
+// @note         2. Item di dalam folder yang terhapus di-restore lewat folder tersebut
+func (h *DocumentTrashHandler) RestoreTrashItem(c *fiber.Ctx) error {
+	item, err := h.trashUC.GetTrashItem(c.Params("type"), c.Params("id"))
+	if err != nil {
+		return trashError(c, err)
+	}
+	if !h.canManageItem(c, item) {
+		return forbiddenCompany(c)
+	}
+
+	restored, err := h.trashUC.Restore(item.Type, item.ID, documentTrashActor(c))
+	if err != nil {
+		return trashError(c, err)
+	}
+	return c.JSON(restored)
+}
+
+// PurgeTrashItem godoc
+// @Summary      Hapus permanen item recycle bin
+// @Description  Menghapus folder (beserta isinya) atau dokumen di recycle bin secara permanen, termasuk file di storage. Tidak bisa dibatalkan.
+// @Tags         Documents
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type  path      string  true  "Tipe item (folder atau document)"
+// @Param        id    path      string  true  "Folder ID atau Document ID"
+// @Success      200   {object}  domain.DocumentTrashItem
+// @Failure      400   {object}  domain.ErrorResponse
+// @Failure      403   {object}  domain.ErrorResponse
+// @Failure      404   {object}  domain.ErrorResponse
+// @Failure      409   {object}  domain.ErrorResponse  "Item berada di bawah legal hold"
+// @Router       /api/v1/documents/trash/{type}/{id} [delete]
+// @note         Catatan Teknis:
+// @note         1. Hanya superadmin/administrator yang bisa purge manual
+// @note         2. Item yang tercakup legal hold aktif tidak bisa di-purge (manual maupun otomatis)
+func (h *DocumentTrashHandler) PurgeTrashItem(c *fiber.Ctx) error {
+	roleName, _ := c.Locals("roleName").(string)
+	if !utils.IsSuperAdminLike(roleName) {
+		return c.Status(fiber.StatusForbidden).JSON(domain.ErrorResponse{
+			Error:   "forbidden",
+			Message: "Hanya superadmin dan administrator yang dapat menghapus permanen item recycle bin",
+		})
+	}
+
+	purged, err := h.trashUC.Purge(c.Params("type"), c.Params("id"), documentTrashActor(c))
+	if err != nil {
+		return trashError(c, err)
+	}
+	return c.JSON(fiber.Map{
+		"message": "Item telah dihapus permanen",
+		"item":    purged,
+	})
+}
+
+// canManageItem mengecek akses restore: superadmin, atau admin company pemilik item
+func (h *DocumentTrashHandler) canManageItem(c *fiber.Ctx, item *domain.DocumentTrashItem) bool {
+	roleName, _ := c.Locals("roleName").(string)
+	if utils.IsSuperAdminLike(roleName) {
+		return true
+	}
+	if item.CompanyID == nil {
+		return false
+	}
+	return canAccessCompany(c, h.companyUC, *item.CompanyID, true)
+}
+
+func documentTrashActor(c *fiber.Ctx) usecase.DocumentTrashActor {
+	userID, _ := c.Locals("userID").(string)
+	username, _ := c.Locals("username").(string)
+	return usecase.DocumentTrashActor{
+		UserID:    userID,
+		Username:  username,
+		IPAddress: getClientIP(c),
+		UserAgent: c.Get("User-Agent", ""),
+	}
+}
+
+func trashError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrTrashItemNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(domain.ErrorResponse{
+			Error:   "not_found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrTrashParentDeleted):
+		return c.Status(fiber.StatusConflict).JSON(domain.ErrorResponse{
+			Error:   "parent_deleted",
+			Message: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrLegalHoldActive):
+		return c.Status(fiber.StatusConflict).JSON(domain.ErrorResponse{
+			Error:   "legal_hold",
+			Message: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrInvalidTrashItemType):
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+	}
+}
@@ -9,10 +9,12 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	storageClient "cloud.google.com/go/storage"
 	"github.com/gofiber/fiber/v2"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/observability"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/storage"
 	"go.uber.org/zap"
 )
@@ -158,6 +160,14 @@ func ServeFile(c *fiber.Ctx) error {
 		})
 	}
 
+	// Arsip export data company hanya boleh diunduh lewat link sementara (lihat CompanyExportHandler)
+	if bucketPath == "exports" {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error":   "forbidden",
+			"message": "Arsip export hanya bisa diunduh melalui link unduh export",
+		})
+	}
+
 	zapLog.Info("Processing file serve request",
 		zap.String("bucket_path", bucketPath),
 		zap.String("filename", filename),
@@ -188,6 +198,7 @@ func serveFromGCPStorage(c *fiber.Ctx, gcpStorage interface {
 	GetContext() context.Context
 }, bucketPath, filename, fullPath string) error {
 	zapLog := logger.GetLogger()
+	start := time.Now()
 
 	// Ambil GCP Storage client dan bucket name dari GCPStorageManager
 	ctx := gcpStorage.GetContext()
@@ -298,6 +309,7 @@ func serveFromGCPStorage(c *fiber.Ctx, gcpStorage interface {
 
 	// Stream file content to response
 	bytesWritten, err := io.Copy(c.Response().BodyWriter(), reader)
+	observability.ObserveStorage(storage.BackendGCS, "download", start, bytesWritten, err)
 	if err != nil {
 		zapLog.Error("Failed to stream file content",
 			zap.String("object_path", objectPath),
@@ -384,7 +396,11 @@ func serveFromLocalStorage(c *fiber.Ctx, storageManager storage.StorageManager,
 	)
 
 	// Read file content
+	start := time.Now()
 	fileData, err := os.ReadFile(filePath)
+	if !os.IsNotExist(err) {
+		observability.ObserveStorage(storage.BackendLocal, "download", start, int64(len(fileData)), err)
+	}
 	if err != nil {
 		if os.IsNotExist(err) {
 			zapLog.Warn("File not found in filesystem",
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/usecase"
+)
+
+// FinancialAnalyticsHandler handles analytics realisasi (trend, growth, LTM, forecast vs RKAP)
+type FinancialAnalyticsHandler struct {
+	analyticsUC usecase.FinancialAnalyticsUseCase
+	companyUC   usecase.CompanyUseCase
+}
+
+// NewFinancialAnalyticsHandler creates a new financial analytics handler
+func NewFinancialAnalyticsHandler(analyticsUC usecase.FinancialAnalyticsUseCase, companyUC usecase.CompanyUseCase) *FinancialAnalyticsHandler {
+	return &FinancialAnalyticsHandler{
+		analyticsUC: analyticsUC,
+		companyUC:   companyUC,
+	}
+}
+
+// GetTrend godoc
+// @Summary      Get financial trend
+// @Description  Time series realisasi bulanan per metrik lintas tahun beserta pertumbuhan month-over-month, year-over-year, dan rolling 12 bulan (LTM)
+// @Tags         Financial Analytics
+// @Accept       json
+// @Produce      json
+// @Param        company_id    path      string  true   "Company ID"
+// @Param        metrics       query     string  false  "Daftar metrik dipisah koma (key/konsep taksonomi, contoh: revenue,net_profit). Kosong = semua"
+// @Param        start_period  query     string  false  "Start period (YYYY-MM), default 24 bulan sebelum end_period"
+// @Param        end_period    query     string  false  "End period (YYYY-MM), default periode realisasi terakhir"
+// @Success      200           {object}  domain.FinancialTrendResponse
+// @Failure      400           {object}  domain.ErrorResponse
+// @Failure      403           {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/{company_id}/financial-analytics/trend [get]
+// @Security     BearerAuth
+// @note         Catatan Teknis:
+// @note         1. Bulan tanpa realisasi bernilai null, growth/LTM yang membutuhkan bulan tersebut juga null
+// @note         2. LTM: metrik arus dijumlah, rasio dirata-rata, metrik saldo (neraca, saldo akhir kas) tidak punya LTM
+// @note         3. Maksimal rentang 120 bulan
+func (h *FinancialAnalyticsHandler) GetTrend(c *fiber.Ctx) error {
+	companyID := c.Params("company_id")
+	if !canAccessCompany(c, h.companyUC, companyID, false) {
+		return forbiddenCompany(c)
+	}
+
+	trend, err := h.analyticsUC.GetTrend(companyID, parseMetricsQuery(c), c.Query("start_period"), c.Query("end_period"))
+	if err != nil {
+		return analyticsError(c, err)
+	}
+	return c.JSON(trend)
+}
+
+// GetForecast godoc
+// @Summary      Get financial forecast
+// @Description  Proyeksi realisasi setahun penuh dengan metode run-rate dan regresi linear bulanan, dibandingkan dengan RKAP tahun yang sama
+// @Tags         Financial Analytics
+// @Accept       json
+// @Produce      json
+// @Param        company_id  path      string  true   "Company ID"
+// @Param        year        query     string  true   "Tahun (YYYY)"
+// @Param        metrics     query     string  false  "Daftar metrik dipisah koma. Kosong = semua"
+// @Success      200         {object}  domain.FinancialForecastResponse
+// @Failure      400         {object}  domain.ErrorResponse
+// @Failure      403         {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/{company_id}/financial-analytics/forecast [get]
+// @Security     BearerAuth
+// @note         Catatan Teknis:
+// @note         1. Run-rate: metrik arus = rata-rata bulanan x 12, saldo = posisi terakhir, rasio = rata-rata berjalan
+// @note         2. Linear: bulan tanpa realisasi diisi garis regresi (points[].forecast), lalu diagregasi setahun
+// @note         3. Achievement = proyeksi / RKAP x 100, null jika RKAP belum ada atau bernilai 0
+func (h *FinancialAnalyticsHandler) GetForecast(c *fiber.Ctx) error {
+	companyID := c.Params("company_id")
+	if !canAccessCompany(c, h.companyUC, companyID, false) {
+		return forbiddenCompany(c)
+	}
+
+	forecast, err := h.analyticsUC.GetForecast(companyID, c.Query("year"), parseMetricsQuery(c))
+	if err != nil {
+		return analyticsError(c, err)
+	}
+	return c.JSON(forecast)
+}
+
+// ExportAnalytics godoc
+// @Summary      Export financial analytics to Excel
+// @Description  Export trend (sheet Trend) dan forecast tahun end_period (sheet Forecast) dengan angka yang sama seperti endpoint trend/forecast
+// @Tags         Financial Analytics
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        company_id    path      string  true   "Company ID"
+// @Param        metrics       query     string  false  "Daftar metrik dipisah koma. Kosong = semua"
+// @Param        start_period  query     string  false  "Start period (YYYY-MM)"
+// @Param        end_period    query     string  false  "End period (YYYY-MM)"
+// @Success      200           {file}    application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Failure      400           {object}  domain.ErrorResponse
+// @Failure      403           {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/{company_id}/financial-analytics/export [get]
+// @Security     BearerAuth
+func (h *FinancialAnalyticsHandler) ExportAnalytics(c *fiber.Ctx) error {
+	companyID := c.Params("company_id")
+	if !canAccessCompany(c, h.companyUC, companyID, false) {
+		return forbiddenCompany(c)
+	}
+
+	data, err := h.analyticsUC.ExportAnalyticsExcel(companyID, parseMetricsQuery(c), c.Query("start_period"), c.Query("end_period"))
+	if err != nil {
+		return analyticsError(c, err)
+	}
+
+	c.Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=financial_analytics_%s.xlsx", companyID))
+	return c.Send(data)
+}
+
+// parseMetricsQuery membaca query metrics=revenue,net_profit
+func parseMetricsQuery(c *fiber.Ctx) []string {
+	var metrics []string
+	for _, metric := range strings.Split(c.Query("metrics"), ",") {
+		if metric = strings.TrimSpace(metric); metric != "" {
+			metrics = append(metrics, metric)
+		}
+	}
+	return metrics
+}
+
+func analyticsError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, usecase.ErrUnknownFinancialMetric) || errors.Is(err, usecase.ErrInvalidAnalyticsPeriod) {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+		Error:   "internal_error",
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,145 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/usecase"
+)
+
+// FinancialBenchmarkHandler handles ranking dan benchmarking KPI lintas company
+type FinancialBenchmarkHandler struct {
+	benchmarkUC usecase.FinancialBenchmarkUseCase
+	companyUC   usecase.CompanyUseCase
+}
+
+// NewFinancialBenchmarkHandler creates a new financial benchmark handler
+func NewFinancialBenchmarkHandler(benchmarkUC usecase.FinancialBenchmarkUseCase, companyUC usecase.CompanyUseCase) *FinancialBenchmarkHandler {
+	return &FinancialBenchmarkHandler{
+		benchmarkUC: benchmarkUC,
+		companyUC:   companyUC,
+	}
+}
+
+// GetBenchmark godoc
+// @Summary      Get cross-company KPI benchmark
+// @Description  Ranking KPI (realisasi YTD atau pencapaian vs RKAP) untuk daftar company atau satu subtree, beserta percentile dan statistik sebaran
+// @Tags         Financial Analytics
+// @Accept       json
+// @Produce      json
+// @Param        period           query     string  true   "Periode (YYYY-MM), realisasi YTD Januari sampai periode ini"
+// @Param        metrics          query     string  false  "Daftar metrik dipisah koma (default: roe,revenue)"
+// @Param        company_ids      query     string  false  "Daftar company ID dipisah koma"
+// @Param        root_company_id  query     string  false  "Root subtree (root + seluruh descendants). Default company user jika company_ids kosong"
+// @Param        level            query     int     false  "Hanya company pada level ini (contoh: 2)"
+// @Param        rank_by          query     string  false  "value (default) atau achievement"
+// @Success      200              {object}  domain.FinancialBenchmarkResponse
+// @Failure      400              {object}  domain.ErrorResponse
+// @Failure      403              {object}  domain.ErrorResponse
+// @Router       /api/v1/financial-benchmarks [get]
+// @Security     BearerAuth
+// @note         Catatan Teknis:
+// @note         1. Authorization: setiap company di company_ids (atau root_company_id) harus bisa diakses user
+// @note         2. Ranking: nilai sama mendapat rank sama, company tanpa data diletakkan di akhir tanpa rank
+// @note         3. Metrik beban, pajak, liabilitas, dan debt to equity: nilai lebih kecil lebih baik (higher_is_better=false)
+func (h *FinancialBenchmarkHandler) GetBenchmark(c *fiber.Ctx) error {
+	req, status, errResp := h.parseBenchmarkRequest(c)
+	if errResp != nil {
+		return c.Status(status).JSON(errResp)
+	}
+
+	benchmark, err := h.benchmarkUC.GetBenchmark(*req)
+	if err != nil {
+		return benchmarkError(c, err)
+	}
+	return c.JSON(benchmark)
+}
+
+// ExportBenchmark godoc
+// @Summary      Export cross-company KPI benchmark to Excel
+// @Description  Export hasil benchmark (satu sheet per metrik) dengan parameter yang sama seperti GET /financial-benchmarks
+// @Tags         Financial Analytics
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        period           query     string  true   "Periode (YYYY-MM)"
+// @Param        metrics          query     string  false  "Daftar metrik dipisah koma"
+// @Param        company_ids      query     string  false  "Daftar company ID dipisah koma"
+// @Param        root_company_id  query     string  false  "Root subtree"
+// @Param        level            query     int     false  "Filter level company"
+// @Param        rank_by          query     string  false  "value (default) atau achievement"
+// @Success      200              {file}    application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Failure      400              {object}  domain.ErrorResponse
+// @Failure      403              {object}  domain.ErrorResponse
+// @Router       /api/v1/financial-benchmarks/export [get]
+// @Security     BearerAuth
+func (h *FinancialBenchmarkHandler) ExportBenchmark(c *fiber.Ctx) error {
+	req, status, errResp := h.parseBenchmarkRequest(c)
+	if errResp != nil {
+		return c.Status(status).JSON(errResp)
+	}
+
+	data, err := h.benchmarkUC.ExportBenchmarkExcel(*req)
+	if err != nil {
+		return benchmarkError(c, err)
+	}
+
+	c.Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=financial_benchmark_%s.xlsx", req.Period))
+	return c.Send(data)
+}
+
+// parseBenchmarkRequest membaca query dan memastikan user punya akses ke seluruh scope company
+func (h *FinancialBenchmarkHandler) parseBenchmarkRequest(c *fiber.Ctx) (*domain.FinancialBenchmarkRequest, int, *domain.ErrorResponse) {
+	req := &domain.FinancialBenchmarkRequest{
+		Period:        c.Query("period"),
+		Metrics:       parseMetricsQuery(c),
+		RootCompanyID: c.Query("root_company_id"),
+		RankBy:        c.Query("rank_by"),
+	}
+	for _, id := range strings.Split(c.Query("company_ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			req.CompanyIDs = append(req.CompanyIDs, id)
+		}
+	}
+	if levelStr := c.Query("level"); levelStr != "" {
+		level, err := strconv.Atoi(levelStr)
+		if err != nil || level < 0 {
+			return nil, fiber.StatusBadRequest, &domain.ErrorResponse{
+				Error:   "invalid_request",
+				Message: "level must be a non-negative integer",
+			}
+		}
+		req.Level = &level
+	}
+	if len(req.CompanyIDs) == 0 && req.RootCompanyID == "" {
+		req.RootCompanyID = localCompanyID(c)
+	}
+
+	scope := req.CompanyIDs
+	if len(scope) == 0 && req.RootCompanyID != "" {
+		// Descendants company yang bisa diakses otomatis bisa diakses juga
+		scope = []string{req.RootCompanyID}
+	}
+	for _, companyID := range scope {
+		if !canAccessCompany(c, h.companyUC, companyID, false) {
+			return nil, fiber.StatusForbidden, &domain.ErrorResponse{
+				Error:   "forbidden",
+				Message: "You don't have access to company " + companyID,
+			}
+		}
+	}
+	return req, 0, nil
+}
+
+func benchmarkError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, usecase.ErrInvalidBenchmarkScope) {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+	}
+	return analyticsError(c, err)
+}
@@ -0,0 +1,548 @@
+package http
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/usecase"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// financialImportStreamInterval adalah jeda antar event progress pada stream SSE
+const financialImportStreamInterval = time.Second
+
+// FinancialImportHandler handles job import bulk financial report (staging, preview diff, commit sebagian)
+type FinancialImportHandler struct {
+	importUC  usecase.FinancialImportUseCase
+	companyUC usecase.CompanyUseCase
+}
+
+// NewFinancialImportHandler creates a new financial import handler
+func NewFinancialImportHandler(importUC usecase.FinancialImportUseCase, companyUC usecase.CompanyUseCase) *FinancialImportHandler {
+	return &FinancialImportHandler{
+		importUC:  importUC,
+		companyUC: companyUC,
+	}
+}
+
+// CreateImportJob godoc
+// @Summary      Upload file ke staging import
+// @Description  Upload file bulk financial report (Excel, CSV, atau JSON taksonomi) sekali, parsing dan perbandingan dengan data yang sudah ada berjalan di background. Response berisi job ID untuk polling progress.
+// @Tags         Financial Reports
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        file    formData  file    true   "File .xlsx (template bulk upload), .csv, atau .json"
+// @Param        format  formData  string  false  "xlsx, csv, atau json (default: dari ekstensi file)"
+// @Success      202   {object}  domain.FinancialImportJobModel
+// @Failure      400   {object}  domain.ErrorResponse
+// @Failure      403   {object}  domain.ErrorResponse
+// @Failure      500   {object}  domain.ErrorResponse
+// @Router       /api/v1/financial-import-jobs [post]
+// @note         Catatan Teknis:
+// @note         1. Status job: processing -> ready (siap review) -> committing -> completed, atau failed jika workbook tidak valid
+// @note         2. Setiap baris diberi status diff: new, overwrite, unchanged, error
+// @note         3. Tidak ada data financial report yang berubah sampai endpoint commit dipanggil
+// @note         4. Kolom CSV dan fakta JSON boleh memakai kode akun company yang sudah dipetakan di /companies/{id}/financial-account-mappings
+func (h *FinancialImportHandler) CreateImportJob(c *fiber.Ctx) error {
+	fileName, fileData, status, errResp := readUploadedFile(c)
+	if errResp != nil {
+		return c.Status(status).JSON(errResp)
+	}
+
+	accessibleCodes, status, errResp := accessibleCompanyCodes(c, h.companyUC)
+	if errResp != nil {
+		return c.Status(status).JSON(errResp)
+	}
+
+	job, err := h.importUC.CreateJob(fileName, c.FormValue("format"), fileData, accessibleCodes, financialImportActor(c))
+	if errors.Is(err, usecase.ErrFinancialFormatUnsupported) {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_file_format",
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "import_failed",
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(job)
+}
+
+// ListImportJobs godoc
+// @Summary      List job import financial report
+// @Description  Mengambil daftar job import. Superadmin/administrator melihat semua job, user lain hanya job miliknya.
+// @Tags         Financial Reports
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        status     query     string  false  "Filter status (processing, ready, committing, completed, failed)"
+// @Param        page       query     int     false  "Page number (default: 1)"
+// @Param        page_size  query     int     false  "Page size (default: 20)"
+// @Success      200        {object}  map[string]interface{}
+// @Failure      500        {object}  domain.ErrorResponse
+// @Router       /api/v1/financial-import-jobs [get]
+func (h *FinancialImportHandler) ListImportJobs(c *fiber.Ctx) error {
+	filter := repository.FinancialImportJobFilter{Status: c.Query("status")}
+	roleName, _ := c.Locals("roleName").(string)
+	if !utils.IsSuperAdminLike(roleName) {
+		filter.CreatedBy, _ = c.Locals("userID").(string)
+	}
+
+	page, pageSize := parsePagination(c)
+	filter.Limit = pageSize
+	filter.Offset = (page - 1) * pageSize
+
+	jobs, total, err := h.importUC.ListJobs(filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to fetch import jobs: " + err.Error(),
+		})
+	}
+
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+	return c.JSON(fiber.Map{
+		"data":        jobs,
+		"total":       total,
+		"page":        page,
+		"page_size":   pageSize,
+		"total_pages": totalPages,
+	})
+}
+
+// GetImportJob godoc
+// @Summary      Get job import financial report
+// @Description  Mengambil status dan progress job import (processed_rows/total_rows saat parsing, committed_rows+failed_rows/commit_total saat commit)
+// @Tags         Financial Reports
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Import job ID"
+// @Success      200  {object}  domain.FinancialImportJobModel
+// @Failure      403  {object}  domain.ErrorResponse
+// @Failure      404  {object}  domain.ErrorResponse
+// @Router       /api/v1/financial-import-jobs/{id} [get]
+func (h *FinancialImportHandler) GetImportJob(c *fiber.Ctx) error {
+	job, status, errResp := h.loadAccessibleJob(c)
+	if errResp != nil {
+		return c.Status(status).JSON(errResp)
+	}
+	return c.JSON(job)
+}
+
+// StreamImportJob godoc
+// @Summary      Stream progress job import (SSE)
+// @Description  Server-Sent Events berisi snapshot job setiap detik sampai job selesai diproses (ready, completed, atau failed)
+// @Tags         Financial Reports
+// @Produce      text/event-stream
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Import job ID"
+// @Success      200  {string}  text/event-stream
+// @Failure      403  {object}  domain.ErrorResponse
+// @Failure      404  {object}  domain.ErrorResponse
+// @Router       /api/v1/financial-import-jobs/{id}/stream [get]
+// @note         Alternatif dari polling GET /financial-import-jobs/{id}. Event bernama "progress", data berupa JSON job.
+func (h *FinancialImportHandler) StreamImportJob(c *fiber.Ctx) error {
+	job, status, errResp := h.loadAccessibleJob(c)
+	if errResp != nil {
+		return c.Status(status).JSON(errResp)
+	}
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+
+	jobID := job.ID
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		for {
+			current, err := h.importUC.GetJob(jobID)
+			if err != nil {
+				return
+			}
+			payload, err := json.Marshal(current)
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "event: progress\ndata: %s\n\n", payload); err != nil {
+				return
+			}
+			if err := w.Flush(); err != nil {
+				return // Client menutup koneksi
+			}
+			if current.Status != domain.FinancialImportStatusProcessing && current.Status != domain.FinancialImportStatusCommitting {
+				return
+			}
+			time.Sleep(financialImportStreamInterval)
+		}
+	})
+	return nil
+}
+
+// GetImportJobRows godoc
+// @Summary      Preview baris staging job import
+// @Description  Mengambil baris staging beserta status diff (new, overwrite, unchanged, error), perubahan per field untuk overwrite, dan error validasi
+// @Tags         Financial Reports
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path      string  true   "Import job ID"
+// @Param        status     query     string  false  "Filter status diff (new, overwrite, unchanged, error)"
+// @Param        page       query     int     false  "Page number (default: 1)"
+// @Param        page_size  query     int     false  "Page size (default: 20)"
+// @Success      200        {object}  map[string]interface{}
+// @Failure      403        {object}  domain.ErrorResponse
+// @Failure      404        {object}  domain.ErrorResponse
+// @Router       /api/v1/financial-import-jobs/{id}/rows [get]
+func (h *FinancialImportHandler) GetImportJobRows(c *fiber.Ctx) error {
+	job, status, errResp := h.loadAccessibleJob(c)
+	if errResp != nil {
+		return c.Status(status).JSON(errResp)
+	}
+
+	page, pageSize := parsePagination(c)
+	rows, total, err := h.importUC.GetJobRows(job.ID, repository.FinancialImportRowFilter{
+		Status: c.Query("status"),
+		Limit:  pageSize,
+		Offset: (page - 1) * pageSize,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to fetch import rows: " + err.Error(),
+		})
+	}
+
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+	return c.JSON(fiber.Map{
+		"data":        rows,
+		"total":       total,
+		"page":        page,
+		"page_size":   pageSize,
+		"total_pages": totalPages,
+	})
+}
+
+// CommitImportJob godoc
+// @Summary      Commit baris staging ke financial report
+// @Description  Menyimpan baris staging yang dipilih ke financial report di background. Tanpa row_numbers, semua baris new dan overwrite di-commit.
+// @Tags         Financial Reports
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                               true   "Import job ID"
+// @Param        request  body      domain.CommitFinancialImportRequest  false  "Baris yang dipilih"
+// @Success      202      {object}  domain.FinancialImportJobModel
+// @Failure      400      {object}  domain.ErrorResponse
+// @Failure      403      {object}  domain.ErrorResponse
+// @Failure      404      {object}  domain.ErrorResponse
+// @Failure      409      {object}  domain.ErrorResponse
+// @Router       /api/v1/financial-import-jobs/{id}/commit [post]
+// @note         Catatan Teknis:
+// @note         1. Baris error dan unchanged selalu dilewati, baris yang sudah ter-commit tidak disimpan ulang
+// @note         2. Baris yang gagal saat commit bisa di-commit ulang setelah job completed
+// @note         3. Data existing dicek ulang saat commit sehingga perubahan setelah preview tetap di-update, bukan diduplikasi
+func (h *FinancialImportHandler) CommitImportJob(c *fiber.Ctx) error {
+	job, status, errResp := h.loadAccessibleJob(c)
+	if errResp != nil {
+		return c.Status(status).JSON(errResp)
+	}
+
+	var req domain.CommitFinancialImportRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+				Error:   "invalid_request",
+				Message: "Invalid request body: " + err.Error(),
+			})
+		}
+	}
+
+	job, err := h.importUC.CommitJob(job.ID, &req, financialImportActor(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrFinancialImportNotReady):
+			return c.Status(fiber.StatusConflict).JSON(domain.ErrorResponse{
+				Error:   "import_not_ready",
+				Message: "Job import masih diproses atau gagal, tidak bisa di-commit",
+			})
+		case errors.Is(err, usecase.ErrFinancialImportNoRows):
+			return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+				Error:   "no_rows_selected",
+				Message: "Tidak ada baris new/overwrite yang belum di-commit pada pilihan ini",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "commit_failed",
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(job)
+}
+
+// DownloadImportErrorReport godoc
+// @Summary      Download error report job import
+// @Description  Download workbook berisi baris yang gagal validasi atau gagal di-commit beserta pesan error-nya. Kolom sama dengan template sehingga bisa diperbaiki dan di-upload ulang.
+// @Tags         Financial Reports
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Import job ID"
+// @Success      200  {file}    application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Failure      403  {object}  domain.ErrorResponse
+// @Failure      404  {object}  domain.ErrorResponse
+// @Router       /api/v1/financial-import-jobs/{id}/error-report [get]
+func (h *FinancialImportHandler) DownloadImportErrorReport(c *fiber.Ctx) error {
+	job, status, errResp := h.loadAccessibleJob(c)
+	if errResp != nil {
+		return c.Status(status).JSON(errResp)
+	}
+
+	content, err := h.importUC.BuildErrorReport(job.ID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrFinancialImportNoErrorRows) {
+			return c.Status(fiber.StatusNotFound).JSON(domain.ErrorResponse{
+				Error:   "not_found",
+				Message: "Job import tidak memiliki baris error",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "export_failed",
+			Message: "Failed to build error report: " + err.Error(),
+		})
+	}
+
+	filename := fmt.Sprintf("financial_import_errors_%s.xlsx", job.ID[:8])
+	c.Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	return c.Send(content)
+}
+
+// loadAccessibleJob mengambil job dan memastikan user adalah pembuat job (atau superadmin/administrator)
+func (h *FinancialImportHandler) loadAccessibleJob(c *fiber.Ctx) (*domain.FinancialImportJobModel, int, *domain.ErrorResponse) {
+	job, err := h.importUC.GetJob(c.Params("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.StatusNotFound, &domain.ErrorResponse{Error: "not_found", Message: "Import job not found"}
+		}
+		return nil, fiber.StatusInternalServerError, &domain.ErrorResponse{Error: "internal_error", Message: "Failed to fetch import job: " + err.Error()}
+	}
+
+	roleName, _ := c.Locals("roleName").(string)
+	userID, _ := c.Locals("userID").(string)
+	if !utils.IsSuperAdminLike(roleName) && job.CreatedBy != userID {
+		return nil, fiber.StatusForbidden, &domain.ErrorResponse{Error: "forbidden", Message: "You don't have access to this import job"}
+	}
+	return job, fiber.StatusOK, nil
+}
+
+// GetFinancialTaxonomy godoc
+// @Summary      Taksonomi konsep financial report
+// @Description  Daftar kode konsep yang dipakai format JSON, kolom CSV, dan mapping kode akun company
+// @Tags         Financial Reports
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  domain.FinancialTaxonomyResponse
+// @Router       /api/v1/financial-reports/taxonomy [get]
+func (h *FinancialImportHandler) GetFinancialTaxonomy(c *fiber.Ctx) error {
+	return c.JSON(domain.FinancialTaxonomyResponse{
+		Taxonomy: usecase.FinancialTaxonomyVersion,
+		Concepts: usecase.FinancialTaxonomy(),
+	})
+}
+
+// GetAccountMappings godoc
+// @Summary      Get mapping kode akun company
+// @Description  Mengambil mapping kode akun chart-of-accounts company ke konsep taksonomi financial report
+// @Tags         Financial Reports
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Company ID"
+// @Success      200  {array}   domain.FinancialAccountMappingModel
+// @Failure      403  {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/{id}/financial-account-mappings [get]
+func (h *FinancialImportHandler) GetAccountMappings(c *fiber.Ctx) error {
+	companyID := c.Params("id")
+	if !canAccessCompany(c, h.companyUC, companyID, false) {
+		return forbiddenCompany(c)
+	}
+
+	mappings, err := h.importUC.GetAccountMappings(companyID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to fetch account mappings: " + err.Error(),
+		})
+	}
+	return c.JSON(mappings)
+}
+
+// SetAccountMappings godoc
+// @Summary      Set mapping kode akun company
+// @Description  Mengganti seluruh mapping kode akun company ke konsep taksonomi. Dipakai saat import CSV/JSON yang memakai kode akun company.
+// @Tags         Financial Reports
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                                     true  "Company ID"
+// @Param        request  body      domain.SetFinancialAccountMappingsRequest  true  "Mapping akun"
+// @Success      200      {array}   domain.FinancialAccountMappingModel
+// @Failure      400      {object}  domain.ErrorResponse
+// @Failure      403      {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/{id}/financial-account-mappings [put]
+// @note         Catatan Teknis:
+// @note         1. Beberapa kode akun boleh dipetakan ke konsep yang sama, nilainya dijumlahkan saat import
+// @note         2. Multiplier (default 1) dipakai untuk akun dengan tanda terbalik, contoh -1 untuk beban yang dicatat negatif
+func (h *FinancialImportHandler) SetAccountMappings(c *fiber.Ctx) error {
+	companyID := c.Params("id")
+	if !canAccessCompany(c, h.companyUC, companyID, true) {
+		return forbiddenCompany(c)
+	}
+
+	var req domain.SetFinancialAccountMappingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+	}
+
+	mappings, err := h.importUC.SetAccountMappings(companyID, &req, financialImportActor(c))
+	if errors.Is(err, usecase.ErrInvalidAccountMapping) {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+	}
+	return c.JSON(mappings)
+}
+
+// financialImportActor membaca identitas user dari context JWT untuk inputter dan audit trail
+func financialImportActor(c *fiber.Ctx) usecase.FinancialImportActor {
+	userID, _ := c.Locals("userID").(string)
+	username, _ := c.Locals("username").(string)
+	return usecase.FinancialImportActor{
+		UserID:    userID,
+		Username:  username,
+		IPAddress: getClientIP(c),
+		UserAgent: c.Get("User-Agent", ""),
+	}
+}
+
+// readUploadedWorkbook membaca file Excel dari form field "file"
+func readUploadedWorkbook(c *fiber.Ctx) (string, []byte, int, *domain.ErrorResponse) {
+	fileName, fileData, status, errResp := readUploadedFile(c)
+	if errResp != nil {
+		return "", nil, status, errResp
+	}
+
+	filename := strings.ToLower(fileName)
+	if !strings.HasSuffix(filename, ".xlsx") && !strings.HasSuffix(filename, ".xls") {
+		return "", nil, fiber.StatusBadRequest, &domain.ErrorResponse{
+			Error:   "invalid_file_format",
+			Message: "Format file tidak valid. Hanya file Excel (.xlsx, .xls) yang diperbolehkan",
+		}
+	}
+	return fileName, fileData, fiber.StatusOK, nil
+}
+
+// readUploadedFile membaca file dari form field "file" tanpa memeriksa ekstensi
+func readUploadedFile(c *fiber.Ctx) (string, []byte, int, *domain.ErrorResponse) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		return "", nil, fiber.StatusBadRequest, &domain.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "File tidak ditemukan dalam request",
+		}
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return "", nil, fiber.StatusInternalServerError, &domain.ErrorResponse{Error: "file_read_error", Message: "Gagal membaca file"}
+	}
+	defer src.Close()
+
+	fileData, err := io.ReadAll(src)
+	if err != nil {
+		return "", nil, fiber.StatusInternalServerError, &domain.ErrorResponse{Error: "file_read_error", Message: "Gagal membaca file"}
+	}
+	return file.Filename, fileData, fiber.StatusOK, nil
+}
+
+// accessibleCompanyCodes mengembalikan kode company yang boleh diisi user pada workbook bulk upload.
+// nil = semua company aktif (superadmin/administrator); user lain hanya company sendiri dan turunannya.
+func accessibleCompanyCodes(c *fiber.Ctx, companyUC usecase.CompanyUseCase) (map[string]bool, int, *domain.ErrorResponse) {
+	roleName, _ := c.Locals("roleName").(string)
+	if utils.IsSuperAdminLike(roleName) {
+		return nil, fiber.StatusOK, nil
+	}
+
+	userCompanyID := localCompanyID(c)
+	if userCompanyID == "" {
+		return nil, fiber.StatusForbidden, &domain.ErrorResponse{Error: "forbidden", Message: "User company not found"}
+	}
+
+	userCompany, err := companyUC.GetCompanyByID(userCompanyID)
+	if err != nil {
+		return nil, fiber.StatusInternalServerError, &domain.ErrorResponse{Error: "internal_error", Message: "Failed to get user company"}
+	}
+	descendants, err := companyUC.GetCompanyDescendants(userCompanyID)
+	if err != nil {
+		return nil, fiber.StatusInternalServerError, &domain.ErrorResponse{Error: "internal_error", Message: "Failed to get company descendants"}
+	}
+
+	codes := map[string]bool{userCompany.Code: true}
+	for _, company := range descendants {
+		if company.IsActive {
+			codes[company.Code] = true
+		}
+	}
+	return codes, fiber.StatusOK, nil
+}
+
+// workbookErrorResponse memetakan error parsing workbook ke response HTTP
+func workbookErrorResponse(c *fiber.Ctx, err error) error {
+	var workbookErr *usecase.FinancialWorkbookError
+	if errors.As(err, &workbookErr) {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_excel_file",
+			Message: workbookErr.Message,
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+		Error:   "file_read_error",
+		Message: err.Error(),
+	})
+}
+
+// parsePagination membaca query page dan page_size (default 1 dan 20, maksimal 100)
+func parsePagination(c *fiber.Ctx) (int, int) {
+	page := 1
+	if parsed, err := strconv.Atoi(c.Query("page")); err == nil && parsed > 0 {
+		page = parsed
+	}
+	pageSize := 20
+	if parsed, err := strconv.Atoi(c.Query("page_size")); err == nil && parsed > 0 && parsed <= 100 {
+		pageSize = parsed
+	}
+	return page, pageSize
+}
@@ -0,0 +1,148 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/usecase"
+)
+
+// GetRKAPPhasing godoc
+// @Summary      Get RKAP phasing
+// @Description  Bobot phasing RKAP tahunan ke bulan atau kuartal untuk satu versi RKAP. Granularity "even" berarti belum di-set (rata 1/12 per bulan)
+// @Tags         Financial Reports
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Financial Report ID (RKAP awal atau revisi)"
+// @Success      200  {object}  domain.RKAPPhasingResponse
+// @Failure      403  {object}  domain.ErrorResponse
+// @Failure      404  {object}  domain.ErrorResponse
+// @Router       /api/v1/financial-reports/{id}/phasing [get]
+func (h *FinancialReportHandler) GetRKAPPhasing(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if status, errResp := h.rkapReportAccess(c, id); errResp != nil {
+		return c.Status(status).JSON(errResp)
+	}
+
+	phasing, err := h.financialReportUseCase.GetRKAPPhasing(id)
+	if err != nil {
+		return rkapRevisionError(c, err)
+	}
+	return c.JSON(phasing)
+}
+
+// SetRKAPPhasing godoc
+// @Summary      Set RKAP phasing
+// @Description  Mengganti bobot phasing RKAP (monthly: 12 bulan, quarterly: 4 kuartal). Bobot default (metric kosong) dan bobot per metrik masing-masing harus berjumlah 100
+// @Tags         Financial Reports
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                        true  "Financial Report ID (RKAP awal atau revisi draft)"
+// @Param        phasing  body      domain.SetRKAPPhasingRequest  true  "Granularity dan bobot phasing"
+// @Success      200      {object}  domain.RKAPPhasingResponse
+// @Failure      400      {object}  domain.ErrorResponse
+// @Failure      403      {object}  domain.ErrorResponse
+// @Failure      404      {object}  domain.ErrorResponse
+// @Failure      409      {object}  domain.ErrorResponse
+// @Router       /api/v1/financial-reports/{id}/phasing [put]
+// @note         Catatan Teknis:
+// @note         1. Hanya metrik arus (laba rugi, arus kas) yang di-phasing; saldo neraca dan rasio dibandingkan dengan target tahunan
+// @note         2. weights kosong menghapus phasing (kembali rata per bulan)
+// @note         3. Revisi RKAP yang sudah approved/rejected tidak bisa diubah phasing-nya
+func (h *FinancialReportHandler) SetRKAPPhasing(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if status, errResp := h.rkapReportAccess(c, id); errResp != nil {
+		return c.Status(status).JSON(errResp)
+	}
+
+	var req domain.SetRKAPPhasingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	username, _ := c.Locals("username").(string)
+	phasing, err := h.financialReportUseCase.SetRKAPPhasing(id, &req, userID, username, getClientIP(c), c.Get("User-Agent"))
+	if err != nil {
+		return rkapRevisionError(c, err)
+	}
+	return c.JSON(phasing)
+}
+
+// GetPeriodComparison godoc
+// @Summary      Compare realisasi with phased RKAP for a period
+// @Description  Perbandingan realisasi satu periode (bulan, kuartal, semester, atau tahun) dengan RKAP yang di-phasing ke periode tersebut
+// @Tags         Financial Reports
+// @Produce      json
+// @Security     BearerAuth
+// @Param        company_id    path      string  true   "Company ID"
+// @Param        period        query     string  true   "Periode: YYYY-MM, YYYY-Q1..Q4, YYYY-S1..S2, atau YYYY"
+// @Param        audited       query     bool    false  "true = angka audited, default management account (unaudited)"
+// @Param        rkap_version  query     int     false  "Versi RKAP pembanding. Default: versi approved terakhir"
+// @Success      200           {object}  domain.FinancialPeriodComparisonResponse
+// @Failure      400           {object}  domain.ErrorResponse
+// @Failure      403           {object}  domain.ErrorResponse
+// @Failure      404           {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/{company_id}/financial-reports/period-comparison [get]
+// @note         Catatan Teknis:
+// @note         1. Realisasi dari granularitas apapun di dalam periode dipakai; data paling detail didahulukan dan report yang bulannya sudah tercakup diabaikan
+// @note         2. Metrik arus dijumlah dan dibandingkan dengan RKAP x bobot phasing (kuartal dipecah pro-rata per bulan), saldo memakai posisi terakhir, rasio rata-rata tertimbang bulan
+// @note         3. complete=false jika ada bulan dalam periode yang belum memiliki realisasi
+func (h *FinancialReportHandler) GetPeriodComparison(c *fiber.Ctx) error {
+	companyID := c.Params("company_id")
+	if !canAccessCompany(c, h.companyUseCase, companyID, false) {
+		return forbiddenCompany(c)
+	}
+
+	audited := false
+	if value := c.Query("audited"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+				Error:   "invalid_request",
+				Message: "audited must be true or false",
+			})
+		}
+		audited = parsed
+	}
+	rkapVersion, errResp := parseRKAPVersionQuery(c, "rkap_version")
+	if errResp != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errResp)
+	}
+
+	comparison, err := h.financialReportUseCase.GetPeriodComparison(companyID, c.Query("period"), audited, rkapVersion)
+	if errors.Is(err, usecase.ErrInvalidFinancialPeriod) {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		return rkapRevisionError(c, err)
+	}
+	return c.JSON(comparison)
+}
+
+// rkapReportAccess memastikan report adalah RKAP dan company-nya bisa diakses user
+func (h *FinancialReportHandler) rkapReportAccess(c *fiber.Ctx, id string) (int, *domain.ErrorResponse) {
+	report, err := h.financialReportUseCase.GetFinancialReportByID(id)
+	if err != nil || !report.IsRKAP {
+		return fiber.StatusNotFound, &domain.ErrorResponse{
+			Error:   "not_found",
+			Message: "RKAP not found",
+		}
+	}
+	if !canAccessCompany(c, h.companyUseCase, report.CompanyID, false) {
+		return fiber.StatusForbidden, &domain.ErrorResponse{
+			Error:   "forbidden",
+			Message: "You don't have access to this company",
+		}
+	}
+	return 0, nil
+}
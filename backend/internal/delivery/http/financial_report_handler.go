@@ -2,27 +2,24 @@ package http
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
-	"io"
-	"math"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
-	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/audit"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/usecase"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/utils"
 	"github.com/xuri/excelize/v2"
-	"go.uber.org/zap"
-	"gorm.io/gorm"
 )
 
 // FinancialReportHandler handles financial report-related HTTP requests
 type FinancialReportHandler struct {
 	financialReportUseCase usecase.FinancialReportUseCase
 	companyUseCase         usecase.CompanyUseCase
+	importUseCase          usecase.FinancialImportUseCase
 }
 
 // NewFinancialReportHandler creates a new financial report handler
@@ -30,12 +27,14 @@ func NewFinancialReportHandler(financialReportUseCase usecase.FinancialReportUse
 	return &FinancialReportHandler{
 		financialReportUseCase: financialReportUseCase,
 		companyUseCase:         usecase.NewCompanyUseCase(),
+		importUseCase:          usecase.NewFinancialImportUseCase(),
 	}
 }
 
 // CreateFinancialReport handles financial report creation
 // @Summary      Buat Financial Report Baru (RKAP atau Realisasi)
-// @Description  Membuat financial report baru (RKAP tahunan atau Realisasi bulanan). RKAP hanya boleh 1x per tahun per perusahaan.
+// @Description  Membuat financial report baru (RKAP tahunan atau Realisasi). RKAP hanya boleh 1x per tahun per perusahaan.
+// @Description  Realisasi bisa bulanan (default), kuartal (2024-Q1), semester (2024-S1), atau tahunan (2024) lewat period_type; is_audited membedakan angka audited dari management account.
 // @Tags         Financial Reports
 // @Accept       json
 // @Produce      json
@@ -261,9 +260,11 @@ func (h *FinancialReportHandler) GetFinancialReportsByCompanyID(c *fiber.Ctx) er
 // @Param        company_id  query     string  true  "Company ID"
 // @Param        year        query     string  true  "Year (format: YYYY)"
 // @Param        month       query     string  true  "Month (format: MM, 01-12)"
+// @Param        rkap_version  query   int     false "Versi RKAP pembanding (0 = RKAP awal). Default: versi approved terakhir"
 // @Success      200         {object}  domain.FinancialReportComparisonResponse
 // @Failure      400         {object}  domain.ErrorResponse
 // @Failure      401         {object}  domain.ErrorResponse
+// @Failure      404         {object}  domain.ErrorResponse
 // @Router       /api/v1/financial-reports/compare [get]
 func (h *FinancialReportHandler) GetComparison(c *fiber.Ctx) error {
 	companyID := c.Query("company_id")
@@ -277,7 +278,18 @@ func (h *FinancialReportHandler) GetComparison(c *fiber.Ctx) error {
 		})
 	}
 
-	comparison, err := h.financialReportUseCase.GetComparison(companyID, year, month)
+	rkapVersion, errResp := parseRKAPVersionQuery(c, "rkap_version")
+	if errResp != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errResp)
+	}
+
+	comparison, err := h.financialReportUseCase.GetComparison(companyID, year, month, rkapVersion)
+	if errors.Is(err, usecase.ErrRKAPVersionNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(domain.ErrorResponse{
+			Error:   "not_found",
+			Message: err.Error(),
+		})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
 			Error:   "comparison_failed",
@@ -441,6 +453,7 @@ func (h *FinancialReportHandler) DeleteFinancialReport(c *fiber.Ctx) error {
 // @Param        company_id    path      string  true   "Company ID"
 // @Param        start_period  query     string  true   "Start period (YYYY-MM)"
 // @Param        end_period    query     string  true   "End period (YYYY-MM)"
+// @Param        rkap_version  query     int     false  "Versi RKAP pembanding (0 = RKAP awal). Default: versi approved terakhir"
 // @Success      200           {file}    application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Failure      400           {object}  domain.ErrorResponse
 // @Failure      401           {object}  domain.ErrorResponse
@@ -451,18 +464,110 @@ func (h *FinancialReportHandler) ExportPerformanceExcel(c *fiber.Ctx) error {
 	startPeriod := c.Query("start_period")
 	endPeriod := c.Query("end_period")
 
+	company, status, errResp := h.performanceExportCompany(c, companyID, startPeriod, endPeriod)
+	if errResp != nil {
+		return c.Status(status).JSON(errResp)
+	}
+
+	rkapVersion, errResp := parseRKAPVersionQuery(c, "rkap_version")
+	if errResp != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errResp)
+	}
+
+	// Generate Excel
+	excelData, err := h.financialReportUseCase.ExportPerformanceExcel(companyID, startPeriod, endPeriod, rkapVersion)
+	if errors.Is(err, usecase.ErrRKAPVersionNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(domain.ErrorResponse{
+			Error:   "not_found",
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "export_failed",
+			Message: err.Error(),
+		})
+	}
+
+	// Generate filename
+	filename := performanceExportFilename(company, startPeriod, endPeriod, "xlsx")
+
+	// Set headers dan kirim file
+	c.Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	return c.Send(excelData)
+}
+
+// ExportPerformancePDF handles exporting performance data to PDF
+// @Summary      Export Performance Data to PDF
+// @Description  Export performance data (Balance Sheet, Profit & Loss, Cashflow, Ratio) ke PDF dengan kop company (logo), kolom RKAP vs Realisasi beserta variance, nomor halaman, dan blok tanda tangan
+// @Tags         Financial Reports
+// @Accept       json
+// @Produce      application/pdf
+// @Security     BearerAuth
+// @Param        company_id    path      string  true   "Company ID"
+// @Param        start_period  query     string  true   "Start period (YYYY-MM)"
+// @Param        end_period    query     string  true   "End period (YYYY-MM)"
+// @Param        rkap_version  query     int     false  "Versi RKAP pembanding (0 = RKAP awal). Default: versi approved terakhir"
+// @Success      200           {file}    application/pdf
+// @Failure      400           {object}  domain.ErrorResponse
+// @Failure      401           {object}  domain.ErrorResponse
+// @Failure      403           {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/{company_id}/performance/export/pdf [get]
+// @note         Catatan Teknis:
+// @note         1. Baris dan judul section sama dengan export Excel (definisi bersama di usecase)
+// @note         2. Variance = Realisasi - RKAP, Variance % dihitung terhadap RKAP (kosong jika RKAP 0 atau belum ada)
+// @note         3. Nama penanda tangan diambil dari pengurus dengan jabatan Direktur Keuangan dan Direktur Utama
+// @note         4. Kolom RKAP memakai versi rkap_version jika diisi, default versi RKAP approved terakhir
+func (h *FinancialReportHandler) ExportPerformancePDF(c *fiber.Ctx) error {
+	companyID := c.Params("company_id")
+	startPeriod := c.Query("start_period")
+	endPeriod := c.Query("end_period")
+
+	company, status, errResp := h.performanceExportCompany(c, companyID, startPeriod, endPeriod)
+	if errResp != nil {
+		return c.Status(status).JSON(errResp)
+	}
+
+	rkapVersion, errResp := parseRKAPVersionQuery(c, "rkap_version")
+	if errResp != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errResp)
+	}
+
+	pdfData, err := h.financialReportUseCase.ExportPerformancePDF(companyID, startPeriod, endPeriod, rkapVersion)
+	if errors.Is(err, usecase.ErrRKAPVersionNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(domain.ErrorResponse{
+			Error:   "not_found",
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "export_failed",
+			Message: err.Error(),
+		})
+	}
+
+	filename := performanceExportFilename(company, startPeriod, endPeriod, "pdf")
+	c.Set("Content-Type", "application/pdf")
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	return c.Send(pdfData)
+}
+
+// performanceExportCompany memvalidasi parameter dan akses export performa (Excel/PDF), lalu mengembalikan company
+func (h *FinancialReportHandler) performanceExportCompany(c *fiber.Ctx, companyID, startPeriod, endPeriod string) (*domain.CompanyModel, int, *domain.ErrorResponse) {
 	if companyID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+		return nil, fiber.StatusBadRequest, &domain.ErrorResponse{
 			Error:   "invalid_request",
 			Message: "Company ID is required",
-		})
+		}
 	}
 
 	if startPeriod == "" || endPeriod == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+		return nil, fiber.StatusBadRequest, &domain.ErrorResponse{
 			Error:   "invalid_request",
 			Message: "Start period and end period are required (format: YYYY-MM)",
-		})
+		}
 	}
 
 	// Get user info for authorization check
@@ -472,10 +577,10 @@ func (h *FinancialReportHandler) ExportPerformanceExcel(c *fiber.Ctx) error {
 	// Authorization check
 	if !utils.IsSuperAdminLike(roleName) {
 		if userCompanyID == nil {
-			return c.Status(fiber.StatusForbidden).JSON(domain.ErrorResponse{
+			return nil, fiber.StatusForbidden, &domain.ErrorResponse{
 				Error:   "forbidden",
 				Message: "Unauthorized access",
-			})
+			}
 		}
 
 		var userCompanyIDStr string
@@ -486,24 +591,67 @@ func (h *FinancialReportHandler) ExportPerformanceExcel(c *fiber.Ctx) error {
 		}
 
 		if userCompanyIDStr != companyID {
-			return c.Status(fiber.StatusForbidden).JSON(domain.ErrorResponse{
+			return nil, fiber.StatusForbidden, &domain.ErrorResponse{
 				Error:   "forbidden",
 				Message: "You can only export performance data for your own company",
-			})
+			}
 		}
 	}
 
 	// Ambil info company untuk filename
 	company, err := h.companyUseCase.GetCompanyByID(companyID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(domain.ErrorResponse{
+		return nil, fiber.StatusNotFound, &domain.ErrorResponse{
 			Error:   "not_found",
 			Message: "Company not found",
-		})
+		}
 	}
+	return company, 0, nil
+}
 
-	// Generate Excel
-	excelData, err := h.financialReportUseCase.ExportPerformanceExcel(companyID, startPeriod, endPeriod)
+func performanceExportFilename(company *domain.CompanyModel, startPeriod, endPeriod, extension string) string {
+	return fmt.Sprintf("Performance_%s_%s_%s.%s",
+		strings.ReplaceAll(company.Name, " ", "_"),
+		startPeriod,
+		endPeriod,
+		extension,
+	)
+}
+
+// ExportFinancialReports handles exporting realisasi financial reports in an exchange format
+// @Summary      Export Financial Reports (xlsx, csv, json)
+// @Description  Export realisasi bulanan company dalam format template Excel, CSV, atau JSON taksonomi. File hasil export bisa di-import ulang lewat /financial-import-jobs.
+// @Tags         Financial Reports
+// @Accept       json
+// @Produce      application/json
+// @Produce      text/csv
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security     BearerAuth
+// @Param        company_id    path      string  true   "Company ID"
+// @Param        format        query     string  false  "xlsx, csv, atau json (default: xlsx)"
+// @Param        start_period  query     string  false  "Start period (YYYY-MM)"
+// @Param        end_period    query     string  false  "End period (YYYY-MM)"
+// @Success      200           {file}    file
+// @Failure      400           {object}  domain.ErrorResponse
+// @Failure      403           {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/{company_id}/financial-reports/export [get]
+// @note         Catatan Teknis:
+// @note         1. Kolom CSV memakai key field (contoh: revenue), fakta JSON memakai kode konsep taksonomi (contoh: pdv:Revenue)
+// @note         2. Daftar konsep tersedia di /financial-reports/taxonomy
+func (h *FinancialReportHandler) ExportFinancialReports(c *fiber.Ctx) error {
+	companyID := c.Params("company_id")
+	if !canAccessCompany(c, h.companyUseCase, companyID, false) {
+		return forbiddenCompany(c)
+	}
+
+	formatName := c.Query("format", domain.FinancialFormatXLSX)
+	data, format, err := h.financialReportUseCase.ExportFinancialReports(companyID, formatName, c.Query("start_period"), c.Query("end_period"))
+	if errors.Is(err, usecase.ErrFinancialFormatUnsupported) {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_format",
+			Message: err.Error(),
+		})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
 			Error:   "export_failed",
@@ -511,17 +659,17 @@ func (h *FinancialReportHandler) ExportPerformanceExcel(c *fiber.Ctx) error {
 		})
 	}
 
-	// Generate filename
-	filename := fmt.Sprintf("Performance_%s_%s_%s.xlsx",
-		strings.ReplaceAll(company.Name, " ", "_"),
-		startPeriod,
-		endPeriod,
-	)
+	userID, _ := c.Locals("userID").(string)
+	username, _ := c.Locals("username").(string)
+	audit.LogAction(userID, username, audit.ActionExportFinancialReports, audit.ResourceFinancialReport, companyID, getClientIP(c), c.Get("User-Agent"), audit.StatusSuccess, map[string]interface{}{
+		"format":       format.Name(),
+		"start_period": c.Query("start_period"),
+		"end_period":   c.Query("end_period"),
+	})
 
-	// Set headers dan kirim file
-	c.Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
-	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
-	return c.Send(excelData)
+	c.Set("Content-Type", format.ContentType())
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=financial_reports_%s%s", companyID, format.Extension()))
+	return c.Send(data)
 }
 
 // GenerateBulkUploadTemplate handles downloading Excel template for financial report bulk upload
@@ -638,42 +786,8 @@ func (h *FinancialReportHandler) GenerateBulkUploadTemplate(c *fiber.Ctx) error
 	}
 
 	// Set headers - semua field Financial Report (Is RKAP dihapus karena default false untuk bulk upload realisasi bulanan)
-	headers := []string{
-		"Kode Perusahaan",
-		"Nama Perusahaan",
-		"Tahun",
-		"Bulan",
-		// Neraca
-		"Aset Lancar",
-		"Aset Tidak Lancar",
-		"Liabilitas Jangka Pendek",
-		"Liabilitas Jangka Panjang",
-		"Ekuitas",
-		// Laba Rugi
-		"Pendapatan",
-		"Beban Usaha",
-		"Laba Usaha",
-		"Pendapatan Lain-Lain",
-		"Pajak",
-		"Laba Bersih",
-		// Cashflow
-		"Arus Kas Operasi",
-		"Arus Kas Investasi",
-		"Arus Kas Pendanaan",
-		"Saldo Akhir",
-		// Rasio
-		"ROE (%)",
-		"ROI (%)",
-		"Rasio Lancar (%)",
-		"Rasio Kas (%)",
-		"EBITDA",
-		"EBITDA Margin (%)",
-		"Net Profit Margin (%)",
-		"Operating Profit Margin (%)",
-		"Debt to Equity",
-		// Metadata
-		"Keterangan",
-	}
+	// Header dipakai bersama dengan parser import agar urutan kolom selalu sama
+	headers := usecase.FinancialImportHeaders()
 
 	// Set header row with styling
 	for i, header := range headers {
@@ -880,570 +994,35 @@ func setCellValue(f *excelize.File, sheetName string, row, col int, value interf
 // @Failure      500   {object}  domain.ErrorResponse
 // @Router       /api/v1/financial-reports/bulk-upload/validate [post]
 func (h *FinancialReportHandler) ValidateBulkExcelFile(c *fiber.Ctx) error {
-	// Get file from form
-	file, err := c.FormFile("file")
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
-			Error:   "invalid_request",
-			Message: "File tidak ditemukan dalam request",
-		})
-	}
-
-	// Validate file extension
-	filename := file.Filename
-	if !strings.HasSuffix(strings.ToLower(filename), ".xlsx") && !strings.HasSuffix(strings.ToLower(filename), ".xls") {
-		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
-			Error:   "invalid_file_format",
-			Message: "Format file tidak valid. Hanya file Excel (.xlsx, .xls) yang diperbolehkan",
-		})
-	}
-
-	// Open file
-	src, err := file.Open()
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
-			Error:   "file_read_error",
-			Message: "Gagal membaca file",
-		})
-	}
-	defer src.Close()
-
-	// Read file into buffer
-	fileData := make([]byte, file.Size)
-	if _, err := src.Read(fileData); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
-			Error:   "file_read_error",
-			Message: "Gagal membaca file",
-		})
-	}
-
-	// Open Excel file
-	f, err := excelize.OpenReader(bytes.NewReader(fileData))
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
-			Error:   "invalid_excel_file",
-			Message: "File Excel tidak valid atau corrupt",
-		})
-	}
-	defer func() {
-		if err := f.Close(); err != nil {
-			fmt.Printf("Error closing Excel file: %v\n", err)
-		}
-	}()
-
-	// Get user info for company access validation
-	companyID := c.Locals("companyID")
-	roleName := c.Locals("roleName").(string)
-
-	// Get accessible company codes
-	var accessibleCompanyCodes map[string]bool
-	if roleName == "superadmin" || roleName == "administrator" {
-		companies, err := h.companyUseCase.GetAllCompanies(false) // Only active companies for calculations
-		if err == nil {
-			accessibleCompanyCodes = make(map[string]bool)
-			for _, company := range companies {
-				// Company is already filtered by repository to only include active
-				accessibleCompanyCodes[company.Code] = true
-			}
-		}
-	} else if companyID != nil {
-		var userCompanyID string
-		if companyIDPtr, ok := companyID.(*string); ok && companyIDPtr != nil {
-			userCompanyID = *companyIDPtr
-		} else if companyIDStr, ok := companyID.(string); ok {
-			userCompanyID = companyIDStr
-		}
-
-		if userCompanyID != "" {
-			userCompany, err := h.companyUseCase.GetCompanyByID(userCompanyID)
-			if err == nil {
-				descendants, err := h.companyUseCase.GetCompanyDescendants(userCompanyID)
-				if err == nil {
-					accessibleCompanyCodes = make(map[string]bool)
-					accessibleCompanyCodes[userCompany.Code] = true
-					for _, company := range descendants {
-						if company.IsActive {
-							accessibleCompanyCodes[company.Code] = true
-						}
-					}
-				}
-			}
-		}
+	_, fileData, status, errResp := readUploadedWorkbook(c)
+	if errResp != nil {
+		return c.Status(status).JSON(errResp)
 	}
 
-	// Get first sheet name
-	sheetName := f.GetSheetName(0)
-	if sheetName == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
-			Error:   "invalid_excel_file",
-			Message: "File Excel tidak memiliki sheet",
-		})
+	accessibleCodes, status, errResp := accessibleCompanyCodes(c, h.companyUseCase)
+	if errResp != nil {
+		return c.Status(status).JSON(errResp)
 	}
 
-	// Get all rows
-	rows, err := f.GetRows(sheetName)
+	rows, err := h.importUseCase.ValidateWorkbook(fileData, accessibleCodes)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
-			Error:   "invalid_excel_file",
-			Message: "Gagal membaca data dari Excel",
-		})
-	}
-
-	if len(rows) < 2 {
-		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
-			Error:   "invalid_excel_file",
-			Message: "File Excel harus memiliki minimal header dan 1 baris data",
-		})
+		return workbookErrorResponse(c, err)
 	}
 
-	// Expected headers (sesuai dengan template baru: Kode Perusahaan, Nama Perusahaan, Tahun, Bulan, kemudian data keuangan)
-	expectedHeaders := []string{
-		"Kode Perusahaan", "Nama Perusahaan", "Tahun", "Bulan",
-		// Neraca
-		"Aset Lancar", "Aset Tidak Lancar", "Liabilitas Jangka Pendek", "Liabilitas Jangka Panjang", "Ekuitas",
-		// Laba Rugi
-		"Pendapatan", "Beban Usaha", "Laba Usaha", "Pendapatan Lain-Lain", "Pajak", "Laba Bersih",
-		// Cashflow
-		"Arus Kas Operasi", "Arus Kas Investasi", "Arus Kas Pendanaan", "Saldo Akhir",
-		// Rasio
-		"ROE (%)", "ROI (%)", "Rasio Lancar (%)", "Rasio Kas (%)", "EBITDA", "EBITDA Margin (%)",
-		"Net Profit Margin (%)", "Operating Profit Margin (%)", "Debt to Equity",
-		// Metadata
-		"Remark",
-	}
-
-	// Validate headers (optional check - just warn if mismatch)
-	headers := rows[0]
-	if len(headers) < len(expectedHeaders) {
-		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
-			Error:   "invalid_excel_file",
-			Message: fmt.Sprintf("Header tidak lengkap. Diperlukan minimal %d kolom, ditemukan %d", len(expectedHeaders), len(headers)),
-		})
-	}
-
-	// Parse and validate data rows
-	var errors []map[string]interface{}
-	var data []map[string]interface{}
-
-	for rowIndex := 1; rowIndex < len(rows); rowIndex++ {
-		row := rows[rowIndex]
-		rowNum := rowIndex + 1 // Excel row number (1-based)
-
-		// Skip empty rows
-		if len(row) == 0 || (len(row) == 1 && strings.TrimSpace(row[0]) == "") {
-			continue
-		}
-
-		rowData := make(map[string]interface{})
-		rowErrors := []map[string]interface{}{}
-
-		colIndex := 0
-
-		// Company Code (required)
-		var companyCode string
-		var companyIDValue string
-		if len(row) > colIndex {
-			companyCode = strings.TrimSpace(row[colIndex])
-			if companyCode == "" {
-				rowErrors = append(rowErrors, map[string]interface{}{
-					"row":     rowNum,
-					"column":  "Kode Perusahaan",
-					"message": "Kode Perusahaan wajib diisi",
-				})
-			} else {
-				if accessibleCompanyCodes != nil && !accessibleCompanyCodes[companyCode] {
-					rowErrors = append(rowErrors, map[string]interface{}{
-						"row":     rowNum,
-						"column":  "Kode Perusahaan",
-						"message": fmt.Sprintf("Kode Perusahaan '%s' tidak ditemukan atau tidak dapat diakses", companyCode),
-					})
-				} else {
-					company, err := h.companyUseCase.GetCompanyByCode(companyCode)
-					if err != nil || company == nil || !company.IsActive {
-						rowErrors = append(rowErrors, map[string]interface{}{
-							"row":     rowNum,
-							"column":  "Kode Perusahaan",
-							"message": fmt.Sprintf("Kode Perusahaan '%s' tidak ditemukan", companyCode),
-						})
-					} else {
-						companyIDValue = company.ID
-						rowData["company_id"] = companyIDValue
-					}
-				}
-			}
-		} else {
-			rowErrors = append(rowErrors, map[string]interface{}{
-				"row":     rowNum,
-				"column":  "Kode Perusahaan",
-				"message": "Kode Perusahaan wajib diisi",
-			})
-		}
-		colIndex++
-
-		// Skip Company Name (colIndex 1) - it's just for reference
-		colIndex++
-
-		// Helper function untuk convert cell value ke string (handle berbagai tipe)
-		cellToString := func(cellValue interface{}) string {
-			if cellValue == nil {
-				return ""
-			}
-			switch v := cellValue.(type) {
-			case string:
-				return v
-			case float64:
-				// Excel number sebagai float
-				if v == float64(int64(v)) {
-					return fmt.Sprintf("%.0f", v) // Remove decimal if whole number
-				}
-				return fmt.Sprintf("%g", v)
-			case int:
-				return fmt.Sprintf("%d", v)
-			case int64:
-				return fmt.Sprintf("%d", v)
-			default:
-				return fmt.Sprintf("%v", v)
-			}
-		}
-
-		// Helper function untuk parse tahun dengan fleksibel (handle berbagai format Excel)
-		parseYear := func(value string) (string, error) {
-			value = strings.TrimSpace(value)
-			if value == "" {
-				return "", fmt.Errorf("tahun tidak boleh kosong")
-			}
-
-			// Try parse as float first (Excel might return "2025.0" or "2025")
-			if yearFloat, err := strconv.ParseFloat(value, 64); err == nil {
-				yearInt := int(yearFloat)
-				if yearFloat != float64(yearInt) {
-					return "", fmt.Errorf("tahun harus berupa bilangan bulat, nilai: '%s'", value)
-				}
-				yearStr := fmt.Sprintf("%04d", yearInt)
-				if yearInt < 1900 || yearInt > 2100 {
-					return "", fmt.Errorf("tahun harus antara 1900-2100, nilai: '%s'", value)
-				}
-				return yearStr, nil
-			}
-
-			// Try parse as int directly
-			if yearInt, err := strconv.Atoi(value); err == nil {
-				yearStr := fmt.Sprintf("%04d", yearInt)
-				if yearInt < 1900 || yearInt > 2100 {
-					return "", fmt.Errorf("tahun harus antara 1900-2100, nilai: '%s'", value)
-				}
-				return yearStr, nil
-			}
-
-			// If it's already a string, validate it's 4 digits
-			if len(value) == 4 {
-				if yearInt, err := strconv.Atoi(value); err == nil {
-					if yearInt < 1900 || yearInt > 2100 {
-						return "", fmt.Errorf("tahun harus antara 1900-2100, nilai: '%s'", value)
-					}
-					return value, nil
-				}
-			}
-
-			return "", fmt.Errorf("tahun harus berupa 4 digit (format YYYY), nilai yang diterima: '%s'", value)
-		}
-
-		// Tahun (required, format YYYY)
-		var year string
-		if len(row) > colIndex {
-			rawValue := row[colIndex]
-			yearStr := cellToString(rawValue) // Convert to string first
-			parsedYear, err := parseYear(yearStr)
-			if err != nil {
-				rowErrors = append(rowErrors, map[string]interface{}{
-					"row":     rowNum,
-					"column":  "Tahun",
-					"message": err.Error(),
-				})
-			} else {
-				year = parsedYear
-				rowData["year"] = year
-			}
-		} else {
-			rowErrors = append(rowErrors, map[string]interface{}{
-				"row":     rowNum,
-				"column":  "Tahun",
-				"message": "Tahun wajib diisi dengan format YYYY",
-			})
-		}
-		colIndex++
-
-		// Helper function untuk parse bulan dengan fleksibel (handle berbagai format Excel)
-		parseBulan := func(value string) (int, error) {
-			value = strings.TrimSpace(value)
-			if value == "" {
-				return 0, fmt.Errorf("bulan tidak boleh kosong")
-			}
-
-			// Try parse as float first (Excel might return "1.0" or "1")
-			if bulanFloat, err := strconv.ParseFloat(value, 64); err == nil {
-				bulanInt := int(bulanFloat)
-				if bulanFloat != float64(bulanInt) {
-					return 0, fmt.Errorf("bulan harus berupa bilangan bulat (1-12), nilai: '%s'", value)
-				}
-				if bulanInt < 1 || bulanInt > 12 {
-					return 0, fmt.Errorf("bulan harus antara 1-12, nilai: '%s'", value)
-				}
-				return bulanInt, nil
-			}
-
-			// Try parse as int directly
-			if bulanInt, err := strconv.Atoi(value); err == nil {
-				if bulanInt < 1 || bulanInt > 12 {
-					return 0, fmt.Errorf("bulan harus antara 1-12, nilai: '%s'", value)
-				}
-				return bulanInt, nil
-			}
-
-			return 0, fmt.Errorf("bulan harus berupa angka (1-12), nilai yang diterima: '%s'", value)
-		}
-
-		// Bulan (required, 1-12)
-		var bulan int
-		var period string
-
-		if len(row) > colIndex {
-			rawValue := row[colIndex]
-			bulanStr := cellToString(rawValue) // Convert to string first
-			parsedBulan, err := parseBulan(bulanStr)
-			if err != nil {
-				rowErrors = append(rowErrors, map[string]interface{}{
-					"row":     rowNum,
-					"column":  "Bulan",
-					"message": err.Error(),
-				})
-			} else {
-				bulan = parsedBulan
-				if year != "" {
-					period = fmt.Sprintf("%s-%02d", year, bulan)
-					rowData["period"] = period
-				}
-			}
+	rowErrors := []domain.FinancialImportRowError{}
+	data := []domain.CreateFinancialReportRequest{}
+	for _, row := range rows {
+		if row.Valid() {
+			data = append(data, row.Request)
 		} else {
-			rowErrors = append(rowErrors, map[string]interface{}{
-				"row":     rowNum,
-				"column":  "Bulan",
-				"message": "Bulan wajib diisi (1-12)",
-			})
-		}
-		colIndex++
-
-		// Is RKAP tidak ada di template (default false untuk bulk upload realisasi bulanan)
-		isRKAP := false
-		rowData["is_rkap"] = isRKAP
-
-		// Validate numeric fields - Helper function untuk parse int64
-		parseInt64Field := func(value string, fieldName string, required bool, allowNegative bool) (int64, bool) {
-			if value == "" {
-				if required {
-					rowErrors = append(rowErrors, map[string]interface{}{
-						"row":     rowNum,
-						"column":  fieldName,
-						"message": fmt.Sprintf("%s wajib diisi", fieldName),
-					})
-					return 0, false
-				}
-				return 0, true // Optional field, empty is OK
-			}
-			parsed, err := strconv.ParseFloat(value, 64)
-			if err != nil {
-				rowErrors = append(rowErrors, map[string]interface{}{
-					"row":     rowNum,
-					"column":  fieldName,
-					"message": fmt.Sprintf("%s harus berupa angka", fieldName),
-				})
-				return 0, false
-			}
-			if !allowNegative && parsed < 0 {
-				rowErrors = append(rowErrors, map[string]interface{}{
-					"row":     rowNum,
-					"column":  fieldName,
-					"message": fmt.Sprintf("%s tidak boleh negatif", fieldName),
-				})
-				return 0, false
-			}
-			return int64(parsed), true
-		}
-
-		// Validate float64 fields
-		// MaxDecimal10_2: 99999999.99 (untuk decimal(10,2))
-		const MaxDecimal10_2 = 99999999.99
-		parseFloat64Field := func(value string, fieldName string, required bool, allowNegative bool, isPercentage bool) (float64, bool) {
-			if value == "" {
-				if required {
-					rowErrors = append(rowErrors, map[string]interface{}{
-						"row":     rowNum,
-						"column":  fieldName,
-						"message": fmt.Sprintf("%s wajib diisi", fieldName),
-					})
-					return 0, false
-				}
-				return 0, true // Optional field, empty is OK
-			}
-			parsed, err := strconv.ParseFloat(value, 64)
-			if err != nil {
-				rowErrors = append(rowErrors, map[string]interface{}{
-					"row":     rowNum,
-					"column":  fieldName,
-					"message": fmt.Sprintf("%s harus berupa angka", fieldName),
-				})
-				return 0, false
-			}
-			if !allowNegative && parsed < 0 {
-				rowErrors = append(rowErrors, map[string]interface{}{
-					"row":     rowNum,
-					"column":  fieldName,
-					"message": fmt.Sprintf("%s tidak boleh negatif", fieldName),
-				})
-				return 0, false
-			}
-			// Validasi untuk decimal(10,2) - maksimal 99999999.99
-			if parsed > MaxDecimal10_2 {
-				rowErrors = append(rowErrors, map[string]interface{}{
-					"row":     rowNum,
-					"column":  fieldName,
-					"message": fmt.Sprintf("%s: nilai terlalu besar (maksimal %.2f)", fieldName, MaxDecimal10_2),
-				})
-				return 0, false
-			}
-			// Validasi untuk persentase tidak boleh > 100%
-			if isPercentage && parsed > 100 {
-				rowErrors = append(rowErrors, map[string]interface{}{
-					"row":     rowNum,
-					"column":  fieldName,
-					"message": fmt.Sprintf("%s: nilai tidak boleh melebihi 100%%", fieldName),
-				})
-				return 0, false
-			}
-			return parsed, true
-		}
-
-		// Neraca fields (optional, but allow 0)
-		neracaFields := []string{"Current Assets", "Non Current Assets", "Short Term Liabilities", "Long Term Liabilities", "Equity"}
-		for _, fieldName := range neracaFields {
-			if len(row) > colIndex {
-				valueStr := strings.TrimSpace(row[colIndex])
-				if valueStr != "" {
-					value, ok := parseInt64Field(valueStr, fieldName, false, true)
-					if ok {
-						rowData[strings.ToLower(strings.ReplaceAll(fieldName, " ", "_"))] = value
-					}
-				}
-			}
-			colIndex++
-		}
-
-		// Laba Rugi fields (optional, allow negative for expenses and tax)
-		labaRugiFields := []struct {
-			name          string
-			allowNegative bool
-		}{
-			{"Revenue", false},
-			{"Operating Expenses", true},
-			{"Operating Profit", true},
-			{"Other Income", true},
-			{"Tax", true},
-			{"Net Profit", true},
-		}
-		for _, field := range labaRugiFields {
-			fieldKey := strings.ToLower(strings.ReplaceAll(field.name, " ", "_"))
-			if len(row) > colIndex {
-				valueStr := strings.TrimSpace(row[colIndex])
-				if valueStr != "" {
-					value, ok := parseInt64Field(valueStr, field.name, false, field.allowNegative)
-					if ok {
-						rowData[fieldKey] = value
-					}
-				}
-			}
-			colIndex++
-		}
-
-		// Cashflow fields (optional, allow negative)
-		cashflowFields := []string{"Operating Cashflow", "Investing Cashflow", "Financing Cashflow", "Ending Balance"}
-		for _, fieldName := range cashflowFields {
-			if len(row) > colIndex {
-				valueStr := strings.TrimSpace(row[colIndex])
-				if valueStr != "" {
-					value, ok := parseInt64Field(valueStr, fieldName, false, true)
-					if ok {
-						rowData[strings.ToLower(strings.ReplaceAll(fieldName, " ", "_"))] = value
-					}
-				}
-			}
-			colIndex++
-		}
-
-		// Rasio fields (optional, default 0)
-		rasioFields := []struct {
-			name    string
-			isInt64 bool
-		}{
-			{"ROE (%)", false},
-			{"ROI (%)", false},
-			{"Current Ratio", false},
-			{"Cash Ratio", false},
-			{"EBITDA", true},
-			{"EBITDA Margin (%)", false},
-			{"Net Profit Margin (%)", false},
-			{"Operating Profit Margin (%)", false},
-			{"Debt to Equity", false},
-		}
-		for _, field := range rasioFields {
-			fieldKey := strings.ToLower(strings.ReplaceAll(strings.ReplaceAll(field.name, " (%)", ""), " ", "_"))
-			if len(row) > colIndex {
-				valueStr := strings.TrimSpace(row[colIndex])
-				if valueStr != "" {
-					if field.isInt64 {
-						value, ok := parseInt64Field(valueStr, field.name, false, true)
-						if ok {
-							rowData[fieldKey] = value
-						}
-					} else {
-						// Tentukan apakah field ini persentase
-						isPercentage := strings.Contains(field.name, "ROE") ||
-							strings.Contains(field.name, "ROI") ||
-							strings.Contains(field.name, "Rasio Lancar") ||
-							strings.Contains(field.name, "Rasio Kas") ||
-							strings.Contains(field.name, "Margin") ||
-							strings.Contains(field.name, "(%")
-
-						value, ok := parseFloat64Field(valueStr, field.name, false, true, isPercentage)
-						if ok {
-							rowData[fieldKey] = value
-						}
-					}
-				}
-			}
-			colIndex++
-		}
-
-		// Remark (optional)
-		if len(row) > colIndex {
-			remark := strings.TrimSpace(row[colIndex])
-			if remark != "" {
-				rowData["remark"] = remark
-			}
-		}
-
-		// Add row errors to errors list
-		errors = append(errors, rowErrors...)
-
-		// Add row data only if no errors
-		if len(rowErrors) == 0 {
-			data = append(data, rowData)
+			rowErrors = append(rowErrors, row.Errors...)
 		}
 	}
 
 	// Return validation result
 	return c.Status(fiber.StatusOK).JSON(map[string]interface{}{
-		"valid":  len(errors) == 0,
-		"errors": errors,
+		"valid":  len(rowErrors) == 0,
+		"errors": rowErrors,
 		"data":   data,
 	})
 }
@@ -1460,736 +1039,29 @@ func (h *FinancialReportHandler) ValidateBulkExcelFile(c *fiber.Ctx) error {
 // @Failure      400   {object}  domain.ErrorResponse
 // @Failure      500   {object}  domain.ErrorResponse
 // @Router       /api/v1/financial-reports/bulk-upload [post]
+// @note         Upload langsung tanpa review. Untuk preview diff dan commit sebagian gunakan /financial-import-jobs.
 func (h *FinancialReportHandler) UploadBulkFinancialReports(c *fiber.Ctx) error {
-	// Get file from form
-	file, err := c.FormFile("file")
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
-			Error:   "invalid_request",
-			Message: "File tidak ditemukan dalam request",
-		})
-	}
-
-	filename := strings.ToLower(file.Filename)
-	if !strings.HasSuffix(filename, ".xlsx") && !strings.HasSuffix(filename, ".xls") {
-		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
-			Error:   "invalid_file_format",
-			Message: "Format file tidak valid. Hanya file Excel (.xlsx, .xls) yang diperbolehkan",
-		})
-	}
-
-	src, err := file.Open()
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
-			Error:   "file_read_error",
-			Message: "Gagal membaca file",
-		})
-	}
-	defer src.Close()
-
-	fileData, err := io.ReadAll(src)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
-			Error:   "file_read_error",
-			Message: "Gagal membaca file",
-		})
-	}
-
-	f, err := excelize.OpenReader(bytes.NewReader(fileData))
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
-			Error:   "invalid_excel_file",
-			Message: "File Excel tidak valid atau corrupt",
-		})
-	}
-	defer func() {
-		if err := f.Close(); err != nil {
-			fmt.Printf("Error closing Excel file: %v\n", err)
-		}
-	}()
-
-	// Get user info
-	userID := c.Locals("userID").(string)
-	username := c.Locals("username").(string)
-	roleName := c.Locals("roleName").(string)
-	companyID := c.Locals("companyID")
-
-	// Determine accessible company codes
-	var accessibleCompanyCodes map[string]bool
-	if roleName == "superadmin" || roleName == "administrator" {
-		companies, err := h.companyUseCase.GetAllCompanies(false) // Only active companies for calculations
-		if err == nil {
-			accessibleCompanyCodes = make(map[string]bool)
-			for _, company := range companies {
-				// Company is already filtered by repository to only include active
-				accessibleCompanyCodes[company.Code] = true
-			}
-		}
-	} else if companyID != nil {
-		var userCompanyID string
-		if companyIDPtr, ok := companyID.(*string); ok && companyIDPtr != nil {
-			userCompanyID = *companyIDPtr
-		} else if companyIDStr, ok := companyID.(string); ok {
-			userCompanyID = companyIDStr
-		}
-
-		if userCompanyID != "" {
-			userCompany, err := h.companyUseCase.GetCompanyByID(userCompanyID)
-			if err == nil {
-				descendants, err := h.companyUseCase.GetCompanyDescendants(userCompanyID)
-				if err == nil {
-					accessibleCompanyCodes = make(map[string]bool)
-					accessibleCompanyCodes[userCompany.Code] = true
-					for _, company := range descendants {
-						if company.IsActive {
-							accessibleCompanyCodes[company.Code] = true
-						}
-					}
-				}
-			}
-		}
+	_, fileData, status, errResp := readUploadedWorkbook(c)
+	if errResp != nil {
+		return c.Status(status).JSON(errResp)
 	}
 
-	sheetName := f.GetSheetName(0)
-	if sheetName == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
-			Error:   "invalid_excel_file",
-			Message: "File Excel tidak memiliki sheet",
-		})
+	accessibleCodes, status, errResp := accessibleCompanyCodes(c, h.companyUseCase)
+	if errResp != nil {
+		return c.Status(status).JSON(errResp)
 	}
 
-	rows, err := f.GetRows(sheetName)
+	result, err := h.importUseCase.ImportWorkbook(fileData, accessibleCodes, financialImportActor(c))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
-			Error:   "invalid_excel_file",
-			Message: "Gagal membaca data dari Excel",
-		})
-	}
-
-	if len(rows) < 2 {
-		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
-			Error:   "invalid_excel_file",
-			Message: "File Excel harus memiliki minimal header dan 1 baris data",
-		})
-	}
-
-	// Get IP and User Agent for audit log
-	ipAddress := c.IP()
-	userAgent := c.Get("User-Agent", "")
-
-	// Process rows - reuse validation logic but create reports
-	errorsList := []map[string]interface{}{}
-	successCount := 0
-	failedCount := 0
-	createdCount := 0
-	updatedCount := 0
-
-	// Helper functions for parsing (same as validation)
-	// MaxInt64: 9223372036854775807 (untuk PostgreSQL bigint)
-	// MaxDecimal10_2: 99999999.99 (untuk decimal(10,2))
-	const MaxDecimal10_2 = 99999999.99
-
-	parseInt64Field := func(value string, allowNegative bool) (int64, error) {
-		parsed, err := strconv.ParseFloat(value, 64)
-		if err != nil {
-			return 0, err
-		}
-		if !allowNegative && parsed < 0 {
-			return 0, fmt.Errorf("nilai tidak boleh negatif")
-		}
-		// Validasi tidak melebihi MaxInt64
-		if parsed > float64(math.MaxInt64) {
-			return 0, fmt.Errorf("nilai terlalu besar (maksimal %d)", math.MaxInt64)
-		}
-		// Validasi tidak kurang dari MinInt64
-		if parsed < float64(math.MinInt64) {
-			return 0, fmt.Errorf("nilai terlalu kecil (minimal %d)", math.MinInt64)
-		}
-		return int64(parsed), nil
-	}
-
-	parseFloat64Field := func(value string, allowNegative bool, isPercentage bool) (float64, error) {
-		parsed, err := strconv.ParseFloat(value, 64)
-		if err != nil {
-			return 0, err
-		}
-		if !allowNegative && parsed < 0 {
-			return 0, fmt.Errorf("nilai tidak boleh negatif")
-		}
-		// Validasi untuk decimal(10,2) - maksimal 99999999.99
-		if parsed > MaxDecimal10_2 {
-			return 0, fmt.Errorf("nilai terlalu besar (maksimal %.2f)", MaxDecimal10_2)
-		}
-		// Validasi untuk persentase tidak boleh > 100%
-		if isPercentage && parsed > 100 {
-			return 0, fmt.Errorf("nilai tidak boleh melebihi 100%%")
-		}
-		return parsed, nil
-	}
-
-	for rowIndex := 1; rowIndex < len(rows); rowIndex++ {
-		row := rows[rowIndex]
-		rowNum := rowIndex + 1
-
-		if len(row) == 0 || (len(row) == 1 && strings.TrimSpace(row[0]) == "") {
-			continue
-		}
-
-		rowErrors := []map[string]interface{}{}
-		colIndex := 0
-
-		// Company Code (colIndex 0)
-		var companyIDValue string
-		if len(row) > colIndex {
-			companyCode := strings.TrimSpace(row[colIndex])
-			if companyCode == "" {
-				rowErrors = append(rowErrors, map[string]interface{}{
-					"row":     rowNum,
-					"column":  "Kode Perusahaan",
-					"message": "Kode Perusahaan wajib diisi",
-				})
-			} else {
-				if accessibleCompanyCodes != nil && !accessibleCompanyCodes[companyCode] {
-					rowErrors = append(rowErrors, map[string]interface{}{
-						"row":     rowNum,
-						"column":  "Kode Perusahaan",
-						"message": fmt.Sprintf("Kode Perusahaan '%s' tidak ditemukan atau tidak dapat diakses", companyCode),
-					})
-				} else {
-					company, err := h.companyUseCase.GetCompanyByCode(companyCode)
-					if err != nil || company == nil || !company.IsActive {
-						rowErrors = append(rowErrors, map[string]interface{}{
-							"row":     rowNum,
-							"column":  "Kode Perusahaan",
-							"message": fmt.Sprintf("Kode Perusahaan '%s' tidak ditemukan", companyCode),
-						})
-					} else {
-						companyIDValue = company.ID
-					}
-				}
-			}
-		}
-		colIndex++ // Move to Company Name (colIndex 1)
-
-		// Skip Company Name (colIndex 1) - it's just for reference, we don't need to validate it
-		colIndex++ // Move to Tahun (colIndex 2)
-
-		// Helper function untuk convert cell value ke string (handle berbagai tipe)
-		cellToString := func(cellValue interface{}) string {
-			if cellValue == nil {
-				return ""
-			}
-			switch v := cellValue.(type) {
-			case string:
-				return v
-			case float64:
-				// Excel number sebagai float
-				if v == float64(int64(v)) {
-					return fmt.Sprintf("%.0f", v) // Remove decimal if whole number
-				}
-				return fmt.Sprintf("%g", v)
-			case int:
-				return fmt.Sprintf("%d", v)
-			case int64:
-				return fmt.Sprintf("%d", v)
-			default:
-				return fmt.Sprintf("%v", v)
-			}
-		}
-
-		// Helper function untuk parse tahun dengan fleksibel (handle berbagai format Excel)
-		parseYear := func(value string) (string, error) {
-			value = strings.TrimSpace(value)
-			if value == "" {
-				return "", fmt.Errorf("tahun tidak boleh kosong")
-			}
-
-			// Try parse as float first (Excel might return "2025.0" or "2025")
-			if yearFloat, err := strconv.ParseFloat(value, 64); err == nil {
-				yearInt := int(yearFloat)
-				if yearFloat != float64(yearInt) {
-					return "", fmt.Errorf("tahun harus berupa bilangan bulat, nilai: '%s'", value)
-				}
-				yearStr := fmt.Sprintf("%04d", yearInt)
-				if yearInt < 1900 || yearInt > 2100 {
-					return "", fmt.Errorf("tahun harus antara 1900-2100, nilai: '%s'", value)
-				}
-				return yearStr, nil
-			}
-
-			// Try parse as int directly
-			if yearInt, err := strconv.Atoi(value); err == nil {
-				yearStr := fmt.Sprintf("%04d", yearInt)
-				if yearInt < 1900 || yearInt > 2100 {
-					return "", fmt.Errorf("tahun harus antara 1900-2100, nilai: '%s'", value)
-				}
-				return yearStr, nil
-			}
-
-			// If it's already a string, validate it's 4 digits
-			if len(value) == 4 {
-				if yearInt, err := strconv.Atoi(value); err == nil {
-					if yearInt < 1900 || yearInt > 2100 {
-						return "", fmt.Errorf("tahun harus antara 1900-2100, nilai: '%s'", value)
-					}
-					return value, nil
-				}
-			}
-
-			return "", fmt.Errorf("tahun harus berupa 4 digit (format YYYY), nilai yang diterima: '%s'", value)
-		}
-
-		// Tahun (required, format YYYY)
-		var year string
-		if len(row) > colIndex {
-			rawValue := row[colIndex]
-			yearStr := cellToString(rawValue) // Convert to string first
-			parsedYear, err := parseYear(yearStr)
-			if err != nil {
-				rowErrors = append(rowErrors, map[string]interface{}{
-					"row":     rowNum,
-					"column":  "Tahun",
-					"message": err.Error(),
-				})
-			} else {
-				year = parsedYear
-			}
-		} else {
-			rowErrors = append(rowErrors, map[string]interface{}{
-				"row":     rowNum,
-				"column":  "Tahun",
-				"message": "Tahun wajib diisi dengan format YYYY",
-			})
-		}
-		colIndex++
-
-		// Helper function untuk parse bulan dengan fleksibel (handle berbagai format Excel)
-		parseBulan := func(value string) (int, error) {
-			value = strings.TrimSpace(value)
-			if value == "" {
-				return 0, fmt.Errorf("bulan tidak boleh kosong")
-			}
-
-			// Try parse as float first (Excel might return "1.0" or "1")
-			if bulanFloat, err := strconv.ParseFloat(value, 64); err == nil {
-				bulanInt := int(bulanFloat)
-				if bulanFloat != float64(bulanInt) {
-					return 0, fmt.Errorf("bulan harus berupa bilangan bulat (1-12), nilai: '%s'", value)
-				}
-				if bulanInt < 1 || bulanInt > 12 {
-					return 0, fmt.Errorf("bulan harus antara 1-12, nilai: '%s'", value)
-				}
-				return bulanInt, nil
-			}
-
-			// Try parse as int directly
-			if bulanInt, err := strconv.Atoi(value); err == nil {
-				if bulanInt < 1 || bulanInt > 12 {
-					return 0, fmt.Errorf("bulan harus antara 1-12, nilai: '%s'", value)
-				}
-				return bulanInt, nil
-			}
-
-			return 0, fmt.Errorf("bulan harus berupa angka (1-12), nilai yang diterima: '%s'", value)
-		}
-
-		// Bulan (required, 1-12)
-		var bulan int
-		var period string
-		bulanParsed := false
-
-		if len(row) > colIndex {
-			rawValue := row[colIndex]
-			bulanStr := cellToString(rawValue) // Convert to string first
-			parsedBulan, err := parseBulan(bulanStr)
-			if err != nil {
-				rowErrors = append(rowErrors, map[string]interface{}{
-					"row":     rowNum,
-					"column":  "Bulan",
-					"message": err.Error(),
-				})
-			} else {
-				bulan = parsedBulan
-				if year != "" {
-					period = fmt.Sprintf("%s-%02d", year, bulan)
-				}
-				bulanParsed = true
-			}
-		} else {
-			rowErrors = append(rowErrors, map[string]interface{}{
-				"row":     rowNum,
-				"column":  "Bulan",
-				"message": "Bulan wajib diisi (1-12)",
-			})
-		}
-		colIndex++
-
-		// Is RKAP tidak ada di template (default false untuk bulk upload realisasi bulanan)
-		isRKAP := false
-
-		// Validate year and bulan are set
-		if !bulanParsed || year == "" {
-			// Bulan atau tahun parsing gagal, error sudah ditambahkan
-			errorsList = append(errorsList, rowErrors...)
-			failedCount++
-			continue
-		}
-
-		// Generate period from year and bulan
-		if period == "" {
-			period = fmt.Sprintf("%s-%02d", year, bulan)
-		}
-
-		// If there are errors so far, skip this row
-		if len(rowErrors) > 0 {
-			errorsList = append(errorsList, rowErrors...)
-			failedCount++
-			continue
-		}
-
-		// Build CreateFinancialReportRequest
-		req := domain.CreateFinancialReportRequest{
-			CompanyID: companyIDValue,
-			Year:      year,
-			Period:    period,
-			IsRKAP:    isRKAP,
-		}
-
-		// Parse Neraca fields (optional)
-		for i, fieldName := range []string{"Current Assets", "Non Current Assets", "Short Term Liabilities", "Long Term Liabilities", "Equity"} {
-			if len(row) > colIndex {
-				valueStr := strings.TrimSpace(row[colIndex])
-				if valueStr != "" {
-					value, err := parseInt64Field(valueStr, true)
-					if err != nil {
-						rowErrors = append(rowErrors, map[string]interface{}{
-							"row":     rowNum,
-							"column":  fieldName,
-							"message": fmt.Sprintf("%s: %v", fieldName, err),
-						})
-					} else {
-						switch i {
-						case 0:
-							req.CurrentAssets = value
-						case 1:
-							req.NonCurrentAssets = value
-						case 2:
-							req.ShortTermLiabilities = value
-						case 3:
-							req.LongTermLiabilities = value
-						case 4:
-							req.Equity = value
-						}
-					}
-				}
-			}
-			colIndex++
-		}
-
-		// Parse Laba Rugi fields (optional, headers sudah dalam Bahasa Indonesia)
-		labaRugiFields := []struct {
-			name          string
-			allowNegative bool
-			target        *int64
-		}{
-			{"Pendapatan", false, &req.Revenue},
-			{"Beban Usaha", true, &req.OperatingExpenses},
-			{"Laba Usaha", true, &req.OperatingProfit},
-			{"Pendapatan Lain-Lain", true, &req.OtherIncome},
-			{"Pajak", true, &req.Tax},
-			{"Laba Bersih", true, &req.NetProfit},
-		}
-		for _, field := range labaRugiFields {
-			if len(row) > colIndex {
-				valueStr := strings.TrimSpace(row[colIndex])
-				if valueStr != "" {
-					value, err := parseInt64Field(valueStr, field.allowNegative)
-					if err != nil {
-						rowErrors = append(rowErrors, map[string]interface{}{
-							"row":     rowNum,
-							"column":  field.name,
-							"message": fmt.Sprintf("%s: %v", field.name, err),
-						})
-					} else {
-						*field.target = value
-					}
-				}
-			}
-			colIndex++
-		}
-
-		// Parse Cashflow fields (optional, headers sudah dalam Bahasa Indonesia)
-		for i, fieldName := range []string{"Arus Kas Operasi", "Arus Kas Investasi", "Arus Kas Pendanaan", "Saldo Akhir"} {
-			if len(row) > colIndex {
-				valueStr := strings.TrimSpace(row[colIndex])
-				if valueStr != "" {
-					value, err := parseInt64Field(valueStr, true)
-					if err != nil {
-						rowErrors = append(rowErrors, map[string]interface{}{
-							"row":     rowNum,
-							"column":  fieldName,
-							"message": fmt.Sprintf("%s: %v", fieldName, err),
-						})
-					} else {
-						switch i {
-						case 0:
-							req.OperatingCashflow = value
-						case 1:
-							req.InvestingCashflow = value
-						case 2:
-							req.FinancingCashflow = value
-						case 3:
-							req.EndingBalance = value
-						}
-					}
-				}
-			}
-			colIndex++
-		}
-
-		// Parse Rasio fields (headers sudah dalam Bahasa Indonesia)
-		rasioFields := []struct {
-			name          string
-			isInt64       bool
-			targetInt64   *int64
-			targetFloat64 *float64
-		}{
-			{"ROE (%)", false, nil, &req.ROE},
-			{"ROI (%)", false, nil, &req.ROI},
-			{"Rasio Lancar (%)", false, nil, &req.CurrentRatio},
-			{"Rasio Kas (%)", false, nil, &req.CashRatio},
-			{"EBITDA", true, &req.EBITDA, nil},
-			{"EBITDA Margin (%)", false, nil, &req.EBITDAMargin},
-			{"Net Profit Margin (%)", false, nil, &req.NetProfitMargin},
-			{"Operating Profit Margin (%)", false, nil, &req.OperatingProfitMargin},
-			{"Debt to Equity", false, nil, &req.DebtToEquity},
-		}
-		for _, field := range rasioFields {
-			if len(row) > colIndex {
-				valueStr := strings.TrimSpace(row[colIndex])
-				if valueStr != "" {
-					if field.isInt64 {
-						value, err := parseInt64Field(valueStr, true)
-						if err != nil {
-							rowErrors = append(rowErrors, map[string]interface{}{
-								"row":     rowNum,
-								"column":  field.name,
-								"message": fmt.Sprintf("%s: %v", field.name, err),
-							})
-						} else {
-							*field.targetInt64 = value
-						}
-					} else {
-						// Tentukan apakah field ini persentase
-						isPercentage := strings.Contains(field.name, "ROE") ||
-							strings.Contains(field.name, "ROI") ||
-							strings.Contains(field.name, "Rasio Lancar") ||
-							strings.Contains(field.name, "Rasio Kas") ||
-							strings.Contains(field.name, "Margin") ||
-							strings.Contains(field.name, "(%")
-
-						value, err := parseFloat64Field(valueStr, true, isPercentage)
-						if err != nil {
-							rowErrors = append(rowErrors, map[string]interface{}{
-								"row":     rowNum,
-								"column":  field.name,
-								"message": fmt.Sprintf("%s: %v", field.name, err),
-							})
-						} else {
-							*field.targetFloat64 = value
-						}
-					}
-				}
-			}
-			colIndex++
-		}
-
-		// Remark (optional)
-		if len(row) > colIndex {
-			remark := strings.TrimSpace(row[colIndex])
-			if remark != "" {
-				req.Remark = &remark
-			}
-		}
-
-		// If there are parsing errors, skip this row
-		if len(rowErrors) > 0 {
-			errorsList = append(errorsList, rowErrors...)
-			failedCount++
-			continue
-		}
-
-		// Check if financial report already exists (upsert logic)
-		var existingReport *domain.FinancialReportModel
-		var err error
-
-		if isRKAP {
-			// For RKAP, check by company_id, year, and is_rkap
-			existingReport, err = h.financialReportUseCase.GetRKAPByCompanyIDAndYear(companyIDValue, year)
-			if err != nil && err != gorm.ErrRecordNotFound {
-				errorsList = append(errorsList, map[string]interface{}{
-					"row":     rowNum,
-					"column":  "general",
-					"message": fmt.Sprintf("Error checking existing RKAP: %v", err),
-				})
-				failedCount++
-				continue
-			}
-		} else {
-			// For Realisasi, check by company_id, period, and is_rkap
-			existingReport, err = h.financialReportUseCase.GetRealisasiByCompanyIDAndPeriod(companyIDValue, period)
-			if err != nil && err != gorm.ErrRecordNotFound {
-				errorsList = append(errorsList, map[string]interface{}{
-					"row":     rowNum,
-					"column":  "general",
-					"message": fmt.Sprintf("Error checking existing Realisasi: %v", err),
-				})
-				failedCount++
-				continue
-			}
-		}
-
-		// Upsert: Update if exists, Create if not
-		if existingReport != nil {
-			// Convert CreateFinancialReportRequest to UpdateFinancialReportRequest
-			updateReq := domain.UpdateFinancialReportRequest{
-				Year:   &year,
-				Period: &period,
-				IsRKAP: &isRKAP,
-				// Neraca
-				CurrentAssets:        &req.CurrentAssets,
-				NonCurrentAssets:     &req.NonCurrentAssets,
-				ShortTermLiabilities: &req.ShortTermLiabilities,
-				LongTermLiabilities:  &req.LongTermLiabilities,
-				Equity:               &req.Equity,
-				// Laba Rugi
-				Revenue:           &req.Revenue,
-				OperatingExpenses: &req.OperatingExpenses,
-				OperatingProfit:   &req.OperatingProfit,
-				OtherIncome:       &req.OtherIncome,
-				Tax:               &req.Tax,
-				NetProfit:         &req.NetProfit,
-				// Cashflow
-				OperatingCashflow: &req.OperatingCashflow,
-				InvestingCashflow: &req.InvestingCashflow,
-				FinancingCashflow: &req.FinancingCashflow,
-				EndingBalance:     &req.EndingBalance,
-				// Rasio
-				ROE:                   &req.ROE,
-				ROI:                   &req.ROI,
-				CurrentRatio:          &req.CurrentRatio,
-				CashRatio:             &req.CashRatio,
-				EBITDA:                &req.EBITDA,
-				EBITDAMargin:          &req.EBITDAMargin,
-				NetProfitMargin:       &req.NetProfitMargin,
-				OperatingProfitMargin: &req.OperatingProfitMargin,
-				DebtToEquity:          &req.DebtToEquity,
-				Remark:                req.Remark,
-			}
-
-			// Update existing report
-			_, err = h.financialReportUseCase.UpdateFinancialReport(existingReport.ID, &updateReq, userID, username, ipAddress, userAgent)
-			if err != nil {
-				// Parse error untuk menentukan kolom yang bermasalah
-				errMsg := err.Error()
-				column := "general"
-
-				// Cek apakah error terkait validasi rasio > 100%
-				if strings.Contains(errMsg, "rasio keuangan tidak boleh melebihi 100%") {
-					// Tentukan kolom mana yang bermasalah
-					if updateReq.ROE != nil && *updateReq.ROE > 100 {
-						column = "ROE (%)"
-					} else if updateReq.ROI != nil && *updateReq.ROI > 100 {
-						column = "ROI (%)"
-					} else if updateReq.CurrentRatio != nil && *updateReq.CurrentRatio > 100 {
-						column = "Rasio Lancar (%)"
-					} else if updateReq.CashRatio != nil && *updateReq.CashRatio > 100 {
-						column = "Rasio Kas (%)"
-					} else if updateReq.EBITDAMargin != nil && *updateReq.EBITDAMargin > 100 {
-						column = "EBITDA Margin (%)"
-					} else if updateReq.NetProfitMargin != nil && *updateReq.NetProfitMargin > 100 {
-						column = "Net Profit Margin (%)"
-					} else if updateReq.OperatingProfitMargin != nil && *updateReq.OperatingProfitMargin > 100 {
-						column = "Operating Profit Margin (%)"
-					}
-					errMsg = fmt.Sprintf("%s: nilai tidak boleh melebihi 100%%", column)
-				} else if strings.Contains(errMsg, "numeric field overflow") || strings.Contains(errMsg, "SQLSTATE 22003") {
-					// Error overflow dari database - cari field mana yang bermasalah
-					column = "Data numerik terlalu besar"
-					errMsg = "Nilai terlalu besar untuk disimpan. Pastikan: int64 tidak melebihi 9,223,372,036,854,775,807 dan persentase/rasio tidak melebihi 99,999,999.99"
-				}
-
-				errorsList = append(errorsList, map[string]interface{}{
-					"row":     rowNum,
-					"column":  column,
-					"message": errMsg,
-				})
-				failedCount++
-				continue
-			}
-			updatedCount++
-		} else {
-			// Create new report
-			_, err = h.financialReportUseCase.CreateFinancialReport(&req, userID, username, ipAddress, userAgent)
-			if err != nil {
-				// Parse error untuk menentukan kolom yang bermasalah
-				errMsg := err.Error()
-				column := "general"
-
-				// Cek apakah error terkait validasi rasio > 100%
-				if strings.Contains(errMsg, "rasio keuangan tidak boleh melebihi 100%") {
-					// Tentukan kolom mana yang bermasalah
-					if req.ROE > 100 {
-						column = "ROE (%)"
-					} else if req.ROI > 100 {
-						column = "ROI (%)"
-					} else if req.CurrentRatio > 100 {
-						column = "Rasio Lancar (%)"
-					} else if req.CashRatio > 100 {
-						column = "Rasio Kas (%)"
-					} else if req.EBITDAMargin > 100 {
-						column = "EBITDA Margin (%)"
-					} else if req.NetProfitMargin > 100 {
-						column = "Net Profit Margin (%)"
-					} else if req.OperatingProfitMargin > 100 {
-						column = "Operating Profit Margin (%)"
-					}
-					errMsg = fmt.Sprintf("%s: nilai tidak boleh melebihi 100%%", column)
-				}
-
-				errorsList = append(errorsList, map[string]interface{}{
-					"row":     rowNum,
-					"column":  column,
-					"message": errMsg,
-				})
-				failedCount++
-				continue
-			}
-			createdCount++
-		}
-
-		successCount++
-	}
-
-	// Log hasil upload untuk debugging
-	zapLog := logger.GetLogger()
-	zapLog.Info("Bulk upload completed",
-		zap.Int("success", successCount),
-		zap.Int("failed", failedCount),
-		zap.Int("created", createdCount),
-		zap.Int("updated", updatedCount),
-		zap.Int("total_errors", len(errorsList)),
-	)
-	if len(errorsList) > 0 {
-		zapLog.Warn("Upload errors found", zap.Any("errors", errorsList))
+		return workbookErrorResponse(c, err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(map[string]interface{}{
-		"success": successCount,
-		"failed":  failedCount,
-		"created": createdCount,
-		"updated": updatedCount,
-		"errors":  errorsList,
-		"message": fmt.Sprintf("Upload selesai: %d berhasil (%d dibuat, %d diupdate), %d gagal", successCount, createdCount, updatedCount, failedCount),
+		"success": result.Success,
+		"failed":  result.Failed,
+		"created": result.Created,
+		"updated": result.Updated,
+		"errors":  result.Errors,
+		"message": fmt.Sprintf("Upload selesai: %d berhasil (%d dibuat, %d diupdate), %d gagal", result.Success, result.Created, result.Updated, result.Failed),
 	})
 }
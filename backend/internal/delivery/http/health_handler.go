@@ -0,0 +1,80 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/health"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/utils"
+)
+
+// LivenessHandler menandakan proses API hidup (tanpa mengecek dependency)
+// @Summary      Liveness Check
+// @Description  Mengecek apakah proses API berjalan. Tidak mengecek database atau dependency lain, sehingga restart container hanya dipicu jika proses benar-benar hang. Endpoint ini public.
+// @Tags         General
+// @Produce      json
+// @Success      200  {object}  map[string]string  "API hidup. Response berisi status: 'OK' dan service: 'pedeve-backend'"
+// @Router       /health/live [get]
+// @note         Catatan Teknis:
+// @note         1. Public Endpoint: Endpoint ini tidak memerlukan authentication
+// @note         2. Liveness: Gunakan untuk livenessProbe; /health adalah alias untuk kompatibilitas
+// @note         3. Readiness: Status dependency ada di /health/ready
+func LivenessHandler(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"status":  "OK",
+		"service": "pedeve-backend",
+	})
+}
+
+// ReadinessHandler mengecek apakah API siap menerima traffic
+// @Summary      Readiness Check
+// @Description  Mengecek database (koneksi dan tabel migrasi), storage, key-value store, encryption, secret manager, dan heartbeat scheduler. Response public hanya berisi status keseluruhan; detail per komponen ada di /api/v1/health/details (admin).
+// @Tags         General
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}  "Siap (status up atau degraded)"
+// @Failure      503  {object}  map[string]interface{}  "Tidak siap (komponen critical down)"
+// @Router       /health/ready [get]
+// @note         Catatan Teknis:
+// @note         1. Public Endpoint: Endpoint ini tidak memerlukan authentication dan tidak membocorkan detail komponen
+// @note         2. Status: up, degraded (komponen non-critical bermasalah, tetap 200), down (503)
+// @note         3. Cache: Hasil check di-cache HEALTH_CACHE_SECONDS (default 5 detik) agar probe tidak membebani dependency
+// @note         4. Timeout: Setiap komponen dibatasi HEALTH_CHECK_TIMEOUT_SECONDS (default 3 detik)
+func ReadinessHandler(c *fiber.Ctx) error {
+	report := health.GetChecker().Check(c.UserContext(), false)
+
+	status := fiber.StatusOK
+	if !report.Ready() {
+		status = fiber.StatusServiceUnavailable
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"status":     report.Status,
+		"checked_at": report.CheckedAt,
+	})
+}
+
+// GetHealthDetailsHandler mengembalikan hasil readiness check per komponen
+// @Summary      Detail Health Check
+// @Description  Mengembalikan status, latensi, dan detail setiap komponen readiness (database, migrations, storage, kvstore, encryption, secret_manager, scheduler). Hanya untuk superadmin/administrator.
+// @Tags         General
+// @Produce      json
+// @Security     BearerAuth
+// @Param        refresh  query     bool  false  "true untuk mengabaikan cache dan menjalankan check ulang"
+// @Success      200  {object}  health.Report
+// @Failure      401  {object}  domain.ErrorResponse  "Token tidak valid atau user tidak terautentikasi"
+// @Failure      403  {object}  domain.ErrorResponse  "Hanya superadmin/administrator"
+// @Router       /api/v1/health/details [get]
+// @note         Catatan Teknis:
+// @note         1. Critical: Komponen critical yang down membuat /health/ready mengembalikan 503
+// @note         2. Non-critical: secret_manager dan scheduler hanya menurunkan status menjadi degraded
+// @note         3. Scheduler: Heartbeat dianggap macet jika tidak berdetak selama 2x interval scheduler
+func GetHealthDetailsHandler(c *fiber.Ctx) error {
+	roleName, _ := c.Locals("roleName").(string)
+	if !utils.IsSuperAdminLike(roleName) {
+		return c.Status(fiber.StatusForbidden).JSON(domain.ErrorResponse{
+			Error:   "forbidden",
+			Message: "Only superadmin or administrator can view health details",
+		})
+	}
+
+	report := health.GetChecker().Check(c.UserContext(), c.QueryBool("refresh"))
+	return c.Status(fiber.StatusOK).JSON(report)
+}
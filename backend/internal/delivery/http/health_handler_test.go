@@ -0,0 +1,45 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthHandlers(t *testing.T) {
+	app := fiber.New()
+	app.Get("/health/live", LivenessHandler)
+	app.Get("/health/ready", ReadinessHandler)
+	app.Get("/details", func(c *fiber.Ctx) error {
+		c.Locals("roleName", c.Query("role"))
+		return c.Next()
+	}, GetHealthDetailsHandler)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/health/live", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/health/ready", nil), -1)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	var ready map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &ready))
+	assert.Contains(t, ready, "status")
+	assert.NotContains(t, ready, "components")
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/details?role=user", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 403, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/details?role=superadmin", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	body, _ = io.ReadAll(resp.Body)
+	assert.Contains(t, string(body), `"components"`)
+	assert.Contains(t, string(body), `"latency_ms"`)
+}
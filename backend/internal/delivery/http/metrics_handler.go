@@ -0,0 +1,39 @@
+package http
+
+import (
+	"crypto/subtle"
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/observability"
+)
+
+var metricsHTTPHandler = adaptor.HTTPHandler(observability.MetricsHTTPHandler())
+
+// MetricsHandler mengekspos metric aplikasi dalam format Prometheus
+// @Summary      Prometheus metrics
+// @Description  Metric HTTP (RED per route template), durasi query database, operasi storage, background job, dan penolakan rate limit dalam format Prometheus text exposition.
+// @Tags         General
+// @Produce      plain
+// @Param        Authorization  header    string  false  "Bearer <METRICS_TOKEN> jika METRICS_TOKEN diset"
+// @Success      200  {string}  string  "Metric dalam format Prometheus"
+// @Failure      401  {object}  domain.ErrorResponse
+// @Router       /metrics [get]
+// @note         Catatan Teknis:
+// @note         1. Jika env METRICS_TOKEN diset, scraper wajib mengirim header Authorization: Bearer <METRICS_TOKEN>
+// @note         2. Label route memakai template (misalnya /api/v1/companies/:id), request tanpa route yang cocok berlabel "unmatched"
+func MetricsHandler(c *fiber.Ctx) error {
+	if token := os.Getenv("METRICS_TOKEN"); token != "" {
+		provided := strings.TrimPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			return c.Status(fiber.StatusUnauthorized).JSON(domain.ErrorResponse{
+				Error:   "unauthorized",
+				Message: "Invalid metrics token",
+			})
+		}
+	}
+	return metricsHTTPHandler(c)
+}
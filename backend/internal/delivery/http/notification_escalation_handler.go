@@ -0,0 +1,223 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/audit"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/usecase"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// NotificationEscalationHandler handles escalation HTTP requests untuk notifikasi expired yang tidak ditindaklanjuti
+type NotificationEscalationHandler struct {
+	escalationUC usecase.NotificationEscalationUseCase
+	companyUC    usecase.CompanyUseCase
+}
+
+// NewNotificationEscalationHandler creates a new notification escalation handler
+func NewNotificationEscalationHandler(escalationUC usecase.NotificationEscalationUseCase, companyUC usecase.CompanyUseCase) *NotificationEscalationHandler {
+	return &NotificationEscalationHandler{
+		escalationUC: escalationUC,
+		companyUC:    companyUC,
+	}
+}
+
+// ResolveEscalationRequest payload untuk menutup rantai eskalasi
+type ResolveEscalationRequest struct {
+	Note string `json:"note" example:"Dokumen perpanjangan sudah diterima, menunggu upload"`
+}
+
+// ListEscalations godoc
+// @Summary      List notification escalations
+// @Description  Mengambil daftar rantai eskalasi dokumen/masa jabatan expired beserta riwayat level yang sudah dijalankan
+// @Tags         Notification Settings
+// @Accept       json
+// @Produce      json
+// @Param        status         query     string  false  "Filter status (open, resolved)"
+// @Param        resource_type  query     string  false  "Filter resource (document, director)"
+// @Param        page           query     int     false  "Page number (default: 1)"
+// @Param        page_size      query     int     false  "Page size (default: 20)"
+// @Success      200            {object}  map[string]interface{}
+// @Failure      401            {object}  domain.ErrorResponse
+// @Failure      403            {object}  domain.ErrorResponse
+// @Router       /api/v1/notification-escalations [get]
+// @Security     BearerAuth
+// @note         Catatan Teknis:
+// @note         1. Authorization: Superadmin/administrator melihat semua eskalasi, admin hanya eskalasi company sendiri dan turunannya
+// @note         2. Level: Level 1 = admin company asal, level 2 = admin parent company, dst sampai holding
+func (h *NotificationEscalationHandler) ListEscalations(c *fiber.Ctx) error {
+	filter := repository.NotificationEscalationFilter{
+		Status:       c.Query("status"),
+		ResourceType: c.Query("resource_type"),
+	}
+
+	roleName, _ := c.Locals("roleName").(string)
+	if !utils.IsSuperAdminLike(roleName) {
+		userCompanyID := localCompanyID(c)
+		if strings.ToLower(roleName) != "admin" || userCompanyID == "" {
+			return c.Status(fiber.StatusForbidden).JSON(domain.ErrorResponse{
+				Error:   "forbidden",
+				Message: "Only administrators can view notification escalations",
+			})
+		}
+		descendants, err := h.companyUC.GetCompanyDescendants(userCompanyID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+				Error:   "internal_error",
+				Message: "Failed to resolve company scope: " + err.Error(),
+			})
+		}
+		filter.CompanyIDs = []string{userCompanyID}
+		for _, d := range descendants {
+			filter.CompanyIDs = append(filter.CompanyIDs, d.ID)
+		}
+	}
+
+	page := 1
+	if parsed, err := strconv.Atoi(c.Query("page")); err == nil && parsed > 0 {
+		page = parsed
+	}
+	pageSize := 20
+	if parsed, err := strconv.Atoi(c.Query("page_size")); err == nil && parsed > 0 && parsed <= 100 {
+		pageSize = parsed
+	}
+	filter.Limit = pageSize
+	filter.Offset = (page - 1) * pageSize
+
+	escalations, total, err := h.escalationUC.ListEscalations(filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to fetch notification escalations: " + err.Error(),
+		})
+	}
+
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+	return c.JSON(fiber.Map{
+		"data":        escalations,
+		"total":       total,
+		"page":        page,
+		"page_size":   pageSize,
+		"total_pages": totalPages,
+	})
+}
+
+// GetEscalation godoc
+// @Summary      Get notification escalation
+// @Description  Mengambil detail rantai eskalasi beserta setiap level (company target dan admin penerima)
+// @Tags         Notification Settings
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Escalation ID"
+// @Success      200  {object}  domain.NotificationEscalationModel
+// @Failure      403  {object}  domain.ErrorResponse
+// @Failure      404  {object}  domain.ErrorResponse
+// @Router       /api/v1/notification-escalations/{id} [get]
+// @Security     BearerAuth
+func (h *NotificationEscalationHandler) GetEscalation(c *fiber.Ctx) error {
+	escalation, status, errResp := h.loadAccessibleEscalation(c, c.Params("id"))
+	if errResp != nil {
+		return c.Status(status).JSON(errResp)
+	}
+	return c.JSON(escalation)
+}
+
+// ResolveEscalation godoc
+// @Summary      Resolve notification escalation
+// @Description  Menutup rantai eskalasi sehingga tidak ada level berikutnya yang dikirim
+// @Tags         Notification Settings
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                    true   "Escalation ID"
+// @Param        payload  body      ResolveEscalationRequest  false  "Catatan penyelesaian"
+// @Success      200      {object}  domain.NotificationEscalationModel
+// @Failure      400      {object}  domain.ErrorResponse
+// @Failure      403      {object}  domain.ErrorResponse
+// @Failure      404      {object}  domain.ErrorResponse
+// @Router       /api/v1/notification-escalations/{id}/resolve [post]
+// @Security     BearerAuth
+// @note         Catatan Teknis:
+// @note         1. Otomatis: Rantai juga ditutup otomatis saat dokumen diperbarui dengan tanggal expired baru, dokumen dihapus, atau masa jabatan diperpanjang
+func (h *NotificationEscalationHandler) ResolveEscalation(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, status, errResp := h.loadAccessibleEscalation(c, id); errResp != nil {
+		return c.Status(status).JSON(errResp)
+	}
+
+	var req ResolveEscalationRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+				Error:   "invalid_request",
+				Message: "Invalid request body: " + err.Error(),
+			})
+		}
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	username, _ := c.Locals("username").(string)
+
+	escalation, err := h.escalationUC.Resolve(id, userID, strings.TrimSpace(req.Note))
+	if err != nil {
+		if errors.Is(err, usecase.ErrEscalationAlreadyResolved) {
+			return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+				Error:   "already_resolved",
+				Message: "Escalation is already resolved",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to resolve escalation: " + err.Error(),
+		})
+	}
+
+	audit.LogAction(userID, username, audit.ActionResolveEscalation, audit.ResourceNotification, escalation.ID, getClientIP(c), c.Get("User-Agent"), audit.StatusSuccess, map[string]interface{}{
+		"resource_type": escalation.ResourceType,
+		"resource_id":   escalation.ResourceID,
+		"level":         escalation.CurrentLevel,
+		"note":          escalation.ResolutionNote,
+	})
+
+	return c.JSON(escalation)
+}
+
+// loadAccessibleEscalation mengambil eskalasi dan memastikan requester boleh mengaksesnya
+func (h *NotificationEscalationHandler) loadAccessibleEscalation(c *fiber.Ctx, id string) (*domain.NotificationEscalationModel, int, *domain.ErrorResponse) {
+	escalation, err := h.escalationUC.GetEscalation(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.StatusNotFound, &domain.ErrorResponse{Error: "not_found", Message: "Escalation not found"}
+		}
+		return nil, fiber.StatusInternalServerError, &domain.ErrorResponse{Error: "internal_error", Message: "Failed to get escalation: " + err.Error()}
+	}
+
+	roleName, _ := c.Locals("roleName").(string)
+	if utils.IsSuperAdminLike(roleName) {
+		return escalation, fiber.StatusOK, nil
+	}
+
+	userCompanyID := localCompanyID(c)
+	if strings.ToLower(roleName) == "admin" && userCompanyID != "" && escalation.CompanyID != nil {
+		if hasAccess, err := h.companyUC.ValidateCompanyAccess(userCompanyID, *escalation.CompanyID); err == nil && hasAccess {
+			return escalation, fiber.StatusOK, nil
+		}
+	}
+	return nil, fiber.StatusForbidden, &domain.ErrorResponse{Error: "forbidden", Message: "You don't have access to this escalation"}
+}
+
+// localCompanyID membaca company ID user dari context JWT (bisa *string atau string)
+func localCompanyID(c *fiber.Ctx) string {
+	if companyIDPtr, ok := c.Locals("companyID").(*string); ok && companyIDPtr != nil {
+		return *companyIDPtr
+	}
+	if companyIDStr, ok := c.Locals("companyID").(string); ok {
+		return companyIDStr
+	}
+	return ""
+}
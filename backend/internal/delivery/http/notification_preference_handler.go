@@ -0,0 +1,241 @@
+package http
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/audit"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/usecase"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/utils"
+)
+
+// NotificationPreferenceHandler handles notification preference HTTP requests (override user & default company)
+type NotificationPreferenceHandler struct {
+	prefUC    usecase.NotificationPreferenceUseCase
+	companyUC usecase.CompanyUseCase
+}
+
+// NewNotificationPreferenceHandler creates a new notification preference handler
+func NewNotificationPreferenceHandler(prefUC usecase.NotificationPreferenceUseCase, companyUC usecase.CompanyUseCase) *NotificationPreferenceHandler {
+	return &NotificationPreferenceHandler{
+		prefUC:    prefUC,
+		companyUC: companyUC,
+	}
+}
+
+// GetMyPreferences godoc
+// @Summary      Get notification preferences
+// @Description  Mengambil override preferensi notifikasi milik user (field null = mewarisi default company/sistem) beserta preferensi efektif yang dipakai scheduler
+// @Tags         Notification Settings
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}  "Response berisi preferences (override user) dan effective (hasil resolusi beserta sumber tiap field)"
+// @Failure      401  {object}  domain.ErrorResponse
+// @Failure      500  {object}  domain.ErrorResponse
+// @Router       /api/v1/notification-preferences [get]
+// @Security     BearerAuth
+// @note         Catatan Teknis:
+// @note         1. Prioritas: override user -> default company terdekat (company user, lalu parent ke atas) -> default sistem
+// @note         2. Reminder Offsets: Reminder dikirim sekali per tahap, contoh 60/30/7/0 hari sebelum expired
+// @note         3. Quiet Hours: Reminder yang jatuh di quiet hours ditunda ke run scheduler berikutnya
+func (h *NotificationPreferenceHandler) GetMyPreferences(c *fiber.Ctx) error {
+	userIDVal := c.Locals("userID")
+	if userIDVal == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(domain.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "Authentication required",
+		})
+	}
+	userID := userIDVal.(string)
+
+	prefs, err := h.prefUC.GetUserPreferences(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to get notification preferences: " + err.Error(),
+		})
+	}
+	effective, err := h.prefUC.ResolveForUser(userID, systemThresholdDays())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to resolve notification preferences: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"preferences": prefs,
+		"effective":   effective,
+	})
+}
+
+// UpdateMyPreferences godoc
+// @Summary      Update notification preferences
+// @Description  Mengubah override preferensi notifikasi milik user. Field yang tidak dikirim tidak berubah, gunakan reset_fields untuk kembali mewarisi default company
+// @Tags         Notification Settings
+// @Accept       json
+// @Produce      json
+// @Param        preferences  body      domain.NotificationPreferenceRequest  true  "Preferensi notifikasi (semua field opsional)"
+// @Success      200          {object}  map[string]interface{}  "Response berisi preferences (override user) dan effective"
+// @Failure      400          {object}  domain.ErrorResponse
+// @Failure      401          {object}  domain.ErrorResponse
+// @Router       /api/v1/notification-preferences [put]
+// @Security     BearerAuth
+// @note         Catatan Teknis:
+// @note         1. Validasi: threshold_days 1-365, reminder_offsets 0-365 (maksimal 10 nilai), quiet hours format HH:MM, timezone nama IANA
+// @note         2. Reset: reset_fields menerima in_app_enabled, threshold_days, reminder_offsets, type_preferences, quiet_hours, timezone
+func (h *NotificationPreferenceHandler) UpdateMyPreferences(c *fiber.Ctx) error {
+	userIDVal := c.Locals("userID")
+	if userIDVal == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(domain.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "Authentication required",
+		})
+	}
+	userID := userIDVal.(string)
+	username, _ := c.Locals("username").(string)
+
+	var req domain.NotificationPreferenceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	prefs, err := h.prefUC.UpdateUserPreferences(userID, &req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "update_failed",
+			Message: err.Error(),
+		})
+	}
+	effective, err := h.prefUC.ResolveForUser(userID, systemThresholdDays())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to resolve notification preferences: " + err.Error(),
+		})
+	}
+
+	audit.LogAction(userID, username, audit.ActionUpdateNotificationPrefs, audit.ResourceNotification, prefs.ID, getClientIP(c), c.Get("User-Agent"), audit.StatusSuccess, map[string]interface{}{
+		"scope_type": usecase.PreferenceScopeUser,
+		"scope_id":   userID,
+	})
+
+	return c.JSON(fiber.Map{
+		"preferences": prefs,
+		"effective":   effective,
+	})
+}
+
+// GetCompanyPreferences godoc
+// @Summary      Get company notification defaults
+// @Description  Mengambil default preferensi notifikasi sebuah company. Default ini diwarisi oleh user di company tersebut dan company turunannya (kecuali di-override)
+// @Tags         Notification Settings
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Company ID"
+// @Success      200  {object}  domain.NotificationPreferenceModel
+// @Failure      401  {object}  domain.ErrorResponse
+// @Failure      403  {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/{id}/notification-preferences [get]
+// @Security     BearerAuth
+// @note         Catatan Teknis:
+// @note         1. Authorization: Superadmin/administrator untuk semua company, admin untuk company sendiri dan turunannya
+func (h *NotificationPreferenceHandler) GetCompanyPreferences(c *fiber.Ctx) error {
+	companyID := c.Params("id")
+	if !h.canManageCompany(c, companyID) {
+		return c.Status(fiber.StatusForbidden).JSON(domain.ErrorResponse{
+			Error:   "forbidden",
+			Message: "You don't have access to manage notification preferences for this company",
+		})
+	}
+
+	prefs, err := h.prefUC.GetCompanyPreferences(companyID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to get company notification preferences: " + err.Error(),
+		})
+	}
+	return c.JSON(prefs)
+}
+
+// UpdateCompanyPreferences godoc
+// @Summary      Update company notification defaults
+// @Description  Mengubah default preferensi notifikasi sebuah company (diwarisi user di company tersebut dan company turunannya)
+// @Tags         Notification Settings
+// @Accept       json
+// @Produce      json
+// @Param        id           path      string                                true  "Company ID"
+// @Param        preferences  body      domain.NotificationPreferenceRequest  true  "Default preferensi notifikasi (semua field opsional)"
+// @Success      200          {object}  domain.NotificationPreferenceModel
+// @Failure      400          {object}  domain.ErrorResponse
+// @Failure      401          {object}  domain.ErrorResponse
+// @Failure      403          {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/{id}/notification-preferences [put]
+// @Security     BearerAuth
+// @note         Catatan Teknis:
+// @note         1. Authorization: Superadmin/administrator untuk semua company, admin untuk company sendiri dan turunannya
+// @note         2. Pewarisan: Company terdekat ke user menimpa default company di atasnya per field
+func (h *NotificationPreferenceHandler) UpdateCompanyPreferences(c *fiber.Ctx) error {
+	companyID := c.Params("id")
+	if !h.canManageCompany(c, companyID) {
+		return c.Status(fiber.StatusForbidden).JSON(domain.ErrorResponse{
+			Error:   "forbidden",
+			Message: "You don't have access to manage notification preferences for this company",
+		})
+	}
+	userID, _ := c.Locals("userID").(string)
+	username, _ := c.Locals("username").(string)
+
+	var req domain.NotificationPreferenceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	prefs, err := h.prefUC.UpdateCompanyPreferences(companyID, &req, userID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "update_failed",
+			Message: err.Error(),
+		})
+	}
+
+	audit.LogAction(userID, username, audit.ActionUpdateNotificationPrefs, audit.ResourceNotification, prefs.ID, getClientIP(c), c.Get("User-Agent"), audit.StatusSuccess, map[string]interface{}{
+		"scope_type": usecase.PreferenceScopeCompany,
+		"scope_id":   companyID,
+	})
+
+	return c.JSON(prefs)
+}
+
+// canManageCompany mengecek apakah requester boleh mengatur default notifikasi company
+func (h *NotificationPreferenceHandler) canManageCompany(c *fiber.Ctx, companyID string) bool {
+	roleName, _ := c.Locals("roleName").(string)
+	if utils.IsSuperAdminLike(roleName) {
+		return true
+	}
+
+	userCompanyID := localCompanyID(c)
+	if strings.ToLower(roleName) == "admin" && userCompanyID != "" {
+		hasAccess, err := h.companyUC.ValidateCompanyAccess(userCompanyID, companyID)
+		return err == nil && hasAccess
+	}
+	return false
+}
+
+// systemThresholdDays membaca default threshold sistem yang sama dengan scheduler
+func systemThresholdDays() int {
+	if parsed, err := strconv.Atoi(os.Getenv("NOTIFICATION_EXPIRY_THRESHOLD_DAYS")); err == nil && parsed > 0 {
+		return parsed
+	}
+	return 14
+}
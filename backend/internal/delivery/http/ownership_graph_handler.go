@@ -0,0 +1,122 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/usecase"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/utils"
+)
+
+// OwnershipGraphHandler handles struktur kepemilikan grup (graph, siklus, kepemilikan efektif)
+type OwnershipGraphHandler struct {
+	ownershipUC usecase.OwnershipGraphUseCase
+	companyUC   usecase.CompanyUseCase
+}
+
+// NewOwnershipGraphHandler creates a new ownership graph handler
+func NewOwnershipGraphHandler(ownershipUC usecase.OwnershipGraphUseCase, companyUC usecase.CompanyUseCase) *OwnershipGraphHandler {
+	return &OwnershipGraphHandler{
+		ownershipUC: ownershipUC,
+		companyUC:   companyUC,
+	}
+}
+
+// GetOwnershipGraph godoc
+// @Summary      Get group ownership graph
+// @Description  Graph kepemilikan grup: node company (dan pemegang saham eksternal), edge kepemilikan langsung, siklus, dan company dengan total kepemilikan tidak 100%. Format JSON atau Graphviz DOT.
+// @Tags         Company Management
+// @Produce      json
+// @Produce      text/vnd.graphviz
+// @Security     BearerAuth
+// @Param        root_company_id   query     string  false  "Root graph (company dan turunannya). Default: semua company untuk superadmin, company user untuk role lain"
+// @Param        include_external  query     bool    false  "Sertakan pemegang saham individu/eksternal (default: true)"
+// @Param        format            query     string  false  "json (default) atau dot"
+// @Success      200               {object}  domain.OwnershipGraphResponse
+// @Failure      400               {object}  domain.ErrorResponse
+// @Failure      403               {object}  domain.ErrorResponse
+// @Failure      404               {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/ownership-graph [get]
+// @note         Catatan Teknis:
+// @note         1. Edge from -> to berarti from memiliki percent % saham to
+// @note         2. Jika root diisi, setiap node company berisi effective_ownership: kepemilikan look-through root (perkalian persentase sepanjang semua rantai)
+// @note         3. Company pemegang saham di luar scope root tetap ditampilkan dengan in_scope=false
+func (h *OwnershipGraphHandler) GetOwnershipGraph(c *fiber.Ctx) error {
+	rootCompanyID := c.Query("root_company_id")
+	roleName, _ := c.Locals("roleName").(string)
+	if rootCompanyID == "" && !utils.IsSuperAdminLike(roleName) {
+		rootCompanyID = localCompanyID(c)
+	}
+	if (rootCompanyID != "" || !utils.IsSuperAdminLike(roleName)) && !canAccessCompany(c, h.companyUC, rootCompanyID, false) {
+		return forbiddenCompany(c)
+	}
+
+	includeExternal := true
+	if value := c.Query("include_external"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+				Error:   "invalid_request",
+				Message: "include_external must be true or false",
+			})
+		}
+		includeExternal = parsed
+	}
+
+	format := c.Query("format", "json")
+	if format != "json" && format != "dot" {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "format must be json or dot",
+		})
+	}
+
+	graph, err := h.ownershipUC.GetOwnershipGraph(rootCompanyID, includeExternal)
+	if err != nil {
+		return notFoundOrError(c, err, "Company not found")
+	}
+
+	if format == "dot" {
+		c.Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+		return c.SendString(usecase.RenderOwnershipGraphDOT(graph))
+	}
+	return c.JSON(graph)
+}
+
+// GetEffectiveOwnership godoc
+// @Summary      Get effective ownership between two companies
+// @Description  Kepemilikan efektif (look-through) company terhadap company lain: jumlah perkalian persentase di setiap rantai kepemilikan
+// @Tags         Company Management
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id                 path      string  true  "Company pemilik"
+// @Param        target_company_id  query     string  true  "Company yang dimiliki"
+// @Success      200                {object}  domain.EffectiveOwnershipResponse
+// @Failure      400                {object}  domain.ErrorResponse
+// @Failure      403                {object}  domain.ErrorResponse
+// @Failure      404                {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/{id}/effective-ownership [get]
+// @note         Catatan Teknis:
+// @note         1. Contoh: holding memiliki 60% A dan A memiliki 50% B, maka kepemilikan efektif holding atas B adalah 30%
+// @note         2. Rantai yang membentuk siklus dihentikan sehingga setiap company hanya dilewati sekali per rantai
+// @note         3. Maksimal 50 rantai dengan persentase terbesar dikembalikan (paths_truncated=true jika dipotong)
+func (h *OwnershipGraphHandler) GetEffectiveOwnership(c *fiber.Ctx) error {
+	fromCompanyID := c.Params("id")
+	toCompanyID := c.Query("target_company_id")
+	if toCompanyID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "target_company_id is required",
+		})
+	}
+	if !canAccessCompany(c, h.companyUC, fromCompanyID, false) || !canAccessCompany(c, h.companyUC, toCompanyID, false) {
+		return forbiddenCompany(c)
+	}
+
+	ownership, err := h.ownershipUC.GetEffectiveOwnership(fromCompanyID, toCompanyID)
+	if err != nil {
+		return notFoundOrError(c, err, "Company not found")
+	}
+	return c.JSON(ownership)
+}
@@ -176,6 +176,8 @@ func (h *ReportHandler) GetReport(c *fiber.Ctx) error {
 // @Failure      400         {object}  domain.ErrorResponse
 // @Failure      401         {object}  domain.ErrorResponse
 // @Router       /api/v1/reports [get]
+// @note         Catatan Teknis:
+// @note         1. Endpoint compatibility: data diambil dari realisasi bulanan financial_reports (Opex = operating_expenses, NPAT = net_profit)
 func (h *ReportHandler) GetAllReports(c *fiber.Ctx) error {
 	// Get user info
 	companyID := c.Locals("companyID")
@@ -408,8 +410,8 @@ func (h *ReportHandler) UpdateReport(c *fiber.Ctx) error {
 		})
 	}
 
-	// Audit log
-	audit.LogAction(userID, username, "update_report", audit.ResourceReport, report.ID, getClientIP(c), c.Get("User-Agent"), audit.StatusSuccess, map[string]interface{}{
+	// Audit log dengan diff per field
+	audit.LogChanges(userID, username, "update_report", audit.ResourceReport, report.ID, getClientIP(c), c.Get("User-Agent"), audit.DiffModels(existingReport, report), map[string]interface{}{
 		"company_id": report.CompanyID,
 		"period":     report.Period,
 	})
@@ -431,6 +433,8 @@ func (h *ReportHandler) UpdateReport(c *fiber.Ctx) error {
 // @Failure      403  {object}  domain.ErrorResponse
 // @Failure      404  {object}  domain.ErrorResponse
 // @Router       /api/v1/reports/{id} [delete]
+// @note         Catatan Teknis:
+// @note         1. Report adalah realisasi financial report yang sama, sehingga data neraca/arus kas/rasio periode tersebut ikut terhapus
 func (h *ReportHandler) DeleteReport(c *fiber.Ctx) error {
 	id := c.Params("id")
 
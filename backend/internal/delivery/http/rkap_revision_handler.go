@@ -0,0 +1,208 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/usecase"
+)
+
+// GetRKAPVersions godoc
+// @Summary      List RKAP versions
+// @Description  Daftar versi RKAP satu tahun: RKAP awal (versi 0) dan seluruh revisi (RKAP Perubahan) beserta tanggal berlaku dan status approval
+// @Tags         Financial Reports
+// @Produce      json
+// @Security     BearerAuth
+// @Param        company_id  path      string  true  "Company ID"
+// @Param        year        path      string  true  "Tahun RKAP (YYYY)"
+// @Success      200         {array}   domain.FinancialReportModel
+// @Failure      403         {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/{company_id}/rkap/{year}/versions [get]
+// @note         Catatan Teknis:
+// @note         1. RKAP awal selalu dianggap approved; revisi berstatus draft, approved, atau rejected
+// @note         2. Comparison dan export performa default memakai versi approved dengan nomor tertinggi
+func (h *FinancialReportHandler) GetRKAPVersions(c *fiber.Ctx) error {
+	companyID := c.Params("company_id")
+	if !canAccessCompany(c, h.companyUseCase, companyID, false) {
+		return forbiddenCompany(c)
+	}
+
+	versions, err := h.financialReportUseCase.GetRKAPVersions(companyID, c.Params("year"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(domain.ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+	}
+	return c.JSON(versions)
+}
+
+// CreateRKAPRevision godoc
+// @Summary      Create RKAP revision (RKAP Perubahan)
+// @Description  Membuat revisi RKAP berstatus draft. Nilai yang tidak diisi disalin dari versi RKAP approved terakhir
+// @Tags         Financial Reports
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        company_id  path      string                            true  "Company ID"
+// @Param        year        path      string                            true  "Tahun RKAP (YYYY)"
+// @Param        revision    body      domain.CreateRKAPRevisionRequest  true  "Tanggal berlaku, alasan, dan nilai revisi"
+// @Success      201         {object}  domain.FinancialReportModel
+// @Failure      400         {object}  domain.ErrorResponse
+// @Failure      403         {object}  domain.ErrorResponse
+// @Failure      404         {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/{company_id}/rkap/{year}/revisions [post]
+// @note         Catatan Teknis:
+// @note         1. Hanya boleh ada satu revisi draft per tahun; draft bisa diubah lewat PUT /financial-reports/{id}
+// @note         2. effective_date (YYYY-MM-DD) harus berada di tahun RKAP
+func (h *FinancialReportHandler) CreateRKAPRevision(c *fiber.Ctx) error {
+	companyID := c.Params("company_id")
+	if !canAccessCompany(c, h.companyUseCase, companyID, false) {
+		return forbiddenCompany(c)
+	}
+
+	var req domain.CreateRKAPRevisionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	username, _ := c.Locals("username").(string)
+	revision, err := h.financialReportUseCase.CreateRKAPRevision(companyID, c.Params("year"), &req, userID, username, getClientIP(c), c.Get("User-Agent"))
+	if err != nil {
+		return rkapRevisionError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(revision)
+}
+
+// ApproveRKAPRevision godoc
+// @Summary      Approve RKAP revision
+// @Description  Menyetujui revisi RKAP draft sehingga menjadi RKAP pembanding default
+// @Tags         Financial Reports
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Financial Report ID revisi RKAP"
+// @Success      200  {object}  domain.FinancialReportModel
+// @Failure      403  {object}  domain.ErrorResponse
+// @Failure      404  {object}  domain.ErrorResponse
+// @Failure      409  {object}  domain.ErrorResponse
+// @Router       /api/v1/rkap-revisions/{id}/approve [post]
+// @note         Catatan Teknis:
+// @note         1. Authorization: superadmin/administrator atau admin company (dan ancestor-nya)
+func (h *FinancialReportHandler) ApproveRKAPRevision(c *fiber.Ctx) error {
+	return h.reviewRKAPRevision(c, h.financialReportUseCase.ApproveRKAPRevision)
+}
+
+// RejectRKAPRevision godoc
+// @Summary      Reject RKAP revision
+// @Description  Menolak revisi RKAP draft. Revisi yang ditolak tetap tersimpan sebagai riwayat
+// @Tags         Financial Reports
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Financial Report ID revisi RKAP"
+// @Success      200  {object}  domain.FinancialReportModel
+// @Failure      403  {object}  domain.ErrorResponse
+// @Failure      404  {object}  domain.ErrorResponse
+// @Failure      409  {object}  domain.ErrorResponse
+// @Router       /api/v1/rkap-revisions/{id}/reject [post]
+func (h *FinancialReportHandler) RejectRKAPRevision(c *fiber.Ctx) error {
+	return h.reviewRKAPRevision(c, h.financialReportUseCase.RejectRKAPRevision)
+}
+
+func (h *FinancialReportHandler) reviewRKAPRevision(c *fiber.Ctx, review func(id, userID, username, ipAddress, userAgent string) (*domain.FinancialReportModel, error)) error {
+	id := c.Params("id")
+	report, err := h.financialReportUseCase.GetFinancialReportByID(id)
+	if err != nil {
+		return rkapRevisionError(c, usecase.ErrRKAPVersionNotFound)
+	}
+	if !canAccessCompany(c, h.companyUseCase, report.CompanyID, true) {
+		return forbiddenCompany(c)
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	username, _ := c.Locals("username").(string)
+	revision, err := review(id, userID, username, getClientIP(c), c.Get("User-Agent"))
+	if err != nil {
+		return rkapRevisionError(c, err)
+	}
+	return c.JSON(revision)
+}
+
+// DiffRKAPVersions godoc
+// @Summary      Diff RKAP versions
+// @Description  Perbandingan baris per baris dua versi RKAP (nilai awal, nilai baru, selisih, dan persentase perubahan)
+// @Tags         Financial Reports
+// @Produce      json
+// @Security     BearerAuth
+// @Param        company_id  path      string  true   "Company ID"
+// @Param        year        path      string  true   "Tahun RKAP (YYYY)"
+// @Param        from        query     int     false  "Versi awal (default: versi sebelum to)"
+// @Param        to          query     int     false  "Versi tujuan (default: versi terakhir)"
+// @Success      200         {object}  domain.RKAPRevisionDiffResponse
+// @Failure      400         {object}  domain.ErrorResponse
+// @Failure      403         {object}  domain.ErrorResponse
+// @Failure      404         {object}  domain.ErrorResponse
+// @Router       /api/v1/companies/{company_id}/rkap/{year}/diff [get]
+// @note         Catatan Teknis:
+// @note         1. Baris mengikuti taksonomi financial (neraca, laba rugi, arus kas, rasio); changed=false untuk baris yang tidak berubah
+func (h *FinancialReportHandler) DiffRKAPVersions(c *fiber.Ctx) error {
+	companyID := c.Params("company_id")
+	if !canAccessCompany(c, h.companyUseCase, companyID, false) {
+		return forbiddenCompany(c)
+	}
+
+	from, errResp := parseRKAPVersionQuery(c, "from")
+	if errResp != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errResp)
+	}
+	to, errResp := parseRKAPVersionQuery(c, "to")
+	if errResp != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errResp)
+	}
+
+	diff, err := h.financialReportUseCase.DiffRKAPVersions(companyID, c.Params("year"), from, to)
+	if err != nil {
+		return rkapRevisionError(c, err)
+	}
+	return c.JSON(diff)
+}
+
+// parseRKAPVersionQuery membaca nomor versi RKAP dari query (nil jika kosong)
+func parseRKAPVersionQuery(c *fiber.Ctx, key string) (*int, *domain.ErrorResponse) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 0 {
+		return nil, &domain.ErrorResponse{
+			Error:   "invalid_request",
+			Message: key + " must be a non-negative integer",
+		}
+	}
+	return &version, nil
+}
+
+func rkapRevisionError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrRKAPVersionNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(domain.ErrorResponse{
+			Error:   "not_found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrRKAPRevisionNotDraft):
+		return c.Status(fiber.StatusConflict).JSON(domain.ErrorResponse{
+			Error:   "conflict",
+			Message: err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
+		Error:   "rkap_revision_failed",
+		Message: err.Error(),
+	})
+}
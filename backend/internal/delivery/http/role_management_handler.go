@@ -132,6 +132,8 @@ func (h *RoleManagementHandler) UpdateRole(c *fiber.Ctx) error {
 		})
 	}
 
+	oldRole, _ := h.roleUseCase.GetRoleByID(id)
+
 	role, err := h.roleUseCase.UpdateRole(id, req.Name, req.Description, req.Level)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
@@ -142,7 +144,7 @@ func (h *RoleManagementHandler) UpdateRole(c *fiber.Ctx) error {
 
 	userID := c.Locals("userID").(string)
 	username := c.Locals("username").(string)
-	audit.LogAction(userID, username, audit.ActionUpdate, audit.ResourceRole, id, getClientIP(c), c.Get("User-Agent"), audit.StatusSuccess, nil)
+	audit.LogChanges(userID, username, audit.ActionUpdate, audit.ResourceRole, id, getClientIP(c), c.Get("User-Agent"), audit.DiffModels(oldRole, role), nil)
 
 	return c.Status(fiber.StatusOK).JSON(role)
 }
@@ -351,6 +351,8 @@ func (h *UserManagementHandler) UpdateUser(c *fiber.Ctx) error {
 		}
 	}
 
+	oldUser, _ := h.userUseCase.GetUserByID(id)
+
 	user, err := h.userUseCase.UpdateUser(id, req.Username, req.Email, req.CompanyID, req.RoleID)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(domain.ErrorResponse{
@@ -361,7 +363,7 @@ func (h *UserManagementHandler) UpdateUser(c *fiber.Ctx) error {
 
 	userID := c.Locals("userID").(string)
 	username := c.Locals("username").(string)
-	audit.LogAction(userID, username, audit.ActionUpdateUser, audit.ResourceUser, id, getClientIP(c), c.Get("User-Agent"), audit.StatusSuccess, nil)
+	audit.LogChanges(userID, username, audit.ActionUpdateUser, audit.ResourceUser, id, getClientIP(c), c.Get("User-Agent"), audit.DiffModels(oldUser, user), nil)
 
 	return c.Status(fiber.StatusOK).JSON(user)
 }
@@ -511,7 +513,7 @@ func (h *UserManagementHandler) ToggleUserStatus(c *fiber.Ctx) error {
 	} else {
 		action = "deactivate_user"
 	}
-	audit.LogAction(userID, username, action, audit.ResourceUser, id, getClientIP(c), c.Get("User-Agent"), audit.StatusSuccess, map[string]interface{}{
+	audit.LogChanges(userID, username, action, audit.ResourceUser, id, getClientIP(c), c.Get("User-Agent"), audit.DiffModels(targetUser, user), map[string]interface{}{
 		"is_active": user.IsActive,
 	})
 
@@ -0,0 +1,14 @@
+package domain
+
+// CompanyHierarchyModel adalah closure table hierarki company: satu baris untuk setiap pasangan
+// ancestor-descendant (termasuk baris diri sendiri dengan depth 0). Dipelihara oleh CompanyRepository
+// (create dan update parent_id) di transaksi yang sama; bisa dibangun ulang dengan repository.RebuildCompanyHierarchy.
+type CompanyHierarchyModel struct {
+	AncestorID   string `gorm:"primaryKey;column:ancestor_id" json:"ancestor_id"`
+	DescendantID string `gorm:"primaryKey;column:descendant_id;index" json:"descendant_id"`
+	Depth        int    `gorm:"not null;index" json:"depth"` // 0 = diri sendiri, 1 = anak langsung, dst
+}
+
+func (CompanyHierarchyModel) TableName() string {
+	return "company_hierarchy"
+}
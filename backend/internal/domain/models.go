@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"gorm.io/datatypes"
+	"gorm.io/gorm"
 )
 
 // User merepresentasikan user dalam sistem (domain model)
@@ -132,6 +133,54 @@ func (UserActivityLog) TableName() string {
 	return "user_activity_logs"
 }
 
+// FieldHistoryItem merepresentasikan satu perubahan nilai pada sebuah field entity
+type FieldHistoryItem struct {
+	LogID     string      `json:"log_id"`
+	Action    string      `json:"action"`
+	UserID    string      `json:"user_id"`
+	Username  string      `json:"username"`
+	Old       interface{} `json:"old"`
+	New       interface{} `json:"new"`
+	ChangedAt time.Time   `json:"changed_at"`
+}
+
+// EntityHistoryEntry merepresentasikan satu aksi (create/update/delete) pada sebuah entity beserta perubahannya
+type EntityHistoryEntry struct {
+	LogID     string                 `json:"log_id"`
+	Action    string                 `json:"action"`
+	UserID    string                 `json:"user_id"`
+	Username  string                 `json:"username"`
+	Status    string                 `json:"status"`
+	Changes   map[string]interface{} `json:"changes"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// EntityHistoryResponse berisi riwayat lengkap sebuah entity (timeline aksi dan riwayat per field)
+type EntityHistoryResponse struct {
+	Resource   string                        `json:"resource"`
+	ResourceID string                        `json:"resource_id"`
+	Entries    []EntityHistoryEntry          `json:"entries"`
+	Fields     map[string][]FieldHistoryItem `json:"fields"` // Key: nama field, urut dari perubahan paling lama
+}
+
+// AuditSinkDeadLetter menyimpan audit event yang gagal dikirim ke sink eksternal (SIEM) setelah semua retry habis
+type AuditSinkDeadLetter struct {
+	ID          string     `gorm:"primaryKey" json:"id"`
+	Sink        string     `gorm:"index;not null" json:"sink"` // syslog, webhook, file
+	EventID     string     `gorm:"index" json:"event_id"`
+	Payload     string     `gorm:"type:text;not null" json:"payload"` // Audit event dalam format JSON
+	LastError   string     `gorm:"type:text" json:"last_error"`
+	Attempts    int        `json:"attempts"`
+	ReplayedAt  *time.Time `gorm:"index" json:"replayed_at,omitempty"` // Diisi jika sudah berhasil dikirim ulang
+	CreatedAt   time.Time  `gorm:"index" json:"created_at"`
+	EventTimeAt time.Time  `json:"event_time_at"`
+}
+
+// TableName menentukan nama tabel untuk AuditSinkDeadLetter
+func (AuditSinkDeadLetter) TableName() string {
+	return "audit_sink_dead_letters"
+}
+
 // Document merepresentasikan sebuah document (domain model)
 type Document struct {
 	ID          string `json:"id"`
@@ -152,6 +201,11 @@ type DocumentFolderModel struct {
 	CreatedAt time.Time             `json:"created_at"`
 	UpdatedAt time.Time             `json:"updated_at"`
 	Children  []DocumentFolderModel `gorm:"foreignKey:ParentID" json:"children,omitempty"`
+
+	// Recycle bin (soft delete): TrashRootID = ID folder/dokumen yang dihapus user, sama untuk seluruh isi yang ikut terhapus
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty" swaggertype:"string"`
+	DeletedBy   string         `json:"deleted_by,omitempty"`
+	TrashRootID string         `gorm:"index" json:"trash_root_id,omitempty"`
 }
 
 func (DocumentFolderModel) TableName() string {
@@ -175,6 +229,11 @@ type DocumentModel struct {
 	CreatedAt  time.Time      `json:"created_at"`
 	UpdatedAt  time.Time      `json:"updated_at"`
 
+	// Recycle bin (soft delete), lihat DocumentFolderModel
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty" swaggertype:"string"`
+	DeletedBy   string         `json:"deleted_by,omitempty"`
+	TrashRootID string         `gorm:"index" json:"trash_root_id,omitempty"`
+
 	Folder *DocumentFolderModel `gorm:"foreignKey:FolderID" json:"folder,omitempty"`
 }
 
@@ -182,6 +241,28 @@ func (DocumentModel) TableName() string {
 	return "documents"
 }
 
+// Jenis item di recycle bin dokumen
+const (
+	TrashItemFolder   = "folder"
+	TrashItemDocument = "document"
+)
+
+// DocumentTrashItem merepresentasikan satu item recycle bin: folder atau dokumen yang dihapus user
+// (isi folder yang ikut terhapus tidak ditampilkan terpisah, hanya dihitung)
+type DocumentTrashItem struct {
+	Type          string    `json:"type"` // folder atau document
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	CompanyID     *string   `json:"company_id"`
+	ParentID      *string   `json:"parent_id"` // Parent folder (untuk folder) atau folder dokumen
+	DeletedAt     time.Time `json:"deleted_at"`
+	DeletedBy     string    `json:"deleted_by"`
+	FolderCount   int64     `json:"folder_count"`   // Jumlah folder dalam item (termasuk folder itu sendiri)
+	DocumentCount int64     `json:"document_count"` // Jumlah dokumen dalam item
+	TotalSize     int64     `json:"total_size"`
+	PurgeAt       time.Time `json:"purge_at"` // Waktu item dihapus permanen otomatis
+}
+
 // NotificationModel merepresentasikan notifikasi in-app untuk user
 type NotificationModel struct {
 	ID           string     `gorm:"primaryKey" json:"id"`
@@ -211,7 +292,7 @@ type NotificationSettingsModel struct {
 	UserID              string    `gorm:"uniqueIndex;not null" json:"user_id"`
 	EmailEnabled        bool      `gorm:"default:true" json:"email_enabled"`
 	InAppEnabled        bool      `gorm:"default:true" json:"in_app_enabled"`
-	ExpiryThresholdDays int       `gorm:"default:14" json:"expiry_threshold_days"` // Jumlah hari sebelum expired untuk membuat notifikasi pertama kali (default: 14 hari)
+	ExpiryThresholdDays *int      `json:"expiry_threshold_days"` // Jumlah hari sebelum expired untuk membuat notifikasi pertama kali (nil = belum diatur user, ikut default company/sistem)
 	CreatedAt           time.Time `json:"created_at"`
 	UpdatedAt           time.Time `json:"updated_at"`
 }
@@ -220,6 +301,118 @@ func (NotificationSettingsModel) TableName() string {
 	return "notification_settings"
 }
 
+// NotificationPreferenceModel menyimpan preferensi notifikasi per scope: default company atau override per user.
+// Field bernilai nil berarti mewarisi level di atasnya (user -> company terdekat ke atas -> default sistem).
+type NotificationPreferenceModel struct {
+	ID              string         `gorm:"primaryKey" json:"id"`
+	ScopeType       string         `gorm:"uniqueIndex:idx_notification_preferences_scope;not null" json:"scope_type"` // company, user
+	ScopeID         string         `gorm:"uniqueIndex:idx_notification_preferences_scope;not null" json:"scope_id"`   // company_id atau user_id
+	InAppEnabled    *bool          `json:"in_app_enabled"`
+	ThresholdDays   *int           `json:"threshold_days"`                        // Dipakai sebagai satu-satunya offset jika reminder_offsets kosong
+	ReminderOffsets *string        `json:"reminder_offsets"`                      // Hari sebelum expired dipisah koma, contoh: "60,30,7,0"
+	TypePreferences datatypes.JSON `json:"type_preferences" swaggertype:"object"` // Opt-in/out per tipe, contoh: {"director_term_expiry": false}
+	QuietHoursStart *string        `json:"quiet_hours_start"`                     // Format HH:MM, notifikasi ditunda selama quiet hours
+	QuietHoursEnd   *string        `json:"quiet_hours_end"`                       // Format HH:MM (boleh melewati tengah malam, contoh 22:00-06:00)
+	Timezone        *string        `json:"timezone"`                              // Nama IANA, contoh: Asia/Jakarta
+	UpdatedBy       string         `json:"updated_by"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+}
+
+func (NotificationPreferenceModel) TableName() string {
+	return "notification_preferences"
+}
+
+// NotificationPreferenceRequest adalah payload update preferensi notifikasi (semua field opsional)
+type NotificationPreferenceRequest struct {
+	InAppEnabled    *bool           `json:"in_app_enabled"`
+	ThresholdDays   *int            `json:"threshold_days"`
+	ReminderOffsets []int           `json:"reminder_offsets"`
+	TypePreferences map[string]bool `json:"type_preferences"`
+	QuietHoursStart *string         `json:"quiet_hours_start"`
+	QuietHoursEnd   *string         `json:"quiet_hours_end"`
+	Timezone        *string         `json:"timezone"`
+	ResetFields     []string        `json:"reset_fields"` // Field yang dikembalikan ke nilai warisan, contoh: ["reminder_offsets", "quiet_hours"]
+}
+
+// EffectiveNotificationPreferences adalah hasil resolusi preferensi yang benar-benar dipakai scheduler untuk seorang user
+type EffectiveNotificationPreferences struct {
+	UserID          string            `json:"user_id"`
+	InAppEnabled    bool              `json:"in_app_enabled"`
+	ThresholdDays   int               `json:"threshold_days"`
+	ReminderOffsets []int             `json:"reminder_offsets"` // Urut menurun, contoh: [60, 30, 7, 0]
+	TypeEnabled     map[string]bool   `json:"type_enabled"`
+	QuietHoursStart string            `json:"quiet_hours_start,omitempty"`
+	QuietHoursEnd   string            `json:"quiet_hours_end,omitempty"`
+	Timezone        string            `json:"timezone"`
+	Sources         map[string]string `json:"sources"` // Asal nilai per field: user, company:<id>, system
+}
+
+// NotificationReminderLogModel mencatat reminder yang sudah dikirim, agar setiap tahap (offset) hanya dikirim sekali per user
+type NotificationReminderLogModel struct {
+	ID               string    `gorm:"primaryKey" json:"id"`
+	UserID           string    `gorm:"uniqueIndex:idx_notification_reminder_logs_unique;not null" json:"user_id"`
+	ResourceType     string    `gorm:"uniqueIndex:idx_notification_reminder_logs_unique;not null" json:"resource_type"` // document, director
+	ResourceID       string    `gorm:"uniqueIndex:idx_notification_reminder_logs_unique;not null" json:"resource_id"`
+	DueDate          string    `gorm:"uniqueIndex:idx_notification_reminder_logs_unique;size:10;not null" json:"due_date"` // Tanggal expired (YYYY-MM-DD), reminder ulang jika tanggal berubah
+	OffsetDays       int       `gorm:"uniqueIndex:idx_notification_reminder_logs_unique" json:"offset_days"`               // Tahap reminder, -1 = sudah expired
+	NotificationType string    `gorm:"index" json:"notification_type"`
+	NotificationID   string    `gorm:"index" json:"notification_id"`
+	SentAt           time.Time `gorm:"index" json:"sent_at"`
+}
+
+func (NotificationReminderLogModel) TableName() string {
+	return "notification_reminder_logs"
+}
+
+// Status rantai eskalasi notifikasi expired
+const (
+	EscalationStatusOpen     = "open"
+	EscalationStatusResolved = "resolved"
+)
+
+// NotificationEscalationModel melacak rantai eskalasi untuk satu dokumen/masa jabatan yang sudah expired
+// Level 1 = admin company asal resource, level 2 = admin parent company, dst mengikuti rantai ancestor
+type NotificationEscalationModel struct {
+	ID              string     `gorm:"primaryKey" json:"id"`
+	ResourceType    string     `gorm:"uniqueIndex:idx_notification_escalations_resource;not null" json:"resource_type"` // document, director
+	ResourceID      string     `gorm:"uniqueIndex:idx_notification_escalations_resource;not null" json:"resource_id"`
+	DueDate         string     `gorm:"uniqueIndex:idx_notification_escalations_resource;size:10;not null" json:"due_date"` // Tanggal expired (YYYY-MM-DD)
+	ResourceName    string     `json:"resource_name"`
+	CompanyID       *string    `gorm:"index" json:"company_id"`            // Company asal resource (target level 1)
+	FirstNotifiedAt time.Time  `gorm:"index" json:"first_notified_at"`     // Notifikasi expired pertama, dasar perhitungan eskalasi
+	CurrentLevel    int        `gorm:"default:0" json:"current_level"`     // 0 = belum dieskalasi
+	Status          string     `gorm:"index;default:'open'" json:"status"` // open, resolved
+	LastEscalatedAt *time.Time `json:"last_escalated_at"`
+	ResolvedAt      *time.Time `json:"resolved_at"`
+	ResolvedBy      *string    `json:"resolved_by"` // User ID, nil jika di-resolve otomatis oleh sistem
+	ResolutionNote  string     `gorm:"type:text" json:"resolution_note"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+
+	Levels []NotificationEscalationLevelModel `gorm:"foreignKey:EscalationID" json:"levels,omitempty"`
+}
+
+func (NotificationEscalationModel) TableName() string {
+	return "notification_escalations"
+}
+
+// NotificationEscalationLevelModel mencatat setiap level eskalasi yang sudah dijalankan
+type NotificationEscalationLevelModel struct {
+	ID             string         `gorm:"primaryKey" json:"id"`
+	EscalationID   string         `gorm:"uniqueIndex:idx_notification_escalation_levels_unique;not null" json:"escalation_id"`
+	Level          int            `gorm:"uniqueIndex:idx_notification_escalation_levels_unique" json:"level"`
+	CompanyID      string         `gorm:"index;not null" json:"company_id"` // Company yang admin-nya dinotifikasi pada level ini
+	CompanyName    string         `json:"company_name"`
+	RecipientIDs   datatypes.JSON `json:"recipient_ids" swaggertype:"array,string"` // User ID admin yang menerima notifikasi
+	RecipientCount int            `json:"recipient_count"`                          // 0 jika company tidak punya admin aktif
+	EscalatedAt    time.Time      `json:"escalated_at"`
+}
+
+func (NotificationEscalationLevelModel) TableName() string {
+	return "notification_escalation_levels"
+}
+
 // DocumentFolderStat menyimpan agregasi dokumen per folder
 type DocumentFolderStat struct {
 	FolderID  *string `json:"folder_id"`
@@ -236,12 +429,118 @@ type DocumentTypeModel struct {
 	CreatedBy  string    `gorm:"index;not null" json:"created_by"`    // User yang membuat
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
+
+	// Jadwal retensi: nil = dokumen disimpan permanen
+	RetentionDays  *int   `json:"retention_days"`
+	RetentionBasis string `gorm:"default:'created_at'" json:"retention_basis"` // created_at atau expiry_date (metadata dokumen)
 }
 
 func (DocumentTypeModel) TableName() string {
 	return "document_types"
 }
 
+// Dasar perhitungan masa retensi dokumen
+const (
+	RetentionBasisCreatedAt  = "created_at"
+	RetentionBasisExpiryDate = "expiry_date"
+)
+
+// DocumentStatusArchived adalah status dokumen yang masa retensinya habis dan menunggu review disposisi
+const DocumentStatusArchived = "archived"
+
+// Jenis resource yang bisa dikenai legal hold
+const (
+	LegalHoldResourceDocument = "document"
+	LegalHoldResourceFolder   = "folder"
+	LegalHoldResourceCompany  = "company"
+)
+
+// LegalHoldModel merepresentasikan legal hold pada dokumen, folder (beserta isinya) atau company.
+// Selama hold aktif (ReleasedAt nil), dokumen yang tercakup tidak bisa dihapus maupun didisposisi.
+type LegalHoldModel struct {
+	ID             string     `gorm:"primaryKey" json:"id"`
+	ResourceType   string     `gorm:"index;not null" json:"resource_type"` // document, folder, company
+	ResourceID     string     `gorm:"index;not null" json:"resource_id"`
+	ResourceName   string     `json:"resource_name"`
+	Reason         string     `gorm:"type:text;not null" json:"reason"`
+	CaseReference  string     `json:"case_reference"` // Nomor perkara/permintaan pemeriksaan (opsional)
+	PlacedBy       string     `gorm:"not null" json:"placed_by"`
+	PlacedByName   string     `json:"placed_by_name"`
+	PlacedAt       time.Time  `gorm:"index" json:"placed_at"`
+	ReleasedBy     string     `json:"released_by,omitempty"`
+	ReleasedByName string     `json:"released_by_name,omitempty"`
+	ReleasedAt     *time.Time `gorm:"index" json:"released_at"`
+	ReleaseNote    string     `gorm:"type:text" json:"release_note,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func (LegalHoldModel) TableName() string {
+	return "legal_holds"
+}
+
+// Status disposisi dokumen
+const (
+	DispositionStatusPendingReview = "pending_review" // Dokumen diarsipkan, menunggu keputusan reviewer
+	DispositionStatusDeleted       = "deleted"        // Disetujui, dokumen dan file dihapus
+	DispositionStatusRetained      = "retained"       // Ditolak, dokumen dipertahankan sampai RetainUntil
+)
+
+// DocumentDispositionModel mencatat satu siklus disposisi dokumen (arsip -> review -> hapus/pertahankan).
+// Record tetap disimpan setelah dokumen dihapus sebagai bahan laporan disposisi untuk auditor.
+type DocumentDispositionModel struct {
+	ID                 string     `gorm:"primaryKey" json:"id"`
+	DocumentID         string     `gorm:"index;not null" json:"document_id"`
+	DocumentName       string     `json:"document_name"`
+	FileName           string     `json:"file_name"`
+	FolderID           *string    `json:"folder_id"`
+	CompanyID          *string    `gorm:"index" json:"company_id"`
+	DocumentTypeName   string     `gorm:"index" json:"document_type_name"`
+	RetentionDays      int        `json:"retention_days"`
+	RetentionBasis     string     `json:"retention_basis"`
+	RetentionExpiredAt time.Time  `json:"retention_expired_at"`
+	PreviousStatus     string     `json:"previous_status"` // Status dokumen sebelum diarsipkan (dipulihkan jika dipertahankan)
+	Status             string     `gorm:"index;not null" json:"status"`
+	ArchivedAt         time.Time  `gorm:"index" json:"archived_at"`
+	ReviewedBy         string     `json:"reviewed_by,omitempty"`
+	ReviewedByName     string     `json:"reviewed_by_name,omitempty"`
+	ReviewedAt         *time.Time `json:"reviewed_at"`
+	ReviewNote         string     `gorm:"type:text" json:"review_note,omitempty"`
+	RetainUntil        *time.Time `json:"retain_until"` // Untuk status retained: disposisi berikutnya tidak dibuat sebelum tanggal ini
+	DisposedAt         *time.Time `json:"disposed_at"`
+	CreatedAt          time.Time  `gorm:"index" json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+func (DocumentDispositionModel) TableName() string {
+	return "document_dispositions"
+}
+
+// UpdateRetentionScheduleRequest untuk mengatur jadwal retensi jenis dokumen
+type UpdateRetentionScheduleRequest struct {
+	RetentionDays  *int   `json:"retention_days"`  // null = simpan permanen
+	RetentionBasis string `json:"retention_basis"` // created_at (default) atau expiry_date
+}
+
+// CreateLegalHoldRequest untuk memasang legal hold
+type CreateLegalHoldRequest struct {
+	ResourceType  string `json:"resource_type"`
+	ResourceID    string `json:"resource_id"`
+	Reason        string `json:"reason"`
+	CaseReference string `json:"case_reference"`
+}
+
+// ReleaseLegalHoldRequest untuk melepas legal hold
+type ReleaseLegalHoldRequest struct {
+	Note string `json:"note"`
+}
+
+// ReviewDispositionRequest untuk keputusan reviewer atas disposisi dokumen
+type ReviewDispositionRequest struct {
+	Note       string `json:"note"`
+	RetainDays int    `json:"retain_days"` // Hanya untuk retain: lama dokumen dipertahankan sebelum dievaluasi ulang
+}
+
 // ShareholderTypeModel merepresentasikan jenis pemegang saham (master data)
 type ShareholderTypeModel struct {
 	ID         string    `gorm:"primaryKey" json:"id"`
@@ -321,6 +620,11 @@ type CompanyModel struct {
 	CreatedAt           time.Time `json:"created_at"`
 	UpdatedAt           time.Time `json:"updated_at"`
 
+	// Pembubaran (restrukturisasi): company yang dibubarkan tetap tersimpan sebagai riwayat read-only
+	DissolvedAt         *time.Time `gorm:"index" json:"dissolved_at,omitempty"`           // Tanggal efektif pembubaran
+	DissolutionReason   *string    `gorm:"type:text" json:"dissolution_reason,omitempty"` // Alasan pembubaran
+	MergedIntoCompanyID *string    `gorm:"index" json:"merged_into_company_id,omitempty"` // Company penerima jika dibubarkan karena merger
+
 	// Relationships
 	Shareholders   []ShareholderModel   `gorm:"foreignKey:CompanyID" json:"shareholders,omitempty"`
 	BusinessFields []BusinessFieldModel `gorm:"foreignKey:CompanyID" json:"business_fields,omitempty"`
@@ -394,6 +698,126 @@ func (DirectorModel) TableName() string {
 	return "directors"
 }
 
+// Jenis dan status masa jabatan pengurus
+const (
+	DirectorTermAppointment   = "appointment"   // Pengangkatan pertama
+	DirectorTermReappointment = "reappointment" // Pengangkatan kembali (perpanjangan)
+
+	DirectorTermStatusActive = "active"
+	DirectorTermStatusClosed = "closed"
+
+	DirectorTermClosedRenewed = "renewed" // Ditutup karena diperpanjang dengan masa jabatan baru
+	DirectorTermClosedRemoved = "removed" // Ditutup karena pengurus dihapus dari company
+)
+
+// DirectorTermModel merepresentasikan satu masa jabatan pengurus (pengangkatan atau pengangkatan kembali)
+// DirectorModel.StartDate/EndDate selalu mengikuti masa jabatan yang aktif
+type DirectorTermModel struct {
+	ID                   string     `gorm:"primaryKey" json:"id"`
+	DirectorID           string     `gorm:"index;not null" json:"director_id"`
+	CompanyID            string     `gorm:"index;not null" json:"company_id"`
+	FullName             string     `json:"full_name"` // Snapshot nama agar laporan historis tetap lengkap meski pengurus dihapus
+	Position             string     `gorm:"not null" json:"position"`
+	TermNumber           int        `gorm:"not null;default:1" json:"term_number"`                  // 1 = pengangkatan pertama, 2 = periode kedua, dst
+	AppointmentType      string     `gorm:"not null;default:'appointment'" json:"appointment_type"` // appointment, reappointment
+	StartDate            *time.Time `gorm:"index" json:"start_date"`
+	EndDate              *time.Time `gorm:"index" json:"end_date"`
+	Status               string     `gorm:"index;not null;default:'active'" json:"status"` // active, closed
+	ClosedReason         string     `json:"closed_reason,omitempty"`                       // renewed, removed
+	ClosedAt             *time.Time `json:"closed_at"`
+	PreviousTermID       *string    `gorm:"index" json:"previous_term_id"`
+	ResolutionDocumentID *string    `gorm:"index" json:"resolution_document_id"` // Dokumen SK/RUPS (DocumentModel dengan DirectorID pengurus ini)
+	ResolutionNumber     string     `json:"resolution_number"`                   // Nomor SK/akta RUPS
+	ResolutionDate       *time.Time `json:"resolution_date"`
+	Notes                string     `gorm:"type:text" json:"notes"`
+	CreatedBy            string     `json:"created_by"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+
+	ResolutionDocument *DocumentModel `gorm:"foreignKey:ResolutionDocumentID" json:"resolution_document,omitempty"`
+}
+
+func (DirectorTermModel) TableName() string {
+	return "director_terms"
+}
+
+// CompanyPositionRequirementModel menentukan jabatan pengurus yang wajib terisi di sebuah company
+type CompanyPositionRequirementModel struct {
+	ID         string    `gorm:"primaryKey" json:"id"`
+	CompanyID  string    `gorm:"uniqueIndex:idx_company_position_requirements_unique;not null" json:"company_id"`
+	PositionID string    `gorm:"uniqueIndex:idx_company_position_requirements_unique;not null" json:"position_id"` // Reference ke DirectorPositionModel
+	MinCount   int       `gorm:"not null;default:1" json:"min_count"`                                              // Jumlah minimal pengurus aktif pada jabatan ini
+	CreatedBy  string    `json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	Position *DirectorPositionModel `gorm:"foreignKey:PositionID" json:"position,omitempty"`
+}
+
+func (CompanyPositionRequirementModel) TableName() string {
+	return "company_position_requirements"
+}
+
+// RenewDirectorTermRequest untuk perpanjangan masa jabatan (menutup masa jabatan aktif dan membuka yang baru)
+type RenewDirectorTermRequest struct {
+	StartDate            *DateOnly `json:"start_date"` // Default: sehari setelah akhir masa jabatan aktif
+	EndDate              *DateOnly `json:"end_date"`
+	Position             string    `json:"position"` // Opsional, default jabatan saat ini
+	ResolutionDocumentID *string   `json:"resolution_document_id"`
+	ResolutionNumber     string    `json:"resolution_number"`
+	ResolutionDate       *DateOnly `json:"resolution_date"`
+	Notes                string    `json:"notes"`
+}
+
+// LinkTermResolutionRequest untuk menautkan dokumen SK/RUPS ke masa jabatan
+type LinkTermResolutionRequest struct {
+	ResolutionDocumentID string    `json:"resolution_document_id"`
+	ResolutionNumber     string    `json:"resolution_number"`
+	ResolutionDate       *DateOnly `json:"resolution_date"`
+}
+
+// PositionRequirementRequest satu jabatan wajib untuk company
+type PositionRequirementRequest struct {
+	PositionID string `json:"position_id"`
+	MinCount   int    `json:"min_count"` // Default 1
+}
+
+// BoardMember satu pengurus yang menjabat pada tanggal laporan
+type BoardMember struct {
+	DirectorID           string     `json:"director_id"`
+	TermID               string     `json:"term_id"`
+	FullName             string     `json:"full_name"`
+	Position             string     `json:"position"`
+	TermNumber           int        `json:"term_number"`
+	StartDate            *time.Time `json:"start_date"`
+	EndDate              *time.Time `json:"end_date"`
+	DaysRemaining        *int       `json:"days_remaining"` // Sisa hari masa jabatan dari tanggal laporan (nil jika tanpa tanggal akhir)
+	ResolutionDocumentID *string    `json:"resolution_document_id"`
+	ResolutionNumber     string     `json:"resolution_number"`
+}
+
+// PositionVacancy jabatan wajib yang kurang terisi pada tanggal tertentu
+type PositionVacancy struct {
+	PositionID   string `json:"position_id"`
+	Position     string `json:"position"`
+	Required     int    `json:"required"`
+	Filled       int    `json:"filled"`
+	Vacant       int    `json:"vacant"`
+	LastHolderID string `json:"last_holder_id,omitempty"` // Pengurus terakhir yang menjabat (jika ada)
+	VacantSince  string `json:"vacant_since,omitempty"`   // Tanggal mulai kosong (YYYY-MM-DD), kosong jika belum pernah terisi
+}
+
+// BoardCompositionReport susunan pengurus sebuah company pada tanggal tertentu
+type BoardCompositionReport struct {
+	CompanyID   string            `json:"company_id"`
+	CompanyName string            `json:"company_name"`
+	AsOf        string            `json:"as_of"` // YYYY-MM-DD
+	Members     []BoardMember     `json:"members"`
+	ByPosition  map[string]int    `json:"by_position"`
+	Vacancies   []PositionVacancy `json:"vacancies"`
+	TotalActive int               `json:"total_active"`
+}
+
 // UserCompanyAssignmentModel untuk junction table - support multiple company assignments per user
 type UserCompanyAssignmentModel struct {
 	ID        string    `gorm:"primaryKey" json:"id"`
@@ -414,7 +838,9 @@ func (UserCompanyAssignmentModel) TableName() string {
 	return "user_company_assignments"
 }
 
-// ReportModel merepresentasikan laporan bulanan perusahaan
+// ReportModel merepresentasikan laporan bulanan perusahaan (bentuk data endpoint /reports).
+// Deprecated: data disimpan sebagai realisasi FinancialReportModel. Tabel reports hanya dibaca oleh
+// cmd/migrate-legacy-reports untuk database lama dan tidak lagi di-AutoMigrate.
 type ReportModel struct {
 	ID             string    `gorm:"primaryKey" json:"id"`
 	Period         string    `gorm:"index;not null" json:"period"` // Format: YYYY-MM (e.g., "2025-09")
@@ -449,6 +875,19 @@ type FinancialReportModel struct {
 	IsRKAP     bool    `gorm:"index;default:false" json:"is_rkap"` // true = RKAP (tahunan), false = Realisasi (bulanan)
 	InputterID *string `gorm:"index" json:"inputter_id"`           // User yang menginput
 
+	// Granularitas periode realisasi: monthly (2024-01), quarterly (2024-Q1), semester (2024-S1), annual (2024).
+	// Kosong = monthly (data lama). RKAP selalu annual
+	PeriodType string `gorm:"type:varchar(20);index" json:"period_type"`
+	IsAudited  bool   `gorm:"index;default:false" json:"is_audited"` // true = angka audited, false = management account (unaudited)
+
+	// Versi RKAP (RKAP Perubahan): 0 = RKAP awal, 1..n = revisi. Kosong/0 untuk realisasi
+	RKAPVersion    int        `gorm:"index;default:0" json:"rkap_version"`
+	RKAPStatus     string     `gorm:"type:varchar(20);index" json:"rkap_status,omitempty"` // draft, approved, rejected
+	EffectiveDate  *time.Time `json:"effective_date,omitempty"`                            // Tanggal berlaku revisi RKAP
+	RevisionReason *string    `gorm:"type:text" json:"revision_reason,omitempty"`          // Alasan revisi RKAP
+	ApprovedBy     *string    `json:"approved_by,omitempty"`                               // User yang menyetujui/menolak revisi
+	ApprovedAt     *time.Time `json:"approved_at,omitempty"`
+
 	// A. NERACA (Balance Sheet)
 	CurrentAssets        int64 `gorm:"default:0" json:"current_assets"`         // Aset Lancar
 	NonCurrentAssets     int64 `gorm:"default:0" json:"non_current_assets"`     // Aset Tidak Lancar
@@ -463,6 +902,7 @@ type FinancialReportModel struct {
 	OtherIncome       int64 `gorm:"default:0" json:"other_income"`       // Pendapatan Lain-Lain
 	Tax               int64 `gorm:"default:0" json:"tax"`                // Tax
 	NetProfit         int64 `gorm:"default:0" json:"net_profit"`         // Laba Bersih
+	Dividend          int64 `gorm:"default:0" json:"dividend"`           // Dividen (sebelumnya hanya ada di modul report lama)
 
 	// C. CASHFLOW (Arus Kas)
 	OperatingCashflow int64 `gorm:"default:0" json:"operating_cashflow"` // Arus kas bersih dari operasi
@@ -480,11 +920,14 @@ type FinancialReportModel struct {
 	NetProfitMargin       float64 `gorm:"type:decimal(10,2);default:0" json:"net_profit_margin"`       // Net Profit Margin
 	OperatingProfitMargin float64 `gorm:"type:decimal(10,2);default:0" json:"operating_profit_margin"` // Operating Profit Margin
 	DebtToEquity          float64 `gorm:"type:decimal(10,2);default:0" json:"debt_to_equity"`          // Debt to Equity
+	FinancialRatio        float64 `gorm:"type:decimal(10,2);default:0" json:"financial_ratio"`         // Rasio ringkas dari modul report lama (/reports)
 
 	// Metadata
-	Remark    *string   `gorm:"type:text" json:"remark"` // Optional
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Attachment *string   `gorm:"type:text" json:"attachment"`                    // Optional, lampiran dari modul report lama
+	Remark     *string   `gorm:"type:text" json:"remark"`                        // Optional
+	Source     string    `gorm:"type:varchar(20);index" json:"source,omitempty"` // Asal data: kosong = input/import user, seeder = data dummy development
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
 
 	// Relationships
 	Company  *CompanyModel `gorm:"foreignKey:CompanyID" json:"company,omitempty"`
@@ -495,12 +938,93 @@ func (FinancialReportModel) TableName() string {
 	return "financial_reports"
 }
 
+// Granularitas periode financial report
+const (
+	FinancialPeriodMonthly   = "monthly"
+	FinancialPeriodQuarterly = "quarterly"
+	FinancialPeriodSemester  = "semester"
+	FinancialPeriodAnnual    = "annual"
+)
+
+// FinancialReportSourceSeeder menandai realisasi yang dibuat report seeder development (hanya ini yang dihapus reset)
+const FinancialReportSourceSeeder = "seeder"
+
+// FinancialRKAPPhasingModel bobot phasing RKAP tahunan ke bulan atau kuartal (per versi RKAP)
+type FinancialRKAPPhasingModel struct {
+	ID        string    `gorm:"primaryKey" json:"id"`
+	RKAPID    string    `gorm:"column:rkap_id;index;not null" json:"rkap_id"` // financial_reports.id (RKAP awal atau revisi)
+	Period    string    `gorm:"not null" json:"period"`                       // 2024-01 (monthly) atau 2024-Q1 (quarterly)
+	Metric    string    `json:"metric"`                                       // Key taksonomi, kosong = default semua metrik arus
+	Weight    float64   `gorm:"type:decimal(7,4)" json:"weight"`              // Persen dari nilai tahunan
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (FinancialRKAPPhasingModel) TableName() string {
+	return "financial_rkap_phasings"
+}
+
+// RKAPPhasingWeight satu bobot phasing pada request/response
+type RKAPPhasingWeight struct {
+	Period string  `json:"period"`
+	Metric string  `json:"metric,omitempty"`
+	Weight float64 `json:"weight"`
+}
+
+// SetRKAPPhasingRequest untuk request body set phasing RKAP (menggantikan phasing sebelumnya)
+type SetRKAPPhasingRequest struct {
+	Granularity string              `json:"granularity" validate:"required"` // monthly atau quarterly
+	Weights     []RKAPPhasingWeight `json:"weights"`                         // Bobot per metrik (kosong = default) harus berjumlah 100
+}
+
+// RKAPPhasingResponse untuk response phasing RKAP. Granularity "even" = belum di-set (rata 1/12 per bulan)
+type RKAPPhasingResponse struct {
+	RKAPID      string              `json:"rkap_id"`
+	Year        string              `json:"year"`
+	RKAPVersion int                 `json:"rkap_version"`
+	Granularity string              `json:"granularity"`
+	Weights     []RKAPPhasingWeight `json:"weights"`
+}
+
+// FinancialPeriodComparisonLine satu baris perbandingan realisasi vs RKAP phased untuk satu periode
+type FinancialPeriodComparisonLine struct {
+	Key         string   `json:"key"`
+	Label       string   `json:"label"`
+	Section     string   `json:"section"`
+	Unit        string   `json:"unit"`
+	Aggregation string   `json:"aggregation"` // sum, last, average
+	Realisasi   *float64 `json:"realisasi"`   // null jika belum ada realisasi di periode
+	RKAPPhased  *float64 `json:"rkap_phased"` // RKAP tahunan x bobot phasing periode (saldo dan rasio: target tahunan)
+	RKAPAnnual  *float64 `json:"rkap_annual"`
+	Variance    *float64 `json:"variance"`    // Realisasi - RKAP phased
+	Achievement *float64 `json:"achievement"` // Realisasi / RKAP phased x 100
+}
+
+// FinancialPeriodComparisonResponse untuk response perbandingan periode (bulan, kuartal, semester, tahun)
+type FinancialPeriodComparisonResponse struct {
+	CompanyID     string                          `json:"company_id"`
+	Period        string                          `json:"period"`
+	PeriodType    string                          `json:"period_type"`
+	Year          string                          `json:"year"`
+	StartMonth    int                             `json:"start_month"`
+	EndMonth      int                             `json:"end_month"`
+	Audited       bool                            `json:"audited"`
+	RKAPVersion   *int                            `json:"rkap_version"`
+	RKAPPhasing   string                          `json:"rkap_phasing"`   // monthly, quarterly, atau even
+	MonthsCovered int                             `json:"months_covered"` // Jumlah bulan periode yang tercakup realisasi
+	Complete      bool                            `json:"complete"`
+	Sources       []string                        `json:"sources"` // Periode realisasi yang dipakai (setelah de-duplikasi granularitas)
+	Lines         []FinancialPeriodComparisonLine `json:"lines"`
+}
+
 // CreateFinancialReportRequest untuk request body create financial report
 type CreateFinancialReportRequest struct {
 	CompanyID string `json:"company_id" validate:"required"`
 	Year      string `json:"year" validate:"required,regexp=^\\d{4}$"` // Format: "2024"
 	Period    string `json:"period" validate:"required"`               // Format: "2024" untuk RKAP, "2024-01" untuk Realisasi
 	IsRKAP    bool   `json:"is_rkap"`                                  // true = RKAP, false = Realisasi
+	// Realisasi: monthly (default), quarterly (2024-Q1), semester (2024-S1), annual (2024)
+	PeriodType string `json:"period_type"`
+	IsAudited  bool   `json:"is_audited"` // Angka audited (umumnya laporan tahunan)
 
 	// Neraca
 	CurrentAssets        int64 `json:"current_assets"`
@@ -516,6 +1040,7 @@ type CreateFinancialReportRequest struct {
 	OtherIncome       int64 `json:"other_income"`
 	Tax               int64 `json:"tax"`
 	NetProfit         int64 `json:"net_profit"`
+	Dividend          int64 `json:"dividend"`
 
 	// Cashflow
 	OperatingCashflow int64 `json:"operating_cashflow"`
@@ -542,6 +1067,8 @@ type UpdateFinancialReportRequest struct {
 	Year   *string `json:"year" validate:"omitempty,regexp=^\\d{4}$"`
 	Period *string `json:"period"`
 	IsRKAP *bool   `json:"is_rkap"`
+	// Period mengikuti format period_type report (monthly, quarterly, semester, annual)
+	IsAudited *bool `json:"is_audited"`
 
 	// Neraca
 	CurrentAssets        *int64 `json:"current_assets"`
@@ -557,6 +1084,7 @@ type UpdateFinancialReportRequest struct {
 	OtherIncome       *int64 `json:"other_income"`
 	Tax               *int64 `json:"tax"`
 	NetProfit         *int64 `json:"net_profit"`
+	Dividend          *int64 `json:"dividend"`
 
 	// Cashflow
 	OperatingCashflow *int64 `json:"operating_cashflow"`
@@ -578,6 +1106,44 @@ type UpdateFinancialReportRequest struct {
 	Remark *string `json:"remark"`
 }
 
+// Status approval versi RKAP. RKAP awal (versi 0) selalu dianggap approved
+const (
+	RKAPStatusDraft    = "draft"
+	RKAPStatusApproved = "approved"
+	RKAPStatusRejected = "rejected"
+)
+
+// CreateRKAPRevisionRequest untuk request body revisi RKAP (RKAP Perubahan).
+// Field nilai yang tidak diisi disalin dari versi RKAP approved terakhir; year, period, dan is_rkap diabaikan
+type CreateRKAPRevisionRequest struct {
+	EffectiveDate string  `json:"effective_date" validate:"required"` // Format: YYYY-MM-DD, harus di tahun RKAP
+	Reason        *string `json:"reason"`
+	UpdateFinancialReportRequest
+}
+
+// RKAPRevisionDiffLine perubahan satu baris RKAP antar dua versi
+type RKAPRevisionDiffLine struct {
+	Key           string   `json:"key"`
+	Label         string   `json:"label"`
+	Section       string   `json:"section"` // neraca, laba_rugi, arus_kas, rasio
+	Unit          string   `json:"unit"`
+	From          float64  `json:"from"`
+	To            float64  `json:"to"`
+	Change        float64  `json:"change"`         // To - From
+	ChangePercent *float64 `json:"change_percent"` // null jika nilai awal 0
+	Changed       bool     `json:"changed"`
+}
+
+// RKAPRevisionDiffResponse untuk response diff dua versi RKAP
+type RKAPRevisionDiffResponse struct {
+	CompanyID    string                 `json:"company_id"`
+	Year         string                 `json:"year"`
+	From         *FinancialReportModel  `json:"from"`
+	To           *FinancialReportModel  `json:"to"`
+	ChangedLines int                    `json:"changed_lines"`
+	Lines        []RKAPRevisionDiffLine `json:"lines"`
+}
+
 // FinancialReportComparisonResponse untuk response perbandingan RKAP vs Realisasi YTD
 type FinancialReportComparisonResponse struct {
 	CompanyID string `json:"company_id"`
@@ -601,6 +1167,273 @@ type ComparisonItem struct {
 	Percentage   float64     `json:"percentage"`    // Persentase (Realisasi YTD / RKAP * 100)
 }
 
+// Cara agregasi metrik financial untuk YTD, LTM, dan forecast setahun
+const (
+	FinancialAggregationSum     = "sum"     // Arus (laba rugi, arus kas): dijumlahkan
+	FinancialAggregationLast    = "last"    // Saldo (neraca, saldo akhir kas): nilai bulan terakhir
+	FinancialAggregationAverage = "average" // Rasio: rata-rata bulanan
+)
+
+// FinancialTrendPoint satu titik time series bulanan realisasi
+type FinancialTrendPoint struct {
+	Period    string   `json:"period"`     // YYYY-MM
+	Value     *float64 `json:"value"`      // nil jika realisasi bulan tersebut belum ada
+	MoMGrowth *float64 `json:"mom_growth"` // % terhadap bulan sebelumnya
+	YoYGrowth *float64 `json:"yoy_growth"` // % terhadap bulan yang sama tahun sebelumnya
+	LTM       *float64 `json:"ltm"`        // Rolling 12 bulan (sum/average), nil untuk metrik saldo atau jika 12 bulan belum lengkap
+}
+
+// FinancialTrendSeries time series satu metrik taksonomi
+type FinancialTrendSeries struct {
+	Metric      string                `json:"metric"` // Key taksonomi, contoh: revenue
+	Label       string                `json:"label"`
+	Unit        string                `json:"unit"`        // IDR, percent, ratio
+	Aggregation string                `json:"aggregation"` // sum, last, average
+	Points      []FinancialTrendPoint `json:"points"`
+}
+
+// FinancialTrendResponse response analytics trend bulanan lintas tahun
+type FinancialTrendResponse struct {
+	CompanyID   string                 `json:"company_id"`
+	StartPeriod string                 `json:"start_period"`
+	EndPeriod   string                 `json:"end_period"`
+	Series      []FinancialTrendSeries `json:"series"`
+}
+
+// FinancialForecastPoint nilai aktual atau proyeksi linear satu bulan
+type FinancialForecastPoint struct {
+	Period   string   `json:"period"`
+	Actual   *float64 `json:"actual"`   // Realisasi (nil jika belum ada)
+	Forecast *float64 `json:"forecast"` // Proyeksi linear untuk bulan tanpa realisasi
+}
+
+// FinancialForecastItem proyeksi realisasi setahun penuh satu metrik dibanding RKAP
+type FinancialForecastItem struct {
+	Metric             string                   `json:"metric"`
+	Label              string                   `json:"label"`
+	Unit               string                   `json:"unit"`
+	Aggregation        string                   `json:"aggregation"`
+	MonthsActual       int                      `json:"months_actual"`        // Jumlah bulan yang sudah ada realisasi
+	ActualYTD          *float64                 `json:"actual_ytd"`           // Realisasi YTD sesuai aggregation
+	RunRate            *float64                 `json:"run_rate"`             // Proyeksi setahun dari rata-rata bulanan (run-rate)
+	Linear             *float64                 `json:"linear"`               // Proyeksi setahun dari regresi linear bulanan
+	RKAP               *float64                 `json:"rkap"`                 // Target RKAP tahunan (nil jika RKAP belum ada)
+	RunRateAchievement *float64                 `json:"run_rate_achievement"` // RunRate / RKAP * 100
+	LinearAchievement  *float64                 `json:"linear_achievement"`   // Linear / RKAP * 100
+	Points             []FinancialForecastPoint `json:"points"`
+}
+
+// FinancialForecastResponse response forecast realisasi setahun penuh
+type FinancialForecastResponse struct {
+	CompanyID  string                  `json:"company_id"`
+	Year       string                  `json:"year"`
+	AsOfPeriod string                  `json:"as_of_period"` // Periode realisasi terakhir pada tahun tersebut
+	Items      []FinancialForecastItem `json:"items"`
+}
+
+// Dasar ranking benchmark
+const (
+	FinancialBenchmarkRankByValue       = "value"       // Nilai realisasi YTD
+	FinancialBenchmarkRankByAchievement = "achievement" // Pencapaian realisasi YTD terhadap RKAP (%)
+)
+
+// FinancialBenchmarkEntry posisi satu company pada satu metrik benchmark
+type FinancialBenchmarkEntry struct {
+	CompanyID   string   `json:"company_id"`
+	CompanyCode string   `json:"company_code"`
+	CompanyName string   `json:"company_name"`
+	Level       int      `json:"level"`
+	Value       *float64 `json:"value"`       // Realisasi YTD sampai periode (nil jika belum ada realisasi)
+	RKAP        *float64 `json:"rkap"`        // Target RKAP tahunan
+	Achievement *float64 `json:"achievement"` // Value / RKAP * 100
+	Rank        *int     `json:"rank"`        // 1 = terbaik; nil jika tidak punya nilai untuk dasar ranking
+	Percentile  *float64 `json:"percentile"`  // 100 = terbaik, 0 = terburuk
+}
+
+// FinancialBenchmarkStats statistik sebaran nilai dasar ranking
+type FinancialBenchmarkStats struct {
+	Count   int      `json:"count"` // Jumlah company yang punya nilai
+	Min     *float64 `json:"min"`
+	P25     *float64 `json:"p25"`
+	Median  *float64 `json:"median"`
+	P75     *float64 `json:"p75"`
+	Max     *float64 `json:"max"`
+	Average *float64 `json:"average"`
+}
+
+// FinancialBenchmarkMetric ranking semua company untuk satu metrik
+type FinancialBenchmarkMetric struct {
+	Metric         string                    `json:"metric"`
+	Label          string                    `json:"label"`
+	Unit           string                    `json:"unit"`
+	Aggregation    string                    `json:"aggregation"`
+	HigherIsBetter bool                      `json:"higher_is_better"`
+	Stats          FinancialBenchmarkStats   `json:"stats"`
+	Entries        []FinancialBenchmarkEntry `json:"entries"` // Urut berdasarkan rank
+}
+
+// FinancialBenchmarkRequest parameter benchmark lintas company
+type FinancialBenchmarkRequest struct {
+	Period        string   // YYYY-MM, realisasi YTD dihitung Januari sampai periode ini
+	Metrics       []string // Key/konsep taksonomi, kosong = ROE dan revenue
+	CompanyIDs    []string // Daftar company eksplisit
+	RootCompanyID string   // Atau seluruh subtree (root + descendants)
+	Level         *int     // Opsional: hanya company pada level tertentu
+	RankBy        string   // value (default) atau achievement
+}
+
+// FinancialBenchmarkResponse hasil benchmark lintas company
+type FinancialBenchmarkResponse struct {
+	Period    string                     `json:"period"`
+	RankBy    string                     `json:"rank_by"`
+	Companies int                        `json:"companies"`
+	Metrics   []FinancialBenchmarkMetric `json:"metrics"`
+}
+
+// Status job import financial report
+const (
+	FinancialImportStatusProcessing = "processing" // Workbook sedang di-parse ke staging
+	FinancialImportStatusReady      = "ready"      // Staging selesai, menunggu review & commit
+	FinancialImportStatusCommitting = "committing" // Baris terpilih sedang disimpan ke financial_reports
+	FinancialImportStatusCompleted  = "completed"  // Commit selesai (bisa commit ulang baris yang tersisa)
+	FinancialImportStatusFailed     = "failed"     // Workbook tidak valid atau proses terhenti
+)
+
+// Status diff baris staging terhadap data financial report yang sudah ada
+const (
+	FinancialImportRowStatusNew       = "new"       // Belum ada data untuk company + periode
+	FinancialImportRowStatusOverwrite = "overwrite" // Sudah ada data dan nilainya berbeda
+	FinancialImportRowStatusUnchanged = "unchanged" // Sudah ada data dengan nilai yang sama
+	FinancialImportRowStatusError     = "error"     // Gagal validasi, tidak bisa di-commit
+)
+
+// Status commit per baris staging
+const (
+	FinancialImportCommitCommitted = "committed"
+	FinancialImportCommitFailed    = "failed"
+)
+
+// FinancialImportJobModel adalah satu kali upload workbook bulk financial report
+type FinancialImportJobModel struct {
+	ID            string     `gorm:"primaryKey" json:"id"`
+	FileName      string     `json:"file_name"`
+	Format        string     `gorm:"default:'xlsx'" json:"format"` // xlsx, csv, json
+	Status        string     `gorm:"index;not null" json:"status"`
+	TotalRows     int        `gorm:"default:0" json:"total_rows"`     // Jumlah baris data (tanpa header dan baris kosong)
+	ProcessedRows int        `gorm:"default:0" json:"processed_rows"` // Progress parsing ke staging
+	NewRows       int        `gorm:"default:0" json:"new_rows"`
+	OverwriteRows int        `gorm:"default:0" json:"overwrite_rows"`
+	UnchangedRows int        `gorm:"default:0" json:"unchanged_rows"`
+	ErrorRows     int        `gorm:"default:0" json:"error_rows"`
+	CommitTotal   int        `gorm:"default:0" json:"commit_total"`   // Jumlah baris yang dipilih pada commit terakhir
+	CommittedRows int        `gorm:"default:0" json:"committed_rows"` // Baris yang berhasil disimpan pada commit terakhir
+	FailedRows    int        `gorm:"default:0" json:"failed_rows"`    // Baris yang gagal disimpan pada commit terakhir
+	ErrorMessage  string     `gorm:"type:text" json:"error_message,omitempty"`
+	CreatedBy     string     `gorm:"index;not null" json:"created_by"`
+	CreatedByName string     `json:"created_by_name"`
+	StartedAt     time.Time  `json:"started_at"`
+	StagedAt      *time.Time `json:"staged_at"`
+	CommittedAt   *time.Time `json:"committed_at"`
+	CreatedAt     time.Time  `gorm:"index" json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func (FinancialImportJobModel) TableName() string {
+	return "financial_import_jobs"
+}
+
+// FinancialImportRowModel adalah satu baris workbook di staging beserta hasil validasi dan diff-nya
+type FinancialImportRowModel struct {
+	ID                string         `gorm:"primaryKey" json:"id"`
+	JobID             string         `gorm:"uniqueIndex:idx_financial_import_rows_job_row;not null" json:"job_id"`
+	RowNumber         int            `gorm:"uniqueIndex:idx_financial_import_rows_job_row" json:"row_number"` // Nomor baris Excel/CSV atau nomor entri JSON (1-based)
+	CompanyCode       string         `json:"company_code"`
+	CompanyID         string         `gorm:"index" json:"company_id"`
+	Year              string         `json:"year"`
+	Period            string         `json:"period"`
+	Status            string         `gorm:"index;not null" json:"status"`                // new, overwrite, unchanged, error
+	ExistingReportID  *string        `json:"existing_report_id,omitempty"`                // Financial report yang akan ditimpa
+	Data              datatypes.JSON `json:"data,omitempty" swaggertype:"object"`         // CreateFinancialReportRequest hasil parsing
+	Changes           datatypes.JSON `json:"changes,omitempty" swaggertype:"object"`      // Field yang berubah: {"field": {"old": x, "new": y}}
+	Errors            datatypes.JSON `json:"errors,omitempty" swaggertype:"array,object"` // []FinancialImportRowError
+	RawValues         datatypes.JSON `json:"raw_values" swaggertype:"array,string"`       // Nilai sel asli untuk error report workbook
+	CommitStatus      string         `gorm:"index" json:"commit_status"`                  // kosong = belum di-commit, committed, failed
+	CommitError       string         `gorm:"type:text" json:"commit_error,omitempty"`
+	CommittedReportID *string        `json:"committed_report_id,omitempty"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
+
+func (FinancialImportRowModel) TableName() string {
+	return "financial_import_rows"
+}
+
+// FinancialImportRowError adalah satu error validasi pada baris workbook
+type FinancialImportRowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column"`
+	Message string `json:"message"`
+}
+
+// CommitFinancialImportRequest untuk memilih baris staging yang akan disimpan
+type CommitFinancialImportRequest struct {
+	RowNumbers    []int `json:"row_numbers"`    // Nomor baris Excel yang dipilih; kosong = semua baris new & overwrite
+	SkipOverwrite bool  `json:"skip_overwrite"` // Jika RowNumbers kosong, hanya commit baris new
+}
+
+// Format file import/export financial report
+const (
+	FinancialFormatXLSX = "xlsx"
+	FinancialFormatCSV  = "csv"
+	FinancialFormatJSON = "json"
+)
+
+// FinancialConcept adalah satu konsep taksonomi financial report (kode stabil untuk pertukaran data)
+type FinancialConcept struct {
+	Concept string `json:"concept"` // Contoh: pdv:Revenue
+	Key     string `json:"key"`     // Nama field FinancialReportModel di JSON / kolom CSV
+	Label   string `json:"label"`   // Header template Excel
+	Section string `json:"section"` // neraca, laba_rugi, arus_kas, rasio
+	Unit    string `json:"unit"`    // IDR, percent, ratio
+}
+
+// FinancialTaxonomyResponse daftar konsep taksonomi yang didukung import/export
+type FinancialTaxonomyResponse struct {
+	Taxonomy string             `json:"taxonomy"`
+	Concepts []FinancialConcept `json:"concepts"`
+}
+
+// FinancialAccountMappingModel memetakan kode akun chart-of-accounts milik company ke konsep taksonomi.
+// Beberapa akun boleh dipetakan ke konsep yang sama (nilainya dijumlahkan saat import).
+type FinancialAccountMappingModel struct {
+	ID          string    `gorm:"primaryKey" json:"id"`
+	CompanyID   string    `gorm:"uniqueIndex:idx_financial_account_mapping_company_code;not null" json:"company_id"`
+	AccountCode string    `gorm:"uniqueIndex:idx_financial_account_mapping_company_code;not null" json:"account_code"`
+	AccountName string    `json:"account_name"`
+	Concept     string    `gorm:"not null" json:"concept"`
+	Multiplier  float64   `gorm:"default:1" json:"multiplier"` // Contoh: -1 untuk akun yang tercatat dengan tanda terbalik
+	CreatedBy   string    `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (FinancialAccountMappingModel) TableName() string {
+	return "financial_account_mappings"
+}
+
+// FinancialAccountMappingItem satu baris mapping akun pada request
+type FinancialAccountMappingItem struct {
+	AccountCode string   `json:"account_code" validate:"required"`
+	AccountName string   `json:"account_name"`
+	Concept     string   `json:"concept" validate:"required"` // Kode konsep atau key field (contoh: pdv:Revenue atau revenue)
+	Multiplier  *float64 `json:"multiplier"`                  // Optional, default 1
+}
+
+// SetFinancialAccountMappingsRequest mengganti seluruh mapping akun milik company
+type SetFinancialAccountMappingsRequest struct {
+	Mappings []FinancialAccountMappingItem `json:"mappings"`
+}
+
 // CreateReportRequest untuk request body create report
 type CreateReportRequest struct {
 	Period         string  `json:"period" validate:"required,regexp=^\\d{4}-\\d{2}$"` // Format: YYYY-MM
@@ -726,6 +1559,232 @@ type CompanyCreateRequest struct {
 	Directors          []DirectorRequest     `json:"directors"`
 }
 
+// CompanyImportIssue adalah satu error validasi pada file import company (sheet + nomor baris Excel/CSV)
+type CompanyImportIssue struct {
+	Sheet   string `json:"sheet"`
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// CompanyImportCompany adalah ringkasan satu company pada hasil import (preview atau commit)
+type CompanyImportCompany struct {
+	Row             int                  `json:"row"`
+	ID              string               `json:"id,omitempty"` // Terisi setelah commit
+	Code            string               `json:"code"`
+	Name            string               `json:"name"`
+	ParentCode      string               `json:"parent_code,omitempty"`
+	ParentSource    string               `json:"parent_source,omitempty"` // existing (sudah ada di database) atau file (dibuat dari file yang sama)
+	Level           int                  `json:"level"`
+	Shareholders    int                  `json:"shareholders"`
+	OwnershipTotal  float64              `json:"ownership_total"`
+	HasMainBusiness bool                 `json:"has_main_business"`
+	Directors       int                  `json:"directors"`
+	Errors          []CompanyImportIssue `json:"errors,omitempty"`
+}
+
+// CompanyImportResult adalah hasil import company bulk; dry_run=true berarti belum ada data yang disimpan
+type CompanyImportResult struct {
+	DryRun         bool                   `json:"dry_run"`
+	Committed      bool                   `json:"committed"`
+	TotalCompanies int                    `json:"total_companies"`
+	ValidCompanies int                    `json:"valid_companies"`
+	ErrorCount     int                    `json:"error_count"`
+	Companies      []CompanyImportCompany `json:"companies"`
+	Errors         []CompanyImportIssue   `json:"errors,omitempty"` // Error yang tidak terikat ke satu company (header, kode tidak dikenal)
+}
+
+// Jenis node graph kepemilikan
+const (
+	OwnershipNodeCompany  = "company"
+	OwnershipNodeExternal = "external" // Pemegang saham individu/badan di luar grup (shareholder_company_id kosong)
+)
+
+// OwnershipGraphNode adalah company atau pemegang saham eksternal pada graph kepemilikan
+type OwnershipGraphNode struct {
+	ID                 string   `json:"id"` // Company ID, atau "external:<identitas>" untuk pemegang saham eksternal
+	Type               string   `json:"type"`
+	Code               string   `json:"code,omitempty"`
+	Name               string   `json:"name"`
+	Level              int      `json:"level,omitempty"`
+	InScope            bool     `json:"in_scope"`                      // false = company pemegang saham di luar grup yang diminta
+	ShareholderTotal   *float64 `json:"shareholder_total,omitempty"`   // Total persentase pemegang saham company ini
+	EffectiveOwnership *float64 `json:"effective_ownership,omitempty"` // Kepemilikan efektif root (look-through), hanya jika root diisi
+}
+
+// OwnershipGraphEdge adalah kepemilikan langsung: From memiliki Percent % saham To
+type OwnershipGraphEdge struct {
+	From         string  `json:"from"`
+	To           string  `json:"to"`
+	Percent      float64 `json:"percent"`
+	IsMainParent bool    `json:"is_main_parent"`
+}
+
+// OwnershipIssue adalah masalah struktur kepemilikan pada data yang sudah tersimpan
+type OwnershipIssue struct {
+	CompanyID string `json:"company_id"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+}
+
+// OwnershipGraphResponse adalah graph kepemilikan grup (nodes/edges) beserta siklus dan masalah total kepemilikan
+type OwnershipGraphResponse struct {
+	RootCompanyID string               `json:"root_company_id,omitempty"`
+	Nodes         []OwnershipGraphNode `json:"nodes"`
+	Edges         []OwnershipGraphEdge `json:"edges"`
+	Cycles        [][]string           `json:"cycles"` // Setiap siklus berisi kode company yang saling memiliki
+	Issues        []OwnershipIssue     `json:"issues"`
+}
+
+// EffectiveOwnershipPath adalah satu rantai kepemilikan dari company asal ke company tujuan
+type EffectiveOwnershipPath struct {
+	Codes    []string  `json:"codes"`    // Kode company sepanjang rantai, dari asal ke tujuan
+	Percents []float64 `json:"percents"` // Persentase kepemilikan per langkah
+	Percent  float64   `json:"percent"`  // Hasil perkalian persentase sepanjang rantai
+}
+
+// EffectiveOwnershipResponse adalah kepemilikan efektif (look-through) satu company atas company lain
+type EffectiveOwnershipResponse struct {
+	FromCompanyID    string                   `json:"from_company_id"`
+	FromCode         string                   `json:"from_code"`
+	ToCompanyID      string                   `json:"to_company_id"`
+	ToCode           string                   `json:"to_code"`
+	DirectPercent    float64                  `json:"direct_percent"`
+	EffectivePercent float64                  `json:"effective_percent"`
+	Paths            []EffectiveOwnershipPath `json:"paths"`
+	PathsTruncated   bool                     `json:"paths_truncated"`
+}
+
+// Aksi restrukturisasi company
+const (
+	RestructuringActionMove     = "move"     // Pindah company beserta seluruh anak perusahaannya ke induk baru
+	RestructuringActionMerge    = "merge"    // Gabung company ke company lain, company asal dibubarkan
+	RestructuringActionDissolve = "dissolve" // Bubarkan company (soft, riwayat tetap tersimpan read-only)
+
+	CompanyStatusDissolved = "Dibubarkan"
+)
+
+// CompanyRestructuringEventModel mencatat setiap aksi restrukturisasi (move, merge, dissolve)
+type CompanyRestructuringEventModel struct {
+	ID                  string         `gorm:"primaryKey" json:"id"`
+	Action              string         `gorm:"type:varchar(20);index;not null" json:"action"`
+	CompanyID           string         `gorm:"index;not null" json:"company_id"`         // Company yang dipindah/di-merge/dibubarkan
+	TargetCompanyID     *string        `gorm:"index" json:"target_company_id,omitempty"` // Induk baru (move) atau company penerima (merge)
+	PreviousParentID    *string        `gorm:"index" json:"previous_parent_id,omitempty"`
+	EffectiveDate       time.Time      `gorm:"index;not null" json:"effective_date"`
+	Reason              *string        `gorm:"type:text" json:"reason,omitempty"`
+	Impact              datatypes.JSON `json:"impact" swaggertype:"object"` // CompanyRestructuringImpact
+	PerformedBy         string         `gorm:"index" json:"performed_by"`
+	PerformedByUsername string         `json:"performed_by_username"`
+	CreatedAt           time.Time      `json:"created_at"`
+}
+
+func (CompanyRestructuringEventModel) TableName() string {
+	return "company_restructuring_events"
+}
+
+// MoveCompanyRequest untuk memindahkan company (beserta subtree) ke induk baru
+type MoveCompanyRequest struct {
+	NewParentID   string  `json:"new_parent_id" validate:"required"`
+	EffectiveDate string  `json:"effective_date"` // Format: YYYY-MM-DD, default hari ini
+	Reason        *string `json:"reason"`
+}
+
+// MergeCompanyRequest untuk menggabungkan company ke company penerima
+type MergeCompanyRequest struct {
+	TargetCompanyID string  `json:"target_company_id" validate:"required"`
+	EffectiveDate   string  `json:"effective_date"` // Format: YYYY-MM-DD, default hari ini
+	Reason          *string `json:"reason"`
+}
+
+// DissolveCompanyRequest untuk membubarkan company
+type DissolveCompanyRequest struct {
+	EffectiveDate string  `json:"effective_date"` // Format: YYYY-MM-DD, default hari ini
+	Reason        *string `json:"reason"`
+}
+
+// CompanyRestructuringLevelChange perubahan posisi satu company akibat restrukturisasi
+type CompanyRestructuringLevelChange struct {
+	CompanyID   string  `json:"company_id"`
+	Code        string  `json:"code"`
+	Name        string  `json:"name"`
+	OldParentID *string `json:"old_parent_id"`
+	NewParentID *string `json:"new_parent_id"`
+	OldLevel    int     `json:"old_level"`
+	NewLevel    int     `json:"new_level"`
+}
+
+// CompanyRestructuringImpact ringkasan data yang terdampak restrukturisasi.
+// Untuk merge: data yang dipindah ke company penerima. Untuk dissolve: data yang dipertahankan sebagai riwayat read-only
+type CompanyRestructuringImpact struct {
+	Companies                  []CompanyRestructuringLevelChange `json:"companies"`
+	DocumentFolders            int64                             `json:"document_folders"`
+	Documents                  int64                             `json:"documents"`
+	FinancialReports           int64                             `json:"financial_reports"`
+	FinancialReportConflicts   []string                          `json:"financial_report_conflicts,omitempty"` // Periode yang sudah ada di penerima, tetap di company asal
+	Users                      int64                             `json:"users"`
+	UserAssignments            int64                             `json:"user_assignments"`
+	DeactivatedUserAssignments int64                             `json:"deactivated_user_assignments"` // Assignment duplikat (user sudah di-assign ke penerima)
+	Shareholdings              int64                             `json:"shareholdings"`                // Kepemilikan saham di company lain yang dialihkan
+}
+
+// CompanyRestructuringResult hasil (atau preview) aksi restrukturisasi
+type CompanyRestructuringResult struct {
+	Preview bool                            `json:"preview"`
+	Action  string                          `json:"action"`
+	Impact  CompanyRestructuringImpact      `json:"impact"`
+	Event   *CompanyRestructuringEventModel `json:"event,omitempty"` // Hanya diisi jika tidak preview
+}
+
+// Status job export data company
+const (
+	CompanyExportStatusProcessing = "processing" // Arsip sedang dibuat di background
+	CompanyExportStatusCompleted  = "completed"  // Arsip siap diunduh sampai expires_at
+	CompanyExportStatusFailed     = "failed"     // Gagal dibuat atau terhenti karena restart
+	CompanyExportStatusExpired    = "expired"    // Arsip sudah dihapus dari storage setelah masa simpan
+)
+
+// CompanyExportJobModel adalah satu permintaan export data company (data portability) ke arsip zip
+type CompanyExportJobModel struct {
+	ID                 string     `gorm:"primaryKey" json:"id"`
+	CompanyID          string     `gorm:"index;not null" json:"company_id"`
+	CompanyCode        string     `json:"company_code"`
+	CompanyName        string     `json:"company_name"`
+	IncludeDescendants bool       `gorm:"default:false" json:"include_descendants"`
+	Status             string     `gorm:"index;not null" json:"status"`
+	TotalSteps         int        `gorm:"default:0" json:"total_steps"`     // Jumlah tahap (per company + file dokumen + index)
+	ProcessedSteps     int        `gorm:"default:0" json:"processed_steps"` // Progress pembuatan arsip
+	CurrentStep        string     `json:"current_step,omitempty"`
+	CompanyCount       int        `gorm:"default:0" json:"company_count"`
+	DocumentCount      int        `gorm:"default:0" json:"document_count"`
+	MissingFileCount   int        `gorm:"default:0" json:"missing_file_count"` // File dokumen yang tidak ditemukan di storage
+	FilePath           string     `json:"-"`                                   // Object path arsip di storage (exports/<id>.zip)
+	FileSize           int64      `gorm:"default:0" json:"file_size"`
+	ErrorMessage       string     `gorm:"type:text" json:"error_message,omitempty"`
+	CreatedBy          string     `gorm:"index;not null" json:"created_by"`
+	CreatedByName      string     `json:"created_by_name"`
+	StartedAt          time.Time  `json:"started_at"`
+	CompletedAt        *time.Time `json:"completed_at"`
+	ExpiresAt          *time.Time `gorm:"index" json:"expires_at"` // Arsip dihapus dari storage setelah waktu ini
+	CreatedAt          time.Time  `gorm:"index" json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+func (CompanyExportJobModel) TableName() string {
+	return "company_export_jobs"
+}
+
+// CreateCompanyExportRequest untuk membuat export data company
+type CreateCompanyExportRequest struct {
+	IncludeDescendants bool `json:"include_descendants"` // Sertakan seluruh anak perusahaan
+}
+
+// CompanyExportDownloadLink adalah link unduh arsip export yang berlaku sementara
+type CompanyExportDownloadLink struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
 // CompanyUpdateRequest untuk update company dengan data lengkap
 type CompanyUpdateRequest struct {
 	Name               string                `json:"name"`
@@ -1,7 +1,6 @@
 package audit
 
 import (
-	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
@@ -24,48 +23,19 @@ var PermanentResources = []string{
 	ResourceDocument,        // Document Management
 	ResourceCompany,         // Subsidiary
 	ResourceUser,            // User Management
+	ResourceShareholder,     // Pemegang saham (riwayat perubahan per entity)
+	ResourceDirector,        // Pengurus (riwayat perubahan per entity)
+	ResourceRole,            // Role Management
 }
 
 // InitAuditLogger menginisialisasi audit logger
 func InitAuditLogger() {
 	zapLog := logger.GetLogger()
+	// Tabel audit_logs, user_activity_logs, dan index-nya dibuat oleh migrasi schema (package migrations)
 	auditLogger = repository.NewAuditLogger(database.GetDB())
 
-	// Auto migrate tabel audit log (regular, dengan retention)
-	if err := database.GetDB().AutoMigrate(&domain.AuditLog{}); err != nil {
-		zapLog.Error("Error migrating audit log table", zap.Error(err))
-		return
-	}
-
-	// Auto migrate tabel user activity log (permanent, tanpa retention)
-	if err := database.GetDB().AutoMigrate(&domain.UserActivityLog{}); err != nil {
-		zapLog.Error("Error migrating user activity log table", zap.Error(err))
-		return
-	}
-
-	// Buat composite index untuk performa cleanup query (created_at + log_type)
-	// Index ini akan mempercepat query cleanup yang sering memfilter berdasarkan waktu dan tipe log
-	if database.GetDB().Migrator().HasIndex(&domain.AuditLog{}, "idx_audit_logs_created_at_log_type") {
-		// Index sudah ada, skip
-	} else {
-		if err := database.GetDB().Exec("CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at_log_type ON audit_logs(created_at, log_type)").Error; err != nil {
-			zapLog.Warn("Failed to create composite index for audit logs", zap.Error(err))
-			// Tidak fatal, lanjutkan saja
-		} else {
-			zapLog.Info("Composite index created for audit logs", zap.String("index", "idx_audit_logs_created_at_log_type"))
-		}
-	}
-
-	// Buat index untuk user activity logs
-	if database.GetDB().Migrator().HasIndex(&domain.UserActivityLog{}, "idx_user_activity_logs_created_at") {
-		// Index sudah ada, skip
-	} else {
-		if err := database.GetDB().Exec("CREATE INDEX IF NOT EXISTS idx_user_activity_logs_created_at ON user_activity_logs(created_at)").Error; err != nil {
-			zapLog.Warn("Failed to create index for user activity logs", zap.Error(err))
-		} else {
-			zapLog.Info("Index created for user activity logs", zap.String("index", "idx_user_activity_logs_created_at"))
-		}
-	}
+	// Inisialisasi sink eksternal (syslog/webhook/file) untuk pengiriman ke SIEM
+	InitAuditSinks(database.GetDB())
 
 	zapLog.Info("Audit logger initialized",
 		zap.Strings("permanent_resources", PermanentResources),
@@ -80,6 +50,9 @@ func LogAction(userID, username, action, resource, resourceID, ipAddress, userAg
 			_ = auditLogger.Log(userID, username, action, resource, resourceID, ipAddress, userAgent, status, details)
 		}()
 	}
+
+	// Kirim juga ke sink eksternal (SIEM) jika dikonfigurasi, tanpa blocking request
+	dispatchToSinks(userID, username, action, resource, resourceID, ipAddress, userAgent, status, details)
 }
 
 // Constants untuk action types
@@ -103,9 +76,16 @@ const (
 	ActionDeleteUser = "delete_user"
 
 	// Company/Subsidiary actions
-	ActionCreateCompany = "create_company"
-	ActionUpdateCompany = "update_company"
-	ActionDeleteCompany = "delete_company"
+	ActionCreateCompany      = "create_company"
+	ActionUpdateCompany      = "update_company"
+	ActionDeleteCompany      = "delete_company"
+	ActionImportCompany      = "import_companies"
+	ActionRestructureCompany = "restructure_company"
+
+	// Director term actions (siklus masa jabatan pengurus)
+	ActionRenewDirectorTerm          = "renew_director_term"
+	ActionLinkTermResolution         = "link_director_term_resolution"
+	ActionUpdatePositionRequirements = "update_position_requirements"
 
 	// Document actions
 	ActionCreateDoc = "create_document"
@@ -113,6 +93,18 @@ const (
 	ActionDeleteDoc = "delete_document"
 	ActionViewDoc   = "view_document"
 
+	// Document retention & legal hold actions
+	ActionSetRetentionSchedule = "set_retention_schedule"
+	ActionPlaceLegalHold       = "place_legal_hold"
+	ActionReleaseLegalHold     = "release_legal_hold"
+	ActionArchiveDocument      = "archive_document"
+	ActionDisposeDocument      = "dispose_document"
+	ActionRetainDocument       = "retain_document"
+
+	// Recycle bin dokumen/folder
+	ActionRestoreDocument = "restore_document"
+	ActionPurgeDocument   = "purge_document"
+
 	// File Management actions (untuk modul File Management)
 	ActionCreateFile   = "create_file"
 	ActionUpdateFile   = "update_file"
@@ -127,6 +119,22 @@ const (
 	ActionExportReport   = "export_report"
 	ActionDeleteReport   = "delete_report"
 
+	// Financial Report import actions
+	ActionImportFinancialReports = "import_financial_reports"
+	ActionCommitFinancialImport  = "commit_financial_import"
+	ActionExportFinancialReports = "export_financial_reports"
+	ActionUpdateAccountMapping   = "update_financial_account_mapping"
+
+	// Company data export (data portability) actions
+	ActionExportCompanyData     = "export_company_data"
+	ActionCreateExportLink      = "create_company_export_link"
+	ActionDownloadCompanyExport = "download_company_export"
+
+	// RKAP revision (RKAP Perubahan) actions
+	ActionCreateRKAPRevision  = "create_rkap_revision"
+	ActionApproveRKAPRevision = "approve_rkap_revision"
+	ActionRejectRKAPRevision  = "reject_rkap_revision"
+
 	// 2FA actions
 	ActionEnable2FA  = "enable_2fa"
 	ActionDisable2FA = "disable_2fa"
@@ -134,6 +142,8 @@ const (
 	// Notification actions
 	ActionMarkNotificationRead     = "mark_notification_read"
 	ActionMarkAllNotificationsRead = "mark_all_notifications_read"
+	ActionUpdateNotificationPrefs  = "update_notification_preferences"
+	ActionResolveEscalation        = "resolve_notification_escalation"
 )
 
 // Constants untuk resource types
@@ -148,6 +158,10 @@ const (
 	ResourceReport          = "report"           // Untuk modul Report Management
 	ResourceFinancialReport = "financial_report" // Untuk modul Financial Report (RKAP & Realisasi)
 	ResourceNotification    = "notification"     // Untuk modul Notification
+	ResourceShareholder     = "shareholder"      // Pemegang saham perusahaan
+	ResourceDirector        = "director"         // Pengurus/direksi perusahaan
+	ResourceFolder          = "folder"           // Folder dokumen (dicatat terpisah dari dokumen)
+	ResourceLegalHold       = "legal_hold"       // Legal hold dokumen/folder/company
 )
 
 // Constants untuk status
@@ -0,0 +1,386 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// FieldChange menyimpan nilai lama dan nilai baru dari satu field
+// Format JSON {"old": ..., "new": ...} sama dengan format details.changes yang sudah dipakai frontend
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// Aksi perubahan untuk item dalam koleksi (shareholders, directors, dll)
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeUpdated = "updated"
+)
+
+// CollectionChange merepresentasikan perubahan satu item di dalam koleksi (misalnya satu pemegang saham)
+type CollectionChange struct {
+	Action   string                 // added, removed, updated
+	Index    int                    // Posisi item (di data baru untuk added/updated, di data lama untuk removed)
+	EntityID string                 // ID entity (ID baru untuk added/updated, ID lama untuk removed)
+	Snapshot map[string]interface{} // Snapshot field (sudah di-mask) untuk added/removed
+	Changes  map[string]FieldChange // Diff per field untuk updated
+}
+
+// sensitiveFields berisi nama field JSON yang nilainya harus di-mask sebelum disimpan ke log
+var sensitiveFields = map[string]bool{
+	"ktp":             true,
+	"npwp":            true,
+	"identity_number": true,
+	"password":        true,
+	"secret":          true,
+	"backup_codes":    true,
+	"token":           true,
+}
+
+// ignoredFields tidak dianggap sebagai perubahan data (diatur otomatis oleh GORM)
+var ignoredFields = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+}
+
+// IsSensitiveField mengecek apakah field (nama JSON) harus di-mask di audit log
+func IsSensitiveField(field string) bool {
+	return sensitiveFields[strings.ToLower(field)]
+}
+
+// MaskValue menyamarkan nilai sensitif, hanya menyisakan 4 karakter terakhir
+// Contoh: "3174012345678901" -> "************8901"
+func MaskValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	s := fmt.Sprintf("%v", value)
+	if s == "" {
+		return ""
+	}
+	runes := []rune(s)
+	if len(runes) <= 4 {
+		return strings.Repeat("*", len(runes))
+	}
+	return strings.Repeat("*", len(runes)-4) + string(runes[len(runes)-4:])
+}
+
+// DiffModels membandingkan dua domain model dengan tipe yang sama dan mengembalikan perubahan per field.
+// Key adalah nama field JSON. Field relasi (struct/slice), field dengan json:"-", created_at dan updated_at diabaikan.
+// Field sensitif (KTP, NPWP, identity_number, dll) di-mask. Field datatypes.JSON (metadata) dibandingkan per key,
+// misalnya "metadata.expiry_date".
+func DiffModels(oldModel, newModel interface{}) map[string]FieldChange {
+	changes := make(map[string]FieldChange)
+
+	oldVal := indirectValue(reflect.ValueOf(oldModel))
+	newVal := indirectValue(reflect.ValueOf(newModel))
+	if !oldVal.IsValid() && !newVal.IsValid() {
+		return changes
+	}
+
+	oldFields := flattenModel(oldVal)
+	newFields := flattenModel(newVal)
+
+	for field, newValue := range newFields {
+		oldValue, existed := oldFields[field]
+		if existed && reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		changes[field] = maskChange(field, oldValue, newValue)
+	}
+	for field, oldValue := range oldFields {
+		if _, exists := newFields[field]; !exists {
+			changes[field] = maskChange(field, oldValue, nil)
+		}
+	}
+
+	return changes
+}
+
+// SnapshotModel mengembalikan representasi field-field model (sudah di-mask) untuk disimpan di log
+// Berguna untuk mencatat data yang ditambahkan atau dihapus
+func SnapshotModel(model interface{}) map[string]interface{} {
+	snapshot := make(map[string]interface{})
+	val := indirectValue(reflect.ValueOf(model))
+	if !val.IsValid() {
+		return snapshot
+	}
+	for field, value := range flattenModel(val) {
+		if value == nil || value == "" {
+			continue
+		}
+		if IsSensitiveField(lastSegment(field)) {
+			value = MaskValue(value)
+		}
+		snapshot[field] = value
+	}
+	return snapshot
+}
+
+// DiffCollection membandingkan dua koleksi model (misalnya shareholders lama vs baru).
+// Item dicocokkan dengan keyFn (misalnya full_name|ktp untuk director), bukan berdasarkan posisi,
+// sehingga perubahan urutan tidak dianggap sebagai perubahan data.
+func DiffCollection[T any](oldItems, newItems []T, keyFn func(T) string) []CollectionChange {
+	var result []CollectionChange
+
+	oldByKey := make(map[string]int, len(oldItems))
+	for i, item := range oldItems {
+		oldByKey[keyFn(item)] = i
+	}
+
+	matched := make(map[int]bool, len(oldItems))
+	for i, item := range newItems {
+		oldIdx, exists := oldByKey[keyFn(item)]
+		if !exists || matched[oldIdx] {
+			result = append(result, CollectionChange{
+				Action:   ChangeAdded,
+				Index:    i,
+				EntityID: modelID(item),
+				Snapshot: SnapshotModel(item),
+			})
+			continue
+		}
+		matched[oldIdx] = true
+
+		diff := DiffModels(oldItems[oldIdx], item)
+		// ID bisa berubah jika data dibuat ulang, tidak perlu dicatat sebagai perubahan field
+		delete(diff, "id")
+		if len(diff) > 0 {
+			result = append(result, CollectionChange{
+				Action:   ChangeUpdated,
+				Index:    i,
+				EntityID: modelID(item),
+				Changes:  diff,
+			})
+		}
+	}
+
+	for i, item := range oldItems {
+		if !matched[i] {
+			result = append(result, CollectionChange{
+				Action:   ChangeRemoved,
+				Index:    i,
+				EntityID: modelID(item),
+				Snapshot: SnapshotModel(item),
+			})
+		}
+	}
+
+	return result
+}
+
+// FlattenCollectionChanges mengubah hasil DiffCollection ke format details.changes yang dipakai frontend:
+//   - "<prefix>_added_<n>" / "<prefix>_removed_<n>": snapshot + action
+//   - "<prefix>_<index>_<field>": {"old": ..., "new": ...}
+func FlattenCollectionChanges(prefix string, items []CollectionChange, into map[string]interface{}) {
+	added, removed := 0, 0
+	for _, item := range items {
+		switch item.Action {
+		case ChangeAdded, ChangeRemoved:
+			info := map[string]interface{}{"action": item.Action}
+			for k, v := range item.Snapshot {
+				if k == "id" || k == "company_id" {
+					continue
+				}
+				info[k] = v
+			}
+			if item.Action == ChangeAdded {
+				into[fmt.Sprintf("%s_added_%d", prefix, added)] = info
+				added++
+			} else {
+				into[fmt.Sprintf("%s_removed_%d", prefix, removed)] = info
+				removed++
+			}
+		case ChangeUpdated:
+			for field, change := range item.Changes {
+				into[fmt.Sprintf("%s_%d_%s", prefix, item.Index, field)] = change
+			}
+		}
+	}
+}
+
+// LogChanges mencatat aksi update beserta diff per field di details["changes"].
+// details boleh nil; field lain di details (company_id, period, dll) tetap disimpan apa adanya.
+func LogChanges(userID, username, action, resource, resourceID, ipAddress, userAgent string, changes map[string]FieldChange, details map[string]interface{}) {
+	if details == nil {
+		details = make(map[string]interface{})
+	}
+	if _, exists := details["changes"]; !exists {
+		details["changes"] = changes
+	}
+	LogAction(userID, username, action, resource, resourceID, ipAddress, userAgent, StatusSuccess, details)
+}
+
+// LogCollectionChanges mencatat setiap perubahan item koleksi sebagai log tersendiri per entity
+// (misalnya resource "director" dengan ID director), sehingga riwayat per entity bisa ditelusuri.
+func LogCollectionChanges(userID, username, resource, ipAddress, userAgent string, items []CollectionChange, details map[string]interface{}) {
+	for _, item := range items {
+		if item.EntityID == "" {
+			continue
+		}
+		entry := make(map[string]interface{}, len(details)+1)
+		for k, v := range details {
+			entry[k] = v
+		}
+
+		action := ActionUpdate
+		switch item.Action {
+		case ChangeAdded:
+			action = ActionCreate
+			entry["changes"] = snapshotAsChanges(item.Snapshot, false)
+		case ChangeRemoved:
+			action = ActionDelete
+			entry["changes"] = snapshotAsChanges(item.Snapshot, true)
+		default:
+			entry["changes"] = item.Changes
+		}
+		LogAction(userID, username, action, resource, item.EntityID, ipAddress, userAgent, StatusSuccess, entry)
+	}
+}
+
+// snapshotAsChanges mengubah snapshot menjadi format FieldChange (old=nil untuk data baru, new=nil untuk data dihapus)
+func snapshotAsChanges(snapshot map[string]interface{}, removed bool) map[string]FieldChange {
+	changes := make(map[string]FieldChange, len(snapshot))
+	for field, value := range snapshot {
+		if removed {
+			changes[field] = FieldChange{Old: value}
+		} else {
+			changes[field] = FieldChange{New: value}
+		}
+	}
+	return changes
+}
+
+func maskChange(field string, oldValue, newValue interface{}) FieldChange {
+	if IsSensitiveField(lastSegment(field)) {
+		return FieldChange{Old: MaskValue(oldValue), New: MaskValue(newValue)}
+	}
+	return FieldChange{Old: oldValue, New: newValue}
+}
+
+func lastSegment(field string) string {
+	if idx := strings.LastIndex(field, "."); idx >= 0 {
+		return field[idx+1:]
+	}
+	return field
+}
+
+func indirectValue(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// modelID mengambil nilai field ID (jika ada) dari sebuah model
+func modelID(model interface{}) string {
+	v := indirectValue(reflect.ValueOf(model))
+	if !v.IsValid() || v.Kind() != reflect.Struct {
+		return ""
+	}
+	f := v.FieldByName("ID")
+	if !f.IsValid() || f.Kind() != reflect.String {
+		return ""
+	}
+	return f.String()
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// flattenModel mengubah struct menjadi map nama field JSON -> nilai yang sudah dinormalisasi
+func flattenModel(v reflect.Value) map[string]interface{} {
+	result := make(map[string]interface{})
+	if !v.IsValid() || v.Kind() != reflect.Struct {
+		return result
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := jsonFieldName(sf)
+		if name == "" || ignoredFields[name] {
+			continue
+		}
+
+		fv := v.Field(i)
+		ft := sf.Type
+		isPtr := ft.Kind() == reflect.Ptr
+		if isPtr {
+			ft = ft.Elem()
+		}
+
+		switch {
+		case ft == timeType:
+			if isPtr && fv.IsNil() {
+				result[name] = nil
+				continue
+			}
+			tv := indirectValue(fv).Interface().(time.Time)
+			if tv.IsZero() {
+				result[name] = nil
+			} else {
+				result[name] = tv.Format(time.RFC3339)
+			}
+		case ft.Kind() == reflect.Slice && ft.Elem().Kind() == reflect.Uint8:
+			// datatypes.JSON / json.RawMessage: bandingkan per key jika berupa object
+			bv := indirectValue(fv)
+			if !bv.IsValid() || bv.Kind() != reflect.Slice {
+				// Pointer nil (*datatypes.JSON, *[]byte) dianggap kosong
+				continue
+			}
+			raw := bv.Bytes()
+			if ft.ConvertibleTo(rawMessageType) && len(raw) > 0 {
+				var obj map[string]interface{}
+				if err := json.Unmarshal(raw, &obj); err == nil {
+					for k, val := range obj {
+						result[name+"."+k] = val
+					}
+					continue
+				}
+			}
+			if len(raw) > 0 {
+				result[name] = string(raw)
+			}
+		case ft.Kind() == reflect.Struct || ft.Kind() == reflect.Slice || ft.Kind() == reflect.Map:
+			// Relasi (Company, Folder, Shareholders, dll) tidak dibandingkan di sini
+			continue
+		default:
+			if isPtr {
+				if fv.IsNil() {
+					result[name] = nil
+				} else {
+					result[name] = fv.Elem().Interface()
+				}
+			} else {
+				result[name] = fv.Interface()
+			}
+		}
+	}
+	return result
+}
+
+func jsonFieldName(sf reflect.StructField) string {
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	name := strings.Split(tag, ",")[0]
+	if name == "" {
+		name = sf.Name
+	}
+	return name
+}
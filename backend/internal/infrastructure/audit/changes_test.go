@@ -0,0 +1,241 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+)
+
+type testParent struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type testChild struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type testModel struct {
+	ID         string          `json:"id"`
+	Name       string          `json:"name"`
+	Capital    float64         `json:"capital"`
+	ParentID   *string         `json:"parent_id"`
+	Parent     *testParent     `json:"parent,omitempty"`
+	Owner      testParent      `json:"owner"`
+	Children   []testChild     `json:"children"`
+	Tags       map[string]bool `json:"tags"`
+	NPWP       string          `json:"npwp"`
+	Password   string          `json:"password"`
+	Token      *string         `json:"token"`
+	Internal   string          `json:"-"`
+	StartDate  time.Time       `json:"start_date"`
+	EndDate    *time.Time      `json:"end_date"`
+	Metadata   datatypes.JSON  `json:"metadata"`
+	Extra      *datatypes.JSON `json:"extra"`
+	Raw        *[]byte         `json:"raw"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+	unexported string
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestDiffModels(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	end := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	extra := datatypes.JSON(`{"a":1}`)
+	raw := []byte("xyz")
+
+	tests := []struct {
+		name     string
+		oldModel interface{}
+		newModel interface{}
+		expected map[string]FieldChange
+	}{
+		{
+			name:     "no changes",
+			oldModel: &testModel{ID: "1", Name: "A"},
+			newModel: &testModel{ID: "1", Name: "A"},
+			expected: map[string]FieldChange{},
+		},
+		{
+			name:     "scalar fields",
+			oldModel: &testModel{ID: "1", Name: "A", Capital: 1},
+			newModel: &testModel{ID: "1", Name: "B", Capital: 2},
+			expected: map[string]FieldChange{
+				"name":    {Old: "A", New: "B"},
+				"capital": {Old: float64(1), New: float64(2)},
+			},
+		},
+		{
+			name:     "nested structs, relations, maps and json:\"-\" are ignored",
+			oldModel: &testModel{ID: "1", Parent: &testParent{Name: "P1"}, Owner: testParent{Name: "O1"}, Children: []testChild{{Name: "C1"}}, Tags: map[string]bool{"a": true}, Internal: "x", unexported: "x"},
+			newModel: &testModel{ID: "1", Parent: &testParent{Name: "P2"}, Owner: testParent{Name: "O2"}, Children: []testChild{{Name: "C2"}}, Tags: map[string]bool{"b": true}, Internal: "y", unexported: "y"},
+			expected: map[string]FieldChange{},
+		},
+		{
+			name:     "pointer fields",
+			oldModel: &testModel{ID: "1"},
+			newModel: &testModel{ID: "1", ParentID: strPtr("p1")},
+			expected: map[string]FieldChange{
+				"parent_id": {Old: nil, New: "p1"},
+			},
+		},
+		{
+			name:     "time values formatted as RFC3339 and zero time as nil",
+			oldModel: &testModel{ID: "1", CreatedAt: start},
+			newModel: &testModel{ID: "1", StartDate: start, EndDate: &end, CreatedAt: end, UpdatedAt: end},
+			expected: map[string]FieldChange{
+				"start_date": {Old: nil, New: "2025-01-02T03:04:05Z"},
+				"end_date":   {Old: nil, New: "2026-01-02T00:00:00Z"},
+			},
+		},
+		{
+			name:     "sensitive fields are masked",
+			oldModel: &testModel{ID: "1", NPWP: "012345678901234", Password: "oldsecret", Token: strPtr("tok-123456")},
+			newModel: &testModel{ID: "1", NPWP: "012345678909999", Password: "newsecret", Token: nil},
+			expected: map[string]FieldChange{
+				"npwp":     {Old: "***********1234", New: "***********9999"},
+				"password": {Old: "*****cret", New: "*****cret"},
+				"token":    {Old: "******3456", New: nil},
+			},
+		},
+		{
+			name:     "json metadata compared per key",
+			oldModel: &testModel{ID: "1", Metadata: datatypes.JSON(`{"expiry_date":"2025-01-01","npwp":"012345678901234"}`)},
+			newModel: &testModel{ID: "1", Metadata: datatypes.JSON(`{"expiry_date":"2026-01-01","npwp":"012345678901234","note":"x"}`)},
+			expected: map[string]FieldChange{
+				"metadata.expiry_date": {Old: "2025-01-01", New: "2026-01-01"},
+				"metadata.note":        {Old: nil, New: "x"},
+			},
+		},
+		{
+			name:     "nil byte slice pointers do not panic",
+			oldModel: &testModel{ID: "1"},
+			newModel: &testModel{ID: "1", Extra: &extra, Raw: &raw},
+			expected: map[string]FieldChange{
+				"extra.a": {Old: nil, New: float64(1)},
+				"raw":     {Old: nil, New: "xyz"},
+			},
+		},
+		{
+			name:     "nil models",
+			oldModel: (*testModel)(nil),
+			newModel: &testModel{ID: "1"},
+			expected: map[string]FieldChange{
+				"id":         {Old: nil, New: "1"},
+				"name":       {Old: nil, New: ""},
+				"capital":    {Old: nil, New: float64(0)},
+				"npwp":       {Old: nil, New: ""},
+				"password":   {Old: nil, New: ""},
+				"parent_id":  {Old: nil, New: nil},
+				"token":      {Old: nil, New: nil},
+				"start_date": {Old: nil, New: nil},
+				"end_date":   {Old: nil, New: nil},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NotPanics(t, func() {
+				assert.Equal(t, tt.expected, DiffModels(tt.oldModel, tt.newModel))
+			})
+		})
+	}
+}
+
+func TestMaskValue(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected interface{}
+	}{
+		{nil, nil},
+		{"", ""},
+		{"123", "***"},
+		{"1234", "****"},
+		{"3174012345678901", "************8901"},
+		{12345678, "****5678"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, MaskValue(tt.value))
+	}
+}
+
+func TestSnapshotModel(t *testing.T) {
+	snapshot := SnapshotModel(testModel{ID: "1", Name: "A", NPWP: "012345678901234", Metadata: datatypes.JSON(`{"token":"abcdefgh"}`)})
+	assert.Equal(t, map[string]interface{}{
+		"id":             "1",
+		"name":           "A",
+		"capital":        float64(0),
+		"npwp":           "***********1234",
+		"metadata.token": "****efgh",
+	}, snapshot)
+}
+
+func TestDiffCollection(t *testing.T) {
+	key := func(c testChild) string { return c.Name }
+
+	tests := []struct {
+		name     string
+		oldItems []testChild
+		newItems []testChild
+		expected map[string]interface{}
+	}{
+		{
+			name:     "reorder is not a change",
+			oldItems: []testChild{{ID: "1", Name: "A"}, {ID: "2", Name: "B"}},
+			newItems: []testChild{{ID: "2", Name: "B"}, {ID: "1", Name: "A"}},
+			expected: map[string]interface{}{},
+		},
+		{
+			name:     "added and removed",
+			oldItems: []testChild{{ID: "1", Name: "A"}},
+			newItems: []testChild{{ID: "2", Name: "B"}},
+			expected: map[string]interface{}{
+				"child_added_0":   map[string]interface{}{"action": ChangeAdded, "name": "B"},
+				"child_removed_0": map[string]interface{}{"action": ChangeRemoved, "name": "A"},
+			},
+		},
+		{
+			name:     "recreated item with new ID is not a change",
+			oldItems: []testChild{{ID: "1", Name: "A"}},
+			newItems: []testChild{{ID: "9", Name: "A"}},
+			expected: map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := make(map[string]interface{})
+			FlattenCollectionChanges("child", DiffCollection(tt.oldItems, tt.newItems, key), changes)
+			assert.Equal(t, tt.expected, changes)
+		})
+	}
+
+	t.Run("updated", func(t *testing.T) {
+		type share struct {
+			ID      string  `json:"id"`
+			Name    string  `json:"name"`
+			Percent float64 `json:"percent"`
+		}
+		oldItems := []share{{ID: "1", Name: "A", Percent: 40}, {ID: "2", Name: "B", Percent: 60}}
+		newItems := []share{{ID: "2", Name: "B", Percent: 50}, {ID: "1", Name: "A", Percent: 40}}
+		items := DiffCollection(oldItems, newItems, func(s share) string { return s.Name })
+		assert.Equal(t, []CollectionChange{{
+			Action:   ChangeUpdated,
+			Index:    0,
+			EntityID: "2",
+			Changes:  map[string]FieldChange{"percent": {Old: float64(60), New: float64(50)}},
+		}}, items)
+
+		changes := make(map[string]interface{})
+		FlattenCollectionChanges("shareholder", items, changes)
+		assert.Equal(t, map[string]interface{}{
+			"shareholder_0_percent": FieldChange{Old: float64(60), New: float64(50)},
+		}, changes)
+	})
+}
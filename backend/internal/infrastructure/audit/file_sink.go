@@ -0,0 +1,76 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileSink menulis audit event ke file JSONL (satu event JSON per baris), cocok untuk di-tail oleh agent SIEM
+type FileSink struct {
+	path string
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink membuat file sink baru. Direktori dibuat otomatis jika belum ada.
+func NewFileSink(path string) (*FileSink, error) {
+	if path == "" {
+		return nil, fmt.Errorf("file sink path is required")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, fmt.Errorf("failed to create audit sink directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) // #nosec G304 - path dari konfigurasi server
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit sink file: %w", err)
+	}
+	return &FileSink{path: path, file: file}, nil
+}
+
+// NewFileSinkFromEnv membuat file sink dari AUDIT_FILE_PATH (default: logs/audit-events.jsonl)
+func NewFileSinkFromEnv() (*FileSink, error) {
+	path := os.Getenv("AUDIT_FILE_PATH")
+	if path == "" {
+		path = filepath.Join("logs", "audit-events.jsonl")
+	}
+	return NewFileSink(path)
+}
+
+// Name mengembalikan nama sink
+func (s *FileSink) Name() string {
+	return SinkFile
+}
+
+// Send menulis satu baris JSON ke file
+func (s *FileSink) Send(_ context.Context, event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return fmt.Errorf("file sink is closed")
+	}
+	_, err = s.file.Write(line)
+	return err
+}
+
+// Close menutup file
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
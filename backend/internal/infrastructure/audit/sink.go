@@ -0,0 +1,546 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Event adalah representasi audit event yang dikirim ke sink eksternal (SIEM)
+type Event struct {
+	ID         string                 `json:"id"`
+	Timestamp  time.Time              `json:"timestamp"`
+	UserID     string                 `json:"user_id,omitempty"`
+	Username   string                 `json:"username,omitempty"`
+	Action     string                 `json:"action"`
+	Resource   string                 `json:"resource,omitempty"`
+	ResourceID string                 `json:"resource_id,omitempty"`
+	IPAddress  string                 `json:"ip_address,omitempty"`
+	UserAgent  string                 `json:"user_agent,omitempty"`
+	Status     string                 `json:"status"`
+	LogType    string                 `json:"log_type"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+}
+
+// Sink adalah tujuan pengiriman audit event (syslog, webhook, file, dll)
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, event Event) error
+	Close() error
+}
+
+// SinkOptions mengatur perilaku buffer dan retry untuk setiap sink
+type SinkOptions struct {
+	BufferSize  int           // Kapasitas antrian per sink
+	MaxAttempts int           // Jumlah percobaan kirim sebelum masuk dead-letter queue
+	BaseBackoff time.Duration // Jeda retry awal (dikali 2 setiap percobaan)
+	MaxBackoff  time.Duration // Batas atas jeda retry
+	SendTimeout time.Duration // Timeout untuk satu kali pengiriman
+}
+
+// DefaultSinkOptions mengembalikan konfigurasi default buffer dan retry
+func DefaultSinkOptions() SinkOptions {
+	return SinkOptions{
+		BufferSize:  1000,
+		MaxAttempts: 5,
+		BaseBackoff: 500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+		SendTimeout: 10 * time.Second,
+	}
+}
+
+// SinkHealth berisi status pengiriman sebuah sink untuk endpoint health/lag
+type SinkHealth struct {
+	Name          string     `json:"name"`
+	Healthy       bool       `json:"healthy"`
+	QueueLength   int        `json:"queue_length"`
+	QueueCapacity int        `json:"queue_capacity"`
+	Delivered     int64      `json:"delivered"`
+	Retries       int64      `json:"retries"`
+	DeadLettered  int64      `json:"dead_lettered"`
+	Dropped       int64      `json:"dropped"`     // Event yang dibuang karena antrian dead-letter juga penuh
+	LagSeconds    float64    `json:"lag_seconds"` // Umur event tertua yang belum terkirim
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty"`
+	LastErrorAt   *time.Time `json:"last_error_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+}
+
+type queuedEvent struct {
+	event      Event
+	enqueuedAt time.Time
+}
+
+// sinkWorker mengelola antrian dan pengiriman async untuk satu sink
+type sinkWorker struct {
+	sink        Sink
+	opts        SinkOptions
+	queue       chan queuedEvent
+	deadLetters chan domain.AuditSinkDeadLetter // nil jika dead-letter queue tidak dikonfigurasi
+	done        chan struct{}
+	stopped     chan struct{}
+
+	delivered    int64
+	retries      int64
+	deadLettered int64
+	dropped      int64
+
+	mu            sync.Mutex
+	pending       []time.Time // enqueuedAt event yang belum selesai diproses, urut sesuai antrian (index 0 = head)
+	lastSuccessAt time.Time
+	lastErrorAt   time.Time
+	lastError     string
+}
+
+// SinkDispatcher meneruskan audit event ke semua sink yang terdaftar
+type SinkDispatcher struct {
+	workers []*sinkWorker
+	dlq     *gorm.DB
+
+	// Penulisan dead-letter ke database dilakukan satu goroutine terpisah,
+	// sehingga Dispatch (dipanggil dari goroutine request) tidak pernah menunggu insert DB
+	deadLetters chan domain.AuditSinkDeadLetter
+	dlqDone     chan struct{}
+	dlqStopped  chan struct{}
+
+	closeOnce sync.Once
+}
+
+// Instance dispatcher global (nil jika tidak ada sink yang dikonfigurasi)
+var sinkDispatcher *SinkDispatcher
+
+// NewSinkDispatcher membuat dispatcher dan langsung menjalankan worker untuk setiap sink.
+// dlq digunakan untuk menyimpan event yang gagal dikirim (boleh nil, event hanya di-log).
+func NewSinkDispatcher(dlq *gorm.DB, opts SinkOptions, sinks ...Sink) *SinkDispatcher {
+	d := &SinkDispatcher{dlq: dlq}
+	if dlq != nil {
+		d.deadLetters = make(chan domain.AuditSinkDeadLetter, opts.BufferSize)
+		d.dlqDone = make(chan struct{})
+		d.dlqStopped = make(chan struct{})
+		go d.runDeadLetterWriter()
+	}
+	for _, s := range sinks {
+		w := &sinkWorker{
+			sink:        s,
+			opts:        opts,
+			queue:       make(chan queuedEvent, opts.BufferSize),
+			deadLetters: d.deadLetters,
+			done:        make(chan struct{}),
+			stopped:     make(chan struct{}),
+		}
+		d.workers = append(d.workers, w)
+		go w.run()
+	}
+	return d
+}
+
+// Dispatch memasukkan event ke antrian semua sink tanpa blocking.
+// Jika antrian penuh, event diteruskan ke antrian dead-letter (juga non-blocking) agar tidak hilang.
+func (d *SinkDispatcher) Dispatch(event Event) {
+	for _, w := range d.workers {
+		item := queuedEvent{event: event, enqueuedAt: time.Now()}
+		w.mu.Lock()
+		select {
+		case w.queue <- item:
+			w.pending = append(w.pending, item.enqueuedAt)
+			w.mu.Unlock()
+		default:
+			w.mu.Unlock()
+			w.deadLetter(event, 0, fmt.Errorf("sink buffer full"))
+		}
+	}
+}
+
+// Health mengembalikan status semua sink
+func (d *SinkDispatcher) Health() []SinkHealth {
+	result := make([]SinkHealth, 0, len(d.workers))
+	for _, w := range d.workers {
+		result = append(result, w.health())
+	}
+	return result
+}
+
+// Close menghentikan semua worker setelah antrian dikosongkan (atau timeout tercapai)
+func (d *SinkDispatcher) Close(timeout time.Duration) {
+	d.closeOnce.Do(func() { d.close(timeout) })
+}
+
+func (d *SinkDispatcher) close(timeout time.Duration) {
+	deadline := time.After(timeout)
+	for _, w := range d.workers {
+		close(w.done)
+	}
+	for _, w := range d.workers {
+		select {
+		case <-w.stopped:
+		case <-deadline:
+		}
+		_ = w.sink.Close()
+	}
+	if d.dlqDone != nil {
+		close(d.dlqDone)
+		select {
+		case <-d.dlqStopped:
+		case <-deadline:
+		}
+	}
+}
+
+// runDeadLetterWriter menyimpan dead-letter ke database secara berurutan
+func (d *SinkDispatcher) runDeadLetterWriter() {
+	defer close(d.dlqStopped)
+	for {
+		select {
+		case letter := <-d.deadLetters:
+			d.storeDeadLetter(letter)
+		case <-d.dlqDone:
+			// Simpan sisa dead-letter sebelum berhenti
+			for {
+				select {
+				case letter := <-d.deadLetters:
+					d.storeDeadLetter(letter)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (d *SinkDispatcher) storeDeadLetter(letter domain.AuditSinkDeadLetter) {
+	if err := d.dlq.Create(&letter).Error; err != nil {
+		logger.GetLogger().Error("Failed to store audit event in dead-letter queue",
+			zap.String("sink", letter.Sink),
+			zap.String("event_id", letter.EventID),
+			zap.Error(err),
+		)
+	}
+}
+
+// ReplayDeadLetters mengirim ulang event di dead-letter queue untuk sink tertentu (semua sink jika kosong).
+// Mengembalikan jumlah event yang berhasil dikirim ulang.
+func (d *SinkDispatcher) ReplayDeadLetters(sinkName string, limit int) (int, error) {
+	if d.dlq == nil {
+		return 0, fmt.Errorf("dead-letter queue is not configured")
+	}
+
+	query := d.dlq.Where("replayed_at IS NULL")
+	if sinkName != "" {
+		query = query.Where("sink = ?", sinkName)
+	}
+	var letters []domain.AuditSinkDeadLetter
+	if err := query.Order("created_at ASC").Limit(limit).Find(&letters).Error; err != nil {
+		return 0, err
+	}
+
+	replayed := 0
+	for _, letter := range letters {
+		w := d.worker(letter.Sink)
+		if w == nil {
+			continue
+		}
+		var event Event
+		if err := json.Unmarshal([]byte(letter.Payload), &event); err != nil {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), w.opts.SendTimeout)
+		err := w.sink.Send(ctx, event)
+		cancel()
+		if err != nil {
+			d.dlq.Model(&letter).Updates(map[string]interface{}{
+				"last_error": err.Error(),
+				"attempts":   letter.Attempts + 1,
+			})
+			continue
+		}
+		now := time.Now()
+		d.dlq.Model(&letter).Update("replayed_at", &now)
+		w.markSuccess()
+		replayed++
+	}
+	return replayed, nil
+}
+
+func (d *SinkDispatcher) worker(name string) *sinkWorker {
+	for _, w := range d.workers {
+		if w.sink.Name() == name {
+			return w
+		}
+	}
+	return nil
+}
+
+func (w *sinkWorker) run() {
+	defer close(w.stopped)
+	for {
+		select {
+		case item := <-w.queue:
+			w.deliver(item)
+		case <-w.done:
+			// Kosongkan sisa antrian sebelum berhenti
+			for {
+				select {
+				case item := <-w.queue:
+					w.deliver(item)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// deliver mengirim satu event dengan retry exponential backoff, lalu ke dead-letter queue jika tetap gagal
+func (w *sinkWorker) deliver(item queuedEvent) {
+	defer func() {
+		// Event selesai diproses (terkirim atau masuk DLQ), head antrian bergeser ke event berikutnya
+		w.mu.Lock()
+		if len(w.pending) > 0 {
+			w.pending = w.pending[1:]
+		}
+		w.mu.Unlock()
+	}()
+
+	backoff := w.opts.BaseBackoff
+	var lastErr error
+	for attempt := 1; attempt <= w.opts.MaxAttempts; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), w.opts.SendTimeout)
+		lastErr = w.sink.Send(ctx, item.event)
+		cancel()
+		if lastErr == nil {
+			w.markSuccess()
+			return
+		}
+
+		w.markError(lastErr)
+		if attempt == w.opts.MaxAttempts {
+			break
+		}
+		atomic.AddInt64(&w.retries, 1)
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > w.opts.MaxBackoff {
+			backoff = w.opts.MaxBackoff
+		}
+	}
+
+	w.deadLetter(item.event, w.opts.MaxAttempts, lastErr)
+}
+
+func (w *sinkWorker) markSuccess() {
+	atomic.AddInt64(&w.delivered, 1)
+	w.mu.Lock()
+	w.lastSuccessAt = time.Now()
+	w.mu.Unlock()
+}
+
+func (w *sinkWorker) markError(err error) {
+	w.mu.Lock()
+	w.lastErrorAt = time.Now()
+	w.lastError = err.Error()
+	w.mu.Unlock()
+}
+
+func (w *sinkWorker) deadLetter(event Event, attempts int, cause error) {
+	atomic.AddInt64(&w.deadLettered, 1)
+	zapLog := logger.GetLogger()
+
+	errMsg := ""
+	if cause != nil {
+		errMsg = cause.Error()
+	}
+	if w.deadLetters == nil {
+		zapLog.Error("Audit event dropped, dead-letter queue not configured",
+			zap.String("sink", w.sink.Name()),
+			zap.String("event_id", event.ID),
+			zap.String("error", errMsg),
+		)
+		return
+	}
+
+	payload, _ := json.Marshal(event)
+	letter := domain.AuditSinkDeadLetter{
+		ID:          uuid.GenerateUUID(),
+		Sink:        w.sink.Name(),
+		EventID:     event.ID,
+		Payload:     string(payload),
+		LastError:   errMsg,
+		Attempts:    attempts,
+		CreatedAt:   time.Now(),
+		EventTimeAt: event.Timestamp,
+	}
+	select {
+	case w.deadLetters <- letter:
+	default:
+		// Antrian dead-letter penuh (database lambat/down): buang event dan hitung agar terlihat di health
+		atomic.AddInt64(&w.dropped, 1)
+		zapLog.Error("Audit event dropped, dead-letter queue full",
+			zap.String("sink", w.sink.Name()),
+			zap.String("event_id", event.ID),
+			zap.String("error", errMsg),
+		)
+	}
+}
+
+func (w *sinkWorker) health() SinkHealth {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	h := SinkHealth{
+		Name:          w.sink.Name(),
+		QueueLength:   len(w.queue),
+		QueueCapacity: cap(w.queue),
+		Delivered:     atomic.LoadInt64(&w.delivered),
+		Retries:       atomic.LoadInt64(&w.retries),
+		DeadLettered:  atomic.LoadInt64(&w.deadLettered),
+		Dropped:       atomic.LoadInt64(&w.dropped),
+		LastError:     w.lastError,
+	}
+	if len(w.pending) > 0 {
+		h.LagSeconds = time.Since(w.pending[0]).Seconds()
+	}
+	if !w.lastSuccessAt.IsZero() {
+		t := w.lastSuccessAt
+		h.LastSuccessAt = &t
+	}
+	if !w.lastErrorAt.IsZero() {
+		t := w.lastErrorAt
+		h.LastErrorAt = &t
+	}
+
+	// Sehat jika error terakhir sudah "tertutup" oleh pengiriman sukses dan antrian tidak hampir penuh
+	recovered := w.lastErrorAt.IsZero() || w.lastSuccessAt.After(w.lastErrorAt)
+	h.Healthy = recovered && h.QueueLength < h.QueueCapacity*9/10
+	return h
+}
+
+// InitAuditSinks membaca konfigurasi sink dari environment dan menjalankan dispatcher.
+// AUDIT_SINKS berisi daftar sink dipisah koma: syslog, webhook, file. Kosong = sink nonaktif.
+func InitAuditSinks(dlq *gorm.DB) {
+	zapLog := logger.GetLogger()
+
+	names := strings.TrimSpace(os.Getenv("AUDIT_SINKS"))
+	if names == "" {
+		zapLog.Info("Audit sinks disabled (AUDIT_SINKS not set)")
+		return
+	}
+
+	opts := DefaultSinkOptions()
+	if v, err := strconv.Atoi(os.Getenv("AUDIT_SINK_BUFFER_SIZE")); err == nil && v > 0 {
+		opts.BufferSize = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("AUDIT_SINK_MAX_ATTEMPTS")); err == nil && v > 0 {
+		opts.MaxAttempts = v
+	}
+
+	var sinks []Sink
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		var (
+			sink Sink
+			err  error
+		)
+		switch name {
+		case "":
+			continue
+		case SinkSyslog:
+			sink, err = NewSyslogSinkFromEnv()
+		case SinkWebhook:
+			sink, err = NewWebhookSinkFromEnv()
+		case SinkFile:
+			sink, err = NewFileSinkFromEnv()
+		default:
+			err = fmt.Errorf("unknown audit sink")
+		}
+		if err != nil {
+			zapLog.Error("Failed to initialize audit sink", zap.String("sink", name), zap.Error(err))
+			continue
+		}
+		sinks = append(sinks, sink)
+	}
+
+	if len(sinks) == 0 {
+		zapLog.Warn("No audit sink initialized", zap.String("audit_sinks", names))
+		return
+	}
+
+	sinkDispatcher = NewSinkDispatcher(dlq, opts, sinks...)
+
+	sinkNames := make([]string, 0, len(sinks))
+	for _, s := range sinks {
+		sinkNames = append(sinkNames, s.Name())
+	}
+	zapLog.Info("Audit sinks initialized",
+		zap.Strings("sinks", sinkNames),
+		zap.Int("buffer_size", opts.BufferSize),
+		zap.Int("max_attempts", opts.MaxAttempts),
+	)
+}
+
+// CloseSinks mengirim sisa antrian ke sink, menyimpan dead-letter yang tertunda, lalu menutup sink.
+// Dipanggil saat graceful shutdown agar event yang belum terkirim tidak hilang.
+func CloseSinks(timeout time.Duration) {
+	if sinkDispatcher == nil {
+		return
+	}
+	sinkDispatcher.Close(timeout)
+}
+
+// GetSinkHealth mengembalikan status semua sink (kosong jika sink nonaktif)
+func GetSinkHealth() []SinkHealth {
+	if sinkDispatcher == nil {
+		return []SinkHealth{}
+	}
+	return sinkDispatcher.Health()
+}
+
+// SinksEnabled mengecek apakah ada sink eksternal yang aktif
+func SinksEnabled() bool {
+	return sinkDispatcher != nil
+}
+
+// ReplayDeadLetters mengirim ulang dead-letter events melalui dispatcher global
+func ReplayDeadLetters(sinkName string, limit int) (int, error) {
+	if sinkDispatcher == nil {
+		return 0, fmt.Errorf("audit sinks are not enabled")
+	}
+	return sinkDispatcher.ReplayDeadLetters(sinkName, limit)
+}
+
+// dispatchToSinks meneruskan event ke sink eksternal jika dispatcher aktif
+func dispatchToSinks(userID, username, action, resource, resourceID, ipAddress, userAgent, status string, details map[string]interface{}) {
+	if sinkDispatcher == nil {
+		return
+	}
+
+	logType := LogTypeUserAction
+	if action == "system_error" || action == "database_error" || action == "validation_error" || action == "panic" {
+		logType = LogTypeTechnicalError
+	}
+
+	sinkDispatcher.Dispatch(Event{
+		ID:         uuid.GenerateUUID(),
+		Timestamp:  time.Now().UTC(),
+		UserID:     userID,
+		Username:   username,
+		Action:     action,
+		Resource:   resource,
+		ResourceID: resourceID,
+		IPAddress:  ipAddress,
+		UserAgent:  userAgent,
+		Status:     status,
+		LogType:    logType,
+		Details:    details,
+	})
+}
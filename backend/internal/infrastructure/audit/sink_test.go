@@ -0,0 +1,331 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEvent() Event {
+	return Event{
+		ID:         "evt-1",
+		Timestamp:  time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		UserID:     "user-1",
+		Username:   "admin\"holding]",
+		Action:     ActionUpdateCompany,
+		Resource:   ResourceCompany,
+		ResourceID: "company-1",
+		IPAddress:  "10.0.0.1",
+		Status:     StatusSuccess,
+		LogType:    LogTypeUserAction,
+		Details:    map[string]interface{}{"name": "PT Test"},
+	}
+}
+
+// readOctetFrame membaca satu frame syslog octet-counting ("<len> <msg>")
+func readOctetFrame(r *bufio.Reader) (string, error) {
+	lenStr, err := r.ReadString(' ')
+	if err != nil {
+		return "", err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(lenStr))
+	if err != nil {
+		return "", err
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// TestSyslogSink_LocalListener mengirim event ke listener TCP lokal dan memverifikasi format RFC 5424
+func TestSyslogSink_LocalListener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		msg, err := readOctetFrame(bufio.NewReader(conn))
+		if err == nil {
+			received <- msg
+		}
+	}()
+
+	sink, err := NewSyslogSink(SyslogSinkConfig{Address: ln.Addr().String(), Hostname: "dms-host"})
+	require.NoError(t, err)
+	defer sink.Close()
+
+	require.NoError(t, sink.Send(context.Background(), testEvent()))
+
+	select {
+	case msg := <-received:
+		// PRI = facility 13 (log audit) * 8 + severity 6 (info) = 110
+		assert.True(t, strings.HasPrefix(msg, "<110>1 2025-01-02T03:04:05.000000Z dms-host pedeve-dms "), msg)
+		assert.Contains(t, msg, " update_company [audit@32473 ")
+		assert.Contains(t, msg, `user="admin\"holding\]"`)
+		assert.Contains(t, msg, "\xEF\xBB\xBF{\"id\":\"evt-1\"")
+	case <-time.After(3 * time.Second):
+		t.Fatal("syslog listener did not receive message")
+	}
+}
+
+// TestWebhookSink_Signature memastikan request webhook membawa signature HMAC yang valid
+func TestWebhookSink_Signature(t *testing.T) {
+	secret := "s3cret"
+	var gotSignature, gotTimestamp string
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get(WebhookSignatureHeader)
+		gotTimestamp = r.Header.Get(WebhookTimestampHeader)
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	_, err := NewWebhookSink(WebhookSinkConfig{URL: server.URL, Secret: secret})
+	assert.Error(t, err, "plain http must be rejected unless explicitly allowed")
+
+	sink, err := NewWebhookSink(WebhookSinkConfig{URL: server.URL, Secret: secret, AllowHTTP: true})
+	require.NoError(t, err)
+	require.NoError(t, sink.Send(context.Background(), testEvent()))
+
+	assert.Equal(t, "sha256="+SignWebhookPayload(secret, gotTimestamp, gotBody), gotSignature)
+	assert.Contains(t, string(gotBody), `"resource_id":"company-1"`)
+}
+
+// TestFileSink_WritesJSONL memastikan setiap event ditulis sebagai satu baris JSON
+func TestFileSink_WritesJSONL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "audit.jsonl")
+	sink, err := NewFileSink(path)
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Send(context.Background(), testEvent()))
+	require.NoError(t, sink.Send(context.Background(), testEvent()))
+	require.NoError(t, sink.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 2)
+}
+
+type flakySink struct {
+	failures int
+	calls    int
+}
+
+func (f *flakySink) Name() string { return "flaky" }
+func (f *flakySink) Close() error { return nil }
+func (f *flakySink) Send(_ context.Context, _ Event) error {
+	f.calls++
+	if f.calls <= f.failures {
+		return errors.New("collector unavailable")
+	}
+	return nil
+}
+
+// TestSinkDispatcher_RetryAndDeadLetter memastikan retry berjalan dan event masuk DLQ setelah percobaan habis
+func TestSinkDispatcher_RetryAndDeadLetter(t *testing.T) {
+	db := helpers.SetupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.AuditSinkDeadLetter{}))
+
+	opts := SinkOptions{BufferSize: 10, MaxAttempts: 3, BaseBackoff: time.Millisecond, MaxBackoff: time.Millisecond, SendTimeout: time.Second}
+
+	t.Run("recovers after retry", func(t *testing.T) {
+		sink := &flakySink{failures: 2}
+		d := NewSinkDispatcher(db, opts, sink)
+		d.Dispatch(testEvent())
+		d.Close(2 * time.Second)
+
+		health := d.Health()[0]
+		assert.Equal(t, int64(1), health.Delivered)
+		assert.Equal(t, int64(2), health.Retries)
+		assert.Equal(t, int64(0), health.DeadLettered)
+		assert.True(t, health.Healthy)
+	})
+
+	t.Run("dead-letters and replays", func(t *testing.T) {
+		sink := &flakySink{failures: 3}
+		d := NewSinkDispatcher(db, opts, sink)
+		d.Dispatch(testEvent())
+		d.Close(2 * time.Second)
+
+		health := d.Health()[0]
+		assert.Equal(t, int64(1), health.DeadLettered)
+		assert.False(t, health.Healthy)
+
+		var letters []domain.AuditSinkDeadLetter
+		require.NoError(t, db.Where("sink = ?", "flaky").Find(&letters).Error)
+		require.Len(t, letters, 1)
+		assert.Equal(t, "evt-1", letters[0].EventID)
+		assert.Equal(t, 3, letters[0].Attempts)
+
+		replayed, err := d.ReplayDeadLetters("flaky", 10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, replayed)
+
+		var pending int64
+		db.Model(&domain.AuditSinkDeadLetter{}).Where("replayed_at IS NULL").Count(&pending)
+		assert.Equal(t, int64(0), pending)
+	})
+}
+
+// blockingSink menahan Send sampai release ditutup (mensimulasikan collector yang hang)
+type blockingSink struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingSink) Name() string { return "blocking" }
+func (b *blockingSink) Close() error { return nil }
+func (b *blockingSink) Send(ctx context.Context, _ Event) error {
+	select {
+	case b.started <- struct{}{}:
+	default:
+	}
+	<-b.release
+	return nil
+}
+
+// TestSinkDispatcher_BufferFull memastikan Dispatch tidak menunggu insert DLQ dan lag dihitung dari head antrian
+func TestSinkDispatcher_BufferFull(t *testing.T) {
+	db := helpers.SetupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.AuditSinkDeadLetter{}))
+
+	sink := &blockingSink{started: make(chan struct{}, 1), release: make(chan struct{})}
+	opts := SinkOptions{BufferSize: 1, MaxAttempts: 1, BaseBackoff: time.Millisecond, MaxBackoff: time.Millisecond, SendTimeout: time.Second}
+	d := NewSinkDispatcher(db, opts, sink)
+
+	d.Dispatch(testEvent())
+	<-sink.started
+	time.Sleep(50 * time.Millisecond)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		d.Dispatch(testEvent())
+	}
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+
+	// Event pertama masih tertahan di sink: lag = umurnya, bukan umur event terbaru
+	health := d.Health()[0]
+	assert.GreaterOrEqual(t, health.LagSeconds, 0.05)
+	assert.Equal(t, 1, health.QueueLength)
+	assert.Equal(t, int64(4), health.DeadLettered)
+
+	close(sink.release)
+	d.Close(2 * time.Second)
+
+	health = d.Health()[0]
+	assert.Equal(t, int64(2), health.Delivered)
+	assert.Zero(t, health.LagSeconds)
+
+	var stored int64
+	db.Model(&domain.AuditSinkDeadLetter{}).Where("sink = ?", "blocking").Count(&stored)
+	assert.Equal(t, int64(4)-health.Dropped, stored)
+}
+
+// TestSinkWorker_DropsWhenDeadLetterQueueFull memastikan event dibuang (dan dihitung) saat antrian DLQ penuh
+func TestSinkWorker_DropsWhenDeadLetterQueueFull(t *testing.T) {
+	w := &sinkWorker{
+		sink:        &flakySink{},
+		queue:       make(chan queuedEvent, 1),
+		deadLetters: make(chan domain.AuditSinkDeadLetter, 1),
+	}
+	w.deadLetter(testEvent(), 0, errors.New("sink buffer full"))
+	w.deadLetter(testEvent(), 0, errors.New("sink buffer full"))
+
+	assert.Len(t, w.deadLetters, 1)
+	health := w.health()
+	assert.Equal(t, int64(2), health.DeadLettered)
+	assert.Equal(t, int64(1), health.Dropped)
+}
+
+// recordingSink mencatat event yang diterima dengan jeda kecil agar antrian belum kosong saat shutdown
+type recordingSink struct {
+	mu     sync.Mutex
+	events []string
+	closed bool
+	fail   bool
+}
+
+func (r *recordingSink) Name() string { return "recording" }
+func (r *recordingSink) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	return nil
+}
+func (r *recordingSink) Send(_ context.Context, event Event) error {
+	time.Sleep(5 * time.Millisecond)
+	if r.fail {
+		return errors.New("collector unavailable")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event.ID)
+	return nil
+}
+
+// TestCloseSinks_DrainsQueue memastikan shutdown mengirim sisa antrian dan menyimpan dead-letter yang tertunda
+func TestCloseSinks_DrainsQueue(t *testing.T) {
+	db := helpers.SetupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.AuditSinkDeadLetter{}))
+	opts := SinkOptions{BufferSize: 50, MaxAttempts: 1, BaseBackoff: time.Millisecond, MaxBackoff: time.Millisecond, SendTimeout: time.Second}
+
+	previous := sinkDispatcher
+	defer func() { sinkDispatcher = previous }()
+
+	t.Run("delivers queued events", func(t *testing.T) {
+		sink := &recordingSink{}
+		sinkDispatcher = NewSinkDispatcher(db, opts, sink)
+		for i := 0; i < 20; i++ {
+			dispatchToSinks("user-1", "admin", ActionUpdateCompany, ResourceCompany, "company-1", "", "", StatusSuccess, nil)
+		}
+		CloseSinks(5 * time.Second)
+
+		sink.mu.Lock()
+		defer sink.mu.Unlock()
+		assert.Len(t, sink.events, 20)
+		assert.True(t, sink.closed)
+		assert.Zero(t, sinkDispatcher.Health()[0].QueueLength)
+
+		// Close berikutnya tidak panic
+		CloseSinks(time.Second)
+	})
+
+	t.Run("stores pending dead letters", func(t *testing.T) {
+		sink := &recordingSink{fail: true}
+		sinkDispatcher = NewSinkDispatcher(db, opts, sink)
+		for i := 0; i < 20; i++ {
+			dispatchToSinks("user-1", "admin", ActionUpdateCompany, ResourceCompany, "company-1", "", "", StatusSuccess, nil)
+		}
+		CloseSinks(5 * time.Second)
+
+		var stored int64
+		db.Model(&domain.AuditSinkDeadLetter{}).Where("sink = ?", "recording").Count(&stored)
+		assert.Equal(t, int64(20), stored)
+	})
+}
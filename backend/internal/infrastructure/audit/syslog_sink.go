@@ -0,0 +1,223 @@
+package audit
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Nama sink yang dikenali oleh AUDIT_SINKS
+const (
+	SinkSyslog  = "syslog"
+	SinkWebhook = "webhook"
+	SinkFile    = "file"
+)
+
+const (
+	syslogFacilityAudit = 13    // RFC 5424: "log audit"
+	syslogEnterpriseID  = 32473 // Private Enterprise Number untuk contoh/dokumentasi (RFC 5612)
+	syslogNilValue      = "-"
+)
+
+// SyslogSinkConfig berisi konfigurasi sink syslog RFC 5424
+type SyslogSinkConfig struct {
+	Address            string // host:port collector syslog
+	UseTLS             bool   // RFC 5425 (syslog over TLS)
+	CAFile             string // CA tambahan untuk verifikasi sertifikat collector
+	InsecureSkipVerify bool   // Hanya untuk testing lokal
+	AppName            string
+	Hostname           string
+	DialTimeout        time.Duration
+}
+
+// SyslogSink mengirim audit event sebagai pesan RFC 5424 melalui TCP/TLS dengan octet-counting framing (RFC 6587)
+type SyslogSink struct {
+	cfg       SyslogSinkConfig
+	tlsConfig *tls.Config
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSyslogSink membuat syslog sink baru. Koneksi dibuka saat event pertama dikirim.
+func NewSyslogSink(cfg SyslogSinkConfig) (*SyslogSink, error) {
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("syslog address is required")
+	}
+	if cfg.AppName == "" {
+		cfg.AppName = "pedeve-dms"
+	}
+	if cfg.Hostname == "" {
+		if host, err := os.Hostname(); err == nil {
+			cfg.Hostname = host
+		} else {
+			cfg.Hostname = syslogNilValue
+		}
+	}
+	if cfg.DialTimeout == 0 {
+		cfg.DialTimeout = 5 * time.Second
+	}
+
+	sink := &SyslogSink{cfg: cfg}
+	if cfg.UseTLS {
+		tlsConfig := &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: cfg.InsecureSkipVerify, // #nosec G402 - hanya aktif jika diset eksplisit untuk testing
+		}
+		if cfg.CAFile != "" {
+			caPEM, err := os.ReadFile(cfg.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read syslog CA file: %w", err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(caPEM) {
+				return nil, fmt.Errorf("invalid syslog CA file")
+			}
+			tlsConfig.RootCAs = pool
+		}
+		sink.tlsConfig = tlsConfig
+	}
+	return sink, nil
+}
+
+// NewSyslogSinkFromEnv membuat syslog sink dari environment variables
+func NewSyslogSinkFromEnv() (*SyslogSink, error) {
+	return NewSyslogSink(SyslogSinkConfig{
+		Address:            os.Getenv("AUDIT_SYSLOG_ADDR"),
+		UseTLS:             os.Getenv("AUDIT_SYSLOG_TLS") == "true",
+		CAFile:             os.Getenv("AUDIT_SYSLOG_CA_FILE"),
+		InsecureSkipVerify: os.Getenv("AUDIT_SYSLOG_TLS_SKIP_VERIFY") == "true",
+		AppName:            os.Getenv("AUDIT_SYSLOG_APP_NAME"),
+	})
+}
+
+// Name mengembalikan nama sink
+func (s *SyslogSink) Name() string {
+	return SinkSyslog
+}
+
+// Send mengirim satu event. Jika koneksi putus, koneksi ditutup dan dibuka ulang pada percobaan berikutnya.
+func (s *SyslogSink) Send(ctx context.Context, event Event) error {
+	msg, err := FormatRFC5424(event, s.cfg.Hostname, s.cfg.AppName)
+	if err != nil {
+		return err
+	}
+	frame := fmt.Sprintf("%d %s", len(msg), msg)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		conn, err := s.dial(ctx)
+		if err != nil {
+			return fmt.Errorf("syslog dial failed: %w", err)
+		}
+		s.conn = conn
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = s.conn.SetWriteDeadline(deadline)
+	}
+	if _, err := s.conn.Write([]byte(frame)); err != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+		return fmt.Errorf("syslog write failed: %w", err)
+	}
+	return nil
+}
+
+// Close menutup koneksi ke collector
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+func (s *SyslogSink) dial(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: s.cfg.DialTimeout}
+	if s.tlsConfig == nil {
+		return dialer.DialContext(ctx, "tcp", s.cfg.Address)
+	}
+	tlsDialer := &tls.Dialer{NetDialer: dialer, Config: s.tlsConfig}
+	return tlsDialer.DialContext(ctx, "tcp", s.cfg.Address)
+}
+
+// FormatRFC5424 memformat event menjadi pesan syslog RFC 5424:
+// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [STRUCTURED-DATA] MSG
+// MSG berisi event lengkap dalam JSON (UTF-8 dengan BOM sesuai RFC 5424 section 6.4).
+func FormatRFC5424(event Event, hostname, appName string) (string, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+
+	pri := syslogFacilityAudit*8 + syslogSeverity(event.Status)
+	sd := fmt.Sprintf("[audit@%d eventId=\"%s\" user=\"%s\" resource=\"%s\" resourceId=\"%s\" status=\"%s\" ip=\"%s\"]",
+		syslogEnterpriseID,
+		escapeSDValue(event.ID),
+		escapeSDValue(event.Username),
+		escapeSDValue(event.Resource),
+		escapeSDValue(event.ResourceID),
+		escapeSDValue(event.Status),
+		escapeSDValue(event.IPAddress),
+	)
+
+	return fmt.Sprintf("<%d>1 %s %s %s %d %s %s \xEF\xBB\xBF%s",
+		pri,
+		event.Timestamp.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		syslogHeaderField(hostname, 255),
+		syslogHeaderField(appName, 48),
+		os.Getpid(),
+		syslogHeaderField(event.Action, 32),
+		sd,
+		body,
+	), nil
+}
+
+// syslogSeverity memetakan status audit ke severity syslog
+func syslogSeverity(status string) int {
+	switch status {
+	case StatusError:
+		return 3 // Error
+	case StatusFailure:
+		return 4 // Warning
+	default:
+		return 6 // Informational
+	}
+}
+
+// syslogHeaderField memastikan field header hanya berisi PRINTUSASCII tanpa spasi dan tidak melebihi batas panjang
+func syslogHeaderField(value string, maxLen int) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r > 32 && r < 127 {
+			b.WriteRune(r)
+		}
+	}
+	result := b.String()
+	if result == "" {
+		return syslogNilValue
+	}
+	if len(result) > maxLen {
+		result = result[:maxLen]
+	}
+	return result
+}
+
+// escapeSDValue meng-escape karakter '"', '\' dan ']' pada PARAM-VALUE structured data
+func escapeSDValue(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+	return replacer.Replace(value)
+}
@@ -0,0 +1,129 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/secrets"
+)
+
+// Header yang dikirim webhook sink untuk verifikasi di sisi penerima
+const (
+	WebhookSignatureHeader = "X-Audit-Signature" // sha256=<hex HMAC-SHA256(secret, timestamp + "." + body)>
+	WebhookTimestampHeader = "X-Audit-Timestamp" // Unix timestamp (detik), untuk mencegah replay
+	WebhookEventIDHeader   = "X-Audit-Event-Id"
+)
+
+// WebhookSinkConfig berisi konfigurasi webhook sink
+type WebhookSinkConfig struct {
+	URL       string
+	Secret    string
+	AllowHTTP bool // Hanya untuk testing lokal, production wajib HTTPS
+	Timeout   time.Duration
+}
+
+// WebhookSink mengirim audit event sebagai JSON via HTTP POST dengan signature HMAC-SHA256
+type WebhookSink struct {
+	cfg    WebhookSinkConfig
+	client *http.Client
+}
+
+// NewWebhookSink membuat webhook sink baru
+func NewWebhookSink(cfg WebhookSinkConfig) (*WebhookSink, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("webhook url is required")
+	}
+	parsed, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook url: %w", err)
+	}
+	if parsed.Scheme != "https" && !(cfg.AllowHTTP && parsed.Scheme == "http") {
+		return nil, fmt.Errorf("webhook url must use https")
+	}
+	if cfg.Secret == "" {
+		return nil, fmt.Errorf("webhook secret is required")
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &WebhookSink{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+// NewWebhookSinkFromEnv membuat webhook sink dari environment variables.
+// Secret diambil dari secret manager (key: audit_webhook_secret) dengan fallback AUDIT_WEBHOOK_SECRET.
+func NewWebhookSinkFromEnv() (*WebhookSink, error) {
+	secret, _ := secrets.GetSecretWithFallback("audit_webhook_secret", "AUDIT_WEBHOOK_SECRET", "")
+	cfg := WebhookSinkConfig{
+		URL:       os.Getenv("AUDIT_WEBHOOK_URL"),
+		Secret:    secret,
+		AllowHTTP: os.Getenv("AUDIT_WEBHOOK_ALLOW_HTTP") == "true",
+	}
+	if v, err := strconv.Atoi(os.Getenv("AUDIT_WEBHOOK_TIMEOUT_SECONDS")); err == nil && v > 0 {
+		cfg.Timeout = time.Duration(v) * time.Second
+	}
+	return NewWebhookSink(cfg)
+}
+
+// Name mengembalikan nama sink
+func (s *WebhookSink) Name() string {
+	return SinkWebhook
+}
+
+// Send mengirim satu event. Response non-2xx dianggap gagal agar di-retry.
+func (s *WebhookSink) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookEventIDHeader, event.ID)
+	req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhookPayload(s.cfg.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Close tidak melakukan apa-apa (koneksi HTTP dikelola oleh client)
+func (s *WebhookSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// SignWebhookPayload menghitung signature HMAC-SHA256 (hex) atas timestamp + "." + body
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
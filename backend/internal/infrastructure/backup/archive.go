@@ -0,0 +1,120 @@
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+)
+
+// archiveWriter menulis entry tar.gz, opsional dienkripsi, ke w
+type archiveWriter struct {
+	tw      *tar.Writer
+	gz      *gzip.Writer
+	enc     *encryptWriter
+	modTime time.Time
+}
+
+func newArchiveWriter(w io.Writer, opts Options) (*archiveWriter, error) {
+	a := &archiveWriter{modTime: time.Now().UTC()}
+	if opts.Passphrase != "" {
+		enc, err := newEncryptWriter(w, opts.Passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize encryption: %w", err)
+		}
+		a.enc = enc
+		w = enc
+	}
+	a.gz = gzip.NewWriter(w)
+	a.tw = tar.NewWriter(a.gz)
+	return a, nil
+}
+
+// add menulis satu entry dan mengembalikan checksum SHA-256 isinya
+func (a *archiveWriter) add(name string, size int64, r io.Reader) (string, error) {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    size,
+		ModTime: a.modTime,
+	}
+	if err := a.tw.WriteHeader(header); err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(a.tw, hash), r); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (a *archiveWriter) Close() error {
+	if err := a.tw.Close(); err != nil {
+		return err
+	}
+	if err := a.gz.Close(); err != nil {
+		return err
+	}
+	if a.enc != nil {
+		return a.enc.Close()
+	}
+	return nil
+}
+
+// IsEncrypted memeriksa apakah r (yang belum dibaca) adalah arsip terenkripsi
+func IsEncrypted(r *bufio.Reader) bool {
+	magic, err := r.Peek(len(encryptedMagic))
+	return err == nil && bytes.Equal(magic, encryptedMagic)
+}
+
+// openArchive membuka arsip (terenkripsi atau tidak) untuk dibaca entry per entry
+func openArchive(r io.Reader, opts Options) (*tar.Reader, func() error, error) {
+	br := bufio.NewReader(r)
+	var plain io.Reader = br
+	if IsEncrypted(br) {
+		if opts.Passphrase == "" {
+			return nil, nil, ErrPassphraseRequired
+		}
+		dec, err := newDecryptReader(br, opts.Passphrase)
+		if err != nil {
+			return nil, nil, err
+		}
+		plain = dec
+	}
+	gz, err := gzip.NewReader(plain)
+	if err != nil {
+		if err == ErrDecryptFailed {
+			return nil, nil, err
+		}
+		return nil, nil, fmt.Errorf("not a backup archive: %w", err)
+	}
+	return tar.NewReader(gz), gz.Close, nil
+}
+
+// hashingReader menghitung SHA-256 dan jumlah byte yang dibaca
+type hashingReader struct {
+	r      io.Reader
+	digest hash.Hash
+	n      int64
+}
+
+func newHashingReader(r io.Reader) *hashingReader {
+	return &hashingReader{r: r, digest: sha256.New()}
+}
+
+func (h *hashingReader) Read(p []byte) (int, error) {
+	n, err := h.r.Read(p)
+	h.n += int64(n)
+	h.digest.Write(p[:n])
+	return n, err
+}
+
+func (h *hashingReader) sum() string {
+	return hex.EncodeToString(h.digest.Sum(nil))
+}
@@ -0,0 +1,160 @@
+// Package backup membuat dan memulihkan backup lengkap DMS: seluruh baris database beserta file
+// (dokumen dan logo company) yang direferensikan di storage, dalam satu arsip.
+//
+// Format arsip adalah tar.gz dengan isi berurutan:
+//
+//	tables/<table>.jsonl   satu baris JSON per row (key = nama kolom), urutan tabel mengikuti foreign key
+//	blobs/<object path>    isi file di storage (misalnya blobs/documents/abc.pdf)
+//	manifest.json          versi schema, jumlah row, ukuran dan checksum SHA-256 setiap entry
+//
+// Arsip bisa dienkripsi dengan passphrase (lihat crypto.go); hasilnya tetap satu file.
+// Restore hanya dilakukan ke instance kosong yang schema-nya sudah di-migrate ke versi yang sama
+// dengan backup (`migrate up -to <schema_version>`), di PostgreSQL maupun SQLite dan ke storage
+// backend apa pun; URL file di kolom blob (termasuk public URL GCS lama) ditulis ulang menjadi
+// /api/v1/files/<object path> agar menunjuk ke storage tujuan. Kolom yang dienkripsi aplikasi (misalnya secret 2FA) disalin apa adanya, jadi
+// instance tujuan harus memakai ENCRYPTION_KEY yang sama.
+package backup
+
+import (
+	"errors"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+)
+
+// FormatVersion dinaikkan jika layout arsip berubah secara tidak kompatibel
+const FormatVersion = 1
+
+// Nama entry di dalam arsip
+const (
+	manifestEntry = "manifest.json"
+	tablesDir     = "tables/"
+	blobsDir      = "blobs/"
+)
+
+var (
+	// ErrPassphraseRequired: arsip terenkripsi dibuka tanpa passphrase
+	ErrPassphraseRequired = errors.New("backup archive is encrypted, passphrase required")
+	// ErrChecksumMismatch: isi arsip tidak sama dengan manifest (arsip rusak atau diubah)
+	ErrChecksumMismatch = errors.New("backup archive checksum mismatch")
+	// ErrTargetNotEmpty: restore hanya boleh ke instance kosong
+	ErrTargetNotEmpty = errors.New("restore target is not empty")
+	// ErrSchemaVersionMismatch: versi schema tujuan berbeda dengan versi schema backup
+	ErrSchemaVersionMismatch = errors.New("restore target schema version does not match backup")
+)
+
+// Manifest mendeskripsikan isi arsip backup
+type Manifest struct {
+	FormatVersion  int          `json:"format_version"`
+	CreatedAt      time.Time    `json:"created_at"`
+	SchemaVersion  int64        `json:"schema_version"`  // Versi migrasi terakhir database sumber
+	SourceDialect  string       `json:"source_dialect"`  // postgres / sqlite
+	StorageBackend string       `json:"storage_backend"` // gcs / local
+	Tables         []TableEntry `json:"tables"`
+	Blobs          []BlobEntry  `json:"blobs"`
+	// File yang direferensikan database tetapi tidak ada di storage saat backup
+	MissingBlobs []MissingBlob `json:"missing_blobs,omitempty"`
+}
+
+// TableEntry adalah satu file tables/<table>.jsonl
+type TableEntry struct {
+	Name   string `json:"name"`
+	Rows   int64  `json:"rows"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// BlobEntry adalah satu file storage di blobs/
+type BlobEntry struct {
+	Path        string `json:"path"` // Object path di storage (documents/abc.pdf)
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	SHA256      string `json:"sha256"`
+}
+
+// MissingBlob adalah referensi file yang tidak bisa dibaca saat backup
+type MissingBlob struct {
+	Table  string `json:"table"`
+	RowID  string `json:"row_id"`
+	URL    string `json:"url"`
+	Reason string `json:"reason"`
+}
+
+// Options mengatur pembuatan dan pembacaan arsip
+type Options struct {
+	// Passphrase untuk enkripsi arsip; kosong = arsip tidak dienkripsi
+	Passphrase string
+	// Progress dipanggil untuk setiap tabel/file yang diproses (opsional)
+	Progress func(format string, args ...interface{})
+}
+
+func (o Options) progress(format string, args ...interface{}) {
+	if o.Progress != nil {
+		o.Progress(format, args...)
+	}
+}
+
+// Tables mengembalikan model yang di-backup, diurutkan supaya tabel induk (target foreign key)
+// selalu di-restore sebelum tabel anaknya. schema_migrations tidak termasuk karena dibuat oleh migrate.
+// Tambahkan model baru di sini setiap kali migrasi menambah tabel.
+func Tables() []interface{} {
+	return []interface{}{
+		// Akses: role, permission, company, user
+		&domain.RoleModel{},
+		&domain.PermissionModel{},
+		&domain.RolePermissionModel{},
+		&domain.CompanyModel{},
+		&domain.CompanyHierarchyModel{},
+		&domain.UserModel{},
+		&domain.TwoFactorAuth{},
+		&domain.UserCompanyAssignmentModel{},
+		// Master data
+		&domain.BusinessFieldModel{},
+		&domain.ShareholderTypeModel{},
+		&domain.DirectorPositionModel{},
+		&domain.DocumentTypeModel{},
+		&domain.ShareholderModel{},
+		&domain.DirectorModel{},
+		// Dokumen (director_terms mereferensikan dokumen SK)
+		&domain.DocumentFolderModel{},
+		&domain.DocumentModel{},
+		&domain.DirectorTermModel{},
+		&domain.CompanyPositionRequirementModel{},
+		&domain.LegalHoldModel{},
+		&domain.DocumentDispositionModel{},
+		// Laporan keuangan
+		&domain.FinancialReportModel{},
+		&domain.FinancialRKAPPhasingModel{},
+		&domain.FinancialAccountMappingModel{},
+		&domain.FinancialImportJobModel{},
+		&domain.FinancialImportRowModel{},
+		&domain.ReportModel{}, // Tabel reports lama, lihat legacyTables
+		// Notifikasi
+		&domain.NotificationSettingsModel{},
+		&domain.NotificationPreferenceModel{},
+		&domain.NotificationModel{},
+		&domain.NotificationReminderLogModel{},
+		&domain.NotificationEscalationModel{},
+		&domain.NotificationEscalationLevelModel{},
+		// Riwayat dan audit
+		&domain.CompanyRestructuringEventModel{},
+		&domain.CompanyExportJobModel{},
+		&domain.AuditLog{},
+		&domain.UserActivityLog{},
+		&domain.AuditSinkDeadLetter{},
+	}
+}
+
+// legacyTables adalah tabel yang tidak dibuat migrasi tetapi masih ada di database lama
+// (reports sebelum data dipindah ke financial_reports). Di-skip saat backup jika tabel tidak ada,
+// dan dibuat saat restore jika backup berisi tabel tersebut.
+var legacyTables = map[string]bool{
+	"reports": true,
+}
+
+// blobColumns adalah kolom yang berisi URL file di storage. Arsip export company
+// (company_export_jobs.file_path) sengaja tidak disalin: sementara dan bisa dibuat ulang.
+var blobColumns = map[string]string{
+	"documents": "file_path",
+	"companies": "logo",
+}
@@ -0,0 +1,264 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database/migrations"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/storage"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "dms.db")), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	db.Exec("PRAGMA foreign_keys = ON")
+	_, err = migrations.Up(db, 0)
+	require.NoError(t, err)
+	return db
+}
+
+func seed(t *testing.T, db *gorm.DB, manager storage.StorageManager) {
+	now := time.Date(2026, 10, 18, 9, 30, 0, 123456000, time.UTC)
+	parent := "c-root"
+	root := domain.CompanyModel{ID: "c-root", Name: "Root", Code: "ROOT", IsActive: true, Logo: "/api/v1/files/logos/root.png", CreatedAt: now, UpdatedAt: now}
+	// Public URL GCS lama: restore harus menulis ulang ke URL proxy agar tidak menunjuk bucket asal
+	child := domain.CompanyModel{ID: "c-child", Name: "Child", Code: "CHILD", ParentID: &parent, Level: 1, IsActive: false, Logo: "https://storage.googleapis.com/old-bucket/logos/child.png", CreatedAt: now, UpdatedAt: now}
+	companies := repository.NewCompanyRepositoryWithDB(db)
+	require.NoError(t, companies.Create(&root))
+	require.NoError(t, companies.Create(&child))
+	// is_active false harus tetap false (kolom punya default true)
+	require.NoError(t, db.Model(&child).Update("is_active", false).Error)
+
+	user := domain.UserModel{ID: "u1", Username: "alice", Email: "a@x", Password: "hash", CompanyID: &parent, IsActive: true, CreatedAt: now, UpdatedAt: now}
+	require.NoError(t, db.Create(&user).Error)
+
+	folderParent := "f-child"
+	// ID child lebih kecil dari parent: urutan export (primary key) melanggar self reference
+	require.NoError(t, db.Create(&domain.DocumentFolderModel{ID: "f-parent", Name: "Parent", CompanyID: &parent, CreatedAt: now, UpdatedAt: now}).Error)
+	require.NoError(t, db.Create(&domain.DocumentFolderModel{ID: "f-child", Name: "Mid", ParentID: strPtr("f-parent"), CreatedAt: now, UpdatedAt: now}).Error)
+	require.NoError(t, db.Create(&domain.DocumentFolderModel{ID: "f-a", Name: "Child", ParentID: &folderParent, CreatedAt: now, UpdatedAt: now}).Error)
+
+	folderID := "f-a"
+	doc := domain.DocumentModel{ID: "d1", FolderID: &folderID, Name: "SK", FileName: "sk.pdf", FilePath: "/api/v1/files/documents/sk.pdf", MimeType: "application/pdf", Size: 4, Metadata: datatypes.JSON(`{"expiry_date":"2027-01-01"}`), UploaderID: "u1", CreatedAt: now, UpdatedAt: now}
+	require.NoError(t, db.Create(&doc).Error)
+	missing := domain.DocumentModel{ID: "d2", Name: "Gone", FileName: "gone.pdf", FilePath: "/api/v1/files/documents/gone.pdf", MimeType: "application/pdf", CreatedAt: now, UpdatedAt: now}
+	require.NoError(t, db.Create(&missing).Error)
+
+	require.NoError(t, db.Create(&domain.ShareholderModel{ID: "s1", CompanyID: "c-child", Name: "Root", OwnershipPercent: 12.3456789012, CreatedAt: now, UpdatedAt: now}).Error)
+
+	_, err := manager.UploadFile("documents", "sk.pdf", []byte("%PDF"), "application/pdf")
+	require.NoError(t, err)
+	_, err = manager.UploadFile("logos", "root.png", []byte("png!"), "image/png")
+	require.NoError(t, err)
+	_, err = manager.UploadFile("logos", "child.png", []byte("png?"), "image/png")
+	require.NoError(t, err)
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestTablesCoverSchema(t *testing.T) {
+	db := openDB(t)
+	tables, err := db.Migrator().GetTables()
+	require.NoError(t, err)
+	var listed []string
+	for _, model := range Tables() {
+		codec, err := newTableCodec(db, model)
+		require.NoError(t, err)
+		if !legacyTables[codec.table()] {
+			listed = append(listed, codec.table())
+		}
+	}
+	var expected []string
+	for _, table := range tables {
+		if table != "schema_migrations" {
+			expected = append(expected, table)
+		}
+	}
+	sort.Strings(listed)
+	sort.Strings(expected)
+	assert.Equal(t, expected, listed)
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, passphrase := range []string{"", "correct horse"} {
+		t.Run("passphrase="+passphrase, func(t *testing.T) {
+			source := openDB(t)
+			sourceStorage := storage.NewLocalStorageManager(t.TempDir())
+			seed(t, source, sourceStorage)
+
+			var archive bytes.Buffer
+			manifest, err := Create(context.Background(), source, sourceStorage, &archive, Options{Passphrase: passphrase})
+			require.NoError(t, err)
+			assert.Len(t, manifest.Blobs, 3)
+			require.Len(t, manifest.MissingBlobs, 1)
+			assert.Equal(t, "d2", manifest.MissingBlobs[0].RowID)
+
+			if passphrase != "" {
+				_, err = Verify(bytes.NewReader(archive.Bytes()), Options{})
+				assert.ErrorIs(t, err, ErrPassphraseRequired)
+				_, err = Verify(bytes.NewReader(archive.Bytes()), Options{Passphrase: "wrong"})
+				assert.ErrorIs(t, err, ErrDecryptFailed)
+				_, err = Verify(bytes.NewReader(archive.Bytes()[:archive.Len()-10]), Options{Passphrase: passphrase})
+				assert.Error(t, err)
+			}
+			verified, err := Verify(bytes.NewReader(archive.Bytes()), Options{Passphrase: passphrase})
+			require.NoError(t, err)
+			assert.Equal(t, manifest.SchemaVersion, verified.SchemaVersion)
+
+			target := openDB(t)
+			targetDir := t.TempDir()
+			targetStorage := storage.NewLocalStorageManager(targetDir)
+			open := func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(archive.Bytes())), nil }
+			_, err = Restore(context.Background(), target, targetStorage, open, Options{Passphrase: passphrase})
+			require.NoError(t, err)
+
+			var child domain.CompanyModel
+			require.NoError(t, target.First(&child, "id = ?", "c-child").Error)
+			assert.False(t, child.IsActive)
+			assert.Equal(t, "c-root", *child.ParentID)
+			assert.Equal(t, "/api/v1/files/logos/child.png", child.Logo)
+			var user domain.UserModel
+			require.NoError(t, target.First(&user, "id = ?", "u1").Error)
+			assert.Equal(t, "hash", user.Password)
+			var folder domain.DocumentFolderModel
+			require.NoError(t, target.First(&folder, "id = ?", "f-a").Error)
+			assert.Equal(t, "f-child", *folder.ParentID)
+			var doc domain.DocumentModel
+			require.NoError(t, target.First(&doc, "id = ?", "d1").Error)
+			assert.JSONEq(t, `{"expiry_date":"2027-01-01"}`, string(doc.Metadata))
+			assert.Equal(t, time.Date(2026, 10, 18, 9, 30, 0, 123456000, time.UTC), doc.CreatedAt.UTC())
+			var holder domain.ShareholderModel
+			require.NoError(t, target.First(&holder, "id = ?", "s1").Error)
+			assert.InDelta(t, 12.3456789012, holder.OwnershipPercent, 1e-12)
+			var paths int64
+			target.Model(&domain.CompanyHierarchyModel{}).Count(&paths)
+			assert.Equal(t, int64(3), paths)
+
+			data, err := os.ReadFile(filepath.Join(targetDir, "documents", "sk.pdf"))
+			require.NoError(t, err)
+			assert.Equal(t, "%PDF", string(data))
+			data, err = os.ReadFile(filepath.Join(targetDir, "logos", "child.png"))
+			require.NoError(t, err)
+			assert.Equal(t, "png?", string(data))
+
+			// Restore kedua ditolak karena target sudah berisi data
+			_, err = Restore(context.Background(), target, targetStorage, open, Options{Passphrase: passphrase})
+			assert.ErrorIs(t, err, ErrTargetNotEmpty)
+		})
+	}
+}
+
+func TestLegacyReportsTable(t *testing.T) {
+	source := openDB(t)
+	sourceStorage := storage.NewLocalStorageManager(t.TempDir())
+	var archive bytes.Buffer
+	manifest, err := Create(context.Background(), source, sourceStorage, &archive, Options{})
+	require.NoError(t, err)
+	for _, table := range manifest.Tables {
+		assert.NotEqual(t, "reports", table.Name, "tabel reports yang tidak ada di-skip")
+	}
+
+	// Database lama yang masih punya tabel reports
+	require.NoError(t, source.Migrator().CreateTable(&domain.ReportModel{}))
+	require.NoError(t, source.Create(&domain.CompanyModel{ID: "c1", Name: "Root", Code: "ROOT", IsActive: true}).Error)
+	require.NoError(t, source.Create(&domain.ReportModel{ID: "r1", Period: "2025-06", CompanyID: "c1", Revenue: 100}).Error)
+	archive.Reset()
+	_, err = Create(context.Background(), source, sourceStorage, &archive, Options{})
+	require.NoError(t, err)
+
+	target := openDB(t)
+	open := func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(archive.Bytes())), nil }
+	_, err = Restore(context.Background(), target, storage.NewLocalStorageManager(t.TempDir()), open, Options{})
+	require.NoError(t, err)
+	var report domain.ReportModel
+	require.NoError(t, target.First(&report, "id = ?", "r1").Error)
+	assert.Equal(t, int64(100), report.Revenue)
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	source := openDB(t)
+	sourceStorage := storage.NewLocalStorageManager(t.TempDir())
+	seed(t, source, sourceStorage)
+	var archive bytes.Buffer
+	_, err := Create(context.Background(), source, sourceStorage, &archive, Options{})
+	require.NoError(t, err)
+
+	// Rebuild archive dengan satu blob diubah
+	reader, closeArchive, err := openArchive(bytes.NewReader(archive.Bytes()), Options{})
+	require.NoError(t, err)
+	var tampered bytes.Buffer
+	writer, err := newArchiveWriter(&tampered, Options{})
+	require.NoError(t, err)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		data, _ := io.ReadAll(reader)
+		if header.Name == "blobs/documents/sk.pdf" {
+			data = []byte("EVIL")
+		}
+		_, err = writer.add(header.Name, int64(len(data)), bytes.NewReader(data))
+		require.NoError(t, err)
+	}
+	closeArchive()
+	require.NoError(t, writer.Close())
+
+	_, err = Verify(&tampered, Options{})
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+}
+
+func TestRestoreRequiresMatchingSchema(t *testing.T) {
+	source := openDB(t)
+	sourceStorage := storage.NewLocalStorageManager(t.TempDir())
+	var archive bytes.Buffer
+	_, err := Create(context.Background(), source, sourceStorage, &archive, Options{})
+	require.NoError(t, err)
+
+	target, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "t.db")), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	_, err = migrations.Up(target, 20261018090000)
+	require.NoError(t, err)
+	open := func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(archive.Bytes())), nil }
+	_, err = Restore(context.Background(), target, sourceStorage, open, Options{})
+	assert.ErrorIs(t, err, ErrSchemaVersionMismatch)
+}
+
+func TestLargeEncryptedStream(t *testing.T) {
+	payload := bytes.Repeat([]byte("0123456789abcdef"), cryptoChunkSize/8) // tepat 2 chunk
+	for _, size := range []int{0, 1, cryptoChunkSize, len(payload)} {
+		var out bytes.Buffer
+		w, err := newEncryptWriter(&out, "pw")
+		require.NoError(t, err)
+		_, err = w.Write(payload[:size])
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		r, err := newDecryptReaderFrom(&out, "pw")
+		require.NoError(t, err)
+		got, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, payload[:size], got)
+	}
+}
+
+func newDecryptReaderFrom(r io.Reader, passphrase string) (*decryptReader, error) {
+	return newDecryptReader(bufio.NewReader(r), passphrase)
+}
@@ -0,0 +1,267 @@
+package backup
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// tableCodec mengubah row model <-> JSON berdasarkan schema GORM model, sehingga nilai tetap
+// bertipe benar saat dipindah antar dialect (misalnya bool SQLite 0/1 -> boolean PostgreSQL)
+type tableCodec struct {
+	model  interface{}
+	schema *schema.Schema
+	// Kolom foreign key ke tabel yang sama (misalnya document_folders.parent_id): di-restore
+	// setelah semua row tabel masuk supaya urutan row tidak melanggar constraint
+	selfRefColumns []string
+}
+
+func newTableCodec(db *gorm.DB, model interface{}) (*tableCodec, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, fmt.Errorf("failed to parse model %T: %w", model, err)
+	}
+	codec := &tableCodec{model: model, schema: stmt.Schema}
+
+	seen := map[string]bool{}
+	for _, rel := range stmt.Schema.Relationships.Relations {
+		// Relations juga memuat relasi milik model lain yang mengarah ke tabel ini (untuk constraint)
+		if rel.Schema.Table != stmt.Schema.Table || rel.FieldSchema == nil || rel.FieldSchema.Table != stmt.Schema.Table {
+			continue
+		}
+		for _, ref := range rel.References {
+			if ref.PrimaryKey != nil && ref.ForeignKey != nil && !seen[ref.ForeignKey.DBName] {
+				seen[ref.ForeignKey.DBName] = true
+				codec.selfRefColumns = append(codec.selfRefColumns, ref.ForeignKey.DBName)
+			}
+		}
+	}
+	return codec, nil
+}
+
+func (c *tableCodec) table() string {
+	return c.schema.Table
+}
+
+// primaryKey mengembalikan nilai primary key row (untuk pesan error dan update self reference)
+func (c *tableCodec) primaryKey(row map[string]interface{}) map[string]interface{} {
+	key := make(map[string]interface{}, len(c.schema.PrimaryFieldDBNames))
+	for _, name := range c.schema.PrimaryFieldDBNames {
+		key[name] = row[name]
+	}
+	return key
+}
+
+// encode mengubah satu instance model (reflect.Value struct) menjadi map kolom -> nilai JSON
+func (c *tableCodec) encode(ctx context.Context, value reflect.Value) (map[string]interface{}, error) {
+	row := make(map[string]interface{}, len(c.schema.DBNames))
+	for _, name := range c.schema.DBNames {
+		fieldValue, _ := c.schema.FieldsByDBName[name].ValueOf(ctx, value)
+		encoded, err := encodeValue(fieldValue)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", c.table(), name, err)
+		}
+		row[name] = encoded
+	}
+	return row, nil
+}
+
+func encodeValue(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		value = rv.Elem().Interface()
+	}
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano), nil
+	case driver.Valuer:
+		// datatypes.JSON dan tipe custom lain disimpan dalam bentuk nilai driver-nya
+		dv, err := v.Value()
+		if err != nil {
+			return nil, err
+		}
+		if b, ok := dv.([]byte); ok {
+			return string(b), nil
+		}
+		return encodeValue(dv)
+	}
+	return value, nil
+}
+
+// decode mengubah satu row JSON menjadi nilai kolom bertipe sesuai field model, siap di-insert.
+// Insert memakai map (bukan struct) karena GORM mengganti zero value dengan default kolom
+// (misalnya is_active=false menjadi true). Kolom self reference dikembalikan terpisah
+// untuk di-update setelah semua row tabel masuk.
+func (c *tableCodec) decode(ctx context.Context, row map[string]interface{}) (map[string]interface{}, map[string]interface{}, error) {
+	instance := reflect.New(c.schema.ModelType).Elem()
+	values := make(map[string]interface{}, len(row))
+	var deferred map[string]interface{}
+	for name, raw := range row {
+		field, ok := c.schema.FieldsByDBName[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("column %s.%s does not exist in this version", c.table(), name)
+		}
+		if err := setField(ctx, field, instance, raw); err != nil {
+			return nil, nil, fmt.Errorf("%s.%s: %w", c.table(), name, err)
+		}
+		value, _ := field.ValueOf(ctx, instance)
+		if raw != nil && c.isSelfRef(name) {
+			if deferred == nil {
+				deferred = map[string]interface{}{}
+			}
+			deferred[name] = value
+			continue
+		}
+		values[name] = value
+	}
+	return values, deferred, nil
+}
+
+func (c *tableCodec) isSelfRef(column string) bool {
+	for _, name := range c.selfRefColumns {
+		if name == column {
+			return true
+		}
+	}
+	return false
+}
+
+func setField(ctx context.Context, field *schema.Field, structValue reflect.Value, raw interface{}) error {
+	target := field.ReflectValueOf(ctx, structValue)
+	if raw == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+	base := field.FieldType
+	isPtr := base.Kind() == reflect.Ptr
+	if isPtr {
+		base = base.Elem()
+	}
+	value, err := decodeValue(base, raw)
+	if err != nil {
+		return err
+	}
+	if isPtr {
+		ptr := reflect.New(base)
+		ptr.Elem().Set(value)
+		target.Set(ptr)
+		return nil
+	}
+	target.Set(value)
+	return nil
+}
+
+// decodeValue mengubah nilai JSON (string, bool, json.Number) menjadi nilai bertipe base
+func decodeValue(base reflect.Type, raw interface{}) (reflect.Value, error) {
+	out := reflect.New(base)
+	if base == timeType {
+		s, ok := raw.(string)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected timestamp string, got %T", raw)
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out.Elem().Set(reflect.ValueOf(t))
+		return out.Elem(), nil
+	}
+	if scanner, ok := out.Interface().(sql.Scanner); ok {
+		src := raw
+		if n, ok := raw.(json.Number); ok {
+			src = n.String()
+		}
+		if err := scanner.Scan(src); err != nil {
+			return reflect.Value{}, err
+		}
+		return out.Elem(), nil
+	}
+
+	elem := out.Elem()
+	switch base.Kind() {
+	case reflect.String:
+		s, ok := raw.(string)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected string, got %T", raw)
+		}
+		elem.SetString(s)
+	case reflect.Bool:
+		b, ok := raw.(bool)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected bool, got %T", raw)
+		}
+		elem.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := jsonNumber(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		i, err := strconv.ParseInt(n, 10, 64)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		elem.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := jsonNumber(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		u, err := strconv.ParseUint(n, 10, 64)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		elem.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		n, err := jsonNumber(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		f, err := strconv.ParseFloat(n, 64)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		elem.SetFloat(f)
+	case reflect.Slice:
+		if base.Elem().Kind() != reflect.Uint8 {
+			return reflect.Value{}, fmt.Errorf("unsupported type %s", base)
+		}
+		// []byte di-encode encoding/json sebagai base64
+		s, ok := raw.(string)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected base64 string, got %T", raw)
+		}
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		elem.SetBytes(b)
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported type %s", base)
+	}
+	return elem, nil
+}
+
+func jsonNumber(raw interface{}) (string, error) {
+	n, ok := raw.(json.Number)
+	if !ok {
+		return "", fmt.Errorf("expected number, got %T", raw)
+	}
+	return n.String(), nil
+}
@@ -0,0 +1,184 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Arsip terenkripsi: header lalu rangkaian chunk AES-256-GCM (STREAM construction).
+//
+//	header = encryptedMagic (8) | salt (16) | nonce prefix (7)
+//	chunk  = AES-GCM(plaintext maks. cryptoChunkSize byte), nonce = prefix | counter (4) | last flag (1)
+//
+// Key diturunkan dari passphrase dengan scrypt. Counter dan last flag di nonce mencegah chunk
+// ditukar urutannya atau arsip dipotong tanpa terdeteksi.
+var encryptedMagic = []byte("DMSBAK\x00\x01")
+
+const (
+	cryptoSaltSize        = 16
+	cryptoNoncePrefixSize = 7
+	cryptoChunkSize       = 64 * 1024
+
+	// Parameter scrypt (rekomendasi interaktif 2017+, ~100ms)
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// ErrDecryptFailed: passphrase salah atau arsip terenkripsi rusak
+var ErrDecryptFailed = errors.New("failed to decrypt backup archive (wrong passphrase or corrupted archive)")
+
+func deriveAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func chunkNonce(prefix []byte, counter uint32, last bool) []byte {
+	nonce := make([]byte, 0, 12)
+	nonce = append(nonce, prefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, counter)
+	if last {
+		return append(nonce, 1)
+	}
+	return append(nonce, 0)
+}
+
+// encryptWriter mengenkripsi stream per chunk; Close wajib dipanggil untuk menulis chunk terakhir
+type encryptWriter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	prefix  []byte
+	counter uint32
+	buf     []byte
+}
+
+func newEncryptWriter(w io.Writer, passphrase string) (*encryptWriter, error) {
+	header := make([]byte, cryptoSaltSize+cryptoNoncePrefixSize)
+	if _, err := rand.Read(header); err != nil {
+		return nil, err
+	}
+	salt, prefix := header[:cryptoSaltSize], header[cryptoSaltSize:]
+	aead, err := deriveAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(encryptedMagic); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &encryptWriter{w: w, aead: aead, prefix: prefix, buf: make([]byte, 0, cryptoChunkSize)}, nil
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		// Chunk penuh baru ditulis saat ada data berikutnya, supaya chunk terakhir selalu ditandai last
+		if len(e.buf) == cryptoChunkSize {
+			if err := e.seal(false); err != nil {
+				return written, err
+			}
+		}
+		n := copy(e.buf[len(e.buf):cryptoChunkSize], p)
+		e.buf = e.buf[:len(e.buf)+n]
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+func (e *encryptWriter) seal(last bool) error {
+	if e.counter == ^uint32(0) {
+		return errors.New("backup archive too large to encrypt")
+	}
+	sealed := e.aead.Seal(nil, chunkNonce(e.prefix, e.counter, last), e.buf, nil)
+	e.counter++
+	e.buf = e.buf[:0]
+	_, err := e.w.Write(sealed)
+	return err
+}
+
+func (e *encryptWriter) Close() error {
+	return e.seal(true)
+}
+
+// decryptReader membaca stream hasil encryptWriter
+type decryptReader struct {
+	r       *bufio.Reader
+	aead    cipher.AEAD
+	prefix  []byte
+	counter uint32
+	chunk   []byte // plaintext chunk aktif yang belum dibaca
+	done    bool
+}
+
+func newDecryptReader(r *bufio.Reader, passphrase string) (*decryptReader, error) {
+	header := make([]byte, len(encryptedMagic)+cryptoSaltSize+cryptoNoncePrefixSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("failed to read encryption header: %w", err)
+	}
+	if !bytes.Equal(header[:len(encryptedMagic)], encryptedMagic) {
+		return nil, errors.New("not an encrypted backup archive")
+	}
+	header = header[len(encryptedMagic):]
+	aead, err := deriveAEAD(passphrase, header[:cryptoSaltSize])
+	if err != nil {
+		return nil, err
+	}
+	return &decryptReader{r: r, aead: aead, prefix: header[cryptoSaltSize:]}, nil
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.chunk) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.chunk)
+	d.chunk = d.chunk[n:]
+	return n, nil
+}
+
+func (d *decryptReader) next() error {
+	sealed := make([]byte, cryptoChunkSize+d.aead.Overhead())
+	n, err := io.ReadFull(d.r, sealed)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		if err == io.EOF {
+			// Stream berakhir sebelum chunk bertanda last: arsip terpotong
+			return ErrDecryptFailed
+		}
+		return err
+	}
+	sealed = sealed[:n]
+	// Chunk terakhir adalah chunk yang tidak diikuti data lagi
+	_, peekErr := d.r.Peek(1)
+	last := peekErr == io.EOF
+	plain, openErr := d.aead.Open(sealed[:0], chunkNonce(d.prefix, d.counter, last), sealed, nil)
+	if openErr != nil {
+		return ErrDecryptFailed
+	}
+	d.counter++
+	d.chunk = plain
+	d.done = last
+	return nil
+}
@@ -0,0 +1,212 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database/migrations"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/storage"
+	"gorm.io/gorm"
+)
+
+// blobRef adalah satu referensi file dari row database
+type blobRef struct {
+	table string
+	rowID string
+	url   string
+}
+
+// Create menulis backup lengkap database dan file yang direferensikannya ke w.
+// Semua tabel dibaca dalam satu transaksi (snapshot REPEATABLE READ di PostgreSQL) sehingga
+// isi tabel konsisten satu sama lain; file dibaca setelahnya (file di storage tidak pernah
+// ditimpa, upload baru selalu memakai nama baru).
+func Create(ctx context.Context, db *gorm.DB, manager storage.StorageManager, w io.Writer, opts Options) (*Manifest, error) {
+	status, err := migrations.GetStatus(db)
+	if err != nil {
+		return nil, err
+	}
+	if err := status.Err(); err != nil {
+		return nil, fmt.Errorf("cannot back up database: %w", err)
+	}
+
+	archive, err := newArchiveWriter(w, opts)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &Manifest{
+		FormatVersion:  FormatVersion,
+		CreatedAt:      time.Now().UTC(),
+		SchemaVersion:  status.Current,
+		SourceDialect:  db.Dialector.Name(),
+		StorageBackend: storage.BackendName(manager),
+		Tables:         []TableEntry{},
+		Blobs:          []BlobEntry{},
+	}
+
+	var txOptions *sql.TxOptions
+	if db.Dialector.Name() == "postgres" {
+		txOptions = &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	}
+	var refs []blobRef
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, model := range Tables() {
+			codec, err := newTableCodec(tx, model)
+			if err != nil {
+				return err
+			}
+			if legacyTables[codec.table()] && !tx.Migrator().HasTable(codec.table()) {
+				continue
+			}
+			entry, tableRefs, err := exportTable(ctx, tx, codec, archive)
+			if err != nil {
+				return fmt.Errorf("failed to export %s: %w", codec.table(), err)
+			}
+			opts.progress("table %-36s %8d rows", entry.Name, entry.Rows)
+			manifest.Tables = append(manifest.Tables, entry)
+			refs = append(refs, tableRefs...)
+		}
+		return nil
+	}, txOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := exportBlobs(manager, archive, manifest, refs, opts); err != nil {
+		return nil, err
+	}
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := archive.add(manifestEntry, int64(len(manifestJSON)), bytes.NewReader(manifestJSON)); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finalize archive: %w", err)
+	}
+	return manifest, nil
+}
+
+// exportTable menulis semua row tabel ke file sementara (ukuran entry tar harus diketahui
+// sebelum ditulis) lalu menyalinnya ke arsip
+func exportTable(ctx context.Context, tx *gorm.DB, codec *tableCodec, archive *archiveWriter) (TableEntry, []blobRef, error) {
+	entry := TableEntry{Name: codec.table()}
+	tmp, err := os.CreateTemp("", "dms-backup-*.jsonl")
+	if err != nil {
+		return entry, nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	buffered := bufio.NewWriter(tmp)
+	encoder := json.NewEncoder(buffered)
+	encoder.SetEscapeHTML(false)
+
+	rows, err := tx.Model(codec.model).Unscoped().Order(strings.Join(codec.schema.PrimaryFieldDBNames, ", ")).Rows()
+	if err != nil {
+		return entry, nil, err
+	}
+	defer rows.Close()
+
+	blobColumn := blobColumns[codec.table()]
+	var refs []blobRef
+	for rows.Next() {
+		instance := reflect.New(codec.schema.ModelType)
+		if err := tx.ScanRows(rows, instance.Interface()); err != nil {
+			return entry, nil, err
+		}
+		row, err := codec.encode(ctx, instance.Elem())
+		if err != nil {
+			return entry, nil, err
+		}
+		if err := encoder.Encode(row); err != nil {
+			return entry, nil, err
+		}
+		entry.Rows++
+		if url, ok := row[blobColumn].(string); ok && blobColumn != "" && url != "" {
+			refs = append(refs, blobRef{table: codec.table(), rowID: fmt.Sprint(row[codec.schema.PrimaryFieldDBNames[0]]), url: url})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return entry, nil, err
+	}
+	if err := buffered.Flush(); err != nil {
+		return entry, nil, err
+	}
+
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return entry, nil, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return entry, nil, err
+	}
+	entry.Size = size
+	entry.SHA256, err = archive.add(tablesDir+entry.Name+".jsonl", size, tmp)
+	return entry, refs, err
+}
+
+// exportBlobs menyalin file yang direferensikan row ke arsip (sekali per object path).
+// File yang sudah tidak ada di storage dicatat di manifest.MissingBlobs; error lain membatalkan backup.
+func exportBlobs(manager storage.StorageManager, archive *archiveWriter, manifest *Manifest, refs []blobRef, opts Options) error {
+	byPath := map[string]blobRef{}
+	for _, ref := range refs {
+		objectPath, err := storage.ObjectPathFromURL(ref.url)
+		if err != nil {
+			manifest.MissingBlobs = append(manifest.MissingBlobs, MissingBlob{Table: ref.table, RowID: ref.rowID, URL: ref.url, Reason: err.Error()})
+			opts.progress("invalid file url %s (%s %s)", ref.url, ref.table, ref.rowID)
+			continue
+		}
+		if _, ok := byPath[objectPath]; !ok {
+			byPath[objectPath] = ref
+		}
+	}
+	paths := make([]string, 0, len(byPath))
+	for objectPath := range byPath {
+		paths = append(paths, objectPath)
+	}
+	sort.Strings(paths)
+
+	for _, objectPath := range paths {
+		ref := byPath[objectPath]
+		exists, err := manager.FileExists(path.Dir(objectPath), path.Base(objectPath))
+		if err != nil {
+			return fmt.Errorf("failed to check file %s: %w", objectPath, err)
+		}
+		if !exists {
+			manifest.MissingBlobs = append(manifest.MissingBlobs, MissingBlob{Table: ref.table, RowID: ref.rowID, URL: ref.url, Reason: "file not found in storage"})
+			opts.progress("missing file %s (%s %s)", objectPath, ref.table, ref.rowID)
+			continue
+		}
+		data, err := storage.ReadFileByURL(manager, objectPath)
+		if err != nil {
+			return fmt.Errorf("failed to read file %s: %w", objectPath, err)
+		}
+
+		contentType := mime.TypeByExtension(path.Ext(objectPath))
+		if contentType == "" {
+			contentType = http.DetectContentType(data)
+		}
+		checksum, err := archive.add(blobsDir+objectPath, int64(len(data)), bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		manifest.Blobs = append(manifest.Blobs, BlobEntry{Path: objectPath, ContentType: contentType, Size: int64(len(data)), SHA256: checksum})
+	}
+	opts.progress("files %d copied, %d missing", len(manifest.Blobs), len(manifest.MissingBlobs))
+	return nil
+}
@@ -0,0 +1,378 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database/migrations"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/storage"
+	"gorm.io/gorm"
+)
+
+// restoreBatchSize adalah jumlah row per INSERT saat restore
+const restoreBatchSize = 200
+
+// entryDigest adalah ukuran dan checksum satu entry yang dibaca dari arsip
+type entryDigest struct {
+	size   int64
+	sha256 string
+	lines  int64
+}
+
+// Verify membaca seluruh arsip dan mencocokkan setiap entry dengan manifest
+// (ukuran, checksum SHA-256, dan jumlah row tabel). Arsip terenkripsi sekaligus diuji passphrase-nya.
+func Verify(r io.Reader, opts Options) (*Manifest, error) {
+	reader, closeArchive, err := openArchive(r, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer closeArchive()
+
+	digests := map[string]entryDigest{}
+	var manifest *Manifest
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if header.Name == manifestEntry {
+			manifest = &Manifest{}
+			if err := json.NewDecoder(reader).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("invalid manifest: %w", err)
+			}
+			continue
+		}
+		hashing := newHashingReader(reader)
+		counter := &lineCounter{}
+		if _, err := io.Copy(counter, hashing); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+		digests[header.Name] = entryDigest{size: hashing.n, sha256: hashing.sum(), lines: counter.lines}
+	}
+	if manifest == nil {
+		return nil, errors.New("invalid backup archive: manifest.json not found")
+	}
+	if manifest.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("unsupported backup format version %d (expected %d)", manifest.FormatVersion, FormatVersion)
+	}
+
+	expected := map[string]bool{}
+	check := func(name string, size int64, checksum string) error {
+		expected[name] = true
+		digest, ok := digests[name]
+		if !ok {
+			return fmt.Errorf("%w: %s is missing from archive", ErrChecksumMismatch, name)
+		}
+		if digest.size != size || digest.sha256 != checksum {
+			return fmt.Errorf("%w: %s", ErrChecksumMismatch, name)
+		}
+		return nil
+	}
+	for _, table := range manifest.Tables {
+		name := tablesDir + table.Name + ".jsonl"
+		if err := check(name, table.Size, table.SHA256); err != nil {
+			return nil, err
+		}
+		if digests[name].lines != table.Rows {
+			return nil, fmt.Errorf("%w: %s has %d rows, manifest says %d", ErrChecksumMismatch, name, digests[name].lines, table.Rows)
+		}
+	}
+	for _, blob := range manifest.Blobs {
+		if err := check(blobsDir+blob.Path, blob.Size, blob.SHA256); err != nil {
+			return nil, err
+		}
+	}
+	for name := range digests {
+		if !expected[name] {
+			return nil, fmt.Errorf("%w: %s is not listed in manifest", ErrChecksumMismatch, name)
+		}
+	}
+	return manifest, nil
+}
+
+// lineCounter menghitung jumlah baris (row JSONL) tanpa menyimpan isinya
+type lineCounter struct {
+	lines int64
+}
+
+func (l *lineCounter) Write(p []byte) (int, error) {
+	l.lines += int64(bytes.Count(p, []byte{'\n'}))
+	return len(p), nil
+}
+
+// Restore memulihkan arsip ke database dan storage tujuan.
+// open dipanggil dua kali: pertama untuk Verify (arsip rusak tidak pernah di-restore sebagian),
+// kedua untuk memasukkan data. Semua row masuk dalam satu transaksi; file di-upload selama
+// transaksi berjalan, jadi jika restore gagal file yang sudah ter-upload tertinggal di storage
+// tanpa referensi dan aman dihapus.
+func Restore(ctx context.Context, db *gorm.DB, manager storage.StorageManager, open func() (io.ReadCloser, error), opts Options) (*Manifest, error) {
+	r, err := open()
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := Verify(r, opts)
+	r.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	codecs, err := restoreCodecs(db, manifest)
+	if err != nil {
+		return nil, err
+	}
+	if err := createLegacyTables(db, manifest, codecs); err != nil {
+		return nil, err
+	}
+	if err := checkRestoreTarget(db, manifest, codecs); err != nil {
+		return nil, err
+	}
+
+	r, err = open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	reader, closeArchive, err := openArchive(r, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer closeArchive()
+
+	blobs := make(map[string]BlobEntry, len(manifest.Blobs))
+	for _, blob := range manifest.Blobs {
+		blobs[blob.Path] = blob
+	}
+	rowCounts := make(map[string]int64, len(manifest.Tables))
+	for _, table := range manifest.Tables {
+		rowCounts[table.Name] = table.Rows
+	}
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for {
+			header, err := reader.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read archive: %w", err)
+			}
+			switch {
+			case strings.HasPrefix(header.Name, tablesDir):
+				table := strings.TrimSuffix(strings.TrimPrefix(header.Name, tablesDir), ".jsonl")
+				restored, err := restoreTable(ctx, tx, codecs[table], reader)
+				if err != nil {
+					return fmt.Errorf("failed to restore %s: %w", table, err)
+				}
+				if restored != rowCounts[table] {
+					return fmt.Errorf("%w: restored %d rows into %s, manifest says %d", ErrChecksumMismatch, restored, table, rowCounts[table])
+				}
+				opts.progress("table %-36s %8d rows", table, restored)
+			case strings.HasPrefix(header.Name, blobsDir):
+				if err := restoreBlob(manager, blobs[strings.TrimPrefix(header.Name, blobsDir)], reader); err != nil {
+					return err
+				}
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	opts.progress("files %d restored", len(manifest.Blobs))
+	return manifest, nil
+}
+
+// restoreCodecs memetakan tabel di manifest ke model binary ini
+func restoreCodecs(db *gorm.DB, manifest *Manifest) (map[string]*tableCodec, error) {
+	known := map[string]*tableCodec{}
+	for _, model := range Tables() {
+		codec, err := newTableCodec(db, model)
+		if err != nil {
+			return nil, err
+		}
+		known[codec.table()] = codec
+	}
+	codecs := make(map[string]*tableCodec, len(manifest.Tables))
+	for _, table := range manifest.Tables {
+		codec, ok := known[table.Name]
+		if !ok {
+			return nil, fmt.Errorf("table %s in backup is unknown to this binary", table.Name)
+		}
+		codecs[table.Name] = codec
+	}
+	return codecs, nil
+}
+
+// createLegacyTables membuat tabel lama yang ada di backup tetapi tidak dibuat migrasi di database tujuan
+func createLegacyTables(db *gorm.DB, manifest *Manifest, codecs map[string]*tableCodec) error {
+	for _, table := range manifest.Tables {
+		if !legacyTables[table.Name] || db.Migrator().HasTable(table.Name) {
+			continue
+		}
+		if err := db.Migrator().CreateTable(codecs[table.Name].model); err != nil {
+			return fmt.Errorf("failed to create legacy table %s: %w", table.Name, err)
+		}
+	}
+	return nil
+}
+
+// checkRestoreTarget memastikan versi schema tujuan sama dengan backup dan semua tabel masih kosong
+func checkRestoreTarget(db *gorm.DB, manifest *Manifest, codecs map[string]*tableCodec) error {
+	status, err := migrations.GetStatus(db)
+	if err != nil {
+		return err
+	}
+	if len(status.Unknown) > 0 {
+		return migrations.ErrUnknownMigrations
+	}
+	for _, pending := range status.Pending {
+		if pending.Version <= manifest.SchemaVersion {
+			return fmt.Errorf("%w: target is at %d, backup is at %d (run `migrate up -to %d` first)",
+				ErrSchemaVersionMismatch, status.Current, manifest.SchemaVersion, manifest.SchemaVersion)
+		}
+	}
+	if status.Current != manifest.SchemaVersion {
+		return fmt.Errorf("%w: target is at %d, backup is at %d (restore into a fresh database migrated with `migrate up -to %d`)",
+			ErrSchemaVersionMismatch, status.Current, manifest.SchemaVersion, manifest.SchemaVersion)
+	}
+
+	var notEmpty []string
+	for _, table := range manifest.Tables {
+		var count int64
+		if err := db.Table(codecs[table.Name].table()).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			notEmpty = append(notEmpty, table.Name)
+		}
+	}
+	if len(notEmpty) > 0 {
+		sort.Strings(notEmpty)
+		return fmt.Errorf("%w: %s already contain data (restore into a freshly migrated database before the API is started)",
+			ErrTargetNotEmpty, strings.Join(notEmpty, ", "))
+	}
+	return nil
+}
+
+// restoreTable memasukkan row JSONL secara batch. Hook model dilewati karena row di arsip sudah
+// lengkap (tabel turunan seperti company_hierarchy ikut di-restore dari arsip).
+func restoreTable(ctx context.Context, tx *gorm.DB, codec *tableCodec, r io.Reader) (int64, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	session := tx.Session(&gorm.Session{SkipHooks: true})
+
+	type selfRef struct {
+		key     map[string]interface{}
+		columns map[string]interface{}
+	}
+	var (
+		restored int64
+		deferred []selfRef
+		columns  []string
+		batch    = make([]map[string]interface{}, 0, restoreBatchSize)
+	)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := session.Table(codec.table()).Create(&batch).Error; err != nil {
+			return err
+		}
+		restored += int64(len(batch))
+		batch = make([]map[string]interface{}, 0, restoreBatchSize)
+		return nil
+	}
+
+	blobColumn := blobColumns[codec.table()]
+	for {
+		row := map[string]interface{}{}
+		if err := decoder.Decode(&row); err == io.EOF {
+			break
+		} else if err != nil {
+			return restored, err
+		}
+		if blobColumn != "" {
+			rewriteBlobURL(row, blobColumn)
+		}
+		values, selfRefColumns, err := codec.decode(ctx, row)
+		if err != nil {
+			return restored, err
+		}
+		if selfRefColumns != nil {
+			deferred = append(deferred, selfRef{key: codec.primaryKey(row), columns: selfRefColumns})
+		}
+
+		// Satu INSERT batch harus memiliki kolom yang sama; hanya kolom yang ada di backup yang
+		// di-insert sehingga kolom dari migrasi setelah versi backup memakai default-nya
+		rowColumns := make([]string, 0, len(values))
+		for name := range values {
+			rowColumns = append(rowColumns, name)
+		}
+		sort.Strings(rowColumns)
+		if !equalColumns(columns, rowColumns) || len(batch) == restoreBatchSize {
+			if err := flush(); err != nil {
+				return restored, err
+			}
+			columns = rowColumns
+		}
+		batch = append(batch, values)
+	}
+	if err := flush(); err != nil {
+		return restored, err
+	}
+
+	for _, ref := range deferred {
+		if err := tx.Table(codec.table()).Where(ref.key).UpdateColumns(ref.columns).Error; err != nil {
+			return restored, err
+		}
+	}
+	return restored, nil
+}
+
+// rewriteBlobURL mengganti URL file (termasuk public URL GCS lama yang menyebut bucket asal) dengan
+// URL proxy /api/v1/files/<object path>, sehingga row menunjuk ke file hasil restoreBlob di storage tujuan.
+// URL yang tidak bisa diubah menjadi object path dibiarkan apa adanya (tercatat di MissingBlobs saat backup).
+func rewriteBlobURL(row map[string]interface{}, column string) {
+	url, ok := row[column].(string)
+	if !ok || url == "" {
+		return
+	}
+	if objectPath, err := storage.ObjectPathFromURL(url); err == nil {
+		row[column] = storage.FilesURLPrefix + objectPath
+	}
+}
+
+func equalColumns(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// restoreBlob meng-upload satu file ke storage tujuan pada object path yang sama. Bersama rewriteBlobURL,
+// URL di database (/api/v1/files/<object path>) tetap valid untuk backend storage mana pun
+func restoreBlob(manager storage.StorageManager, blob BlobEntry, r *tar.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if _, err := manager.UploadFile(path.Dir(blob.Path), path.Base(blob.Path), data, blob.ContentType); err != nil {
+		return fmt.Errorf("failed to upload %s: %w", blob.Path, err)
+	}
+	return nil
+}
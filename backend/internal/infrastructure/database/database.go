@@ -3,10 +3,12 @@ package database
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
-	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database/migrations"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/observability"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/secrets"
 	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
@@ -39,8 +41,16 @@ func getSQLCipherKey() string {
 	return key
 }
 
-// InitDB menginisialisasi koneksi database
+// InitDB membuka koneksi database lalu memverifikasi versi schema.
+// Aplikasi menolak berjalan jika schema tidak sesuai dengan migrasi yang dikenal binary (lihat ensureSchema).
 func InitDB() {
+	Connect()
+	ensureSchema()
+}
+
+// Connect membuka koneksi database (PostgreSQL jika DATABASE_URL diset, selain itu SQLite) tanpa menyentuh schema.
+// Dipakai langsung oleh cmd/migrate.
+func Connect() {
 	zapLog := logger.GetLogger()
 	var err error
 	var dialector gorm.Dialector
@@ -53,9 +63,9 @@ func InitDB() {
 		// Check if SQLCipher encryption is enabled
 		enableSQLCipher := os.Getenv("ENABLE_SQLCIPHER")
 		sqlcipherKey := getSQLCipherKey()
-		
+
 		dbPath := "dms.db"
-		
+
 		if enableSQLCipher == "true" && sqlcipherKey != "" {
 			zapLog.Info("Using SQLite database with SQLCipher encryption (development)")
 			// SQLCipher menggunakan pragma key untuk encryption
@@ -63,12 +73,12 @@ func InitDB() {
 			// Note: GORM SQLite driver menggunakan github.com/glebarez/go-sqlite yang support SQLCipher
 			// via build tags, tapi untuk compatibility kita gunakan approach pragma key
 			// Jika SQLCipher library terinstall, kita bisa set key via connection string
-			
+
 			// Untuk SQLCipher dengan GORM, kita perlu menggunakan custom driver
 			// Tapi untuk quick implementation, kita gunakan approach dengan pragma
 			// Database akan di-encrypt saat first access dengan key yang diberikan
 			dialector = sqlite.Open(fmt.Sprintf("%s?_pragma_key=%s&_pragma_cipher_page_size=4096", dbPath, sqlcipherKey))
-			zapLog.Info("SQLCipher encryption enabled for SQLite database", 
+			zapLog.Info("SQLCipher encryption enabled for SQLite database",
 				zap.String("db_path", dbPath),
 				zap.Bool("encryption_enabled", true))
 		} else {
@@ -94,6 +104,11 @@ func InitDB() {
 		zapLog.Fatal("Failed to connect to database", zap.Error(err))
 	}
 
+	// Metric durasi query dan span DB (untuk query yang membawa context request)
+	if err := DB.Use(observability.NewGormPlugin()); err != nil {
+		zapLog.Fatal("Failed to register observability plugin", zap.Error(err))
+	}
+
 	// Konfigurasi connection pooling (hanya untuk PostgreSQL)
 	// SQLite tidak memerlukan connection pooling karena file-based
 	if dbURL != "" {
@@ -126,198 +141,70 @@ func InitDB() {
 		)
 	}
 
-	// Auto migrate schema
-	// Catatan: AutoMigrate akan otomatis sync schema (tambah kolom, index, dll) saat aplikasi start
-	// Ini berarti setiap kali deploy ke GCP, schema akan otomatis ter-update sesuai model terbaru
-	// TAPI: Data di local TIDAK ikut ter-copy ke production, hanya schema/structure yang di-sync
-	err = DB.AutoMigrate(
-		&domain.UserModel{},
-		&domain.TwoFactorAuth{},
-		&domain.AuditLog{},
-		&domain.UserActivityLog{}, // Permanent audit log untuk data penting (report, document, company, user)
-		&domain.CompanyModel{},
-		&domain.RoleModel{},
-		&domain.PermissionModel{},
-		&domain.RolePermissionModel{},
-		&domain.ShareholderModel{},
-		&domain.BusinessFieldModel{},
-		&domain.DirectorModel{},
-		&domain.UserCompanyAssignmentModel{},
-		&domain.ReportModel{},          // Report Management
-		&domain.FinancialReportModel{}, // Financial Report (RKAP & Realisasi)
-		&domain.DocumentFolderModel{},
-		&domain.DocumentModel{},
-		&domain.DocumentTypeModel{}, // Document Types Management
-		&domain.ShareholderTypeModel{},
-		&domain.DirectorPositionModel{},     // Shareholder Types Management
-		&domain.NotificationModel{},         // Notifications
-		&domain.NotificationSettingsModel{}, // Notification Settings
-	)
-	if err != nil {
-		zapLog.Fatal("Failed to migrate database", zap.Error(err))
-	}
-
-	// Ensure 'role' column on users table has no default value.
-	// This is important so that new users created without an explicit role
-	// don't accidentally get a default like 'user' or 'superadmin'.
-	if err := DB.Exec("ALTER TABLE users ALTER COLUMN role DROP DEFAULT").Error; err != nil {
-		zapLog.Warn("Failed to drop default for users.role (this may be expected on SQLite or if already dropped)", zap.Error(err))
-	}
-
-	// Migration: Add shareholder_company_id field to shareholders table if not exists
-	if dbURL != "" {
-		// PostgreSQL
-		if err := DB.Exec(`
-			DO $$
-			BEGIN
-				IF NOT EXISTS (
-					SELECT 1 
-					FROM information_schema.columns 
-					WHERE table_name = 'shareholders' 
-					AND column_name = 'shareholder_company_id'
-				) THEN
-					ALTER TABLE shareholders 
-					ADD COLUMN shareholder_company_id VARCHAR(255);
-					CREATE INDEX IF NOT EXISTS idx_shareholders_shareholder_company_id ON shareholders(shareholder_company_id);
-				END IF;
-			END $$;
-		`).Error; err != nil {
-			zapLog.Warn("Failed to add shareholder_company_id column with PostgreSQL syntax (may already exist)", zap.Error(err))
+	// Log encryption status
+	if dbURL == "" {
+		enableSQLCipher := os.Getenv("ENABLE_SQLCIPHER")
+		if enableSQLCipher == "true" {
+			zapLog.Info("SQLite database encryption (SQLCipher) is enabled")
+			zapLog.Info("For production, ensure SQLCIPHER_KEY is stored securely in secret manager")
 		} else {
-			zapLog.Info("Shareholder company_id column migration completed (PostgreSQL)")
+			zapLog.Info("SQLite database encryption is disabled (set ENABLE_SQLCIPHER=true to enable)")
+			zapLog.Info("Note: For production, use PostgreSQL with Cloud SQL (automatic encryption) or filesystem encryption")
 		}
 	} else {
-		// SQLite - check if column exists first
-		var count int64
-		if err := DB.Raw(`
-			SELECT COUNT(*) FROM pragma_table_info('shareholders') WHERE name = 'shareholder_company_id'
-		`).Scan(&count).Error; err == nil && count == 0 {
-			if err := DB.Exec("ALTER TABLE shareholders ADD COLUMN shareholder_company_id VARCHAR(255)").Error; err != nil {
-				zapLog.Warn("Failed to add shareholder_company_id column to SQLite (may already exist)", zap.Error(err))
-			} else {
-				zapLog.Info("Shareholder company_id column migration completed (SQLite)")
-			}
-		} else {
-			zapLog.Info("Shareholder company_id column already exists or check failed")
-		}
+		zapLog.Info("PostgreSQL database in use")
+		zapLog.Info("For encryption at rest: GCP Cloud SQL has automatic encryption, or use filesystem encryption (LUKS) for self-hosted")
 	}
 
-	// Migration: Update ownership_percent column to support 10 decimal places
-	if dbURL != "" {
-		// PostgreSQL - change to numeric(20,10) for 10 decimal places
-		if err := DB.Exec(`
-			DO $$
-			BEGIN
-				ALTER TABLE shareholders 
-				ALTER COLUMN ownership_percent TYPE NUMERIC(20,10);
-			EXCEPTION
-				WHEN OTHERS THEN
-					-- Ignore if column doesn't exist or type is already correct
-					NULL;
-			END $$;
-		`).Error; err != nil {
-			zapLog.Warn("Failed to update ownership_percent column type (may already be correct)", zap.Error(err))
-		} else {
-			zapLog.Info("Ownership percent column type migration completed (PostgreSQL)")
-		}
-	}
+	zapLog.Info("Database connected successfully")
+}
 
-	// Migration: Add currency field to companies table if not exists
-	// This ensures backward compatibility with existing databases
-	// Try PostgreSQL syntax first (DO block)
-	if dbURL != "" {
-		// PostgreSQL
-		if err := DB.Exec(`
-			DO $$
-			BEGIN
-				IF NOT EXISTS (
-					SELECT 1 
-					FROM information_schema.columns 
-					WHERE table_name = 'companies' 
-					AND column_name = 'currency'
-				) THEN
-					ALTER TABLE companies 
-					ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'IDR';
-				END IF;
-			END $$;
-		`).Error; err != nil {
-			zapLog.Warn("Failed to add currency column with PostgreSQL syntax (may already exist)", zap.Error(err))
-		} else {
-			zapLog.Info("Currency column migration completed (PostgreSQL)")
-		}
-	} else {
-		// SQLite - check if column exists first
-		var count int64
-		if err := DB.Raw(`
-			SELECT COUNT(*) FROM pragma_table_info('companies') WHERE name = 'currency'
-		`).Scan(&count).Error; err == nil && count == 0 {
-			if err := DB.Exec("ALTER TABLE companies ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'IDR'").Error; err != nil {
-				zapLog.Warn("Failed to add currency column to SQLite (may already exist)", zap.Error(err))
-			} else {
-				zapLog.Info("Currency column migration completed (SQLite)")
-			}
-		} else {
-			zapLog.Info("Currency column already exists or check failed")
-		}
+// migrateOnStartup menentukan apakah migrasi pending dijalankan otomatis saat startup.
+// MIGRATE_ON_STARTUP=true/false; default aktif kecuali ENV=production, di mana migrasi
+// dijalankan terpisah lewat cmd/migrate sebelum aplikasi di-deploy.
+func migrateOnStartup() bool {
+	if value := os.Getenv("MIGRATE_ON_STARTUP"); value != "" {
+		return strings.EqualFold(value, "true")
 	}
+	return os.Getenv("ENV") != "production"
+}
 
-	// Create indexes untuk performance
-	// Company hierarchy indexes
-	if err := DB.Exec("CREATE INDEX IF NOT EXISTS idx_companies_parent_id ON companies(parent_id)").Error; err != nil {
-		zapLog.Warn("Failed to create index idx_companies_parent_id", zap.Error(err))
-	}
-	if err := DB.Exec("CREATE INDEX IF NOT EXISTS idx_companies_level ON companies(level)").Error; err != nil {
-		zapLog.Warn("Failed to create index idx_companies_level", zap.Error(err))
-	}
+// ensureSchema memverifikasi versi schema terhadap migrasi yang dikenal binary:
+//   - schema lebih baru dari binary (ada migrasi tidak dikenal): aplikasi berhenti
+//   - ada migrasi pending: dijalankan jika migrateOnStartup, selain itu aplikasi berhenti
+func ensureSchema() {
+	zapLog := logger.GetLogger()
 
-	// User company relationship indexes
-	if err := DB.Exec("CREATE INDEX IF NOT EXISTS idx_users_company_id ON users(company_id)").Error; err != nil {
-		zapLog.Warn("Failed to create index idx_users_company_id", zap.Error(err))
-	}
-	if err := DB.Exec("CREATE INDEX IF NOT EXISTS idx_users_role_id ON users(role_id)").Error; err != nil {
-		zapLog.Warn("Failed to create index idx_users_role_id", zap.Error(err))
+	status, err := migrations.GetStatus(DB)
+	if err != nil {
+		zapLog.Fatal("Failed to read schema version", zap.Error(err))
 	}
-
-	// Notification indexes untuk optimasi query unread count
-	// Composite index untuk query: WHERE user_id = ? AND is_read = ?
-	// Index ini sangat penting untuk optimasi GetUnreadCount yang dipanggil setiap 30 detik
-	if err := DB.Exec("CREATE INDEX IF NOT EXISTS idx_notifications_user_id_is_read ON notifications(user_id, is_read)").Error; err != nil {
-		zapLog.Warn("Failed to create composite index idx_notifications_user_id_is_read", zap.Error(err))
-	} else {
-		zapLog.Info("Composite index created for notifications", zap.String("index", "idx_notifications_user_id_is_read"))
+	if len(status.Unknown) > 0 {
+		zapLog.Fatal("Database schema is newer than this binary, refusing to start",
+			zap.Int64("schema_version", status.Current),
+			zap.Int64("expected_version", status.Latest),
+			zap.Int("unknown_migrations", len(status.Unknown)),
+		)
 	}
 
-	// Index untuk query superadmin: WHERE is_read = ? (untuk count semua unread)
-	// Coba partial index dulu (PostgreSQL), jika gagal fallback ke regular index (SQLite/PostgreSQL)
-	// Partial index lebih efisien karena hanya index row dengan is_read = false
-	if err := DB.Exec("CREATE INDEX IF NOT EXISTS idx_notifications_is_read ON notifications(is_read) WHERE is_read = false").Error; err != nil {
-		// Partial index tidak didukung (SQLite) atau gagal, gunakan regular index
-		zapLog.Debug("Partial index not supported or failed, using regular index", zap.Error(err))
-		if err := DB.Exec("CREATE INDEX IF NOT EXISTS idx_notifications_is_read_fallback ON notifications(is_read)").Error; err != nil {
-			zapLog.Warn("Failed to create index idx_notifications_is_read_fallback", zap.Error(err))
-		} else {
-			zapLog.Info("Regular index created for notifications is_read", zap.String("index", "idx_notifications_is_read_fallback"))
+	if len(status.Pending) > 0 {
+		if !migrateOnStartup() {
+			zapLog.Fatal("Database schema is out of date, run `migrate up` before starting (or set MIGRATE_ON_STARTUP=true)",
+				zap.Int64("schema_version", status.Current),
+				zap.Int64("expected_version", status.Latest),
+				zap.Int("pending_migrations", len(status.Pending)),
+			)
 		}
-	} else {
-		zapLog.Info("Partial index created for notifications is_read (PostgreSQL)", zap.String("index", "idx_notifications_is_read"))
-	}
-
-	// Log encryption status
-	if dbURL == "" {
-		enableSQLCipher := os.Getenv("ENABLE_SQLCIPHER")
-		if enableSQLCipher == "true" {
-			zapLog.Info("SQLite database encryption (SQLCipher) is enabled")
-			zapLog.Info("For production, ensure SQLCIPHER_KEY is stored securely in secret manager")
-		} else {
-			zapLog.Info("SQLite database encryption is disabled (set ENABLE_SQLCIPHER=true to enable)")
-			zapLog.Info("Note: For production, use PostgreSQL with Cloud SQL (automatic encryption) or filesystem encryption")
+		ran, err := migrations.Up(DB, 0)
+		if err != nil {
+			zapLog.Fatal("Failed to apply database migrations", zap.Error(err))
+		}
+		for _, m := range ran {
+			zapLog.Info("Applied database migration", zap.Int64("version", m.Version), zap.String("name", m.Name))
 		}
-	} else {
-		zapLog.Info("PostgreSQL database in use")
-		zapLog.Info("For encryption at rest: GCP Cloud SQL has automatic encryption, or use filesystem encryption (LUKS) for self-hosted")
 	}
 
-	zapLog.Info("Database connected and migrated successfully")
+	zapLog.Info("Database schema is up to date", zap.Int64("schema_version", status.Latest))
 }
 
 // GetDB mengembalikan instance database
@@ -0,0 +1,718 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Baseline: schema yang sebelumnya dibuat AutoMigrate di database.InitDB dan audit.InitAuditLogger.
+// Idempotent, jadi aman dijalankan di database lama yang dibuat AutoMigrate (belum punya schema_migrations)
+// maupun database baru. Schema dibekukan sebagai snapshot struct di bawah (bukan model domain), sehingga
+// perubahan model setelah baseline tidak mengubah migrasi ini dan harus lewat migrasi baru.
+func init() {
+	register(Migration{
+		Version: 20261018090000,
+		Name:    "baseline_schema",
+		Up:      baselineSchemaUp,
+		Down:    baselineSchemaDown,
+	})
+}
+
+func baselineModels() []interface{} {
+	return []interface{}{
+		&baselineUserModel{},
+		&baselineTwoFactorAuth{},
+		&baselineAuditLog{},
+		&baselineUserActivityLog{}, // Permanent audit log untuk data penting (report, document, company, user)
+		&baselineCompanyModel{},
+		&baselineRoleModel{},
+		&baselinePermissionModel{},
+		&baselineRolePermissionModel{},
+		&baselineShareholderModel{},
+		&baselineBusinessFieldModel{},
+		&baselineDirectorModel{},
+		&baselineUserCompanyAssignmentModel{},
+		&baselineFinancialReportModel{}, // Financial Report (RKAP & Realisasi), juga sumber data /reports
+		&baselineDocumentFolderModel{},
+		&baselineDocumentModel{},
+		&baselineDocumentTypeModel{}, // Document Types Management
+		&baselineShareholderTypeModel{},
+		&baselineDirectorPositionModel{},            // Shareholder Types Management
+		&baselineNotificationModel{},                // Notifications
+		&baselineNotificationSettingsModel{},        // Notification Settings
+		&baselineNotificationPreferenceModel{},      // Preferensi notifikasi (default company & override user)
+		&baselineNotificationReminderLogModel{},     // Riwayat reminder yang sudah dikirim per tahap
+		&baselineNotificationEscalationModel{},      // Rantai eskalasi notifikasi expired
+		&baselineNotificationEscalationLevelModel{}, // Riwayat level eskalasi
+		&baselineDirectorTermModel{},                // Riwayat masa jabatan pengurus
+		&baselineCompanyPositionRequirementModel{},  // Jabatan pengurus wajib per company
+		&baselineFinancialImportJobModel{},          // Job import bulk financial report
+		&baselineFinancialImportRowModel{},          // Staging baris import financial report
+		&baselineFinancialAccountMappingModel{},     // Mapping kode akun company ke taksonomi
+		&baselineFinancialRKAPPhasingModel{},        // Bobot phasing RKAP ke bulan/kuartal
+		&baselineCompanyRestructuringEventModel{},   // Riwayat restrukturisasi company (move, merge, dissolve)
+		&baselineCompanyHierarchyModel{},            // Closure table hierarki company
+		&baselineAuditSinkDeadLetter{},              // Dead-letter queue audit sink (SIEM)
+	}
+}
+
+func baselineSchemaUp(tx *gorm.DB) error {
+	if err := tx.AutoMigrate(baselineModels()...); err != nil {
+		return err
+	}
+	if !isPostgres(tx) {
+		return nil
+	}
+
+	// Kolom role (legacy) tidak boleh punya default supaya user baru tanpa role eksplisit
+	// tidak otomatis mendapat role seperti 'user' atau 'superadmin'
+	if err := tx.Exec("ALTER TABLE users ALTER COLUMN role DROP DEFAULT").Error; err != nil {
+		return err
+	}
+
+	// ownership_percent butuh 10 digit desimal dengan bagian bulat sampai 100
+	return tx.Exec(`
+		DO $$
+		BEGIN
+			ALTER TABLE shareholders
+			ALTER COLUMN ownership_percent TYPE NUMERIC(20,10);
+		EXCEPTION
+			WHEN OTHERS THEN
+				NULL;
+		END $$;
+	`).Error
+}
+
+// baselineSchemaDown menolak dijalankan: menghapus seluruh tabel aplikasi berarti menghapus semua data
+func baselineSchemaDown(tx *gorm.DB) error {
+	return ErrIrreversible
+}
+
+// Snapshot model domain saat baseline dibuat. Hanya tag gorm yang dipertahankan; TableName eksplisit
+// supaya nama tabel, index, dan constraint sama dengan database lama yang dibuat AutoMigrate.
+
+type baselineUserModel struct {
+	ID        string `gorm:"primaryKey"`
+	Username  string `gorm:"uniqueIndex;not null"`
+	Email     string `gorm:"uniqueIndex;not null"`
+	Role      string
+	Password  string  `gorm:"not null"`
+	CompanyID *string `gorm:"index"`
+	RoleID    *string `gorm:"index"`
+	IsActive  bool    `gorm:"default:true;index"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (baselineUserModel) TableName() string {
+	return "users"
+}
+
+type baselineTwoFactorAuth struct {
+	ID          string `gorm:"primaryKey"`
+	UserID      string `gorm:"uniqueIndex;not null"`
+	Secret      string `gorm:"not null"`
+	Enabled     bool   `gorm:"default:false"`
+	BackupCodes string `gorm:"type:text"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (baselineTwoFactorAuth) TableName() string {
+	return "two_factor_auths"
+}
+
+type baselineAuditLog struct {
+	ID         string `gorm:"primaryKey"`
+	UserID     string `gorm:"index"`
+	Username   string `gorm:"index"`
+	Action     string `gorm:"index;not null"`
+	Resource   string `gorm:"index"`
+	ResourceID string `gorm:"index"`
+	IPAddress  string
+	UserAgent  string
+	Details    string    `gorm:"type:text"`
+	Status     string    `gorm:"index;not null"`
+	LogType    string    `gorm:"index;default:'user_action'"`
+	CreatedAt  time.Time `gorm:"index"`
+}
+
+func (baselineAuditLog) TableName() string {
+	return "audit_logs"
+}
+
+type baselineUserActivityLog struct {
+	ID         string `gorm:"primaryKey"`
+	UserID     string `gorm:"index"`
+	Username   string `gorm:"index"`
+	Action     string `gorm:"index;not null"`
+	Resource   string `gorm:"index;not null"`
+	ResourceID string `gorm:"index"`
+	IPAddress  string
+	UserAgent  string
+	Details    string    `gorm:"type:text"`
+	Status     string    `gorm:"index;not null"`
+	CreatedAt  time.Time `gorm:"index"`
+}
+
+func (baselineUserActivityLog) TableName() string {
+	return "user_activity_logs"
+}
+
+type baselineShareholderModel struct {
+	ID                   string  `gorm:"primaryKey"`
+	CompanyID            string  `gorm:"index;not null"`
+	ShareholderCompanyID *string `gorm:"index"`
+	Type                 string  `gorm:"not null"`
+	Name                 string  `gorm:"not null"`
+	IdentityNumber       string
+	OwnershipPercent     float64 `gorm:"not null;type:decimal(10,10)"`
+	ShareCount           int64
+	ShareSheetCount      *int64
+	ShareValuePerSheet   *int64
+	AuthorizedCapital    *int64
+	PaidUpCapital        *int64
+	IsMainParent         bool `gorm:"default:false"`
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	ShareholderCompany   *baselineCompanyModel `gorm:"foreignKey:ShareholderCompanyID"`
+}
+
+func (baselineShareholderModel) TableName() string {
+	return "shareholders"
+}
+
+type baselineBusinessFieldModel struct {
+	ID                   string `gorm:"primaryKey"`
+	CompanyID            string `gorm:"index;not null"`
+	IndustrySector       string `gorm:"not null"`
+	KBLI                 string
+	MainBusinessActivity string `gorm:"type:text"`
+	AdditionalActivities string `gorm:"type:text"`
+	StartOperationDate   *time.Time
+	IsMain               bool `gorm:"default:true"`
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+func (baselineBusinessFieldModel) TableName() string {
+	return "business_fields"
+}
+
+type baselineDirectorModel struct {
+	ID              string `gorm:"primaryKey"`
+	CompanyID       string `gorm:"index;not null"`
+	Position        string `gorm:"not null"`
+	FullName        string `gorm:"not null"`
+	KTP             string
+	NPWP            string
+	StartDate       *time.Time
+	EndDate         *time.Time `gorm:"index"`
+	DomicileAddress string     `gorm:"type:text"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (baselineDirectorModel) TableName() string {
+	return "directors"
+}
+
+type baselineCompanyModel struct {
+	ID                  string `gorm:"primaryKey"`
+	Name                string `gorm:"not null;index"`
+	ShortName           string `gorm:"index"`
+	Code                string `gorm:"uniqueIndex;not null"`
+	Description         string `gorm:"type:text"`
+	NPWP                string `gorm:"index"`
+	NIB                 string `gorm:"index"`
+	Status              string `gorm:"default:'Aktif'"`
+	Logo                string
+	Phone               string
+	Fax                 string
+	Email               string
+	Website             string
+	Address             string `gorm:"type:text"`
+	OperationalAddress  string `gorm:"type:text"`
+	AuthorizedCapital   *int64
+	PaidUpCapital       *int64
+	Currency            string  `gorm:"default:'IDR';not null"`
+	ParentID            *string `gorm:"index"`
+	MainParentCompanyID *string `gorm:"index"`
+	Level               int     `gorm:"not null;default:0;index"`
+	IsActive            bool    `gorm:"default:true;index"`
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	DissolvedAt         *time.Time                   `gorm:"index"`
+	DissolutionReason   *string                      `gorm:"type:text"`
+	MergedIntoCompanyID *string                      `gorm:"index"`
+	Shareholders        []baselineShareholderModel   `gorm:"foreignKey:CompanyID"`
+	BusinessFields      []baselineBusinessFieldModel `gorm:"foreignKey:CompanyID"`
+	Directors           []baselineDirectorModel      `gorm:"foreignKey:CompanyID"`
+}
+
+func (baselineCompanyModel) TableName() string {
+	return "companies"
+}
+
+type baselineRoleModel struct {
+	ID          string `gorm:"primaryKey"`
+	Name        string `gorm:"uniqueIndex;not null"`
+	Description string `gorm:"type:text"`
+	Level       int    `gorm:"not null;default:3;index"`
+	IsSystem    bool   `gorm:"default:false;index"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (baselineRoleModel) TableName() string {
+	return "roles"
+}
+
+type baselinePermissionModel struct {
+	ID          string `gorm:"primaryKey"`
+	Name        string `gorm:"uniqueIndex;not null"`
+	Description string `gorm:"type:text"`
+	Resource    string `gorm:"not null;index"`
+	Action      string `gorm:"not null;index"`
+	Scope       string `gorm:"not null;default:'company';index"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (baselinePermissionModel) TableName() string {
+	return "permissions"
+}
+
+type baselineRolePermissionModel struct {
+	RoleID       string `gorm:"primaryKey;index"`
+	PermissionID string `gorm:"primaryKey;index"`
+	CreatedAt    time.Time
+}
+
+func (baselineRolePermissionModel) TableName() string {
+	return "role_permissions"
+}
+
+type baselineUserCompanyAssignmentModel struct {
+	ID        string  `gorm:"primaryKey"`
+	UserID    string  `gorm:"index;not null"`
+	CompanyID string  `gorm:"index;not null"`
+	RoleID    *string `gorm:"index"`
+	IsActive  bool    `gorm:"default:true;index"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	User      *baselineUserModel    `gorm:"foreignKey:UserID"`
+	Company   *baselineCompanyModel `gorm:"foreignKey:CompanyID"`
+	Role      *baselineRoleModel    `gorm:"foreignKey:RoleID"`
+}
+
+func (baselineUserCompanyAssignmentModel) TableName() string {
+	return "user_company_assignments"
+}
+
+type baselineFinancialReportModel struct {
+	ID                    string  `gorm:"primaryKey"`
+	CompanyID             string  `gorm:"index;not null"`
+	Year                  string  `gorm:"index;not null"`
+	Period                string  `gorm:"index;not null"`
+	IsRKAP                bool    `gorm:"index;default:false"`
+	InputterID            *string `gorm:"index"`
+	PeriodType            string  `gorm:"type:varchar(20);index"`
+	IsAudited             bool    `gorm:"index;default:false"`
+	RKAPVersion           int     `gorm:"index;default:0"`
+	RKAPStatus            string  `gorm:"type:varchar(20);index"`
+	EffectiveDate         *time.Time
+	RevisionReason        *string `gorm:"type:text"`
+	ApprovedBy            *string
+	ApprovedAt            *time.Time
+	CurrentAssets         int64   `gorm:"default:0"`
+	NonCurrentAssets      int64   `gorm:"default:0"`
+	ShortTermLiabilities  int64   `gorm:"default:0"`
+	LongTermLiabilities   int64   `gorm:"default:0"`
+	Equity                int64   `gorm:"default:0"`
+	Revenue               int64   `gorm:"default:0"`
+	OperatingExpenses     int64   `gorm:"default:0"`
+	OperatingProfit       int64   `gorm:"default:0"`
+	OtherIncome           int64   `gorm:"default:0"`
+	Tax                   int64   `gorm:"default:0"`
+	NetProfit             int64   `gorm:"default:0"`
+	Dividend              int64   `gorm:"default:0"`
+	OperatingCashflow     int64   `gorm:"default:0"`
+	InvestingCashflow     int64   `gorm:"default:0"`
+	FinancingCashflow     int64   `gorm:"default:0"`
+	EndingBalance         int64   `gorm:"default:0"`
+	ROE                   float64 `gorm:"type:decimal(10,2);default:0"`
+	ROI                   float64 `gorm:"type:decimal(10,2);default:0"`
+	CurrentRatio          float64 `gorm:"type:decimal(10,2);default:0"`
+	CashRatio             float64 `gorm:"type:decimal(10,2);default:0"`
+	EBITDA                int64   `gorm:"default:0"`
+	EBITDAMargin          float64 `gorm:"type:decimal(10,2);default:0"`
+	NetProfitMargin       float64 `gorm:"type:decimal(10,2);default:0"`
+	OperatingProfitMargin float64 `gorm:"type:decimal(10,2);default:0"`
+	DebtToEquity          float64 `gorm:"type:decimal(10,2);default:0"`
+	FinancialRatio        float64 `gorm:"type:decimal(10,2);default:0"`
+	Attachment            *string `gorm:"type:text"`
+	Remark                *string `gorm:"type:text"`
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+	Company               *baselineCompanyModel `gorm:"foreignKey:CompanyID"`
+	Inputter              *baselineUserModel    `gorm:"foreignKey:InputterID"`
+}
+
+func (baselineFinancialReportModel) TableName() string {
+	return "financial_reports"
+}
+
+type baselineDocumentFolderModel struct {
+	ID        string  `gorm:"primaryKey"`
+	Name      string  `gorm:"not null"`
+	CompanyID *string `gorm:"index"`
+	ParentID  *string `gorm:"index"`
+	CreatedBy string  `gorm:"index;not null;default:''"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Children  []baselineDocumentFolderModel `gorm:"foreignKey:ParentID"`
+}
+
+func (baselineDocumentFolderModel) TableName() string {
+	return "document_folders"
+}
+
+type baselineDocumentModel struct {
+	ID         string  `gorm:"primaryKey"`
+	FolderID   *string `gorm:"index"`
+	DirectorID *string `gorm:"index"`
+	Name       string  `gorm:"not null"`
+	FileName   string  `gorm:"not null"`
+	FilePath   string  `gorm:"not null"`
+	MimeType   string  `gorm:"not null"`
+	Size       int64   `gorm:"not null"`
+	Status     string  `gorm:"default:'active'"`
+	Metadata   datatypes.JSON
+	UploaderID string `gorm:"index"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Folder     *baselineDocumentFolderModel `gorm:"foreignKey:FolderID"`
+}
+
+func (baselineDocumentModel) TableName() string {
+	return "documents"
+}
+
+type baselineDocumentTypeModel struct {
+	ID         string `gorm:"primaryKey"`
+	Name       string `gorm:"uniqueIndex;not null"`
+	IsActive   bool   `gorm:"default:true;index"`
+	UsageCount int64  `gorm:"default:0"`
+	CreatedBy  string `gorm:"index;not null"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (baselineDocumentTypeModel) TableName() string {
+	return "document_types"
+}
+
+type baselineShareholderTypeModel struct {
+	ID         string `gorm:"primaryKey"`
+	Name       string `gorm:"uniqueIndex;not null"`
+	IsActive   bool   `gorm:"default:true;index"`
+	UsageCount int64  `gorm:"default:0"`
+	CreatedBy  string `gorm:"index;not null"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (baselineShareholderTypeModel) TableName() string {
+	return "shareholder_types"
+}
+
+type baselineDirectorPositionModel struct {
+	ID         string `gorm:"primaryKey"`
+	Name       string `gorm:"uniqueIndex;not null"`
+	IsActive   bool   `gorm:"default:true;index"`
+	UsageCount int64  `gorm:"default:0"`
+	CreatedBy  string `gorm:"index;not null"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (baselineDirectorPositionModel) TableName() string {
+	return "director_positions"
+}
+
+type baselineNotificationModel struct {
+	ID           string    `gorm:"primaryKey"`
+	UserID       string    `gorm:"index;not null"`
+	Type         string    `gorm:"index;not null"`
+	Title        string    `gorm:"not null"`
+	Message      string    `gorm:"type:text;not null"`
+	ResourceType string    `gorm:"index"`
+	ResourceID   *string   `gorm:"index"`
+	IsRead       bool      `gorm:"default:false;index"`
+	CreatedAt    time.Time `gorm:"index"`
+	ReadAt       *time.Time
+}
+
+func (baselineNotificationModel) TableName() string {
+	return "notifications"
+}
+
+type baselineNotificationSettingsModel struct {
+	ID                  string `gorm:"primaryKey"`
+	UserID              string `gorm:"uniqueIndex;not null"`
+	EmailEnabled        bool   `gorm:"default:true"`
+	InAppEnabled        bool   `gorm:"default:true"`
+	ExpiryThresholdDays int    `gorm:"default:14"`
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+func (baselineNotificationSettingsModel) TableName() string {
+	return "notification_settings"
+}
+
+type baselineNotificationPreferenceModel struct {
+	ID              string `gorm:"primaryKey"`
+	ScopeType       string `gorm:"uniqueIndex:idx_notification_preferences_scope;not null"`
+	ScopeID         string `gorm:"uniqueIndex:idx_notification_preferences_scope;not null"`
+	InAppEnabled    *bool
+	ThresholdDays   *int
+	ReminderOffsets *string
+	TypePreferences datatypes.JSON
+	QuietHoursStart *string
+	QuietHoursEnd   *string
+	Timezone        *string
+	UpdatedBy       string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (baselineNotificationPreferenceModel) TableName() string {
+	return "notification_preferences"
+}
+
+type baselineNotificationReminderLogModel struct {
+	ID               string    `gorm:"primaryKey"`
+	UserID           string    `gorm:"uniqueIndex:idx_notification_reminder_logs_unique;not null"`
+	ResourceType     string    `gorm:"uniqueIndex:idx_notification_reminder_logs_unique;not null"`
+	ResourceID       string    `gorm:"uniqueIndex:idx_notification_reminder_logs_unique;not null"`
+	DueDate          string    `gorm:"uniqueIndex:idx_notification_reminder_logs_unique;size:10;not null"`
+	OffsetDays       int       `gorm:"uniqueIndex:idx_notification_reminder_logs_unique"`
+	NotificationType string    `gorm:"index"`
+	NotificationID   string    `gorm:"index"`
+	SentAt           time.Time `gorm:"index"`
+}
+
+func (baselineNotificationReminderLogModel) TableName() string {
+	return "notification_reminder_logs"
+}
+
+type baselineNotificationEscalationLevelModel struct {
+	ID             string `gorm:"primaryKey"`
+	EscalationID   string `gorm:"uniqueIndex:idx_notification_escalation_levels_unique;not null"`
+	Level          int    `gorm:"uniqueIndex:idx_notification_escalation_levels_unique"`
+	CompanyID      string `gorm:"index;not null"`
+	CompanyName    string
+	RecipientIDs   datatypes.JSON
+	RecipientCount int
+	EscalatedAt    time.Time
+}
+
+func (baselineNotificationEscalationLevelModel) TableName() string {
+	return "notification_escalation_levels"
+}
+
+type baselineNotificationEscalationModel struct {
+	ID              string `gorm:"primaryKey"`
+	ResourceType    string `gorm:"uniqueIndex:idx_notification_escalations_resource;not null"`
+	ResourceID      string `gorm:"uniqueIndex:idx_notification_escalations_resource;not null"`
+	DueDate         string `gorm:"uniqueIndex:idx_notification_escalations_resource;size:10;not null"`
+	ResourceName    string
+	CompanyID       *string   `gorm:"index"`
+	FirstNotifiedAt time.Time `gorm:"index"`
+	CurrentLevel    int       `gorm:"default:0"`
+	Status          string    `gorm:"index;default:'open'"`
+	LastEscalatedAt *time.Time
+	ResolvedAt      *time.Time
+	ResolvedBy      *string
+	ResolutionNote  string `gorm:"type:text"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	Levels          []baselineNotificationEscalationLevelModel `gorm:"foreignKey:EscalationID"`
+}
+
+func (baselineNotificationEscalationModel) TableName() string {
+	return "notification_escalations"
+}
+
+type baselineDirectorTermModel struct {
+	ID                   string `gorm:"primaryKey"`
+	DirectorID           string `gorm:"index;not null"`
+	CompanyID            string `gorm:"index;not null"`
+	FullName             string
+	Position             string     `gorm:"not null"`
+	TermNumber           int        `gorm:"not null;default:1"`
+	AppointmentType      string     `gorm:"not null;default:'appointment'"`
+	StartDate            *time.Time `gorm:"index"`
+	EndDate              *time.Time `gorm:"index"`
+	Status               string     `gorm:"index;not null;default:'active'"`
+	ClosedReason         string
+	ClosedAt             *time.Time
+	PreviousTermID       *string `gorm:"index"`
+	ResolutionDocumentID *string `gorm:"index"`
+	ResolutionNumber     string
+	ResolutionDate       *time.Time
+	Notes                string `gorm:"type:text"`
+	CreatedBy            string
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	ResolutionDocument   *baselineDocumentModel `gorm:"foreignKey:ResolutionDocumentID"`
+}
+
+func (baselineDirectorTermModel) TableName() string {
+	return "director_terms"
+}
+
+type baselineCompanyPositionRequirementModel struct {
+	ID         string `gorm:"primaryKey"`
+	CompanyID  string `gorm:"uniqueIndex:idx_company_position_requirements_unique;not null"`
+	PositionID string `gorm:"uniqueIndex:idx_company_position_requirements_unique;not null"`
+	MinCount   int    `gorm:"not null;default:1"`
+	CreatedBy  string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Position   *baselineDirectorPositionModel `gorm:"foreignKey:PositionID"`
+}
+
+func (baselineCompanyPositionRequirementModel) TableName() string {
+	return "company_position_requirements"
+}
+
+type baselineFinancialImportJobModel struct {
+	ID            string `gorm:"primaryKey"`
+	FileName      string
+	Format        string `gorm:"default:'xlsx'"`
+	Status        string `gorm:"index;not null"`
+	TotalRows     int    `gorm:"default:0"`
+	ProcessedRows int    `gorm:"default:0"`
+	NewRows       int    `gorm:"default:0"`
+	OverwriteRows int    `gorm:"default:0"`
+	UnchangedRows int    `gorm:"default:0"`
+	ErrorRows     int    `gorm:"default:0"`
+	CommitTotal   int    `gorm:"default:0"`
+	CommittedRows int    `gorm:"default:0"`
+	FailedRows    int    `gorm:"default:0"`
+	ErrorMessage  string `gorm:"type:text"`
+	CreatedBy     string `gorm:"index;not null"`
+	CreatedByName string
+	StartedAt     time.Time
+	StagedAt      *time.Time
+	CommittedAt   *time.Time
+	CreatedAt     time.Time `gorm:"index"`
+	UpdatedAt     time.Time
+}
+
+func (baselineFinancialImportJobModel) TableName() string {
+	return "financial_import_jobs"
+}
+
+type baselineFinancialImportRowModel struct {
+	ID                string `gorm:"primaryKey"`
+	JobID             string `gorm:"uniqueIndex:idx_financial_import_rows_job_row;not null"`
+	RowNumber         int    `gorm:"uniqueIndex:idx_financial_import_rows_job_row"`
+	CompanyCode       string
+	CompanyID         string `gorm:"index"`
+	Year              string
+	Period            string
+	Status            string `gorm:"index;not null"`
+	ExistingReportID  *string
+	Data              datatypes.JSON
+	Changes           datatypes.JSON
+	Errors            datatypes.JSON
+	RawValues         datatypes.JSON
+	CommitStatus      string `gorm:"index"`
+	CommitError       string `gorm:"type:text"`
+	CommittedReportID *string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+func (baselineFinancialImportRowModel) TableName() string {
+	return "financial_import_rows"
+}
+
+type baselineFinancialAccountMappingModel struct {
+	ID          string `gorm:"primaryKey"`
+	CompanyID   string `gorm:"uniqueIndex:idx_financial_account_mapping_company_code;not null"`
+	AccountCode string `gorm:"uniqueIndex:idx_financial_account_mapping_company_code;not null"`
+	AccountName string
+	Concept     string  `gorm:"not null"`
+	Multiplier  float64 `gorm:"default:1"`
+	CreatedBy   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (baselineFinancialAccountMappingModel) TableName() string {
+	return "financial_account_mappings"
+}
+
+type baselineFinancialRKAPPhasingModel struct {
+	ID        string `gorm:"primaryKey"`
+	RKAPID    string `gorm:"column:rkap_id;index;not null"`
+	Period    string `gorm:"not null"`
+	Metric    string
+	Weight    float64 `gorm:"type:decimal(7,4)"`
+	CreatedAt time.Time
+}
+
+func (baselineFinancialRKAPPhasingModel) TableName() string {
+	return "financial_rkap_phasings"
+}
+
+type baselineCompanyRestructuringEventModel struct {
+	ID                  string    `gorm:"primaryKey"`
+	Action              string    `gorm:"type:varchar(20);index;not null"`
+	CompanyID           string    `gorm:"index;not null"`
+	TargetCompanyID     *string   `gorm:"index"`
+	PreviousParentID    *string   `gorm:"index"`
+	EffectiveDate       time.Time `gorm:"index;not null"`
+	Reason              *string   `gorm:"type:text"`
+	Impact              datatypes.JSON
+	PerformedBy         string `gorm:"index"`
+	PerformedByUsername string
+	CreatedAt           time.Time
+}
+
+func (baselineCompanyRestructuringEventModel) TableName() string {
+	return "company_restructuring_events"
+}
+
+type baselineCompanyHierarchyModel struct {
+	AncestorID   string `gorm:"primaryKey;column:ancestor_id"`
+	DescendantID string `gorm:"primaryKey;column:descendant_id;index"`
+	Depth        int    `gorm:"not null;index"`
+}
+
+func (baselineCompanyHierarchyModel) TableName() string {
+	return "company_hierarchy"
+}
+
+type baselineAuditSinkDeadLetter struct {
+	ID          string `gorm:"primaryKey"`
+	Sink        string `gorm:"index;not null"`
+	EventID     string `gorm:"index"`
+	Payload     string `gorm:"type:text;not null"`
+	LastError   string `gorm:"type:text"`
+	Attempts    int
+	ReplayedAt  *time.Time `gorm:"index"`
+	CreatedAt   time.Time  `gorm:"index"`
+	EventTimeAt time.Time
+}
+
+func (baselineAuditSinkDeadLetter) TableName() string {
+	return "audit_sink_dead_letters"
+}
@@ -0,0 +1,55 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// Index performa yang sebelumnya dibuat ad-hoc (CREATE INDEX IF NOT EXISTS) di database.InitDB
+// dan audit.InitAuditLogger setiap startup.
+func init() {
+	register(Migration{
+		Version: 20261018090100,
+		Name:    "performance_indexes",
+		Up:      performanceIndexesUp,
+		Down:    performanceIndexesDown,
+	})
+}
+
+var performanceIndexes = []struct {
+	name string
+	sql  string
+}{
+	// Hierarki company
+	{"idx_companies_parent_id", "CREATE INDEX IF NOT EXISTS idx_companies_parent_id ON companies(parent_id)"},
+	{"idx_companies_level", "CREATE INDEX IF NOT EXISTS idx_companies_level ON companies(level)"},
+	// Relasi user - company/role
+	{"idx_users_company_id", "CREATE INDEX IF NOT EXISTS idx_users_company_id ON users(company_id)"},
+	{"idx_users_role_id", "CREATE INDEX IF NOT EXISTS idx_users_role_id ON users(role_id)"},
+	// Unread count notifikasi: WHERE user_id = ? AND is_read = ?
+	{"idx_notifications_user_id_is_read", "CREATE INDEX IF NOT EXISTS idx_notifications_user_id_is_read ON notifications(user_id, is_read)"},
+	// Unread count superadmin: partial index hanya untuk notifikasi yang belum dibaca (PostgreSQL dan SQLite)
+	{"idx_notifications_is_read", "CREATE INDEX IF NOT EXISTS idx_notifications_is_read ON notifications(is_read) WHERE is_read = false"},
+	// Cleanup audit log berdasarkan waktu dan tipe log
+	{"idx_audit_logs_created_at_log_type", "CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at_log_type ON audit_logs(created_at, log_type)"},
+	{"idx_user_activity_logs_created_at", "CREATE INDEX IF NOT EXISTS idx_user_activity_logs_created_at ON user_activity_logs(created_at)"},
+}
+
+func performanceIndexesUp(tx *gorm.DB) error {
+	// Index non-partial lama untuk SQLite digantikan partial index idx_notifications_is_read
+	if err := tx.Exec("DROP INDEX IF EXISTS idx_notifications_is_read_fallback").Error; err != nil {
+		return err
+	}
+	for _, index := range performanceIndexes {
+		if err := tx.Exec(index.sql).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func performanceIndexesDown(tx *gorm.DB) error {
+	for i := len(performanceIndexes) - 1; i >= 0; i-- {
+		if err := tx.Exec("DROP INDEX IF EXISTS " + performanceIndexes[i].name).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
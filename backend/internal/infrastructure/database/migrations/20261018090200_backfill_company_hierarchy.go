@@ -0,0 +1,100 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+// Backfill closure table hierarki company untuk database lama (sebelumnya dijalankan di database.InitDB
+// setiap startup jika tabel kosong). Selanjutnya tabel dipelihara oleh CompanyRepository.
+func init() {
+	register(Migration{
+		Version: 20261018090200,
+		Name:    "backfill_company_hierarchy",
+		Up:      backfillCompanyHierarchyUp,
+		Down:    backfillCompanyHierarchyDown,
+	})
+}
+
+// backfillCompany adalah kolom companies yang dibaca backfill pada versi ini
+type backfillCompany struct {
+	ID       string
+	ParentID *string
+	Level    int
+}
+
+func (backfillCompany) TableName() string {
+	return "companies"
+}
+
+// backfillCompanyHierarchyPath adalah baris company_hierarchy pada versi ini
+type backfillCompanyHierarchyPath struct {
+	AncestorID   string
+	DescendantID string
+	Depth        int
+}
+
+func (backfillCompanyHierarchyPath) TableName() string {
+	return "company_hierarchy"
+}
+
+func backfillCompanyHierarchyUp(tx *gorm.DB) error {
+	var existing int64
+	if err := tx.Model(&backfillCompanyHierarchyPath{}).Count(&existing).Error; err != nil {
+		return err
+	}
+	if existing > 0 {
+		return nil
+	}
+
+	var companies []backfillCompany
+	if err := tx.Select("id", "parent_id", "level").Order("id").Find(&companies).Error; err != nil {
+		return err
+	}
+	byID := make(map[string]*backfillCompany, len(companies))
+	for i := range companies {
+		byID[companies[i].ID] = &companies[i]
+	}
+
+	var paths []backfillCompanyHierarchyPath
+	levels := make(map[string]int)
+	for i := range companies {
+		company := &companies[i]
+		paths = append(paths, backfillCompanyHierarchyPath{AncestorID: company.ID, DescendantID: company.ID})
+
+		// Naik lewat parent_id sampai root; parent yang hilang dianggap root dan siklus dihentikan
+		seen := map[string]bool{company.ID: true}
+		depth := 0
+		current := company
+		for current.ParentID != nil && *current.ParentID != "" {
+			parent, ok := byID[*current.ParentID]
+			if !ok || seen[parent.ID] {
+				break
+			}
+			seen[parent.ID] = true
+			depth++
+			paths = append(paths, backfillCompanyHierarchyPath{AncestorID: parent.ID, DescendantID: company.ID, Depth: depth})
+			current = parent
+		}
+		if depth != company.Level {
+			levels[company.ID] = depth
+		}
+	}
+
+	if len(paths) > 0 {
+		if err := tx.CreateInBatches(paths, 500).Error; err != nil {
+			return err
+		}
+	}
+	for id, level := range levels {
+		if err := tx.Model(&backfillCompany{}).Where("id = ?", id).Update("level", level).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Down tidak menghapus isi closure table: tabel tetap dibutuhkan oleh schema baseline
+// dan dipelihara CompanyRepository, jadi rollback cukup menghapus catatan migrasinya
+func backfillCompanyHierarchyDown(tx *gorm.DB) error {
+	return nil
+}
@@ -0,0 +1,55 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Tabel job export data company (data portability). Arsipnya sendiri disimpan di storage (exports/).
+func init() {
+	register(Migration{
+		Version: 20261018100000,
+		Name:    "company_export_jobs",
+		Up:      companyExportJobsUp,
+		Down:    companyExportJobsDown,
+	})
+}
+
+// companyExportJob adalah snapshot schema company_export_jobs pada versi ini
+type companyExportJob struct {
+	ID                 string `gorm:"primaryKey"`
+	CompanyID          string `gorm:"index;not null"`
+	CompanyCode        string
+	CompanyName        string
+	IncludeDescendants bool   `gorm:"default:false"`
+	Status             string `gorm:"index;not null"`
+	TotalSteps         int    `gorm:"default:0"`
+	ProcessedSteps     int    `gorm:"default:0"`
+	CurrentStep        string
+	CompanyCount       int `gorm:"default:0"`
+	DocumentCount      int `gorm:"default:0"`
+	MissingFileCount   int `gorm:"default:0"`
+	FilePath           string
+	FileSize           int64  `gorm:"default:0"`
+	ErrorMessage       string `gorm:"type:text"`
+	CreatedBy          string `gorm:"index;not null"`
+	CreatedByName      string
+	StartedAt          time.Time
+	CompletedAt        *time.Time
+	ExpiresAt          *time.Time `gorm:"index"`
+	CreatedAt          time.Time  `gorm:"index"`
+	UpdatedAt          time.Time
+}
+
+func (companyExportJob) TableName() string {
+	return "company_export_jobs"
+}
+
+func companyExportJobsUp(tx *gorm.DB) error {
+	return tx.Migrator().CreateTable(&companyExportJob{})
+}
+
+func companyExportJobsDown(tx *gorm.DB) error {
+	return tx.Migrator().DropTable(&companyExportJob{})
+}
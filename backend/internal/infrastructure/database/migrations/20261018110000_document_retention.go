@@ -0,0 +1,107 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Jadwal retensi per jenis dokumen, legal hold, dan catatan disposisi dokumen.
+func init() {
+	register(Migration{
+		Version: 20261018110000,
+		Name:    "document_retention",
+		Up:      documentRetentionUp,
+		Down:    documentRetentionDown,
+	})
+}
+
+// documentTypeRetention adalah kolom retensi yang ditambahkan ke document_types pada versi ini
+type documentTypeRetention struct {
+	RetentionDays  *int
+	RetentionBasis string `gorm:"default:'created_at'"`
+}
+
+func (documentTypeRetention) TableName() string {
+	return "document_types"
+}
+
+// legalHold adalah snapshot schema legal_holds pada versi ini
+type legalHold struct {
+	ID             string `gorm:"primaryKey"`
+	ResourceType   string `gorm:"index;not null"`
+	ResourceID     string `gorm:"index;not null"`
+	ResourceName   string
+	Reason         string `gorm:"type:text;not null"`
+	CaseReference  string
+	PlacedBy       string `gorm:"not null"`
+	PlacedByName   string
+	PlacedAt       time.Time `gorm:"index"`
+	ReleasedBy     string
+	ReleasedByName string
+	ReleasedAt     *time.Time `gorm:"index"`
+	ReleaseNote    string     `gorm:"type:text"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (legalHold) TableName() string {
+	return "legal_holds"
+}
+
+// documentDisposition adalah snapshot schema document_dispositions pada versi ini
+type documentDisposition struct {
+	ID                 string `gorm:"primaryKey"`
+	DocumentID         string `gorm:"index;not null"`
+	DocumentName       string
+	FileName           string
+	FolderID           *string
+	CompanyID          *string `gorm:"index"`
+	DocumentTypeName   string  `gorm:"index"`
+	RetentionDays      int
+	RetentionBasis     string
+	RetentionExpiredAt time.Time
+	PreviousStatus     string
+	Status             string    `gorm:"index;not null"`
+	ArchivedAt         time.Time `gorm:"index"`
+	ReviewedBy         string
+	ReviewedByName     string
+	ReviewedAt         *time.Time
+	ReviewNote         string `gorm:"type:text"`
+	RetainUntil        *time.Time
+	DisposedAt         *time.Time
+	CreatedAt          time.Time `gorm:"index"`
+	UpdatedAt          time.Time
+}
+
+func (documentDisposition) TableName() string {
+	return "document_dispositions"
+}
+
+func documentRetentionUp(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	// Baseline versi lama (AutoMigrate model domain) bisa sudah membuat kolom ini
+	for _, column := range []string{"RetentionDays", "RetentionBasis"} {
+		if !migrator.HasColumn(&documentTypeRetention{}, column) {
+			if err := migrator.AddColumn(&documentTypeRetention{}, column); err != nil {
+				return err
+			}
+		}
+	}
+	return migrator.CreateTable(&legalHold{}, &documentDisposition{})
+}
+
+func documentRetentionDown(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	if err := migrator.DropTable(&documentDisposition{}, &legalHold{}); err != nil {
+		return err
+	}
+	for _, column := range []string{"RetentionBasis", "RetentionDays"} {
+		if migrator.HasColumn(&documentTypeRetention{}, column) {
+			if err := migrator.DropColumn(&documentTypeRetention{}, column); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
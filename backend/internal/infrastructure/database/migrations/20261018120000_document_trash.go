@@ -0,0 +1,96 @@
+package migrations
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Recycle bin dokumen: kolom soft delete untuk documents dan document_folders.
+func init() {
+	register(Migration{
+		Version: 20261018120000,
+		Name:    "document_trash",
+		Up:      documentTrashUp,
+		Down:    documentTrashDown,
+	})
+}
+
+// documentTrash adalah kolom recycle bin yang ditambahkan ke documents pada versi ini
+type documentTrash struct {
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+	DeletedBy   string
+	TrashRootID string `gorm:"index"`
+}
+
+func (documentTrash) TableName() string {
+	return "documents"
+}
+
+// documentFolderTrash adalah kolom recycle bin yang ditambahkan ke document_folders pada versi ini
+type documentFolderTrash struct {
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+	DeletedBy   string
+	TrashRootID string `gorm:"index"`
+}
+
+func (documentFolderTrash) TableName() string {
+	return "document_folders"
+}
+
+var documentTrashColumns = []string{"DeletedAt", "DeletedBy", "TrashRootID"}
+
+func documentTrashUp(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	// Baseline versi lama (AutoMigrate model domain) bisa sudah membuat kolom ini
+	for _, model := range []interface{}{&documentTrash{}, &documentFolderTrash{}} {
+		for _, column := range documentTrashColumns {
+			if !migrator.HasColumn(model, column) {
+				if err := migrator.AddColumn(model, column); err != nil {
+					return err
+				}
+			}
+		}
+		for _, index := range []string{"DeletedAt", "TrashRootID"} {
+			if !migrator.HasIndex(model, index) {
+				if err := migrator.CreateIndex(model, index); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func documentTrashDown(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	// Tanpa kolom deleted_at item di recycle bin akan muncul kembali sebagai data aktif, sedangkan menghapusnya
+	// di sini bisa melanggar legal hold. Rollback ditolak sampai recycle bin dikosongkan oleh operator.
+	var trashedDocuments, trashedFolders int64
+	if err := tx.Table("documents").Where("deleted_at IS NOT NULL").Count(&trashedDocuments).Error; err != nil {
+		return err
+	}
+	if err := tx.Table("document_folders").Where("deleted_at IS NOT NULL").Count(&trashedFolders).Error; err != nil {
+		return err
+	}
+	if trashedDocuments > 0 || trashedFolders > 0 {
+		return fmt.Errorf("recycle bin still contains %d documents and %d folders: restore or purge them (release legal holds first) before rolling back", trashedDocuments, trashedFolders)
+	}
+	for _, model := range []interface{}{&documentTrash{}, &documentFolderTrash{}} {
+		for _, index := range []string{"TrashRootID", "DeletedAt"} {
+			if migrator.HasIndex(model, index) {
+				if err := migrator.DropIndex(model, index); err != nil {
+					return err
+				}
+			}
+		}
+		for _, column := range documentTrashColumns {
+			if migrator.HasColumn(model, column) {
+				if err := migrator.DropColumn(model, column); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
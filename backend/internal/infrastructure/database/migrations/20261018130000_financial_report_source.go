@@ -0,0 +1,48 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// Asal data financial report, supaya reset data development hanya menghapus realisasi dari seeder.
+func init() {
+	register(Migration{
+		Version: 20261018130000,
+		Name:    "financial_report_source",
+		Up:      financialReportSourceUp,
+		Down:    financialReportSourceDown,
+	})
+}
+
+// financialReportSource adalah kolom yang ditambahkan ke financial_reports pada versi ini
+type financialReportSource struct {
+	Source string `gorm:"type:varchar(20);index"`
+}
+
+func (financialReportSource) TableName() string {
+	return "financial_reports"
+}
+
+func financialReportSourceUp(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	if !migrator.HasColumn(&financialReportSource{}, "Source") {
+		if err := migrator.AddColumn(&financialReportSource{}, "Source"); err != nil {
+			return err
+		}
+	}
+	if !migrator.HasIndex(&financialReportSource{}, "Source") {
+		return migrator.CreateIndex(&financialReportSource{}, "Source")
+	}
+	return nil
+}
+
+func financialReportSourceDown(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	if migrator.HasIndex(&financialReportSource{}, "Source") {
+		if err := migrator.DropIndex(&financialReportSource{}, "Source"); err != nil {
+			return err
+		}
+	}
+	if migrator.HasColumn(&financialReportSource{}, "Source") {
+		return migrator.DropColumn(&financialReportSource{}, "Source")
+	}
+	return nil
+}
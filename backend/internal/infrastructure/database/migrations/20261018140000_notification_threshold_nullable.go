@@ -0,0 +1,59 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// expiry_threshold_days di notification_settings menjadi nullable tanpa default, supaya threshold yang
+// belum pernah diatur user (NULL) bisa dibedakan dari 14 yang dipilih user sendiri.
+func init() {
+	register(Migration{
+		Version: 20261018140000,
+		Name:    "notification_threshold_nullable",
+		Up:      notificationThresholdNullableUp,
+		Down:    notificationThresholdNullableDown,
+	})
+}
+
+// legacyNotificationThresholdDays adalah default kolom sebelum versi ini
+const legacyNotificationThresholdDays = 14
+
+// notificationSettingsThreshold adalah definisi kolom threshold pada versi ini
+type notificationSettingsThreshold struct {
+	ExpiryThresholdDays *int
+}
+
+func (notificationSettingsThreshold) TableName() string {
+	return "notification_settings"
+}
+
+// notificationSettingsLegacyThreshold adalah definisi kolom threshold sebelum versi ini
+type notificationSettingsLegacyThreshold struct {
+	ExpiryThresholdDays int `gorm:"default:14"`
+}
+
+func (notificationSettingsLegacyThreshold) TableName() string {
+	return "notification_settings"
+}
+
+func notificationThresholdNullableUp(tx *gorm.DB) error {
+	if isPostgres(tx) {
+		if err := tx.Exec("ALTER TABLE notification_settings ALTER COLUMN expiry_threshold_days DROP DEFAULT").Error; err != nil {
+			return err
+		}
+	} else if err := tx.Migrator().AlterColumn(&notificationSettingsThreshold{}, "ExpiryThresholdDays"); err != nil {
+		return err
+	}
+	// Baris yang tidak pernah di-update sejak dibuat masih memegang default lama, bukan pilihan user
+	return tx.Exec("UPDATE notification_settings SET expiry_threshold_days = NULL WHERE expiry_threshold_days = ? AND updated_at = created_at",
+		legacyNotificationThresholdDays).Error
+}
+
+func notificationThresholdNullableDown(tx *gorm.DB) error {
+	if err := tx.Exec("UPDATE notification_settings SET expiry_threshold_days = ? WHERE expiry_threshold_days IS NULL",
+		legacyNotificationThresholdDays).Error; err != nil {
+		return err
+	}
+	if isPostgres(tx) {
+		return tx.Exec("ALTER TABLE notification_settings ALTER COLUMN expiry_threshold_days SET DEFAULT 14").Error
+	}
+	return tx.Migrator().AlterColumn(&notificationSettingsLegacyThreshold{}, "ExpiryThresholdDays")
+}
@@ -0,0 +1,244 @@
+// Package migrations berisi migrasi schema berversi (up/down) untuk PostgreSQL dan SQLite.
+//
+// Setiap migrasi adalah file Go <version>_<name>.go yang mendaftarkan dirinya lewat register di init().
+// Version memakai timestamp UTC YYYYMMDDHHMMSS (dibuat oleh `go run ./cmd/migrate create <name>`)
+// supaya migrasi dari branch berbeda tidak bentrok. Migrasi yang sudah dijalankan dicatat di tabel
+// schema_migrations. Setiap migrasi berjalan di dalam satu transaksi bersama pencatatannya, sehingga
+// migrasi yang gagal tidak meninggalkan schema setengah jadi.
+//
+// Aturan menulis migrasi:
+//   - Jangan mengubah migrasi yang sudah di-deploy; buat migrasi baru.
+//   - Jangan memakai AutoMigrate dengan model domain di migrasi baru (model akan terus berubah);
+//     definisikan struct lokal di file migrasi atau gunakan SQL eksplisit per dialect (lihat isPostgres).
+//   - Down harus membalik Up. Jika tidak mungkin (misalnya backfill data), kembalikan ErrIrreversible.
+//   - Tabel baru juga harus didaftarkan di backup.Tables() supaya ikut di-backup.
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration adalah satu langkah perubahan schema
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// SchemaMigration adalah baris di tabel schema_migrations (satu per migrasi yang sudah dijalankan)
+type SchemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false" json:"version"`
+	Name      string    `gorm:"type:varchar(255);not null" json:"name"`
+	AppliedAt time.Time `gorm:"not null" json:"applied_at"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+var (
+	// ErrIrreversible dikembalikan Down untuk migrasi yang tidak bisa dibalik
+	ErrIrreversible = errors.New("migration is irreversible")
+	// ErrPendingMigrations: database tertinggal dari binary (jalankan migrate up)
+	ErrPendingMigrations = errors.New("database schema has pending migrations")
+	// ErrUnknownMigrations: database berisi migrasi yang tidak dikenal binary (binary lebih lama dari schema)
+	ErrUnknownMigrations = errors.New("database schema has migrations unknown to this binary")
+)
+
+var registry = map[int64]Migration{}
+
+// register dipanggil dari init() setiap file migrasi
+func register(m Migration) {
+	if m.Version <= 0 || m.Name == "" || m.Up == nil || m.Down == nil {
+		panic(fmt.Sprintf("migrations: invalid migration %d_%s", m.Version, m.Name))
+	}
+	if existing, ok := registry[m.Version]; ok {
+		panic(fmt.Sprintf("migrations: duplicate version %d (%s and %s)", m.Version, existing.Name, m.Name))
+	}
+	registry[m.Version] = m
+}
+
+// All mengembalikan semua migrasi terdaftar, urut version
+func All() []Migration {
+	all := make([]Migration, 0, len(registry))
+	for _, m := range registry {
+		all = append(all, m)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Version < all[j].Version })
+	return all
+}
+
+// Latest mengembalikan version migrasi terbaru yang dikenal binary ini
+func Latest() int64 {
+	all := All()
+	if len(all) == 0 {
+		return 0
+	}
+	return all[len(all)-1].Version
+}
+
+// Status adalah perbandingan migrasi yang dikenal binary dengan yang tercatat di database
+type Status struct {
+	Current int64             `json:"current_version"` // version tertinggi yang sudah dijalankan (0 = belum ada)
+	Latest  int64             `json:"latest_version"`  // version terbaru yang dikenal binary
+	Applied []SchemaMigration `json:"applied"`
+	Pending []Migration       `json:"-"`
+	Unknown []SchemaMigration `json:"unknown,omitempty"`
+}
+
+// GetStatus membaca schema_migrations dan membandingkannya dengan migrasi terdaftar
+func GetStatus(db *gorm.DB) (*Status, error) {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{Latest: Latest(), Applied: applied}
+	appliedSet := make(map[int64]bool, len(applied))
+	for _, row := range applied {
+		appliedSet[row.Version] = true
+		if row.Version > status.Current {
+			status.Current = row.Version
+		}
+		if _, ok := registry[row.Version]; !ok {
+			status.Unknown = append(status.Unknown, row)
+		}
+	}
+	for _, m := range All() {
+		if !appliedSet[m.Version] {
+			status.Pending = append(status.Pending, m)
+		}
+	}
+	return status, nil
+}
+
+// Err mengembalikan ErrUnknownMigrations atau ErrPendingMigrations (ter-wrap dengan versi), nil jika up to date
+func (s *Status) Err() error {
+	if len(s.Unknown) > 0 {
+		return fmt.Errorf("%w: database at version %d, binary knows up to %d", ErrUnknownMigrations, s.Current, s.Latest)
+	}
+	if len(s.Pending) > 0 {
+		return fmt.Errorf("%w: %d pending (database at version %d, expected %d)", ErrPendingMigrations, len(s.Pending), s.Current, s.Latest)
+	}
+	return nil
+}
+
+// Check memastikan schema database sama persis dengan migrasi yang dikenal binary
+func Check(db *gorm.DB) error {
+	status, err := GetStatus(db)
+	if err != nil {
+		return err
+	}
+	return status.Err()
+}
+
+// Up menjalankan semua migrasi pending sampai target (0 = terbaru), urut version.
+// Migrasi pending yang version-nya lebih kecil dari migrasi yang sudah dijalankan
+// (misalnya hasil merge branch) tetap dijalankan.
+func Up(db *gorm.DB, target int64) ([]Migration, error) {
+	var ran []Migration
+	err := withLock(db, func(conn *gorm.DB) error {
+		status, err := GetStatus(conn)
+		if err != nil {
+			return err
+		}
+		for _, m := range status.Pending {
+			if target > 0 && m.Version > target {
+				break
+			}
+			if err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := m.Up(tx); err != nil {
+					return err
+				}
+				return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now().UTC()}).Error
+			}); err != nil {
+				return fmt.Errorf("migration %d_%s up failed: %w", m.Version, m.Name, err)
+			}
+			ran = append(ran, m)
+		}
+		return nil
+	})
+	return ran, err
+}
+
+// Down membalik migrasi terakhir sebanyak steps (urut version menurun)
+func Down(db *gorm.DB, steps int) ([]Migration, error) {
+	var ran []Migration
+	err := withLock(db, func(conn *gorm.DB) error {
+		status, err := GetStatus(conn)
+		if err != nil {
+			return err
+		}
+		if len(status.Unknown) > 0 {
+			return fmt.Errorf("%w: cannot roll back with this binary", ErrUnknownMigrations)
+		}
+
+		applied := status.Applied
+		sort.Slice(applied, func(i, j int) bool { return applied[i].Version > applied[j].Version })
+		for i := 0; i < steps && i < len(applied); i++ {
+			m := registry[applied[i].Version]
+			if err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := m.Down(tx); err != nil {
+					return err
+				}
+				return tx.Delete(&SchemaMigration{}, "version = ?", m.Version).Error
+			}); err != nil {
+				return fmt.Errorf("migration %d_%s down failed: %w", m.Version, m.Name, err)
+			}
+			ran = append(ran, m)
+		}
+		return nil
+	})
+	return ran, err
+}
+
+// appliedMigrations membaca schema_migrations (kosong jika tabel belum ada, misalnya database baru)
+func appliedMigrations(db *gorm.DB) ([]SchemaMigration, error) {
+	var applied []SchemaMigration
+	if !db.Migrator().HasTable(&SchemaMigration{}) {
+		return applied, nil
+	}
+	if err := db.Order("version").Find(&applied).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	return applied, nil
+}
+
+// withLock menjalankan fn di satu koneksi yang memegang advisory lock (PostgreSQL),
+// supaya beberapa instance yang start bersamaan tidak menjalankan migrasi yang sama dua kali.
+// SQLite hanya punya satu writer sehingga tidak perlu lock.
+func withLock(db *gorm.DB, fn func(conn *gorm.DB) error) error {
+	if !isPostgres(db) {
+		if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+			return fmt.Errorf("failed to prepare schema_migrations table: %w", err)
+		}
+		return fn(db)
+	}
+	return db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", lockKey()).Error; err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", lockKey())
+		if err := conn.AutoMigrate(&SchemaMigration{}); err != nil {
+			return fmt.Errorf("failed to prepare schema_migrations table: %w", err)
+		}
+		return fn(conn)
+	})
+}
+
+func lockKey() int64 {
+	h := fnv.New64a()
+	h.Write([]byte("pedeve-dms-app:schema_migrations"))
+	return int64(h.Sum64() >> 1)
+}
+
+func isPostgres(db *gorm.DB) bool {
+	return db.Dialector.Name() == "postgres"
+}
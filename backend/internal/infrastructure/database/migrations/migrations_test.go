@@ -0,0 +1,183 @@
+package migrations
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	return db
+}
+
+func TestUpDownStatus(t *testing.T) {
+	db := openDB(t)
+	assert.True(t, errors.Is(Check(db), ErrPendingMigrations))
+
+	ran, err := Up(db, 0)
+	require.NoError(t, err)
+	assert.Len(t, ran, len(All()))
+	require.NoError(t, Check(db))
+	assert.True(t, db.Migrator().HasTable(&domain.CompanyModel{}))
+	assert.True(t, db.Migrator().HasIndex("notifications", "idx_notifications_is_read"))
+
+	ran, err = Up(db, 0)
+	require.NoError(t, err)
+	assert.Empty(t, ran)
+
+	// Turunkan semua kecuali baseline
+	n := len(All()) - 1
+	ran, err = Down(db, n)
+	require.NoError(t, err)
+	require.Len(t, ran, n)
+	assert.Equal(t, Latest(), ran[0].Version)
+	assert.False(t, db.Migrator().HasIndex("notifications", "idx_notifications_is_read"))
+	status, err := GetStatus(db)
+	require.NoError(t, err)
+	assert.Len(t, status.Pending, n)
+
+	// Baseline tidak bisa di-rollback, tabel tetap ada
+	_, err = Down(db, 10)
+	assert.True(t, errors.Is(err, ErrIrreversible))
+	assert.True(t, db.Migrator().HasTable(&domain.CompanyModel{}))
+	status, err = GetStatus(db)
+	require.NoError(t, err)
+	assert.Equal(t, int64(20261018090000), status.Current)
+}
+
+func TestUnknownVersionRejected(t *testing.T) {
+	db := openDB(t)
+	_, err := Up(db, 0)
+	require.NoError(t, err)
+	require.NoError(t, db.Create(&SchemaMigration{Version: 29990101000000, Name: "future"}).Error)
+	assert.True(t, errors.Is(Check(db), ErrUnknownMigrations))
+	_, err = Down(db, 1)
+	assert.True(t, errors.Is(err, ErrUnknownMigrations))
+}
+
+func TestBaselineOnLegacyAutoMigratedDatabase(t *testing.T) {
+	db := openDB(t)
+	require.NoError(t, db.AutoMigrate(baselineModels()...))
+	company := domain.CompanyModel{ID: "c1", Name: "Root", Code: "ROOT", IsActive: true}
+	require.NoError(t, db.Session(&gorm.Session{SkipHooks: true}).Create(&company).Error)
+	child := domain.CompanyModel{ID: "c2", Name: "Child", Code: "CHILD", ParentID: &company.ID, IsActive: true}
+	require.NoError(t, db.Session(&gorm.Session{SkipHooks: true}).Create(&child).Error)
+
+	_, err := Up(db, 0)
+	require.NoError(t, err)
+	var paths int64
+	db.Model(&domain.CompanyHierarchyModel{}).Count(&paths)
+	assert.Equal(t, int64(3), paths)
+	var depth int
+	require.NoError(t, db.Model(&domain.CompanyHierarchyModel{}).Select("depth").Where("ancestor_id = ? AND descendant_id = ?", "c1", "c2").Scan(&depth).Error)
+	assert.Equal(t, 1, depth)
+	require.NoError(t, db.First(&child, "id = ?", "c2").Error)
+	assert.Equal(t, 1, child.Level)
+}
+
+func TestFailedMigrationRollsBack(t *testing.T) {
+	db := openDB(t)
+	_, err := Up(db, 0)
+	require.NoError(t, err)
+
+	register(Migration{
+		Version: 29990101000001,
+		Name:    "broken",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Exec("CREATE TABLE broken_tmp (id integer)").Error; err != nil {
+				return err
+			}
+			return errors.New("boom")
+		},
+		Down: func(tx *gorm.DB) error { return nil },
+	})
+	defer delete(registry, 29990101000001)
+
+	_, err = Up(db, 0)
+	assert.Error(t, err)
+	assert.False(t, db.Migrator().HasTable("broken_tmp"))
+	assert.True(t, errors.Is(Check(db), ErrPendingMigrations))
+}
+
+func TestDocumentTrashDownRefusesNonEmptyTrash(t *testing.T) {
+	db := openDB(t)
+	_, err := Up(db, 0)
+	require.NoError(t, err)
+
+	steps := 0
+	for _, m := range All() {
+		if m.Version >= 20261018120000 {
+			steps++
+		}
+	}
+	doc := domain.DocumentModel{ID: "doc-1", Name: "a", FileName: "a.pdf", FilePath: "/a.pdf", MimeType: "application/pdf",
+		DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}, TrashRootID: "doc-1"}
+	require.NoError(t, db.Create(&doc).Error)
+
+	_, err = Down(db, steps)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "restore or purge")
+	var count int64
+	require.NoError(t, db.Table("documents").Where("id = ?", "doc-1").Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+	assert.True(t, db.Migrator().HasColumn("documents", "trash_root_id"))
+
+	// Setelah recycle bin dikosongkan rollback berjalan (migrasi sesudahnya sudah di-rollback di atas)
+	require.NoError(t, db.Exec("DELETE FROM documents WHERE id = 'doc-1'").Error)
+	_, err = Down(db, 1)
+	require.NoError(t, err)
+	assert.False(t, db.Migrator().HasColumn("documents", "trash_root_id"))
+}
+
+func TestNotificationThresholdNullableKeepsUserChoices(t *testing.T) {
+	db := openDB(t)
+	_, err := Up(db, 20261018130000)
+	require.NoError(t, err)
+
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	edited := created.Add(time.Hour)
+	rows := []struct {
+		id        string
+		threshold int
+		updatedAt time.Time
+	}{
+		{"untouched", 14, created},
+		{"chose-14", 14, edited},
+		{"chose-30", 30, created},
+	}
+	for _, row := range rows {
+		require.NoError(t, db.Exec("INSERT INTO notification_settings (id, user_id, expiry_threshold_days, created_at, updated_at) VALUES (?, ?, ?, ?, ?)",
+			row.id, row.id, row.threshold, created, row.updatedAt).Error)
+	}
+
+	_, err = Up(db, 0)
+	require.NoError(t, err)
+	threshold := func(id string) *int {
+		var value *int
+		require.NoError(t, db.Table("notification_settings").Where("id = ?", id).Select("expiry_threshold_days").Row().Scan(&value))
+		return value
+	}
+	assert.Nil(t, threshold("untouched"))
+	require.NotNil(t, threshold("chose-14"))
+	assert.Equal(t, 14, *threshold("chose-14"))
+	require.NotNil(t, threshold("chose-30"))
+	assert.Equal(t, 30, *threshold("chose-30"))
+
+	// Insert tanpa threshold tidak lagi mendapat default 14
+	require.NoError(t, db.Exec("INSERT INTO notification_settings (id, user_id) VALUES ('new', 'new')").Error)
+	assert.Nil(t, threshold("new"))
+
+	_, err = Down(db, 1)
+	require.NoError(t, err)
+	require.NotNil(t, threshold("untouched"))
+	assert.Equal(t, 14, *threshold("untouched"))
+}
@@ -176,6 +176,11 @@ func EncryptIfNotEncrypted(data string) (string, error) {
 	return Encrypt(data)
 }
 
+// IsInitialized mengembalikan true jika InitEncryption sudah berhasil (dipakai readiness check)
+func IsInitialized() bool {
+	return initialized
+}
+
 // GetEncryptionKeyLength mengembalikan panjang key yang diperlukan
 func GetEncryptionKeyLength() int {
 	return 32 // 32 bytes = 256 bits untuk AES-256
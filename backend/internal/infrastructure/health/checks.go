@@ -0,0 +1,203 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database/migrations"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/encryption"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/kvstore"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/secrets"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/storage"
+)
+
+// Sentinel object yang dicek di storage (tidak harus ada; yang diuji adalah akses ke bucket/direktori)
+const defaultStorageSentinel = "healthcheck/sentinel"
+
+// probe untuk self-test encryption
+const encryptionProbe = "readiness-probe"
+
+// DefaultChecks mengembalikan pemeriksaan readiness aplikasi:
+// database + versi schema, storage, key-value store, dan encryption bersifat critical;
+// secret manager dan heartbeat scheduler non-critical (aplikasi tetap melayani request
+// dengan key yang sudah dimuat saat startup, tetapi statusnya degraded).
+func DefaultChecks() []Check {
+	return []Check{
+		{Name: "database", Critical: true, Run: checkDatabase},
+		{Name: "migrations", Critical: true, Run: checkMigrations},
+		{Name: "storage", Critical: true, Run: checkStorage},
+		{Name: "kvstore", Critical: true, Run: checkKVStore},
+		{Name: "encryption", Critical: true, Run: checkEncryption},
+		{Name: "secret_manager", Critical: false, Run: checkSecretManager},
+		{Name: "scheduler", Critical: false, Run: checkScheduler},
+	}
+}
+
+func checkDatabase(ctx context.Context) Result {
+	db := database.GetDB()
+	if db == nil {
+		return Down(errors.New("database not initialized"), nil)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return Down(err, nil)
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return Down(err, nil)
+	}
+	stats := sqlDB.Stats()
+	return Up(map[string]interface{}{
+		"dialect":          db.Dialector.Name(),
+		"open_connections": stats.OpenConnections,
+		"in_use":           stats.InUse,
+		"idle":             stats.Idle,
+		"wait_count":       stats.WaitCount,
+	})
+}
+
+func checkMigrations(ctx context.Context) Result {
+	db := database.GetDB()
+	if db == nil {
+		return Down(errors.New("database not initialized"), nil)
+	}
+	status, err := migrations.GetStatus(db.WithContext(ctx))
+	if err != nil {
+		return Down(err, nil)
+	}
+	details := map[string]interface{}{
+		"current_version": status.Current,
+		"latest_version":  status.Latest,
+	}
+	if len(status.Pending) > 0 {
+		details["pending"] = len(status.Pending)
+	}
+	if len(status.Unknown) > 0 {
+		details["unknown"] = len(status.Unknown)
+	}
+	if err := status.Err(); err != nil {
+		return Down(err, details)
+	}
+	return Up(details)
+}
+
+func checkStorage(ctx context.Context) Result {
+	manager, err := storage.GetStorageManager()
+	if err != nil {
+		return Down(err, nil)
+	}
+	if closer, ok := manager.(interface{ Close() error }); ok {
+		defer closer.Close()
+	}
+
+	backend := storage.BackendName(manager)
+	details := map[string]interface{}{"backend": backend}
+
+	// GetStorageManager diam-diam fallback ke local jika client GCS gagal dibuat
+	if os.Getenv("GCP_STORAGE_ENABLED") == "true" && backend != storage.BackendGCS {
+		return Down(errors.New("GCP storage is enabled but the client could not be initialized (using local fallback)"), details)
+	}
+
+	sentinel := os.Getenv("HEALTH_STORAGE_SENTINEL")
+	if sentinel == "" {
+		sentinel = defaultStorageSentinel
+	}
+	dir, file := path.Split(sentinel)
+	exists, err := manager.FileExists(strings.Trim(dir, "/"), file)
+	if err != nil {
+		return Down(err, details)
+	}
+	details["sentinel"] = sentinel
+	details["sentinel_exists"] = exists
+
+	if local, ok := manager.(*storage.LocalStorageManager); ok {
+		details["base_path"] = local.GetBasePath()
+		info, err := os.Stat(local.GetBasePath())
+		switch {
+		case os.IsNotExist(err):
+			return Degraded("local upload directory does not exist yet", details)
+		case err != nil:
+			return Down(err, details)
+		case !info.IsDir():
+			return Down(errors.New("local upload path is not a directory"), details)
+		}
+	}
+	return Up(details)
+}
+
+func checkKVStore(ctx context.Context) Result {
+	store := kvstore.GetStore()
+	details := map[string]interface{}{"backend": store.Name()}
+	if err := store.Ping(ctx); err != nil {
+		return Down(err, details)
+	}
+	return Up(details)
+}
+
+func checkEncryption(ctx context.Context) Result {
+	if !encryption.IsInitialized() {
+		return Down(errors.New("encryption not initialized"), nil)
+	}
+	ciphertext, err := encryption.Encrypt(encryptionProbe)
+	if err != nil {
+		return Down(err, nil)
+	}
+	plaintext, err := encryption.Decrypt(ciphertext)
+	if err != nil {
+		return Down(err, nil)
+	}
+	if plaintext != encryptionProbe {
+		return Down(errors.New("encryption round-trip mismatch"), nil)
+	}
+	return Up(map[string]interface{}{"algorithm": "AES-256-GCM"})
+}
+
+func checkSecretManager(ctx context.Context) Result {
+	manager := secrets.GetSecretManager()
+	if closer, ok := manager.(interface{ Close() error }); ok {
+		defer closer.Close()
+	}
+
+	var backend string
+	switch manager.(type) {
+	case *secrets.GCPSecretManager:
+		backend = "gcp"
+	case *secrets.VaultSecretManager:
+		backend = "vault"
+	default:
+		backend = "env"
+	}
+	details := map[string]interface{}{"backend": backend}
+
+	if _, err := manager.GetEncryptionKey(); err != nil {
+		if backend == "env" {
+			// Tanpa ENCRYPTION_KEY aplikasi memakai default key development
+			return Degraded("ENCRYPTION_KEY not set, default development key in use", details)
+		}
+		return Down(err, details)
+	}
+	return Up(details)
+}
+
+func checkScheduler(ctx context.Context) Result {
+	statuses := Heartbeats()
+	details := map[string]interface{}{"schedulers": statuses}
+	if len(statuses) == 0 {
+		return Degraded("no scheduler registered", details)
+	}
+
+	stale := []string{}
+	for _, status := range statuses {
+		if status.Stale {
+			stale = append(stale, status.Name)
+		}
+	}
+	if len(stale) > 0 {
+		return Down(fmt.Errorf("scheduler heartbeat stale: %s", strings.Join(stale, ", ")), details)
+	}
+	return Up(details)
+}
@@ -0,0 +1,211 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Status kesehatan komponen maupun keseluruhan aplikasi
+type Status string
+
+const (
+	StatusUp       Status = "up"
+	StatusDegraded Status = "degraded"
+	StatusDown     Status = "down"
+)
+
+const (
+	defaultCheckTimeout = 3 * time.Second
+	defaultCacheTTL     = 5 * time.Second
+)
+
+// Check adalah satu pemeriksaan readiness.
+// Komponen Critical yang down membuat aplikasi not ready (503); komponen non-critical yang down
+// hanya menurunkan status keseluruhan menjadi degraded.
+type Check struct {
+	Name     string
+	Critical bool
+	Run      func(ctx context.Context) Result
+}
+
+// Result adalah hasil Run sebuah Check (Status kosong dianggap up)
+type Result struct {
+	Status  Status
+	Message string
+	Details map[string]interface{}
+}
+
+// ComponentReport adalah hasil pemeriksaan satu komponen beserta latensinya
+type ComponentReport struct {
+	Name      string                 `json:"name"`
+	Status    Status                 `json:"status"`
+	Critical  bool                   `json:"critical"`
+	LatencyMs float64                `json:"latency_ms"`
+	Message   string                 `json:"message,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// Report adalah hasil readiness check seluruh komponen
+type Report struct {
+	Status     Status            `json:"status"`
+	CheckedAt  time.Time         `json:"checked_at"`
+	DurationMs float64           `json:"duration_ms"`
+	Components []ComponentReport `json:"components"`
+}
+
+// Ready mengembalikan true jika tidak ada komponen critical yang down
+func (r *Report) Ready() bool {
+	return r.Status != StatusDown
+}
+
+// Up membuat Result sehat dengan detail opsional
+func Up(details map[string]interface{}) Result {
+	return Result{Status: StatusUp, Details: details}
+}
+
+// Degraded membuat Result degraded dengan pesan penjelasan
+func Degraded(message string, details map[string]interface{}) Result {
+	return Result{Status: StatusDegraded, Message: message, Details: details}
+}
+
+// Down membuat Result down dari error
+func Down(err error, details map[string]interface{}) Result {
+	return Result{Status: StatusDown, Message: err.Error(), Details: details}
+}
+
+// Checker menjalankan daftar Check secara paralel dengan timeout per komponen
+// dan menyimpan hasil terakhir selama cacheTTL agar probe yang sering tidak membebani
+// database, bucket storage, atau secret manager.
+type Checker struct {
+	checks   []Check
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	mu     sync.Mutex
+	cached *Report
+}
+
+// NewChecker membuat Checker. Timeout per komponen dari HEALTH_CHECK_TIMEOUT_SECONDS (default 3),
+// durasi cache dari HEALTH_CACHE_SECONDS (default 5, 0 = tanpa cache).
+func NewChecker(checks ...Check) *Checker {
+	return &Checker{
+		checks:   checks,
+		timeout:  envDuration("HEALTH_CHECK_TIMEOUT_SECONDS", defaultCheckTimeout),
+		cacheTTL: envDuration("HEALTH_CACHE_SECONDS", defaultCacheTTL),
+	}
+}
+
+// Check menjalankan semua pemeriksaan, atau mengembalikan hasil cache jika masih berlaku dan refresh=false
+func (c *Checker) Check(ctx context.Context, refresh bool) *Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !refresh && c.cached != nil && time.Since(c.cached.CheckedAt) < c.cacheTTL {
+		return c.cached
+	}
+
+	start := time.Now()
+	components := make([]ComponentReport, len(c.checks))
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			components[i] = c.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := &Report{
+		Status:     aggregate(components),
+		CheckedAt:  start,
+		DurationMs: milliseconds(time.Since(start)),
+		Components: components,
+	}
+	c.cached = report
+	return report
+}
+
+// run menjalankan satu Check; check yang melewati timeout dilaporkan down tanpa menunggu selesai
+func (c *Checker) run(ctx context.Context, check Check) ComponentReport {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan Result, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- Down(fmt.Errorf("check panicked: %v", r), nil)
+			}
+		}()
+		done <- check.Run(ctx)
+	}()
+
+	var result Result
+	select {
+	case result = <-done:
+	case <-ctx.Done():
+		result = Down(fmt.Errorf("timeout after %s", c.timeout), nil)
+	}
+	if result.Status == "" {
+		result.Status = StatusUp
+	}
+
+	return ComponentReport{
+		Name:      check.Name,
+		Status:    result.Status,
+		Critical:  check.Critical,
+		LatencyMs: milliseconds(time.Since(start)),
+		Message:   result.Message,
+		Details:   result.Details,
+	}
+}
+
+// aggregate: critical down -> down, komponen lain yang tidak up -> degraded
+func aggregate(components []ComponentReport) Status {
+	status := StatusUp
+	for _, component := range components {
+		switch {
+		case component.Status == StatusDown && component.Critical:
+			return StatusDown
+		case component.Status != StatusUp:
+			status = StatusDegraded
+		}
+	}
+	return status
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds < 0 {
+		return fallback
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// Checker default aplikasi (dibuat saat pertama dipakai)
+var (
+	defaultChecker     *Checker
+	defaultCheckerOnce sync.Once
+)
+
+// GetChecker mengembalikan Checker dengan DefaultChecks
+func GetChecker() *Checker {
+	defaultCheckerOnce.Do(func() {
+		defaultChecker = NewChecker(DefaultChecks()...)
+	})
+	return defaultChecker
+}
@@ -0,0 +1,95 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database/migrations"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestChecker_AggregateAndTimeout(t *testing.T) {
+	t.Setenv("HEALTH_CHECK_TIMEOUT_SECONDS", "0.05")
+	checker := NewChecker(
+		Check{Name: "ok", Critical: true, Run: func(context.Context) Result { return Up(nil) }},
+		Check{Name: "soft", Run: func(context.Context) Result { return Down(errors.New("boom"), nil) }},
+	)
+	report := checker.Check(context.Background(), true)
+	assert.Equal(t, StatusDegraded, report.Status)
+	assert.True(t, report.Ready())
+
+	slow := NewChecker(Check{Name: "slow", Critical: true, Run: func(ctx context.Context) Result {
+		time.Sleep(time.Second)
+		return Up(nil)
+	}})
+	start := time.Now()
+	report = slow.Check(context.Background(), true)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Contains(t, report.Components[0].Message, "timeout")
+
+	panics := NewChecker(Check{Name: "p", Critical: true, Run: func(context.Context) Result { panic("x") }})
+	assert.Equal(t, StatusDown, panics.Check(context.Background(), true).Status)
+}
+
+func TestChecker_Cache(t *testing.T) {
+	calls := 0
+	checker := NewChecker(Check{Name: "c", Run: func(context.Context) Result { calls++; return Up(nil) }})
+	checker.Check(context.Background(), false)
+	checker.Check(context.Background(), false)
+	assert.Equal(t, 1, calls)
+	checker.Check(context.Background(), true)
+	assert.Equal(t, 2, calls)
+}
+
+func TestHeartbeats(t *testing.T) {
+	RegisterHeartbeat("t_fresh", time.Hour, 0)
+	RegisterHeartbeat("t_late", 10*time.Millisecond, 0)
+	RegisterHeartbeat("t_beat", 10*time.Millisecond, 0)
+	time.Sleep(30 * time.Millisecond)
+	Beat("t_beat")
+	byName := map[string]HeartbeatStatus{}
+	for _, hb := range Heartbeats() {
+		byName[hb.Name] = hb
+	}
+	assert.False(t, byName["t_fresh"].Stale)
+	assert.True(t, byName["t_late"].Stale)
+	assert.False(t, byName["t_beat"].Stale)
+	assert.NotNil(t, byName["t_beat"].LastBeat)
+	assert.Equal(t, StatusDown, checkScheduler(context.Background()).Status)
+}
+
+func TestDefaultChecks(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	previous := database.DB
+	database.DB = db
+	defer func() { database.DB = previous }()
+	t.Setenv("UPLOAD_BASE_PATH", t.TempDir())
+	t.Setenv("ENCRYPTION_KEY", "0123456789abcdef0123456789abcdef")
+	require.NoError(t, encryption.InitEncryption())
+
+	assert.Equal(t, StatusUp, checkDatabase(context.Background()).Status)
+	migrationResult := checkMigrations(context.Background())
+	assert.Equal(t, StatusDown, migrationResult.Status)
+
+	_, err = migrations.Up(db, 0)
+	require.NoError(t, err)
+	assert.Equal(t, StatusUp, checkMigrations(context.Background()).Status)
+	assert.Equal(t, StatusUp, checkStorage(context.Background()).Status)
+	assert.Equal(t, StatusUp, checkKVStore(context.Background()).Status)
+	assert.Equal(t, StatusUp, checkEncryption(context.Background()).Status)
+	assert.Equal(t, StatusUp, checkSecretManager(context.Background()).Status)
+
+	t.Setenv("GCP_STORAGE_ENABLED", "true")
+	t.Setenv("GCP_STORAGE_BUCKET", "missing-bucket")
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", "/nonexistent.json")
+	assert.Equal(t, StatusDown, checkStorage(context.Background()).Status)
+}
@@ -0,0 +1,77 @@
+package health
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// heartbeat menyimpan detak terakhir satu background scheduler
+type heartbeat struct {
+	interval time.Duration
+	firstDue time.Time // batas run pertama (registrasi + initial delay)
+	lastBeat time.Time
+	beats    int64
+}
+
+var (
+	heartbeats   = map[string]*heartbeat{}
+	heartbeatsMu sync.RWMutex
+)
+
+// staleFactor: scheduler dianggap macet jika tidak berdetak selama staleFactor x interval
+const staleFactor = 2
+
+// RegisterHeartbeat mendaftarkan scheduler yang diharapkan berdetak setiap interval,
+// dengan run pertama setelah initialDelay sejak registrasi.
+func RegisterHeartbeat(name string, interval, initialDelay time.Duration) {
+	heartbeatsMu.Lock()
+	defer heartbeatsMu.Unlock()
+	heartbeats[name] = &heartbeat{interval: interval, firstDue: time.Now().Add(initialDelay)}
+}
+
+// Beat mencatat bahwa loop scheduler masih berjalan (dipanggil setiap iterasi, terlepas dari hasil job)
+func Beat(name string) {
+	heartbeatsMu.Lock()
+	defer heartbeatsMu.Unlock()
+	if hb, ok := heartbeats[name]; ok {
+		hb.lastBeat = time.Now()
+		hb.beats++
+	}
+}
+
+// HeartbeatStatus adalah snapshot heartbeat satu scheduler
+type HeartbeatStatus struct {
+	Name            string     `json:"name"`
+	IntervalSeconds float64    `json:"interval_seconds"`
+	LastBeat        *time.Time `json:"last_beat"` // nil jika belum pernah run
+	Beats           int64      `json:"beats"`
+	Stale           bool       `json:"stale"`
+}
+
+// Heartbeats mengembalikan status semua scheduler yang terdaftar (urut nama)
+func Heartbeats() []HeartbeatStatus {
+	heartbeatsMu.RLock()
+	defer heartbeatsMu.RUnlock()
+
+	now := time.Now()
+	result := make([]HeartbeatStatus, 0, len(heartbeats))
+	for name, hb := range heartbeats {
+		status := HeartbeatStatus{
+			Name:            name,
+			IntervalSeconds: hb.interval.Seconds(),
+			Beats:           hb.beats,
+		}
+		if hb.beats > 0 {
+			lastBeat := hb.lastBeat
+			status.LastBeat = &lastBeat
+			status.Stale = now.Sub(hb.lastBeat) > staleFactor*hb.interval
+		} else {
+			// Belum pernah run: macet jika run pertama terlambat lebih dari satu interval
+			status.Stale = now.Sub(hb.firstDue) > hb.interval
+		}
+		result = append(result, status)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
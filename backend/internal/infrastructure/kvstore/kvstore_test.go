@@ -0,0 +1,166 @@
+package kvstore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testStores mengembalikan backend yang diuji: memory selalu, Redis jika TEST_REDIS_URL atau REDIS_URL diset
+// (misalnya: docker run -p 6379:6379 redis:7-alpine lalu TEST_REDIS_URL=redis://localhost:6379/15)
+func testStores(t *testing.T) map[string]Store {
+	stores := map[string]Store{"memory": NewMemoryStore()}
+	redisURL := os.Getenv("TEST_REDIS_URL")
+	if redisURL == "" {
+		redisURL = os.Getenv("REDIS_URL")
+	}
+	if redisURL == "" {
+		t.Log("TEST_REDIS_URL not set, skipping Redis backend")
+		return stores
+	}
+	store, err := NewRedisStore(redisURL, "dms-test:"+uuid.NewString()+":")
+	require.NoError(t, err)
+	require.NoError(t, store.Ping(context.Background()))
+	stores["redis"] = store
+	return stores
+}
+
+func TestStoreBackends(t *testing.T) {
+	for name, store := range testStores(t) {
+		store := store
+		t.Run(name, func(t *testing.T) {
+			defer store.Close()
+			ctx := context.Background()
+
+			t.Run("Get Set Delete", func(t *testing.T) {
+				_, err := store.Get(ctx, "missing")
+				assert.ErrorIs(t, err, ErrNotFound)
+
+				require.NoError(t, store.Set(ctx, "a", "1", 0))
+				value, err := store.Get(ctx, "a")
+				require.NoError(t, err)
+				assert.Equal(t, "1", value)
+
+				require.NoError(t, store.Delete(ctx, "a"))
+				_, err = store.Get(ctx, "a")
+				assert.ErrorIs(t, err, ErrNotFound)
+			})
+
+			t.Run("TTL", func(t *testing.T) {
+				require.NoError(t, store.Set(ctx, "ttl", "x", 100*time.Millisecond))
+				_, err := store.Get(ctx, "ttl")
+				require.NoError(t, err)
+				time.Sleep(200 * time.Millisecond)
+				_, err = store.Get(ctx, "ttl")
+				assert.ErrorIs(t, err, ErrNotFound)
+			})
+
+			t.Run("DeletePrefix", func(t *testing.T) {
+				require.NoError(t, store.Set(ctx, "p:u1:a", "1", time.Minute))
+				require.NoError(t, store.Set(ctx, "p:u1:b", "1", time.Minute))
+				require.NoError(t, store.Set(ctx, "p:u10:a", "1", time.Minute))
+				require.NoError(t, store.Set(ctx, "p*:x", "1", time.Minute))
+				require.NoError(t, store.DeletePrefix(ctx, "p:u1:"))
+
+				_, err := store.Get(ctx, "p:u1:a")
+				assert.ErrorIs(t, err, ErrNotFound)
+				_, err = store.Get(ctx, "p:u10:a")
+				assert.NoError(t, err)
+
+				// Karakter glob di prefix dicocokkan apa adanya
+				require.NoError(t, store.DeletePrefix(ctx, "p*"))
+				_, err = store.Get(ctx, "p:u10:a")
+				assert.NoError(t, err)
+				_, err = store.Get(ctx, "p*:x")
+				assert.ErrorIs(t, err, ErrNotFound)
+			})
+
+			t.Run("Incr is atomic and keeps the first TTL", func(t *testing.T) {
+				var wg sync.WaitGroup
+				for i := 0; i < 50; i++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						_, err := store.Incr(ctx, "counter", 300*time.Millisecond)
+						assert.NoError(t, err)
+					}()
+				}
+				wg.Wait()
+				value, err := store.Get(ctx, "counter")
+				require.NoError(t, err)
+				assert.Equal(t, "50", value)
+
+				time.Sleep(400 * time.Millisecond)
+				count, err := store.Incr(ctx, "counter", time.Minute)
+				require.NoError(t, err)
+				assert.Equal(t, int64(1), count)
+			})
+
+			t.Run("AllowRate is a token bucket", func(t *testing.T) {
+				interval := 200 * time.Millisecond
+				var wg sync.WaitGroup
+				var mu sync.Mutex
+				allowed := 0
+				for i := 0; i < 20; i++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						ok, err := store.AllowRate(ctx, "bucket", interval, 5)
+						assert.NoError(t, err)
+						if ok {
+							mu.Lock()
+							allowed++
+							mu.Unlock()
+						}
+					}()
+				}
+				wg.Wait()
+				assert.Equal(t, 5, allowed)
+
+				// Satu token terisi per interval, bukan seluruh burst sekaligus seperti fixed window
+				time.Sleep(interval + 50*time.Millisecond)
+				ok, err := store.AllowRate(ctx, "bucket", interval, 5)
+				require.NoError(t, err)
+				assert.True(t, ok)
+				ok, err = store.AllowRate(ctx, "bucket", interval, 5)
+				require.NoError(t, err)
+				assert.False(t, ok)
+			})
+
+			t.Run("PubSub", func(t *testing.T) {
+				first, err := store.Subscribe(ctx, "events")
+				require.NoError(t, err)
+				second, err := store.Subscribe(ctx, "events")
+				require.NoError(t, err)
+				defer second.Close()
+
+				require.NoError(t, store.Publish(ctx, "events", "hello"))
+				require.NoError(t, store.Publish(ctx, "other", "ignored"))
+				for _, sub := range []Subscription{first, second} {
+					select {
+					case msg := <-sub.Messages():
+						assert.Equal(t, "hello", msg)
+					case <-time.After(2 * time.Second):
+						t.Fatal("message not received")
+					}
+				}
+
+				require.NoError(t, first.Close())
+				require.NoError(t, store.Publish(ctx, "events", fmt.Sprintf("after-%s", name)))
+				select {
+				case msg := <-second.Messages():
+					assert.Equal(t, "after-"+name, msg)
+				case <-time.After(2 * time.Second):
+					t.Fatal("message not received")
+				}
+			})
+		})
+	}
+}
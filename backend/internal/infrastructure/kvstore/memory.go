@@ -0,0 +1,243 @@
+package kvstore
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memorySubscriberBuffer kapasitas channel per subscriber; pesan dibuang jika subscriber lambat
+const memorySubscriberBuffer = 64
+
+type memoryEntry struct {
+	value     string
+	expiresAt time.Time // zero = tanpa kedaluwarsa
+}
+
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// MemoryStore adalah Store in-process. State tidak dibagi antar instance,
+// cocok untuk development, test, dan deployment satu instance.
+type MemoryStore struct {
+	mu          sync.Mutex
+	entries     map[string]memoryEntry
+	subscribers map[string]map[*memorySubscription]struct{}
+	done        chan struct{}
+	closeOnce   sync.Once
+}
+
+// NewMemoryStore membuat memory store dan menjalankan cleanup key kedaluwarsa setiap menit
+func NewMemoryStore() *MemoryStore {
+	s := &MemoryStore{
+		entries:     make(map[string]memoryEntry),
+		subscribers: make(map[string]map[*memorySubscription]struct{}),
+		done:        make(chan struct{}),
+	}
+	go s.cleanupLoop(time.Minute)
+	return s
+}
+
+func (s *MemoryStore) Name() string {
+	return "memory"
+}
+
+func (s *MemoryStore) Get(_ context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	if entry.expired(time.Now()) {
+		delete(s.entries, key)
+		return "", ErrNotFound
+	}
+	return entry.value, nil
+}
+
+func (s *MemoryStore) Set(_ context.Context, key, value string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := memoryEntry{value: value}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+	s.entries[key] = entry
+	return nil
+}
+
+func (s *MemoryStore) Delete(_ context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+func (s *MemoryStore) DeletePrefix(_ context.Context, prefix string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key := range s.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.entries, key)
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) Incr(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	entry, ok := s.entries[key]
+	if !ok || entry.expired(now) {
+		entry = memoryEntry{value: "0"}
+		if ttl > 0 {
+			entry.expiresAt = now.Add(ttl)
+		}
+	}
+	current, err := strconv.ParseInt(entry.value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("kvstore: value of %q is not an integer", key)
+	}
+	current++
+	entry.value = strconv.FormatInt(current, 10)
+	s.entries[key] = entry
+	return current, nil
+}
+
+func (s *MemoryStore) AllowRate(_ context.Context, key string, interval time.Duration, burst int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Value menyimpan theoretical arrival time (TAT) dalam unix nano
+	now := time.Now()
+	tat := now
+	if entry, ok := s.entries[key]; ok && !entry.expired(now) {
+		stored, err := strconv.ParseInt(entry.value, 10, 64)
+		if err != nil {
+			return false, fmt.Errorf("kvstore: value of %q is not a rate state", key)
+		}
+		if storedTAT := time.Unix(0, stored); storedTAT.After(now) {
+			tat = storedTAT
+		}
+	}
+	newTAT := tat.Add(interval)
+	if newTAT.Sub(now) > time.Duration(burst)*interval {
+		return false, nil
+	}
+	s.entries[key] = memoryEntry{value: strconv.FormatInt(newTAT.UnixNano(), 10), expiresAt: newTAT}
+	return true, nil
+}
+
+func (s *MemoryStore) Publish(_ context.Context, channel, message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sub := range s.subscribers[channel] {
+		select {
+		case sub.messages <- message:
+		default:
+			// Subscriber lambat: pesan dibuang agar publisher tidak ikut terblokir
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) Subscribe(_ context.Context, channel string) (Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub := &memorySubscription{
+		store:    s,
+		channel:  channel,
+		messages: make(chan string, memorySubscriberBuffer),
+	}
+	if s.subscribers[channel] == nil {
+		s.subscribers[channel] = make(map[*memorySubscription]struct{})
+	}
+	s.subscribers[channel][sub] = struct{}{}
+	return sub, nil
+}
+
+func (s *MemoryStore) Ping(_ context.Context) error {
+	return nil
+}
+
+// Close menghentikan cleanup loop dan menutup semua subscription
+func (s *MemoryStore) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for channel, subs := range s.subscribers {
+			for sub := range subs {
+				close(sub.messages)
+			}
+			delete(s.subscribers, channel)
+		}
+	})
+	return nil
+}
+
+func (s *MemoryStore) cleanupLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.removeExpired()
+		}
+	}
+}
+
+func (s *MemoryStore) removeExpired() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for key, entry := range s.entries {
+		if entry.expired(now) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+type memorySubscription struct {
+	store    *MemoryStore
+	channel  string
+	messages chan string
+}
+
+func (sub *memorySubscription) Messages() <-chan string {
+	return sub.messages
+}
+
+func (sub *memorySubscription) Close() error {
+	sub.store.mu.Lock()
+	defer sub.store.mu.Unlock()
+
+	subs := sub.store.subscribers[sub.channel]
+	if _, ok := subs[sub]; !ok {
+		return nil
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(sub.store.subscribers, sub.channel)
+	}
+	close(sub.messages)
+	return nil
+}
@@ -0,0 +1,186 @@
+package kvstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Ukuran batch SCAN/DEL untuk DeletePrefix
+const redisScanCount = 500
+
+// incrScript: INCR + PEXPIRE hanya saat key baru dibuat, atomik dalam satu script
+var incrScript = redis.NewScript(`
+local current = redis.call("INCR", KEYS[1])
+if current == 1 and tonumber(ARGV[1]) > 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return current
+`)
+
+// allowRateScript: GCRA dengan jam server Redis (TIME) supaya semua instance memakai jam yang sama.
+// KEYS[1] menyimpan theoretical arrival time (TAT) dalam mikrodetik; ARGV[1] interval, ARGV[2] burst (mikrodetik/jumlah).
+var allowRateScript = redis.NewScript(`
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local tat = tonumber(redis.call("GET", KEYS[1]) or now)
+if tat < now then
+	tat = now
+end
+local new_tat = tat + interval
+if new_tat - now > burst * interval then
+	return 0
+end
+-- string.format: konversi number Lua default (%.14g) kehilangan presisi untuk timestamp mikrodetik
+redis.call("SET", KEYS[1], string.format("%.0f", new_tat), "PX", string.format("%.0f", math.ceil((new_tat - now) / 1000)))
+return 1
+`)
+
+// RedisStore adalah Store berbasis Redis yang dibagi oleh semua instance aplikasi.
+// Semua key dan channel diberi prefix agar tidak bentrok dengan aplikasi lain di Redis yang sama.
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisStore membuat Redis store dari URL redis:// atau rediss:// (TLS)
+func NewRedisStore(redisURL, prefix string) (*RedisStore, error) {
+	options, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid REDIS_URL: %w", err)
+	}
+	return &RedisStore{client: redis.NewClient(options), prefix: prefix}, nil
+}
+
+func (s *RedisStore) Name() string {
+	return "redis"
+}
+
+func (s *RedisStore) key(key string) string {
+	return s.prefix + key
+}
+
+func (s *RedisStore) Get(ctx context.Context, key string) (string, error) {
+	value, err := s.client.Get(ctx, s.key(key)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrNotFound
+	}
+	return value, err
+}
+
+func (s *RedisStore) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	if ttl < 0 {
+		ttl = 0
+	}
+	return s.client.Set(ctx, s.key(key), value, ttl).Err()
+}
+
+func (s *RedisStore) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = s.key(key)
+	}
+	return s.client.Del(ctx, prefixed...).Err()
+}
+
+// DeletePrefix memakai SCAN (bukan KEYS) supaya Redis tidak terblokir pada keyspace besar
+func (s *RedisStore) DeletePrefix(ctx context.Context, prefix string) error {
+	pattern := escapeRedisPattern(s.key(prefix)) + "*"
+	var cursor uint64
+	for {
+		keys, next, err := s.client.Scan(ctx, cursor, pattern, redisScanCount).Result()
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if err := s.client.Del(ctx, keys...).Err(); err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+func (s *RedisStore) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return incrScript.Run(ctx, s.client, []string{s.key(key)}, ttl.Milliseconds()).Int64()
+}
+
+func (s *RedisStore) AllowRate(ctx context.Context, key string, interval time.Duration, burst int) (bool, error) {
+	allowed, err := allowRateScript.Run(ctx, s.client, []string{s.key(key)}, interval.Microseconds(), burst).Int64()
+	if err != nil {
+		return false, err
+	}
+	return allowed == 1, nil
+}
+
+func (s *RedisStore) Publish(ctx context.Context, channel, message string) error {
+	return s.client.Publish(ctx, s.key(channel), message).Err()
+}
+
+func (s *RedisStore) Subscribe(ctx context.Context, channel string) (Subscription, error) {
+	pubsub := s.client.Subscribe(ctx, s.key(channel))
+	// Tunggu konfirmasi subscribe agar pesan yang dipublish setelah fungsi ini kembali tidak terlewat
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, err
+	}
+
+	sub := &redisSubscription{pubsub: pubsub, messages: make(chan string, memorySubscriberBuffer)}
+	go func() {
+		defer close(sub.messages)
+		for msg := range pubsub.Channel() {
+			select {
+			case sub.messages <- msg.Payload:
+			default:
+				// Sama dengan memory store: subscriber lambat kehilangan pesan
+			}
+		}
+	}()
+	return sub, nil
+}
+
+func (s *RedisStore) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
+
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
+
+type redisSubscription struct {
+	pubsub   *redis.PubSub
+	messages chan string
+}
+
+func (sub *redisSubscription) Messages() <-chan string {
+	return sub.messages
+}
+
+func (sub *redisSubscription) Close() error {
+	return sub.pubsub.Close()
+}
+
+// escapeRedisPattern meng-escape karakter glob supaya prefix dicocokkan apa adanya
+func escapeRedisPattern(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
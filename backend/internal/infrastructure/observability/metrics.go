@@ -0,0 +1,183 @@
+package observability
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const metricsNamespace = "dms"
+
+// Status label untuk operasi non-HTTP (storage, job)
+const (
+	StatusSuccess = "success"
+	StatusError   = "error"
+)
+
+// Registry khusus aplikasi (bukan prometheus.DefaultRegisterer) supaya metric library lain tidak ikut ter-expose
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "http_requests_total",
+		Help:      "Jumlah HTTP request per method, route template, dan status code.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latensi HTTP request per method, route template, dan status code.",
+		Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"method", "route", "status"})
+
+	httpRequestsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "http_requests_in_flight",
+		Help:      "Jumlah HTTP request yang sedang diproses.",
+	})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Durasi query GORM per operasi dan tabel.",
+		Buckets:   []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+	}, []string{"operation", "table"})
+
+	dbQueryErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "db_query_errors_total",
+		Help:      "Jumlah query GORM yang gagal (record not found tidak dihitung).",
+	}, []string{"operation", "table"})
+
+	storageOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "storage_operation_duration_seconds",
+		Help:      "Durasi operasi storage (upload, download, delete, exists) per backend.",
+		Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"backend", "operation", "status"})
+
+	storageBytesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "storage_bytes_total",
+		Help:      "Jumlah byte yang di-upload/di-download ke storage.",
+	}, []string{"backend", "operation"})
+
+	jobRunsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "job_runs_total",
+		Help:      "Jumlah eksekusi background job (scheduler notifikasi, cleanup) per status.",
+	}, []string{"job", "status"})
+
+	jobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "job_duration_seconds",
+		Help:      "Durasi eksekusi background job.",
+		Buckets:   []float64{0.1, 0.5, 1, 5, 15, 30, 60, 300, 900},
+	}, []string{"job"})
+
+	jobLastSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "job_last_success_timestamp_seconds",
+		Help:      "Unix timestamp eksekusi background job terakhir yang berhasil.",
+	}, []string{"job"})
+
+	jobItemsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "job_items_total",
+		Help:      "Jumlah item yang diproses background job (misalnya notifikasi dibuat, record dihapus).",
+	}, []string{"job", "item"})
+
+	rateLimitRejectionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "rate_limit_rejections_total",
+		Help:      "Jumlah request yang ditolak rate limiter.",
+	}, []string{"limiter", "key_type"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestsTotal,
+		httpRequestDuration,
+		httpRequestsInFlight,
+		dbQueryDuration,
+		dbQueryErrorsTotal,
+		storageOperationDuration,
+		storageBytesTotal,
+		jobRunsTotal,
+		jobDuration,
+		jobLastSuccess,
+		jobItemsTotal,
+		rateLimitRejectionsTotal,
+	)
+}
+
+// MetricsHTTPHandler mengembalikan handler net/http untuk endpoint /metrics (format Prometheus)
+func MetricsHTTPHandler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// HTTPRequestStarted menambah gauge request in-flight; panggil HTTPRequestFinished setelah request selesai
+func HTTPRequestStarted() {
+	httpRequestsInFlight.Inc()
+}
+
+// HTTPRequestFinished mencatat metric RED untuk satu request. route adalah template route
+// (misalnya /api/v1/companies/:id), bukan path asli, agar cardinality label tetap kecil.
+func HTTPRequestFinished(method, route string, status int, duration time.Duration) {
+	httpRequestsInFlight.Dec()
+	labels := prometheus.Labels{"method": method, "route": route, "status": strconv.Itoa(status)}
+	httpRequestsTotal.With(labels).Inc()
+	httpRequestDuration.With(labels).Observe(duration.Seconds())
+}
+
+// ObserveDBQuery mencatat durasi (dan error) satu query
+func ObserveDBQuery(operation, table string, duration time.Duration, failed bool) {
+	dbQueryDuration.WithLabelValues(operation, table).Observe(duration.Seconds())
+	if failed {
+		dbQueryErrorsTotal.WithLabelValues(operation, table).Inc()
+	}
+}
+
+// ObserveStorage mencatat satu operasi storage; bytes diabaikan jika <= 0
+func ObserveStorage(backend, operation string, start time.Time, bytes int64, err error) {
+	status := StatusSuccess
+	if err != nil {
+		status = StatusError
+	}
+	storageOperationDuration.WithLabelValues(backend, operation, status).Observe(time.Since(start).Seconds())
+	if err == nil && bytes > 0 {
+		storageBytesTotal.WithLabelValues(backend, operation).Add(float64(bytes))
+	}
+}
+
+// ObserveJob mencatat hasil satu eksekusi background job
+func ObserveJob(job string, start time.Time, err error) {
+	status := StatusSuccess
+	if err != nil {
+		status = StatusError
+	}
+	jobRunsTotal.WithLabelValues(job, status).Inc()
+	jobDuration.WithLabelValues(job).Observe(time.Since(start).Seconds())
+	if err == nil {
+		jobLastSuccess.WithLabelValues(job).SetToCurrentTime()
+	}
+}
+
+// AddJobItems menambah counter item yang diproses job
+func AddJobItems(job, item string, count int) {
+	if count > 0 {
+		jobItemsTotal.WithLabelValues(job, item).Add(float64(count))
+	}
+}
+
+// RateLimitRejected mencatat request yang ditolak rate limiter (key_type: ip atau user)
+func RateLimitRejected(limiter, keyType string) {
+	rateLimitRejectionsTotal.WithLabelValues(limiter, keyType).Inc()
+}
@@ -0,0 +1,88 @@
+package observability
+
+import (
+	"context"
+	"os"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	tracerName         = "github.com/repoareta/pedeve-dms-app/backend"
+	defaultServiceName = "pedeve-backend"
+)
+
+// InitTracing mengaktifkan export trace OTLP/HTTP jika OTEL_EXPORTER_OTLP_ENDPOINT
+// atau OTEL_EXPORTER_OTLP_TRACES_ENDPOINT diset. Konfigurasi lain mengikuti env standar OpenTelemetry
+// (OTEL_EXPORTER_OTLP_HEADERS, OTEL_SERVICE_NAME, OTEL_TRACES_SAMPLER, OTEL_TRACES_SAMPLER_ARG).
+// Tanpa endpoint, tracer global tetap no-op dan StartSpan tidak menambah overhead berarti.
+// Fungsi shutdown yang dikembalikan harus dipanggil saat aplikasi berhenti untuk flush span.
+func InitTracing(ctx context.Context) (func(context.Context) error, error) {
+	zapLog := logger.GetLogger()
+	noop := func(context.Context) error { return nil }
+
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		zapLog.Info("OpenTelemetry tracing disabled (OTEL_EXPORTER_OTLP_ENDPOINT not set)")
+		return noop, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return noop, err
+	}
+
+	attrs := []attribute.KeyValue{}
+	if os.Getenv("OTEL_SERVICE_NAME") == "" {
+		attrs = append(attrs, semconv.ServiceName(defaultServiceName))
+	}
+	if env := os.Getenv("ENV"); env != "" {
+		attrs = append(attrs, semconv.DeploymentEnvironment(env))
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(attrs...))
+	if err != nil {
+		return noop, err
+	}
+
+	// Sampler default SDK: parentbased_always_on, bisa diubah lewat OTEL_TRACES_SAMPLER
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	zapLog.Info("OpenTelemetry tracing enabled", zap.String("exporter", "otlp/http"))
+	return provider.Shutdown, nil
+}
+
+// Tracer mengembalikan tracer aplikasi dari provider global
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// StartSpan memulai span internal sebagai child dari span di ctx (ctx nil diperlakukan sebagai Background)
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan menandai span error (jika err != nil) lalu menutupnya
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -83,7 +83,8 @@ func (g *GCPStorageManager) Close() error {
 // data: konten file sebagai byte array
 // contentType: MIME type (contoh: "image/png")
 // Return: public URL untuk file yang di-upload
-func (g *GCPStorageManager) UploadFile(bucketPath string, filename string, data []byte, contentType string) (string, error) {
+func (g *GCPStorageManager) UploadFile(bucketPath string, filename string, data []byte, contentType string) (_ string, err error) {
+	defer observeStorage(BackendGCS, "upload", time.Now(), int64(len(data)), &err)
 	zapLog := logger.GetLogger()
 
 	// Buat object path dalam bucket
@@ -121,14 +122,16 @@ func (g *GCPStorageManager) UploadFile(bucketPath string, filename string, data
 		return "", fmt.Errorf("failed to close writer: %w", err)
 	}
 
-	// Buat object bisa dibaca publik
-	if err := obj.ACL().Set(g.ctx, storage.AllUsers, storage.RoleReader); err != nil {
-		zapLog.Warn("Failed to set public ACL on object (may need bucket-level IAM instead)",
-			zap.String("bucket", g.bucketName),
-			zap.String("object_path", objectPath),
-			zap.Error(err),
-		)
-		// Continue anyway, as bucket might use Uniform access control
+	// Buat object bisa dibaca publik (kecuali folder privat seperti arsip export)
+	if !isPrivateBucketPath(bucketPath) {
+		if err := obj.ACL().Set(g.ctx, storage.AllUsers, storage.RoleReader); err != nil {
+			zapLog.Warn("Failed to set public ACL on object (may need bucket-level IAM instead)",
+				zap.String("bucket", g.bucketName),
+				zap.String("object_path", objectPath),
+				zap.Error(err),
+			)
+			// Continue anyway, as bucket might use Uniform access control
+		}
 	}
 
 	// Generate public URL
@@ -146,7 +149,8 @@ func (g *GCPStorageManager) UploadFile(bucketPath string, filename string, data
 }
 
 // DeleteFile deletes a file from GCP Cloud Storage
-func (g *GCPStorageManager) DeleteFile(bucketPath string, filename string) error {
+func (g *GCPStorageManager) DeleteFile(bucketPath string, filename string) (err error) {
+	defer observeStorage(BackendGCS, "delete", time.Now(), 0, &err)
 	zapLog := logger.GetLogger()
 
 	objectPath := fmt.Sprintf("%s/%s", bucketPath, filename)
@@ -190,13 +194,14 @@ func (g *GCPStorageManager) GetSignedURL(bucketPath string, filename string, exp
 }
 
 // FileExists checks if a file exists in GCP Cloud Storage
-func (g *GCPStorageManager) FileExists(bucketPath string, filename string) (bool, error) {
+func (g *GCPStorageManager) FileExists(bucketPath string, filename string) (_ bool, err error) {
+	defer observeStorage(BackendGCS, "exists", time.Now(), 0, &err)
 	objectPath := fmt.Sprintf("%s/%s", bucketPath, filename)
 	
 	bucket := g.client.Bucket(g.bucketName)
 	obj := bucket.Object(objectPath)
 	
-	_, err := obj.Attrs(g.ctx)
+	_, err = obj.Attrs(g.ctx)
 	if err == storage.ErrObjectNotExist {
 		return false, nil
 	}
@@ -220,7 +225,8 @@ func NewLocalStorageManager(basePath string) *LocalStorageManager {
 }
 
 // UploadFile uploads a file to local filesystem
-func (l *LocalStorageManager) UploadFile(bucketPath string, filename string, data []byte, contentType string) (string, error) {
+func (l *LocalStorageManager) UploadFile(bucketPath string, filename string, data []byte, contentType string) (_ string, err error) {
+	defer observeStorage(BackendLocal, "upload", time.Now(), int64(len(data)), &err)
 	zapLog := logger.GetLogger()
 
 	// Buat directory kalau belum ada
@@ -250,7 +256,8 @@ func (l *LocalStorageManager) UploadFile(bucketPath string, filename string, dat
 }
 
 // DeleteFile deletes a file from local filesystem
-func (l *LocalStorageManager) DeleteFile(bucketPath string, filename string) error {
+func (l *LocalStorageManager) DeleteFile(bucketPath string, filename string) (err error) {
+	defer observeStorage(BackendLocal, "delete", time.Now(), 0, &err)
 	filePath := fmt.Sprintf("%s/%s/%s", l.basePath, bucketPath, filename)
 	return os.Remove(filePath)
 }
@@ -262,9 +269,10 @@ func (l *LocalStorageManager) GetFileURL(bucketPath string, filename string) (st
 }
 
 // FileExists checks if a file exists in local filesystem
-func (l *LocalStorageManager) FileExists(bucketPath string, filename string) (bool, error) {
+func (l *LocalStorageManager) FileExists(bucketPath string, filename string) (_ bool, err error) {
+	defer observeStorage(BackendLocal, "exists", time.Now(), 0, &err)
 	filePath := fmt.Sprintf("%s/%s/%s", l.basePath, bucketPath, filename)
-	_, err := os.Stat(filePath)
+	_, err = os.Stat(filePath)
 	if os.IsNotExist(err) {
 		return false, nil
 	}
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/observability"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Label backend untuk metric dan span storage
+const (
+	BackendGCS   = "gcs"
+	BackendLocal = "local"
+)
+
+// observeStorage dipanggil lewat defer dengan pointer ke error hasil, supaya error yang
+// di-return (bukan nilai saat defer didaftarkan) yang tercatat
+func observeStorage(backend, operation string, start time.Time, bytes int64, err *error) {
+	observability.ObserveStorage(backend, operation, start, bytes, *err)
+}
+
+// BackendName mengembalikan label backend storage manager (gcs/local) untuk metric dan span
+func BackendName(manager StorageManager) string {
+	if _, ok := manager.(*GCPStorageManager); ok {
+		return BackendGCS
+	}
+	return BackendLocal
+}
+
+// UploadFileWithContext sama dengan manager.UploadFile, dengan span "storage.upload" sebagai child dari span di ctx
+func UploadFileWithContext(ctx context.Context, manager StorageManager, bucketPath, filename string, data []byte, contentType string) (string, error) {
+	_, span := observability.StartSpan(ctx, "storage.upload",
+		attribute.String("storage.backend", BackendName(manager)),
+		attribute.String("storage.bucket_path", bucketPath),
+		attribute.Int("storage.size", len(data)),
+	)
+	url, err := manager.UploadFile(bucketPath, filename, data, contentType)
+	observability.EndSpan(span, err)
+	return url, err
+}
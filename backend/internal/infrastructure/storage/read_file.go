@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// FilesURLPrefix adalah prefix URL proxy file backend (lihat handler ServeFile)
+const FilesURLPrefix = "/api/v1/files/"
+
+// fileReader diimplementasikan storage manager yang bisa membaca isi file untuk proses internal
+// (misalnya menempelkan logo company ke export PDF)
+type fileReader interface {
+	ReadFile(bucketPath string, filename string) ([]byte, error)
+}
+
+// ReadFile reads file content from GCP Cloud Storage
+func (g *GCPStorageManager) ReadFile(bucketPath string, filename string) (data []byte, err error) {
+	defer func(start time.Time) {
+		observeStorage(BackendGCS, "download", start, int64(len(data)), &err)
+	}(time.Now())
+	objectPath := fmt.Sprintf("%s/%s", bucketPath, filename)
+	reader, err := g.client.Bucket(g.bucketName).Object(objectPath).NewReader(g.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// ReadFile reads file content from local filesystem
+func (l *LocalStorageManager) ReadFile(bucketPath string, filename string) (data []byte, err error) {
+	defer func(start time.Time) {
+		observeStorage(BackendLocal, "download", start, int64(len(data)), &err)
+	}(time.Now())
+	return os.ReadFile(fmt.Sprintf("%s/%s/%s", l.basePath, bucketPath, filename))
+}
+
+// gcsPublicURLPrefix adalah prefix public URL GCS lama (https://storage.googleapis.com/{bucket}/{object_path})
+const gcsPublicURLPrefix = "https://storage.googleapis.com/"
+
+// ObjectPathFromURL mengubah URL file yang disimpan di database menjadi object path di storage
+// (/api/v1/files/logos/a.png, /logos/a.png, atau public URL GCS -> logos/a.png)
+func ObjectPathFromURL(fileURL string) (string, error) {
+	objectPath := fileURL
+	if strings.HasPrefix(objectPath, gcsPublicURLPrefix) {
+		// Buang nama bucket
+		parts := strings.SplitN(strings.TrimPrefix(objectPath, gcsPublicURLPrefix), "/", 2)
+		if len(parts) < 2 {
+			return "", fmt.Errorf("invalid file url: %s", fileURL)
+		}
+		objectPath = parts[1]
+	}
+	objectPath = strings.TrimPrefix(strings.TrimPrefix(objectPath, FilesURLPrefix), "/")
+	if objectPath == "" || strings.Contains(objectPath, "..") || strings.Contains(objectPath, "://") {
+		return "", fmt.Errorf("invalid file url: %s", fileURL)
+	}
+	return objectPath, nil
+}
+
+// ReadFileByURL membaca file berdasarkan URL yang disimpan di database
+// (format /api/v1/files/logos/filename.png atau /logos/filename.png)
+func ReadFileByURL(manager StorageManager, fileURL string) ([]byte, error) {
+	objectPath, err := ObjectPathFromURL(fileURL)
+	if err != nil {
+		return nil, err
+	}
+	reader, ok := manager.(fileReader)
+	if !ok {
+		return nil, fmt.Errorf("storage manager does not support reading files")
+	}
+	return reader.ReadFile(path.Dir(objectPath), path.Base(objectPath))
+}
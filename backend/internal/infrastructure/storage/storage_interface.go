@@ -1,10 +1,15 @@
 package storage
 
+import "io"
+
 // StorageManager interface untuk file storage management
 // Support multiple backends: Local filesystem, GCP Cloud Storage, dll
 type StorageManager interface {
 	// UploadFile uploads a file and returns the public URL
 	UploadFile(bucketPath string, filename string, data []byte, contentType string) (string, error)
+
+	// UploadStream uploads file content from a reader (for large files) and returns the public URL
+	UploadStream(bucketPath string, filename string, r io.Reader, contentType string) (string, error)
 	
 	// DeleteFile deletes a file from storage
 	DeleteFile(bucketPath string, filename string) error
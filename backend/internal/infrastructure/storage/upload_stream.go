@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"go.uber.org/zap"
+)
+
+// ExportsBucketPath adalah folder arsip export data; object di sini privat (tanpa ACL public-read)
+// dan hanya bisa diunduh lewat link unduh sementara
+const ExportsBucketPath = "exports"
+
+// isPrivateBucketPath mengecek apakah object di bucketPath tidak boleh dibuat public
+func isPrivateBucketPath(bucketPath string) bool {
+	return bucketPath == ExportsBucketPath
+}
+
+// UploadStream upload isi reader ke GCP Cloud Storage tanpa memuat seluruh file ke memory
+func (g *GCPStorageManager) UploadStream(bucketPath string, filename string, r io.Reader, contentType string) (_ string, err error) {
+	var written int64
+	defer func(start time.Time) {
+		observeStorage(BackendGCS, "upload", start, written, &err)
+	}(time.Now())
+	zapLog := logger.GetLogger()
+
+	objectPath := fmt.Sprintf("%s/%s", bucketPath, filename)
+	obj := g.client.Bucket(g.bucketName).Object(objectPath)
+	writer := obj.NewWriter(g.ctx)
+	writer.ContentType = contentType
+	if isPrivateBucketPath(bucketPath) {
+		writer.CacheControl = "private, no-store"
+	} else {
+		writer.CacheControl = "public, max-age=3600"
+	}
+
+	written, err = io.Copy(writer, r)
+	if err != nil {
+		writer.Close()
+		zapLog.Error("Failed to write file to GCP Storage",
+			zap.String("bucket", g.bucketName),
+			zap.String("object_path", objectPath),
+			zap.Error(err),
+		)
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		zapLog.Error("Failed to close GCP Storage writer",
+			zap.String("bucket", g.bucketName),
+			zap.String("object_path", objectPath),
+			zap.Error(err),
+		)
+		return "", fmt.Errorf("failed to close writer: %w", err)
+	}
+
+	if !isPrivateBucketPath(bucketPath) {
+		if err := obj.ACL().Set(g.ctx, storage.AllUsers, storage.RoleReader); err != nil {
+			zapLog.Warn("Failed to set public ACL on object (may need bucket-level IAM instead)",
+				zap.String("bucket", g.bucketName),
+				zap.String("object_path", objectPath),
+				zap.Error(err),
+			)
+		}
+	}
+
+	zapLog.Info("File uploaded successfully to GCP Storage",
+		zap.String("bucket", g.bucketName),
+		zap.String("object_path", objectPath),
+		zap.Int64("size", written),
+	)
+	return fmt.Sprintf("https://storage.googleapis.com/%s/%s", g.bucketName, objectPath), nil
+}
+
+// UploadStream upload isi reader ke local filesystem tanpa memuat seluruh file ke memory
+func (l *LocalStorageManager) UploadStream(bucketPath string, filename string, r io.Reader, contentType string) (_ string, err error) {
+	var written int64
+	defer func(start time.Time) {
+		observeStorage(BackendLocal, "upload", start, written, &err)
+	}(time.Now())
+
+	dirPath := fmt.Sprintf("%s/%s", l.basePath, bucketPath)
+	if err := os.MkdirAll(dirPath, 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+	file, err := os.Create(fmt.Sprintf("%s/%s", dirPath, filename))
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %w", err)
+	}
+	written, err = io.Copy(file, r)
+	if err != nil {
+		file.Close()
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to close file: %w", err)
+	}
+	return fmt.Sprintf("/%s/%s", bucketPath, filename), nil
+}
@@ -1,26 +1,25 @@
 package middleware
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/base64"
-	"sync"
+	"errors"
 	"time"
 
 	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/kvstore"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
 )
 
-// CSRF token store (in-memory, bisa diganti dengan Redis untuk production)
-var csrfTokens = make(map[string]time.Time)
-var csrfMutex sync.RWMutex
-
 const (
 	csrfTokenHeader    = "X-CSRF-Token"
 	csrfTokenCookie    = "csrf_token"
 	csrfTokenExpiry    = 24 * time.Hour
-	csrfTokenCleanupInterval = 1 * time.Hour
+	csrfTokenKeyPrefix = "csrf:"
+	csrfStoreTimeout   = 2 * time.Second
 )
 
 // GenerateCSRFToken menghasilkan token CSRF baru
@@ -32,64 +31,32 @@ func GenerateCSRFToken() (string, error) {
 	return base64.URLEncoding.EncodeToString(tokenBytes), nil
 }
 
-// StoreCSRFToken menyimpan token CSRF dengan masa kedaluwarsa
-func StoreCSRFToken(token string) {
-	csrfMutex.Lock()
-	defer csrfMutex.Unlock()
-	csrfTokens[token] = time.Now().Add(csrfTokenExpiry)
+// StoreCSRFToken menyimpan token CSRF di kvstore dengan masa kedaluwarsa.
+// Dengan Redis, token yang diterbitkan satu instance bisa divalidasi instance lain.
+func StoreCSRFToken(token string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), csrfStoreTimeout)
+	defer cancel()
+	return kvstore.GetStore().Set(ctx, csrfTokenKeyPrefix+token, "1", csrfTokenExpiry)
 }
 
-// ValidateCSRFToken memvalidasi token CSRF
+// ValidateCSRFToken memvalidasi token CSRF (token expired otomatis hilang karena TTL store)
 func ValidateCSRFToken(token string) bool {
 	if token == "" {
 		return false
 	}
 
-	csrfMutex.RLock()
-	defer csrfMutex.RUnlock()
-
-	expiry, exists := csrfTokens[token]
-	if !exists {
-		return false
-	}
-
-	if time.Now().After(expiry) {
-		// Token expired, hapus
-		csrfMutex.RUnlock()
-		csrfMutex.Lock()
-		delete(csrfTokens, token)
-		csrfMutex.Unlock()
-		csrfMutex.RLock()
+	ctx, cancel := context.WithTimeout(context.Background(), csrfStoreTimeout)
+	defer cancel()
+	_, err := kvstore.GetStore().Get(ctx, csrfTokenKeyPrefix+token)
+	if err != nil {
+		if !errors.Is(err, kvstore.ErrNotFound) {
+			logger.GetLogger().Error("Failed to read CSRF token from store", zap.Error(err))
+		}
 		return false
 	}
-
 	return true
 }
 
-// CleanupExpiredCSRFTokens menghapus token CSRF yang expired
-func CleanupExpiredCSRFTokens() {
-	csrfMutex.Lock()
-	defer csrfMutex.Unlock()
-
-	now := time.Now()
-	for token, expiry := range csrfTokens {
-		if now.After(expiry) {
-			delete(csrfTokens, token)
-		}
-	}
-}
-
-// StartCSRFTokenCleanup memulai cleanup background untuk token yang expired
-func StartCSRFTokenCleanup() {
-	go func() {
-		ticker := time.NewTicker(csrfTokenCleanupInterval)
-		defer ticker.Stop()
-		for range ticker.C {
-			CleanupExpiredCSRFTokens()
-		}
-	}()
-}
-
 // CSRFMiddleware memvalidasi token CSRF untuk request yang mengubah state (untuk Fiber)
 func CSRFMiddleware(c *fiber.Ctx) error {
 	// Skip pengecekan CSRF untuk method yang aman (GET, HEAD, OPTIONS)
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/kvstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func middlewareTestStores(t *testing.T) map[string]kvstore.Store {
+	stores := map[string]kvstore.Store{"memory": kvstore.NewMemoryStore()}
+	if redisURL := os.Getenv("TEST_REDIS_URL"); redisURL != "" {
+		store, err := kvstore.NewRedisStore(redisURL, "dms-test:"+uuid.NewString()+":")
+		require.NoError(t, err)
+		require.NoError(t, store.Ping(context.Background()))
+		stores["redis"] = store
+	}
+	return stores
+}
+
+// newInstance mensimulasikan satu instance API; semua instance memakai kvstore global yang sama
+func newInstance(limiter *RateLimiter) *fiber.App {
+	app := fiber.New()
+	app.Get("/csrf", func(c *fiber.Ctx) error {
+		token, err := GenerateCSRFToken()
+		if err != nil {
+			return err
+		}
+		if err := StoreCSRFToken(token); err != nil {
+			return err
+		}
+		return c.SendString(token)
+	})
+	app.Post("/mutate", RateLimitMiddleware(limiter), CSRFMiddleware, func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	return app
+}
+
+func TestSharedStoreAcrossInstances(t *testing.T) {
+	for name, store := range middlewareTestStores(t) {
+		t.Run(name, func(t *testing.T) {
+			kvstore.SetStore(store)
+			defer kvstore.SetStore(kvstore.NewMemoryStore())
+
+			// burst 3 tanpa refill: 3 request untuk semua instance
+			limiter := NewRateLimiter("test-"+uuid.NewString(), rate.Limit(0), 3)
+			instanceA, instanceB := newInstance(limiter), newInstance(limiter)
+
+			resp, err := instanceA.Test(httptest.NewRequest(fiber.MethodGet, "/csrf", nil))
+			require.NoError(t, err)
+			buf := make([]byte, 128)
+			n, _ := resp.Body.Read(buf)
+			token := string(buf[:n])
+			require.NotEmpty(t, token)
+
+			post := func(app *fiber.App, csrf string) int {
+				req := httptest.NewRequest(fiber.MethodPost, "/mutate", nil)
+				req.Header.Set("X-Forwarded-For", "10.0.0.1")
+				if csrf != "" {
+					req.Header.Set(csrfTokenHeader, csrf)
+				}
+				resp, err := app.Test(req)
+				require.NoError(t, err)
+				return resp.StatusCode
+			}
+
+			// Token dari instance A diterima instance B
+			assert.Equal(t, fiber.StatusNoContent, post(instanceB, token))
+			assert.Equal(t, fiber.StatusForbidden, post(instanceB, "unknown-token"))
+			assert.Equal(t, fiber.StatusNoContent, post(instanceA, token))
+
+			// Limit dihitung bersama: request ke-4 ditolak di instance mana pun
+			assert.Equal(t, fiber.StatusTooManyRequests, post(instanceB, token))
+			assert.Equal(t, fiber.StatusTooManyRequests, post(instanceA, token))
+		})
+	}
+}
+
+// unavailableStore mensimulasikan Redis yang down
+type unavailableStore struct {
+	kvstore.Store
+}
+
+func (unavailableStore) AllowRate(context.Context, string, time.Duration, int) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func TestRateLimiterFallsBackToMemoryWhenStoreUnavailable(t *testing.T) {
+	kvstore.SetStore(unavailableStore{Store: kvstore.NewMemoryStore()})
+	defer kvstore.SetStore(kvstore.NewMemoryStore())
+
+	limiter := NewRateLimiter("auth-"+uuid.NewString(), rate.Limit(5.0/60.0), 2)
+	assert.True(t, limiter.Allow("10.0.0.1"))
+	assert.True(t, limiter.Allow("10.0.0.1"))
+	assert.False(t, limiter.Allow("10.0.0.1"), "limit tetap berlaku saat store tidak tersedia")
+	assert.True(t, limiter.Allow("10.0.0.2"))
+}
+
+func TestRateLimiterRefillsGradually(t *testing.T) {
+	kvstore.SetStore(kvstore.NewMemoryStore())
+	defer kvstore.SetStore(kvstore.NewMemoryStore())
+
+	// 10 rps, burst 2: setelah burst habis hanya satu request per 100ms (tidak ada reset window yang menggandakan limit)
+	limiter := NewRateLimiter("test-"+uuid.NewString(), rate.Limit(10), 2)
+	assert.True(t, limiter.Allow("k"))
+	assert.True(t, limiter.Allow("k"))
+	assert.False(t, limiter.Allow("k"))
+	time.Sleep(120 * time.Millisecond)
+	assert.True(t, limiter.Allow("k"))
+	assert.False(t, limiter.Allow("k"))
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/observability"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// unmatchedRoute adalah label route untuk request yang tidak cocok dengan route manapun (404),
+// supaya path acak dari scanner tidak membuat label metric baru
+const unmatchedRoute = "unmatched"
+
+// ObservabilityMiddleware mencatat metric RED per route template dan membuat span server per request.
+// Context span disimpan di c.UserContext() sehingga handler bisa meneruskannya ke usecase/repository/storage.
+func ObservabilityMiddleware(c *fiber.Ctx) error {
+	start := time.Now()
+	observability.HTTPRequestStarted()
+
+	// Lanjutkan trace dari header traceparent jika ada (misalnya dari load balancer atau frontend)
+	headers := make(http.Header)
+	c.Request().Header.VisitAll(func(key, value []byte) {
+		headers.Add(string(key), string(value))
+	})
+	ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), propagation.HeaderCarrier(headers))
+	ctx, span := observability.Tracer().Start(ctx, c.Method(),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("http.request.method", c.Method()),
+			attribute.String("url.path", c.Path()),
+			attribute.String("client.address", getClientIP(c)),
+		),
+	)
+	c.SetUserContext(ctx)
+
+	err := c.Next()
+
+	// Error yang dikembalikan handler baru menjadi status code setelah error handler Fiber,
+	// jadi status diturunkan dari error lebih dulu
+	status := c.Response().StatusCode()
+	route := c.Route().Path
+	if err != nil {
+		status = fiber.StatusInternalServerError
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			status = fiberErr.Code
+			// Tidak ada route yang cocok: Fiber mengembalikan ErrNotFound dan c.Route() berisi group/middleware terakhir
+			if fiberErr.Code == fiber.StatusNotFound {
+				route = unmatchedRoute
+			}
+		}
+	}
+	observability.HTTPRequestFinished(c.Method(), route, status, time.Since(start))
+
+	span.SetName(c.Method() + " " + route)
+	span.SetAttributes(
+		attribute.String("http.route", route),
+		attribute.Int("http.response.status_code", status),
+	)
+	if status >= fiber.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+
+	return err
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/observability"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObservabilityMiddleware_RouteTemplates(t *testing.T) {
+	app := fiber.New()
+	app.Use(ObservabilityMiddleware)
+	api := app.Group("/obs")
+	api.Get("/companies/:id", func(c *fiber.Ctx) error { return c.SendString("ok") })
+	api.Get("/fail", func(c *fiber.Ctx) error { return fiber.NewError(fiber.StatusBadGateway, "upstream") })
+
+	for _, path := range []string{"/obs/companies/1", "/obs/companies/2", "/obs/fail", "/obs/random-scanner-path"} {
+		_, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+		require.NoError(t, err)
+	}
+
+	rec := httptest.NewRecorder()
+	observability.MetricsHTTPHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	metrics := string(body)
+	assert.Contains(t, metrics, `dms_http_requests_total{method="GET",route="/obs/companies/:id",status="200"} 2`)
+	assert.Contains(t, metrics, `dms_http_requests_total{method="GET",route="/obs/fail",status="502"} 1`)
+	assert.Contains(t, metrics, `dms_http_requests_total{method="GET",route="unmatched",status="404"}`)
+	assert.NotContains(t, metrics, "random-scanner-path")
+	assert.Contains(t, metrics, "dms_http_requests_in_flight 0")
+}
@@ -1,77 +1,78 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/config"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/kvstore"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/observability"
 	"go.uber.org/zap"
 	"golang.org/x/time/rate"
 )
 
-// RateLimiter holds rate limiters for different endpoints
+// RateLimiter holds rate limiters for different endpoints.
+// Token bucket (GCRA) disimpan di kvstore (Redis jika dikonfigurasi) sehingga limit berlaku
+// untuk semua instance di belakang load balancer, bukan per instance.
 type RateLimiter struct {
-	visitors map[string]*visitor
-	mu       sync.RWMutex
-	rate     rate.Limit
+	name     string
+	interval time.Duration // Waktu pengisian satu token (1/rps); 0 berarti tanpa limit
 	burst    int
+	// fallback dipakai saat store bersama tidak bisa diakses, agar proteksi brute force tetap aktif (per instance)
+	fallback *kvstore.MemoryStore
 }
 
-type visitor struct {
-	limiter  *rate.Limiter
-	lastSeen time.Time
-}
-
-// NewRateLimiter creates a new rate limiter
-func NewRateLimiter(rps rate.Limit, burst int) *RateLimiter {
-	rl := &RateLimiter{
-		visitors: make(map[string]*visitor),
-		rate:     rps,
+// NewRateLimiter creates a new rate limiter dengan semantik token bucket yang sama seperti rate.Limiter:
+// burst request boleh langsung, lalu satu request per 1/rps detik.
+func NewRateLimiter(name string, rps rate.Limit, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	var interval time.Duration
+	switch {
+	case rps == rate.Inf:
+		interval = 0
+	case rps <= 0:
+		// Tanpa refill: token burst hanya terisi kembali setelah 24 jam tidak ada request
+		interval = 24 * time.Hour
+	default:
+		interval = time.Duration(float64(time.Second) / float64(rps))
+	}
+	return &RateLimiter{
+		name:     name,
+		interval: interval,
 		burst:    burst,
+		fallback: kvstore.NewMemoryStore(),
 	}
-
-	// Bersihkan visitor lama setiap menit
-	go rl.cleanupVisitors()
-
-	return rl
 }
 
-// GetVisitor gets or creates a visitor limiter
-func (rl *RateLimiter) GetVisitor(ip string) *rate.Limiter {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
-
-	v, exists := rl.visitors[ip]
-	if !exists {
-		limiter := rate.NewLimiter(rl.rate, rl.burst)
-		rl.visitors[ip] = &visitor{
-			limiter:  limiter,
-			lastSeen: time.Now(),
-		}
-		return limiter
+// Allow memakai satu token untuk key (IP atau user ID) dan mengembalikan false jika limit terlampaui.
+// Jika store bersama tidak bisa diakses, limit dihitung di memory instance ini (bukan fail open),
+// supaya gangguan Redis tidak mematikan proteksi brute force di login.
+func (rl *RateLimiter) Allow(key string) bool {
+	if rl.interval == 0 {
+		return true
 	}
+	bucketKey := fmt.Sprintf("ratelimit:%s:%s", rl.name, key)
 
-	v.lastSeen = time.Now()
-	return v.limiter
-}
-
-// cleanupVisitors removes old visitors
-func (rl *RateLimiter) cleanupVisitors() {
-	for {
-		time.Sleep(time.Minute)
-		rl.mu.Lock()
-		for ip, v := range rl.visitors {
-			if time.Since(v.lastSeen) > 3*time.Minute {
-				delete(rl.visitors, ip)
-			}
-		}
-		rl.mu.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	allowed, err := kvstore.GetStore().AllowRate(ctx, bucketKey, rl.interval, rl.burst)
+	if err == nil {
+		return allowed
 	}
+
+	logger.GetLogger().Warn("Rate limit store unavailable, using in-memory limiter",
+		zap.String("limiter", rl.name),
+		zap.Error(err),
+	)
+	allowed, err = rl.fallback.AllowRate(ctx, bucketKey, rl.interval, rl.burst)
+	return err == nil && allowed
 }
 
 // Global rate limiters (akan di-initialize dari config)
@@ -90,7 +91,7 @@ func InitRateLimiters() {
 
 	// Initialize General rate limiter
 	generalRate, generalBurst := cfg.RateLimit.GetGeneralRateLimit()
-	GeneralRateLimiter = NewRateLimiter(generalRate, generalBurst)
+	GeneralRateLimiter = NewRateLimiter("general", generalRate, generalBurst)
 	zapLog.Info("General rate limiter initialized",
 		zap.Float64("rps", cfg.RateLimit.General.RPS),
 		zap.Int("burst", cfg.RateLimit.General.Burst),
@@ -98,7 +99,7 @@ func InitRateLimiters() {
 
 	// Initialize Auth rate limiter
 	authRate, authBurst := cfg.RateLimit.GetAuthRateLimit()
-	AuthRateLimiter = NewRateLimiter(authRate, authBurst)
+	AuthRateLimiter = NewRateLimiter("auth", authRate, authBurst)
 	zapLog.Info("Auth rate limiter initialized",
 		zap.Int("rpm", cfg.RateLimit.Auth.RPM),
 		zap.Int("burst", cfg.RateLimit.Auth.Burst),
@@ -106,7 +107,7 @@ func InitRateLimiters() {
 
 	// Initialize Strict rate limiter
 	strictRate, strictBurst := cfg.RateLimit.GetStrictRateLimit()
-	StrictRateLimiter = NewRateLimiter(strictRate, strictBurst)
+	StrictRateLimiter = NewRateLimiter("strict", strictRate, strictBurst)
 	zapLog.Info("Strict rate limiter initialized",
 		zap.Int("rpm", cfg.RateLimit.Strict.RPM),
 		zap.Int("burst", cfg.RateLimit.Strict.Burst),
@@ -142,10 +143,9 @@ func RateLimitMiddleware(limiter *RateLimiter) fiber.Handler {
 		}
 
 		ip := getClientIP(c)
-		visitorLimiter := limiter.GetVisitor(ip)
-
 		// Check if request is allowed
-		if !visitorLimiter.Allow() {
+		if !limiter.Allow(ip) {
+			observability.RateLimitRejected(limiter.name, "ip")
 			zapLog := logger.GetLogger()
 			zapLog.Warn("Rate limit exceeded",
 				zap.String("ip", ip),
@@ -179,8 +179,8 @@ func UserRateLimitMiddleware(limiter *RateLimiter) fiber.Handler {
 		if userIDVal == nil {
 			// Jika tidak ada userID, fallback ke IP-based (untuk backward compatibility)
 			ip := getClientIP(c)
-			visitorLimiter := limiter.GetVisitor(ip)
-			if !visitorLimiter.Allow() {
+			if !limiter.Allow(ip) {
+				observability.RateLimitRejected(limiter.name, "ip")
 				zapLog := logger.GetLogger()
 				zapLog.Warn("Rate limit exceeded (IP-based fallback)",
 					zap.String("ip", ip),
@@ -195,10 +195,9 @@ func UserRateLimitMiddleware(limiter *RateLimiter) fiber.Handler {
 		}
 
 		userID := fmt.Sprintf("%v", userIDVal)
-		visitorLimiter := limiter.GetVisitor(userID)
-
 		// Check if request is allowed
-		if !visitorLimiter.Allow() {
+		if !limiter.Allow("user:" + userID) {
+			observability.RateLimitRejected(limiter.name, "user")
 			zapLog := logger.GetLogger()
 			zapLog.Warn("Rate limit exceeded (user-based)",
 				zap.String("user_id", userID),
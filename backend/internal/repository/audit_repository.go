@@ -15,9 +15,13 @@ var PermanentResources = []string{
 	"report",           // Report Management
 	"financial_report", // Financial Report (RKAP & Realisasi)
 	"document",         // Document Management
+	"folder",           // Folder dokumen
 	"company",          // Subsidiary
 	"user",             // User Management
 	"notification",     // Notification Management
+	"shareholder",      // Pemegang saham (riwayat perubahan per entity)
+	"director",         // Pengurus (riwayat perubahan per entity)
+	"role",             // Role Management
 }
 
 // IsPermanentResource mengecek apakah resource termasuk permanent (tidak akan dihapus)
@@ -182,3 +186,69 @@ func GetUserActivityLogs(userID, action, resource, resourceID, status string, li
 	err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&logs).Error
 	return logs, total, err
 }
+
+// GetUserActivityLogsByEntity mengambil semua user activity logs untuk satu entity (resource + resource ID),
+// diurutkan dari yang paling lama supaya bisa dibaca sebagai timeline
+func GetUserActivityLogsByEntity(resource, resourceID string) ([]domain.UserActivityLog, error) {
+	var logs []domain.UserActivityLog
+	err := database.GetDB().
+		Where("resource = ? AND resource_id = ?", resource, resourceID).
+		Order("created_at ASC").
+		Find(&logs).Error
+	return logs, err
+}
+
+// GetEntityCompanyID mengembalikan company pemilik entity untuk pengecekan akses riwayat.
+// String kosong berarti entity tidak terikat company (misalnya role) atau sudah tidak ada.
+func GetEntityCompanyID(resource, resourceID string) (string, error) {
+	db := database.GetDB()
+	var companyID *string
+	pluck := func(model interface{}, column, id string) error {
+		var values []*string
+		if err := db.Unscoped().Model(model).Where("id = ?", id).Limit(1).Pluck(column, &values).Error; err != nil {
+			return err
+		}
+		companyID = nil
+		if len(values) > 0 {
+			companyID = values[0]
+		}
+		return nil
+	}
+
+	var err error
+	switch resource {
+	case "company":
+		err = pluck(&domain.CompanyModel{}, "id", resourceID)
+	case "report", "financial_report":
+		err = pluck(&domain.FinancialReportModel{}, "company_id", resourceID)
+	case "shareholder":
+		err = pluck(&domain.ShareholderModel{}, "company_id", resourceID)
+	case "director":
+		err = pluck(&domain.DirectorModel{}, "company_id", resourceID)
+	case "user":
+		err = pluck(&domain.UserModel{}, "company_id", resourceID)
+	case "notification":
+		// Notifikasi mengikuti company penerimanya
+		if err = pluck(&domain.NotificationModel{}, "user_id", resourceID); err == nil && companyID != nil {
+			err = pluck(&domain.UserModel{}, "company_id", *companyID)
+		}
+	case "folder":
+		err = pluck(&domain.DocumentFolderModel{}, "company_id", resourceID)
+	case "document":
+		// Dokumen mengikuti company folder-nya, atau company pengurus untuk dokumen individu
+		var doc domain.DocumentModel
+		if err = db.Unscoped().Select("folder_id", "director_id").Where("id = ?", resourceID).Limit(1).Find(&doc).Error; err != nil {
+			break
+		}
+		if doc.FolderID != nil {
+			err = pluck(&domain.DocumentFolderModel{}, "company_id", *doc.FolderID)
+		}
+		if err == nil && (companyID == nil || *companyID == "") && doc.DirectorID != nil {
+			err = pluck(&domain.DirectorModel{}, "company_id", *doc.DirectorID)
+		}
+	}
+	if err != nil || companyID == nil {
+		return "", err
+	}
+	return *companyID, nil
+}
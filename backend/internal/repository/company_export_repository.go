@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// CompanyExportJobFilter untuk filter list job export data company
+type CompanyExportJobFilter struct {
+	CreatedBy string // kosong = semua user
+	CompanyID string
+	Status    string
+	Limit     int
+	Offset    int
+}
+
+// CompanyExportRepository interface untuk job export data company
+type CompanyExportRepository interface {
+	CreateJob(job *domain.CompanyExportJobModel) error
+	UpdateJob(job *domain.CompanyExportJobModel) error
+	UpdateJobProgress(id string, fields map[string]interface{}) error
+	GetJobByID(id string) (*domain.CompanyExportJobModel, error)
+	ListJobs(filter CompanyExportJobFilter) ([]domain.CompanyExportJobModel, int64, error)
+	UpdateJobsByStatus(status string, fields map[string]interface{}) (int64, error)
+	ListExpiredJobs(now time.Time) ([]domain.CompanyExportJobModel, error)
+}
+
+type companyExportRepository struct {
+	db *gorm.DB
+}
+
+// NewCompanyExportRepository creates a new company export repository
+func NewCompanyExportRepository() CompanyExportRepository {
+	return NewCompanyExportRepositoryWithDB(database.GetDB())
+}
+
+// NewCompanyExportRepositoryWithDB creates a new company export repository with injected DB (for testing)
+func NewCompanyExportRepositoryWithDB(db *gorm.DB) CompanyExportRepository {
+	return &companyExportRepository{db: db}
+}
+
+func (r *companyExportRepository) CreateJob(job *domain.CompanyExportJobModel) error {
+	return r.db.Create(job).Error
+}
+
+func (r *companyExportRepository) UpdateJob(job *domain.CompanyExportJobModel) error {
+	return r.db.Save(job).Error
+}
+
+// UpdateJobProgress hanya update kolom progress (processed_steps, current_step, dll) tanpa menimpa field lain
+func (r *companyExportRepository) UpdateJobProgress(id string, fields map[string]interface{}) error {
+	return r.db.Model(&domain.CompanyExportJobModel{}).
+		Where("id = ?", id).
+		Updates(fields).Error
+}
+
+func (r *companyExportRepository) GetJobByID(id string) (*domain.CompanyExportJobModel, error) {
+	var job domain.CompanyExportJobModel
+	if err := r.db.Where("id = ?", id).First(&job).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *companyExportRepository) ListJobs(filter CompanyExportJobFilter) ([]domain.CompanyExportJobModel, int64, error) {
+	query := r.db.Model(&domain.CompanyExportJobModel{})
+	if filter.CreatedBy != "" {
+		query = query.Where("created_by = ?", filter.CreatedBy)
+	}
+	if filter.CompanyID != "" {
+		query = query.Where("company_id = ?", filter.CompanyID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit).Offset(filter.Offset)
+	}
+
+	var jobs []domain.CompanyExportJobModel
+	err := query.Order("created_at DESC").Find(&jobs).Error
+	return jobs, total, err
+}
+
+// UpdateJobsByStatus update semua job dengan status tertentu (dipakai untuk memulihkan job yang terhenti saat restart)
+func (r *companyExportRepository) UpdateJobsByStatus(status string, fields map[string]interface{}) (int64, error) {
+	result := r.db.Model(&domain.CompanyExportJobModel{}).
+		Where("status = ?", status).
+		Updates(fields)
+	return result.RowsAffected, result.Error
+}
+
+// ListExpiredJobs mengambil job completed yang masa simpan arsipnya sudah lewat
+func (r *companyExportRepository) ListExpiredJobs(now time.Time) ([]domain.CompanyExportJobModel, error) {
+	var jobs []domain.CompanyExportJobModel
+	err := r.db.Where("status = ? AND expires_at IS NOT NULL AND expires_at <= ?", domain.CompanyExportStatusCompleted, now).
+		Order("expires_at ASC").
+		Find(&jobs).Error
+	return jobs, err
+}
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Pemeliharaan closure table company_hierarchy. Dipanggil di transaksi yang sama dengan perubahan
+// companies supaya hierarki tidak pernah tertinggal dari parent_id.
+
+// ErrCompanyHierarchyCycle dikembalikan jika parent baru adalah company itu sendiri atau turunannya
+var ErrCompanyHierarchyCycle = errors.New("parent company tidak boleh company itu sendiri atau turunannya")
+
+// InsertCompanyHierarchy menambahkan path company baru: diri sendiri + seluruh ancestor parent.
+// Idempotent: path yang sudah ada tidak diubah.
+func InsertCompanyHierarchy(tx *gorm.DB, companyID string, parentID *string) error {
+	self := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&domain.CompanyHierarchyModel{AncestorID: companyID, DescendantID: companyID})
+	if self.Error != nil {
+		return fmt.Errorf("failed to create company hierarchy: %w", self.Error)
+	}
+	if self.RowsAffected == 0 || parentID == nil || *parentID == "" {
+		return nil
+	}
+	err := tx.Exec(`INSERT INTO company_hierarchy (ancestor_id, descendant_id, depth)
+		SELECT ancestor_id, ?, depth + 1 FROM company_hierarchy WHERE descendant_id = ?`, companyID, *parentID).Error
+	if err != nil {
+		return fmt.Errorf("failed to create company hierarchy: %w", err)
+	}
+	return nil
+}
+
+// SyncCompanyHierarchy memindahkan subtree company di closure table jika parent di tabel companies
+// berbeda dengan parent di closure table. Dipanggil setelah parent_id company diubah.
+func SyncCompanyHierarchy(tx *gorm.DB, companyID string) error {
+	var company domain.CompanyModel
+	if err := tx.Select("id", "parent_id").Where("id = ?", companyID).First(&company).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to get company: %w", err)
+	}
+	parentID := ""
+	if company.ParentID != nil {
+		parentID = *company.ParentID
+	}
+
+	var paths []domain.CompanyHierarchyModel
+	if err := tx.Where("descendant_id = ? AND depth <= 1", companyID).Find(&paths).Error; err != nil {
+		return fmt.Errorf("failed to get company hierarchy: %w", err)
+	}
+	hasSelf, currentParentID := false, ""
+	for _, path := range paths {
+		if path.Depth == 0 {
+			hasSelf = true
+		} else {
+			currentParentID = path.AncestorID
+		}
+	}
+	if !hasSelf {
+		return InsertCompanyHierarchy(tx, companyID, company.ParentID)
+	}
+	if currentParentID == parentID {
+		return nil
+	}
+
+	if parentID != "" {
+		var inSubtree int64
+		if err := tx.Model(&domain.CompanyHierarchyModel{}).Where("ancestor_id = ? AND descendant_id = ?", companyID, parentID).Count(&inSubtree).Error; err != nil {
+			return fmt.Errorf("failed to check company hierarchy: %w", err)
+		}
+		if inSubtree > 0 {
+			return ErrCompanyHierarchyCycle
+		}
+	}
+
+	// Putus path dari ancestor lama ke seluruh subtree, lalu sambungkan ke ancestor parent baru
+	subtree := tx.Model(&domain.CompanyHierarchyModel{}).Select("descendant_id").Where("ancestor_id = ?", companyID)
+	err := tx.Where("descendant_id IN (?) AND ancestor_id NOT IN (?)", subtree, subtree).Delete(&domain.CompanyHierarchyModel{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to detach company hierarchy: %w", err)
+	}
+	if parentID == "" {
+		return nil
+	}
+	err = tx.Exec(`INSERT INTO company_hierarchy (ancestor_id, descendant_id, depth)
+		SELECT p.ancestor_id, s.descendant_id, p.depth + s.depth + 1
+		FROM company_hierarchy p, company_hierarchy s
+		WHERE p.descendant_id = ? AND s.ancestor_id = ?`, parentID, companyID).Error
+	if err != nil {
+		return fmt.Errorf("failed to attach company hierarchy: %w", err)
+	}
+	return nil
+}
+
+// DeleteCompanyHierarchy menghapus seluruh path yang melibatkan company yang di-hard delete
+func DeleteCompanyHierarchy(tx *gorm.DB, companyIDs []string) error {
+	if len(companyIDs) == 0 {
+		return nil
+	}
+	err := tx.Where("ancestor_id IN ? OR descendant_id IN ?", companyIDs, companyIDs).Delete(&domain.CompanyHierarchyModel{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete company hierarchy: %w", err)
+	}
+	return nil
+}
+
+// CompanyLevelFix perubahan level company hasil rebuild hierarki
+type CompanyLevelFix struct {
+	CompanyID string `json:"company_id"`
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	OldLevel  int    `json:"old_level"`
+	NewLevel  int    `json:"new_level"`
+}
+
+// CompanyHierarchyRebuildResult ringkasan rebuild closure table hierarki company
+type CompanyHierarchyRebuildResult struct {
+	DryRun      bool              `json:"dry_run"`
+	Companies   int               `json:"companies"`
+	Paths       int               `json:"paths"`
+	LevelFixes  []CompanyLevelFix `json:"level_fixes"`
+	Orphans     []string          `json:"orphans"` // Kode company dengan parent_id yang tidak ditemukan (diperlakukan sebagai root)
+	CycleCodes  []string          `json:"cycle_codes"`
+	PathsBefore int64             `json:"paths_before"`
+}
+
+// RebuildCompanyHierarchy membangun ulang closure table dari parent_id seluruh company (aktif maupun nonaktif)
+// dan memperbaiki kolom level (jumlah ancestor). Dry run hanya menghitung tanpa menyimpan.
+func RebuildCompanyHierarchy(db *gorm.DB, dryRun bool) (*CompanyHierarchyRebuildResult, error) {
+	var companies []domain.CompanyModel
+	if err := db.Select("id", "code", "name", "parent_id", "level").Order("code").Find(&companies).Error; err != nil {
+		return nil, fmt.Errorf("failed to load companies: %w", err)
+	}
+	byID := make(map[string]*domain.CompanyModel, len(companies))
+	for i := range companies {
+		byID[companies[i].ID] = &companies[i]
+	}
+
+	result := &CompanyHierarchyRebuildResult{
+		DryRun:     dryRun,
+		Companies:  len(companies),
+		LevelFixes: []CompanyLevelFix{},
+		Orphans:    []string{},
+		CycleCodes: []string{},
+	}
+	if err := db.Model(&domain.CompanyHierarchyModel{}).Count(&result.PathsBefore).Error; err != nil {
+		return nil, fmt.Errorf("failed to count company hierarchy: %w", err)
+	}
+
+	inCycle := make(map[string]bool)
+	var paths []domain.CompanyHierarchyModel
+	for i := range companies {
+		company := &companies[i]
+		paths = append(paths, domain.CompanyHierarchyModel{AncestorID: company.ID, DescendantID: company.ID})
+
+		// Naik lewat parent_id sampai root; rantai berhenti di parent yang hilang atau saat siklus terdeteksi
+		position := map[string]int{company.ID: 0}
+		chain := []string{company.ID}
+		current := company
+		for current.ParentID != nil && *current.ParentID != "" {
+			parent, ok := byID[*current.ParentID]
+			if !ok {
+				if current == company {
+					result.Orphans = append(result.Orphans, company.Code)
+				}
+				break
+			}
+			if start, seen := position[parent.ID]; seen {
+				for _, id := range chain[start:] {
+					inCycle[id] = true
+				}
+				break
+			}
+			position[parent.ID] = len(chain)
+			chain = append(chain, parent.ID)
+			paths = append(paths, domain.CompanyHierarchyModel{AncestorID: parent.ID, DescendantID: company.ID, Depth: len(chain) - 1})
+			current = parent
+		}
+
+		if level := len(chain) - 1; level != company.Level {
+			result.LevelFixes = append(result.LevelFixes, CompanyLevelFix{
+				CompanyID: company.ID,
+				Code:      company.Code,
+				Name:      company.Name,
+				OldLevel:  company.Level,
+				NewLevel:  level,
+			})
+		}
+	}
+	for i := range companies {
+		if inCycle[companies[i].ID] {
+			result.CycleCodes = append(result.CycleCodes, companies[i].Code)
+		}
+	}
+	result.Paths = len(paths)
+
+	if dryRun {
+		return result, nil
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&domain.CompanyHierarchyModel{}).Error; err != nil {
+			return fmt.Errorf("failed to clear company hierarchy: %w", err)
+		}
+		if len(paths) > 0 {
+			if err := tx.CreateInBatches(paths, 500).Error; err != nil {
+				return fmt.Errorf("failed to create company hierarchy: %w", err)
+			}
+		}
+		for _, fix := range result.LevelFixes {
+			if err := tx.Model(&domain.CompanyModel{}).Where("id = ?", fix.CompanyID).Update("level", fix.NewLevel).Error; err != nil {
+				return fmt.Errorf("failed to update company level: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
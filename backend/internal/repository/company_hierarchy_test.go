@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"github.com/repoareta/pedeve-dms-app/backend/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func createHierarchyCompany(t *testing.T, db *gorm.DB, code string, parent *domain.CompanyModel) *domain.CompanyModel {
+	company := &domain.CompanyModel{ID: uuid.GenerateUUID(), Code: code, Name: code, IsActive: true}
+	if parent != nil {
+		company.ParentID = &parent.ID
+		company.Level = parent.Level + 1
+	}
+	require.NoError(t, NewCompanyRepositoryWithDB(db).Create(company))
+	return company
+}
+
+func codes(companies []domain.CompanyModel) []string {
+	result := []string{}
+	for _, c := range companies {
+		result = append(result, c.Code)
+	}
+	return result
+}
+
+func TestCompanyHierarchy(t *testing.T) {
+	db := helpers.SetupTestDB(t)
+	defer helpers.CleanupTestDB(t, db)
+	repo := NewCompanyRepositoryWithDB(db)
+
+	root := createHierarchyCompany(t, db, "ROOT", nil)
+	a := createHierarchyCompany(t, db, "A", root)
+	b := createHierarchyCompany(t, db, "B", root)
+	a1 := createHierarchyCompany(t, db, "A1", a)
+	a11 := createHierarchyCompany(t, db, "A11", a1)
+
+	// Deeper than the old CTE limit of 10
+	parent := a11
+	for i := 0; i < 12; i++ {
+		parent = createHierarchyCompany(t, db, "D"+string(rune('a'+i)), parent)
+	}
+	deepest := parent
+
+	descendants, err := repo.GetDescendants(root.ID)
+	require.NoError(t, err)
+	assert.Len(t, descendants, 16)
+	ok, err := repo.IsDescendantOf(deepest.ID, root.ID)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ancestors, err := repo.GetAncestors(a11.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A1", "A", "ROOT"}, codes(ancestors))
+
+	// Move A1 subtree under B through Save (UpdateCompanyFull path)
+	a1.ParentID = &b.ID
+	require.NoError(t, repo.Update(a1))
+	ok, _ = repo.IsDescendantOf(deepest.ID, b.ID)
+	assert.True(t, ok)
+	ok, _ = repo.IsDescendantOf(deepest.ID, a.ID)
+	assert.False(t, ok)
+	ancestors, _ = repo.GetAncestors(a11.ID)
+	assert.Equal(t, []string{"A1", "B", "ROOT"}, codes(ancestors))
+
+	// Cycle rejected, parent_id di companies ikut di-rollback
+	a1.ParentID = &deepest.ID
+	assert.ErrorIs(t, repo.Update(a1), ErrCompanyHierarchyCycle)
+	stored, err := repo.GetByID(a1.ID)
+	require.NoError(t, err)
+	assert.Equal(t, b.ID, *stored.ParentID)
+	a1.ParentID = &b.ID
+
+	// Inactive filtering hides subtree below inactive company
+	require.NoError(t, repo.Delete(a1.ID))
+	active, _ := repo.GetDescendants(b.ID)
+	assert.Empty(t, active)
+	all, _ := repo.GetDescendantsWithOptions(b.ID, true)
+	assert.Len(t, all, 14)
+	ok, _ = repo.IsDescendantOf(a11.ID, b.ID)
+	assert.False(t, ok)
+	ok, _ = repo.IsDescendantOfWithOptions(a11.ID, b.ID, true)
+	assert.True(t, ok)
+
+	// Rebuild from scratch gives the same closure and fixes levels
+	before := int64(0)
+	db.Model(&domain.CompanyHierarchyModel{}).Count(&before)
+	require.NoError(t, db.Model(&domain.CompanyModel{}).Where("id = ?", a11.ID).Update("level", 9).Error)
+	require.NoError(t, db.Where("1 = 1").Delete(&domain.CompanyHierarchyModel{}).Error)
+	result, err := repo.RebuildHierarchy(false)
+	require.NoError(t, err)
+	assert.Equal(t, int(before), result.Paths)
+	require.Len(t, result.LevelFixes, 1)
+	assert.Equal(t, 3, result.LevelFixes[0].NewLevel)
+	ok, _ = repo.IsDescendantOfWithOptions(deepest.ID, root.ID, true)
+	assert.True(t, ok)
+
+	// Hard delete removes paths
+	require.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(deepest).Error; err != nil {
+			return err
+		}
+		return DeleteCompanyHierarchy(tx, []string{deepest.ID})
+	}))
+	var remaining int64
+	db.Model(&domain.CompanyHierarchyModel{}).Where("descendant_id = ?", deepest.ID).Count(&remaining)
+	assert.Zero(t, remaining)
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"gorm.io/gorm"
+)
+
+// CompanyPositionRequirementRepository interface untuk jabatan pengurus wajib per company
+type CompanyPositionRequirementRepository interface {
+	GetByCompanyID(companyID string) ([]domain.CompanyPositionRequirementModel, error)
+	ReplaceForCompany(companyID string, requirements []domain.CompanyPositionRequirementModel) error
+}
+
+type companyPositionRequirementRepository struct {
+	db *gorm.DB
+}
+
+// NewCompanyPositionRequirementRepository creates a new company position requirement repository
+func NewCompanyPositionRequirementRepository() CompanyPositionRequirementRepository {
+	return NewCompanyPositionRequirementRepositoryWithDB(database.GetDB())
+}
+
+// NewCompanyPositionRequirementRepositoryWithDB creates a new company position requirement repository with injected DB (for testing)
+func NewCompanyPositionRequirementRepositoryWithDB(db *gorm.DB) CompanyPositionRequirementRepository {
+	return &companyPositionRequirementRepository{db: db}
+}
+
+func (r *companyPositionRequirementRepository) GetByCompanyID(companyID string) ([]domain.CompanyPositionRequirementModel, error) {
+	var requirements []domain.CompanyPositionRequirementModel
+	err := r.db.Preload("Position").Where("company_id = ?", companyID).Find(&requirements).Error
+	return requirements, err
+}
+
+// ReplaceForCompany mengganti seluruh daftar jabatan wajib sebuah company
+func (r *companyPositionRequirementRepository) ReplaceForCompany(companyID string, requirements []domain.CompanyPositionRequirementModel) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("company_id = ?", companyID).Delete(&domain.CompanyPositionRequirementModel{}).Error; err != nil {
+			return err
+		}
+		for i := range requirements {
+			requirements[i].CompanyID = companyID
+			if requirements[i].ID == "" {
+				requirements[i].ID = uuid.GenerateUUID()
+			}
+			if err := tx.Omit("Position").Create(&requirements[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
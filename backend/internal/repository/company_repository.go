@@ -14,13 +14,16 @@ type CompanyRepository interface {
 	GetAll(includeInactive bool) ([]domain.CompanyModel, error)
 	GetByParentID(parentID string) ([]domain.CompanyModel, error)
 	GetChildren(companyID string) ([]domain.CompanyModel, error)
-	GetDescendants(companyID string) ([]domain.CompanyModel, error) // Get all descendants (children, grandchildren, etc)
-	GetAncestors(companyID string) ([]domain.CompanyModel, error)   // Get all ancestors (parent, grandparent, etc)
+	GetDescendants(companyID string) ([]domain.CompanyModel, error)                                  // Get all active descendants (children, grandchildren, etc)
+	GetDescendantsWithOptions(companyID string, includeInactive bool) ([]domain.CompanyModel, error) // Descendants termasuk nonaktif jika includeInactive
+	GetAncestors(companyID string) ([]domain.CompanyModel, error)                                    // Get all ancestors (parent, grandparent, etc)
 	Update(company *domain.CompanyModel) error
 	Delete(id string) error
-	IsDescendantOf(childID, parentID string) (bool, error) // Check if childID is descendant of parentID
-	GetRootHolding() (*domain.CompanyModel, error)         // Get the root holding company (parent_id = NULL)
-	CountRootHoldings() (int64, error)                     // Count companies with parent_id = NULL
+	IsDescendantOf(childID, parentID string) (bool, error)                                  // Check if childID is descendant of parentID
+	GetRootHolding() (*domain.CompanyModel, error)                                          // Get the root holding company (parent_id = NULL)
+	CountRootHoldings() (int64, error)                                                      // Count companies with parent_id = NULL
+	IsDescendantOfWithOptions(childID, parentID string, includeInactive bool) (bool, error) // IsDescendantOf, opsional termasuk company nonaktif
+	RebuildHierarchy(dryRun bool) (*CompanyHierarchyRebuildResult, error)                   // Rebuild closure table company_hierarchy dan level
 }
 
 type companyRepository struct {
@@ -39,8 +42,14 @@ func NewCompanyRepository() CompanyRepository {
 	return NewCompanyRepositoryWithDB(database.GetDB())
 }
 
+// Create menyimpan company dan path closure table-nya dalam satu transaksi
 func (r *companyRepository) Create(company *domain.CompanyModel) error {
-	return r.db.Create(company).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(company).Error; err != nil {
+			return err
+		}
+		return InsertCompanyHierarchy(tx, company.ID, company.ParentID)
+	})
 }
 
 func (r *companyRepository) GetByID(id string) (*domain.CompanyModel, error) {
@@ -81,71 +90,56 @@ func (r *companyRepository) GetChildren(companyID string) ([]domain.CompanyModel
 	return r.GetByParentID(companyID)
 }
 
-// GetDescendants menggunakan recursive CTE untuk mendapatkan semua descendants
-// Optimized dengan limit untuk mencegah temp_file_limit error pada hierarki yang sangat dalam
+// GetDescendants mengembalikan semua descendants aktif (children, grandchildren, etc)
 func (r *companyRepository) GetDescendants(companyID string) ([]domain.CompanyModel, error) {
+	return r.GetDescendantsWithOptions(companyID, false)
+}
+
+// inactiveBetweenCondition true jika ada company nonaktif di antara ancestor (placeholder) dan companies.id.
+// Sama dengan perilaku lama: subtree di bawah company nonaktif ikut tersembunyi
+const inactiveBetweenCondition = `EXISTS (
+		SELECT 1 FROM company_hierarchy up
+		JOIN company_hierarchy down ON down.descendant_id = up.ancestor_id
+		JOIN companies between_company ON between_company.id = up.ancestor_id
+		WHERE up.descendant_id = companies.id AND up.depth > 0
+		  AND down.ancestor_id = ? AND down.depth > 0
+		  AND between_company.is_active = ?
+	)`
+
+// GetDescendantsWithOptions membaca descendants dari closure table company_hierarchy (tanpa batas kedalaman).
+// includeInactive=false hanya mengembalikan company aktif yang tidak berada di bawah company nonaktif
+func (r *companyRepository) GetDescendantsWithOptions(companyID string, includeInactive bool) ([]domain.CompanyModel, error) {
 	var descendants []domain.CompanyModel
-
-	// PostgreSQL recursive CTE untuk mendapatkan semua descendants (children, grandchildren, etc)
-	// Optimized dengan depth limit dan result limit untuk mencegah temp_file_limit error
-	// CRITICAL: Gunakan DISTINCT untuk prevent duplicate jika ada bug di data hierarchy
-	query := `
-		WITH RECURSIVE descendants AS (
-			-- Base case: direct children
-			SELECT DISTINCT id, name, short_name, code, description, npwp, nib, status, logo, phone, fax, email, website,
-			       address, operational_address, authorized_capital, paid_up_capital, currency, parent_id, main_parent_company_id, level, is_active, created_at, updated_at,
-			       1 as depth
-			FROM companies 
-			WHERE parent_id = ? AND is_active = true
-			UNION
-			-- Recursive case: children of children (max depth 10 levels untuk prevent infinite recursion dan reduce temp file usage)
-			-- CRITICAL: Gunakan UNION (bukan UNION ALL) untuk prevent duplicate rows
-			SELECT DISTINCT c.id, c.name, c.short_name, c.code, c.description, c.npwp, c.nib, c.status, c.logo, c.phone, c.fax, c.email, c.website,
-			       c.address, c.operational_address, c.authorized_capital, c.paid_up_capital, c.currency, c.parent_id, c.main_parent_company_id, c.level, c.is_active, c.created_at, c.updated_at,
-			       d.depth + 1 as depth
-			FROM companies c
-			INNER JOIN descendants d ON c.parent_id = d.id
-			WHERE c.is_active = true AND d.depth < 10
-		)
-		SELECT DISTINCT id, name, short_name, code, description, npwp, nib, status, logo, phone, fax, email, website, 
-		       address, operational_address, authorized_capital, paid_up_capital, currency, parent_id, main_parent_company_id, level, is_active, created_at, updated_at
-		FROM descendants 
-		ORDER BY level, name
-		LIMIT 10000
-	`
-
-	err := r.db.Raw(query, companyID).Scan(&descendants).Error
-	if err != nil {
-		return nil, err
+	query := r.db.Select("companies.*").
+		Joins("JOIN company_hierarchy ON company_hierarchy.descendant_id = companies.id").
+		Where("company_hierarchy.ancestor_id = ? AND company_hierarchy.depth > 0", companyID)
+	if !includeInactive {
+		query = query.Where("companies.is_active = ?", true).Where("NOT "+inactiveBetweenCondition, companyID, false)
 	}
-
-	// Pastikan kita return semua descendants termasuk direct children
-	return descendants, nil
+	err := query.Order("companies.level, companies.name").Find(&descendants).Error
+	return descendants, err
 }
 
-// GetAncestors menggunakan recursive CTE untuk mendapatkan semua ancestors
+// GetAncestors mengembalikan semua ancestors (termasuk nonaktif), dimulai dari parent langsung
 func (r *companyRepository) GetAncestors(companyID string) ([]domain.CompanyModel, error) {
 	var ancestors []domain.CompanyModel
-
-	// PostgreSQL recursive CTE
-	query := `
-		WITH RECURSIVE ancestors AS (
-			-- Base case: company itself
-			SELECT * FROM companies WHERE id = ?
-			UNION ALL
-			-- Recursive case: parent of parent
-			SELECT c.* FROM companies c
-			INNER JOIN ancestors a ON c.id = a.parent_id
-		)
-		SELECT * FROM ancestors WHERE id != ?
-	`
-
-	err := r.db.Raw(query, companyID, companyID).Scan(&ancestors).Error
+	err := r.db.Select("companies.*").
+		Joins("JOIN company_hierarchy ON company_hierarchy.ancestor_id = companies.id").
+		Where("company_hierarchy.descendant_id = ? AND company_hierarchy.depth > 0", companyID).
+		Order("company_hierarchy.depth").
+		Find(&ancestors).Error
 	return ancestors, err
 }
 
+// Update menyimpan company; jika parent_id berubah, subtree di closure table ikut dipindahkan
+// di transaksi yang sama (ErrCompanyHierarchyCycle membatalkan perubahan)
 func (r *companyRepository) Update(company *domain.CompanyModel) error {
-	return r.db.Save(company).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(company).Error; err != nil {
+			return err
+		}
+		return SyncCompanyHierarchy(tx, company.ID)
+	})
 }
 
 func (r *companyRepository) Delete(id string) error {
@@ -153,20 +147,29 @@ func (r *companyRepository) Delete(id string) error {
 	return r.db.Model(&domain.CompanyModel{}).Where("id = ?", id).Update("is_active", false).Error
 }
 
-// IsDescendantOf checks if childID is a descendant of parentID
+// IsDescendantOf checks if childID is an active descendant of parentID
 func (r *companyRepository) IsDescendantOf(childID, parentID string) (bool, error) {
-	descendants, err := r.GetDescendants(parentID)
-	if err != nil {
-		return false, err
-	}
+	return r.IsDescendantOfWithOptions(childID, parentID, false)
+}
 
-	for _, desc := range descendants {
-		if desc.ID == childID {
-			return true, nil
-		}
+// IsDescendantOfWithOptions cek ancestry lewat satu lookup primary key di closure table
+func (r *companyRepository) IsDescendantOfWithOptions(childID, parentID string, includeInactive bool) (bool, error) {
+	var count int64
+	query := r.db.Model(&domain.CompanyModel{}).
+		Joins("JOIN company_hierarchy ON company_hierarchy.descendant_id = companies.id").
+		Where("company_hierarchy.ancestor_id = ? AND company_hierarchy.descendant_id = ? AND company_hierarchy.depth > 0", parentID, childID)
+	if !includeInactive {
+		query = query.Where("companies.is_active = ?", true).Where("NOT "+inactiveBetweenCondition, parentID, false)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
 	}
+	return count > 0, nil
+}
 
-	return false, nil
+// RebuildHierarchy membangun ulang closure table dan memperbaiki level company
+func (r *companyRepository) RebuildHierarchy(dryRun bool) (*CompanyHierarchyRebuildResult, error) {
+	return RebuildCompanyHierarchy(r.db, dryRun)
 }
 
 // GetRootHolding returns the root holding company (parent_id = NULL)
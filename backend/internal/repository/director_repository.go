@@ -9,6 +9,7 @@ import (
 type DirectorRepository interface {
 	Create(director *domain.DirectorModel) error
 	Update(director *domain.DirectorModel) error
+	GetByID(id string) (*domain.DirectorModel, error)
 	GetByCompanyID(companyID string) ([]domain.DirectorModel, error)
 	DeleteByCompanyID(companyID string) error
 	Delete(id string) error
@@ -38,6 +39,15 @@ func (r *directorRepository) Update(director *domain.DirectorModel) error {
 	return r.db.Save(director).Error
 }
 
+func (r *directorRepository) GetByID(id string) (*domain.DirectorModel, error) {
+	var director domain.DirectorModel
+	err := r.db.Where("id = ?", id).First(&director).Error
+	if err != nil {
+		return nil, err
+	}
+	return &director, nil
+}
+
 func (r *directorRepository) GetByCompanyID(companyID string) ([]domain.DirectorModel, error) {
 	var directors []domain.DirectorModel
 	err := r.db.Where("company_id = ?", companyID).Find(&directors).Error
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"gorm.io/gorm"
+)
+
+// DirectorTermRepository interface untuk riwayat masa jabatan pengurus
+type DirectorTermRepository interface {
+	Create(term *domain.DirectorTermModel) error
+	Update(term *domain.DirectorTermModel) error
+	GetByID(id string) (*domain.DirectorTermModel, error)
+	GetByDirectorID(directorID string) ([]domain.DirectorTermModel, error)
+	GetActiveByDirectorID(directorID string) (*domain.DirectorTermModel, error)
+	GetByCompanyID(companyID string) ([]domain.DirectorTermModel, error)
+	Renew(current *domain.DirectorTermModel, next *domain.DirectorTermModel, director *domain.DirectorModel) error
+	CloseActiveByDirectorID(directorID, reason string, closedAt time.Time) error
+}
+
+type directorTermRepository struct {
+	db *gorm.DB
+}
+
+// NewDirectorTermRepository creates a new director term repository
+func NewDirectorTermRepository() DirectorTermRepository {
+	return NewDirectorTermRepositoryWithDB(database.GetDB())
+}
+
+// NewDirectorTermRepositoryWithDB creates a new director term repository with injected DB (for testing)
+func NewDirectorTermRepositoryWithDB(db *gorm.DB) DirectorTermRepository {
+	return &directorTermRepository{db: db}
+}
+
+func (r *directorTermRepository) Create(term *domain.DirectorTermModel) error {
+	if term.ID == "" {
+		term.ID = uuid.GenerateUUID()
+	}
+	return r.db.Create(term).Error
+}
+
+func (r *directorTermRepository) Update(term *domain.DirectorTermModel) error {
+	return r.db.Omit("ResolutionDocument").Save(term).Error
+}
+
+func (r *directorTermRepository) GetByID(id string) (*domain.DirectorTermModel, error) {
+	var term domain.DirectorTermModel
+	err := r.db.Preload("ResolutionDocument").Where("id = ?", id).First(&term).Error
+	if err != nil {
+		return nil, err
+	}
+	return &term, nil
+}
+
+func (r *directorTermRepository) GetByDirectorID(directorID string) ([]domain.DirectorTermModel, error) {
+	var terms []domain.DirectorTermModel
+	err := r.db.Preload("ResolutionDocument").Where("director_id = ?", directorID).
+		Order("term_number ASC").Find(&terms).Error
+	return terms, err
+}
+
+func (r *directorTermRepository) GetActiveByDirectorID(directorID string) (*domain.DirectorTermModel, error) {
+	var term domain.DirectorTermModel
+	err := r.db.Where("director_id = ? AND status = ?", directorID, domain.DirectorTermStatusActive).
+		Order("term_number DESC").First(&term).Error
+	if err != nil {
+		return nil, err
+	}
+	return &term, nil
+}
+
+func (r *directorTermRepository) GetByCompanyID(companyID string) ([]domain.DirectorTermModel, error) {
+	var terms []domain.DirectorTermModel
+	err := r.db.Where("company_id = ?", companyID).Order("start_date ASC").Find(&terms).Error
+	return terms, err
+}
+
+// Renew menutup masa jabatan aktif, membuat masa jabatan baru, dan menyinkronkan tanggal di DirectorModel dalam satu transaksi
+func (r *directorTermRepository) Renew(current *domain.DirectorTermModel, next *domain.DirectorTermModel, director *domain.DirectorModel) error {
+	if next.ID == "" {
+		next.ID = uuid.GenerateUUID()
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if current != nil {
+			if err := tx.Omit("ResolutionDocument").Save(current).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+		return tx.Save(director).Error
+	})
+}
+
+func (r *directorTermRepository) CloseActiveByDirectorID(directorID, reason string, closedAt time.Time) error {
+	return r.db.Model(&domain.DirectorTermModel{}).
+		Where("director_id = ? AND status = ?", directorID, domain.DirectorTermStatusActive).
+		Updates(map[string]interface{}{
+			"status":        domain.DirectorTermStatusClosed,
+			"closed_reason": reason,
+			"closed_at":     closedAt,
+		}).Error
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// DocumentDispositionFilter untuk filter list/laporan disposisi dokumen
+type DocumentDispositionFilter struct {
+	Status     string
+	CompanyID  string
+	DocumentID string
+	Since      *time.Time // Berdasarkan archived_at
+	Until      *time.Time
+	Limit      int
+	Offset     int
+}
+
+// DocumentDispositionRepository interface untuk catatan disposisi dokumen
+type DocumentDispositionRepository interface {
+	// Archive membuat catatan disposisi dan mengubah status dokumen menjadi archived dalam satu transaksi
+	Archive(disposition *domain.DocumentDispositionModel) error
+	Update(disposition *domain.DocumentDispositionModel) error
+	GetByID(id string) (*domain.DocumentDispositionModel, error)
+	List(filter DocumentDispositionFilter) ([]domain.DocumentDispositionModel, int64, error)
+	// ListOpenDocumentIDs mengembalikan dokumen yang masih menunggu review atau sedang dipertahankan (retain_until > now)
+	ListOpenDocumentIDs(documentIDs []string, now time.Time) (map[string]bool, error)
+}
+
+type documentDispositionRepository struct {
+	db *gorm.DB
+}
+
+// NewDocumentDispositionRepository creates a new document disposition repository
+func NewDocumentDispositionRepository() DocumentDispositionRepository {
+	return NewDocumentDispositionRepositoryWithDB(database.GetDB())
+}
+
+// NewDocumentDispositionRepositoryWithDB creates a new document disposition repository with injected DB (for testing)
+func NewDocumentDispositionRepositoryWithDB(db *gorm.DB) DocumentDispositionRepository {
+	return &documentDispositionRepository{db: db}
+}
+
+func (r *documentDispositionRepository) Archive(disposition *domain.DocumentDispositionModel) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(disposition).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.DocumentModel{}).
+			Where("id = ?", disposition.DocumentID).
+			Update("status", domain.DocumentStatusArchived).Error
+	})
+}
+
+func (r *documentDispositionRepository) Update(disposition *domain.DocumentDispositionModel) error {
+	return r.db.Save(disposition).Error
+}
+
+func (r *documentDispositionRepository) GetByID(id string) (*domain.DocumentDispositionModel, error) {
+	var disposition domain.DocumentDispositionModel
+	if err := r.db.Where("id = ?", id).First(&disposition).Error; err != nil {
+		return nil, err
+	}
+	return &disposition, nil
+}
+
+func (r *documentDispositionRepository) List(filter DocumentDispositionFilter) ([]domain.DocumentDispositionModel, int64, error) {
+	query := r.db.Model(&domain.DocumentDispositionModel{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.CompanyID != "" {
+		query = query.Where("company_id = ?", filter.CompanyID)
+	}
+	if filter.DocumentID != "" {
+		query = query.Where("document_id = ?", filter.DocumentID)
+	}
+	if filter.Since != nil {
+		query = query.Where("archived_at >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		query = query.Where("archived_at <= ?", *filter.Until)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit).Offset(filter.Offset)
+	}
+
+	var dispositions []domain.DocumentDispositionModel
+	err := query.Order("archived_at DESC").Find(&dispositions).Error
+	return dispositions, total, err
+}
+
+func (r *documentDispositionRepository) ListOpenDocumentIDs(documentIDs []string, now time.Time) (map[string]bool, error) {
+	open := make(map[string]bool)
+	if len(documentIDs) == 0 {
+		return open, nil
+	}
+	var ids []string
+	err := r.db.Model(&domain.DocumentDispositionModel{}).
+		Where("document_id IN ?", documentIDs).
+		Where("status = ? OR (status = ? AND retain_until > ?)", domain.DispositionStatusPendingReview, domain.DispositionStatusRetained, now).
+		Pluck("document_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		open[id] = true
+	}
+	return open, nil
+}
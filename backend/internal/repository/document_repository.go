@@ -1,8 +1,10 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
@@ -15,18 +17,33 @@ type DocumentRepository interface {
 	GetFolderByID(id string) (*domain.DocumentFolderModel, error)
 	GetChildFolders(parentID string) ([]domain.DocumentFolderModel, error)
 	UpdateFolderName(id, name string) error
-	DeleteFolder(id string) error
 
 	ListDocuments(folderID *string) ([]domain.DocumentModel, error)
 	ListDocumentsPaginated(q ListDocumentsQuery) ([]domain.DocumentModel, int64, error)
 	GetDocumentByID(id string) (*domain.DocumentModel, error)
 	CreateDocument(doc *domain.DocumentModel) error
 	UpdateDocument(doc *domain.DocumentModel) error
+	// DeleteDocument menghapus dokumen permanen (tanpa recycle bin), misalnya untuk disposisi retensi
 	DeleteDocument(id string) error
-	DeleteDocumentsByFolder(folderID string) error
+
+	// Recycle bin: soft delete, restore, dan purge per item (TrashRootID)
+	TrashDocument(id, deletedBy string) error
+	TrashFolderTree(folderID, deletedBy string) error
+	ListTrash(companyID *string) ([]domain.DocumentFolderModel, []domain.DocumentModel, error)
+	GetTrashedFolder(id string) (*domain.DocumentFolderModel, error)
+	GetTrashedDocument(id string) (*domain.DocumentModel, error)
+	GetTrashStats(rootIDs []string) (map[string]TrashStat, error)
+	ListExpiredTrashRoots(deletedBefore time.Time) ([]string, error)
+	RestoreTrash(rootID string) error
+	// PurgeTrash menghapus permanen item beserta seluruh isi folder yang ada di recycle bin
+	// dan mengembalikan dokumen yang terhapus (untuk menghapus file di storage)
+	PurgeTrash(rootID string) ([]domain.DocumentModel, error)
 
 	GetFolderStats(companyID *string) ([]domain.DocumentFolderStat, error)
 	GetTotalSize(companyID *string) (int64, error)
+
+	// WithContext mengembalikan repository yang query-nya membawa ctx (span tracing, cancel request)
+	WithContext(ctx context.Context) DocumentRepository
 }
 
 type ListDocumentsQuery struct {
@@ -42,6 +59,13 @@ type ListDocumentsQuery struct {
 	Type       string
 }
 
+// TrashStat adalah ringkasan isi satu item recycle bin
+type TrashStat struct {
+	FolderCount   int64
+	DocumentCount int64
+	TotalSize     int64
+}
+
 type documentRepository struct {
 	db *gorm.DB
 }
@@ -54,6 +78,13 @@ func NewDocumentRepositoryWithDB(db *gorm.DB) DocumentRepository {
 	return &documentRepository{db: db}
 }
 
+func (r *documentRepository) WithContext(ctx context.Context) DocumentRepository {
+	if ctx == nil {
+		return r
+	}
+	return &documentRepository{db: r.db.WithContext(ctx)}
+}
+
 func (r *documentRepository) ListFolders(companyID *string) ([]domain.DocumentFolderModel, error) {
 	var folders []domain.DocumentFolderModel
 	tx := r.db.Order("created_at DESC")
@@ -88,10 +119,6 @@ func (r *documentRepository) GetChildFolders(parentID string) ([]domain.Document
 	return folders, err
 }
 
-func (r *documentRepository) DeleteFolder(id string) error {
-	return r.db.Delete(&domain.DocumentFolderModel{}, "id = ?", id).Error
-}
-
 func (r *documentRepository) ListDocuments(folderID *string) ([]domain.DocumentModel, error) {
 	var docs []domain.DocumentModel
 	tx := r.db.Model(&domain.DocumentModel{}).Order("created_at DESC")
@@ -197,27 +224,264 @@ func (r *documentRepository) DeleteDocument(id string) error {
 	_ = r.db.Where("resource_type = ? AND resource_id = ?", "document", id).
 		Delete(&domain.NotificationModel{}).Error
 
-	// Sekarang hapus dokumen
-	return r.db.Delete(&domain.DocumentModel{}, "id = ?", id).Error
+	// Sekarang hapus dokumen (Unscoped: hapus permanen, bukan soft delete)
+	return r.db.Unscoped().Delete(&domain.DocumentModel{}, "id = ?", id).Error
+}
+
+// trashFields adalah kolom yang diisi saat item dipindahkan ke recycle bin
+func trashFields(rootID, deletedBy string, now time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"deleted_at":    now,
+		"deleted_by":    deletedBy,
+		"trash_root_id": rootID,
+	}
+}
+
+func (r *documentRepository) TrashDocument(id, deletedBy string) error {
+	result := r.db.Model(&domain.DocumentModel{}).
+		Where("id = ?", id).
+		Updates(trashFields(id, deletedBy, time.Now()))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *documentRepository) TrashFolderTree(folderID, deletedBy string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Kumpulkan folder beserta seluruh sub folder yang masih aktif (BFS per level)
+		folderIDs := []string{folderID}
+		current := []string{folderID}
+		for len(current) > 0 {
+			var children []string
+			if err := tx.Model(&domain.DocumentFolderModel{}).Where("parent_id IN ?", current).Pluck("id", &children).Error; err != nil {
+				return fmt.Errorf("failed to get child folders: %w", err)
+			}
+			folderIDs = append(folderIDs, children...)
+			current = children
+		}
+
+		// Dokumen dan folder yang sudah lebih dulu ada di recycle bin tetap di item (TrashRootID) asalnya
+		fields := trashFields(folderID, deletedBy, time.Now())
+		if err := tx.Model(&domain.DocumentModel{}).Where("folder_id IN ?", folderIDs).Updates(fields).Error; err != nil {
+			return fmt.Errorf("failed to trash documents: %w", err)
+		}
+		result := tx.Model(&domain.DocumentFolderModel{}).Where("id IN ?", folderIDs).Updates(fields)
+		if result.Error != nil {
+			return fmt.Errorf("failed to trash folders: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
 }
 
-func (r *documentRepository) DeleteDocumentsByFolder(folderID string) error {
-	// Ambil semua document ID di folder ini dulu
-	var documents []domain.DocumentModel
-	if err := r.db.Where("folder_id = ?", folderID).Find(&documents).Error; err != nil {
-		return fmt.Errorf("failed to get documents: %w", err)
+func (r *documentRepository) ListTrash(companyID *string) ([]domain.DocumentFolderModel, []domain.DocumentModel, error) {
+	// Hanya item yang dihapus langsung oleh user (trash_root_id = id); isi folder ikut item folder-nya
+	folderQuery := r.db.Unscoped().Where("deleted_at IS NOT NULL AND trash_root_id = id")
+	docQuery := r.db.Unscoped().Where("deleted_at IS NOT NULL AND trash_root_id = id")
+	if companyID != nil {
+		folderQuery = folderQuery.Where("company_id = ?", *companyID)
+		docQuery = docQuery.Where("folder_id IN (?) OR director_id IN (?)",
+			r.db.Unscoped().Model(&domain.DocumentFolderModel{}).Select("id").Where("company_id = ?", *companyID),
+			r.db.Model(&domain.DirectorModel{}).Select("id").Where("company_id = ?", *companyID))
 	}
 
-	// Hapus notifikasi untuk setiap dokumen dulu (untuk hindari foreign key constraint)
-	for _, doc := range documents {
-		// Hapus notifikasi dimana resource_type = 'document' dan resource_id = doc.ID
-		// Ignore error - notifications might not exist, this prevents foreign key constraint error
-		_ = r.db.Where("resource_type = ? AND resource_id = ?", "document", doc.ID).
-			Delete(&domain.NotificationModel{}).Error
+	var folders []domain.DocumentFolderModel
+	if err := folderQuery.Order("deleted_at DESC").Find(&folders).Error; err != nil {
+		return nil, nil, err
 	}
+	var docs []domain.DocumentModel
+	if err := docQuery.Order("deleted_at DESC").Find(&docs).Error; err != nil {
+		return nil, nil, err
+	}
+	return folders, docs, nil
+}
 
-	// Sekarang hapus semua dokumen di folder
-	return r.db.Delete(&domain.DocumentModel{}, "folder_id = ?", folderID).Error
+func (r *documentRepository) GetTrashedFolder(id string) (*domain.DocumentFolderModel, error) {
+	var folder domain.DocumentFolderModel
+	if err := r.db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&folder).Error; err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+func (r *documentRepository) GetTrashedDocument(id string) (*domain.DocumentModel, error) {
+	var doc domain.DocumentModel
+	if err := r.db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&doc).Error; err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func (r *documentRepository) GetTrashStats(rootIDs []string) (map[string]TrashStat, error) {
+	stats := make(map[string]TrashStat, len(rootIDs))
+	if len(rootIDs) == 0 {
+		return stats, nil
+	}
+
+	var docRows []struct {
+		TrashRootID   string
+		DocumentCount int64
+		TotalSize     int64
+	}
+	err := r.db.Unscoped().Model(&domain.DocumentModel{}).
+		Select("trash_root_id, COUNT(*) AS document_count, COALESCE(SUM(size),0) AS total_size").
+		Where("deleted_at IS NOT NULL AND trash_root_id IN ?", rootIDs).
+		Group("trash_root_id").
+		Scan(&docRows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range docRows {
+		stat := stats[row.TrashRootID]
+		stat.DocumentCount, stat.TotalSize = row.DocumentCount, row.TotalSize
+		stats[row.TrashRootID] = stat
+	}
+
+	var folderRows []struct {
+		TrashRootID string
+		FolderCount int64
+	}
+	err = r.db.Unscoped().Model(&domain.DocumentFolderModel{}).
+		Select("trash_root_id, COUNT(*) AS folder_count").
+		Where("deleted_at IS NOT NULL AND trash_root_id IN ?", rootIDs).
+		Group("trash_root_id").
+		Scan(&folderRows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range folderRows {
+		stat := stats[row.TrashRootID]
+		stat.FolderCount = row.FolderCount
+		stats[row.TrashRootID] = stat
+	}
+	return stats, nil
+}
+
+func (r *documentRepository) ListExpiredTrashRoots(deletedBefore time.Time) ([]string, error) {
+	var folderRoots, docRoots []string
+	if err := r.db.Unscoped().Model(&domain.DocumentFolderModel{}).
+		Where("deleted_at IS NOT NULL AND deleted_at <= ? AND trash_root_id = id", deletedBefore).
+		Pluck("id", &folderRoots).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Unscoped().Model(&domain.DocumentModel{}).
+		Where("deleted_at IS NOT NULL AND deleted_at <= ? AND trash_root_id = id", deletedBefore).
+		Pluck("id", &docRoots).Error; err != nil {
+		return nil, err
+	}
+	return append(folderRoots, docRoots...), nil
+}
+
+func (r *documentRepository) RestoreTrash(rootID string) error {
+	restore := map[string]interface{}{
+		"deleted_at":    nil,
+		"deleted_by":    "",
+		"trash_root_id": "",
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&domain.DocumentFolderModel{}).Where("trash_root_id = ?", rootID).Updates(restore).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&domain.DocumentModel{}).Where("trash_root_id = ?", rootID).Updates(restore).Error
+	})
+}
+
+func (r *documentRepository) PurgeTrash(rootID string) ([]domain.DocumentModel, error) {
+	var docs []domain.DocumentModel
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Folder item beserta seluruh sub folder di recycle bin, termasuk yang dihapus lebih dulu
+		// sebagai item sendiri (TrashRootID berbeda) tapi masih menunjuk ke folder ini lewat parent_id
+		var folders []domain.DocumentFolderModel
+		if err := tx.Unscoped().Select("id", "parent_id").Where("deleted_at IS NOT NULL AND trash_root_id = ?", rootID).Find(&folders).Error; err != nil {
+			return err
+		}
+		folderIDs := make([]string, 0, len(folders))
+		for _, f := range folders {
+			folderIDs = append(folderIDs, f.ID)
+		}
+		current := folderIDs
+		for len(current) > 0 {
+			var children []domain.DocumentFolderModel
+			if err := tx.Unscoped().Select("id", "parent_id").
+				Where("deleted_at IS NOT NULL AND parent_id IN ? AND id NOT IN ?", current, folderIDs).
+				Find(&children).Error; err != nil {
+				return fmt.Errorf("failed to get child folders: %w", err)
+			}
+			current = nil
+			for _, f := range children {
+				folders = append(folders, f)
+				folderIDs = append(folderIDs, f.ID)
+				current = append(current, f.ID)
+			}
+		}
+
+		docQuery := tx.Unscoped().Where("deleted_at IS NOT NULL AND trash_root_id = ?", rootID)
+		if len(folderIDs) > 0 {
+			docQuery = tx.Unscoped().Where("deleted_at IS NOT NULL AND (trash_root_id = ? OR folder_id IN ?)", rootID, folderIDs)
+		}
+		if err := docQuery.Find(&docs).Error; err != nil {
+			return err
+		}
+		if len(docs) > 0 {
+			docIDs := make([]string, 0, len(docs))
+			for _, doc := range docs {
+				docIDs = append(docIDs, doc.ID)
+			}
+			// Hapus notifikasi dokumen dulu (untuk hindari foreign key constraint)
+			if err := tx.Where("resource_type = ? AND resource_id IN ?", "document", docIDs).Delete(&domain.NotificationModel{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Where("id IN ?", docIDs).Delete(&domain.DocumentModel{}).Error; err != nil {
+				return err
+			}
+		}
+		// Folder anak dihapus sebelum induknya
+		for _, id := range foldersChildFirst(folders) {
+			if err := tx.Unscoped().Delete(&domain.DocumentFolderModel{}, "id = ?", id).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// foldersChildFirst mengurutkan folder supaya setiap folder muncul sebelum folder induknya
+func foldersChildFirst(folders []domain.DocumentFolderModel) []string {
+	children := make(map[string][]string)
+	inSet := make(map[string]bool, len(folders))
+	for _, f := range folders {
+		inSet[f.ID] = true
+	}
+	var roots []string
+	for _, f := range folders {
+		if f.ParentID != nil && inSet[*f.ParentID] {
+			children[*f.ParentID] = append(children[*f.ParentID], f.ID)
+		} else {
+			roots = append(roots, f.ID)
+		}
+	}
+	ordered := make([]string, 0, len(folders))
+	var visit func(id string)
+	visit = func(id string) {
+		for _, child := range children[id] {
+			visit(child)
+		}
+		ordered = append(ordered, id)
+	}
+	for _, root := range roots {
+		visit(root)
+	}
+	return ordered
 }
 
 func (r *documentRepository) GetFolderStats(companyID *string) ([]domain.DocumentFolderStat, error) {
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// FinancialAccountMappingRepository interface untuk mapping kode akun company ke taksonomi financial report
+type FinancialAccountMappingRepository interface {
+	GetByCompanyID(companyID string) ([]domain.FinancialAccountMappingModel, error)
+	ReplaceForCompany(companyID string, mappings []domain.FinancialAccountMappingModel) error
+}
+
+type financialAccountMappingRepository struct {
+	db *gorm.DB
+}
+
+// NewFinancialAccountMappingRepository creates a new financial account mapping repository
+func NewFinancialAccountMappingRepository() FinancialAccountMappingRepository {
+	return NewFinancialAccountMappingRepositoryWithDB(database.GetDB())
+}
+
+// NewFinancialAccountMappingRepositoryWithDB creates a new financial account mapping repository with injected DB (for testing)
+func NewFinancialAccountMappingRepositoryWithDB(db *gorm.DB) FinancialAccountMappingRepository {
+	return &financialAccountMappingRepository{db: db}
+}
+
+func (r *financialAccountMappingRepository) GetByCompanyID(companyID string) ([]domain.FinancialAccountMappingModel, error) {
+	var mappings []domain.FinancialAccountMappingModel
+	err := r.db.Where("company_id = ?", companyID).Order("account_code ASC").Find(&mappings).Error
+	return mappings, err
+}
+
+// ReplaceForCompany menghapus mapping lama dan menyimpan mapping baru dalam satu transaksi
+func (r *financialAccountMappingRepository) ReplaceForCompany(companyID string, mappings []domain.FinancialAccountMappingModel) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("company_id = ?", companyID).Delete(&domain.FinancialAccountMappingModel{}).Error; err != nil {
+			return err
+		}
+		if len(mappings) == 0 {
+			return nil
+		}
+		return tx.Create(&mappings).Error
+	})
+}
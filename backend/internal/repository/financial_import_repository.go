@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// FinancialImportJobFilter untuk filter list job import
+type FinancialImportJobFilter struct {
+	CreatedBy string // kosong = semua user
+	Status    string
+	Limit     int
+	Offset    int
+}
+
+// FinancialImportRowFilter untuk filter baris staging
+type FinancialImportRowFilter struct {
+	Status     string
+	RowNumbers []int
+	Limit      int
+	Offset     int
+}
+
+// FinancialImportRepository interface untuk job import dan staging baris financial report
+type FinancialImportRepository interface {
+	CreateJob(job *domain.FinancialImportJobModel) error
+	UpdateJob(job *domain.FinancialImportJobModel) error
+	UpdateJobProgress(id string, fields map[string]interface{}) error
+	TransitionJob(id string, fromStatuses []string, fields map[string]interface{}) (bool, error)
+	GetJobByID(id string) (*domain.FinancialImportJobModel, error)
+	ListJobs(filter FinancialImportJobFilter) ([]domain.FinancialImportJobModel, int64, error)
+	UpdateJobsByStatus(status string, fields map[string]interface{}) (int64, error)
+	CreateRows(rows []domain.FinancialImportRowModel) error
+	GetRows(jobID string, filter FinancialImportRowFilter) ([]domain.FinancialImportRowModel, int64, error)
+	UpdateRow(row *domain.FinancialImportRowModel) error
+}
+
+type financialImportRepository struct {
+	db *gorm.DB
+}
+
+// NewFinancialImportRepository creates a new financial import repository
+func NewFinancialImportRepository() FinancialImportRepository {
+	return NewFinancialImportRepositoryWithDB(database.GetDB())
+}
+
+// NewFinancialImportRepositoryWithDB creates a new financial import repository with injected DB (for testing)
+func NewFinancialImportRepositoryWithDB(db *gorm.DB) FinancialImportRepository {
+	return &financialImportRepository{db: db}
+}
+
+func (r *financialImportRepository) CreateJob(job *domain.FinancialImportJobModel) error {
+	return r.db.Create(job).Error
+}
+
+func (r *financialImportRepository) UpdateJob(job *domain.FinancialImportJobModel) error {
+	return r.db.Save(job).Error
+}
+
+// UpdateJobProgress hanya update kolom progress (processed_rows, committed_rows, dll) tanpa menimpa field lain
+func (r *financialImportRepository) UpdateJobProgress(id string, fields map[string]interface{}) error {
+	return r.db.Model(&domain.FinancialImportJobModel{}).
+		Where("id = ?", id).
+		Updates(fields).Error
+}
+
+// TransitionJob update job hanya jika status saat ini termasuk fromStatuses (mencegah dua commit berjalan bersamaan)
+// Return false jika status job sudah berubah
+func (r *financialImportRepository) TransitionJob(id string, fromStatuses []string, fields map[string]interface{}) (bool, error) {
+	result := r.db.Model(&domain.FinancialImportJobModel{}).
+		Where("id = ? AND status IN ?", id, fromStatuses).
+		Updates(fields)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *financialImportRepository) GetJobByID(id string) (*domain.FinancialImportJobModel, error) {
+	var job domain.FinancialImportJobModel
+	if err := r.db.Where("id = ?", id).First(&job).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *financialImportRepository) ListJobs(filter FinancialImportJobFilter) ([]domain.FinancialImportJobModel, int64, error) {
+	query := r.db.Model(&domain.FinancialImportJobModel{})
+	if filter.CreatedBy != "" {
+		query = query.Where("created_by = ?", filter.CreatedBy)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit).Offset(filter.Offset)
+	}
+
+	var jobs []domain.FinancialImportJobModel
+	err := query.Order("created_at DESC").Find(&jobs).Error
+	return jobs, total, err
+}
+
+// UpdateJobsByStatus update semua job dengan status tertentu (dipakai untuk memulihkan job yang terhenti saat restart)
+func (r *financialImportRepository) UpdateJobsByStatus(status string, fields map[string]interface{}) (int64, error) {
+	result := r.db.Model(&domain.FinancialImportJobModel{}).
+		Where("status = ?", status).
+		Updates(fields)
+	return result.RowsAffected, result.Error
+}
+
+func (r *financialImportRepository) CreateRows(rows []domain.FinancialImportRowModel) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(rows, 100).Error
+}
+
+func (r *financialImportRepository) GetRows(jobID string, filter FinancialImportRowFilter) ([]domain.FinancialImportRowModel, int64, error) {
+	query := r.db.Model(&domain.FinancialImportRowModel{}).Where("job_id = ?", jobID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if len(filter.RowNumbers) > 0 {
+		query = query.Where("row_number IN ?", filter.RowNumbers)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit).Offset(filter.Offset)
+	}
+
+	var rows []domain.FinancialImportRowModel
+	err := query.Order("row_number ASC").Find(&rows).Error
+	return rows, total, err
+}
+
+func (r *financialImportRepository) UpdateRow(row *domain.FinancialImportRowModel) error {
+	return r.db.Save(row).Error
+}
@@ -13,6 +13,7 @@ type FinancialReportRepository interface {
 	GetByCompanyID(companyID string) ([]domain.FinancialReportModel, error)
 	GetRKAPByCompanyIDAndYear(companyID, year string) (*domain.FinancialReportModel, error)
 	GetRealisasiByCompanyIDAndPeriod(companyID, period string) (*domain.FinancialReportModel, error)
+	GetRealisasiByCompanyIDPeriodAndAudit(companyID, period string, audited bool) (*domain.FinancialReportModel, error)
 	GetRealisasiByCompanyIDAndYear(companyID, year string) ([]domain.FinancialReportModel, error)
 	GetRealisasiYTD(companyID, year, month string) (*domain.FinancialReportModel, error)
 	Update(report *domain.FinancialReportModel) error
@@ -20,6 +21,9 @@ type FinancialReportRepository interface {
 	DeleteAll() error // For reset functionality
 	CountRKAPByCompanyIDAndYear(companyID, year string) (int64, error)
 	GetRKAPYearsByCompanyID(companyID string) ([]string, error)
+	GetByCompanyIDsAndYear(companyIDs []string, year string) ([]domain.FinancialReportModel, error) // RKAP + realisasi banyak company sekaligus (benchmark)
+	GetRKAPVersion(companyID, year string, version int) (*domain.FinancialReportModel, error)
+	GetRKAPVersions(companyID, year string) ([]domain.FinancialReportModel, error) // RKAP awal + seluruh revisi, urut versi
 }
 
 type financialReportRepository struct {
@@ -51,19 +55,23 @@ func (r *financialReportRepository) GetByID(id string) (*domain.FinancialReportM
 	return &report, nil
 }
 
+// GetByCompanyID mengambil realisasi dan RKAP awal company (revisi RKAP diambil lewat GetRKAPVersions)
 func (r *financialReportRepository) GetByCompanyID(companyID string) ([]domain.FinancialReportModel, error) {
 	var reports []domain.FinancialReportModel
 	err := r.db.Preload("Company").Preload("Inputter").
-		Where("company_id = ?", companyID).
+		Where("company_id = ? AND rkap_version = ?", companyID, 0).
 		Order("year DESC, period DESC, created_at DESC").
 		Find(&reports).Error
 	return reports, err
 }
 
+// GetRKAPByCompanyIDAndYear mengambil RKAP yang berlaku: versi approved terakhir (RKAP awal jika belum ada revisi approved)
 func (r *financialReportRepository) GetRKAPByCompanyIDAndYear(companyID, year string) (*domain.FinancialReportModel, error) {
 	var report domain.FinancialReportModel
 	err := r.db.Preload("Company").Preload("Inputter").
 		Where("company_id = ? AND year = ? AND is_rkap = ?", companyID, year, true).
+		Where("rkap_version = ? OR rkap_status = ?", 0, domain.RKAPStatusApproved).
+		Order("rkap_version DESC").
 		First(&report).Error
 	if err != nil {
 		return nil, err
@@ -71,6 +79,27 @@ func (r *financialReportRepository) GetRKAPByCompanyIDAndYear(companyID, year st
 	return &report, nil
 }
 
+// GetRKAPVersion mengambil satu versi RKAP (0 = RKAP awal) apapun statusnya
+func (r *financialReportRepository) GetRKAPVersion(companyID, year string, version int) (*domain.FinancialReportModel, error) {
+	var report domain.FinancialReportModel
+	err := r.db.Preload("Company").Preload("Inputter").
+		Where("company_id = ? AND year = ? AND is_rkap = ? AND rkap_version = ?", companyID, year, true, version).
+		First(&report).Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (r *financialReportRepository) GetRKAPVersions(companyID, year string) ([]domain.FinancialReportModel, error) {
+	var reports []domain.FinancialReportModel
+	err := r.db.Preload("Inputter").
+		Where("company_id = ? AND year = ? AND is_rkap = ?", companyID, year, true).
+		Order("rkap_version ASC").
+		Find(&reports).Error
+	return reports, err
+}
+
 func (r *financialReportRepository) GetRealisasiByCompanyIDAndPeriod(companyID, period string) (*domain.FinancialReportModel, error) {
 	var report domain.FinancialReportModel
 	err := r.db.Preload("Company").Preload("Inputter").
@@ -82,6 +111,17 @@ func (r *financialReportRepository) GetRealisasiByCompanyIDAndPeriod(companyID,
 	return &report, nil
 }
 
+// GetRealisasiByCompanyIDPeriodAndAudit mengambil realisasi satu periode untuk angka audited atau management account
+func (r *financialReportRepository) GetRealisasiByCompanyIDPeriodAndAudit(companyID, period string, audited bool) (*domain.FinancialReportModel, error) {
+	var report domain.FinancialReportModel
+	err := r.db.Where("company_id = ? AND period = ? AND is_rkap = ? AND is_audited = ?", companyID, period, false, audited).
+		First(&report).Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
 func (r *financialReportRepository) GetRealisasiByCompanyIDAndYear(companyID, year string) ([]domain.FinancialReportModel, error) {
 	var reports []domain.FinancialReportModel
 	err := r.db.Preload("Company").Preload("Inputter").
@@ -91,6 +131,18 @@ func (r *financialReportRepository) GetRealisasiByCompanyIDAndYear(companyID, ye
 	return reports, err
 }
 
+// GetByCompanyIDsAndYear mengambil RKAP dan realisasi satu tahun untuk banyak company dalam satu query
+func (r *financialReportRepository) GetByCompanyIDsAndYear(companyIDs []string, year string) ([]domain.FinancialReportModel, error) {
+	var reports []domain.FinancialReportModel
+	if len(companyIDs) == 0 {
+		return reports, nil
+	}
+	err := r.db.Where("company_id IN ? AND year = ?", companyIDs, year).
+		Order("company_id ASC, period ASC").
+		Find(&reports).Error
+	return reports, err
+}
+
 // GetRealisasiYTD menghitung akumulasi realisasi dari Januari sampai bulan yang dipilih
 func (r *financialReportRepository) GetRealisasiYTD(companyID, year, month string) (*domain.FinancialReportModel, error) {
 	var reports []domain.FinancialReportModel
@@ -100,8 +152,9 @@ func (r *financialReportRepository) GetRealisasiYTD(companyID, year, month strin
 	startPeriod := year + "-01"
 	endPeriod := year + "-" + month
 
-	err := r.db.Where("company_id = ? AND year = ? AND is_rkap = ? AND period >= ? AND period <= ?",
-		companyID, year, false, startPeriod, endPeriod).
+	// Hanya management account bulanan (angka audited dan periode kuartal/semester/tahunan tidak ikut)
+	err := r.db.Where("company_id = ? AND year = ? AND is_rkap = ? AND is_audited = ? AND period >= ? AND period <= ?",
+		companyID, year, false, false, startPeriod, endPeriod).
 		Order("period ASC").
 		Find(&reports).Error
 
@@ -133,6 +186,7 @@ func (r *financialReportRepository) GetRealisasiYTD(companyID, year, month strin
 		ytd.OtherIncome += report.OtherIncome
 		ytd.Tax += report.Tax
 		ytd.NetProfit += report.NetProfit
+		ytd.Dividend += report.Dividend
 		ytd.OperatingCashflow += report.OperatingCashflow
 		ytd.InvestingCashflow += report.InvestingCashflow
 		ytd.FinancingCashflow += report.FinancingCashflow
@@ -204,7 +258,7 @@ func (r *financialReportRepository) DeleteAll() error {
 	return r.db.Exec("DELETE FROM financial_reports").Error
 }
 
-// CountRKAPByCompanyIDAndYear menghitung jumlah RKAP untuk validasi (harus hanya 1 per tahun)
+// CountRKAPByCompanyIDAndYear menghitung jumlah RKAP (termasuk revisi) untuk validasi create RKAP awal
 func (r *financialReportRepository) CountRKAPByCompanyIDAndYear(companyID, year string) (int64, error) {
 	var count int64
 	err := r.db.Model(&domain.FinancialReportModel{}).
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// FinancialRKAPPhasingRepository interface untuk bobot phasing RKAP per versi
+type FinancialRKAPPhasingRepository interface {
+	GetByRKAPID(rkapID string) ([]domain.FinancialRKAPPhasingModel, error)
+	ReplaceForRKAP(rkapID string, phasings []domain.FinancialRKAPPhasingModel) error
+}
+
+type financialRKAPPhasingRepository struct {
+	db *gorm.DB
+}
+
+// NewFinancialRKAPPhasingRepository creates a new RKAP phasing repository
+func NewFinancialRKAPPhasingRepository() FinancialRKAPPhasingRepository {
+	return NewFinancialRKAPPhasingRepositoryWithDB(database.GetDB())
+}
+
+// NewFinancialRKAPPhasingRepositoryWithDB creates a new RKAP phasing repository with injected DB (for testing)
+func NewFinancialRKAPPhasingRepositoryWithDB(db *gorm.DB) FinancialRKAPPhasingRepository {
+	return &financialRKAPPhasingRepository{db: db}
+}
+
+func (r *financialRKAPPhasingRepository) GetByRKAPID(rkapID string) ([]domain.FinancialRKAPPhasingModel, error) {
+	var phasings []domain.FinancialRKAPPhasingModel
+	err := r.db.Where("rkap_id = ?", rkapID).Order("metric ASC, period ASC").Find(&phasings).Error
+	return phasings, err
+}
+
+// ReplaceForRKAP menghapus phasing lama dan menyimpan phasing baru dalam satu transaksi (kosong = hapus phasing)
+func (r *financialRKAPPhasingRepository) ReplaceForRKAP(rkapID string, phasings []domain.FinancialRKAPPhasingModel) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("rkap_id = ?", rkapID).Delete(&domain.FinancialRKAPPhasingModel{}).Error; err != nil {
+			return err
+		}
+		if len(phasings) == 0 {
+			return nil
+		}
+		return tx.Create(&phasings).Error
+	})
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// maxFolderDepth membatasi penelusuran parent folder (jaga-jaga jika ada siklus parent_id)
+const maxFolderDepth = 50
+
+// LegalHoldFilter untuk filter list legal hold
+type LegalHoldFilter struct {
+	ResourceType string
+	ResourceID   string
+	ActiveOnly   bool
+	Since        *time.Time // Hold yang dipasang atau dilepas sejak waktu ini (untuk laporan)
+	Until        *time.Time
+	Limit        int
+	Offset       int
+}
+
+// LegalHoldRepository interface untuk legal hold dokumen/folder/company
+type LegalHoldRepository interface {
+	Create(hold *domain.LegalHoldModel) error
+	Update(hold *domain.LegalHoldModel) error
+	GetByID(id string) (*domain.LegalHoldModel, error)
+	List(filter LegalHoldFilter) ([]domain.LegalHoldModel, int64, error)
+	// FindActiveForDocument mengembalikan hold aktif yang mencakup dokumen (langsung, lewat folder induk, atau company), nil jika tidak ada
+	FindActiveForDocument(doc *domain.DocumentModel) (*domain.LegalHoldModel, error)
+	// FindActiveForFolderTree mengembalikan hold aktif yang mencakup folder beserta sub folder dan dokumen di dalamnya, nil jika tidak ada
+	FindActiveForFolderTree(folderID string) (*domain.LegalHoldModel, error)
+}
+
+type legalHoldRepository struct {
+	db *gorm.DB
+}
+
+// NewLegalHoldRepository creates a new legal hold repository
+func NewLegalHoldRepository() LegalHoldRepository {
+	return NewLegalHoldRepositoryWithDB(database.GetDB())
+}
+
+// NewLegalHoldRepositoryWithDB creates a new legal hold repository with injected DB (for testing)
+func NewLegalHoldRepositoryWithDB(db *gorm.DB) LegalHoldRepository {
+	return &legalHoldRepository{db: db}
+}
+
+func (r *legalHoldRepository) Create(hold *domain.LegalHoldModel) error {
+	return r.db.Create(hold).Error
+}
+
+func (r *legalHoldRepository) Update(hold *domain.LegalHoldModel) error {
+	return r.db.Save(hold).Error
+}
+
+func (r *legalHoldRepository) GetByID(id string) (*domain.LegalHoldModel, error) {
+	var hold domain.LegalHoldModel
+	if err := r.db.Where("id = ?", id).First(&hold).Error; err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+func (r *legalHoldRepository) List(filter LegalHoldFilter) ([]domain.LegalHoldModel, int64, error) {
+	query := r.db.Model(&domain.LegalHoldModel{})
+	if filter.ResourceType != "" {
+		query = query.Where("resource_type = ?", filter.ResourceType)
+	}
+	if filter.ResourceID != "" {
+		query = query.Where("resource_id = ?", filter.ResourceID)
+	}
+	if filter.ActiveOnly {
+		query = query.Where("released_at IS NULL")
+	}
+	if filter.Since != nil {
+		query = query.Where("placed_at >= ? OR released_at >= ?", *filter.Since, *filter.Since)
+	}
+	if filter.Until != nil {
+		query = query.Where("placed_at <= ?", *filter.Until)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit).Offset(filter.Offset)
+	}
+
+	var holds []domain.LegalHoldModel
+	err := query.Order("placed_at DESC").Find(&holds).Error
+	return holds, total, err
+}
+
+func (r *legalHoldRepository) FindActiveForDocument(doc *domain.DocumentModel) (*domain.LegalHoldModel, error) {
+	var folderIDs, companyIDs []string
+	if doc.FolderID != nil {
+		var err error
+		folderIDs, companyIDs, err = r.folderAncestry(*doc.FolderID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if doc.DirectorID != nil {
+		var director domain.DirectorModel
+		err := r.db.Select("id", "company_id").Where("id = ?", *doc.DirectorID).First(&director).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if director.CompanyID != "" {
+			companyIDs = append(companyIDs, director.CompanyID)
+		}
+	}
+
+	return r.findActive(r.db.Where("1 = 0").
+		Or("resource_type = ? AND resource_id = ?", domain.LegalHoldResourceDocument, doc.ID).
+		Or("resource_type = ? AND resource_id IN ?", domain.LegalHoldResourceFolder, folderIDs).
+		Or("resource_type = ? AND resource_id IN ?", domain.LegalHoldResourceCompany, companyIDs))
+}
+
+func (r *legalHoldRepository) FindActiveForFolderTree(folderID string) (*domain.LegalHoldModel, error) {
+	ancestorIDs, companyIDs, err := r.folderAncestry(folderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Kumpulkan seluruh sub folder (BFS per level), termasuk yang ada di recycle bin
+	descendantIDs := []string{}
+	current := []string{folderID}
+	for depth := 0; len(current) > 0 && depth < maxFolderDepth; depth++ {
+		var children []string
+		if err := r.db.Unscoped().Model(&domain.DocumentFolderModel{}).Where("parent_id IN ?", current).Pluck("id", &children).Error; err != nil {
+			return nil, err
+		}
+		descendantIDs = append(descendantIDs, children...)
+		current = children
+	}
+	treeFolderIDs := append([]string{folderID}, descendantIDs...)
+
+	return r.findActive(r.db.Where("1 = 0").
+		Or("resource_type = ? AND resource_id IN ?", domain.LegalHoldResourceFolder, append(ancestorIDs, descendantIDs...)).
+		Or("resource_type = ? AND resource_id IN ?", domain.LegalHoldResourceCompany, companyIDs).
+		Or("resource_type = ? AND resource_id IN (?)", domain.LegalHoldResourceDocument,
+			r.db.Unscoped().Model(&domain.DocumentModel{}).Select("id").Where("folder_id IN ?", treeFolderIDs)))
+}
+
+func (r *legalHoldRepository) findActive(scope *gorm.DB) (*domain.LegalHoldModel, error) {
+	var hold domain.LegalHoldModel
+	err := r.db.Where("released_at IS NULL").Where(scope).Order("placed_at ASC").First(&hold).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+// folderAncestry mengembalikan folder beserta seluruh folder induknya, dan company pemilik folder-folder tersebut.
+// Folder di recycle bin ikut ditelusuri karena hold tetap berlaku sampai isinya di-purge.
+func (r *legalHoldRepository) folderAncestry(folderID string) ([]string, []string, error) {
+	var folderIDs, companyIDs []string
+	currentID := folderID
+	for depth := 0; currentID != "" && depth < maxFolderDepth; depth++ {
+		var folder domain.DocumentFolderModel
+		err := r.db.Unscoped().Select("id", "parent_id", "company_id").Where("id = ?", currentID).First(&folder).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		folderIDs = append(folderIDs, folder.ID)
+		if folder.CompanyID != nil {
+			companyIDs = append(companyIDs, *folder.CompanyID)
+		}
+		currentID = ""
+		if folder.ParentID != nil {
+			currentID = *folder.ParentID
+		}
+	}
+	return folderIDs, companyIDs, nil
+}
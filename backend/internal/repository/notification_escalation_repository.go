@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"gorm.io/gorm"
+)
+
+// NotificationEscalationFilter untuk filter list eskalasi
+type NotificationEscalationFilter struct {
+	Status       string
+	ResourceType string
+	CompanyIDs   []string // nil = semua company
+	Limit        int
+	Offset       int
+}
+
+// NotificationEscalationRepository interface untuk rantai eskalasi notifikasi expired
+type NotificationEscalationRepository interface {
+	OpenIfAbsent(escalation *domain.NotificationEscalationModel) (*domain.NotificationEscalationModel, bool, error)
+	GetByID(id string) (*domain.NotificationEscalationModel, error)
+	List(filter NotificationEscalationFilter) ([]domain.NotificationEscalationModel, int64, error)
+	GetOpen() ([]domain.NotificationEscalationModel, error)
+	GetOpenByResource(resourceType, resourceID string) ([]domain.NotificationEscalationModel, error)
+	RecordLevel(escalation *domain.NotificationEscalationModel, level *domain.NotificationEscalationLevelModel) error
+	Resolve(id string, resolvedBy *string, note string, resolvedAt time.Time) error
+}
+
+type notificationEscalationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationEscalationRepository creates a new notification escalation repository
+func NewNotificationEscalationRepository() NotificationEscalationRepository {
+	return NewNotificationEscalationRepositoryWithDB(database.GetDB())
+}
+
+// NewNotificationEscalationRepositoryWithDB creates a new notification escalation repository with injected DB (for testing)
+func NewNotificationEscalationRepositoryWithDB(db *gorm.DB) NotificationEscalationRepository {
+	return &notificationEscalationRepository{db: db}
+}
+
+// OpenIfAbsent membuat rantai eskalasi baru jika resource + due date belum punya rantai
+// Return rantai yang ada/baru dan flag apakah rantai baru dibuat
+func (r *notificationEscalationRepository) OpenIfAbsent(escalation *domain.NotificationEscalationModel) (*domain.NotificationEscalationModel, bool, error) {
+	var existing domain.NotificationEscalationModel
+	err := r.db.Where("resource_type = ? AND resource_id = ? AND due_date = ?",
+		escalation.ResourceType, escalation.ResourceID, escalation.DueDate).First(&existing).Error
+	if err == nil {
+		return &existing, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+
+	if escalation.ID == "" {
+		escalation.ID = uuid.GenerateUUID()
+	}
+	if escalation.Status == "" {
+		escalation.Status = domain.EscalationStatusOpen
+	}
+	if err := r.db.Create(escalation).Error; err != nil {
+		return nil, false, err
+	}
+	return escalation, true, nil
+}
+
+func (r *notificationEscalationRepository) GetByID(id string) (*domain.NotificationEscalationModel, error) {
+	var escalation domain.NotificationEscalationModel
+	err := r.db.Preload("Levels", func(db *gorm.DB) *gorm.DB {
+		return db.Order("level ASC")
+	}).Where("id = ?", id).First(&escalation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &escalation, nil
+}
+
+func (r *notificationEscalationRepository) List(filter NotificationEscalationFilter) ([]domain.NotificationEscalationModel, int64, error) {
+	var escalations []domain.NotificationEscalationModel
+	var total int64
+
+	query := r.db.Model(&domain.NotificationEscalationModel{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.ResourceType != "" {
+		query = query.Where("resource_type = ?", filter.ResourceType)
+	}
+	if filter.CompanyIDs != nil {
+		if len(filter.CompanyIDs) == 0 {
+			return escalations, 0, nil
+		}
+		query = query.Where("company_id IN ?", filter.CompanyIDs)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.Preload("Levels", func(db *gorm.DB) *gorm.DB {
+		return db.Order("level ASC")
+	}).Order("first_notified_at ASC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit).Offset(filter.Offset)
+	}
+	err := query.Find(&escalations).Error
+	return escalations, total, err
+}
+
+func (r *notificationEscalationRepository) GetOpen() ([]domain.NotificationEscalationModel, error) {
+	var escalations []domain.NotificationEscalationModel
+	err := r.db.Where("status = ?", domain.EscalationStatusOpen).Order("first_notified_at ASC").Find(&escalations).Error
+	return escalations, err
+}
+
+func (r *notificationEscalationRepository) GetOpenByResource(resourceType, resourceID string) ([]domain.NotificationEscalationModel, error) {
+	var escalations []domain.NotificationEscalationModel
+	err := r.db.Where("resource_type = ? AND resource_id = ? AND status = ?", resourceType, resourceID, domain.EscalationStatusOpen).
+		Find(&escalations).Error
+	return escalations, err
+}
+
+// RecordLevel menyimpan level eskalasi dan menaikkan current_level dalam satu transaksi
+func (r *notificationEscalationRepository) RecordLevel(escalation *domain.NotificationEscalationModel, level *domain.NotificationEscalationLevelModel) error {
+	if level.ID == "" {
+		level.ID = uuid.GenerateUUID()
+	}
+	level.EscalationID = escalation.ID
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(level).Error; err != nil {
+			return err
+		}
+		escalatedAt := level.EscalatedAt
+		if err := tx.Model(&domain.NotificationEscalationModel{}).Where("id = ?", escalation.ID).Updates(map[string]interface{}{
+			"current_level":     level.Level,
+			"last_escalated_at": escalatedAt,
+		}).Error; err != nil {
+			return err
+		}
+		escalation.CurrentLevel = level.Level
+		escalation.LastEscalatedAt = &escalatedAt
+		return nil
+	})
+}
+
+func (r *notificationEscalationRepository) Resolve(id string, resolvedBy *string, note string, resolvedAt time.Time) error {
+	return r.db.Model(&domain.NotificationEscalationModel{}).
+		Where("id = ? AND status = ?", id, domain.EscalationStatusOpen).
+		Updates(map[string]interface{}{
+			"status":          domain.EscalationStatusResolved,
+			"resolved_at":     resolvedAt,
+			"resolved_by":     resolvedBy,
+			"resolution_note": note,
+		}).Error
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"gorm.io/gorm"
+)
+
+// NotificationPreferenceRepository interface untuk preferensi notifikasi (company default & override user)
+// dan riwayat reminder yang sudah dikirim
+type NotificationPreferenceRepository interface {
+	GetByScope(scopeType, scopeID string) (*domain.NotificationPreferenceModel, error)
+	GetByScopes(scopeType string, scopeIDs []string) ([]domain.NotificationPreferenceModel, error)
+	Save(pref *domain.NotificationPreferenceModel) error
+	HasReminderLog(userID, resourceType, resourceID, dueDate string, offsetDays int) (bool, error)
+	HasAnyReminderLog(userID, resourceType, resourceID, dueDate string) (bool, error)
+	CreateReminderLog(log *domain.NotificationReminderLogModel) error
+}
+
+type notificationPreferenceRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationPreferenceRepository creates a new notification preference repository
+func NewNotificationPreferenceRepository() NotificationPreferenceRepository {
+	return NewNotificationPreferenceRepositoryWithDB(database.GetDB())
+}
+
+// NewNotificationPreferenceRepositoryWithDB creates a new notification preference repository with injected DB (for testing)
+func NewNotificationPreferenceRepositoryWithDB(db *gorm.DB) NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{db: db}
+}
+
+func (r *notificationPreferenceRepository) GetByScope(scopeType, scopeID string) (*domain.NotificationPreferenceModel, error) {
+	var pref domain.NotificationPreferenceModel
+	err := r.db.Where("scope_type = ? AND scope_id = ?", scopeType, scopeID).First(&pref).Error
+	if err != nil {
+		return nil, err
+	}
+	return &pref, nil
+}
+
+func (r *notificationPreferenceRepository) GetByScopes(scopeType string, scopeIDs []string) ([]domain.NotificationPreferenceModel, error) {
+	var prefs []domain.NotificationPreferenceModel
+	if len(scopeIDs) == 0 {
+		return prefs, nil
+	}
+	err := r.db.Where("scope_type = ? AND scope_id IN ?", scopeType, scopeIDs).Find(&prefs).Error
+	return prefs, err
+}
+
+// Save membuat preferensi baru (jika ID kosong) atau menyimpan seluruh field termasuk nilai nil (kembali mewarisi)
+func (r *notificationPreferenceRepository) Save(pref *domain.NotificationPreferenceModel) error {
+	if pref.ID == "" {
+		pref.ID = uuid.GenerateUUID()
+		return r.db.Create(pref).Error
+	}
+	return r.db.Save(pref).Error
+}
+
+func (r *notificationPreferenceRepository) HasReminderLog(userID, resourceType, resourceID, dueDate string, offsetDays int) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.NotificationReminderLogModel{}).
+		Where("user_id = ? AND resource_type = ? AND resource_id = ? AND due_date = ? AND offset_days = ?",
+			userID, resourceType, resourceID, dueDate, offsetDays).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *notificationPreferenceRepository) HasAnyReminderLog(userID, resourceType, resourceID, dueDate string) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.NotificationReminderLogModel{}).
+		Where("user_id = ? AND resource_type = ? AND resource_id = ? AND due_date = ?",
+			userID, resourceType, resourceID, dueDate).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *notificationPreferenceRepository) CreateReminderLog(log *domain.NotificationReminderLogModel) error {
+	if log.ID == "" {
+		log.ID = uuid.GenerateUUID()
+	}
+	if log.SentAt.IsZero() {
+		log.SentAt = time.Now()
+	}
+	return r.db.Create(log).Error
+}
@@ -74,7 +74,7 @@ func (r *notificationRepository) GetByUserID(userID string, unreadOnly bool, lim
 	// Load documents secara manual untuk notifications dengan resource_type = 'document'
 	// Hanya load jika ada notifications
 	if len(notifications) > 0 {
-		docRepo := NewDocumentRepository()
+		docRepo := NewDocumentRepositoryWithDB(r.db)
 		for i := range notifications {
 			if notifications[i].ResourceType == "document" && notifications[i].ResourceID != nil && *notifications[i].ResourceID != "" {
 				doc, err := docRepo.GetDocumentByID(*notifications[i].ResourceID)
@@ -134,7 +134,7 @@ func (r *notificationRepository) GetByUserIDWithFilters(userID string, unreadOnl
 	// Load documents secara manual untuk notifications dengan resource_type = 'document'
 	// Hanya load jika ada notifications
 	if len(notifications) > 0 {
-		docRepo := NewDocumentRepository()
+		docRepo := NewDocumentRepositoryWithDB(r.db)
 		for i := range notifications {
 			if notifications[i].ResourceType == "document" && notifications[i].ResourceID != nil && *notifications[i].ResourceID != "" {
 				doc, err := docRepo.GetDocumentByID(*notifications[i].ResourceID)
@@ -320,7 +320,7 @@ func (r *notificationRepository) GetAllWithFilters(unreadOnly *bool, daysUntilEx
 
 	// Load documents secara manual
 	if len(notifications) > 0 {
-		docRepo := NewDocumentRepository()
+		docRepo := NewDocumentRepositoryWithDB(r.db)
 		for i := range notifications {
 			if notifications[i].ResourceType == "document" && notifications[i].ResourceID != nil && *notifications[i].ResourceID != "" {
 				doc, err := docRepo.GetDocumentByID(*notifications[i].ResourceID)
@@ -389,7 +389,7 @@ func (r *notificationRepository) GetByUserIDsWithFilters(userIDs []string, unrea
 
 	// Load documents secara manual
 	if len(notifications) > 0 {
-		docRepo := NewDocumentRepository()
+		docRepo := NewDocumentRepositoryWithDB(r.db)
 		for i := range notifications {
 			if notifications[i].ResourceType == "document" && notifications[i].ResourceID != nil && *notifications[i].ResourceID != "" {
 				doc, err := docRepo.GetDocumentByID(*notifications[i].ResourceID)
@@ -74,11 +74,11 @@ func (r *notificationSettingsRepository) GetOrCreate(userID string) (*domain.Not
 	if err == gorm.ErrRecordNotFound {
 		// Generate ID sebelum create untuk menghindari duplicate key error
 		settings = &domain.NotificationSettingsModel{
-			ID:                  uuid.GenerateUUID(), // Generate UUID untuk primary key
-			UserID:              userID,
-			EmailEnabled:        true,
-			InAppEnabled:        true,
-			ExpiryThresholdDays: 14, // Default: 14 hari
+			ID:           uuid.GenerateUUID(), // Generate UUID untuk primary key
+			UserID:       userID,
+			EmailEnabled: true,
+			InAppEnabled: true,
+			// ExpiryThresholdDays dibiarkan nil: ikut default company/sistem sampai user mengaturnya
 		}
 		if err := r.Create(settings); err != nil {
 			// Jika create gagal karena duplicate (race condition), coba get lagi
@@ -6,7 +6,9 @@ import (
 	"gorm.io/gorm"
 )
 
-// ReportRepository interface untuk report operations
+// ReportRepository interface untuk report operations.
+// Sejak tabel reports dipensiunkan, repository ini adalah facade di atas realisasi bulanan financial_reports
+// sehingga endpoint /reports tetap bekerja dengan bentuk data lama.
 type ReportRepository interface {
 	Create(report *domain.ReportModel) error
 	GetByID(id string) (*domain.ReportModel, error)
@@ -16,8 +18,9 @@ type ReportRepository interface {
 	GetByCompanyIDs(companyIDs []string) ([]domain.ReportModel, error) // For admin to get reports from their company and children
 	Update(report *domain.ReportModel) error
 	Delete(id string) error
-	DeleteAll() error // For reset functionality
+	DeleteSeeded() error // For reset functionality: hanya realisasi dari report seeder
 	Count() (int64, error)
+	CountSeeded() (int64, error)
 }
 
 type reportRepository struct {
@@ -36,69 +39,140 @@ func NewReportRepository() ReportRepository {
 	return NewReportRepositoryWithDB(database.GetDB())
 }
 
-func (r *reportRepository) Create(report *domain.ReportModel) error {
-	return r.db.Create(report).Error
+// monthlyRealisasi membatasi query ke realisasi bulanan management account, sama seperti GetRealisasiYTD:
+// RKAP, angka audited, dan periode kuartal/semester/tahunan tidak pernah tampil (atau terhapus) lewat /reports
+func monthlyRealisasi(db *gorm.DB) *gorm.DB {
+	return db.Where("is_rkap = ? AND is_audited = ? AND period_type IN ?", false, false, []string{"", domain.FinancialPeriodMonthly})
 }
 
-func (r *reportRepository) GetByID(id string) (*domain.ReportModel, error) {
-	var report domain.ReportModel
-	err := r.db.Preload("Company").Preload("Inputter").Where("id = ?", id).First(&report).Error
+func (r *reportRepository) realisasi() *gorm.DB {
+	return r.db.Model(&domain.FinancialReportModel{}).Scopes(monthlyRealisasi)
+}
+
+// ReportFromFinancialReport mengubah realisasi financial report ke bentuk report lama
+func ReportFromFinancialReport(financial *domain.FinancialReportModel) domain.ReportModel {
+	return domain.ReportModel{
+		ID:             financial.ID,
+		Period:         financial.Period,
+		CompanyID:      financial.CompanyID,
+		InputterID:     financial.InputterID,
+		Revenue:        financial.Revenue,
+		Opex:           financial.OperatingExpenses,
+		NPAT:           financial.NetProfit,
+		Dividend:       financial.Dividend,
+		FinancialRatio: financial.FinancialRatio,
+		Attachment:     financial.Attachment,
+		Remark:         financial.Remark,
+		CreatedAt:      financial.CreatedAt,
+		UpdatedAt:      financial.UpdatedAt,
+		Company:        financial.Company,
+		Inputter:       financial.Inputter,
+	}
+}
+
+// ApplyReportToFinancialReport menyalin field report lama ke financial report; field lain (neraca, arus kas, dll) tidak disentuh
+func ApplyReportToFinancialReport(report *domain.ReportModel, financial *domain.FinancialReportModel) {
+	financial.CompanyID = report.CompanyID
+	financial.Period = report.Period
+	if len(report.Period) >= 4 {
+		financial.Year = report.Period[:4]
+	}
+	financial.IsRKAP = false
+	financial.IsAudited = false
+	financial.PeriodType = domain.FinancialPeriodMonthly
+	financial.InputterID = report.InputterID
+	financial.Revenue = report.Revenue
+	financial.OperatingExpenses = report.Opex
+	financial.NetProfit = report.NPAT
+	financial.Dividend = report.Dividend
+	financial.FinancialRatio = report.FinancialRatio
+	financial.Attachment = report.Attachment
+	financial.Remark = report.Remark
+}
+
+func (r *reportRepository) find(query *gorm.DB) ([]domain.ReportModel, error) {
+	var financials []domain.FinancialReportModel
+	err := query.Preload("Company").Preload("Inputter").
+		Order("period DESC, created_at DESC").
+		Find(&financials).Error
 	if err != nil {
 		return nil, err
 	}
+	reports := make([]domain.ReportModel, 0, len(financials))
+	for i := range financials {
+		reports = append(reports, ReportFromFinancialReport(&financials[i]))
+	}
+	return reports, nil
+}
+
+func (r *reportRepository) first(query *gorm.DB) (*domain.ReportModel, error) {
+	var financial domain.FinancialReportModel
+	if err := query.Preload("Company").Preload("Inputter").First(&financial).Error; err != nil {
+		return nil, err
+	}
+	report := ReportFromFinancialReport(&financial)
 	return &report, nil
 }
 
+func (r *reportRepository) Create(report *domain.ReportModel) error {
+	financial := &domain.FinancialReportModel{ID: report.ID}
+	ApplyReportToFinancialReport(report, financial)
+	if err := r.db.Create(financial).Error; err != nil {
+		return err
+	}
+	report.CreatedAt = financial.CreatedAt
+	report.UpdatedAt = financial.UpdatedAt
+	return nil
+}
+
+func (r *reportRepository) GetByID(id string) (*domain.ReportModel, error) {
+	return r.first(r.realisasi().Where("id = ?", id))
+}
+
 func (r *reportRepository) GetAll() ([]domain.ReportModel, error) {
-	var reports []domain.ReportModel
-	err := r.db.Preload("Company").Preload("Inputter").Order("period DESC, created_at DESC").Find(&reports).Error
-	return reports, err
+	return r.find(r.realisasi())
 }
 
 func (r *reportRepository) GetByCompanyID(companyID string) ([]domain.ReportModel, error) {
-	var reports []domain.ReportModel
-	err := r.db.Preload("Company").Preload("Inputter").
-		Where("company_id = ?", companyID).
-		Order("period DESC, created_at DESC").
-		Find(&reports).Error
-	return reports, err
+	return r.find(r.realisasi().Where("company_id = ?", companyID))
 }
 
 func (r *reportRepository) GetByCompanyIDAndPeriod(companyID, period string) (*domain.ReportModel, error) {
-	var report domain.ReportModel
-	err := r.db.Preload("Company").Preload("Inputter").
-		Where("company_id = ? AND period = ?", companyID, period).
-		First(&report).Error
-	if err != nil {
-		return nil, err
-	}
-	return &report, nil
+	return r.first(r.realisasi().Where("company_id = ? AND period = ?", companyID, period))
 }
 
 func (r *reportRepository) GetByCompanyIDs(companyIDs []string) ([]domain.ReportModel, error) {
-	var reports []domain.ReportModel
-	err := r.db.Preload("Company").Preload("Inputter").
-		Where("company_id IN ?", companyIDs).
-		Order("period DESC, created_at DESC").
-		Find(&reports).Error
-	return reports, err
+	return r.find(r.realisasi().Where("company_id IN ?", companyIDs))
 }
 
+// Update hanya mengubah field yang dikenal report lama, data neraca/arus kas/rasio lain tetap utuh
 func (r *reportRepository) Update(report *domain.ReportModel) error {
-	return r.db.Save(report).Error
+	var financial domain.FinancialReportModel
+	if err := r.realisasi().Where("id = ?", report.ID).First(&financial).Error; err != nil {
+		return err
+	}
+	ApplyReportToFinancialReport(report, &financial)
+	return r.db.Save(&financial).Error
 }
 
+// Delete menghapus realisasi financial report yang sama (report lama dan realisasi adalah satu record)
 func (r *reportRepository) Delete(id string) error {
-	return r.db.Delete(&domain.ReportModel{}, "id = ?", id).Error
+	return r.db.Scopes(monthlyRealisasi).Delete(&domain.FinancialReportModel{}, "id = ?", id).Error
 }
 
-func (r *reportRepository) DeleteAll() error {
-	return r.db.Exec("DELETE FROM reports").Error
+// DeleteSeeded menghapus realisasi yang dibuat report seeder; realisasi yang diinput atau diimport user tidak tersentuh
+func (r *reportRepository) DeleteSeeded() error {
+	return r.db.Scopes(monthlyRealisasi).Where("source = ?", domain.FinancialReportSourceSeeder).Delete(&domain.FinancialReportModel{}).Error
 }
 
 func (r *reportRepository) Count() (int64, error) {
 	var count int64
-	err := r.db.Model(&domain.ReportModel{}).Count(&count).Error
+	err := r.realisasi().Count(&count).Error
 	return count, err
 }
 
+func (r *reportRepository) CountSeeded() (int64, error) {
+	var count int64
+	err := r.realisasi().Where("source = ?", domain.FinancialReportSourceSeeder).Count(&count).Error
+	return count, err
+}
@@ -9,6 +9,8 @@ import (
 type ShareholderRepository interface {
 	Create(shareholder *domain.ShareholderModel) error
 	GetByCompanyID(companyID string) ([]domain.ShareholderModel, error)
+	GetAll() ([]domain.ShareholderModel, error) // Semua pemegang saham (untuk graph kepemilikan grup)
+	Update(shareholder *domain.ShareholderModel) error
 	DeleteByCompanyID(companyID string) error
 	Delete(id string) error
 }
@@ -39,6 +41,16 @@ func (r *shareholderRepository) GetByCompanyID(companyID string) ([]domain.Share
 	return shareholders, err
 }
 
+func (r *shareholderRepository) GetAll() ([]domain.ShareholderModel, error) {
+	var shareholders []domain.ShareholderModel
+	err := r.db.Order("company_id ASC, ownership_percent DESC").Find(&shareholders).Error
+	return shareholders, err
+}
+
+func (r *shareholderRepository) Update(shareholder *domain.ShareholderModel) error {
+	return r.db.Omit("ShareholderCompany").Save(shareholder).Error
+}
+
 func (r *shareholderRepository) DeleteByCompanyID(companyID string) error {
 	return r.db.Where("company_id = ?", companyID).Delete(&domain.ShareholderModel{}).Error
 }
@@ -15,6 +15,7 @@ type UserRepository interface {
 	GetByUsernameOrEmail(usernameOrEmail string) (*domain.UserModel, error)
 	GetByCompanyID(companyID string) ([]domain.UserModel, error)
 	GetByRoleID(roleID string) ([]domain.UserModel, error)
+	GetActiveAdminsByCompanyID(companyID string) ([]domain.UserModel, error)
 	GetAll() ([]domain.UserModel, error)
 	Update(user *domain.UserModel) error
 	Delete(id string) error
@@ -92,6 +93,22 @@ func (r *userRepository) GetByRoleID(roleID string) ([]domain.UserModel, error)
 	return users, err
 }
 
+// GetActiveAdminsByCompanyID mengambil user aktif dengan role admin di company tersebut,
+// baik dari company utama user maupun dari assignment tambahan (user_company_assignments)
+func (r *userRepository) GetActiveAdminsByCompanyID(companyID string) ([]domain.UserModel, error) {
+	var users []domain.UserModel
+	assignedAdmins := r.db.Table("user_company_assignments").
+		Select("user_company_assignments.user_id").
+		Joins("JOIN roles ON roles.id = user_company_assignments.role_id").
+		Where("user_company_assignments.company_id = ? AND user_company_assignments.is_active = ? AND roles.name = ?", companyID, true, "admin")
+	err := r.db.Model(&domain.UserModel{}).
+		Joins("LEFT JOIN roles ON roles.id = users.role_id").
+		Where("users.is_active = ?", true).
+		Where("(users.company_id = ? AND (roles.name = ? OR users.role = ?)) OR users.id IN (?)", companyID, "admin", "admin", assignedAdmins).
+		Find(&users).Error
+	return users, err
+}
+
 func (r *userRepository) GetAll() ([]domain.UserModel, error) {
 	var users []domain.UserModel
 	err := r.db.Find(&users).Error
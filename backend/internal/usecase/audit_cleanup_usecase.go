@@ -8,7 +8,9 @@ import (
 	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/audit"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/health"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/observability"
 	"go.uber.org/zap"
 )
 
@@ -21,6 +23,9 @@ const (
 	DefaultTechnicalErrorRetentionDays = 30
 )
 
+// Nama job untuk metric dms_job_*
+const jobAuditLogCleanup = "audit_log_cleanup"
+
 // getRetentionDays mengambil periode retention dari environment variable
 func GetRetentionDays(logType string) int {
 	var envKey string
@@ -53,7 +58,8 @@ func GetRetentionDays(logType string) int {
 }
 
 // CleanupOldAuditLogs menghapus audit logs yang sudah melewati retention period
-func CleanupOldAuditLogs() error {
+func CleanupOldAuditLogs() (err error) {
+	defer func(start time.Time) { observability.ObserveJob(jobAuditLogCleanup, start, err) }(time.Now())
 	zapLog := logger.GetLogger()
 	
 	// Cleanup user actions
@@ -65,6 +71,7 @@ func CleanupOldAuditLogs() error {
 		zapLog.Error("Error cleaning up user action logs", zap.Error(result.Error))
 		return result.Error
 	}
+	observability.AddJobItems(jobAuditLogCleanup, "user_action_logs_deleted", int(result.RowsAffected))
 	if result.RowsAffected > 0 {
 		zapLog.Info("Cleaned up user action logs",
 			zap.Int64("count", result.RowsAffected),
@@ -81,6 +88,7 @@ func CleanupOldAuditLogs() error {
 		zapLog.Error("Error cleaning up technical error logs", zap.Error(result.Error))
 		return result.Error
 	}
+	observability.AddJobItems(jobAuditLogCleanup, "technical_error_logs_deleted", int(result.RowsAffected))
 	if result.RowsAffected > 0 {
 		zapLog.Info("Cleaned up technical error logs",
 			zap.Int64("count", result.RowsAffected),
@@ -93,24 +101,23 @@ func CleanupOldAuditLogs() error {
 
 // StartAuditLogCleanup memulai background cleanup job untuk audit logs
 func StartAuditLogCleanup() {
-	// Jalankan cleanup setiap 24 jam
-	ticker := time.NewTicker(24 * time.Hour)
-	defer ticker.Stop()
+	health.RegisterHeartbeat(jobAuditLogCleanup, 24*time.Hour, 1*time.Hour)
 
-	// Jalankan cleanup pertama kali setelah 1 jam
+	// Jalankan cleanup pertama kali setelah 1 jam, lalu setiap 24 jam
+	// Catatan: ticker dibuat di dalam goroutine agar tidak berhenti saat fungsi ini return
 	go func() {
 		zapLog := logger.GetLogger()
 		time.Sleep(1 * time.Hour)
+		health.Beat(jobAuditLogCleanup)
 		zapLog.Info("Starting initial audit log cleanup")
 		if err := CleanupOldAuditLogs(); err != nil {
 			zapLog.Error("Initial audit log cleanup failed", zap.Error(err))
 		}
-	}()
 
-	// Jalankan cleanup secara berkala
-	go func() {
-		zapLog := logger.GetLogger()
+		ticker := time.NewTicker(24 * time.Hour)
+		defer ticker.Stop()
 		for range ticker.C {
+			health.Beat(jobAuditLogCleanup)
 			zapLog.Info("Running scheduled audit log cleanup")
 			if err := CleanupOldAuditLogs(); err != nil {
 				zapLog.Error("Scheduled audit log cleanup failed", zap.Error(err))
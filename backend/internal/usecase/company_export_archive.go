@@ -0,0 +1,579 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/storage"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Struktur arsip export data company (zip):
+//
+//	manifest.json                         ringkasan export, daftar company, jumlah data, file yang hilang
+//	index.xlsx                            index company, dokumen, dan laporan keuangan
+//	companies/<kode>/company.json         profil company (+ logo.<ext>)
+//	companies/<kode>/shareholders.json    pemegang saham
+//	companies/<kode>/business_fields.json bidang usaha
+//	companies/<kode>/directors.json       pengurus, director_terms.json untuk riwayat masa jabatan
+//	companies/<kode>/document_folders.json, documents.json, files/<document id>_<nama file>
+//	companies/<kode>/financial_reports.json, legacy_reports.json (jika tabel reports lama masih ada)
+//	companies/<kode>/user_assignments.json user yang terhubung ke company (tanpa password)
+//	companies/<kode>/audit_logs.json, activity_logs.json log yang mereferensikan data di atas
+const companyExportFormatVersion = 1
+
+// companyExportProgressEvery menentukan seberapa sering progress job disimpan (per N tahap)
+const companyExportProgressEvery = 10
+
+// companyExportLogBatchSize membatasi jumlah ID per query IN saat mengambil audit log
+const companyExportLogBatchSize = 500
+
+type companyExportManifest struct {
+	FormatVersion      int                            `json:"format_version"`
+	ExportID           string                         `json:"export_id"`
+	GeneratedAt        time.Time                      `json:"generated_at"`
+	GeneratedBy        string                         `json:"generated_by"`
+	RootCompany        companyExportManifestCompany   `json:"root_company"`
+	IncludeDescendants bool                           `json:"include_descendants"`
+	LegacyReports      bool                           `json:"legacy_reports_included"`
+	Companies          []companyExportManifestCompany `json:"companies"`
+	Counts             map[string]int                 `json:"counts"`
+	MissingFiles       []companyExportMissingFile     `json:"missing_files"`
+}
+
+type companyExportManifestCompany struct {
+	ID        string  `json:"id"`
+	Code      string  `json:"code"`
+	Name      string  `json:"name"`
+	ParentID  *string `json:"parent_id"`
+	Directory string  `json:"directory,omitempty"`
+}
+
+// companyExportMissingFile adalah file yang direferensikan data tapi tidak ada di storage
+type companyExportMissingFile struct {
+	CompanyCode string `json:"company_code"`
+	DocumentID  string `json:"document_id,omitempty"` // Kosong untuk logo company
+	URL         string `json:"url"`
+	Reason      string `json:"reason"`
+}
+
+type companyExportProfile struct {
+	domain.CompanyModel
+	LogoArchivePath string `json:"logo_archive_path,omitempty"`
+}
+
+type companyExportDocument struct {
+	domain.DocumentModel
+	ArchivePath string `json:"archive_path,omitempty"` // Lokasi file di dalam arsip
+	FileMissing bool   `json:"file_missing,omitempty"`
+}
+
+// companyExportUser adalah user yang terhubung ke company, lewat users.company_id (primary) atau assignment
+type companyExportUser struct {
+	UserID       string  `json:"user_id"`
+	Username     string  `json:"username"`
+	Email        string  `json:"email"`
+	Role         string  `json:"role"`
+	IsActive     bool    `json:"is_active"`
+	Source       string  `json:"source"` // primary atau assignment
+	AssignmentID *string `json:"assignment_id,omitempty"`
+}
+
+// companyExportJSONFile adalah satu file JSON di folder company beserta jumlah record-nya (untuk manifest)
+type companyExportJSONFile struct {
+	name  string
+	value interface{}
+	count int
+}
+
+// companyExportBuilder menulis arsip zip ke file sementara, company demi company
+type companyExportBuilder struct {
+	db          *gorm.DB
+	manager     storage.StorageManager
+	job         *domain.CompanyExportJobModel
+	generatedAt time.Time
+	zw          *zip.Writer
+	manifest    companyExportManifest
+
+	totalSteps     int
+	processedSteps int
+	documentCount  int
+	progress       func(fields map[string]interface{})
+
+	// Baris sheet index.xlsx
+	companyRows  [][]interface{}
+	documentRows [][]interface{}
+	reportRows   [][]interface{}
+}
+
+func newCompanyExportBuilder(db *gorm.DB, manager storage.StorageManager, job *domain.CompanyExportJobModel, generatedAt time.Time) *companyExportBuilder {
+	return &companyExportBuilder{
+		db:          db,
+		manager:     manager,
+		job:         job,
+		generatedAt: generatedAt.UTC(),
+		manifest: companyExportManifest{
+			FormatVersion:      companyExportFormatVersion,
+			ExportID:           job.ID,
+			GeneratedAt:        generatedAt.UTC(),
+			GeneratedBy:        job.CreatedByName,
+			IncludeDescendants: job.IncludeDescendants,
+			Counts:             map[string]int{},
+			MissingFiles:       []companyExportMissingFile{},
+		},
+	}
+}
+
+// companyDocumentsQuery memilih dokumen milik company: berada di folder company atau milik pengurus company
+func companyDocumentsQuery(db *gorm.DB, companyID string) *gorm.DB {
+	return db.Model(&domain.DocumentModel{}).
+		Where("folder_id IN (?) OR director_id IN (?)",
+			db.Model(&domain.DocumentFolderModel{}).Select("id").Where("company_id = ?", companyID),
+			db.Model(&domain.DirectorModel{}).Select("id").Where("company_id = ?", companyID),
+		)
+}
+
+// build menulis arsip zip ke dst secara bertahap. Company pertama adalah company yang diminta.
+func (b *companyExportBuilder) build(dst io.Writer, companies []domain.CompanyModel, progress func(fields map[string]interface{})) error {
+	b.progress = progress
+	b.manifest.LegacyReports = b.db.Migrator().HasTable(&domain.ReportModel{})
+
+	for _, company := range companies {
+		var count int64
+		if err := companyDocumentsQuery(b.db, company.ID).Count(&count).Error; err != nil {
+			return fmt.Errorf("gagal menghitung dokumen: %w", err)
+		}
+		b.totalSteps += int(count)
+	}
+	b.totalSteps += len(companies) + 1 // Satu tahap per company + index/manifest
+	b.progress(map[string]interface{}{"total_steps": b.totalSteps, "company_count": len(companies)})
+
+	b.zw = zip.NewWriter(dst)
+
+	directories := companyExportDirectories(companies)
+	codes := make(map[string]string, len(companies))
+	for _, company := range companies {
+		codes[company.ID] = company.Code
+	}
+	for i := range companies {
+		company := companies[i]
+		entry := companyExportManifestCompany{ID: company.ID, Code: company.Code, Name: company.Name, ParentID: company.ParentID, Directory: directories[company.ID]}
+		if i == 0 {
+			b.manifest.RootCompany = companyExportManifestCompany{ID: company.ID, Code: company.Code, Name: company.Name, ParentID: company.ParentID}
+		}
+		b.manifest.Companies = append(b.manifest.Companies, entry)
+
+		b.progress(map[string]interface{}{"current_step": fmt.Sprintf("Company %s", company.Code)})
+		if err := b.addCompany(&company, entry.Directory, codes); err != nil {
+			return fmt.Errorf("gagal mengekspor company %s: %w", company.Code, err)
+		}
+		b.step(true)
+	}
+
+	b.progress(map[string]interface{}{"current_step": "Index"})
+	index, err := b.buildIndex()
+	if err != nil {
+		return fmt.Errorf("gagal membuat index.xlsx: %w", err)
+	}
+	if err := b.writeEntry("index.xlsx", index); err != nil {
+		return err
+	}
+	manifestJSON, err := json.MarshalIndent(b.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := b.writeEntry("manifest.json", manifestJSON); err != nil {
+		return err
+	}
+	return b.zw.Close()
+}
+
+// step menambah progress; disimpan ke job setiap companyExportProgressEvery tahap atau jika force
+func (b *companyExportBuilder) step(force bool) {
+	b.processedSteps++
+	if force || b.processedSteps%companyExportProgressEvery == 0 {
+		b.progress(map[string]interface{}{"processed_steps": b.processedSteps})
+	}
+}
+
+// addCompany mengumpulkan seluruh data satu company dan menulisnya ke folder company di arsip
+func (b *companyExportBuilder) addCompany(company *domain.CompanyModel, dir string, codes map[string]string) error {
+	var (
+		shareholders    []domain.ShareholderModel
+		businessFields  []domain.BusinessFieldModel
+		directors       []domain.DirectorModel
+		directorTerms   []domain.DirectorTermModel
+		folders         []domain.DocumentFolderModel
+		documents       []domain.DocumentModel
+		financials      []domain.FinancialReportModel
+		legacyReports   []domain.ReportModel
+		userAssignments []companyExportUser
+	)
+	byCompany := func(dest interface{}, order string) error {
+		return b.db.Where("company_id = ?", company.ID).Order(order).Find(dest).Error
+	}
+	if err := byCompany(&shareholders, "created_at"); err != nil {
+		return err
+	}
+	if err := byCompany(&businessFields, "created_at"); err != nil {
+		return err
+	}
+	if err := byCompany(&directors, "created_at"); err != nil {
+		return err
+	}
+	if err := byCompany(&directorTerms, "director_id, term_number"); err != nil {
+		return err
+	}
+	if err := byCompany(&folders, "created_at"); err != nil {
+		return err
+	}
+	if err := companyDocumentsQuery(b.db, company.ID).Order("created_at").Find(&documents).Error; err != nil {
+		return err
+	}
+	if err := byCompany(&financials, "period, is_rkap, rkap_version"); err != nil {
+		return err
+	}
+	if b.manifest.LegacyReports {
+		if err := byCompany(&legacyReports, "period"); err != nil {
+			return err
+		}
+	}
+	userAssignments, err := b.companyUsers(company.ID)
+	if err != nil {
+		return err
+	}
+
+	// Profil company + logo
+	profile := companyExportProfile{CompanyModel: *company}
+	profile.Shareholders, profile.BusinessFields, profile.Directors = nil, nil, nil
+	if company.Logo != "" {
+		data, ext, missing, err := b.readStoredFile(company.Logo)
+		if err != nil {
+			return err
+		}
+		if missing != "" {
+			b.addMissing(company.Code, "", company.Logo, missing)
+		} else {
+			profile.LogoArchivePath = dir + "/logo" + ext
+			if err := b.writeEntry(profile.LogoArchivePath, data); err != nil {
+				return err
+			}
+		}
+	}
+
+	// File dokumen
+	folderNames := make(map[string]string, len(folders))
+	for _, folder := range folders {
+		folderNames[folder.ID] = folder.Name
+	}
+	exported := make([]companyExportDocument, 0, len(documents))
+	for _, document := range documents {
+		item := companyExportDocument{DocumentModel: document}
+		data, _, missing, err := b.readStoredFile(document.FilePath)
+		if err != nil {
+			return err
+		}
+		if missing != "" {
+			item.FileMissing = true
+			b.addMissing(company.Code, document.ID, document.FilePath, missing)
+		} else {
+			item.ArchivePath = fmt.Sprintf("%s/files/%s_%s", dir, document.ID, companyExportSafeName(document.FileName))
+			if err := b.writeEntry(item.ArchivePath, data); err != nil {
+				return err
+			}
+		}
+		exported = append(exported, item)
+
+		folderName := ""
+		if document.FolderID != nil {
+			folderName = folderNames[*document.FolderID]
+		}
+		note := ""
+		if item.FileMissing {
+			note = "File tidak ditemukan di storage"
+		}
+		b.documentRows = append(b.documentRows, []interface{}{
+			company.Code, folderName, document.Name, document.FileName, document.MimeType, document.Size,
+			document.Status, document.CreatedAt.Format("2006-01-02 15:04"), item.ArchivePath, note,
+		})
+		b.documentCount++
+		b.step(false)
+	}
+
+	for _, report := range financials {
+		reportType := "Realisasi"
+		if report.IsRKAP {
+			reportType = "RKAP"
+		}
+		b.reportRows = append(b.reportRows, []interface{}{
+			company.Code, report.Year, report.Period, reportType, report.PeriodType, report.IsAudited,
+			report.RKAPVersion, report.RKAPStatus, report.Revenue, report.NetProfit, dir + "/financial_reports.json",
+		})
+	}
+
+	// Log yang mereferensikan company dan data turunannya
+	resourceIDs := []string{company.ID}
+	resourceIDs = appendIDs(resourceIDs, shareholders, func(m domain.ShareholderModel) string { return m.ID })
+	resourceIDs = appendIDs(resourceIDs, businessFields, func(m domain.BusinessFieldModel) string { return m.ID })
+	resourceIDs = appendIDs(resourceIDs, directors, func(m domain.DirectorModel) string { return m.ID })
+	resourceIDs = appendIDs(resourceIDs, directorTerms, func(m domain.DirectorTermModel) string { return m.ID })
+	resourceIDs = appendIDs(resourceIDs, folders, func(m domain.DocumentFolderModel) string { return m.ID })
+	resourceIDs = appendIDs(resourceIDs, documents, func(m domain.DocumentModel) string { return m.ID })
+	resourceIDs = appendIDs(resourceIDs, financials, func(m domain.FinancialReportModel) string { return m.ID })
+	resourceIDs = appendIDs(resourceIDs, legacyReports, func(m domain.ReportModel) string { return m.ID })
+	auditLogs := []domain.AuditLog{}
+	activityLogs := []domain.UserActivityLog{}
+	for start := 0; start < len(resourceIDs); start += companyExportLogBatchSize {
+		end := start + companyExportLogBatchSize
+		if end > len(resourceIDs) {
+			end = len(resourceIDs)
+		}
+		var auditBatch []domain.AuditLog
+		if err := b.db.Where("resource_id IN ?", resourceIDs[start:end]).Order("created_at").Find(&auditBatch).Error; err != nil {
+			return err
+		}
+		auditLogs = append(auditLogs, auditBatch...)
+		var activityBatch []domain.UserActivityLog
+		if err := b.db.Where("resource_id IN ?", resourceIDs[start:end]).Order("created_at").Find(&activityBatch).Error; err != nil {
+			return err
+		}
+		activityLogs = append(activityLogs, activityBatch...)
+	}
+
+	files := []companyExportJSONFile{
+		{"company.json", profile, 1},
+		{"shareholders.json", shareholders, len(shareholders)},
+		{"business_fields.json", businessFields, len(businessFields)},
+		{"directors.json", directors, len(directors)},
+		{"director_terms.json", directorTerms, len(directorTerms)},
+		{"document_folders.json", folders, len(folders)},
+		{"documents.json", exported, len(exported)},
+		{"financial_reports.json", financials, len(financials)},
+		{"user_assignments.json", userAssignments, len(userAssignments)},
+		{"audit_logs.json", auditLogs, len(auditLogs)},
+		{"activity_logs.json", activityLogs, len(activityLogs)},
+	}
+	if b.manifest.LegacyReports {
+		files = append(files, companyExportJSONFile{"legacy_reports.json", legacyReports, len(legacyReports)})
+	}
+	for _, file := range files {
+		content, err := json.MarshalIndent(file.value, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := b.writeEntry(dir+"/"+file.name, content); err != nil {
+			return err
+		}
+		b.manifest.Counts[strings.TrimSuffix(file.name, ".json")] += file.count
+	}
+
+	parentCode := ""
+	if company.ParentID != nil {
+		parentCode = codes[*company.ParentID]
+	}
+	b.companyRows = append(b.companyRows, []interface{}{
+		company.Code, company.Name, company.Level, parentCode, company.Status, company.IsActive,
+		len(shareholders), len(directors), len(documents), len(financials), dir,
+	})
+	return nil
+}
+
+// companyUsers mengambil user dengan company utama ini dan user yang di-assign ke company ini
+func (b *companyExportBuilder) companyUsers(companyID string) ([]companyExportUser, error) {
+	users := []companyExportUser{}
+	var primary []companyExportUser
+	err := b.db.Table("users").
+		Select("users.id AS user_id, users.username, users.email, COALESCE(roles.name, users.role) AS role, users.is_active").
+		Joins("LEFT JOIN roles ON roles.id = users.role_id").
+		Where("users.company_id = ?", companyID).
+		Order("users.username").
+		Scan(&primary).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range primary {
+		user.Source = "primary"
+		users = append(users, user)
+	}
+
+	var assigned []companyExportUser
+	err = b.db.Table("user_company_assignments").
+		Select("user_company_assignments.id AS assignment_id, users.id AS user_id, users.username, users.email, COALESCE(roles.name, '') AS role, user_company_assignments.is_active").
+		Joins("JOIN users ON users.id = user_company_assignments.user_id").
+		Joins("LEFT JOIN roles ON roles.id = user_company_assignments.role_id").
+		Where("user_company_assignments.company_id = ?", companyID).
+		Order("users.username").
+		Scan(&assigned).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range assigned {
+		user.Source = "assignment"
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// readStoredFile membaca file dari storage. File yang tidak ada (atau URL tidak valid) dikembalikan
+// sebagai alasan missing, bukan error; error storage lain membatalkan export.
+func (b *companyExportBuilder) readStoredFile(fileURL string) ([]byte, string, string, error) {
+	objectPath, err := storage.ObjectPathFromURL(fileURL)
+	if err != nil {
+		return nil, "", err.Error(), nil
+	}
+	exists, err := b.manager.FileExists(path.Dir(objectPath), path.Base(objectPath))
+	if err != nil {
+		return nil, "", "", fmt.Errorf("gagal mengecek file %s: %w", objectPath, err)
+	}
+	if !exists {
+		return nil, "", "file not found in storage", nil
+	}
+	data, err := storage.ReadFileByURL(b.manager, objectPath)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("gagal membaca file %s: %w", objectPath, err)
+	}
+	return data, path.Ext(objectPath), "", nil
+}
+
+func (b *companyExportBuilder) addMissing(companyCode, documentID, fileURL, reason string) {
+	b.manifest.MissingFiles = append(b.manifest.MissingFiles, companyExportMissingFile{
+		CompanyCode: companyCode,
+		DocumentID:  documentID,
+		URL:         fileURL,
+		Reason:      reason,
+	})
+	logger.GetLogger().Warn("Company export file missing",
+		zap.String("job_id", b.job.ID),
+		zap.String("company_code", companyCode),
+		zap.String("url", fileURL),
+		zap.String("reason", reason),
+	)
+}
+
+func (b *companyExportBuilder) writeEntry(name string, data []byte) error {
+	w, err := b.zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: b.generatedAt})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// buildIndex membuat index.xlsx: ringkasan, daftar company, dokumen, dan laporan keuangan
+func (b *companyExportBuilder) buildIndex() ([]byte, error) {
+	f := excelize.NewFile()
+	defer func() {
+		if err := f.Close(); err != nil {
+			logger.GetLogger().Warn("Failed to close Excel file", zap.Error(err))
+		}
+	}()
+
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"#E0E0E0"}, Pattern: 1},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	summary := [][]interface{}{
+		{"Export ID", b.job.ID},
+		{"Perusahaan", b.manifest.RootCompany.Name},
+		{"Kode Perusahaan", b.manifest.RootCompany.Code},
+		{"Termasuk Anak Perusahaan", b.job.IncludeDescendants},
+		{"Dibuat Oleh", b.job.CreatedByName},
+		{"Dibuat Pada (UTC)", b.generatedAt.Format("2006-01-02 15:04:05")},
+		{"Jumlah Perusahaan", len(b.manifest.Companies)},
+		{"Jumlah Dokumen", b.documentCount},
+		{"File Tidak Ditemukan", len(b.manifest.MissingFiles)},
+		{"Jumlah Laporan Keuangan", len(b.reportRows)},
+	}
+	sheets := []struct {
+		name    string
+		headers []string
+		rows    [][]interface{}
+	}{
+		{"Ringkasan", []string{"Keterangan", "Nilai"}, summary},
+		{"Perusahaan", []string{"Kode", "Nama", "Level", "Kode Induk", "Status", "Aktif", "Pemegang Saham", "Pengurus", "Dokumen", "Laporan Keuangan", "Folder Arsip"}, b.companyRows},
+		{"Dokumen", []string{"Kode Perusahaan", "Folder", "Nama Dokumen", "Nama File", "Tipe", "Ukuran (byte)", "Status", "Diupload", "Path di Arsip", "Catatan"}, b.documentRows},
+		{"Laporan Keuangan", []string{"Kode Perusahaan", "Tahun", "Periode", "Jenis", "Tipe Periode", "Audited", "Versi RKAP", "Status RKAP", "Revenue", "Laba Bersih", "File"}, b.reportRows},
+	}
+	for i, sheet := range sheets {
+		if i == 0 {
+			if err := f.SetSheetName("Sheet1", sheet.name); err != nil {
+				return nil, err
+			}
+		} else if _, err := f.NewSheet(sheet.name); err != nil {
+			return nil, err
+		}
+		for col, header := range sheet.headers {
+			cell, _ := excelize.CoordinatesToCellName(col+1, 1)
+			if err := f.SetCellValue(sheet.name, cell, header); err != nil {
+				return nil, err
+			}
+			if err := f.SetCellStyle(sheet.name, cell, cell, headerStyle); err != nil {
+				return nil, err
+			}
+		}
+		for row, values := range sheet.rows {
+			cell, _ := excelize.CoordinatesToCellName(1, row+2)
+			if err := f.SetSheetRow(sheet.name, cell, &values); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// companyExportDirectories menentukan folder arsip per company (companies/<kode>, unik meski kode disanitasi)
+func companyExportDirectories(companies []domain.CompanyModel) map[string]string {
+	directories := make(map[string]string, len(companies))
+	used := map[string]bool{}
+	for _, company := range companies {
+		name := companyExportSafeName(company.Code)
+		if used[name] {
+			name = name + "_" + company.ID
+		}
+		used[name] = true
+		directories[company.ID] = "companies/" + name
+	}
+	return directories
+}
+
+// companyExportSafeName membuat nama file/folder yang aman di dalam zip
+func companyExportSafeName(name string) string {
+	name = strings.TrimSpace(path.Base(strings.ReplaceAll(name, "\\", "/")))
+	safe := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_', r == ' ':
+			return r
+		}
+		return '_'
+	}, name)
+	if safe == "" || safe == "." || safe == ".." || safe == "/" {
+		return "file"
+	}
+	return safe
+}
+
+func appendIDs[T any](ids []string, items []T, id func(T) string) []string {
+	for _, item := range items {
+		ids = append(ids, id(item))
+	}
+	return ids
+}
@@ -0,0 +1,444 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/audit"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/health"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/kvstore"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/observability"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/storage"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Konstanta default export data company
+const (
+	// Arsip disimpan 7 hari lalu dihapus dari storage (COMPANY_EXPORT_RETENTION_DAYS)
+	DefaultCompanyExportRetentionDays = 7
+	// Link unduh berlaku 60 menit (COMPANY_EXPORT_LINK_TTL_MINUTES)
+	DefaultCompanyExportLinkTTLMinutes = 60
+)
+
+const (
+	// companyExportStorageDir adalah folder arsip export di storage; tidak bisa diakses lewat /files
+	companyExportStorageDir = storage.ExportsBucketPath
+	// companyExportLinkKeyPrefix adalah prefix key kvstore untuk token link unduh (value = job ID)
+	companyExportLinkKeyPrefix = "company_export_link:"
+	companyExportStoreTimeout  = 2 * time.Second
+
+	jobCompanyExportCleanup = "company_export_cleanup"
+)
+
+var (
+	ErrCompanyExportNotReady    = errors.New("arsip export belum siap atau sudah kedaluwarsa")
+	ErrCompanyExportLinkInvalid = errors.New("link unduh tidak valid atau sudah kedaluwarsa")
+)
+
+// CompanyExportActor identitas user yang meminta export (untuk audit trail)
+type CompanyExportActor struct {
+	UserID    string
+	Username  string
+	IPAddress string
+	UserAgent string
+}
+
+// CompanyExportUseCase interface untuk export data company (data portability) secara asynchronous
+type CompanyExportUseCase interface {
+	// CreateExport membuat job export dan membangun arsip di background
+	CreateExport(companyID string, includeDescendants bool, actor CompanyExportActor) (*domain.CompanyExportJobModel, error)
+	GetJob(id string) (*domain.CompanyExportJobModel, error)
+	ListJobs(filter repository.CompanyExportJobFilter) ([]domain.CompanyExportJobModel, int64, error)
+	// CreateDownloadLink menerbitkan token unduh sementara untuk arsip yang sudah selesai
+	CreateDownloadLink(jobID string, actor CompanyExportActor) (string, time.Time, error)
+	// ResolveDownloadLink mengambil job dari token unduh
+	ResolveDownloadLink(token string) (*domain.CompanyExportJobModel, error)
+	ReadArchive(job *domain.CompanyExportJobModel) ([]byte, error)
+	RecoverInterruptedJobs() (int64, error)
+	CleanupExpiredExports() (int, error)
+}
+
+type companyExportUseCase struct {
+	db          *gorm.DB
+	exportRepo  repository.CompanyExportRepository
+	companyRepo repository.CompanyRepository
+	getStorage  func() (storage.StorageManager, error)
+	runAsync    func(fn func()) // Bisa diganti di test agar job berjalan sinkron
+	now         func() time.Time
+}
+
+// NewCompanyExportUseCaseWithDB creates a new company export use case with injected DB
+func NewCompanyExportUseCaseWithDB(db *gorm.DB) CompanyExportUseCase {
+	return &companyExportUseCase{
+		db:          db,
+		exportRepo:  repository.NewCompanyExportRepositoryWithDB(db),
+		companyRepo: repository.NewCompanyRepositoryWithDB(db),
+		getStorage:  storage.GetStorageManager,
+		runAsync:    func(fn func()) { go fn() },
+		now:         time.Now,
+	}
+}
+
+// NewCompanyExportUseCase creates a new company export use case with default DB
+func NewCompanyExportUseCase() CompanyExportUseCase {
+	return NewCompanyExportUseCaseWithDB(database.GetDB())
+}
+
+// GetCompanyExportRetentionDays mengambil masa simpan arsip export dari environment variable
+func GetCompanyExportRetentionDays() int {
+	return positiveEnvInt("COMPANY_EXPORT_RETENTION_DAYS", DefaultCompanyExportRetentionDays)
+}
+
+// GetCompanyExportLinkTTL mengambil masa berlaku link unduh dari environment variable
+func GetCompanyExportLinkTTL() time.Duration {
+	return time.Duration(positiveEnvInt("COMPANY_EXPORT_LINK_TTL_MINUTES", DefaultCompanyExportLinkTTLMinutes)) * time.Minute
+}
+
+func positiveEnvInt(envKey string, defaultValue int) int {
+	value := os.Getenv(envKey)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		logger.GetLogger().Warn("Invalid environment value, using default",
+			zap.String("env_key", envKey),
+			zap.Int("default", defaultValue),
+			zap.Error(err),
+		)
+		return defaultValue
+	}
+	return parsed
+}
+
+func (uc *companyExportUseCase) CreateExport(companyID string, includeDescendants bool, actor CompanyExportActor) (*domain.CompanyExportJobModel, error) {
+	company, err := uc.companyRepo.GetByID(companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &domain.CompanyExportJobModel{
+		ID:                 uuid.GenerateUUID(),
+		CompanyID:          company.ID,
+		CompanyCode:        company.Code,
+		CompanyName:        company.Name,
+		IncludeDescendants: includeDescendants,
+		Status:             domain.CompanyExportStatusProcessing,
+		CreatedBy:          actor.UserID,
+		CreatedByName:      actor.Username,
+		StartedAt:          uc.now(),
+	}
+	if err := uc.exportRepo.CreateJob(job); err != nil {
+		return nil, fmt.Errorf("failed to create export job: %w", err)
+	}
+
+	audit.LogAction(actor.UserID, actor.Username, audit.ActionExportCompanyData, audit.ResourceCompany, company.ID, actor.IPAddress, actor.UserAgent, "success", map[string]interface{}{
+		"export_id":           job.ID,
+		"company_code":        company.Code,
+		"include_descendants": includeDescendants,
+	})
+
+	uc.runAsync(func() {
+		uc.processJob(job, company)
+	})
+
+	return job, nil
+}
+
+// processJob membangun arsip zip lalu menyimpannya ke storage
+func (uc *companyExportUseCase) processJob(job *domain.CompanyExportJobModel, root *domain.CompanyModel) {
+	zapLog := logger.GetLogger()
+	defer func() {
+		if r := recover(); r != nil {
+			zapLog.Error("Company export job panicked", zap.String("job_id", job.ID), zap.Any("panic", r))
+			uc.failJob(job, "Terjadi kesalahan internal saat membuat arsip")
+		}
+	}()
+
+	manager, err := uc.getStorage()
+	if err != nil {
+		uc.failJob(job, "Gagal menginisialisasi storage: "+err.Error())
+		return
+	}
+
+	companies := []domain.CompanyModel{*root}
+	if job.IncludeDescendants {
+		// Anak perusahaan nonaktif/dibubarkan ikut diekspor karena datanya tetap milik grup
+		descendants, err := uc.companyRepo.GetDescendantsWithOptions(root.ID, true)
+		if err != nil {
+			uc.failJob(job, "Gagal mengambil anak perusahaan: "+err.Error())
+			return
+		}
+		companies = append(companies, descendants...)
+	}
+
+	// Arsip ditulis ke file sementara lalu di-stream ke storage, sehingga memory tidak bergantung pada ukuran dokumen
+	tmp, err := os.CreateTemp("", "dms-company-export-*.zip")
+	if err != nil {
+		uc.failJob(job, "Gagal membuat file sementara: "+err.Error())
+		return
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	builder := newCompanyExportBuilder(uc.db, manager, job, uc.now())
+	err = builder.build(tmp, companies, func(fields map[string]interface{}) {
+		if err := uc.exportRepo.UpdateJobProgress(job.ID, fields); err != nil {
+			zapLog.Warn("Failed to update export job progress", zap.String("job_id", job.ID), zap.Error(err))
+		}
+	})
+	if err != nil {
+		zapLog.Error("Failed to build company export", zap.String("job_id", job.ID), zap.Error(err))
+		uc.failJob(job, err.Error())
+		return
+	}
+
+	size, err := tmp.Seek(0, io.SeekEnd)
+	if err == nil {
+		_, err = tmp.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		uc.failJob(job, "Gagal membaca arsip sementara: "+err.Error())
+		return
+	}
+
+	// Folder exports privat di storage (tanpa ACL public-read); arsip hanya bisa diunduh lewat link unduh
+	fileName := job.ID + "-" + randomHex(16) + ".zip"
+	if _, err := manager.UploadStream(companyExportStorageDir, fileName, tmp, "application/zip"); err != nil {
+		zapLog.Error("Failed to store company export", zap.String("job_id", job.ID), zap.Error(err))
+		uc.failJob(job, "Gagal menyimpan arsip ke storage")
+		return
+	}
+
+	completedAt := uc.now()
+	expiresAt := completedAt.AddDate(0, 0, GetCompanyExportRetentionDays())
+	job.Status = domain.CompanyExportStatusCompleted
+	job.TotalSteps = builder.totalSteps
+	job.ProcessedSteps = builder.totalSteps
+	job.CurrentStep = ""
+	job.CompanyCount = len(companies)
+	job.DocumentCount = builder.documentCount
+	job.MissingFileCount = len(builder.manifest.MissingFiles)
+	job.FilePath = companyExportStorageDir + "/" + fileName
+	job.FileSize = size
+	job.CompletedAt = &completedAt
+	job.ExpiresAt = &expiresAt
+	if err := uc.exportRepo.UpdateJob(job); err != nil {
+		zapLog.Error("Failed to finish export job", zap.String("job_id", job.ID), zap.Error(err))
+		return
+	}
+
+	zapLog.Info("Company export completed",
+		zap.String("job_id", job.ID),
+		zap.String("company_code", job.CompanyCode),
+		zap.Int("companies", job.CompanyCount),
+		zap.Int("documents", job.DocumentCount),
+		zap.Int("missing_files", job.MissingFileCount),
+		zap.Int64("size", job.FileSize),
+	)
+}
+
+func (uc *companyExportUseCase) failJob(job *domain.CompanyExportJobModel, message string) {
+	job.Status = domain.CompanyExportStatusFailed
+	job.ErrorMessage = message
+	if err := uc.exportRepo.UpdateJob(job); err != nil {
+		logger.GetLogger().Error("Failed to mark export job as failed", zap.String("job_id", job.ID), zap.Error(err))
+	}
+}
+
+func (uc *companyExportUseCase) GetJob(id string) (*domain.CompanyExportJobModel, error) {
+	return uc.exportRepo.GetJobByID(id)
+}
+
+func (uc *companyExportUseCase) ListJobs(filter repository.CompanyExportJobFilter) ([]domain.CompanyExportJobModel, int64, error) {
+	return uc.exportRepo.ListJobs(filter)
+}
+
+// isDownloadable memastikan arsip sudah selesai dan belum melewati masa simpan
+func (uc *companyExportUseCase) isDownloadable(job *domain.CompanyExportJobModel) bool {
+	return job.Status == domain.CompanyExportStatusCompleted && job.FilePath != "" &&
+		job.ExpiresAt != nil && uc.now().Before(*job.ExpiresAt)
+}
+
+// CreateDownloadLink menyimpan token acak di kvstore dengan TTL. Link tidak pernah berlaku
+// lebih lama dari masa simpan arsip.
+func (uc *companyExportUseCase) CreateDownloadLink(jobID string, actor CompanyExportActor) (string, time.Time, error) {
+	job, err := uc.exportRepo.GetJobByID(jobID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if !uc.isDownloadable(job) {
+		return "", time.Time{}, ErrCompanyExportNotReady
+	}
+
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", time.Time{}, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(tokenBytes)
+
+	now := uc.now()
+	expiresAt := now.Add(GetCompanyExportLinkTTL())
+	if job.ExpiresAt.Before(expiresAt) {
+		expiresAt = *job.ExpiresAt
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), companyExportStoreTimeout)
+	defer cancel()
+	if err := kvstore.GetStore().Set(ctx, companyExportLinkKeyPrefix+token, job.ID, expiresAt.Sub(now)); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to store download link: %w", err)
+	}
+
+	audit.LogAction(actor.UserID, actor.Username, audit.ActionCreateExportLink, audit.ResourceCompany, job.CompanyID, actor.IPAddress, actor.UserAgent, "success", map[string]interface{}{
+		"export_id":  job.ID,
+		"expires_at": expiresAt,
+	})
+	return token, expiresAt, nil
+}
+
+func (uc *companyExportUseCase) ResolveDownloadLink(token string) (*domain.CompanyExportJobModel, error) {
+	if token == "" {
+		return nil, ErrCompanyExportLinkInvalid
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), companyExportStoreTimeout)
+	defer cancel()
+	jobID, err := kvstore.GetStore().Get(ctx, companyExportLinkKeyPrefix+token)
+	if err != nil {
+		if errors.Is(err, kvstore.ErrNotFound) {
+			return nil, ErrCompanyExportLinkInvalid
+		}
+		return nil, err
+	}
+
+	job, err := uc.exportRepo.GetJobByID(jobID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCompanyExportLinkInvalid
+		}
+		return nil, err
+	}
+	if !uc.isDownloadable(job) {
+		return nil, ErrCompanyExportNotReady
+	}
+	return job, nil
+}
+
+func (uc *companyExportUseCase) ReadArchive(job *domain.CompanyExportJobModel) ([]byte, error) {
+	if !uc.isDownloadable(job) {
+		return nil, ErrCompanyExportNotReady
+	}
+	manager, err := uc.getStorage()
+	if err != nil {
+		return nil, err
+	}
+	exists, err := manager.FileExists(path.Dir(job.FilePath), path.Base(job.FilePath))
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		// Misalnya database di-restore dari backup (arsip export tidak ikut di-backup)
+		return nil, ErrCompanyExportNotReady
+	}
+	return storage.ReadFileByURL(manager, job.FilePath)
+}
+
+// RecoverInterruptedJobs dipanggil saat startup untuk job yang terhenti karena server restart.
+// Arsip setengah jadi tidak pernah di-upload sehingga job cukup ditandai failed.
+func (uc *companyExportUseCase) RecoverInterruptedJobs() (int64, error) {
+	return uc.exportRepo.UpdateJobsByStatus(domain.CompanyExportStatusProcessing, map[string]interface{}{
+		"status":        domain.CompanyExportStatusFailed,
+		"error_message": "Proses terhenti karena server restart, silakan buat export ulang",
+	})
+}
+
+// CleanupExpiredExports menghapus arsip yang melewati masa simpan dari storage dan menandai job expired.
+// Link unduh yang masih tersimpan di kvstore otomatis ditolak karena status job bukan completed lagi.
+func (uc *companyExportUseCase) CleanupExpiredExports() (int, error) {
+	jobs, err := uc.exportRepo.ListExpiredJobs(uc.now())
+	if err != nil || len(jobs) == 0 {
+		return 0, err
+	}
+	manager, err := uc.getStorage()
+	if err != nil {
+		return 0, err
+	}
+
+	zapLog := logger.GetLogger()
+	expired := 0
+	for i := range jobs {
+		job := &jobs[i]
+		if job.FilePath != "" {
+			if err := manager.DeleteFile(path.Dir(job.FilePath), path.Base(job.FilePath)); err != nil {
+				exists, existsErr := manager.FileExists(path.Dir(job.FilePath), path.Base(job.FilePath))
+				if existsErr != nil || exists {
+					zapLog.Warn("Failed to delete expired company export", zap.String("job_id", job.ID), zap.Error(err))
+					continue
+				}
+			}
+		}
+		if err := uc.exportRepo.UpdateJobProgress(job.ID, map[string]interface{}{
+			"status":    domain.CompanyExportStatusExpired,
+			"file_path": "",
+		}); err != nil {
+			return expired, err
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// StartCompanyExportCleanup memulai background job yang menghapus arsip export kedaluwarsa setiap jam
+func StartCompanyExportCleanup() {
+	zapLog := logger.GetLogger()
+	exportUC := NewCompanyExportUseCase()
+	health.RegisterHeartbeat(jobCompanyExportCleanup, time.Hour, 10*time.Minute)
+
+	runCleanup := func() {
+		health.Beat(jobCompanyExportCleanup)
+		start := time.Now()
+		expired, err := exportUC.CleanupExpiredExports()
+		observability.ObserveJob(jobCompanyExportCleanup, start, err)
+		observability.AddJobItems(jobCompanyExportCleanup, "archives_expired", expired)
+		if err != nil {
+			zapLog.Error("Company export cleanup failed", zap.Error(err))
+		} else if expired > 0 {
+			zapLog.Info("Company export cleanup completed", zap.Int("archives_expired", expired))
+		}
+	}
+
+	// Jalankan cleanup pertama kali setelah 10 menit, lalu setiap jam
+	// Catatan: ticker dibuat di dalam goroutine agar tidak berhenti saat fungsi ini return
+	go func() {
+		time.Sleep(10 * time.Minute)
+		runCleanup()
+
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			runCleanup()
+		}
+	}()
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return uuid.GenerateUUID()
+	}
+	return hex.EncodeToString(b)
+}
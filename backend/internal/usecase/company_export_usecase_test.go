@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/storage"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"github.com/repoareta/pedeve-dms-app/backend/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompanyExportUseCase_ExportDownloadAndCleanup tests isi arsip, link unduh, dan cleanup arsip kedaluwarsa
+func TestCompanyExportUseCase_ExportDownloadAndCleanup(t *testing.T) {
+	db := helpers.SetupTestDB(t)
+	defer helpers.CleanupTestDB(t, db)
+	require.NoError(t, db.AutoMigrate(&domain.CompanyExportJobModel{}))
+
+	store := storage.NewLocalStorageManager(t.TempDir())
+	uc := NewCompanyExportUseCaseWithDB(db).(*companyExportUseCase)
+	uc.runAsync = func(fn func()) { fn() }
+	uc.getStorage = func() (storage.StorageManager, error) { return store, nil }
+
+	parent := createTestCompanyForNotification(t, db, nil)
+	child := createTestCompanyForNotification(t, db, &parent.ID)
+
+	folder := &domain.DocumentFolderModel{ID: uuid.GenerateUUID(), Name: "Akta", CompanyID: &child.ID}
+	require.NoError(t, db.Create(folder).Error)
+	fileURL, err := store.UploadFile("documents", "akta.pdf", []byte("%PDF-akta"), "application/pdf")
+	require.NoError(t, err)
+	present := &domain.DocumentModel{ID: uuid.GenerateUUID(), FolderID: &folder.ID, Name: "Akta", FileName: "akta.pdf", FilePath: fileURL}
+	missing := &domain.DocumentModel{ID: uuid.GenerateUUID(), FolderID: &folder.ID, Name: "Hilang", FileName: "hilang.pdf", FilePath: "/uploads/documents/hilang.pdf"}
+	require.NoError(t, db.Create(present).Error)
+	require.NoError(t, db.Create(missing).Error)
+
+	job, err := uc.CreateExport(parent.ID, true, CompanyExportActor{UserID: "exporter", Username: "exporter"})
+	require.NoError(t, err)
+
+	done, err := uc.GetJob(job.ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.CompanyExportStatusCompleted, done.Status, done.ErrorMessage)
+	assert.Equal(t, 2, done.CompanyCount)
+	assert.Equal(t, 2, done.DocumentCount)
+	assert.Equal(t, 1, done.MissingFileCount)
+	require.NotNil(t, done.ExpiresAt)
+
+	token, _, err := uc.CreateDownloadLink(done.ID, CompanyExportActor{UserID: "exporter"})
+	require.NoError(t, err)
+	resolved, err := uc.ResolveDownloadLink(token)
+	require.NoError(t, err)
+	content, err := uc.ReadArchive(resolved)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), done.FileSize)
+
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	require.NoError(t, err)
+	files := map[string]*zip.File{}
+	for _, f := range reader.File {
+		files[f.Name] = f
+	}
+	assert.Contains(t, files, "manifest.json")
+	assert.Contains(t, files, "index.xlsx")
+	var copied *zip.File
+	for name, f := range files {
+		if strings.HasSuffix(name, "akta.pdf") && strings.Contains(name, "/files/") {
+			copied = f
+		}
+	}
+	require.NotNil(t, copied)
+	rc, err := copied.Open()
+	require.NoError(t, err)
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	assert.Equal(t, "%PDF-akta", string(data))
+
+	rc, err = files["manifest.json"].Open()
+	require.NoError(t, err)
+	var manifest map[string]interface{}
+	require.NoError(t, json.NewDecoder(rc).Decode(&manifest))
+	rc.Close()
+	assert.Len(t, manifest["missing_files"], 1)
+
+	_, err = uc.ResolveDownloadLink("bogus")
+	assert.ErrorIs(t, err, ErrCompanyExportLinkInvalid)
+
+	// Setelah masa simpan lewat, arsip dihapus dan link tidak bisa dipakai lagi
+	uc.now = func() time.Time { return done.ExpiresAt.Add(time.Minute) }
+	cleaned, err := uc.CleanupExpiredExports()
+	require.NoError(t, err)
+	assert.Equal(t, 1, cleaned)
+	expired, err := uc.GetJob(done.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CompanyExportStatusExpired, expired.Status)
+	_, _, err = uc.CreateDownloadLink(done.ID, CompanyExportActor{UserID: "exporter"})
+	assert.ErrorIs(t, err, ErrCompanyExportNotReady)
+}
@@ -0,0 +1,898 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/audit"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Nama sheet template import company. Pada CSV, nama sheet menjadi kolom pertama setiap baris.
+const (
+	CompanyImportSheetCompanies    = "Perusahaan"
+	CompanyImportSheetShareholders = "Pemegang Saham"
+	CompanyImportSheetBusiness     = "Bidang Usaha"
+	CompanyImportSheetDirectors    = "Pengurus"
+)
+
+var (
+	ErrCompanyImportFormatUnsupported = errors.New("format file import company harus xlsx atau csv")
+	ErrInvalidCompanyImportFile       = errors.New("file import company tidak valid")
+)
+
+// companyImportColumn adalah satu kolom template; header dicocokkan tanpa membedakan huruf besar/kecil
+type companyImportColumn struct {
+	Header   string
+	Required bool
+	Hint     string
+}
+
+type companyImportSheetDef struct {
+	Name     string
+	Required bool
+	Columns  []companyImportColumn
+}
+
+// companyImportSheetDefs berurutan sesuai sheet template. Sheet selain Perusahaan opsional.
+var companyImportSheetDefs = []companyImportSheetDef{
+	{Name: CompanyImportSheetCompanies, Required: true, Columns: []companyImportColumn{
+		{Header: "Kode Perusahaan", Required: true, Hint: "Unik, belum terdaftar"},
+		{Header: "Nama Perusahaan", Required: true},
+		{Header: "Nama Singkat"},
+		{Header: "Kode Induk", Hint: "Kode perusahaan induk yang sudah ada atau baris lain di file ini"},
+		{Header: "Deskripsi"},
+		{Header: "NPWP", Hint: "15 atau 16 digit, boleh dengan titik/strip"},
+		{Header: "NIB", Hint: "13 digit"},
+		{Header: "Status", Hint: "Default: Aktif"},
+		{Header: "Telepon"},
+		{Header: "Fax"},
+		{Header: "Email"},
+		{Header: "Website"},
+		{Header: "Alamat"},
+		{Header: "Alamat Operasional"},
+		{Header: "Modal Dasar", Hint: "Angka"},
+		{Header: "Modal Disetor", Hint: "Angka, tidak melebihi modal dasar"},
+		{Header: "Mata Uang", Hint: "IDR atau USD, default: IDR"},
+	}},
+	{Name: CompanyImportSheetShareholders, Columns: []companyImportColumn{
+		{Header: "Kode Perusahaan", Required: true},
+		{Header: "Jenis", Required: true, Hint: "Badan Hukum, Individu, dll"},
+		{Header: "Nama", Required: true},
+		{Header: "Kode Perusahaan Pemegang", Hint: "Isi jika pemegang saham adalah perusahaan dalam grup"},
+		{Header: "Nomor Identitas", Hint: "KTP/NPWP"},
+		{Header: "Persentase Kepemilikan", Required: true, Hint: "0-100, total per perusahaan maksimal 100"},
+		{Header: "Jumlah Saham", Hint: "Angka"},
+		{Header: "Jumlah Lembar Saham", Hint: "Angka"},
+		{Header: "Nilai per Lembar", Hint: "Angka"},
+		{Header: "Induk Utama", Hint: "Ya/Tidak"},
+	}},
+	{Name: CompanyImportSheetBusiness, Columns: []companyImportColumn{
+		{Header: "Kode Perusahaan", Required: true, Hint: "Satu baris per perusahaan (bidang usaha utama)"},
+		{Header: "Sektor Industri"},
+		{Header: "KBLI"},
+		{Header: "Kegiatan Usaha Utama"},
+		{Header: "Kegiatan Usaha Tambahan"},
+		{Header: "Tanggal Mulai Operasi", Hint: "YYYY-MM-DD atau DD/MM/YYYY"},
+	}},
+	{Name: CompanyImportSheetDirectors, Columns: []companyImportColumn{
+		{Header: "Kode Perusahaan", Required: true},
+		{Header: "Jabatan", Required: true},
+		{Header: "Nama Lengkap", Required: true},
+		{Header: "KTP"},
+		{Header: "NPWP", Hint: "15 atau 16 digit"},
+		{Header: "Tanggal Mulai", Hint: "YYYY-MM-DD atau DD/MM/YYYY"},
+		{Header: "Tanggal Selesai", Hint: "YYYY-MM-DD atau DD/MM/YYYY"},
+		{Header: "Alamat Domisili"},
+	}},
+}
+
+// CompanyImportOptions mengatur dry-run dan batas akses user pada import company
+type CompanyImportOptions struct {
+	DryRun             bool
+	AllowedParentCodes map[string]bool // Kode company yang boleh menjadi induk; nil = semua (superadmin/administrator)
+	DefaultParentCode  string          // Induk untuk baris tanpa Kode Induk (user non-superadmin)
+	UserID             string
+	Username           string
+	IPAddress          string
+	UserAgent          string
+}
+
+// CompanyImportUseCase interface untuk onboarding company secara bulk dari Excel/CSV
+type CompanyImportUseCase interface {
+	GenerateTemplate(format string) ([]byte, error)
+	Import(fileName, format string, data []byte, options CompanyImportOptions) (*domain.CompanyImportResult, error)
+}
+
+type companyImportUseCase struct {
+	db          *gorm.DB
+	companyRepo repository.CompanyRepository
+}
+
+// NewCompanyImportUseCaseWithDB membuat company import use case dengan DB yang di-inject (untuk testing)
+func NewCompanyImportUseCaseWithDB(db *gorm.DB) CompanyImportUseCase {
+	return &companyImportUseCase{
+		db:          db,
+		companyRepo: repository.NewCompanyRepositoryWithDB(db),
+	}
+}
+
+// NewCompanyImportUseCase membuat company import use case dengan default DB
+func NewCompanyImportUseCase() CompanyImportUseCase {
+	return NewCompanyImportUseCaseWithDB(database.GetDB())
+}
+
+// companyImportFormat menentukan format dari parameter atau ekstensi file
+func companyImportFormat(fileName, format string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		lower := strings.ToLower(fileName)
+		switch {
+		case strings.HasSuffix(lower, ".xlsx"):
+			format = "xlsx"
+		case strings.HasSuffix(lower, ".csv"):
+			format = "csv"
+		}
+	}
+	if format != "xlsx" && format != "csv" {
+		return "", ErrCompanyImportFormatUnsupported
+	}
+	return format, nil
+}
+
+// GenerateTemplate membuat template kosong (xlsx: satu sheet per bagian + sheet Petunjuk, csv: baris header per sheet)
+func (uc *companyImportUseCase) GenerateTemplate(format string) ([]byte, error) {
+	format, err := companyImportFormat("", format)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == "csv" {
+		var buf bytes.Buffer
+		writer := csv.NewWriter(&buf)
+		for _, def := range companyImportSheetDefs {
+			record := []string{def.Name}
+			for _, column := range def.Columns {
+				record = append(record, column.Header)
+			}
+			if err := writer.Write(record); err != nil {
+				return nil, err
+			}
+		}
+		writer.Flush()
+		return buf.Bytes(), writer.Error()
+	}
+
+	f := excelize.NewFile()
+	defer func() {
+		if err := f.Close(); err != nil {
+			logger.GetLogger().Warn("Failed to close Excel file", zap.Error(err))
+		}
+	}()
+
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"#D9E1F2"}, Pattern: 1},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, def := range companyImportSheetDefs {
+		if i == 0 {
+			if err := f.SetSheetName(f.GetSheetName(0), def.Name); err != nil {
+				return nil, err
+			}
+		} else if _, err := f.NewSheet(def.Name); err != nil {
+			return nil, err
+		}
+		for col, column := range def.Columns {
+			cell, _ := excelize.CoordinatesToCellName(col+1, 1)
+			if err := f.SetCellValue(def.Name, cell, column.Header); err != nil {
+				return nil, err
+			}
+		}
+		lastCell, _ := excelize.CoordinatesToCellName(len(def.Columns), 1)
+		if err := f.SetCellStyle(def.Name, "A1", lastCell, headerStyle); err != nil {
+			return nil, err
+		}
+		lastCol, _ := excelize.ColumnNumberToName(len(def.Columns))
+		if err := f.SetColWidth(def.Name, "A", lastCol, 22); err != nil {
+			return nil, err
+		}
+	}
+
+	// Sheet petunjuk: daftar kolom, wajib/opsional, dan format
+	const guide = "Petunjuk"
+	if _, err := f.NewSheet(guide); err != nil {
+		return nil, err
+	}
+	guideRows := [][]interface{}{{"Sheet", "Kolom", "Wajib", "Keterangan"}}
+	for _, def := range companyImportSheetDefs {
+		for _, column := range def.Columns {
+			required := "Tidak"
+			if column.Required {
+				required = "Ya"
+			}
+			guideRows = append(guideRows, []interface{}{def.Name, column.Header, required, column.Hint})
+		}
+	}
+	for i, row := range guideRows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := f.SetSheetRow(guide, cell, &row); err != nil {
+			return nil, err
+		}
+	}
+	if err := f.SetCellStyle(guide, "A1", "D1", headerStyle); err != nil {
+		return nil, err
+	}
+	if err := f.SetColWidth(guide, "A", "D", 28); err != nil {
+		return nil, err
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// companyImportSourceRow adalah satu baris data (tanpa kolom nama sheet pada CSV)
+type companyImportSourceRow struct {
+	Row   int
+	Cells []string
+}
+
+type companyImportSheet struct {
+	columns map[string]int // Header (lowercase) -> index kolom
+	rows    []companyImportSourceRow
+}
+
+func (s *companyImportSheet) value(row companyImportSourceRow, header string) string {
+	if s == nil {
+		return ""
+	}
+	index, ok := s.columns[strings.ToLower(header)]
+	if !ok || index >= len(row.Cells) {
+		return ""
+	}
+	return strings.TrimSpace(row.Cells[index])
+}
+
+func newCompanyImportSheet(header []string) *companyImportSheet {
+	sheet := &companyImportSheet{columns: make(map[string]int, len(header))}
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if _, exists := sheet.columns[key]; key != "" && !exists {
+			sheet.columns[key] = i
+		}
+	}
+	return sheet
+}
+
+func findCompanyImportSheetDef(name string) *companyImportSheetDef {
+	for i := range companyImportSheetDefs {
+		if strings.EqualFold(companyImportSheetDefs[i].Name, strings.TrimSpace(name)) {
+			return &companyImportSheetDefs[i]
+		}
+	}
+	return nil
+}
+
+// decodeCompanyImportXLSX membaca sheet template berdasarkan nama sheet (nilai mentah, tanggal berupa serial Excel)
+func decodeCompanyImportXLSX(data []byte) (map[string]*companyImportSheet, error) {
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: file Excel tidak valid atau corrupt", ErrInvalidCompanyImportFile)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			logger.GetLogger().Warn("Failed to close Excel file", zap.Error(err))
+		}
+	}()
+
+	sheets := make(map[string]*companyImportSheet)
+	for _, name := range f.GetSheetList() {
+		def := findCompanyImportSheetDef(name)
+		if def == nil {
+			continue // Sheet Petunjuk atau sheet tambahan user diabaikan
+		}
+		rows, err := f.GetRows(name, excelize.Options{RawCellValue: true})
+		if err != nil {
+			return nil, fmt.Errorf("%w: gagal membaca sheet %s", ErrInvalidCompanyImportFile, name)
+		}
+		if len(rows) == 0 {
+			continue
+		}
+		sheet := newCompanyImportSheet(rows[0])
+		for i := 1; i < len(rows); i++ {
+			if isEmptyFinancialRow(rows[i]) {
+				continue
+			}
+			sheet.rows = append(sheet.rows, companyImportSourceRow{Row: i + 1, Cells: rows[i]})
+		}
+		sheets[def.Name] = sheet
+	}
+	return sheets, nil
+}
+
+// decodeCompanyImportCSV membaca CSV gabungan: kolom pertama nama sheet, baris pertama tiap sheet adalah header
+func decodeCompanyImportCSV(data []byte) (map[string]*companyImportSheet, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+
+	sheets := make(map[string]*companyImportSheet)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: CSV tidak valid: %v", ErrInvalidCompanyImportFile, err)
+		}
+		if isEmptyFinancialRow(record) {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		def := findCompanyImportSheetDef(record[0])
+		if def == nil {
+			return nil, fmt.Errorf("%w: baris %d: sheet '%s' tidak dikenal, kolom pertama harus salah satu dari %s",
+				ErrInvalidCompanyImportFile, line, record[0], strings.Join(companyImportSheetNames(), ", "))
+		}
+		sheet, ok := sheets[def.Name]
+		if !ok {
+			sheets[def.Name] = newCompanyImportSheet(record[1:])
+			continue
+		}
+		sheet.rows = append(sheet.rows, companyImportSourceRow{Row: line, Cells: record[1:]})
+	}
+	return sheets, nil
+}
+
+func companyImportSheetNames() []string {
+	names := make([]string, 0, len(companyImportSheetDefs))
+	for _, def := range companyImportSheetDefs {
+		names = append(names, def.Name)
+	}
+	return names
+}
+
+// checkCompanyImportHeaders memastikan sheet wajib dan kolom wajib ada
+func checkCompanyImportHeaders(sheets map[string]*companyImportSheet) error {
+	var problems []string
+	for _, def := range companyImportSheetDefs {
+		sheet, ok := sheets[def.Name]
+		if !ok {
+			if def.Required {
+				problems = append(problems, fmt.Sprintf("sheet %s tidak ditemukan", def.Name))
+			}
+			continue
+		}
+		for _, column := range def.Columns {
+			if _, ok := sheet.columns[strings.ToLower(column.Header)]; column.Required && !ok {
+				problems = append(problems, fmt.Sprintf("sheet %s: kolom '%s' tidak ditemukan", def.Name, column.Header))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidCompanyImportFile, strings.Join(problems, "; "))
+	}
+	if sheet := sheets[CompanyImportSheetCompanies]; len(sheet.rows) == 0 {
+		return fmt.Errorf("%w: sheet %s tidak memiliki data", ErrInvalidCompanyImportFile, CompanyImportSheetCompanies)
+	}
+	return nil
+}
+
+// companyImportEntry adalah satu company dari file beserta request CreateCompanyFull-nya
+type companyImportEntry struct {
+	result           domain.CompanyImportCompany
+	request          domain.CompanyCreateRequest
+	parentCode       string
+	parentInFile     bool
+	shareholderCodes []string // Kode perusahaan pemegang saham per index Shareholders (kosong = eksternal)
+	shareholderRows  []int
+	directorRows     []int
+	businessRow      int
+	dependsOn        []string // Kode company di file yang harus dibuat lebih dulu
+}
+
+func (e *companyImportEntry) addIssue(sheet string, row int, column, message string) {
+	e.result.Errors = append(e.result.Errors, domain.CompanyImportIssue{Sheet: sheet, Row: row, Column: column, Message: message})
+}
+
+// companyValidationColumns memetakan field validateCompanyProfile ke kolom template
+var companyValidationColumns = map[string]string{
+	"name":                           "Nama Perusahaan",
+	"code":                           "Kode Perusahaan",
+	"npwp":                           "NPWP",
+	"nib":                            "NIB",
+	"currency":                       "Mata Uang",
+	"paid_up_capital":                "Modal Disetor",
+	"shareholders":                   "Persentase Kepemilikan",
+	"shareholders.name":              "Nama",
+	"shareholders.ownership_percent": "Persentase Kepemilikan",
+	"directors.position":             "Jabatan",
+	"directors.full_name":            "Nama Lengkap",
+	"directors.npwp":                 "NPWP",
+	"directors.end_date":             "Tanggal Selesai",
+}
+
+// addValidationIssue menempatkan hasil validateCompanyProfile ke sheet dan baris asalnya
+func (e *companyImportEntry) addValidationIssue(issue companyValidationIssue) {
+	sheet, row, key := CompanyImportSheetCompanies, e.result.Row, issue.Field
+	if open := strings.Index(issue.Field, "["); open > 0 {
+		closing := strings.Index(issue.Field, "]")
+		index, _ := strconv.Atoi(issue.Field[open+1 : closing])
+		key = issue.Field[:open] + issue.Field[closing+1:]
+		switch issue.Field[:open] {
+		case "shareholders":
+			sheet, row = CompanyImportSheetShareholders, e.shareholderRows[index]
+		case "directors":
+			sheet, row = CompanyImportSheetDirectors, e.directorRows[index]
+		}
+	} else if issue.Field == "shareholders" && len(e.shareholderRows) > 0 {
+		sheet, row = CompanyImportSheetShareholders, e.shareholderRows[len(e.shareholderRows)-1]
+	}
+	e.addIssue(sheet, row, companyValidationColumns[key], issue.Message)
+}
+
+// parseCompanyImportDate menerima YYYY-MM-DD, DD/MM/YYYY, atau serial tanggal Excel
+func parseCompanyImportDate(value string) (*domain.DateOnly, error) {
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{"2006-01-02", "02/01/2006", "2/1/2006"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &domain.DateOnly{Time: t}, nil
+		}
+	}
+	if serial, err := strconv.ParseFloat(value, 64); err == nil && serial > 0 {
+		if t, err := excelize.ExcelDateToTime(serial, false); err == nil {
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+			return &domain.DateOnly{Time: t}, nil
+		}
+	}
+	return nil, fmt.Errorf("format tanggal harus YYYY-MM-DD atau DD/MM/YYYY, nilai: '%s'", value)
+}
+
+func parseCompanyImportBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "", "tidak", "t", "no", "n", "false", "0":
+		return false, nil
+	case "ya", "y", "yes", "true", "1":
+		return true, nil
+	}
+	return false, fmt.Errorf("harus Ya atau Tidak, nilai: '%s'", value)
+}
+
+func parseCompanyImportPercent(value string) (float64, error) {
+	parsed, err := strconv.ParseFloat(strings.TrimSuffix(strings.ReplaceAll(value, ",", "."), "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("harus berupa angka, nilai: '%s'", value)
+	}
+	return parsed, nil
+}
+
+// parseCompanyImportOptionalInt mengisi target jika nilai tidak kosong; error dicatat ke entry
+func parseCompanyImportOptionalInt(entry *companyImportEntry, sheet string, row int, column, value string) *int64 {
+	if value == "" {
+		return nil
+	}
+	parsed, err := parseImportInt64(value, false)
+	if err != nil {
+		entry.addIssue(sheet, row, column, err.Error())
+		return nil
+	}
+	return &parsed
+}
+
+// parseCompanyImportEntries membangun request CreateCompanyFull per company dari semua sheet
+func parseCompanyImportEntries(sheets map[string]*companyImportSheet) ([]*companyImportEntry, []domain.CompanyImportIssue) {
+	var entries []*companyImportEntry
+	var fileIssues []domain.CompanyImportIssue
+	byCode := make(map[string]*companyImportEntry)
+
+	companies := sheets[CompanyImportSheetCompanies]
+	for _, row := range companies.rows {
+		get := func(header string) string { return companies.value(row, header) }
+		entry := &companyImportEntry{
+			result: domain.CompanyImportCompany{
+				Row:        row.Row,
+				Code:       get("Kode Perusahaan"),
+				Name:       get("Nama Perusahaan"),
+				ParentCode: get("Kode Induk"),
+			},
+			parentCode: get("Kode Induk"),
+		}
+		status := get("Status")
+		if status == "" {
+			status = "Aktif"
+		}
+		entry.request = domain.CompanyCreateRequest{
+			Name:               entry.result.Name,
+			ShortName:          get("Nama Singkat"),
+			Code:               entry.result.Code,
+			Description:        get("Deskripsi"),
+			NPWP:               get("NPWP"),
+			NIB:                get("NIB"),
+			Status:             status,
+			Phone:              get("Telepon"),
+			Fax:                get("Fax"),
+			Email:              get("Email"),
+			Website:            get("Website"),
+			Address:            get("Alamat"),
+			OperationalAddress: get("Alamat Operasional"),
+			Currency:           strings.ToUpper(get("Mata Uang")),
+		}
+		entry.request.AuthorizedCapital = parseCompanyImportOptionalInt(entry, CompanyImportSheetCompanies, row.Row, "Modal Dasar", get("Modal Dasar"))
+		entry.request.PaidUpCapital = parseCompanyImportOptionalInt(entry, CompanyImportSheetCompanies, row.Row, "Modal Disetor", get("Modal Disetor"))
+
+		if entry.result.Code != "" {
+			if first, exists := byCode[entry.result.Code]; exists {
+				entry.addIssue(CompanyImportSheetCompanies, row.Row, "Kode Perusahaan",
+					fmt.Sprintf("kode perusahaan '%s' duplikat dengan baris %d", entry.result.Code, first.result.Row))
+			} else {
+				byCode[entry.result.Code] = entry
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	// Baris detail harus merujuk ke kode perusahaan di sheet Perusahaan
+	owner := func(sheetName string, sheet *companyImportSheet, row companyImportSourceRow) *companyImportEntry {
+		code := sheet.value(row, "Kode Perusahaan")
+		entry := byCode[code]
+		if entry == nil {
+			fileIssues = append(fileIssues, domain.CompanyImportIssue{
+				Sheet: sheetName, Row: row.Row, Column: "Kode Perusahaan",
+				Message: fmt.Sprintf("kode perusahaan '%s' tidak ada di sheet %s", code, CompanyImportSheetCompanies),
+			})
+		}
+		return entry
+	}
+
+	if sheet := sheets[CompanyImportSheetShareholders]; sheet != nil {
+		for _, row := range sheet.rows {
+			entry := owner(CompanyImportSheetShareholders, sheet, row)
+			if entry == nil {
+				continue
+			}
+			get := func(header string) string { return sheet.value(row, header) }
+			shareholder := domain.ShareholderRequest{
+				Type:           get("Jenis"),
+				Name:           get("Nama"),
+				IdentityNumber: get("Nomor Identitas"),
+			}
+			if shareholder.Type == "" {
+				entry.addIssue(CompanyImportSheetShareholders, row.Row, "Jenis", "jenis pemegang saham wajib diisi")
+			}
+			percent, err := parseCompanyImportPercent(get("Persentase Kepemilikan"))
+			if err != nil {
+				entry.addIssue(CompanyImportSheetShareholders, row.Row, "Persentase Kepemilikan", err.Error())
+			}
+			shareholder.OwnershipPercent = percent
+			if count := parseCompanyImportOptionalInt(entry, CompanyImportSheetShareholders, row.Row, "Jumlah Saham", get("Jumlah Saham")); count != nil {
+				shareholder.ShareCount = *count
+			}
+			shareholder.ShareSheetCount = parseCompanyImportOptionalInt(entry, CompanyImportSheetShareholders, row.Row, "Jumlah Lembar Saham", get("Jumlah Lembar Saham"))
+			shareholder.ShareValuePerSheet = parseCompanyImportOptionalInt(entry, CompanyImportSheetShareholders, row.Row, "Nilai per Lembar", get("Nilai per Lembar"))
+			isMain, err := parseCompanyImportBool(get("Induk Utama"))
+			if err != nil {
+				entry.addIssue(CompanyImportSheetShareholders, row.Row, "Induk Utama", err.Error())
+			}
+			shareholder.IsMainParent = isMain
+
+			entry.request.Shareholders = append(entry.request.Shareholders, shareholder)
+			entry.shareholderCodes = append(entry.shareholderCodes, get("Kode Perusahaan Pemegang"))
+			entry.shareholderRows = append(entry.shareholderRows, row.Row)
+		}
+	}
+
+	if sheet := sheets[CompanyImportSheetBusiness]; sheet != nil {
+		for _, row := range sheet.rows {
+			entry := owner(CompanyImportSheetBusiness, sheet, row)
+			if entry == nil {
+				continue
+			}
+			if entry.businessRow != 0 {
+				entry.addIssue(CompanyImportSheetBusiness, row.Row, "Kode Perusahaan",
+					fmt.Sprintf("bidang usaha utama sudah diisi di baris %d, hanya satu per perusahaan", entry.businessRow))
+				continue
+			}
+			get := func(header string) string { return sheet.value(row, header) }
+			startDate, err := parseCompanyImportDate(get("Tanggal Mulai Operasi"))
+			if err != nil {
+				entry.addIssue(CompanyImportSheetBusiness, row.Row, "Tanggal Mulai Operasi", err.Error())
+			}
+			entry.request.MainBusiness = &domain.BusinessFieldRequest{
+				IndustrySector:       get("Sektor Industri"),
+				KBLI:                 get("KBLI"),
+				MainBusinessActivity: get("Kegiatan Usaha Utama"),
+				AdditionalActivities: get("Kegiatan Usaha Tambahan"),
+				StartOperationDate:   startDate,
+			}
+			entry.businessRow = row.Row
+		}
+	}
+
+	if sheet := sheets[CompanyImportSheetDirectors]; sheet != nil {
+		for _, row := range sheet.rows {
+			entry := owner(CompanyImportSheetDirectors, sheet, row)
+			if entry == nil {
+				continue
+			}
+			get := func(header string) string { return sheet.value(row, header) }
+			startDate, err := parseCompanyImportDate(get("Tanggal Mulai"))
+			if err != nil {
+				entry.addIssue(CompanyImportSheetDirectors, row.Row, "Tanggal Mulai", err.Error())
+			}
+			endDate, err := parseCompanyImportDate(get("Tanggal Selesai"))
+			if err != nil {
+				entry.addIssue(CompanyImportSheetDirectors, row.Row, "Tanggal Selesai", err.Error())
+			}
+			entry.request.Directors = append(entry.request.Directors, domain.DirectorRequest{
+				Position:        get("Jabatan"),
+				FullName:        get("Nama Lengkap"),
+				KTP:             get("KTP"),
+				NPWP:            get("NPWP"),
+				StartDate:       startDate,
+				EndDate:         endDate,
+				DomicileAddress: get("Alamat Domisili"),
+			})
+			entry.directorRows = append(entry.directorRows, row.Row)
+		}
+	}
+
+	return entries, fileIssues
+}
+
+// Import memvalidasi file dan (jika bukan dry-run dan tidak ada error) membuat semua company dalam satu transaksi
+func (uc *companyImportUseCase) Import(fileName, format string, data []byte, options CompanyImportOptions) (*domain.CompanyImportResult, error) {
+	zapLog := logger.GetLogger()
+
+	format, err := companyImportFormat(fileName, format)
+	if err != nil {
+		return nil, err
+	}
+	var sheets map[string]*companyImportSheet
+	if format == "csv" {
+		sheets, err = decodeCompanyImportCSV(data)
+	} else {
+		sheets, err = decodeCompanyImportXLSX(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCompanyImportHeaders(sheets); err != nil {
+		return nil, err
+	}
+
+	existingCompanies, err := uc.companyRepo.GetAll(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get companies: %w", err)
+	}
+	existing := make(map[string]*domain.CompanyModel, len(existingCompanies))
+	for i := range existingCompanies {
+		existing[existingCompanies[i].Code] = &existingCompanies[i]
+	}
+
+	entries, fileIssues := parseCompanyImportEntries(sheets)
+	inFile := make(map[string]*companyImportEntry, len(entries))
+	for _, entry := range entries {
+		if _, ok := inFile[entry.result.Code]; !ok && entry.result.Code != "" {
+			inFile[entry.result.Code] = entry
+		}
+	}
+
+	for _, entry := range entries {
+		uc.validateImportEntry(entry, existing, inFile, options)
+	}
+	ordered := orderCompanyImportEntries(entries, inFile)
+
+	result := &domain.CompanyImportResult{
+		DryRun:         options.DryRun,
+		TotalCompanies: len(entries),
+		Companies:      make([]domain.CompanyImportCompany, 0, len(ordered)),
+		Errors:         fileIssues,
+	}
+	result.ErrorCount = len(fileIssues)
+	for _, entry := range ordered {
+		if len(entry.result.Errors) == 0 {
+			result.ValidCompanies++
+		}
+		result.ErrorCount += len(entry.result.Errors)
+	}
+
+	if !options.DryRun && result.ErrorCount == 0 {
+		if err := uc.commit(ordered, inFile, existing); err != nil {
+			zapLog.Error("Failed to commit company import", zap.Error(err))
+			return nil, err
+		}
+		result.Committed = true
+
+		for _, entry := range ordered {
+			audit.LogAction(options.UserID, options.Username, audit.ActionCreateCompany, audit.ResourceCompany, entry.result.ID, options.IPAddress, options.UserAgent, audit.StatusSuccess, map[string]interface{}{
+				"source": "import",
+				"code":   entry.result.Code,
+			})
+		}
+	}
+
+	for _, entry := range ordered {
+		result.Companies = append(result.Companies, entry.result)
+	}
+
+	audit.LogAction(options.UserID, options.Username, audit.ActionImportCompany, audit.ResourceCompany, "", options.IPAddress, options.UserAgent, audit.StatusSuccess, map[string]interface{}{
+		"file_name":       fileName,
+		"format":          format,
+		"dry_run":         options.DryRun,
+		"committed":       result.Committed,
+		"total_companies": result.TotalCompanies,
+		"error_count":     result.ErrorCount,
+	})
+
+	return result, nil
+}
+
+// validateImportEntry menjalankan validasi yang sama dengan CreateCompanyFull ditambah resolusi kode induk dan pemegang saham
+func (uc *companyImportUseCase) validateImportEntry(entry *companyImportEntry, existing map[string]*domain.CompanyModel, inFile map[string]*companyImportEntry, options CompanyImportOptions) {
+	for _, issue := range validateCompanyProfile(&entry.request) {
+		entry.addValidationIssue(issue)
+	}
+	code := entry.result.Code
+	if code != "" && existing[code] != nil {
+		entry.addIssue(CompanyImportSheetCompanies, entry.result.Row, "Kode Perusahaan", fmt.Sprintf("kode perusahaan '%s' sudah terdaftar", code))
+	}
+
+	// Baris tanpa Kode Induk milik user non-superadmin ditempatkan di bawah company user (sama dengan CreateCompanyFull)
+	if entry.parentCode == "" && options.DefaultParentCode != "" {
+		entry.parentCode = options.DefaultParentCode
+		entry.result.ParentCode = options.DefaultParentCode
+	}
+
+	total := 0.0
+	for _, sh := range entry.request.Shareholders {
+		total += sh.OwnershipPercent
+	}
+	entry.result.OwnershipTotal = math.Round(total*10000) / 10000
+	entry.result.Shareholders = len(entry.request.Shareholders)
+	entry.result.Directors = len(entry.request.Directors)
+	entry.result.HasMainBusiness = entry.request.MainBusiness != nil
+
+	switch {
+	case entry.parentCode == "":
+		entry.result.Level = 1
+	case entry.parentCode == code:
+		entry.addIssue(CompanyImportSheetCompanies, entry.result.Row, "Kode Induk", "kode induk tidak boleh sama dengan kode perusahaan")
+	case inFile[entry.parentCode] != nil:
+		entry.parentInFile = true
+		entry.result.ParentSource = "file"
+		entry.dependsOn = append(entry.dependsOn, entry.parentCode)
+	case existing[entry.parentCode] != nil:
+		entry.result.ParentSource = "existing"
+		entry.result.Level = existing[entry.parentCode].Level + 1
+		if options.AllowedParentCodes != nil && !options.AllowedParentCodes[entry.parentCode] {
+			entry.addIssue(CompanyImportSheetCompanies, entry.result.Row, "Kode Induk",
+				fmt.Sprintf("anda hanya bisa membuat perusahaan di bawah perusahaan anda atau turunannya, kode induk: '%s'", entry.parentCode))
+		}
+	default:
+		entry.addIssue(CompanyImportSheetCompanies, entry.result.Row, "Kode Induk",
+			fmt.Sprintf("perusahaan induk '%s' tidak ditemukan di database maupun file", entry.parentCode))
+	}
+
+	for i, shareholderCode := range entry.shareholderCodes {
+		switch {
+		case shareholderCode == "":
+		case shareholderCode == code:
+			entry.addIssue(CompanyImportSheetShareholders, entry.shareholderRows[i], "Kode Perusahaan Pemegang", "perusahaan tidak bisa menjadi pemegang saham dirinya sendiri")
+		case inFile[shareholderCode] != nil:
+			entry.dependsOn = append(entry.dependsOn, shareholderCode)
+		case existing[shareholderCode] == nil:
+			entry.addIssue(CompanyImportSheetShareholders, entry.shareholderRows[i], "Kode Perusahaan Pemegang",
+				fmt.Sprintf("perusahaan pemegang saham '%s' tidak ditemukan di database maupun file", shareholderCode))
+		}
+	}
+}
+
+// orderCompanyImportEntries mengurutkan company agar induk dan pemegang saham di file dibuat lebih dulu
+// (urutan file dipertahankan), menghitung level, dan menandai siklus serta dependensi yang tidak valid.
+func orderCompanyImportEntries(entries []*companyImportEntry, inFile map[string]*companyImportEntry) []*companyImportEntry {
+	ordered := make([]*companyImportEntry, 0, len(entries))
+	placed := make(map[*companyImportEntry]bool, len(entries))
+
+	for progress := true; progress; {
+		progress = false
+		for _, entry := range entries {
+			if placed[entry] {
+				continue
+			}
+			ready := true
+			for _, dependency := range entry.dependsOn {
+				if !placed[inFile[dependency]] {
+					ready = false
+					break
+				}
+			}
+			if !ready {
+				continue
+			}
+
+			for _, dependency := range entry.dependsOn {
+				if dep := inFile[dependency]; len(dep.result.Errors) > 0 {
+					entry.addIssue(CompanyImportSheetCompanies, entry.result.Row, "",
+						fmt.Sprintf("perusahaan '%s' di file tidak valid, perbaiki baris %d terlebih dahulu", dependency, dep.result.Row))
+				}
+			}
+			if entry.parentInFile {
+				entry.result.Level = inFile[entry.parentCode].result.Level + 1
+			}
+			ordered = append(ordered, entry)
+			placed[entry] = true
+			progress = true
+		}
+	}
+
+	// Sisa entry saling bergantung (siklus Kode Induk / Kode Perusahaan Pemegang)
+	for _, entry := range entries {
+		if !placed[entry] {
+			entry.addIssue(CompanyImportSheetCompanies, entry.result.Row, "Kode Induk",
+				fmt.Sprintf("terdapat siklus kode induk/pemegang saham yang melibatkan '%s'", entry.result.Code))
+			ordered = append(ordered, entry)
+		}
+	}
+	return ordered
+}
+
+// commit membuat semua company lewat CreateCompanyFull dalam satu transaksi; satu gagal = semua dibatalkan
+func (uc *companyImportUseCase) commit(ordered []*companyImportEntry, inFile map[string]*companyImportEntry, existing map[string]*domain.CompanyModel) error {
+	return uc.db.Transaction(func(tx *gorm.DB) error {
+		companyUC := NewCompanyUseCaseWithDB(tx)
+		companyID := func(code string) *string {
+			if entry := inFile[code]; entry != nil {
+				return &entry.result.ID
+			}
+			if company := existing[code]; company != nil {
+				return &company.ID
+			}
+			return nil
+		}
+
+		for _, entry := range ordered {
+			request := entry.request
+			if entry.parentCode != "" {
+				request.ParentID = companyID(entry.parentCode)
+			}
+			request.Shareholders = append([]domain.ShareholderRequest(nil), entry.request.Shareholders...)
+			for i, shareholderCode := range entry.shareholderCodes {
+				if shareholderCode == "" {
+					continue
+				}
+				request.Shareholders[i].ShareholderCompanyID = companyID(shareholderCode)
+				if request.Shareholders[i].IsMainParent {
+					request.MainParentCompany = request.Shareholders[i].ShareholderCompanyID
+				}
+			}
+
+			company, err := companyUC.CreateCompanyFull(&request)
+			if err != nil {
+				return fmt.Errorf("baris %d (%s): %w", entry.result.Row, entry.result.Code, err)
+			}
+			entry.result.ID = company.ID
+			entry.result.Level = company.Level
+		}
+		return nil
+	})
+}
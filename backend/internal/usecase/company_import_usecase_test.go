@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func buildCompanyImportWorkbook(t *testing.T, sheets map[string][][]interface{}) []byte {
+	f := excelize.NewFile()
+	defer f.Close()
+	for name, rows := range sheets {
+		_, err := f.NewSheet(name)
+		require.NoError(t, err)
+		for i, row := range rows {
+			cell, _ := excelize.CoordinatesToCellName(1, i+1)
+			require.NoError(t, f.SetSheetRow(name, cell, &row))
+		}
+	}
+	buf, err := f.WriteToBuffer()
+	require.NoError(t, err)
+	return buf.Bytes()
+}
+
+func TestCompanyImport(t *testing.T) {
+	db := helpers.SetupTestDB(t)
+	defer helpers.CleanupTestDB(t, db)
+
+	holding := createTestCompanyForNotification(t, db, nil)
+	uc := NewCompanyImportUseCaseWithDB(db)
+
+	companyHeader := []interface{}{"Kode Perusahaan", "Nama Perusahaan", "Kode Induk", "NPWP", "NIB"}
+	shareholderHeader := []interface{}{"Kode Perusahaan", "Jenis", "Nama", "Kode Perusahaan Pemegang", "Persentase Kepemilikan", "Induk Utama"}
+	directorHeader := []interface{}{"Kode Perusahaan", "Jabatan", "Nama Lengkap", "Tanggal Mulai", "Tanggal Selesai"}
+
+	t.Run("Template", func(t *testing.T) {
+		data, err := uc.GenerateTemplate("xlsx")
+		require.NoError(t, err)
+		f, err := excelize.OpenReader(strings.NewReader(string(data)))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Perusahaan", "Pemegang Saham", "Bidang Usaha", "Pengurus", "Petunjuk"}, f.GetSheetList())
+
+		csvData, err := uc.GenerateTemplate("csv")
+		require.NoError(t, err)
+		assert.Equal(t, 4, strings.Count(string(csvData), "\n"))
+	})
+
+	t.Run("Validation errors", func(t *testing.T) {
+		data := buildCompanyImportWorkbook(t, map[string][][]interface{}{
+			"Perusahaan": {
+				companyHeader,
+				{"SUB-A", "PT Sub A", holding.Code, "12.345", ""},
+				{"SUB-A", "PT Sub A Dup", holding.Code, "", ""},
+				{"SUB-B", "PT Sub B", "UNKNOWN", "", "123"},
+				{"SUB-C", "PT Sub C", "SUB-D", "", ""},
+				{"SUB-D", "PT Sub D", "SUB-C", "", ""},
+				{holding.Code, "Existing", "", "", ""},
+			},
+			"Pemegang Saham": {
+				shareholderHeader,
+				{"SUB-A", "Badan Hukum", "Holding", holding.Code, 60, "Ya"},
+				{"SUB-A", "Individu", "Budi", "", 50, ""},
+				{"NOPE", "Individu", "X", "", 10, ""},
+			},
+		})
+		result, err := uc.Import("companies.xlsx", "", data, CompanyImportOptions{DryRun: true})
+		require.NoError(t, err)
+		assert.False(t, result.Committed)
+		assert.Equal(t, 6, result.TotalCompanies)
+		assert.Equal(t, 0, result.ValidCompanies)
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, "Pemegang Saham", result.Errors[0].Sheet)
+
+		messages := map[string]string{}
+		for _, company := range result.Companies {
+			var parts []string
+			for _, issue := range company.Errors {
+				parts = append(parts, issue.Column+": "+issue.Message)
+			}
+			messages[company.Name] = strings.Join(parts, " | ")
+		}
+		assert.Contains(t, messages["PT Sub A"], "NPWP")
+		assert.Contains(t, messages["PT Sub A"], "harus 100")
+		assert.Contains(t, messages["PT Sub A Dup"], "duplikat")
+		assert.Contains(t, messages["PT Sub B"], "tidak ditemukan")
+		assert.Contains(t, messages["PT Sub B"], "NIB")
+		assert.Contains(t, messages["PT Sub C"], "siklus")
+		assert.Contains(t, messages["Existing"], "sudah terdaftar")
+
+		// Commit dengan error tidak menyimpan apapun
+		result, err = uc.Import("companies.xlsx", "", data, CompanyImportOptions{})
+		require.NoError(t, err)
+		assert.False(t, result.Committed)
+		var count int64
+		db.Model(&domain.CompanyModel{}).Count(&count)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Dry run and transactional commit", func(t *testing.T) {
+		// Anak dari SUB-1 ditulis sebelum induknya
+		data := buildCompanyImportWorkbook(t, map[string][][]interface{}{
+			"Perusahaan": {
+				companyHeader,
+				{"SUB-2", "PT Cucu", "SUB-1", "", ""},
+				{"SUB-1", "PT Anak", holding.Code, "01.234.567.8-901.000", "1234567890123"},
+			},
+			"Pemegang Saham": {
+				shareholderHeader,
+				{"SUB-1", "Badan Hukum", "Holding", holding.Code, 100, "Ya"},
+				{"SUB-2", "Badan Hukum", "PT Anak", "SUB-1", 100, "Ya"},
+			},
+			"Bidang Usaha": {{"Kode Perusahaan", "KBLI", "Tanggal Mulai Operasi"}, {"SUB-1", "06100", "2020-01-15"}},
+			"Pengurus":     {directorHeader, {"SUB-1", "Direktur Utama", "Andi", "2024-01-01", "2028-12-31"}},
+		})
+
+		preview, err := uc.Import("companies.xlsx", "", data, CompanyImportOptions{DryRun: true})
+		require.NoError(t, err)
+		require.Equal(t, 0, preview.ErrorCount, preview)
+		require.Len(t, preview.Companies, 2)
+		assert.Equal(t, "SUB-1", preview.Companies[0].Code)
+		assert.Equal(t, "existing", preview.Companies[0].ParentSource)
+		assert.Equal(t, "file", preview.Companies[1].ParentSource)
+		assert.Equal(t, holding.Level+2, preview.Companies[1].Level)
+		assert.True(t, preview.Companies[0].HasMainBusiness)
+
+		result, err := uc.Import("companies.xlsx", "", data, CompanyImportOptions{})
+		require.NoError(t, err)
+		require.True(t, result.Committed)
+
+		companyUC := NewCompanyUseCaseWithDB(db)
+		child, err := companyUC.GetCompanyByCode("SUB-1")
+		require.NoError(t, err)
+		grandchild, err := companyUC.GetCompanyByCode("SUB-2")
+		require.NoError(t, err)
+		assert.Equal(t, child.ID, *grandchild.ParentID)
+		assert.Equal(t, child.ID, *grandchild.MainParentCompanyID)
+
+		full, err := companyUC.GetCompanyByID(child.ID)
+		require.NoError(t, err)
+		assert.Len(t, full.Shareholders, 1)
+		assert.Len(t, full.Directors, 1)
+		require.Len(t, full.BusinessFields, 1)
+		assert.Equal(t, "2020-01-15", full.BusinessFields[0].StartOperationDate.Format("2006-01-02"))
+
+		// Import ulang ditolak karena kode sudah terdaftar
+		again, err := uc.Import("companies.xlsx", "", data, CompanyImportOptions{DryRun: true})
+		require.NoError(t, err)
+		assert.Equal(t, 0, again.ValidCompanies)
+	})
+
+	t.Run("CSV and access restriction", func(t *testing.T) {
+		csvData := strings.Join([]string{
+			"Perusahaan,Kode Perusahaan,Nama Perusahaan,Kode Induk",
+			"Perusahaan,CSV-1,PT CSV,",
+			"Pengurus,Kode Perusahaan,Jabatan,Nama Lengkap,Tanggal Mulai",
+			"Pengurus,CSV-1,Komisaris,Sari,15/02/2024",
+		}, "\n")
+		result, err := uc.Import("companies.csv", "", []byte(csvData), CompanyImportOptions{
+			DryRun:             true,
+			AllowedParentCodes: map[string]bool{"SUB-1": true},
+			DefaultParentCode:  "SUB-1",
+		})
+		require.NoError(t, err)
+		require.Equal(t, 0, result.ErrorCount, result)
+		assert.Equal(t, "SUB-1", result.Companies[0].ParentCode)
+
+		restricted := strings.Replace(csvData, "PT CSV,", "PT CSV,"+holding.Code, 1)
+		result, err = uc.Import("companies.csv", "", []byte(restricted), CompanyImportOptions{
+			DryRun:             true,
+			AllowedParentCodes: map[string]bool{"SUB-1": true},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.ErrorCount)
+
+		_, err = uc.Import("companies.csv", "", []byte("Lainnya,a,b\n"), CompanyImportOptions{DryRun: true})
+		assert.ErrorIs(t, err, ErrInvalidCompanyImportFile)
+	})
+
+	t.Run("CreateCompanyFull shares validation", func(t *testing.T) {
+		companyUC := NewCompanyUseCaseWithDB(db)
+		_, err := companyUC.CreateCompanyFull(&domain.CompanyCreateRequest{Name: "PT X", Code: "X-1", NIB: "12"})
+		assert.ErrorContains(t, err, "NIB")
+		_, err = companyUC.CreateCompanyFull(&domain.CompanyCreateRequest{Name: "PT X", Code: "X-1", Shareholders: []domain.ShareholderRequest{
+			{Name: "A", OwnershipPercent: 70}, {Name: "B", OwnershipPercent: 40},
+		}})
+		assert.ErrorContains(t, err, "harus 100%")
+	})
+}
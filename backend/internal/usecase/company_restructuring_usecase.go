@@ -0,0 +1,539 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/audit"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCompanyDissolved         = errors.New("company sudah dibubarkan, data hanya bisa dibaca")
+	ErrRestructuringCycle       = errors.New("restrukturisasi membentuk siklus hierarki")
+	ErrCompanyHasActiveChildren = errors.New("company masih memiliki anak perusahaan aktif, pindahkan atau merge terlebih dahulu")
+
+	// errRestructuringPreview dipakai untuk rollback transaksi preview
+	errRestructuringPreview = errors.New("restructuring preview")
+)
+
+// CompanyRestructuringOptions opsi eksekusi restrukturisasi.
+// Preview menjalankan aksi yang sama dalam transaksi lalu di-rollback, sehingga dampak preview = dampak sebenarnya.
+type CompanyRestructuringOptions struct {
+	Preview   bool
+	UserID    string
+	Username  string
+	IPAddress string
+	UserAgent string
+}
+
+// CompanyRestructuringUseCase interface untuk aksi korporasi atas hierarki company
+type CompanyRestructuringUseCase interface {
+	MoveCompany(companyID string, data *domain.MoveCompanyRequest, options CompanyRestructuringOptions) (*domain.CompanyRestructuringResult, error)
+	MergeCompany(sourceCompanyID string, data *domain.MergeCompanyRequest, options CompanyRestructuringOptions) (*domain.CompanyRestructuringResult, error)
+	DissolveCompany(companyID string, data *domain.DissolveCompanyRequest, options CompanyRestructuringOptions) (*domain.CompanyRestructuringResult, error)
+	GetRestructuringEvents(companyID string) ([]domain.CompanyRestructuringEventModel, error)
+}
+
+type companyRestructuringUseCase struct {
+	db *gorm.DB
+}
+
+// NewCompanyRestructuringUseCaseWithDB membuat restructuring use case dengan DB yang di-inject (untuk testing)
+func NewCompanyRestructuringUseCaseWithDB(db *gorm.DB) CompanyRestructuringUseCase {
+	return &companyRestructuringUseCase{db: db}
+}
+
+// NewCompanyRestructuringUseCase membuat restructuring use case dengan default DB
+func NewCompanyRestructuringUseCase() CompanyRestructuringUseCase {
+	return NewCompanyRestructuringUseCaseWithDB(database.GetDB())
+}
+
+// ensureCompanyNotDissolved menolak perubahan data company yang sudah dibubarkan
+func ensureCompanyNotDissolved(company *domain.CompanyModel) error {
+	if company != nil && company.DissolvedAt != nil {
+		return fmt.Errorf("%w: %s", ErrCompanyDissolved, company.Name)
+	}
+	return nil
+}
+
+// ensureCompanyIDNotDissolved sama dengan ensureCompanyNotDissolved berdasarkan ID (company tidak ditemukan diabaikan)
+func ensureCompanyIDNotDissolved(companyRepo repository.CompanyRepository, companyID string) error {
+	company, err := companyRepo.GetByID(companyID)
+	if err != nil {
+		return nil
+	}
+	return ensureCompanyNotDissolved(company)
+}
+
+// parseRestructuringDate membaca effective_date (YYYY-MM-DD, default hari ini); tanggal di masa depan ditolak
+func parseRestructuringDate(value string) (time.Time, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if value == "" {
+		return today, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, errors.New("effective_date harus format YYYY-MM-DD")
+	}
+	if date.After(today) {
+		return time.Time{}, errors.New("effective_date tidak boleh di masa depan")
+	}
+	return date, nil
+}
+
+// companyHierarchy snapshot seluruh company (termasuk nonaktif) untuk cek siklus dan hitung ulang level
+type companyHierarchy struct {
+	companies map[string]*domain.CompanyModel
+	children  map[string][]string
+}
+
+func loadCompanyHierarchy(tx *gorm.DB) (*companyHierarchy, error) {
+	var companies []domain.CompanyModel
+	if err := tx.Select("id", "code", "name", "parent_id", "level", "is_active", "dissolved_at").Order("code").Find(&companies).Error; err != nil {
+		return nil, fmt.Errorf("failed to load companies: %w", err)
+	}
+	hierarchy := &companyHierarchy{
+		companies: make(map[string]*domain.CompanyModel, len(companies)),
+		children:  make(map[string][]string),
+	}
+	for i := range companies {
+		company := &companies[i]
+		hierarchy.companies[company.ID] = company
+		if company.ParentID != nil {
+			hierarchy.children[*company.ParentID] = append(hierarchy.children[*company.ParentID], company.ID)
+		}
+	}
+	return hierarchy, nil
+}
+
+// subtree mengembalikan rootID dan seluruh turunannya (BFS, aman terhadap data hierarki yang rusak/siklus)
+func (h *companyHierarchy) subtree(rootID string) []string {
+	visited := map[string]bool{rootID: true}
+	ids := []string{rootID}
+	for i := 0; i < len(ids); i++ {
+		for _, childID := range h.children[ids[i]] {
+			if !visited[childID] {
+				visited[childID] = true
+				ids = append(ids, childID)
+			}
+		}
+	}
+	return ids
+}
+
+func (h *companyHierarchy) inSubtree(rootID, companyID string) bool {
+	for _, id := range h.subtree(rootID) {
+		if id == companyID {
+			return true
+		}
+	}
+	return false
+}
+
+// reparent memindahkan companyID ke newParentID dan menghitung ulang level seluruh subtree-nya
+func (h *companyHierarchy) reparent(tx *gorm.DB, companyID, newParentID string) ([]domain.CompanyRestructuringLevelChange, error) {
+	company := h.companies[companyID]
+	parent := h.companies[newParentID]
+	oldParentID := company.ParentID
+
+	if err := tx.Model(&domain.CompanyModel{}).Where("id = ?", companyID).Update("parent_id", newParentID).Error; err != nil {
+		return nil, fmt.Errorf("failed to update parent company: %w", err)
+	}
+	if err := repository.SyncCompanyHierarchy(tx, companyID); err != nil {
+		return nil, err
+	}
+
+	changes := []domain.CompanyRestructuringLevelChange{}
+	levels := map[string]int{companyID: parent.Level + 1}
+	for _, id := range h.subtree(companyID) {
+		current := h.companies[id]
+		if id != companyID {
+			levels[id] = levels[*current.ParentID] + 1
+		}
+		change := domain.CompanyRestructuringLevelChange{
+			CompanyID:   id,
+			Code:        current.Code,
+			Name:        current.Name,
+			OldParentID: current.ParentID,
+			NewParentID: current.ParentID,
+			OldLevel:    current.Level,
+			NewLevel:    levels[id],
+		}
+		if id == companyID {
+			change.OldParentID = oldParentID
+			change.NewParentID = &parent.ID
+		}
+		if change.NewLevel != change.OldLevel {
+			if err := tx.Model(&domain.CompanyModel{}).Where("id = ?", id).Update("level", change.NewLevel).Error; err != nil {
+				return nil, fmt.Errorf("failed to update company level: %w", err)
+			}
+		}
+		changes = append(changes, change)
+	}
+
+	// Sinkronkan snapshot supaya reparent berikutnya dalam transaksi yang sama memakai posisi terbaru
+	if oldParentID != nil {
+		siblings := h.children[*oldParentID][:0]
+		for _, id := range h.children[*oldParentID] {
+			if id != companyID {
+				siblings = append(siblings, id)
+			}
+		}
+		h.children[*oldParentID] = siblings
+	}
+	h.children[newParentID] = append(h.children[newParentID], companyID)
+	company.ParentID = &parent.ID
+	for id, level := range levels {
+		h.companies[id].Level = level
+	}
+	return changes, nil
+}
+
+// lookupRestructuringCompany mengambil company dari snapshot dan memastikan masih bisa direstrukturisasi
+func (h *companyHierarchy) lookupRestructuringCompany(id, label string) (*domain.CompanyModel, error) {
+	company, ok := h.companies[id]
+	if !ok {
+		return nil, fmt.Errorf("%s not found: %w", label, gorm.ErrRecordNotFound)
+	}
+	if err := ensureCompanyNotDissolved(company); err != nil {
+		return nil, err
+	}
+	if !company.IsActive {
+		return nil, fmt.Errorf("%s %s tidak aktif", label, company.Name)
+	}
+	return company, nil
+}
+
+// run menjalankan aksi dalam satu transaksi, mencatat event, lalu rollback jika preview
+func (uc *companyRestructuringUseCase) run(action, companyID string, effectiveDate string, reason *string, options CompanyRestructuringOptions,
+	apply func(tx *gorm.DB, hierarchy *companyHierarchy, event *domain.CompanyRestructuringEventModel, impact *domain.CompanyRestructuringImpact) error,
+) (*domain.CompanyRestructuringResult, error) {
+	zapLog := logger.GetLogger()
+
+	date, err := parseRestructuringDate(effectiveDate)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.CompanyRestructuringResult{
+		Preview: options.Preview,
+		Action:  action,
+		Impact:  domain.CompanyRestructuringImpact{Companies: []domain.CompanyRestructuringLevelChange{}},
+	}
+	event := &domain.CompanyRestructuringEventModel{
+		ID:                  uuid.GenerateUUID(),
+		Action:              action,
+		CompanyID:           companyID,
+		EffectiveDate:       date,
+		Reason:              reason,
+		PerformedBy:         options.UserID,
+		PerformedByUsername: options.Username,
+	}
+
+	err = uc.db.Transaction(func(tx *gorm.DB) error {
+		hierarchy, err := loadCompanyHierarchy(tx)
+		if err != nil {
+			return err
+		}
+		if err := apply(tx, hierarchy, event, &result.Impact); err != nil {
+			return err
+		}
+
+		impactJSON, err := json.Marshal(result.Impact)
+		if err != nil {
+			return fmt.Errorf("failed to encode restructuring impact: %w", err)
+		}
+		event.Impact = datatypes.JSON(impactJSON)
+		if err := tx.Create(event).Error; err != nil {
+			return fmt.Errorf("failed to record restructuring event: %w", err)
+		}
+
+		if options.Preview {
+			return errRestructuringPreview
+		}
+		return nil
+	})
+	if options.Preview && errors.Is(err, errRestructuringPreview) {
+		return result, nil
+	}
+	if err != nil {
+		zapLog.Warn("Company restructuring failed", zap.String("action", action), zap.String("company_id", companyID), zap.Error(err))
+		return nil, err
+	}
+
+	result.Event = event
+	audit.LogAction(options.UserID, options.Username, audit.ActionRestructureCompany, audit.ResourceCompany, companyID, options.IPAddress, options.UserAgent, audit.StatusSuccess, map[string]interface{}{
+		"restructuring_action": action,
+		"event_id":             event.ID,
+		"target_company_id":    event.TargetCompanyID,
+		"previous_parent_id":   event.PreviousParentID,
+		"effective_date":       date.Format("2006-01-02"),
+		"companies_affected":   len(result.Impact.Companies),
+	})
+	return result, nil
+}
+
+func (uc *companyRestructuringUseCase) MoveCompany(companyID string, data *domain.MoveCompanyRequest, options CompanyRestructuringOptions) (*domain.CompanyRestructuringResult, error) {
+	if data.NewParentID == "" {
+		return nil, errors.New("new_parent_id is required")
+	}
+
+	return uc.run(domain.RestructuringActionMove, companyID, data.EffectiveDate, data.Reason, options, func(tx *gorm.DB, hierarchy *companyHierarchy, event *domain.CompanyRestructuringEventModel, impact *domain.CompanyRestructuringImpact) error {
+		company, err := hierarchy.lookupRestructuringCompany(companyID, "company")
+		if err != nil {
+			return err
+		}
+		if company.Code == "PDV" {
+			return errors.New("holding tidak dapat dipindahkan")
+		}
+		parent, err := hierarchy.lookupRestructuringCompany(data.NewParentID, "new parent company")
+		if err != nil {
+			return err
+		}
+		if company.ParentID != nil && *company.ParentID == parent.ID {
+			return fmt.Errorf("%s sudah berada di bawah %s", company.Name, parent.Name)
+		}
+		if hierarchy.inSubtree(company.ID, parent.ID) {
+			return fmt.Errorf("%w: %s adalah %s sendiri atau anak perusahaannya", ErrRestructuringCycle, parent.Name, company.Name)
+		}
+
+		event.TargetCompanyID = &parent.ID
+		event.PreviousParentID = company.ParentID
+		changes, err := hierarchy.reparent(tx, company.ID, parent.ID)
+		if err != nil {
+			return err
+		}
+		impact.Companies = changes
+		return nil
+	})
+}
+
+func (uc *companyRestructuringUseCase) MergeCompany(sourceCompanyID string, data *domain.MergeCompanyRequest, options CompanyRestructuringOptions) (*domain.CompanyRestructuringResult, error) {
+	if data.TargetCompanyID == "" {
+		return nil, errors.New("target_company_id is required")
+	}
+	if data.TargetCompanyID == sourceCompanyID {
+		return nil, errors.New("company tidak dapat di-merge ke dirinya sendiri")
+	}
+
+	return uc.run(domain.RestructuringActionMerge, sourceCompanyID, data.EffectiveDate, data.Reason, options, func(tx *gorm.DB, hierarchy *companyHierarchy, event *domain.CompanyRestructuringEventModel, impact *domain.CompanyRestructuringImpact) error {
+		source, err := hierarchy.lookupRestructuringCompany(sourceCompanyID, "company")
+		if err != nil {
+			return err
+		}
+		if source.Code == "PDV" {
+			return errors.New("holding tidak dapat di-merge ke company lain")
+		}
+		target, err := hierarchy.lookupRestructuringCompany(data.TargetCompanyID, "target company")
+		if err != nil {
+			return err
+		}
+		if hierarchy.inSubtree(source.ID, target.ID) {
+			return fmt.Errorf("%w: %s adalah anak perusahaan %s, pindahkan terlebih dahulu", ErrRestructuringCycle, target.Name, source.Name)
+		}
+		event.TargetCompanyID = &target.ID
+		event.PreviousParentID = source.ParentID
+
+		// 1. Anak perusahaan (termasuk nonaktif) pindah ke company penerima
+		for _, childID := range append([]string(nil), hierarchy.children[source.ID]...) {
+			changes, err := hierarchy.reparent(tx, childID, target.ID)
+			if err != nil {
+				return err
+			}
+			impact.Companies = append(impact.Companies, changes...)
+		}
+
+		// 2. Folder dokumen beserta dokumen di dalamnya (termasuk yang ada di recycle bin agar restore tidak kembali ke company asal)
+		sourceFolders := tx.Unscoped().Model(&domain.DocumentFolderModel{}).Select("id").Where("company_id = ?", source.ID)
+		if err := tx.Unscoped().Model(&domain.DocumentModel{}).Where("folder_id IN (?)", sourceFolders).Count(&impact.Documents).Error; err != nil {
+			return fmt.Errorf("failed to count documents: %w", err)
+		}
+		folders := tx.Unscoped().Model(&domain.DocumentFolderModel{}).Where("company_id = ?", source.ID).Update("company_id", target.ID)
+		if folders.Error != nil {
+			return fmt.Errorf("failed to move document folders: %w", folders.Error)
+		}
+		impact.DocumentFolders = folders.RowsAffected
+
+		// 3. Laporan keuangan; periode yang sudah ada di penerima tetap di company asal sebagai riwayat
+		if err := mergeFinancialReports(tx, source.ID, target.ID, impact); err != nil {
+			return err
+		}
+
+		// 4. User dan assignment company
+		users := tx.Model(&domain.UserModel{}).Where("company_id = ?", source.ID).Update("company_id", target.ID)
+		if users.Error != nil {
+			return fmt.Errorf("failed to move users: %w", users.Error)
+		}
+		impact.Users = users.RowsAffected
+		if err := mergeUserAssignments(tx, source.ID, target.ID, impact); err != nil {
+			return err
+		}
+
+		// 5. Kepemilikan saham company asal di company lain dialihkan ke penerima
+		shareholdings := tx.Model(&domain.ShareholderModel{}).
+			Where("shareholder_company_id = ? AND company_id <> ?", source.ID, target.ID).
+			Updates(map[string]interface{}{"shareholder_company_id": target.ID, "name": target.Name})
+		if shareholdings.Error != nil {
+			return fmt.Errorf("failed to move shareholdings: %w", shareholdings.Error)
+		}
+		impact.Shareholdings = shareholdings.RowsAffected
+		if impact.Shareholdings > 0 {
+			all, err := repository.NewShareholderRepositoryWithDB(tx).GetAll()
+			if err != nil {
+				return fmt.Errorf("failed to get shareholders: %w", err)
+			}
+			if newOwnershipGraph(all).reaches(target.ID, target.ID) {
+				return fmt.Errorf("%w: kepemilikan saham %s yang dialihkan membuat %s memiliki dirinya sendiri", ErrOwnershipCycle, source.Name, target.Name)
+			}
+		}
+
+		// 6. Company asal dibubarkan dan dicatat sebagai di-merge ke penerima
+		return dissolveCompanyRecord(tx, source, event.EffectiveDate, data.Reason, &target.ID)
+	})
+}
+
+func (uc *companyRestructuringUseCase) DissolveCompany(companyID string, data *domain.DissolveCompanyRequest, options CompanyRestructuringOptions) (*domain.CompanyRestructuringResult, error) {
+	return uc.run(domain.RestructuringActionDissolve, companyID, data.EffectiveDate, data.Reason, options, func(tx *gorm.DB, hierarchy *companyHierarchy, event *domain.CompanyRestructuringEventModel, impact *domain.CompanyRestructuringImpact) error {
+		company, ok := hierarchy.companies[companyID]
+		if !ok {
+			return fmt.Errorf("company not found: %w", gorm.ErrRecordNotFound)
+		}
+		if err := ensureCompanyNotDissolved(company); err != nil {
+			return err
+		}
+		if company.Code == "PDV" {
+			return errors.New("holding tidak dapat dibubarkan")
+		}
+		for _, childID := range hierarchy.children[company.ID] {
+			if child := hierarchy.companies[childID]; child.IsActive && child.DissolvedAt == nil {
+				return fmt.Errorf("%w: %s", ErrCompanyHasActiveChildren, child.Name)
+			}
+		}
+		event.PreviousParentID = company.ParentID
+
+		// Data tidak dipindah, hanya dihitung sebagai riwayat yang menjadi read-only
+		counts := []struct {
+			model interface{}
+			query string
+			dest  *int64
+		}{
+			{&domain.DocumentFolderModel{}, "company_id = ?", &impact.DocumentFolders},
+			{&domain.DocumentModel{}, "folder_id IN (SELECT id FROM document_folders WHERE company_id = ?)", &impact.Documents},
+			{&domain.FinancialReportModel{}, "company_id = ?", &impact.FinancialReports},
+			{&domain.UserModel{}, "company_id = ?", &impact.Users},
+			{&domain.UserCompanyAssignmentModel{}, "company_id = ? AND is_active = true", &impact.UserAssignments},
+			{&domain.ShareholderModel{}, "shareholder_company_id = ?", &impact.Shareholdings},
+		}
+		for _, count := range counts {
+			if err := tx.Model(count.model).Where(count.query, company.ID).Count(count.dest).Error; err != nil {
+				return fmt.Errorf("failed to count company data: %w", err)
+			}
+		}
+
+		return dissolveCompanyRecord(tx, company, event.EffectiveDate, data.Reason, nil)
+	})
+}
+
+func (uc *companyRestructuringUseCase) GetRestructuringEvents(companyID string) ([]domain.CompanyRestructuringEventModel, error) {
+	var events []domain.CompanyRestructuringEventModel
+	err := uc.db.Where("company_id = ? OR target_company_id = ? OR previous_parent_id = ?", companyID, companyID, companyID).
+		Order("created_at DESC").Find(&events).Error
+	return events, err
+}
+
+// dissolveCompanyRecord menandai company dibubarkan (soft): nonaktif, status Dibubarkan, tanggal efektif tercatat
+func dissolveCompanyRecord(tx *gorm.DB, company *domain.CompanyModel, date time.Time, reason *string, mergedInto *string) error {
+	err := tx.Model(&domain.CompanyModel{}).Where("id = ?", company.ID).Updates(map[string]interface{}{
+		"status":                 domain.CompanyStatusDissolved,
+		"is_active":              false,
+		"dissolved_at":           date,
+		"dissolution_reason":     reason,
+		"merged_into_company_id": mergedInto,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to dissolve company: %w", err)
+	}
+	return nil
+}
+
+// financialReportMergeKey identitas periode laporan keuangan (satu laporan per kunci per company)
+func financialReportMergeKey(report domain.FinancialReportModel) string {
+	if report.IsRKAP {
+		return fmt.Sprintf("RKAP %s v%d", report.Year, report.RKAPVersion)
+	}
+	if report.IsAudited {
+		return report.Period + " (audited)"
+	}
+	return report.Period
+}
+
+func mergeFinancialReports(tx *gorm.DB, sourceID, targetID string, impact *domain.CompanyRestructuringImpact) error {
+	var sourceReports, targetReports []domain.FinancialReportModel
+	if err := tx.Select("id", "year", "period", "is_rkap", "is_audited", "rkap_version").Where("company_id = ?", sourceID).Find(&sourceReports).Error; err != nil {
+		return fmt.Errorf("failed to get financial reports: %w", err)
+	}
+	if err := tx.Select("id", "year", "period", "is_rkap", "is_audited", "rkap_version").Where("company_id = ?", targetID).Find(&targetReports).Error; err != nil {
+		return fmt.Errorf("failed to get financial reports: %w", err)
+	}
+
+	existing := make(map[string]bool, len(targetReports))
+	for _, report := range targetReports {
+		existing[financialReportMergeKey(report)] = true
+	}
+	var moveIDs []string
+	for _, report := range sourceReports {
+		key := financialReportMergeKey(report)
+		if existing[key] {
+			impact.FinancialReportConflicts = append(impact.FinancialReportConflicts, key)
+			continue
+		}
+		moveIDs = append(moveIDs, report.ID)
+	}
+	sort.Strings(impact.FinancialReportConflicts)
+
+	if len(moveIDs) > 0 {
+		moved := tx.Model(&domain.FinancialReportModel{}).Where("id IN ?", moveIDs).Update("company_id", targetID)
+		if moved.Error != nil {
+			return fmt.Errorf("failed to move financial reports: %w", moved.Error)
+		}
+		impact.FinancialReports = moved.RowsAffected
+	}
+	return nil
+}
+
+func mergeUserAssignments(tx *gorm.DB, sourceID, targetID string, impact *domain.CompanyRestructuringImpact) error {
+	var assignments []domain.UserCompanyAssignmentModel
+	if err := tx.Where("company_id = ?", sourceID).Find(&assignments).Error; err != nil {
+		return fmt.Errorf("failed to get user assignments: %w", err)
+	}
+	for _, assignment := range assignments {
+		var duplicates int64
+		if err := tx.Model(&domain.UserCompanyAssignmentModel{}).Where("user_id = ? AND company_id = ?", assignment.UserID, targetID).Count(&duplicates).Error; err != nil {
+			return fmt.Errorf("failed to check user assignment: %w", err)
+		}
+		update := map[string]interface{}{"company_id": targetID}
+		if duplicates > 0 {
+			// User sudah punya assignment di penerima: assignment lama tetap di company asal sebagai riwayat
+			update = map[string]interface{}{"is_active": false}
+			impact.DeactivatedUserAssignments++
+		} else {
+			impact.UserAssignments++
+		}
+		if err := tx.Model(&domain.UserCompanyAssignmentModel{}).Where("id = ?", assignment.ID).Updates(update).Error; err != nil {
+			return fmt.Errorf("failed to move user assignment: %w", err)
+		}
+	}
+	return nil
+}
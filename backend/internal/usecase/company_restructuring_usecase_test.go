@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"github.com/repoareta/pedeve-dms-app/backend/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompanyRestructuring(t *testing.T) {
+	db := helpers.SetupTestDB(t)
+	defer helpers.CleanupTestDB(t, db)
+
+	holding := createTestCompanyForNotification(t, db, nil)
+	a := createTestCompanyForNotification(t, db, &holding.ID)
+	b := createTestCompanyForNotification(t, db, &holding.ID)
+	a1 := createTestCompanyForNotification(t, db, &a.ID)
+	require.NoError(t, db.Model(a1).Update("level", 2).Error)
+	a11 := createTestCompanyForNotification(t, db, &a1.ID)
+	require.NoError(t, db.Model(a11).Update("level", 3).Error)
+
+	uc := NewCompanyRestructuringUseCaseWithDB(db)
+	level := func(id string) int {
+		var company domain.CompanyModel
+		require.NoError(t, db.First(&company, "id = ?", id).Error)
+		return company.Level
+	}
+
+	t.Run("Move with preview and cycle prevention", func(t *testing.T) {
+		_, err := uc.MoveCompany(a.ID, &domain.MoveCompanyRequest{NewParentID: a11.ID}, CompanyRestructuringOptions{})
+		assert.ErrorIs(t, err, ErrRestructuringCycle)
+
+		preview, err := uc.MoveCompany(a1.ID, &domain.MoveCompanyRequest{NewParentID: b.ID}, CompanyRestructuringOptions{Preview: true})
+		require.NoError(t, err)
+		assert.True(t, preview.Preview)
+		assert.Nil(t, preview.Event)
+		require.Len(t, preview.Impact.Companies, 2)
+		var events int64
+		db.Model(&domain.CompanyRestructuringEventModel{}).Count(&events)
+		assert.Zero(t, events)
+
+		// Pindah A1 (dan A11) ke bawah A11? siklus. Pindah ke bawah B lalu ke bawah holding langsung
+		result, err := uc.MoveCompany(a1.ID, &domain.MoveCompanyRequest{NewParentID: holding.ID}, CompanyRestructuringOptions{UserID: "u1"})
+		require.NoError(t, err)
+		require.NotNil(t, result.Event)
+		assert.Equal(t, 1, level(a1.ID))
+		assert.Equal(t, 2, level(a11.ID))
+
+		_, err = uc.MoveCompany(a1.ID, &domain.MoveCompanyRequest{NewParentID: a.ID}, CompanyRestructuringOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 2, level(a1.ID))
+		assert.Equal(t, 3, level(a11.ID))
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		folder := &domain.DocumentFolderModel{ID: uuid.GenerateUUID(), Name: "Legal", CompanyID: &a.ID}
+		require.NoError(t, db.Create(folder).Error)
+		require.NoError(t, db.Create(&domain.DocumentModel{ID: uuid.GenerateUUID(), FolderID: &folder.ID, Name: "Akta", FileName: "a.pdf", FilePath: "/a.pdf", MimeType: "application/pdf"}).Error)
+		trashed := &domain.DocumentFolderModel{ID: uuid.GenerateUUID(), Name: "Lama", CompanyID: &a.ID}
+		require.NoError(t, db.Create(trashed).Error)
+		trashedDoc := &domain.DocumentModel{ID: uuid.GenerateUUID(), FolderID: &trashed.ID, Name: "Lama", FileName: "l.pdf", FilePath: "/l.pdf", MimeType: "application/pdf"}
+		require.NoError(t, db.Create(trashedDoc).Error)
+		require.NoError(t, db.Delete(trashedDoc).Error)
+		require.NoError(t, db.Delete(trashed).Error)
+		for _, report := range []domain.FinancialReportModel{
+			{ID: uuid.GenerateUUID(), CompanyID: a.ID, Year: "2024", Period: "2024-01"},
+			{ID: uuid.GenerateUUID(), CompanyID: a.ID, Year: "2024", Period: "2024-02"},
+			{ID: uuid.GenerateUUID(), CompanyID: b.ID, Year: "2024", Period: "2024-01"},
+		} {
+			require.NoError(t, db.Create(&report).Error)
+		}
+		user := createTestUserForNotification(t, db, &a.ID)
+		require.NoError(t, db.Create(&domain.UserCompanyAssignmentModel{ID: uuid.GenerateUUID(), UserID: user.ID, CompanyID: a.ID, IsActive: true}).Error)
+		createTestShareholding(t, db, a, a1, "", 100)
+
+		_, err := uc.MergeCompany(holding.ID, &domain.MergeCompanyRequest{TargetCompanyID: a.ID}, CompanyRestructuringOptions{})
+		assert.ErrorIs(t, err, ErrRestructuringCycle)
+
+		result, err := uc.MergeCompany(a.ID, &domain.MergeCompanyRequest{TargetCompanyID: b.ID, EffectiveDate: "2025-06-30"}, CompanyRestructuringOptions{Preview: true})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), result.Impact.DocumentFolders)
+		assert.Equal(t, int64(2), result.Impact.Documents)
+		assert.Equal(t, int64(1), result.Impact.FinancialReports)
+		assert.Equal(t, []string{"2024-01"}, result.Impact.FinancialReportConflicts)
+		assert.Equal(t, int64(1), result.Impact.Users)
+		assert.Equal(t, int64(1), result.Impact.UserAssignments)
+		assert.Equal(t, int64(1), result.Impact.Shareholdings)
+		assert.Nil(t, result.Event)
+
+		var unchanged domain.CompanyModel
+		require.NoError(t, db.First(&unchanged, "id = ?", a.ID).Error)
+		assert.Nil(t, unchanged.DissolvedAt)
+
+		_, err = uc.MergeCompany(a.ID, &domain.MergeCompanyRequest{TargetCompanyID: b.ID, EffectiveDate: "2025-06-30"}, CompanyRestructuringOptions{})
+		require.NoError(t, err)
+
+		var merged domain.CompanyModel
+		require.NoError(t, db.First(&merged, "id = ?", a.ID).Error)
+		assert.False(t, merged.IsActive)
+		assert.Equal(t, domain.CompanyStatusDissolved, merged.Status)
+		require.NotNil(t, merged.MergedIntoCompanyID)
+		assert.Equal(t, b.ID, *merged.MergedIntoCompanyID)
+		assert.Equal(t, "2025-06-30", merged.DissolvedAt.Format("2006-01-02"))
+
+		// Folder di recycle bin ikut pindah sehingga restore tidak kembali ke company yang dibubarkan
+		var movedTrash domain.DocumentFolderModel
+		require.NoError(t, db.Unscoped().First(&movedTrash, "id = ?", trashed.ID).Error)
+		assert.Equal(t, b.ID, *movedTrash.CompanyID)
+		assert.True(t, movedTrash.DeletedAt.Valid)
+
+		var child domain.CompanyModel
+		require.NoError(t, db.First(&child, "id = ?", a1.ID).Error)
+		assert.Equal(t, b.ID, *child.ParentID)
+		assert.Equal(t, 2, level(a1.ID))
+
+		events, err := uc.GetRestructuringEvents(b.ID)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, domain.RestructuringActionMerge, events[0].Action)
+
+		// Company yang sudah dibubarkan read-only
+		_, err = NewCompanyUseCaseWithDB(db).UpdateCompanyStatus(a.ID, true)
+		assert.ErrorIs(t, err, ErrCompanyDissolved)
+		_, err = NewFinancialReportUseCaseWithDB(db).CreateFinancialReport(&domain.CreateFinancialReportRequest{CompanyID: a.ID, Year: "2024", Period: "2024-05"}, "", "", "", "")
+		assert.ErrorIs(t, err, ErrCompanyDissolved)
+		_, err = uc.MoveCompany(a.ID, &domain.MoveCompanyRequest{NewParentID: holding.ID}, CompanyRestructuringOptions{})
+		assert.ErrorIs(t, err, ErrCompanyDissolved)
+
+		// Dokumen dan pengurus company yang sudah dibubarkan juga read-only
+		docUC := NewDocumentUseCaseWithDB(db)
+		archive := &domain.DocumentFolderModel{ID: uuid.GenerateUUID(), Name: "Arsip", CompanyID: &a.ID}
+		require.NoError(t, db.Create(archive).Error)
+		_, err = docUC.UploadDocument(UploadDocumentInput{FolderID: &archive.ID, FileName: "b.pdf", ContentType: "application/pdf", Data: []byte("%PDF-1.4"), Size: 8})
+		assert.ErrorIs(t, err, ErrCompanyDissolved)
+		_, err = docUC.CreateFolder("Baru", &a.ID, nil, "u1")
+		assert.ErrorIs(t, err, ErrCompanyDissolved)
+		archived := &domain.DocumentModel{ID: uuid.GenerateUUID(), FolderID: &archive.ID, Name: "Arsip", FileName: "c.pdf", FilePath: "/c.pdf", MimeType: "application/pdf"}
+		require.NoError(t, db.Create(archived).Error)
+		title := "Arsip Baru"
+		_, err = docUC.UpdateDocument(archived.ID, UpdateDocumentInput{Title: &title})
+		assert.ErrorIs(t, err, ErrCompanyDissolved)
+		assert.ErrorIs(t, docUC.DeleteDocument(archived.ID, "u1"), ErrCompanyDissolved)
+
+		director := &domain.DirectorModel{ID: uuid.GenerateUUID(), CompanyID: a.ID, Position: "Direktur Utama", FullName: "Budi"}
+		require.NoError(t, db.Create(director).Error)
+		_, err = NewDirectorTermUseCaseWithDB(db).RenewTerm(director.ID, &domain.RenewDirectorTermRequest{}, "u1")
+		assert.ErrorIs(t, err, ErrCompanyDissolved)
+		_, err = NewDirectorTermUseCaseWithDB(db).SetPositionRequirements(a.ID, nil, "u1")
+		assert.ErrorIs(t, err, ErrCompanyDissolved)
+	})
+
+	t.Run("Dissolve", func(t *testing.T) {
+		_, err := uc.DissolveCompany(b.ID, &domain.DissolveCompanyRequest{}, CompanyRestructuringOptions{})
+		assert.ErrorIs(t, err, ErrCompanyHasActiveChildren)
+
+		_, err = uc.DissolveCompany(a11.ID, &domain.DissolveCompanyRequest{EffectiveDate: "2999-01-01"}, CompanyRestructuringOptions{})
+		assert.ErrorContains(t, err, "masa depan")
+
+		result, err := uc.DissolveCompany(a11.ID, &domain.DissolveCompanyRequest{}, CompanyRestructuringOptions{})
+		require.NoError(t, err)
+		require.NotNil(t, result.Event)
+		var dissolved domain.CompanyModel
+		require.NoError(t, db.First(&dissolved, "id = ?", a11.ID).Error)
+		assert.NotNil(t, dissolved.DissolvedAt)
+		assert.Nil(t, dissolved.MergedIntoCompanyID)
+
+		_, err = uc.DissolveCompany(a11.ID, &domain.DissolveCompanyRequest{}, CompanyRestructuringOptions{})
+		assert.ErrorIs(t, err, ErrCompanyDissolved)
+	})
+}
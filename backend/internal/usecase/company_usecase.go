@@ -39,6 +39,7 @@ type companyUseCase struct {
 	businessFieldRepo repository.BusinessFieldRepository
 	directorRepo      repository.DirectorRepository
 	documentRepo      repository.DocumentRepository
+	directorTermRepo  repository.DirectorTermRepository
 }
 
 // NewCompanyUseCaseWithDB membuat company use case dengan DB yang di-inject (untuk testing)
@@ -49,6 +50,7 @@ func NewCompanyUseCaseWithDB(db *gorm.DB) CompanyUseCase {
 		businessFieldRepo: repository.NewBusinessFieldRepositoryWithDB(db),
 		directorRepo:      repository.NewDirectorRepositoryWithDB(db),
 		documentRepo:      repository.NewDocumentRepositoryWithDB(db),
+		directorTermRepo:  repository.NewDirectorTermRepositoryWithDB(db),
 	}
 }
 
@@ -156,6 +158,9 @@ func (uc *companyUseCase) UpdateCompany(id, name, description string) (*domain.C
 	if err != nil {
 		return nil, fmt.Errorf("company not found: %w", err)
 	}
+	if err := ensureCompanyNotDissolved(company); err != nil {
+		return nil, err
+	}
 
 	company.Name = name
 	company.Description = description
@@ -172,6 +177,10 @@ func (uc *companyUseCase) UpdateCompanyStatus(id string, isActive bool) (*domain
 	if err != nil {
 		return nil, fmt.Errorf("company not found: %w", err)
 	}
+	// Company yang dibubarkan tidak bisa diaktifkan kembali lewat update status
+	if err := ensureCompanyNotDissolved(company); err != nil {
+		return nil, err
+	}
 
 	company.IsActive = isActive
 	if err := uc.companyRepo.Update(company); err != nil {
@@ -216,11 +225,11 @@ func (uc *companyUseCase) CreateCompanyFull(data *domain.CompanyCreateRequest) (
 	}
 	// #endregion
 
-	// Validasi keunikan code - cek dengan lock untuk prevent race condition
-	existing, _ := uc.companyRepo.GetByCode(data.Code)
-	if existing != nil {
-		zapLog.Warn("Company code already exists", zap.String("code", data.Code), zap.String("existing_id", existing.ID))
-		return nil, errors.New("company code already exists")
+	// Validasi profil (NPWP/NIB, kepemilikan, pengurus), keunikan code dan parent - sama dengan import bulk
+	level, err := uc.validateCompanyCreate(data)
+	if err != nil {
+		zapLog.Warn("Company create validation failed", zap.String("code", data.Code), zap.Error(err))
+		return nil, err
 	}
 
 	// #region agent log
@@ -241,19 +250,6 @@ func (uc *companyUseCase) CreateCompanyFull(data *domain.CompanyCreateRequest) (
 	// Sekarang: perusahaan bisa dibuat tanpa parent_id, dan parent_id akan di-setup nanti secara terpisah
 	// Validasi ini dihapus karena user ingin bisa membuat perusahaan baru tanpa parent_id di awal
 
-	// Tentukan level
-	// CRITICAL: Level 0 hanya untuk holding company yang sebenarnya (misalnya code = "PDV")
-	// Perusahaan tanpa parent_id yang baru dibuat menggunakan level 1 sebagai default (temporary)
-	// Level akan di-recalculate dengan benar setelah parent_id di-set nanti
-	level := 1 // Default level untuk perusahaan tanpa parent_id (bukan level 0)
-	if data.ParentID != nil {
-		parent, err := uc.companyRepo.GetByID(*data.ParentID)
-		if err != nil {
-			return nil, fmt.Errorf("parent company not found: %w", err)
-		}
-		level = parent.Level + 1
-	}
-
 	// Set default currency ke IDR kalau tidak disediakan
 	currency := data.Currency
 	if currency == "" {
@@ -382,6 +378,8 @@ func (uc *companyUseCase) CreateCompanyFull(data *domain.CompanyCreateRequest) (
 		}
 		if err := uc.directorRepo.Create(director); err != nil {
 			zapLog.Error("Failed to create director", zap.Error(err))
+		} else if err := syncDirectorTerm(uc.directorTermRepo, director, ""); err != nil {
+			zapLog.Warn("Failed to record director term", zap.String("director_id", director.ID), zap.Error(err))
 		}
 	}
 
@@ -403,11 +401,20 @@ func (uc *companyUseCase) UpdateCompanyFull(id string, data *domain.CompanyUpdat
 		return nil, fmt.Errorf("company not found: %w", err)
 	}
 
-	// Cegah update kalau company sudah di-delete (soft delete)
+	// Cegah update kalau company sudah dibubarkan atau di-delete (soft delete)
+	if err := ensureCompanyNotDissolved(company); err != nil {
+		return nil, err
+	}
 	if !company.IsActive {
 		return nil, fmt.Errorf("cannot update inactive company")
 	}
 
+	// Validasi struktur kepemilikan (total 100%, tanpa siklus) sebelum ada data yang berubah
+	if err := validateOwnershipStructure(uc.shareholderRepo, id, data.Shareholders); err != nil {
+		zapLog.Warn("Invalid ownership structure", zap.String("company_id", id), zap.Error(err))
+		return nil, err
+	}
+
 	// CRITICAL: Prevent holding (code = "PDV") from being updated to have parent_id
 	// Holding must always have parent_id = NULL and level = 0
 	if company.Code == "PDV" && data.ParentID != nil && *data.ParentID != "" {
@@ -581,16 +588,23 @@ func (uc *companyUseCase) UpdateCompanyFull(id string, data *domain.CompanyUpdat
 		)
 	}
 
-	// Delete existing related data (except directors - we'll handle them separately to preserve IDs)
-	if err := uc.shareholderRepo.DeleteByCompanyID(id); err != nil {
-		zapLog.Warn("Failed to delete shareholders", zap.String("company_id", id), zap.Error(err))
-	}
+	// Delete existing business fields (shareholders & directors di-handle terpisah untuk mempertahankan ID)
 	if err := uc.businessFieldRepo.DeleteByCompanyID(id); err != nil {
 		zapLog.Warn("Failed to delete business fields", zap.String("company_id", id), zap.Error(err))
 	}
-	// Directors will be handled separately to preserve existing IDs and maintain document relationships
 
-	// Create new shareholders
+	// Update/create shareholders (preserve existing IDs supaya riwayat perubahan per shareholder tetap tersambung)
+	existingShareholders, err := uc.shareholderRepo.GetByCompanyID(id)
+	if err != nil {
+		zapLog.Warn("Failed to get existing shareholders", zap.String("company_id", id), zap.Error(err))
+		existingShareholders = []domain.ShareholderModel{}
+	}
+	existingShareholderMap := make(map[string]*domain.ShareholderModel)
+	for i := range existingShareholders {
+		existingShareholderMap[ShareholderKey(existingShareholders[i])] = &existingShareholders[i]
+	}
+	usedShareholderIDs := make(map[string]bool)
+
 	for _, sh := range data.Shareholders {
 		shareholder := &domain.ShareholderModel{
 			ID:                   uuid.GenerateUUID(),
@@ -607,8 +621,27 @@ func (uc *companyUseCase) UpdateCompanyFull(id string, data *domain.CompanyUpdat
 			PaidUpCapital:        sh.PaidUpCapital,     // Modal Disetor untuk individu
 			IsMainParent:         sh.IsMainParent,
 		}
-		if err := uc.shareholderRepo.Create(shareholder); err != nil {
+
+		if existing, exists := existingShareholderMap[ShareholderKey(*shareholder)]; exists && !usedShareholderIDs[existing.ID] {
+			shareholder.ID = existing.ID
+			shareholder.CreatedAt = existing.CreatedAt
+			if err := uc.shareholderRepo.Update(shareholder); err != nil {
+				zapLog.Error("Failed to update shareholder", zap.String("shareholder_id", shareholder.ID), zap.Error(err))
+				continue
+			}
+		} else if err := uc.shareholderRepo.Create(shareholder); err != nil {
 			zapLog.Error("Failed to create shareholder", zap.Error(err))
+			continue
+		}
+		usedShareholderIDs[shareholder.ID] = true
+	}
+
+	// Delete shareholders that are no longer in the new data
+	for _, existing := range existingShareholders {
+		if !usedShareholderIDs[existing.ID] {
+			if err := uc.shareholderRepo.Delete(existing.ID); err != nil {
+				zapLog.Warn("Failed to delete removed shareholder", zap.String("shareholder_id", existing.ID), zap.Error(err))
+			}
 		}
 	}
 
@@ -648,8 +681,7 @@ func (uc *companyUseCase) UpdateCompanyFull(id string, data *domain.CompanyUpdat
 	// Create map of existing directors by identifier (full_name + ktp) for matching
 	existingDirectorMap := make(map[string]*domain.DirectorModel)
 	for i := range existingDirectors {
-		key := existingDirectors[i].FullName + "|" + existingDirectors[i].KTP
-		existingDirectorMap[key] = &existingDirectors[i]
+		existingDirectorMap[DirectorKey(existingDirectors[i])] = &existingDirectors[i]
 	}
 
 	// Track which directors are still in use
@@ -674,7 +706,7 @@ func (uc *companyUseCase) UpdateCompanyFull(id string, data *domain.CompanyUpdat
 		}
 
 		// Match director berdasarkan identifier (full_name + ktp)
-		key := dir.FullName + "|" + dir.KTP
+		key := DirectorKey(domain.DirectorModel{FullName: dir.FullName, KTP: dir.KTP})
 		existingDirector, exists := existingDirectorMap[key]
 
 		if exists && existingDirector != nil {
@@ -696,6 +728,9 @@ func (uc *companyUseCase) UpdateCompanyFull(id string, data *domain.CompanyUpdat
 					zap.Error(err))
 			} else {
 				usedDirectorIDs[existingDirector.ID] = true
+				if err := syncDirectorTerm(uc.directorTermRepo, existingDirector, ""); err != nil {
+					zapLog.Warn("Failed to sync director term", zap.String("director_id", existingDirector.ID), zap.Error(err))
+				}
 				zapLog.Info("Updated existing director",
 					zap.String("director_id", existingDirector.ID),
 					zap.String("full_name", existingDirector.FullName))
@@ -717,6 +752,9 @@ func (uc *companyUseCase) UpdateCompanyFull(id string, data *domain.CompanyUpdat
 				zapLog.Error("Failed to create director", zap.Error(err))
 			} else {
 				usedDirectorIDs[director.ID] = true
+				if err := syncDirectorTerm(uc.directorTermRepo, director, ""); err != nil {
+					zapLog.Warn("Failed to record director term", zap.String("director_id", director.ID), zap.Error(err))
+				}
 				zapLog.Info("Created new director",
 					zap.String("director_id", director.ID),
 					zap.String("full_name", director.FullName))
@@ -733,6 +771,10 @@ func (uc *companyUseCase) UpdateCompanyFull(id string, data *domain.CompanyUpdat
 					zap.String("full_name", existingDirector.FullName),
 					zap.Error(err))
 			} else {
+				// Riwayat masa jabatan tetap disimpan, masa jabatan aktif ditutup per tanggal penghapusan
+				if err := uc.directorTermRepo.CloseActiveByDirectorID(existingDirector.ID, domain.DirectorTermClosedRemoved, time.Now()); err != nil {
+					zapLog.Warn("Failed to close director term", zap.String("director_id", existingDirector.ID), zap.Error(err))
+				}
 				zapLog.Info("Deleted removed director",
 					zap.String("director_id", existingDirector.ID),
 					zap.String("full_name", existingDirector.FullName))
@@ -3,10 +3,25 @@ package usecase
 import (
 	"fmt"
 
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
 	"go.uber.org/zap"
 )
 
+// ShareholderKey mengembalikan identifier untuk mencocokkan shareholder lama dan baru saat update company.
+// Shareholder perusahaan dicocokkan dari shareholder_company_id, shareholder individu/eksternal dari type + name.
+func ShareholderKey(sh domain.ShareholderModel) string {
+	if sh.ShareholderCompanyID != nil && *sh.ShareholderCompanyID != "" {
+		return "company|" + *sh.ShareholderCompanyID
+	}
+	return sh.Type + "|" + sh.Name
+}
+
+// DirectorKey mengembalikan identifier (full_name + ktp) untuk mencocokkan director lama dan baru saat update company
+func DirectorKey(dir domain.DirectorModel) string {
+	return dir.FullName + "|" + dir.KTP
+}
+
 // updateDescendantsLevel updates the level of all descendants recursively
 // when a company's parent or level changes
 // FIXED: Added max level limit (10) to prevent level from growing infinitely
@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+)
+
+// ownershipTolerance menampung pembulatan persentase kepemilikan (10 digit desimal)
+const ownershipTolerance = 0.0001
+
+// companyValidationIssue adalah satu masalah validasi data company.
+// Field memakai nama field JSON CompanyCreateRequest, misalnya "npwp" atau "shareholders[1].ownership_percent".
+type companyValidationIssue struct {
+	Field   string
+	Message string
+}
+
+// identityDigits menghapus pemisah yang umum pada NPWP/NIB (titik, strip, spasi)
+func identityDigits(value string) string {
+	return strings.NewReplacer(".", "", "-", "", " ", "").Replace(strings.TrimSpace(value))
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return value != ""
+}
+
+// validateNPWP menerima NPWP 15 digit (format lama) atau 16 digit (NIK/NPWP baru), boleh dengan pemisah
+func validateNPWP(value string) error {
+	digits := identityDigits(value)
+	if !isDigits(digits) || (len(digits) != 15 && len(digits) != 16) {
+		return fmt.Errorf("NPWP harus 15 atau 16 digit angka, nilai: '%s'", value)
+	}
+	return nil
+}
+
+// validateNIB menerima NIB OSS 13 digit
+func validateNIB(value string) error {
+	digits := identityDigits(value)
+	if !isDigits(digits) || len(digits) != 13 {
+		return fmt.Errorf("NIB harus 13 digit angka, nilai: '%s'", value)
+	}
+	return nil
+}
+
+// validateCompanyProfile memvalidasi data company tanpa akses database.
+// Dipakai CreateCompanyFull dan import bulk agar aturan validasinya sama.
+func validateCompanyProfile(data *domain.CompanyCreateRequest) []companyValidationIssue {
+	var issues []companyValidationIssue
+	add := func(field, message string) {
+		issues = append(issues, companyValidationIssue{Field: field, Message: message})
+	}
+
+	if strings.TrimSpace(data.Name) == "" {
+		add("name", "nama perusahaan wajib diisi")
+	}
+	if strings.TrimSpace(data.Code) == "" {
+		add("code", "kode perusahaan wajib diisi")
+	}
+	if data.NPWP != "" {
+		if err := validateNPWP(data.NPWP); err != nil {
+			add("npwp", err.Error())
+		}
+	}
+	if data.NIB != "" {
+		if err := validateNIB(data.NIB); err != nil {
+			add("nib", err.Error())
+		}
+	}
+	if data.Currency != "" && data.Currency != "IDR" && data.Currency != "USD" {
+		add("currency", fmt.Sprintf("mata uang harus IDR atau USD, nilai: '%s'", data.Currency))
+	}
+	if data.AuthorizedCapital != nil && data.PaidUpCapital != nil && *data.PaidUpCapital > *data.AuthorizedCapital {
+		add("paid_up_capital", "modal disetor tidak boleh melebihi modal dasar")
+	}
+
+	issues = append(issues, validateShareholderRequests(data.Shareholders)...)
+
+	for i, dir := range data.Directors {
+		field := fmt.Sprintf("directors[%d]", i)
+		if strings.TrimSpace(dir.Position) == "" {
+			add(field+".position", "jabatan pengurus wajib diisi")
+		}
+		if strings.TrimSpace(dir.FullName) == "" {
+			add(field+".full_name", "nama lengkap pengurus wajib diisi")
+		}
+		if dir.NPWP != "" {
+			if err := validateNPWP(dir.NPWP); err != nil {
+				add(field+".npwp", err.Error())
+			}
+		}
+		if dir.StartDate != nil && dir.EndDate != nil && !dir.StartDate.IsZero() && !dir.EndDate.IsZero() && dir.EndDate.Before(dir.StartDate.Time) {
+			add(field+".end_date", "tanggal selesai jabatan tidak boleh sebelum tanggal mulai")
+		}
+	}
+
+	return issues
+}
+
+// validateShareholderRequests memvalidasi pemegang saham: nama wajib, persentase 0-100, dan total tepat 100%.
+// Total 0 (semua persentase 0) diterima karena persentase dihitung dari modal disetor yang belum diisi.
+func validateShareholderRequests(shareholders []domain.ShareholderRequest) []companyValidationIssue {
+	var issues []companyValidationIssue
+	total := 0.0
+	for i, sh := range shareholders {
+		field := fmt.Sprintf("shareholders[%d]", i)
+		if strings.TrimSpace(sh.Name) == "" {
+			issues = append(issues, companyValidationIssue{Field: field + ".name", Message: "nama pemegang saham wajib diisi"})
+		}
+		if sh.OwnershipPercent < 0 || sh.OwnershipPercent > 100 {
+			issues = append(issues, companyValidationIssue{
+				Field:   field + ".ownership_percent",
+				Message: fmt.Sprintf("persentase kepemilikan harus antara 0-100, nilai: %v", sh.OwnershipPercent),
+			})
+		}
+		total += sh.OwnershipPercent
+	}
+	if total != 0 && math.Abs(total-100) > ownershipTolerance {
+		issues = append(issues, companyValidationIssue{
+			Field:   "shareholders",
+			Message: fmt.Sprintf("total kepemilikan pemegang saham harus 100%%, saat ini %.4f%%", math.Round(total*10000)/10000),
+		})
+	}
+	return issues
+}
+
+// joinValidationIssues menggabungkan issue validasi menjadi satu error untuk endpoint JSON
+func joinValidationIssues(issues []companyValidationIssue) error {
+	if len(issues) == 0 {
+		return nil
+	}
+	messages := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		messages = append(messages, fmt.Sprintf("%s: %s", issue.Field, issue.Message))
+	}
+	return errors.New(strings.Join(messages, "; "))
+}
+
+// validateCompanyCreate menjalankan validasi profil dan pengecekan database (kode unik, parent ada).
+// Mengembalikan level company baru berdasarkan parent.
+func (uc *companyUseCase) validateCompanyCreate(data *domain.CompanyCreateRequest) (int, error) {
+	if err := joinValidationIssues(validateCompanyProfile(data)); err != nil {
+		return 0, err
+	}
+
+	// Validasi keunikan code
+	if existing, _ := uc.companyRepo.GetByCode(data.Code); existing != nil {
+		return 0, errors.New("company code already exists")
+	}
+
+	// CRITICAL: Level 0 hanya untuk holding company yang sebenarnya (misalnya code = "PDV")
+	// Perusahaan tanpa parent_id yang baru dibuat menggunakan level 1 sebagai default (temporary)
+	// Level akan di-recalculate dengan benar setelah parent_id di-set nanti
+	level := 1
+	if data.ParentID != nil {
+		parent, err := uc.companyRepo.GetByID(*data.ParentID)
+		if err != nil {
+			return 0, fmt.Errorf("parent company not found: %w", err)
+		}
+		if err := ensureCompanyNotDissolved(parent); err != nil {
+			return 0, err
+		}
+		level = parent.Level + 1
+	}
+	return level, nil
+}
@@ -237,6 +237,10 @@ func (uc *developmentUseCase) ResetSubsidiaryData() error {
 		return fmt.Errorf("failed to delete companies: %w", err)
 	}
 	zapLog.Info("Deleted companies (hard delete)", zap.Int("company_count", len(companyIDs)))
+	if err := repository.DeleteCompanyHierarchy(tx, companyIDs); err != nil {
+		tx.Rollback()
+		return err
+	}
 
 	// 8. CRITICAL: Reset holding company level to 0 dan ensure parent_id is NULL
 	// Ini penting untuk memastikan holding level tidak kacau setelah reset
@@ -244,7 +248,7 @@ func (uc *developmentUseCase) ResetSubsidiaryData() error {
 	if err == nil && holding != nil {
 		// Reset holding level to 0 dan pastikan parent_id is NULL
 		if err := tx.Model(&domain.CompanyModel{}).
-			Where("code = ?", "PDV").
+			Where("id = ?", holding.ID).
 			Updates(map[string]interface{}{
 				"level":     0,
 				"parent_id": nil,
@@ -253,6 +257,10 @@ func (uc *developmentUseCase) ResetSubsidiaryData() error {
 			tx.Rollback()
 			return fmt.Errorf("failed to reset holding level: %w", err)
 		}
+		if err := repository.SyncCompanyHierarchy(tx, holding.ID); err != nil {
+			tx.Rollback()
+			return err
+		}
 		zapLog.Info("Reset holding company level to 0", zap.String("holding_id", holding.ID))
 	}
 
@@ -658,11 +666,12 @@ func truncateString(s string, maxLen int) string {
 	return s[:maxLen] + "..."
 }
 
-// ResetReportData deletes all reports
+// ResetReportData deletes all reports created by the report seeder
+// Realisasi yang diinput atau diimport user (satu tabel dengan data seeder) tidak ikut terhapus
 func (uc *developmentUseCase) ResetReportData() error {
 	zapLog := logger.GetLogger()
 
-	count, err := uc.reportRepo.Count()
+	count, err := uc.reportRepo.CountSeeded()
 	if err != nil {
 		return fmt.Errorf("failed to count reports: %w", err)
 	}
@@ -672,7 +681,7 @@ func (uc *developmentUseCase) ResetReportData() error {
 		return nil
 	}
 
-	err = uc.reportRepo.DeleteAll()
+	err = uc.reportRepo.DeleteSeeded()
 	if err != nil {
 		return fmt.Errorf("failed to delete reports: %w", err)
 	}
@@ -816,7 +825,7 @@ func (uc *developmentUseCase) RunReportSeeder() error {
 				inputterID = &randomUser.ID
 			}
 
-			// Create report
+			// Create report (ditandai sebagai data seeder supaya bisa di-reset tanpa menyentuh data user)
 			report := &domain.ReportModel{
 				ID:             uuid.GenerateUUID(),
 				Period:         period,
@@ -831,7 +840,9 @@ func (uc *developmentUseCase) RunReportSeeder() error {
 				Remark:         nil,
 			}
 
-			if err := uc.reportRepo.Create(report); err != nil {
+			financial := &domain.FinancialReportModel{ID: report.ID, Source: domain.FinancialReportSourceSeeder}
+			repository.ApplyReportToFinancialReport(report, financial)
+			if err := uc.financialReportRepo.Create(financial); err != nil {
 				zapLog.Warn("Failed to create report", zap.String("company", company.ID), zap.String("period", period), zap.Error(err))
 				continue
 			}
@@ -846,7 +857,7 @@ func (uc *developmentUseCase) RunReportSeeder() error {
 
 // CheckReportDataExists checks if report data already exists
 func (uc *developmentUseCase) CheckReportDataExists() (bool, error) {
-	count, err := uc.reportRepo.Count()
+	count, err := uc.reportRepo.CountSeeded()
 	if err != nil {
 		return false, err
 	}
@@ -0,0 +1,496 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const dateLayout = "2006-01-02"
+
+// DirectorTermUseCase interface untuk siklus masa jabatan pengurus (pengangkatan, perpanjangan, kekosongan jabatan)
+type DirectorTermUseCase interface {
+	GetDirector(id string) (*domain.DirectorModel, error)
+	GetTerm(id string) (*domain.DirectorTermModel, error)
+	GetTerms(directorID string) ([]domain.DirectorTermModel, error)
+	RenewTerm(directorID string, req *domain.RenewDirectorTermRequest, actorID string) (*domain.DirectorTermModel, error)
+	LinkResolution(termID string, req *domain.LinkTermResolutionRequest) (*domain.DirectorTermModel, error)
+	GetPositionRequirements(companyID string) ([]domain.CompanyPositionRequirementModel, error)
+	SetPositionRequirements(companyID string, reqs []domain.PositionRequirementRequest, actorID string) ([]domain.CompanyPositionRequirementModel, error)
+	DetectVacancies(companyID string, asOf time.Time) ([]domain.PositionVacancy, error)
+	GetBoardComposition(companyID string, asOf time.Time) (*domain.BoardCompositionReport, error)
+}
+
+type directorTermUseCase struct {
+	termRepo        repository.DirectorTermRepository
+	requirementRepo repository.CompanyPositionRequirementRepository
+	directorRepo    repository.DirectorRepository
+	positionRepo    repository.DirectorPositionRepository
+	docRepo         repository.DocumentRepository
+	companyRepo     repository.CompanyRepository
+	escalationRepo  repository.NotificationEscalationRepository
+	now             func() time.Time
+}
+
+// NewDirectorTermUseCase creates a new director term use case
+func NewDirectorTermUseCase() DirectorTermUseCase {
+	return NewDirectorTermUseCaseWithDB(database.GetDB())
+}
+
+// NewDirectorTermUseCaseWithDB creates a new director term use case with injected DB (for testing)
+func NewDirectorTermUseCaseWithDB(db *gorm.DB) DirectorTermUseCase {
+	return &directorTermUseCase{
+		termRepo:        repository.NewDirectorTermRepositoryWithDB(db),
+		requirementRepo: repository.NewCompanyPositionRequirementRepositoryWithDB(db),
+		directorRepo:    repository.NewDirectorRepositoryWithDB(db),
+		positionRepo:    repository.NewDirectorPositionRepositoryWithDB(db),
+		docRepo:         repository.NewDocumentRepositoryWithDB(db),
+		companyRepo:     repository.NewCompanyRepositoryWithDB(db),
+		escalationRepo:  repository.NewNotificationEscalationRepositoryWithDB(db),
+		now:             time.Now,
+	}
+}
+
+func (uc *directorTermUseCase) GetDirector(id string) (*domain.DirectorModel, error) {
+	return uc.directorRepo.GetByID(id)
+}
+
+func (uc *directorTermUseCase) GetTerm(id string) (*domain.DirectorTermModel, error) {
+	return uc.termRepo.GetByID(id)
+}
+
+// GetTerms mengembalikan riwayat masa jabatan pengurus (membuat masa jabatan awal untuk data lama yang belum punya riwayat)
+func (uc *directorTermUseCase) GetTerms(directorID string) ([]domain.DirectorTermModel, error) {
+	director, err := uc.directorRepo.GetByID(directorID)
+	if err != nil {
+		return nil, err
+	}
+	if err := syncDirectorTerm(uc.termRepo, director, ""); err != nil {
+		return nil, err
+	}
+	return uc.termRepo.GetByDirectorID(directorID)
+}
+
+// RenewTerm menutup masa jabatan aktif dan membuka masa jabatan baru (pengangkatan kembali)
+func (uc *directorTermUseCase) RenewTerm(directorID string, req *domain.RenewDirectorTermRequest, actorID string) (*domain.DirectorTermModel, error) {
+	director, err := uc.directorRepo.GetByID(directorID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureCompanyIDNotDissolved(uc.companyRepo, director.CompanyID); err != nil {
+		return nil, err
+	}
+	if err := syncDirectorTerm(uc.termRepo, director, actorID); err != nil {
+		return nil, err
+	}
+	current, err := uc.termRepo.GetActiveByDirectorID(directorID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if req.EndDate == nil || req.EndDate.IsZero() {
+		return nil, errors.New("end_date is required")
+	}
+	endDate := req.EndDate.Time
+
+	var startDate time.Time
+	switch {
+	case req.StartDate != nil && !req.StartDate.IsZero():
+		startDate = req.StartDate.Time
+	case current != nil && current.EndDate != nil:
+		startDate = current.EndDate.AddDate(0, 0, 1)
+	default:
+		now := uc.now()
+		startDate = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	if !endDate.After(startDate) {
+		return nil, errors.New("end_date must be after start_date")
+	}
+	if current != nil && current.StartDate != nil && !startDate.After(*current.StartDate) {
+		return nil, errors.New("start_date of the new term must be after the start of the current term")
+	}
+
+	if req.ResolutionDocumentID != nil && *req.ResolutionDocumentID != "" {
+		if err := uc.validateResolutionDocument(*req.ResolutionDocumentID, director.ID); err != nil {
+			return nil, err
+		}
+	} else {
+		req.ResolutionDocumentID = nil
+	}
+
+	position := strings.TrimSpace(req.Position)
+	if position == "" {
+		position = director.Position
+	}
+
+	next := &domain.DirectorTermModel{
+		DirectorID:           director.ID,
+		CompanyID:            director.CompanyID,
+		FullName:             director.FullName,
+		Position:             position,
+		TermNumber:           1,
+		AppointmentType:      domain.DirectorTermReappointment,
+		StartDate:            &startDate,
+		EndDate:              &endDate,
+		Status:               domain.DirectorTermStatusActive,
+		ResolutionDocumentID: req.ResolutionDocumentID,
+		ResolutionNumber:     strings.TrimSpace(req.ResolutionNumber),
+		Notes:                req.Notes,
+		CreatedBy:            actorID,
+	}
+	if req.ResolutionDate != nil && !req.ResolutionDate.IsZero() {
+		next.ResolutionDate = &req.ResolutionDate.Time
+	}
+
+	closedAt := uc.now()
+	if current != nil {
+		next.TermNumber = current.TermNumber + 1
+		next.PreviousTermID = &current.ID
+		// Masa jabatan lama berakhir sehari sebelum masa jabatan baru jika tumpang tindih
+		if current.EndDate == nil || !current.EndDate.Before(startDate) {
+			trimmed := startDate.AddDate(0, 0, -1)
+			current.EndDate = &trimmed
+		}
+		current.Status = domain.DirectorTermStatusClosed
+		current.ClosedReason = domain.DirectorTermClosedRenewed
+		current.ClosedAt = &closedAt
+	}
+
+	director.Position = position
+	director.StartDate = &startDate
+	director.EndDate = &endDate
+
+	if err := uc.termRepo.Renew(current, next, director); err != nil {
+		return nil, err
+	}
+
+	// Masa jabatan yang diperpanjang menutup rantai eskalasi notifikasi yang masih terbuka
+	if openEscalations, err := uc.escalationRepo.GetOpenByResource("director", director.ID); err == nil {
+		for _, esc := range openEscalations {
+			var resolvedBy *string
+			if actorID != "" {
+				resolvedBy = &actorID
+			}
+			if err := uc.escalationRepo.Resolve(esc.ID, resolvedBy, "Masa jabatan diperpanjang", closedAt); err != nil {
+				logger.GetLogger().Warn("Failed to resolve director escalation", zap.Error(err), zap.String("escalation_id", esc.ID))
+			}
+		}
+	}
+
+	return uc.termRepo.GetByID(next.ID)
+}
+
+// LinkResolution menautkan dokumen SK/RUPS ke masa jabatan
+func (uc *directorTermUseCase) LinkResolution(termID string, req *domain.LinkTermResolutionRequest) (*domain.DirectorTermModel, error) {
+	term, err := uc.termRepo.GetByID(termID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureCompanyIDNotDissolved(uc.companyRepo, term.CompanyID); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.ResolutionDocumentID) == "" {
+		return nil, errors.New("resolution_document_id is required")
+	}
+	if err := uc.validateResolutionDocument(req.ResolutionDocumentID, term.DirectorID); err != nil {
+		return nil, err
+	}
+
+	term.ResolutionDocumentID = &req.ResolutionDocumentID
+	if number := strings.TrimSpace(req.ResolutionNumber); number != "" {
+		term.ResolutionNumber = number
+	}
+	if req.ResolutionDate != nil && !req.ResolutionDate.IsZero() {
+		term.ResolutionDate = &req.ResolutionDate.Time
+	}
+	if err := uc.termRepo.Update(term); err != nil {
+		return nil, err
+	}
+	return uc.termRepo.GetByID(termID)
+}
+
+// validateResolutionDocument memastikan dokumen SK/RUPS ada dan terkait dengan pengurus yang sama
+func (uc *directorTermUseCase) validateResolutionDocument(documentID, directorID string) error {
+	doc, err := uc.docRepo.GetDocumentByID(documentID)
+	if err != nil {
+		return fmt.Errorf("resolution document not found: %w", err)
+	}
+	if doc.DirectorID == nil || *doc.DirectorID != directorID {
+		return errors.New("resolution document must be linked to the same director (document director_id)")
+	}
+	return nil
+}
+
+func (uc *directorTermUseCase) GetPositionRequirements(companyID string) ([]domain.CompanyPositionRequirementModel, error) {
+	return uc.requirementRepo.GetByCompanyID(companyID)
+}
+
+// SetPositionRequirements mengganti daftar jabatan wajib company (posisi harus aktif di master jabatan)
+func (uc *directorTermUseCase) SetPositionRequirements(companyID string, reqs []domain.PositionRequirementRequest, actorID string) ([]domain.CompanyPositionRequirementModel, error) {
+	company, err := uc.companyRepo.GetByID(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("company not found: %w", err)
+	}
+	if err := ensureCompanyNotDissolved(company); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	requirements := make([]domain.CompanyPositionRequirementModel, 0, len(reqs))
+	for _, r := range reqs {
+		if r.PositionID == "" {
+			return nil, errors.New("position_id is required")
+		}
+		if seen[r.PositionID] {
+			return nil, fmt.Errorf("duplicate position_id: %s", r.PositionID)
+		}
+		seen[r.PositionID] = true
+
+		position, err := uc.positionRepo.GetByID(r.PositionID)
+		if err != nil {
+			return nil, fmt.Errorf("director position %s not found", r.PositionID)
+		}
+		if !position.IsActive {
+			return nil, fmt.Errorf("director position %s is inactive", position.Name)
+		}
+		minCount := r.MinCount
+		if minCount <= 0 {
+			minCount = 1
+		}
+		requirements = append(requirements, domain.CompanyPositionRequirementModel{
+			PositionID: r.PositionID,
+			MinCount:   minCount,
+			CreatedBy:  actorID,
+		})
+	}
+
+	if err := uc.requirementRepo.ReplaceForCompany(companyID, requirements); err != nil {
+		return nil, err
+	}
+	return uc.requirementRepo.GetByCompanyID(companyID)
+}
+
+// DetectVacancies mengembalikan jabatan wajib yang kurang terisi pada tanggal tertentu
+func (uc *directorTermUseCase) DetectVacancies(companyID string, asOf time.Time) ([]domain.PositionVacancy, error) {
+	terms, err := uc.companyTerms(companyID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.vacancies(companyID, terms, asOf)
+}
+
+// GetBoardComposition menyusun laporan susunan pengurus sebuah company pada tanggal tertentu
+func (uc *directorTermUseCase) GetBoardComposition(companyID string, asOf time.Time) (*domain.BoardCompositionReport, error) {
+	company, err := uc.companyRepo.GetByID(companyID)
+	if err != nil {
+		return nil, err
+	}
+	terms, err := uc.companyTerms(companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	asOfKey := asOf.Format(dateLayout)
+	report := &domain.BoardCompositionReport{
+		CompanyID:   company.ID,
+		CompanyName: company.Name,
+		AsOf:        asOfKey,
+		Members:     []domain.BoardMember{},
+		ByPosition:  make(map[string]int),
+	}
+
+	activeDirectors := make(map[string]bool)
+	for _, term := range terms {
+		if !termCovers(term, asOfKey) || activeDirectors[term.DirectorID] {
+			continue
+		}
+		activeDirectors[term.DirectorID] = true
+
+		member := domain.BoardMember{
+			DirectorID:           term.DirectorID,
+			TermID:               term.ID,
+			FullName:             term.FullName,
+			Position:             term.Position,
+			TermNumber:           term.TermNumber,
+			StartDate:            term.StartDate,
+			EndDate:              term.EndDate,
+			ResolutionDocumentID: term.ResolutionDocumentID,
+			ResolutionNumber:     term.ResolutionNumber,
+		}
+		if term.EndDate != nil {
+			endDate, _ := time.Parse(dateLayout, term.EndDate.Format(dateLayout))
+			asOfDate, _ := time.Parse(dateLayout, asOfKey)
+			days := int(endDate.Sub(asOfDate).Hours() / 24)
+			member.DaysRemaining = &days
+		}
+		report.Members = append(report.Members, member)
+		report.ByPosition[term.Position]++
+	}
+	sort.Slice(report.Members, func(i, j int) bool {
+		if report.Members[i].Position != report.Members[j].Position {
+			return report.Members[i].Position < report.Members[j].Position
+		}
+		return report.Members[i].FullName < report.Members[j].FullName
+	})
+	report.TotalActive = len(report.Members)
+
+	report.Vacancies, err = uc.vacancies(companyID, terms, asOf)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// companyTerms mengambil seluruh masa jabatan company, termasuk masa jabatan awal untuk pengurus lama yang belum punya riwayat
+func (uc *directorTermUseCase) companyTerms(companyID string) ([]domain.DirectorTermModel, error) {
+	directors, err := uc.directorRepo.GetByCompanyID(companyID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range directors {
+		if err := syncDirectorTerm(uc.termRepo, &directors[i], ""); err != nil {
+			return nil, err
+		}
+	}
+	return uc.termRepo.GetByCompanyID(companyID)
+}
+
+func (uc *directorTermUseCase) vacancies(companyID string, terms []domain.DirectorTermModel, asOf time.Time) ([]domain.PositionVacancy, error) {
+	requirements, err := uc.requirementRepo.GetByCompanyID(companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	asOfKey := asOf.Format(dateLayout)
+	vacancies := []domain.PositionVacancy{}
+	for _, req := range requirements {
+		if req.Position == nil {
+			continue
+		}
+		holders := make(map[string]bool)
+		var lastHolder *domain.DirectorTermModel
+		for i := range terms {
+			term := &terms[i]
+			if !strings.EqualFold(strings.TrimSpace(term.Position), strings.TrimSpace(req.Position.Name)) {
+				continue
+			}
+			if termCovers(*term, asOfKey) {
+				holders[term.DirectorID] = true
+				continue
+			}
+			// Pengurus terakhir = masa jabatan dengan tanggal akhir paling baru sebelum tanggal laporan
+			if end := termEndKey(*term); end != "" && end < asOfKey {
+				if lastHolder == nil || end > termEndKey(*lastHolder) {
+					lastHolder = term
+				}
+			}
+		}
+
+		if len(holders) >= req.MinCount {
+			continue
+		}
+		vacancy := domain.PositionVacancy{
+			PositionID: req.PositionID,
+			Position:   req.Position.Name,
+			Required:   req.MinCount,
+			Filled:     len(holders),
+			Vacant:     req.MinCount - len(holders),
+		}
+		if lastHolder != nil {
+			vacancy.LastHolderID = lastHolder.DirectorID
+			lastEnd, _ := time.Parse(dateLayout, termEndKey(*lastHolder))
+			vacancy.VacantSince = lastEnd.AddDate(0, 0, 1).Format(dateLayout)
+		}
+		vacancies = append(vacancies, vacancy)
+	}
+	return vacancies, nil
+}
+
+// termCovers mengecek apakah masa jabatan berlaku pada tanggal (YYYY-MM-DD)
+func termCovers(term domain.DirectorTermModel, dateKey string) bool {
+	if term.StartDate != nil && term.StartDate.Format(dateLayout) > dateKey {
+		return false
+	}
+	end := termEndKey(term)
+	return end == "" || end >= dateKey
+}
+
+// termEndKey mengembalikan tanggal akhir efektif masa jabatan (YYYY-MM-DD), kosong jika tanpa batas
+// Masa jabatan pengurus yang dihapus berakhir pada tanggal penghapusan
+func termEndKey(term domain.DirectorTermModel) string {
+	end := ""
+	if term.EndDate != nil {
+		end = term.EndDate.Format(dateLayout)
+	}
+	if term.Status == domain.DirectorTermStatusClosed && term.ClosedReason == domain.DirectorTermClosedRemoved && term.ClosedAt != nil {
+		closed := term.ClosedAt.Format(dateLayout)
+		if end == "" || closed < end {
+			end = closed
+		}
+	}
+	return end
+}
+
+// syncDirectorTerm menjaga masa jabatan aktif tetap sesuai data pengurus:
+// membuat masa jabatan pertama jika belum ada, atau mengoreksi tanggal/jabatan masa jabatan aktif saat data pengurus diedit
+func syncDirectorTerm(termRepo repository.DirectorTermRepository, director *domain.DirectorModel, actorID string) error {
+	if termRepo == nil {
+		return nil
+	}
+	active, err := termRepo.GetActiveByDirectorID(director.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if active == nil {
+		terms, err := termRepo.GetByDirectorID(director.ID)
+		if err != nil {
+			return err
+		}
+		termNumber := len(terms) + 1
+		appointmentType := domain.DirectorTermAppointment
+		if termNumber > 1 {
+			appointmentType = domain.DirectorTermReappointment
+		}
+		return termRepo.Create(&domain.DirectorTermModel{
+			DirectorID:      director.ID,
+			CompanyID:       director.CompanyID,
+			FullName:        director.FullName,
+			Position:        director.Position,
+			TermNumber:      termNumber,
+			AppointmentType: appointmentType,
+			StartDate:       director.StartDate,
+			EndDate:         director.EndDate,
+			Status:          domain.DirectorTermStatusActive,
+			CreatedBy:       actorID,
+		})
+	}
+
+	if active.Position == director.Position && active.FullName == director.FullName && active.CompanyID == director.CompanyID &&
+		sameDate(active.StartDate, director.StartDate) && sameDate(active.EndDate, director.EndDate) {
+		return nil
+	}
+	active.Position = director.Position
+	active.FullName = director.FullName
+	active.CompanyID = director.CompanyID
+	active.StartDate = director.StartDate
+	active.EndDate = director.EndDate
+	active.ResolutionDocument = nil
+	return termRepo.Update(active)
+}
+
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Format(dateLayout) == b.Format(dateLayout)
+}
@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"github.com/repoareta/pedeve-dms-app/backend/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// setupTestDirectorTermUseCase creates a director term use case with in-memory database
+func setupTestDirectorTermUseCase(t *testing.T) (*directorTermUseCase, *gorm.DB) {
+	db := helpers.SetupTestDB(t)
+
+	err := db.AutoMigrate(
+		&domain.DirectorPositionModel{},
+		&domain.DirectorTermModel{},
+		&domain.CompanyPositionRequirementModel{},
+		&domain.NotificationEscalationModel{},
+		&domain.NotificationEscalationLevelModel{},
+	)
+	require.NoError(t, err)
+
+	uc := NewDirectorTermUseCaseWithDB(db).(*directorTermUseCase)
+	return uc, db
+}
+
+// TestDirectorTermUseCase_Lifecycle tests term history, renewal, vacancies and board composition
+func TestDirectorTermUseCase_Lifecycle(t *testing.T) {
+	uc, db := setupTestDirectorTermUseCase(t)
+	defer helpers.CleanupTestDB(t, db)
+
+	company := createTestCompanyForNotification(t, db, nil)
+	presidentDirector := createTestDirectorForTerm(t, db, company.ID, "Direktur Utama", "Budi Santoso", "2022-01-01", "2024-12-31")
+	createTestDirectorForTerm(t, db, company.ID, "Komisaris", "Siti Aminah", "2023-01-01", "2027-12-31")
+
+	presidentPos := createTestPositionForTerm(t, db, "Direktur Utama")
+	commissionerPos := createTestPositionForTerm(t, db, "Komisaris")
+	_, err := uc.SetPositionRequirements(company.ID, []domain.PositionRequirementRequest{
+		{PositionID: presidentPos.ID},
+		{PositionID: commissionerPos.ID, MinCount: 2},
+	}, "tester")
+	require.NoError(t, err)
+
+	t.Run("Legacy director gets an initial appointment term", func(t *testing.T) {
+		terms, err := uc.GetTerms(presidentDirector.ID)
+		require.NoError(t, err)
+		require.Len(t, terms, 1)
+		assert.Equal(t, 1, terms[0].TermNumber)
+		assert.Equal(t, domain.DirectorTermAppointment, terms[0].AppointmentType)
+		assert.Equal(t, domain.DirectorTermStatusActive, terms[0].Status)
+	})
+
+	t.Run("Vacancy detected after term ends", func(t *testing.T) {
+		vacancies, err := uc.DetectVacancies(company.ID, mustDate(t, "2025-02-01"))
+		require.NoError(t, err)
+		require.Len(t, vacancies, 2)
+
+		byPosition := make(map[string]domain.PositionVacancy)
+		for _, v := range vacancies {
+			byPosition[v.Position] = v
+		}
+		assert.Equal(t, 1, byPosition["Direktur Utama"].Vacant)
+		assert.Equal(t, presidentDirector.ID, byPosition["Direktur Utama"].LastHolderID)
+		assert.Equal(t, "2025-01-01", byPosition["Direktur Utama"].VacantSince)
+		assert.Equal(t, 1, byPosition["Komisaris"].Filled)
+		assert.Equal(t, 1, byPosition["Komisaris"].Vacant)
+	})
+
+	t.Run("Renewal requires resolution document of the same director", func(t *testing.T) {
+		otherDirectorID := uuid.GenerateUUID()
+		foreignDoc := createTestResolutionDocument(t, db, &otherDirectorID)
+		_, err := uc.RenewTerm(presidentDirector.ID, &domain.RenewDirectorTermRequest{
+			EndDate:              &domain.DateOnly{Time: mustDate(t, "2029-12-31")},
+			ResolutionDocumentID: &foreignDoc.ID,
+		}, "tester")
+		assert.Error(t, err)
+	})
+
+	t.Run("Renewal closes old term and opens a new one", func(t *testing.T) {
+		resolution := createTestResolutionDocument(t, db, &presidentDirector.ID)
+		term, err := uc.RenewTerm(presidentDirector.ID, &domain.RenewDirectorTermRequest{
+			EndDate:              &domain.DateOnly{Time: mustDate(t, "2029-12-31")},
+			ResolutionDocumentID: &resolution.ID,
+			ResolutionNumber:     "SK-001/RUPS/2025",
+		}, "tester")
+		require.NoError(t, err)
+		assert.Equal(t, 2, term.TermNumber)
+		assert.Equal(t, domain.DirectorTermReappointment, term.AppointmentType)
+		assert.Equal(t, "2025-01-01", term.StartDate.Format("2006-01-02"))
+		require.NotNil(t, term.ResolutionDocument)
+		assert.Equal(t, resolution.ID, term.ResolutionDocument.ID)
+
+		terms, err := uc.GetTerms(presidentDirector.ID)
+		require.NoError(t, err)
+		require.Len(t, terms, 2)
+		assert.Equal(t, domain.DirectorTermStatusClosed, terms[0].Status)
+		assert.Equal(t, domain.DirectorTermClosedRenewed, terms[0].ClosedReason)
+
+		director, err := uc.GetDirector(presidentDirector.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "2029-12-31", director.EndDate.Format("2006-01-02"))
+
+		vacancies, err := uc.DetectVacancies(company.ID, mustDate(t, "2025-02-01"))
+		require.NoError(t, err)
+		require.Len(t, vacancies, 1)
+		assert.Equal(t, "Komisaris", vacancies[0].Position)
+	})
+
+	t.Run("Board composition as of a past date", func(t *testing.T) {
+		report, err := uc.GetBoardComposition(company.ID, mustDate(t, "2022-06-01"))
+		require.NoError(t, err)
+		require.Len(t, report.Members, 1)
+		assert.Equal(t, "Budi Santoso", report.Members[0].FullName)
+		assert.Equal(t, 1, report.Members[0].TermNumber)
+		assert.Equal(t, 1, report.ByPosition["Direktur Utama"])
+
+		report, err = uc.GetBoardComposition(company.ID, mustDate(t, "2025-06-01"))
+		require.NoError(t, err)
+		assert.Equal(t, 2, report.TotalActive)
+	})
+}
+
+func createTestDirectorForTerm(t *testing.T, db *gorm.DB, companyID, position, fullName, start, end string) *domain.DirectorModel {
+	startDate := mustDate(t, start)
+	endDate := mustDate(t, end)
+	director := &domain.DirectorModel{
+		ID:        uuid.GenerateUUID(),
+		CompanyID: companyID,
+		Position:  position,
+		FullName:  fullName,
+		StartDate: &startDate,
+		EndDate:   &endDate,
+	}
+	require.NoError(t, db.Create(director).Error)
+	return director
+}
+
+func createTestPositionForTerm(t *testing.T, db *gorm.DB, name string) *domain.DirectorPositionModel {
+	position := &domain.DirectorPositionModel{
+		ID:        uuid.GenerateUUID(),
+		Name:      name,
+		IsActive:  true,
+		CreatedBy: "tester",
+	}
+	require.NoError(t, db.Create(position).Error)
+	return position
+}
+
+func createTestResolutionDocument(t *testing.T, db *gorm.DB, directorID *string) *domain.DocumentModel {
+	doc := &domain.DocumentModel{
+		ID:         uuid.GenerateUUID(),
+		DirectorID: directorID,
+		Name:       "SK Pengangkatan",
+		FileName:   "sk.pdf",
+		FilePath:   "/uploads/sk.pdf",
+		MimeType:   "application/pdf",
+		Size:       1024,
+		UploaderID: "tester",
+	}
+	require.NoError(t, db.Create(doc).Error)
+	return doc
+}
+
+func mustDate(t *testing.T, value string) time.Time {
+	parsed, err := time.Parse("2006-01-02", value)
+	require.NoError(t, err)
+	return parsed
+}
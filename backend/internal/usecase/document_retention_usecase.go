@@ -0,0 +1,662 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/audit"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/health"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/observability"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/storage"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Nama job untuk metric dms_job_* dan heartbeat readiness
+const jobDocumentDisposition = "document_disposition"
+
+// systemActorName dipakai di audit log untuk aksi yang dijalankan scheduler
+const systemActorName = "system"
+
+var (
+	// ErrLegalHoldActive dikembalikan saat dokumen/folder yang akan dihapus atau didisposisi berada di bawah legal hold
+	ErrLegalHoldActive = errors.New("dokumen berada di bawah legal hold")
+	// ErrDispositionReviewed dikembalikan saat disposisi yang sudah diputuskan direview ulang
+	ErrDispositionReviewed = errors.New("disposisi sudah direview")
+)
+
+// legalHoldError membungkus ErrLegalHoldActive dengan informasi hold yang memblokir
+func legalHoldError(hold *domain.LegalHoldModel) error {
+	return fmt.Errorf("%w (%s %s: %s)", ErrLegalHoldActive, hold.ResourceType, hold.ResourceName, hold.Reason)
+}
+
+// DocumentRetentionActor adalah user yang menjalankan aksi retensi (untuk audit trail)
+type DocumentRetentionActor struct {
+	UserID    string
+	Username  string
+	IPAddress string
+	UserAgent string
+}
+
+// DispositionRunResult adalah ringkasan satu kali evaluasi retensi
+type DispositionRunResult struct {
+	Archived    int `json:"archived"`     // Dokumen yang diarsipkan dan menunggu review
+	SkippedHeld int `json:"skipped_held"` // Dokumen yang masa retensinya habis tapi berada di bawah legal hold
+}
+
+// DocumentRetentionUseCase interface untuk jadwal retensi, legal hold, dan disposisi dokumen
+type DocumentRetentionUseCase interface {
+	SetRetentionSchedule(docTypeID string, retentionDays *int, basis string, actor DocumentRetentionActor) (*domain.DocumentTypeModel, error)
+
+	PlaceLegalHold(req domain.CreateLegalHoldRequest, actor DocumentRetentionActor) (*domain.LegalHoldModel, error)
+	ReleaseLegalHold(id, note string, actor DocumentRetentionActor) (*domain.LegalHoldModel, error)
+	ListLegalHolds(filter repository.LegalHoldFilter) ([]domain.LegalHoldModel, int64, error)
+
+	// RunDisposition mengarsipkan dokumen yang masa retensinya habis dan membuat disposisi pending_review
+	RunDisposition() (*DispositionRunResult, error)
+	ApproveDisposition(id, note string, actor DocumentRetentionActor) (*domain.DocumentDispositionModel, error)
+	RetainDisposition(id, note string, retainDays int, actor DocumentRetentionActor) (*domain.DocumentDispositionModel, error)
+	ListDispositions(filter repository.DocumentDispositionFilter) ([]domain.DocumentDispositionModel, int64, error)
+	// BuildDispositionReport membuat laporan disposisi (xlsx) untuk auditor
+	BuildDispositionReport(filter repository.DocumentDispositionFilter) ([]byte, error)
+}
+
+type documentRetentionUseCase struct {
+	db              *gorm.DB
+	docTypeRepo     repository.DocumentTypeRepository
+	docRepo         repository.DocumentRepository
+	holdRepo        repository.LegalHoldRepository
+	dispositionRepo repository.DocumentDispositionRepository
+	companyRepo     repository.CompanyRepository
+	getStorage      func() (storage.StorageManager, error)
+	now             func() time.Time
+}
+
+// NewDocumentRetentionUseCaseWithDB creates a new document retention use case with injected DB
+func NewDocumentRetentionUseCaseWithDB(db *gorm.DB) DocumentRetentionUseCase {
+	return &documentRetentionUseCase{
+		db:              db,
+		docTypeRepo:     repository.NewDocumentTypeRepositoryWithDB(db),
+		docRepo:         repository.NewDocumentRepositoryWithDB(db),
+		holdRepo:        repository.NewLegalHoldRepositoryWithDB(db),
+		dispositionRepo: repository.NewDocumentDispositionRepositoryWithDB(db),
+		companyRepo:     repository.NewCompanyRepositoryWithDB(db),
+		getStorage:      storage.GetStorageManager,
+		now:             time.Now,
+	}
+}
+
+// NewDocumentRetentionUseCase creates a new document retention use case with default DB
+func NewDocumentRetentionUseCase() DocumentRetentionUseCase {
+	return NewDocumentRetentionUseCaseWithDB(database.GetDB())
+}
+
+func (uc *documentRetentionUseCase) SetRetentionSchedule(docTypeID string, retentionDays *int, basis string, actor DocumentRetentionActor) (*domain.DocumentTypeModel, error) {
+	docType, err := uc.docTypeRepo.GetByID(docTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if retentionDays != nil && *retentionDays <= 0 {
+		return nil, errors.New("retention_days harus lebih dari 0 (kosongkan untuk simpan permanen)")
+	}
+	if basis == "" {
+		basis = domain.RetentionBasisCreatedAt
+	}
+	if basis != domain.RetentionBasisCreatedAt && basis != domain.RetentionBasisExpiryDate {
+		return nil, fmt.Errorf("retention_basis tidak valid: %s (gunakan created_at atau expiry_date)", basis)
+	}
+
+	oldDays := docType.RetentionDays
+	docType.RetentionDays = retentionDays
+	docType.RetentionBasis = basis
+	if err := uc.docTypeRepo.Update(docType); err != nil {
+		return nil, fmt.Errorf("gagal menyimpan jadwal retensi: %w", err)
+	}
+
+	audit.LogAction(actor.UserID, actor.Username, audit.ActionSetRetentionSchedule, audit.ResourceDocument, docType.ID, actor.IPAddress, actor.UserAgent, audit.StatusSuccess, map[string]interface{}{
+		"document_type":      docType.Name,
+		"old_retention_days": oldDays,
+		"retention_days":     retentionDays,
+		"retention_basis":    basis,
+	})
+	return docType, nil
+}
+
+func (uc *documentRetentionUseCase) PlaceLegalHold(req domain.CreateLegalHoldRequest, actor DocumentRetentionActor) (*domain.LegalHoldModel, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, errors.New("alasan legal hold wajib diisi")
+	}
+
+	var resourceName string
+	switch req.ResourceType {
+	case domain.LegalHoldResourceDocument:
+		doc, err := uc.docRepo.GetDocumentByID(req.ResourceID)
+		if err != nil {
+			return nil, err
+		}
+		resourceName = doc.Name
+	case domain.LegalHoldResourceFolder:
+		folder, err := uc.docRepo.GetFolderByID(req.ResourceID)
+		if err != nil {
+			return nil, err
+		}
+		resourceName = folder.Name
+	case domain.LegalHoldResourceCompany:
+		company, err := uc.companyRepo.GetByID(req.ResourceID)
+		if err != nil {
+			return nil, err
+		}
+		resourceName = company.Name
+	default:
+		return nil, fmt.Errorf("resource_type tidak valid: %s (gunakan document, folder, atau company)", req.ResourceType)
+	}
+
+	now := uc.now()
+	hold := &domain.LegalHoldModel{
+		ID:            uuid.GenerateUUID(),
+		ResourceType:  req.ResourceType,
+		ResourceID:    req.ResourceID,
+		ResourceName:  resourceName,
+		Reason:        reason,
+		CaseReference: strings.TrimSpace(req.CaseReference),
+		PlacedBy:      actor.UserID,
+		PlacedByName:  actor.Username,
+		PlacedAt:      now,
+	}
+	if err := uc.holdRepo.Create(hold); err != nil {
+		return nil, fmt.Errorf("gagal memasang legal hold: %w", err)
+	}
+
+	audit.LogAction(actor.UserID, actor.Username, audit.ActionPlaceLegalHold, audit.ResourceLegalHold, hold.ID, actor.IPAddress, actor.UserAgent, audit.StatusSuccess, map[string]interface{}{
+		"resource_type":  hold.ResourceType,
+		"resource_id":    hold.ResourceID,
+		"resource_name":  hold.ResourceName,
+		"reason":         hold.Reason,
+		"case_reference": hold.CaseReference,
+	})
+	return hold, nil
+}
+
+func (uc *documentRetentionUseCase) ReleaseLegalHold(id, note string, actor DocumentRetentionActor) (*domain.LegalHoldModel, error) {
+	hold, err := uc.holdRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if hold.ReleasedAt != nil {
+		return nil, errors.New("legal hold sudah dilepas")
+	}
+
+	now := uc.now()
+	hold.ReleasedAt = &now
+	hold.ReleasedBy = actor.UserID
+	hold.ReleasedByName = actor.Username
+	hold.ReleaseNote = strings.TrimSpace(note)
+	if err := uc.holdRepo.Update(hold); err != nil {
+		return nil, fmt.Errorf("gagal melepas legal hold: %w", err)
+	}
+
+	audit.LogAction(actor.UserID, actor.Username, audit.ActionReleaseLegalHold, audit.ResourceLegalHold, hold.ID, actor.IPAddress, actor.UserAgent, audit.StatusSuccess, map[string]interface{}{
+		"resource_type": hold.ResourceType,
+		"resource_id":   hold.ResourceID,
+		"resource_name": hold.ResourceName,
+		"note":          hold.ReleaseNote,
+	})
+	return hold, nil
+}
+
+func (uc *documentRetentionUseCase) ListLegalHolds(filter repository.LegalHoldFilter) ([]domain.LegalHoldModel, int64, error) {
+	return uc.holdRepo.List(filter)
+}
+
+func (uc *documentRetentionUseCase) RunDisposition() (*DispositionRunResult, error) {
+	result := &DispositionRunResult{}
+	docTypes, err := uc.docTypeRepo.GetAll(true)
+	if err != nil {
+		return nil, err
+	}
+
+	now := uc.now()
+	for i := range docTypes {
+		docType := &docTypes[i]
+		if docType.RetentionDays == nil {
+			continue
+		}
+
+		var batchErr error
+		err := uc.db.Model(&domain.DocumentModel{}).
+			Where("metadata->>'doc_type' = ?", docType.Name).
+			Where("status IS NULL OR status <> ?", domain.DocumentStatusArchived).
+			FindInBatches(&[]domain.DocumentModel{}, 200, func(tx *gorm.DB, _ int) error {
+				docs := *(tx.Statement.Dest.(*[]domain.DocumentModel))
+				batchErr = uc.archiveExpiredDocuments(docType, docs, now, result)
+				return batchErr
+			}).Error
+		if batchErr != nil {
+			return result, batchErr
+		}
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// archiveExpiredDocuments mengarsipkan dokumen dalam satu batch yang masa retensinya sudah habis
+func (uc *documentRetentionUseCase) archiveExpiredDocuments(docType *domain.DocumentTypeModel, docs []domain.DocumentModel, now time.Time, result *DispositionRunResult) error {
+	expired := make([]domain.DocumentModel, 0, len(docs))
+	expiredAt := make(map[string]time.Time, len(docs))
+	for _, doc := range docs {
+		if at, ok := retentionExpiry(doc, docType); ok && !at.After(now) {
+			expired = append(expired, doc)
+			expiredAt[doc.ID] = at
+		}
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(expired))
+	for _, doc := range expired {
+		ids = append(ids, doc.ID)
+	}
+	open, err := uc.dispositionRepo.ListOpenDocumentIDs(ids, now)
+	if err != nil {
+		return err
+	}
+
+	for i := range expired {
+		doc := &expired[i]
+		if open[doc.ID] {
+			continue
+		}
+		hold, err := uc.holdRepo.FindActiveForDocument(doc)
+		if err != nil {
+			return err
+		}
+		if hold != nil {
+			result.SkippedHeld++
+			continue
+		}
+
+		disposition := &domain.DocumentDispositionModel{
+			ID:                 uuid.GenerateUUID(),
+			DocumentID:         doc.ID,
+			DocumentName:       doc.Name,
+			FileName:           doc.FileName,
+			FolderID:           doc.FolderID,
+			CompanyID:          uc.documentCompanyID(doc),
+			DocumentTypeName:   docType.Name,
+			RetentionDays:      *docType.RetentionDays,
+			RetentionBasis:     docType.RetentionBasis,
+			RetentionExpiredAt: expiredAt[doc.ID],
+			PreviousStatus:     doc.Status,
+			Status:             domain.DispositionStatusPendingReview,
+			ArchivedAt:         now,
+		}
+		if err := uc.dispositionRepo.Archive(disposition); err != nil {
+			return fmt.Errorf("gagal mengarsipkan dokumen %s: %w", doc.ID, err)
+		}
+		result.Archived++
+
+		audit.LogAction("", systemActorName, audit.ActionArchiveDocument, audit.ResourceDocument, doc.ID, "", "", audit.StatusSuccess, map[string]interface{}{
+			"disposition_id":       disposition.ID,
+			"document_name":        doc.Name,
+			"document_type":        docType.Name,
+			"retention_days":       disposition.RetentionDays,
+			"retention_basis":      disposition.RetentionBasis,
+			"retention_expired_at": disposition.RetentionExpiredAt,
+		})
+	}
+	return nil
+}
+
+// retentionExpiry menghitung kapan masa retensi dokumen habis; false jika tidak bisa dihitung
+// (misalnya basis expiry_date tapi dokumen tidak punya tanggal expired)
+func retentionExpiry(doc domain.DocumentModel, docType *domain.DocumentTypeModel) (time.Time, bool) {
+	start := doc.CreatedAt
+	if docType.RetentionBasis == domain.RetentionBasisExpiryDate {
+		expiry := parseDocumentExpiryDate(doc.Metadata)
+		if expiry == nil {
+			return time.Time{}, false
+		}
+		start = *expiry
+	}
+	return start.AddDate(0, 0, *docType.RetentionDays), true
+}
+
+// documentCompanyID mengambil company pemilik dokumen dari folder atau pengurus terkait
+func (uc *documentRetentionUseCase) documentCompanyID(doc *domain.DocumentModel) *string {
+	if doc.FolderID != nil {
+		if folder, err := uc.docRepo.GetFolderByID(*doc.FolderID); err == nil && folder.CompanyID != nil {
+			return folder.CompanyID
+		}
+	}
+	if doc.DirectorID != nil {
+		var director domain.DirectorModel
+		if err := uc.db.Select("id", "company_id").Where("id = ?", *doc.DirectorID).First(&director).Error; err == nil && director.CompanyID != "" {
+			return &director.CompanyID
+		}
+	}
+	return nil
+}
+
+func (uc *documentRetentionUseCase) loadPendingDisposition(id string) (*domain.DocumentDispositionModel, error) {
+	disposition, err := uc.dispositionRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if disposition.Status != domain.DispositionStatusPendingReview {
+		return nil, ErrDispositionReviewed
+	}
+	return disposition, nil
+}
+
+func (uc *documentRetentionUseCase) ApproveDisposition(id, note string, actor DocumentRetentionActor) (*domain.DocumentDispositionModel, error) {
+	disposition, err := uc.loadPendingDisposition(id)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := uc.docRepo.GetDocumentByID(disposition.DocumentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Dokumen yang sudah dipindahkan ke recycle bin tetap didisposisi (dihapus permanen)
+		doc, err = uc.docRepo.GetTrashedDocument(disposition.DocumentID)
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if doc != nil {
+		// Hold bisa dipasang setelah dokumen diarsipkan; cek ulang sebelum menghapus
+		hold, err := uc.holdRepo.FindActiveForDocument(doc)
+		if err != nil {
+			return nil, err
+		}
+		if hold != nil {
+			audit.LogAction(actor.UserID, actor.Username, audit.ActionDisposeDocument, audit.ResourceDocument, doc.ID, actor.IPAddress, actor.UserAgent, audit.StatusFailure, map[string]interface{}{
+				"disposition_id": disposition.ID,
+				"legal_hold_id":  hold.ID,
+				"reason":         "legal_hold",
+			})
+			return nil, legalHoldError(hold)
+		}
+		// Data dihapus dulu; file baru dihapus setelah data dan status disposisi tersimpan,
+		// sehingga kegagalan storage tidak meninggalkan record yang menunjuk ke file yang sudah hilang
+		if err := uc.docRepo.DeleteDocument(doc.ID); err != nil {
+			return nil, fmt.Errorf("gagal menghapus dokumen: %w", err)
+		}
+	} else if note == "" {
+		note = "Dokumen sudah tidak ada saat disposisi disetujui"
+	}
+
+	now := uc.now()
+	disposition.Status = domain.DispositionStatusDeleted
+	disposition.ReviewedBy = actor.UserID
+	disposition.ReviewedByName = actor.Username
+	disposition.ReviewedAt = &now
+	disposition.ReviewNote = strings.TrimSpace(note)
+	disposition.DisposedAt = &now
+	if err := uc.dispositionRepo.Update(disposition); err != nil {
+		return nil, err
+	}
+	if doc != nil {
+		if err := deleteDocumentFile(uc.getStorage, doc); err != nil {
+			// Dokumen sudah terhapus; file yang tertinggal hanya di-log agar bisa dibersihkan manual
+			logger.GetLogger().Error("Failed to delete disposed document file",
+				zap.String("disposition_id", disposition.ID),
+				zap.String("document_id", doc.ID),
+				zap.String("file_path", doc.FilePath),
+				zap.Error(err),
+			)
+		}
+	}
+
+	audit.LogAction(actor.UserID, actor.Username, audit.ActionDisposeDocument, audit.ResourceDocument, disposition.DocumentID, actor.IPAddress, actor.UserAgent, audit.StatusSuccess, map[string]interface{}{
+		"disposition_id": disposition.ID,
+		"document_name":  disposition.DocumentName,
+		"file_name":      disposition.FileName,
+		"document_type":  disposition.DocumentTypeName,
+		"note":           disposition.ReviewNote,
+	})
+	return disposition, nil
+}
+
+// deleteDocumentFile menghapus file dokumen dari storage; file yang sudah tidak ada dianggap sudah terhapus
+func deleteDocumentFile(getStorage func() (storage.StorageManager, error), doc *domain.DocumentModel) error {
+	objectPath, err := storage.ObjectPathFromURL(doc.FilePath)
+	if err != nil {
+		logger.GetLogger().Warn("Skipping file deletion for document with invalid file path",
+			zap.String("document_id", doc.ID),
+			zap.String("file_path", doc.FilePath),
+		)
+		return nil
+	}
+	manager, err := getStorage()
+	if err != nil {
+		return fmt.Errorf("gagal menginisialisasi storage: %w", err)
+	}
+	bucketPath, filename := path.Dir(objectPath), path.Base(objectPath)
+	exists, err := manager.FileExists(bucketPath, filename)
+	if err != nil {
+		return fmt.Errorf("gagal mengecek file dokumen: %w", err)
+	}
+	if !exists {
+		return nil
+	}
+	if err := manager.DeleteFile(bucketPath, filename); err != nil {
+		return fmt.Errorf("gagal menghapus file dokumen: %w", err)
+	}
+	return nil
+}
+
+func (uc *documentRetentionUseCase) RetainDisposition(id, note string, retainDays int, actor DocumentRetentionActor) (*domain.DocumentDispositionModel, error) {
+	if retainDays <= 0 {
+		return nil, errors.New("retain_days harus lebih dari 0")
+	}
+	if strings.TrimSpace(note) == "" {
+		return nil, errors.New("alasan dokumen dipertahankan wajib diisi")
+	}
+	disposition, err := uc.loadPendingDisposition(id)
+	if err != nil {
+		return nil, err
+	}
+
+	previousStatus := disposition.PreviousStatus
+	if previousStatus == "" {
+		previousStatus = "active"
+	}
+	err = uc.db.Model(&domain.DocumentModel{}).
+		Where("id = ? AND status = ?", disposition.DocumentID, domain.DocumentStatusArchived).
+		Update("status", previousStatus).Error
+	if err != nil {
+		return nil, fmt.Errorf("gagal memulihkan status dokumen: %w", err)
+	}
+
+	now := uc.now()
+	retainUntil := now.AddDate(0, 0, retainDays)
+	disposition.Status = domain.DispositionStatusRetained
+	disposition.ReviewedBy = actor.UserID
+	disposition.ReviewedByName = actor.Username
+	disposition.ReviewedAt = &now
+	disposition.ReviewNote = strings.TrimSpace(note)
+	disposition.RetainUntil = &retainUntil
+	if err := uc.dispositionRepo.Update(disposition); err != nil {
+		return nil, err
+	}
+
+	audit.LogAction(actor.UserID, actor.Username, audit.ActionRetainDocument, audit.ResourceDocument, disposition.DocumentID, actor.IPAddress, actor.UserAgent, audit.StatusSuccess, map[string]interface{}{
+		"disposition_id": disposition.ID,
+		"document_name":  disposition.DocumentName,
+		"retain_until":   retainUntil,
+		"note":           disposition.ReviewNote,
+	})
+	return disposition, nil
+}
+
+func (uc *documentRetentionUseCase) ListDispositions(filter repository.DocumentDispositionFilter) ([]domain.DocumentDispositionModel, int64, error) {
+	return uc.dispositionRepo.List(filter)
+}
+
+func (uc *documentRetentionUseCase) BuildDispositionReport(filter repository.DocumentDispositionFilter) ([]byte, error) {
+	filter.Limit, filter.Offset = 0, 0
+	dispositions, _, err := uc.dispositionRepo.List(filter)
+	if err != nil {
+		return nil, err
+	}
+	holds, _, err := uc.holdRepo.List(repository.LegalHoldFilter{Since: filter.Since, Until: filter.Until})
+	if err != nil {
+		return nil, err
+	}
+
+	f := excelize.NewFile()
+	defer func() {
+		if err := f.Close(); err != nil {
+			logger.GetLogger().Warn("Failed to close Excel file", zap.Error(err))
+		}
+	}()
+
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"#E0E0E0"}, Pattern: 1},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	statusCount := map[string]int{}
+	dispositionRows := make([][]interface{}, 0, len(dispositions))
+	for _, d := range dispositions {
+		statusCount[d.Status]++
+		companyID := ""
+		if d.CompanyID != nil {
+			companyID = *d.CompanyID
+		}
+		dispositionRows = append(dispositionRows, []interface{}{
+			d.DocumentID, d.DocumentName, d.FileName, d.DocumentTypeName, companyID,
+			d.RetentionDays, d.RetentionBasis, reportTime(&d.RetentionExpiredAt), reportTime(&d.ArchivedAt),
+			d.Status, d.ReviewedByName, reportTime(d.ReviewedAt), d.ReviewNote, reportTime(d.RetainUntil), reportTime(d.DisposedAt),
+		})
+	}
+	holdRows := make([][]interface{}, 0, len(holds))
+	activeHolds := 0
+	for _, h := range holds {
+		if h.ReleasedAt == nil {
+			activeHolds++
+		}
+		holdRows = append(holdRows, []interface{}{
+			h.ResourceType, h.ResourceID, h.ResourceName, h.Reason, h.CaseReference,
+			h.PlacedByName, reportTime(&h.PlacedAt), h.ReleasedByName, reportTime(h.ReleasedAt), h.ReleaseNote,
+		})
+	}
+
+	period := "Semua"
+	if filter.Since != nil || filter.Until != nil {
+		period = fmt.Sprintf("%s s/d %s", reportTime(filter.Since), reportTime(filter.Until))
+	}
+	summary := [][]interface{}{
+		{"Periode (UTC)", period},
+		{"Dibuat Pada (UTC)", uc.now().UTC().Format("2006-01-02 15:04:05")},
+		{"Jumlah Disposisi", len(dispositions)},
+		{"Menunggu Review", statusCount[domain.DispositionStatusPendingReview]},
+		{"Dihapus", statusCount[domain.DispositionStatusDeleted]},
+		{"Dipertahankan", statusCount[domain.DispositionStatusRetained]},
+		{"Legal Hold (dipasang/dilepas pada periode)", len(holds)},
+		{"Legal Hold Masih Aktif", activeHolds},
+	}
+
+	sheets := []struct {
+		name    string
+		headers []string
+		rows    [][]interface{}
+	}{
+		{"Ringkasan", []string{"Keterangan", "Nilai"}, summary},
+		{"Disposisi", []string{"ID Dokumen", "Nama Dokumen", "Nama File", "Jenis Dokumen", "Company ID", "Retensi (hari)", "Dasar Retensi", "Retensi Habis", "Diarsipkan", "Status", "Reviewer", "Direview", "Catatan Review", "Dipertahankan Sampai", "Dihapus"}, dispositionRows},
+		{"Legal Hold", []string{"Jenis Resource", "Resource ID", "Nama Resource", "Alasan", "Referensi Perkara", "Dipasang Oleh", "Dipasang", "Dilepas Oleh", "Dilepas", "Catatan Pelepasan"}, holdRows},
+	}
+	for i, sheet := range sheets {
+		if i == 0 {
+			if err := f.SetSheetName("Sheet1", sheet.name); err != nil {
+				return nil, err
+			}
+		} else if _, err := f.NewSheet(sheet.name); err != nil {
+			return nil, err
+		}
+		for col, header := range sheet.headers {
+			cell, _ := excelize.CoordinatesToCellName(col+1, 1)
+			if err := f.SetCellValue(sheet.name, cell, header); err != nil {
+				return nil, err
+			}
+			if err := f.SetCellStyle(sheet.name, cell, cell, headerStyle); err != nil {
+				return nil, err
+			}
+		}
+		for row, values := range sheet.rows {
+			cell, _ := excelize.CoordinatesToCellName(1, row+2)
+			if err := f.SetSheetRow(sheet.name, cell, &values); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func reportTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+// StartDocumentDispositionScheduler memulai background job harian yang mengevaluasi retensi dokumen
+func StartDocumentDispositionScheduler() {
+	zapLog := logger.GetLogger()
+	retentionUC := NewDocumentRetentionUseCase()
+	health.RegisterHeartbeat(jobDocumentDisposition, 24*time.Hour, 2*time.Hour)
+
+	runDisposition := func() {
+		health.Beat(jobDocumentDisposition)
+		start := time.Now()
+		result, err := retentionUC.RunDisposition()
+		observability.ObserveJob(jobDocumentDisposition, start, err)
+		if result != nil {
+			observability.AddJobItems(jobDocumentDisposition, "documents_archived", result.Archived)
+			observability.AddJobItems(jobDocumentDisposition, "documents_held", result.SkippedHeld)
+		}
+		if err != nil {
+			zapLog.Error("Document disposition run failed", zap.Error(err))
+		} else if result.Archived > 0 || result.SkippedHeld > 0 {
+			zapLog.Info("Document disposition run completed",
+				zap.Int("documents_archived", result.Archived),
+				zap.Int("documents_held", result.SkippedHeld),
+			)
+		}
+	}
+
+	// Jalankan pertama kali setelah 2 jam, lalu setiap 24 jam
+	// Catatan: ticker dibuat di dalam goroutine agar tidak berhenti saat fungsi ini return
+	go func() {
+		time.Sleep(2 * time.Hour)
+		runDisposition()
+
+		ticker := time.NewTicker(24 * time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			runDisposition()
+		}
+	}()
+}
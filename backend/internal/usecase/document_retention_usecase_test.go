@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/storage"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+	"github.com/repoareta/pedeve-dms-app/backend/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+// TestDocumentRetention_LegalHoldAndDisposition tests arsip otomatis, legal hold, review disposisi, dan laporan
+func TestDocumentRetention_LegalHoldAndDisposition(t *testing.T) {
+	db := helpers.SetupTestDB(t)
+	defer helpers.CleanupTestDB(t, db)
+	require.NoError(t, db.AutoMigrate(&domain.DocumentTypeModel{}, &domain.LegalHoldModel{}, &domain.DocumentDispositionModel{}, &domain.NotificationModel{}))
+
+	store := storage.NewLocalStorageManager(t.TempDir())
+	uc := NewDocumentRetentionUseCaseWithDB(db).(*documentRetentionUseCase)
+	uc.getStorage = func() (storage.StorageManager, error) { return store, nil }
+	docUC := NewDocumentUseCaseWithDB(db)
+	admin := DocumentRetentionActor{UserID: "admin", Username: "admin"}
+
+	docType := &domain.DocumentTypeModel{ID: uuid.GenerateUUID(), Name: "Kontrak", IsActive: true, CreatedBy: "admin"}
+	require.NoError(t, db.Create(docType).Error)
+	days := 30
+	_, err := uc.SetRetentionSchedule(docType.ID, &days, "", admin)
+	require.NoError(t, err)
+
+	company := createTestCompanyForNotification(t, db, nil)
+	root := &domain.DocumentFolderModel{ID: uuid.GenerateUUID(), Name: "Root", CompanyID: &company.ID}
+	sub := &domain.DocumentFolderModel{ID: uuid.GenerateUUID(), Name: "Sub", CompanyID: &company.ID, ParentID: &root.ID}
+	require.NoError(t, db.Create(root).Error)
+	require.NoError(t, db.Create(sub).Error)
+
+	fileURL, err := store.UploadFile("documents", "old.pdf", []byte("x"), "application/pdf")
+	require.NoError(t, err)
+	old := time.Now().AddDate(0, 0, -60)
+	newDoc := func(name, path string, created time.Time) *domain.DocumentModel {
+		doc := &domain.DocumentModel{ID: uuid.GenerateUUID(), FolderID: &sub.ID, Name: name, FileName: name + ".pdf", FilePath: path,
+			MimeType: "application/pdf", Status: "active", Metadata: datatypes.JSON(`{"doc_type":"Kontrak"}`), CreatedAt: created}
+		require.NoError(t, db.Create(doc).Error)
+		return doc
+	}
+	expired := newDoc("expired", fileURL, old)
+	held := newDoc("held", "/api/v1/files/documents/held.pdf", old)
+	fresh := newDoc("fresh", "/api/v1/files/documents/fresh.pdf", time.Now())
+
+	_, err = uc.PlaceLegalHold(domain.CreateLegalHoldRequest{ResourceType: domain.LegalHoldResourceDocument, ResourceID: held.ID, Reason: "Perkara"}, admin)
+	require.NoError(t, err)
+
+	t.Run("Held documents and folders cannot be deleted", func(t *testing.T) {
+		assert.ErrorIs(t, docUC.DeleteDocument(held.ID, "user-1"), ErrLegalHoldActive)
+		assert.ErrorIs(t, docUC.DeleteFolder(root.ID, nil, "superadmin", "user-1"), ErrLegalHoldActive)
+
+		companyHold, err := uc.PlaceLegalHold(domain.CreateLegalHoldRequest{ResourceType: domain.LegalHoldResourceCompany, ResourceID: company.ID, Reason: "Audit"}, admin)
+		require.NoError(t, err)
+		assert.ErrorIs(t, docUC.DeleteDocument(fresh.ID, "user-1"), ErrLegalHoldActive)
+		_, err = uc.ReleaseLegalHold(companyHold.ID, "selesai", admin)
+		require.NoError(t, err)
+	})
+
+	t.Run("Expired documents are archived, held ones skipped", func(t *testing.T) {
+		result, err := uc.RunDisposition()
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Archived)
+		assert.Equal(t, 1, result.SkippedHeld)
+
+		var doc domain.DocumentModel
+		require.NoError(t, db.First(&doc, "id = ?", expired.ID).Error)
+		assert.Equal(t, domain.DocumentStatusArchived, doc.Status)
+
+		again, err := uc.RunDisposition()
+		require.NoError(t, err)
+		assert.Equal(t, 0, again.Archived)
+	})
+
+	dispositions, total, err := uc.ListDispositions(repository.DocumentDispositionFilter{DocumentID: expired.ID})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, total)
+	disposition := dispositions[0]
+	assert.Equal(t, company.ID, *disposition.CompanyID)
+
+	t.Run("Retain restores document and postpones re-evaluation", func(t *testing.T) {
+		retained, err := uc.RetainDisposition(disposition.ID, "masih diperlukan", 10, admin)
+		require.NoError(t, err)
+		assert.Equal(t, domain.DispositionStatusRetained, retained.Status)
+		var doc domain.DocumentModel
+		require.NoError(t, db.First(&doc, "id = ?", expired.ID).Error)
+		assert.Equal(t, "active", doc.Status)
+
+		result, err := uc.RunDisposition()
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Archived)
+
+		_, err = uc.ApproveDisposition(disposition.ID, "", admin)
+		assert.ErrorIs(t, err, ErrDispositionReviewed)
+	})
+
+	t.Run("Approve deletes document and file", func(t *testing.T) {
+		uc.now = func() time.Time { return time.Now().AddDate(0, 0, 11) }
+		result, err := uc.RunDisposition()
+		require.NoError(t, err)
+		require.Equal(t, 1, result.Archived)
+
+		pending, _, err := uc.ListDispositions(repository.DocumentDispositionFilter{Status: domain.DispositionStatusPendingReview})
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+
+		// Hold dipasang setelah diarsipkan: approve ditolak
+		folderHold, err := uc.PlaceLegalHold(domain.CreateLegalHoldRequest{ResourceType: domain.LegalHoldResourceFolder, ResourceID: root.ID, Reason: "Investigasi"}, admin)
+		require.NoError(t, err)
+		_, err = uc.ApproveDisposition(pending[0].ID, "", admin)
+		assert.ErrorIs(t, err, ErrLegalHoldActive)
+		_, err = uc.ReleaseLegalHold(folderHold.ID, "", admin)
+		require.NoError(t, err)
+
+		approved, err := uc.ApproveDisposition(pending[0].ID, "ok", admin)
+		require.NoError(t, err)
+		assert.Equal(t, domain.DispositionStatusDeleted, approved.Status)
+		assert.NotNil(t, approved.DisposedAt)
+
+		var count int64
+		db.Model(&domain.DocumentModel{}).Where("id = ?", expired.ID).Count(&count)
+		assert.Zero(t, count)
+		exists, err := store.FileExists("documents", "old.pdf")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("Storage failure does not block approval", func(t *testing.T) {
+		stale := newDoc("stale", "/api/v1/files/documents/stale.pdf", old)
+		result, err := uc.RunDisposition()
+		require.NoError(t, err)
+		require.Equal(t, 1, result.Archived)
+		pending, _, err := uc.ListDispositions(repository.DocumentDispositionFilter{Status: domain.DispositionStatusPendingReview})
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+
+		uc.getStorage = func() (storage.StorageManager, error) { return nil, errors.New("storage unavailable") }
+		defer func() { uc.getStorage = func() (storage.StorageManager, error) { return store, nil } }()
+
+		approved, err := uc.ApproveDisposition(pending[0].ID, "", admin)
+		require.NoError(t, err)
+		assert.Equal(t, domain.DispositionStatusDeleted, approved.Status)
+		var count int64
+		db.Model(&domain.DocumentModel{}).Where("id = ?", stale.ID).Count(&count)
+		assert.Zero(t, count)
+	})
+
+	report, err := uc.BuildDispositionReport(repository.DocumentDispositionFilter{Status: domain.DispositionStatusPendingReview})
+	require.NoError(t, err)
+	assert.NotEmpty(t, report)
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/observability"
+	"github.com/repoareta/pedeve-dms-app/backend/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestUploadDocument_TracingAndMetrics(t *testing.T) {
+	db := helpers.SetupTestDB(t)
+	defer helpers.CleanupTestDB(t, db)
+	require.NoError(t, db.Use(observability.NewGormPlugin()))
+	t.Setenv("UPLOAD_BASE_PATH", t.TempDir())
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	ctx, handlerSpan := observability.StartSpan(context.Background(), "POST /api/v1/documents/upload")
+	doc, err := NewDocumentUseCaseWithDB(db).UploadDocument(UploadDocumentInput{
+		FileName:    "a.pdf",
+		ContentType: "application/pdf",
+		Data:        []byte("%PDF-1.4"),
+		Size:        8,
+		Status:      "active",
+		Context:     ctx,
+	})
+	handlerSpan.End()
+	require.NoError(t, err)
+	require.NotEmpty(t, doc.ID)
+
+	byName := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		byName[span.Name()] = span
+	}
+	handler := byName["POST /api/v1/documents/upload"]
+	usecaseSpan := byName["DocumentUseCase.UploadDocument"]
+	require.NotNil(t, handler)
+	require.NotNil(t, usecaseSpan)
+	require.NotNil(t, byName["storage.upload"])
+	require.NotNil(t, byName["gorm.create"])
+	assert.Equal(t, handler.SpanContext().SpanID(), usecaseSpan.Parent().SpanID())
+	assert.Equal(t, usecaseSpan.SpanContext().SpanID(), byName["storage.upload"].Parent().SpanID())
+	assert.Equal(t, usecaseSpan.SpanContext().SpanID(), byName["gorm.create"].Parent().SpanID())
+
+	rec := httptest.NewRecorder()
+	observability.MetricsHTTPHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	assert.Contains(t, string(body), `dms_storage_operation_duration_seconds_count{backend="local",operation="upload",status="success"}`)
+	assert.Contains(t, string(body), `dms_db_query_duration_seconds_count{operation="create",table="documents"}`)
+}
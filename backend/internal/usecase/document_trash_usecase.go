@@ -0,0 +1,421 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/audit"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/health"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/observability"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/storage"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Nama job untuk metric dms_job_* dan heartbeat readiness
+const jobDocumentTrashPurge = "document_trash_purge"
+
+// DefaultDocumentTrashRetentionDays adalah lama item disimpan di recycle bin sebelum di-purge otomatis
+const DefaultDocumentTrashRetentionDays = 30
+
+var (
+	// ErrTrashItemNotFound dikembalikan saat item tidak ada di recycle bin
+	// (termasuk isi folder yang terhapus; restore/purge dilakukan lewat folder yang dihapus)
+	ErrTrashItemNotFound = errors.New("item tidak ditemukan di recycle bin")
+	// ErrTrashParentDeleted dikembalikan saat restore item yang folder induknya masih di recycle bin
+	ErrTrashParentDeleted = errors.New("folder induk masih berada di recycle bin, restore folder induk terlebih dahulu")
+	// ErrInvalidTrashItemType dikembalikan untuk tipe item selain folder atau document
+	ErrInvalidTrashItemType = errors.New("tipe item tidak valid (gunakan folder atau document)")
+)
+
+// GetDocumentTrashRetentionDays mengambil masa simpan recycle bin dari environment variable
+func GetDocumentTrashRetentionDays() int {
+	return positiveEnvInt("DOCUMENT_TRASH_RETENTION_DAYS", DefaultDocumentTrashRetentionDays)
+}
+
+// DocumentTrashActor adalah user yang me-restore atau mem-purge item recycle bin (untuk audit trail)
+type DocumentTrashActor struct {
+	UserID    string
+	Username  string
+	IPAddress string
+	UserAgent string
+}
+
+// TrashPurgeResult adalah ringkasan satu kali purge otomatis
+type TrashPurgeResult struct {
+	Purged      int `json:"purged"`       // Item yang dihapus permanen
+	SkippedHeld int `json:"skipped_held"` // Item yang sudah kedaluwarsa tapi berada di bawah legal hold
+	Failed      int `json:"failed"`       // Item yang gagal di-purge (dicoba lagi pada run berikutnya)
+}
+
+// DocumentTrashUseCase interface untuk recycle bin dokumen dan folder
+type DocumentTrashUseCase interface {
+	// ListTrash mengembalikan item recycle bin; companyID nil untuk semua company
+	ListTrash(companyID *string) ([]domain.DocumentTrashItem, error)
+	GetTrashItem(itemType, id string) (*domain.DocumentTrashItem, error)
+	Restore(itemType, id string, actor DocumentTrashActor) (*domain.DocumentTrashItem, error)
+	// Purge menghapus item beserta file di storage secara permanen
+	Purge(itemType, id string, actor DocumentTrashActor) (*domain.DocumentTrashItem, error)
+	PurgeExpired() (*TrashPurgeResult, error)
+}
+
+type documentTrashUseCase struct {
+	db         *gorm.DB
+	docRepo    repository.DocumentRepository
+	holdRepo   repository.LegalHoldRepository
+	getStorage func() (storage.StorageManager, error)
+	now        func() time.Time
+}
+
+// NewDocumentTrashUseCaseWithDB creates a new document trash use case with injected DB
+func NewDocumentTrashUseCaseWithDB(db *gorm.DB) DocumentTrashUseCase {
+	return &documentTrashUseCase{
+		db:         db,
+		docRepo:    repository.NewDocumentRepositoryWithDB(db),
+		holdRepo:   repository.NewLegalHoldRepositoryWithDB(db),
+		getStorage: storage.GetStorageManager,
+		now:        time.Now,
+	}
+}
+
+// NewDocumentTrashUseCase creates a new document trash use case with default DB
+func NewDocumentTrashUseCase() DocumentTrashUseCase {
+	return NewDocumentTrashUseCaseWithDB(database.GetDB())
+}
+
+func (uc *documentTrashUseCase) ListTrash(companyID *string) ([]domain.DocumentTrashItem, error) {
+	folders, docs, err := uc.docRepo.ListTrash(companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]domain.DocumentTrashItem, 0, len(folders)+len(docs))
+	for i := range folders {
+		items = append(items, uc.folderItem(&folders[i]))
+	}
+	for i := range docs {
+		items = append(items, uc.documentItem(&docs[i]))
+	}
+	if err := uc.fillStats(items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (uc *documentTrashUseCase) GetTrashItem(itemType, id string) (*domain.DocumentTrashItem, error) {
+	var item domain.DocumentTrashItem
+	switch itemType {
+	case domain.TrashItemFolder:
+		folder, err := uc.docRepo.GetTrashedFolder(id)
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && folder.TrashRootID != folder.ID) {
+			return nil, ErrTrashItemNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		item = uc.folderItem(folder)
+	case domain.TrashItemDocument:
+		doc, err := uc.docRepo.GetTrashedDocument(id)
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && doc.TrashRootID != doc.ID) {
+			return nil, ErrTrashItemNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		item = uc.documentItem(doc)
+	default:
+		return nil, ErrInvalidTrashItemType
+	}
+
+	items := []domain.DocumentTrashItem{item}
+	if err := uc.fillStats(items); err != nil {
+		return nil, err
+	}
+	return &items[0], nil
+}
+
+func (uc *documentTrashUseCase) Restore(itemType, id string, actor DocumentTrashActor) (*domain.DocumentTrashItem, error) {
+	item, err := uc.GetTrashItem(itemType, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Item hanya bisa dikembalikan ke folder induk yang masih aktif
+	if item.ParentID != nil {
+		if _, err := uc.docRepo.GetFolderByID(*item.ParentID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrTrashParentDeleted
+			}
+			return nil, err
+		}
+	}
+
+	if err := uc.docRepo.RestoreTrash(item.ID); err != nil {
+		return nil, fmt.Errorf("gagal me-restore item: %w", err)
+	}
+
+	// Saat folder root company dihapus, folder kosong dibuat otomatis; hapus lagi jika masih kosong agar tidak dobel
+	if item.Type == domain.TrashItemFolder && item.ParentID == nil && item.CompanyID != nil {
+		uc.removePlaceholderRootFolder(*item.CompanyID, item.ID)
+	}
+
+	audit.LogAction(actor.UserID, actor.Username, audit.ActionRestoreDocument, trashAuditResource(item), item.ID, actor.IPAddress, actor.UserAgent, audit.StatusSuccess, map[string]interface{}{
+		"item_type":      item.Type,
+		"name":           item.Name,
+		"company_id":     item.CompanyID,
+		"deleted_by":     item.DeletedBy,
+		"deleted_at":     item.DeletedAt,
+		"folder_count":   item.FolderCount,
+		"document_count": item.DocumentCount,
+	})
+	return item, nil
+}
+
+// removePlaceholderRootFolder menghapus folder root kosong yang dibuat sistem setelah folder root company dihapus
+func (uc *documentTrashUseCase) removePlaceholderRootFolder(companyID, restoredID string) {
+	var placeholders []domain.DocumentFolderModel
+	err := uc.db.Where("company_id = ? AND parent_id IS NULL AND id <> ? AND (created_by = '' OR created_by IS NULL)", companyID, restoredID).
+		Find(&placeholders).Error
+	if err != nil {
+		logger.GetLogger().Warn("Failed to look up placeholder root folder", zap.Error(err), zap.String("company_id", companyID))
+		return
+	}
+	for _, folder := range placeholders {
+		// Isi yang ada di recycle bin juga dihitung agar tidak kehilangan folder induknya
+		var children, docs int64
+		if err := uc.db.Unscoped().Model(&domain.DocumentFolderModel{}).Where("parent_id = ?", folder.ID).Count(&children).Error; err != nil || children > 0 {
+			continue
+		}
+		if err := uc.db.Unscoped().Model(&domain.DocumentModel{}).Where("folder_id = ?", folder.ID).Count(&docs).Error; err != nil || docs > 0 {
+			continue
+		}
+		if err := uc.db.Unscoped().Delete(&domain.DocumentFolderModel{}, "id = ?", folder.ID).Error; err != nil {
+			logger.GetLogger().Warn("Failed to remove placeholder root folder", zap.Error(err), zap.String("folder_id", folder.ID))
+		}
+	}
+}
+
+func (uc *documentTrashUseCase) Purge(itemType, id string, actor DocumentTrashActor) (*domain.DocumentTrashItem, error) {
+	item, err := uc.GetTrashItem(itemType, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.purgeItem(item); err != nil {
+		if errors.Is(err, ErrLegalHoldActive) {
+			audit.LogAction(actor.UserID, actor.Username, audit.ActionPurgeDocument, trashAuditResource(item), item.ID, actor.IPAddress, actor.UserAgent, audit.StatusFailure, map[string]interface{}{
+				"item_type": item.Type,
+				"name":      item.Name,
+				"reason":    "legal_hold",
+				"error":     err.Error(),
+			})
+		}
+		return nil, err
+	}
+
+	audit.LogAction(actor.UserID, actor.Username, audit.ActionPurgeDocument, trashAuditResource(item), item.ID, actor.IPAddress, actor.UserAgent, audit.StatusSuccess, purgeAuditDetails(item, false))
+	return item, nil
+}
+
+func (uc *documentTrashUseCase) PurgeExpired() (*TrashPurgeResult, error) {
+	result := &TrashPurgeResult{}
+	cutoff := uc.now().AddDate(0, 0, -GetDocumentTrashRetentionDays())
+	rootIDs, err := uc.docRepo.ListExpiredTrashRoots(cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rootID := range rootIDs {
+		item, err := uc.GetTrashItem(domain.TrashItemFolder, rootID)
+		if errors.Is(err, ErrTrashItemNotFound) {
+			item, err = uc.GetTrashItem(domain.TrashItemDocument, rootID)
+		}
+		if errors.Is(err, ErrTrashItemNotFound) {
+			// Sudah di-restore atau di-purge sejak query di atas
+			continue
+		}
+		if err == nil {
+			err = uc.purgeItem(item)
+		}
+		if errors.Is(err, ErrLegalHoldActive) {
+			result.SkippedHeld++
+			continue
+		}
+		if err != nil {
+			// Satu item yang gagal tidak menghentikan purge item lain
+			result.Failed++
+			logger.GetLogger().Error("Failed to purge expired trash item", zap.String("trash_item_id", rootID), zap.Error(err))
+			continue
+		}
+		result.Purged++
+		audit.LogAction("", systemActorName, audit.ActionPurgeDocument, trashAuditResource(item), item.ID, "", "", audit.StatusSuccess, purgeAuditDetails(item, true))
+	}
+	return result, nil
+}
+
+// trashAuditResource mengembalikan resource audit item recycle bin supaya riwayat folder tidak tercampur dengan dokumen
+func trashAuditResource(item *domain.DocumentTrashItem) string {
+	if item.Type == domain.TrashItemFolder {
+		return audit.ResourceFolder
+	}
+	return audit.ResourceDocument
+}
+
+// purgeItem menghapus data item (termasuk seluruh isi folder di recycle bin) secara permanen lalu file-nya di storage;
+// item di bawah legal hold tidak di-purge
+func (uc *documentTrashUseCase) purgeItem(item *domain.DocumentTrashItem) error {
+	var hold *domain.LegalHoldModel
+	var err error
+	if item.Type == domain.TrashItemFolder {
+		hold, err = uc.holdRepo.FindActiveForFolderTree(item.ID)
+	} else {
+		var doc *domain.DocumentModel
+		if doc, err = uc.docRepo.GetTrashedDocument(item.ID); err == nil {
+			hold, err = uc.holdRepo.FindActiveForDocument(doc)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if hold != nil {
+		return legalHoldError(hold)
+	}
+
+	docs, err := uc.docRepo.PurgeTrash(item.ID)
+	if err != nil {
+		return err
+	}
+	// File dihapus setelah data terhapus; file yang gagal dihapus hanya di-log agar bisa dibersihkan manual
+	for i := range docs {
+		if err := deleteDocumentFile(uc.getStorage, &docs[i]); err != nil {
+			logger.GetLogger().Error("Failed to delete purged document file",
+				zap.String("trash_item_id", item.ID),
+				zap.String("document_id", docs[i].ID),
+				zap.String("file_path", docs[i].FilePath),
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+func purgeAuditDetails(item *domain.DocumentTrashItem, automatic bool) map[string]interface{} {
+	return map[string]interface{}{
+		"item_type":      item.Type,
+		"name":           item.Name,
+		"company_id":     item.CompanyID,
+		"deleted_by":     item.DeletedBy,
+		"deleted_at":     item.DeletedAt,
+		"folder_count":   item.FolderCount,
+		"document_count": item.DocumentCount,
+		"total_size":     item.TotalSize,
+		"automatic":      automatic,
+	}
+}
+
+func (uc *documentTrashUseCase) folderItem(folder *domain.DocumentFolderModel) domain.DocumentTrashItem {
+	return domain.DocumentTrashItem{
+		Type:      domain.TrashItemFolder,
+		ID:        folder.ID,
+		Name:      folder.Name,
+		CompanyID: folder.CompanyID,
+		ParentID:  folder.ParentID,
+		DeletedAt: folder.DeletedAt.Time,
+		DeletedBy: folder.DeletedBy,
+	}
+}
+
+func (uc *documentTrashUseCase) documentItem(doc *domain.DocumentModel) domain.DocumentTrashItem {
+	return domain.DocumentTrashItem{
+		Type:      domain.TrashItemDocument,
+		ID:        doc.ID,
+		Name:      doc.Name,
+		CompanyID: uc.trashedDocumentCompanyID(doc),
+		ParentID:  doc.FolderID,
+		DeletedAt: doc.DeletedAt.Time,
+		DeletedBy: doc.DeletedBy,
+	}
+}
+
+// trashedDocumentCompanyID mengambil company pemilik dokumen dari folder (termasuk folder di recycle bin) atau pengurus terkait
+func (uc *documentTrashUseCase) trashedDocumentCompanyID(doc *domain.DocumentModel) *string {
+	if doc.FolderID != nil {
+		var folder domain.DocumentFolderModel
+		if err := uc.db.Unscoped().Select("id", "company_id").Where("id = ?", *doc.FolderID).First(&folder).Error; err == nil && folder.CompanyID != nil {
+			return folder.CompanyID
+		}
+	}
+	if doc.DirectorID != nil {
+		var director domain.DirectorModel
+		if err := uc.db.Select("id", "company_id").Where("id = ?", *doc.DirectorID).First(&director).Error; err == nil && director.CompanyID != "" {
+			return &director.CompanyID
+		}
+	}
+	return nil
+}
+
+// fillStats mengisi jumlah isi dan jadwal purge otomatis setiap item
+func (uc *documentTrashUseCase) fillStats(items []domain.DocumentTrashItem) error {
+	rootIDs := make([]string, 0, len(items))
+	for _, item := range items {
+		rootIDs = append(rootIDs, item.ID)
+	}
+	stats, err := uc.docRepo.GetTrashStats(rootIDs)
+	if err != nil {
+		return err
+	}
+	retentionDays := GetDocumentTrashRetentionDays()
+	for i := range items {
+		stat := stats[items[i].ID]
+		items[i].FolderCount = stat.FolderCount
+		items[i].DocumentCount = stat.DocumentCount
+		items[i].TotalSize = stat.TotalSize
+		items[i].PurgeAt = items[i].DeletedAt.AddDate(0, 0, retentionDays)
+	}
+	return nil
+}
+
+// StartDocumentTrashPurge menjalankan purge otomatis item recycle bin yang melewati DOCUMENT_TRASH_RETENTION_DAYS
+func StartDocumentTrashPurge() {
+	zapLog := logger.GetLogger()
+	trashUC := NewDocumentTrashUseCase()
+	health.RegisterHeartbeat(jobDocumentTrashPurge, 24*time.Hour, time.Hour)
+
+	runPurge := func() {
+		health.Beat(jobDocumentTrashPurge)
+		start := time.Now()
+		result, err := trashUC.PurgeExpired()
+		observability.ObserveJob(jobDocumentTrashPurge, start, err)
+		if result != nil {
+			observability.AddJobItems(jobDocumentTrashPurge, "items_purged", result.Purged)
+			observability.AddJobItems(jobDocumentTrashPurge, "items_held", result.SkippedHeld)
+			observability.AddJobItems(jobDocumentTrashPurge, "items_failed", result.Failed)
+		}
+		if err != nil {
+			zapLog.Error("Document trash purge failed", zap.Error(err))
+		} else if result.Purged > 0 || result.SkippedHeld > 0 || result.Failed > 0 {
+			zapLog.Info("Document trash purge completed",
+				zap.Int("items_purged", result.Purged),
+				zap.Int("items_held", result.SkippedHeld),
+				zap.Int("items_failed", result.Failed),
+			)
+		}
+	}
+
+	// Jalankan pertama kali setelah 1 jam, lalu setiap 24 jam
+	// Catatan: ticker dibuat di dalam goroutine agar tidak berhenti saat fungsi ini return
+	go func() {
+		time.Sleep(time.Hour)
+		runPurge()
+
+		ticker := time.NewTicker(24 * time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			runPurge()
+		}
+	}()
+}
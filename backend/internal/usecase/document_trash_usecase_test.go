@@ -0,0 +1,274 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/storage"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+	"github.com/repoareta/pedeve-dms-app/backend/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDocumentTrash_DeleteRestorePurge tests soft delete folder/dokumen, restore, purge manual, dan purge otomatis
+func TestDocumentTrash_DeleteRestorePurge(t *testing.T) {
+	db := helpers.SetupTestDB(t)
+	defer helpers.CleanupTestDB(t, db)
+	require.NoError(t, db.AutoMigrate(&domain.LegalHoldModel{}, &domain.NotificationModel{}))
+
+	store := storage.NewLocalStorageManager(t.TempDir())
+	uc := NewDocumentTrashUseCaseWithDB(db).(*documentTrashUseCase)
+	uc.getStorage = func() (storage.StorageManager, error) { return store, nil }
+	docUC := NewDocumentUseCaseWithDB(db)
+	admin := DocumentTrashActor{UserID: "admin", Username: "admin"}
+
+	company := createTestCompanyForNotification(t, db, nil)
+	root := &domain.DocumentFolderModel{ID: uuid.GenerateUUID(), Name: company.Name, CompanyID: &company.ID, CreatedBy: "admin"}
+	sub := &domain.DocumentFolderModel{ID: uuid.GenerateUUID(), Name: "Sub", CompanyID: &company.ID, ParentID: &root.ID, CreatedBy: "admin"}
+	require.NoError(t, db.Create(root).Error)
+	require.NoError(t, db.Create(sub).Error)
+
+	newDoc := func(name string, folderID string) *domain.DocumentModel {
+		fileURL, err := store.UploadFile("documents", name+".pdf", []byte("x"), "application/pdf")
+		require.NoError(t, err)
+		doc := &domain.DocumentModel{ID: uuid.GenerateUUID(), FolderID: &folderID, Name: name, FileName: name + ".pdf", FilePath: fileURL,
+			MimeType: "application/pdf", Status: "active", Size: 10}
+		require.NoError(t, db.Create(doc).Error)
+		return doc
+	}
+	inRoot := newDoc("in-root", root.ID)
+	inSub := newDoc("in-sub", sub.ID)
+	single := newDoc("single", sub.ID)
+
+	fileExists := func(name string) bool {
+		exists, err := store.FileExists("documents", name+".pdf")
+		require.NoError(t, err)
+		return exists
+	}
+
+	t.Run("Deleted document moves to trash and keeps its file", func(t *testing.T) {
+		require.NoError(t, docUC.DeleteDocument(single.ID, "user-1"))
+		_, err := docUC.GetDocumentByID(single.ID)
+		assert.Error(t, err)
+		assert.True(t, fileExists("single"))
+
+		items, err := uc.ListTrash(&company.ID)
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, domain.TrashItemDocument, items[0].Type)
+		assert.Equal(t, "user-1", items[0].DeletedBy)
+		assert.Equal(t, company.ID, *items[0].CompanyID)
+	})
+
+	t.Run("Deleted root folder trashes its tree as one item", func(t *testing.T) {
+		require.NoError(t, docUC.DeleteFolder(root.ID, nil, "superadmin", "user-1"))
+		_, err := docUC.GetDocumentByID(inSub.ID)
+		assert.Error(t, err)
+
+		// Folder root pengganti dibuat otomatis
+		folders, err := docUC.ListFolders(&company.ID)
+		require.NoError(t, err)
+		require.Len(t, folders, 1)
+		assert.NotEqual(t, root.ID, folders[0].ID)
+
+		items, err := uc.ListTrash(&company.ID)
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		folderItem, err := uc.GetTrashItem(domain.TrashItemFolder, root.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), folderItem.FolderCount)
+		assert.Equal(t, int64(2), folderItem.DocumentCount)
+
+		// Isi folder tidak bisa di-restore sendiri
+		_, err = uc.Restore(domain.TrashItemFolder, sub.ID, admin)
+		assert.ErrorIs(t, err, ErrTrashItemNotFound)
+	})
+
+	t.Run("Document in trashed folder cannot be restored before its folder", func(t *testing.T) {
+		_, err := uc.Restore(domain.TrashItemDocument, single.ID, admin)
+		assert.ErrorIs(t, err, ErrTrashParentDeleted)
+	})
+
+	t.Run("Restore folder brings back its tree and removes the placeholder", func(t *testing.T) {
+		_, err := uc.Restore(domain.TrashItemFolder, root.ID, admin)
+		require.NoError(t, err)
+
+		folders, err := docUC.ListFolders(&company.ID)
+		require.NoError(t, err)
+		assert.Len(t, folders, 2)
+		restored, err := docUC.GetDocumentByID(inRoot.ID)
+		require.NoError(t, err)
+		assert.Empty(t, restored.TrashRootID)
+		_, err = docUC.GetDocumentByID(inSub.ID)
+		require.NoError(t, err)
+
+		// Dokumen yang dihapus terpisah tetap di recycle bin
+		items, err := uc.ListTrash(&company.ID)
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, single.ID, items[0].ID)
+	})
+
+	t.Run("Purge removes rows and files, legal hold blocks purge", func(t *testing.T) {
+		hold := &domain.LegalHoldModel{ID: uuid.GenerateUUID(), ResourceType: domain.LegalHoldResourceFolder, ResourceID: sub.ID,
+			ResourceName: sub.Name, Reason: "Perkara", PlacedAt: time.Now()}
+		require.NoError(t, db.Create(hold).Error)
+		_, err := uc.Purge(domain.TrashItemDocument, single.ID, admin)
+		assert.ErrorIs(t, err, ErrLegalHoldActive)
+		assert.True(t, fileExists("single"))
+
+		now := time.Now()
+		require.NoError(t, db.Model(hold).Update("released_at", now).Error)
+		_, err = uc.Purge(domain.TrashItemDocument, single.ID, admin)
+		require.NoError(t, err)
+		assert.False(t, fileExists("single"))
+		var count int64
+		require.NoError(t, db.Unscoped().Model(&domain.DocumentModel{}).Where("id = ?", single.ID).Count(&count).Error)
+		assert.Zero(t, count)
+	})
+
+	t.Run("Expired trash items are purged automatically", func(t *testing.T) {
+		require.NoError(t, docUC.DeleteFolder(sub.ID, nil, "superadmin", "user-1"))
+
+		result, err := uc.PurgeExpired()
+		require.NoError(t, err)
+		assert.Zero(t, result.Purged)
+
+		uc.now = func() time.Time { return time.Now().AddDate(0, 0, DefaultDocumentTrashRetentionDays+1) }
+		result, err = uc.PurgeExpired()
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Purged)
+		assert.False(t, fileExists("in-sub"))
+		assert.True(t, fileExists("in-root"))
+
+		var count int64
+		require.NoError(t, db.Unscoped().Model(&domain.DocumentFolderModel{}).Where("id = ?", sub.ID).Count(&count).Error)
+		assert.Zero(t, count)
+	})
+}
+
+// TestDocumentTrash_PurgeParentAfterChild tests purge folder yang sub folder/dokumennya lebih dulu dihapus sebagai item sendiri
+func TestDocumentTrash_PurgeParentAfterChild(t *testing.T) {
+	db := helpers.SetupTestDB(t)
+	defer helpers.CleanupTestDB(t, db)
+	require.NoError(t, db.AutoMigrate(&domain.LegalHoldModel{}, &domain.NotificationModel{}))
+	// Satu koneksi supaya PRAGMA berlaku untuk semua query (termasuk transaksi purge)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.Exec("PRAGMA foreign_keys = ON").Error)
+
+	store := storage.NewLocalStorageManager(t.TempDir())
+	uc := NewDocumentTrashUseCaseWithDB(db).(*documentTrashUseCase)
+	uc.getStorage = func() (storage.StorageManager, error) { return store, nil }
+	docUC := NewDocumentUseCaseWithDB(db)
+	admin := DocumentTrashActor{UserID: "admin", Username: "admin"}
+
+	company := createTestCompanyForNotification(t, db, nil)
+	parent := &domain.DocumentFolderModel{ID: uuid.GenerateUUID(), Name: "Parent", CompanyID: &company.ID, CreatedBy: "admin"}
+	child := &domain.DocumentFolderModel{ID: uuid.GenerateUUID(), Name: "Child", CompanyID: &company.ID, ParentID: &parent.ID, CreatedBy: "admin"}
+	grandchild := &domain.DocumentFolderModel{ID: uuid.GenerateUUID(), Name: "Grandchild", CompanyID: &company.ID, ParentID: &child.ID, CreatedBy: "admin"}
+	require.NoError(t, db.Create(parent).Error)
+	require.NoError(t, db.Create(child).Error)
+	require.NoError(t, db.Create(grandchild).Error)
+
+	newDoc := func(name string, folderID string) *domain.DocumentModel {
+		fileURL, err := store.UploadFile("documents", name+".pdf", []byte("x"), "application/pdf")
+		require.NoError(t, err)
+		doc := &domain.DocumentModel{ID: uuid.GenerateUUID(), FolderID: &folderID, Name: name, FileName: name + ".pdf", FilePath: fileURL,
+			MimeType: "application/pdf", Status: "active", Size: 10}
+		require.NoError(t, db.Create(doc).Error)
+		return doc
+	}
+	inParent := newDoc("in-parent", parent.ID)
+	inGrandchild := newDoc("in-grandchild", grandchild.ID)
+
+	// Anak dihapus lebih dulu (item sendiri), baru induknya
+	require.NoError(t, docUC.DeleteFolder(child.ID, nil, "superadmin", "user-1"))
+	require.NoError(t, docUC.DeleteDocument(inParent.ID, "user-1"))
+	require.NoError(t, docUC.DeleteFolder(parent.ID, nil, "superadmin", "user-1"))
+
+	t.Run("Legal hold on a separately trashed child blocks purge of the parent", func(t *testing.T) {
+		hold := &domain.LegalHoldModel{ID: uuid.GenerateUUID(), ResourceType: domain.LegalHoldResourceDocument, ResourceID: inGrandchild.ID,
+			ResourceName: inGrandchild.Name, Reason: "Perkara", PlacedAt: time.Now()}
+		require.NoError(t, db.Create(hold).Error)
+		_, err := uc.Purge(domain.TrashItemFolder, parent.ID, admin)
+		assert.ErrorIs(t, err, ErrLegalHoldActive)
+		require.NoError(t, db.Model(hold).Update("released_at", time.Now()).Error)
+	})
+
+	t.Run("Purging the parent removes the whole subtree", func(t *testing.T) {
+		_, err := uc.Purge(domain.TrashItemFolder, parent.ID, admin)
+		require.NoError(t, err)
+
+		var folders, docs int64
+		require.NoError(t, db.Unscoped().Model(&domain.DocumentFolderModel{}).Where("id IN ?", []string{parent.ID, child.ID, grandchild.ID}).Count(&folders).Error)
+		require.NoError(t, db.Unscoped().Model(&domain.DocumentModel{}).Where("id IN ?", []string{inParent.ID, inGrandchild.ID}).Count(&docs).Error)
+		assert.Zero(t, folders)
+		assert.Zero(t, docs)
+		for _, name := range []string{"in-parent", "in-grandchild"} {
+			exists, err := store.FileExists("documents", name+".pdf")
+			require.NoError(t, err)
+			assert.False(t, exists)
+		}
+
+		// Item anak ikut hilang dari recycle bin, purge otomatis tidak mencoba ulang
+		items, err := uc.ListTrash(&company.ID)
+		require.NoError(t, err)
+		assert.Empty(t, items)
+		roots, err := uc.docRepo.ListExpiredTrashRoots(time.Now().AddDate(1, 0, 0))
+		require.NoError(t, err)
+		assert.Empty(t, roots)
+	})
+}
+
+// failingPurgeRepo menggagalkan purge satu item tertentu
+type failingPurgeRepo struct {
+	repository.DocumentRepository
+	failID string
+}
+
+func (r failingPurgeRepo) PurgeTrash(rootID string) ([]domain.DocumentModel, error) {
+	if rootID == r.failID {
+		return nil, errors.New("database unavailable")
+	}
+	return r.DocumentRepository.PurgeTrash(rootID)
+}
+
+// TestDocumentTrash_PurgeExpiredContinuesOnFailure tests satu item yang gagal di-purge tidak menghentikan item lain
+func TestDocumentTrash_PurgeExpiredContinuesOnFailure(t *testing.T) {
+	db := helpers.SetupTestDB(t)
+	defer helpers.CleanupTestDB(t, db)
+	require.NoError(t, db.AutoMigrate(&domain.LegalHoldModel{}, &domain.NotificationModel{}))
+
+	uc := NewDocumentTrashUseCaseWithDB(db).(*documentTrashUseCase)
+	uc.getStorage = func() (storage.StorageManager, error) { return storage.NewLocalStorageManager(t.TempDir()), nil }
+	docUC := NewDocumentUseCaseWithDB(db)
+
+	company := createTestCompanyForNotification(t, db, nil)
+	folder := &domain.DocumentFolderModel{ID: uuid.GenerateUUID(), Name: "Folder", CompanyID: &company.ID, CreatedBy: "admin"}
+	require.NoError(t, db.Create(folder).Error)
+	var ids []string
+	for _, name := range []string{"a", "b", "c"} {
+		doc := &domain.DocumentModel{ID: uuid.GenerateUUID(), FolderID: &folder.ID, Name: name, FileName: name + ".pdf",
+			FilePath: "/api/v1/files/documents/" + name + ".pdf", MimeType: "application/pdf", Status: "active"}
+		require.NoError(t, db.Create(doc).Error)
+		require.NoError(t, docUC.DeleteDocument(doc.ID, "user-1"))
+		ids = append(ids, doc.ID)
+	}
+
+	uc.docRepo = failingPurgeRepo{DocumentRepository: uc.docRepo, failID: ids[1]}
+	uc.now = func() time.Time { return time.Now().AddDate(0, 0, DefaultDocumentTrashRetentionDays+1) }
+	result, err := uc.PurgeExpired()
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Purged)
+	assert.Equal(t, 1, result.Failed)
+
+	var remaining []string
+	require.NoError(t, db.Unscoped().Model(&domain.DocumentModel{}).Where("id IN ?", ids).Pluck("id", &remaining).Error)
+	assert.Equal(t, []string{ids[1]}, remaining)
+}
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
@@ -8,9 +9,12 @@ import (
 	"time"
 
 	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/observability"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/storage"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+	"go.uber.org/zap"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
@@ -20,14 +24,16 @@ type DocumentUseCase interface {
 	CreateFolder(name string, companyID *string, parentID *string, createdBy string) (*domain.DocumentFolderModel, error)
 	GetFolderByID(id string) (*domain.DocumentFolderModel, error)
 	UpdateFolderName(id string, name string, requesterCompanyID *string, roleName string) (*domain.DocumentFolderModel, error)
-	DeleteFolder(id string, requesterCompanyID *string, roleName string) error
+	// DeleteFolder memindahkan folder beserta isinya ke recycle bin
+	DeleteFolder(id string, requesterCompanyID *string, roleName, deletedBy string) error
 	ListDocuments(folderID *string) ([]domain.DocumentModel, error)
 	ListDocumentsPaginated(params ListDocumentsParams) ([]domain.DocumentModel, int64, error)
 	GetDocumentSummary(companyID *string) ([]domain.DocumentFolderStat, int64, error)
 	GetDocumentByID(id string) (*domain.DocumentModel, error)
 	UploadDocument(input UploadDocumentInput) (*domain.DocumentModel, error)
 	UpdateDocument(id string, input UpdateDocumentInput) (*domain.DocumentModel, error)
-	DeleteDocument(id string) error
+	// DeleteDocument memindahkan dokumen ke recycle bin
+	DeleteDocument(id, deletedBy string) error
 }
 
 type UploadDocumentInput struct {
@@ -41,6 +47,7 @@ type UploadDocumentInput struct {
 	Status      string
 	UploaderID  string
 	Metadata    map[string]interface{}
+	Context     context.Context // Context request untuk tracing (opsional)
 }
 
 type UpdateDocumentInput struct {
@@ -53,6 +60,7 @@ type UpdateDocumentInput struct {
 	FileContentType *string
 	FileData        []byte
 	FileSize        *int64
+	UpdatedBy       string // User yang mengubah (dicatat saat rantai eskalasi ditutup karena dokumen diperbarui)
 }
 
 type ListDocumentsParams struct {
@@ -69,29 +77,41 @@ type ListDocumentsParams struct {
 }
 
 type documentUseCase struct {
-	docRepo     repository.DocumentRepository
-	companyRepo repository.CompanyRepository
+	docRepo        repository.DocumentRepository
+	companyRepo    repository.CompanyRepository
+	directorRepo   repository.DirectorRepository
+	escalationRepo repository.NotificationEscalationRepository
+	holdRepo       repository.LegalHoldRepository
 }
 
 func NewDocumentUseCase() DocumentUseCase {
 	return &documentUseCase{
-		docRepo:     repository.NewDocumentRepository(),
-		companyRepo: repository.NewCompanyRepository(),
+		docRepo:        repository.NewDocumentRepository(),
+		companyRepo:    repository.NewCompanyRepository(),
+		directorRepo:   repository.NewDirectorRepository(),
+		escalationRepo: repository.NewNotificationEscalationRepository(),
+		holdRepo:       repository.NewLegalHoldRepository(),
 	}
 }
 
 func NewDocumentUseCaseWithRepo(repo repository.DocumentRepository) DocumentUseCase {
 	return &documentUseCase{
-		docRepo:     repo,
-		companyRepo: repository.NewCompanyRepository(), // Use default for backward compatibility
+		docRepo:        repo,
+		companyRepo:    repository.NewCompanyRepository(), // Use default for backward compatibility
+		directorRepo:   repository.NewDirectorRepository(),
+		escalationRepo: repository.NewNotificationEscalationRepository(),
+		holdRepo:       repository.NewLegalHoldRepository(),
 	}
 }
 
 // NewDocumentUseCaseWithDB creates a new document use case with injected DB (for testing)
 func NewDocumentUseCaseWithDB(db *gorm.DB) DocumentUseCase {
 	return &documentUseCase{
-		docRepo:     repository.NewDocumentRepositoryWithDB(db),
-		companyRepo: repository.NewCompanyRepositoryWithDB(db),
+		docRepo:        repository.NewDocumentRepositoryWithDB(db),
+		companyRepo:    repository.NewCompanyRepositoryWithDB(db),
+		directorRepo:   repository.NewDirectorRepositoryWithDB(db),
+		escalationRepo: repository.NewNotificationEscalationRepositoryWithDB(db),
+		holdRepo:       repository.NewLegalHoldRepositoryWithDB(db),
 	}
 }
 
@@ -106,6 +126,14 @@ func (uc *documentUseCase) CreateFolder(name string, companyID *string, parentID
 	if createdBy == "" {
 		return nil, fmt.Errorf("creator required")
 	}
+	if companyID != nil && *companyID != "" {
+		if err := ensureCompanyIDNotDissolved(uc.companyRepo, *companyID); err != nil {
+			return nil, err
+		}
+	}
+	if err := uc.ensureDocumentOwnerNotDissolved(parentID, nil); err != nil {
+		return nil, err
+	}
 	folder := &domain.DocumentFolderModel{
 		ID:        uuid.GenerateUUID(),
 		Name:      name,
@@ -146,6 +174,10 @@ func (uc *documentUseCase) UpdateFolderName(id, name string, requesterCompanyID
 		}
 	}
 
+	if err := uc.ensureDocumentOwnerNotDissolved(&folder.ID, nil); err != nil {
+		return nil, err
+	}
+
 	if err := uc.docRepo.UpdateFolderName(id, name); err != nil {
 		return nil, err
 	}
@@ -153,35 +185,7 @@ func (uc *documentUseCase) UpdateFolderName(id, name string, requesterCompanyID
 	return folder, nil
 }
 
-// deleteFolderRecursive menghapus folder beserta semua child folders dan dokumen di dalamnya secara rekursif
-func (uc *documentUseCase) deleteFolderRecursive(folderID string) error {
-	// Ambil child folders dulu
-	childFolders, err := uc.docRepo.GetChildFolders(folderID)
-	if err != nil {
-		return fmt.Errorf("failed to get child folders: %w", err)
-	}
-
-	// Hapus semua child folders secara recursive dulu
-	for _, child := range childFolders {
-		if err := uc.deleteFolderRecursive(child.ID); err != nil {
-			return fmt.Errorf("failed to delete child folder %s: %w", child.ID, err)
-		}
-	}
-
-	// Hapus semua dokumen di dalam folder ini
-	if err := uc.docRepo.DeleteDocumentsByFolder(folderID); err != nil {
-		return fmt.Errorf("failed to delete documents in folder: %w", err)
-	}
-
-	// Finally, delete the folder itself
-	if err := uc.docRepo.DeleteFolder(folderID); err != nil {
-		return fmt.Errorf("failed to delete folder: %w", err)
-	}
-
-	return nil
-}
-
-func (uc *documentUseCase) DeleteFolder(id string, requesterCompanyID *string, roleName string) error {
+func (uc *documentUseCase) DeleteFolder(id string, requesterCompanyID *string, roleName, deletedBy string) error {
 	folder, err := uc.docRepo.GetFolderByID(id)
 	if err != nil {
 		return err
@@ -195,6 +199,19 @@ func (uc *documentUseCase) DeleteFolder(id string, requesterCompanyID *string, r
 		return fmt.Errorf("forbidden: hanya superadmin dan administrator yang dapat menghapus folder")
 	}
 
+	if err := uc.ensureDocumentOwnerNotDissolved(&folder.ID, nil); err != nil {
+		return err
+	}
+
+	// Folder (termasuk sub folder dan dokumennya) yang tercakup legal hold tidak boleh dihapus
+	hold, err := uc.holdRepo.FindActiveForFolderTree(id)
+	if err != nil {
+		return err
+	}
+	if hold != nil {
+		return legalHoldError(hold)
+	}
+
 	// Simpan companyID dan nama folder sebelum dihapus (untuk auto-generate folder baru)
 	var companyIDToRegenerate *string
 	var companyName string
@@ -210,8 +227,8 @@ func (uc *documentUseCase) DeleteFolder(id string, requesterCompanyID *string, r
 		}
 	}
 
-	// Hapus folder secara recursive (termasuk semua child folders dan dokumen)
-	if err := uc.deleteFolderRecursive(id); err != nil {
+	// Pindahkan folder beserta semua child folders dan dokumen ke recycle bin (dapat di-restore sampai di-purge)
+	if err := uc.docRepo.TrashFolderTree(id, deletedBy); err != nil {
 		return err
 	}
 
@@ -236,6 +253,25 @@ func (uc *documentUseCase) DeleteFolder(id string, requesterCompanyID *string, r
 	return nil
 }
 
+// ensureDocumentOwnerNotDissolved menolak perubahan jika company pemilik folder atau direktur sudah dibubarkan
+func (uc *documentUseCase) ensureDocumentOwnerNotDissolved(folderID, directorID *string) error {
+	if folderID != nil && *folderID != "" {
+		if folder, err := uc.docRepo.GetFolderByID(*folderID); err == nil && folder.CompanyID != nil {
+			if err := ensureCompanyIDNotDissolved(uc.companyRepo, *folder.CompanyID); err != nil {
+				return err
+			}
+		}
+	}
+	if directorID != nil && *directorID != "" && uc.directorRepo != nil {
+		if director, err := uc.directorRepo.GetByID(*directorID); err == nil {
+			if err := ensureCompanyIDNotDissolved(uc.companyRepo, director.CompanyID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (uc *documentUseCase) ListDocuments(folderID *string) ([]domain.DocumentModel, error) {
 	return uc.docRepo.ListDocuments(folderID)
 }
@@ -331,11 +367,19 @@ func (uc *documentUseCase) checkReferenceExists(reference string, excludeDocumen
 	return false, nil // Reference is unique
 }
 
-func (uc *documentUseCase) UploadDocument(input UploadDocumentInput) (*domain.DocumentModel, error) {
+func (uc *documentUseCase) UploadDocument(input UploadDocumentInput) (_ *domain.DocumentModel, err error) {
+	ctx, span := observability.StartSpan(input.Context, "DocumentUseCase.UploadDocument")
+	defer func() { observability.EndSpan(span, err) }()
+
 	if input.FileName == "" || len(input.Data) == 0 {
 		return nil, fmt.Errorf("file invalid")
 	}
 
+	// Company yang sudah dibubarkan read-only, termasuk dokumennya
+	if err := uc.ensureDocumentOwnerNotDissolved(input.FolderID, input.DirectorID); err != nil {
+		return nil, err
+	}
+
 	// Validate reference uniqueness if provided
 	if input.Metadata != nil {
 		if reference, ok := input.Metadata["reference"].(string); ok && reference != "" {
@@ -356,7 +400,7 @@ func (uc *documentUseCase) UploadDocument(input UploadDocumentInput) (*domain.Do
 
 	ext := filepath.Ext(input.FileName)
 	newFileName := fmt.Sprintf("%s%s", uuid.GenerateUUID(), ext)
-	fileURL, err := storageManager.UploadFile("documents", newFileName, input.Data, input.ContentType)
+	fileURL, err := storage.UploadFileWithContext(ctx, storageManager, "documents", newFileName, input.Data, input.ContentType)
 	if err != nil {
 		return nil, fmt.Errorf("failed to upload file: %w", err)
 	}
@@ -406,7 +450,7 @@ func (uc *documentUseCase) UploadDocument(input UploadDocumentInput) (*domain.Do
 		UpdatedAt:  time.Now(),
 	}
 
-	if err := uc.docRepo.CreateDocument(doc); err != nil {
+	if err = uc.docRepo.WithContext(ctx).CreateDocument(doc); err != nil {
 		return nil, err
 	}
 
@@ -420,6 +464,14 @@ func (uc *documentUseCase) UpdateDocument(id string, input UpdateDocumentInput)
 		return nil, fmt.Errorf("document not found: %w", err)
 	}
 
+	// Dokumen milik company yang sudah dibubarkan tidak boleh diubah, begitu juga pemindahan ke company tersebut
+	if err := uc.ensureDocumentOwnerNotDissolved(doc.FolderID, doc.DirectorID); err != nil {
+		return nil, err
+	}
+	if err := uc.ensureDocumentOwnerNotDissolved(input.FolderID, input.DirectorID); err != nil {
+		return nil, err
+	}
+
 	// Validasi keunikan reference kalau diisi di metadata update
 	if input.Metadata != nil {
 		if reference, ok := input.Metadata["reference"].(string); ok && reference != "" {
@@ -452,7 +504,8 @@ func (uc *documentUseCase) UpdateDocument(id string, input UpdateDocumentInput)
 	}
 
 	// Optional: update file
-	if len(input.FileData) > 0 && input.FileName != nil {
+	fileReplaced := len(input.FileData) > 0 && input.FileName != nil
+	if fileReplaced {
 		storageManager, err := storage.GetStorageManager()
 		if err != nil {
 			return nil, fmt.Errorf("failed to init storage: %w", err)
@@ -494,9 +547,62 @@ func (uc *documentUseCase) UpdateDocument(id string, input UpdateDocumentInput)
 		return nil, fmt.Errorf("failed to update document: %w", err)
 	}
 
+	// Dokumen yang diperbarui (metadata atau file) dengan tanggal expired yang belum lewat menutup rantai eskalasi yang masih terbuka
+	if input.Metadata != nil || fileReplaced {
+		uc.resolveRenewedDocumentEscalations(doc, input.UpdatedBy)
+	}
+
 	return doc, nil
 }
 
-func (uc *documentUseCase) DeleteDocument(id string) error {
-	return uc.docRepo.DeleteDocument(id)
+// resolveRenewedDocumentEscalations menutup eskalasi dokumen jika tanggal expired ada dan belum lewat.
+// Dokumen tanpa tanggal expired (misalnya field expired_date dikosongkan) tidak dianggap diperpanjang.
+func (uc *documentUseCase) resolveRenewedDocumentEscalations(doc *domain.DocumentModel, updatedBy string) {
+	if uc.escalationRepo == nil {
+		return
+	}
+	expiry := parseDocumentExpiryDate(doc.Metadata)
+	if expiry == nil {
+		return
+	}
+	now := time.Now()
+	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if time.Date(expiry.Year(), expiry.Month(), expiry.Day(), 0, 0, 0, 0, now.Location()).Before(todayStart) {
+		return
+	}
+
+	openEscalations, err := uc.escalationRepo.GetOpenByResource("document", doc.ID)
+	if err != nil {
+		logger.GetLogger().Warn("Failed to get open escalations for document", zap.Error(err), zap.String("document_id", doc.ID))
+		return
+	}
+	var resolvedBy *string
+	if updatedBy != "" {
+		resolvedBy = &updatedBy
+	}
+	for _, esc := range openEscalations {
+		if err := uc.escalationRepo.Resolve(esc.ID, resolvedBy, "Dokumen diperbarui", now); err != nil {
+			logger.GetLogger().Warn("Failed to resolve document escalation", zap.Error(err), zap.String("escalation_id", esc.ID))
+		}
+	}
+}
+
+func (uc *documentUseCase) DeleteDocument(id, deletedBy string) error {
+	doc, err := uc.docRepo.GetDocumentByID(id)
+	if err != nil {
+		return err
+	}
+	if err := uc.ensureDocumentOwnerNotDissolved(doc.FolderID, doc.DirectorID); err != nil {
+		return err
+	}
+	// Dokumen yang tercakup legal hold tidak boleh dihapus
+	hold, err := uc.holdRepo.FindActiveForDocument(doc)
+	if err != nil {
+		return err
+	}
+	if hold != nil {
+		return legalHoldError(hold)
+	}
+	// File di storage tetap disimpan sampai dokumen di-purge dari recycle bin
+	return uc.docRepo.TrashDocument(id, deletedBy)
 }
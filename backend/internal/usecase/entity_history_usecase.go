@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+)
+
+// EntityHistoryFilter mengatur log mana saja yang boleh dilihat oleh requester
+type EntityHistoryFilter struct {
+	UserID             string // Jika diisi, hanya log milik user ini yang ditampilkan (user reguler)
+	HideSuperadminLogs bool   // Sembunyikan log milik superadmin (untuk admin non-superadmin)
+}
+
+// GetEntityHistory membangun riwayat lengkap satu entity dari user activity logs:
+// timeline aksi (create/update/delete) dan riwayat perubahan per field.
+func GetEntityHistory(resource, resourceID string, filter EntityHistoryFilter) (*domain.EntityHistoryResponse, error) {
+	logs, err := repository.GetUserActivityLogsByEntity(resource, resourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &domain.EntityHistoryResponse{
+		Resource:   resource,
+		ResourceID: resourceID,
+		Entries:    []domain.EntityHistoryEntry{},
+		Fields:     make(map[string][]domain.FieldHistoryItem),
+	}
+
+	for _, log := range logs {
+		if filter.UserID != "" && log.UserID != filter.UserID {
+			continue
+		}
+		if filter.HideSuperadminLogs && (log.Username == "" || log.Username == "superadmin") {
+			continue
+		}
+
+		changes := parseDetailChanges(log.Details)
+		response.Entries = append(response.Entries, domain.EntityHistoryEntry{
+			LogID:     log.ID,
+			Action:    log.Action,
+			UserID:    log.UserID,
+			Username:  log.Username,
+			Status:    log.Status,
+			Changes:   changes,
+			CreatedAt: log.CreatedAt,
+		})
+
+		// Hanya perubahan dengan format {"old": ..., "new": ...} yang masuk riwayat per field
+		fields := make([]string, 0, len(changes))
+		for field := range changes {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			change, ok := changes[field].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			oldValue, hasOld := change["old"]
+			newValue, hasNew := change["new"]
+			if !hasOld && !hasNew {
+				continue
+			}
+			response.Fields[field] = append(response.Fields[field], domain.FieldHistoryItem{
+				LogID:     log.ID,
+				Action:    log.Action,
+				UserID:    log.UserID,
+				Username:  log.Username,
+				Old:       oldValue,
+				New:       newValue,
+				ChangedAt: log.CreatedAt,
+			})
+		}
+	}
+
+	return response, nil
+}
+
+// parseDetailChanges mengambil details.changes dari JSON details log (kosong jika tidak ada)
+func parseDetailChanges(details string) map[string]interface{} {
+	if details == "" {
+		return map[string]interface{}{}
+	}
+	var parsed struct {
+		Changes map[string]interface{} `json:"changes"`
+	}
+	if err := json.Unmarshal([]byte(details), &parsed); err != nil || parsed.Changes == nil {
+		return map[string]interface{}{}
+	}
+	return parsed.Changes
+}
@@ -0,0 +1,451 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnknownFinancialMetric = errors.New("unknown financial metric")
+	ErrInvalidAnalyticsPeriod = errors.New("invalid analytics period")
+)
+
+// Batas rentang trend supaya response tetap wajar (10 tahun)
+const financialTrendMaxMonths = 120
+
+// Default rentang trend jika start_period kosong (2 tahun terakhir)
+const financialTrendDefaultMonths = 24
+
+// FinancialAnalyticsUseCase interface untuk analytics realisasi (trend, growth, LTM, forecast).
+// Semua angka dihitung di server dari FinancialReportModel supaya chart frontend dan export Excel identik.
+type FinancialAnalyticsUseCase interface {
+	// GetTrend mengembalikan time series bulanan per metrik beserta MoM, YoY, dan LTM (metrics kosong = semua metrik taksonomi)
+	GetTrend(companyID string, metrics []string, startPeriod, endPeriod string) (*domain.FinancialTrendResponse, error)
+	// GetForecast memproyeksikan realisasi setahun penuh (run-rate dan linear) dibanding RKAP
+	GetForecast(companyID, year string, metrics []string) (*domain.FinancialForecastResponse, error)
+	// ExportAnalyticsExcel menulis hasil GetTrend dan GetForecast (tahun end period) ke Excel
+	ExportAnalyticsExcel(companyID string, metrics []string, startPeriod, endPeriod string) ([]byte, error)
+}
+
+type financialAnalyticsUseCase struct {
+	repo repository.FinancialReportRepository
+}
+
+// NewFinancialAnalyticsUseCaseWithDB creates a new financial analytics use case with injected DB
+func NewFinancialAnalyticsUseCaseWithDB(db *gorm.DB) FinancialAnalyticsUseCase {
+	return &financialAnalyticsUseCase{
+		repo: repository.NewFinancialReportRepositoryWithDB(db),
+	}
+}
+
+// NewFinancialAnalyticsUseCase creates a new financial analytics use case with default DB
+func NewFinancialAnalyticsUseCase() FinancialAnalyticsUseCase {
+	return NewFinancialAnalyticsUseCaseWithDB(database.GetDB())
+}
+
+// financialFieldAggregation menentukan cara agregasi metrik: rasio dirata-rata, saldo neraca diambil bulan terakhir, arus dijumlah
+func financialFieldAggregation(field financialImportField) string {
+	switch {
+	case field.IsFloat:
+		return domain.FinancialAggregationAverage
+	case field.Section == "neraca" || field.Key == "ending_balance":
+		return domain.FinancialAggregationLast
+	default:
+		return domain.FinancialAggregationSum
+	}
+}
+
+// resolveFinancialMetrics mencocokkan nama metrik (key, konsep, atau header taksonomi); kosong = semua metrik
+func resolveFinancialMetrics(names []string) ([]financialImportField, error) {
+	if len(names) == 0 {
+		return financialImportFields, nil
+	}
+	fields := make([]financialImportField, 0, len(names))
+	seen := make(map[int]bool)
+	for _, name := range names {
+		index, ok := lookupFinancialField(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownFinancialMetric, name)
+		}
+		if !seen[index] {
+			seen[index] = true
+			fields = append(fields, financialImportFields[index])
+		}
+	}
+	return fields, nil
+}
+
+// financialMetricValue mengembalikan nilai metrik sebagai float64
+func financialMetricValue(report *domain.FinancialReportModel, field financialImportField) float64 {
+	switch value := financialReportValue(report, field).(type) {
+	case int64:
+		return float64(value)
+	case float64:
+		return value
+	default:
+		return 0
+	}
+}
+
+func parseAnalyticsPeriod(period string) (time.Time, error) {
+	parsed, err := time.Parse("2006-01", period)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s (expected YYYY-MM)", ErrInvalidAnalyticsPeriod, period)
+	}
+	return parsed, nil
+}
+
+func analyticsPeriod(t time.Time) string {
+	return t.Format("2006-01")
+}
+
+// loadRealisasiByPeriod mengambil seluruh realisasi company, di-index per periode YYYY-MM
+func (uc *financialAnalyticsUseCase) loadRealisasiByPeriod(companyID string) (map[string]*domain.FinancialReportModel, string, error) {
+	reports, err := uc.repo.GetByCompanyID(companyID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get financial reports: %w", err)
+	}
+	byPeriod := make(map[string]*domain.FinancialReportModel)
+	latest := ""
+	for i := range reports {
+		// Trend dan forecast berbasis bulan: kuartal/semester/tahunan dan angka audited tidak ikut
+		if !isMonthlyRealisasi(&reports[i]) {
+			continue
+		}
+		byPeriod[reports[i].Period] = &reports[i]
+		if reports[i].Period > latest {
+			latest = reports[i].Period
+		}
+	}
+	return byPeriod, latest, nil
+}
+
+// metricAt mengambil nilai metrik pada periode (nil jika realisasi tidak ada)
+func metricAt(byPeriod map[string]*domain.FinancialReportModel, period string, field financialImportField) *float64 {
+	report, ok := byPeriod[period]
+	if !ok {
+		return nil
+	}
+	value := financialMetricValue(report, field)
+	return &value
+}
+
+func (uc *financialAnalyticsUseCase) GetTrend(companyID string, metrics []string, startPeriod, endPeriod string) (*domain.FinancialTrendResponse, error) {
+	fields, err := resolveFinancialMetrics(metrics)
+	if err != nil {
+		return nil, err
+	}
+	byPeriod, latest, err := uc.loadRealisasiByPeriod(companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Default: periode realisasi terakhir (atau bulan berjalan) mundur 24 bulan
+	if endPeriod == "" {
+		endPeriod = latest
+		if endPeriod == "" {
+			endPeriod = analyticsPeriod(time.Now())
+		}
+	}
+	end, err := parseAnalyticsPeriod(endPeriod)
+	if err != nil {
+		return nil, err
+	}
+	start := end.AddDate(0, -(financialTrendDefaultMonths - 1), 0)
+	if startPeriod != "" {
+		if start, err = parseAnalyticsPeriod(startPeriod); err != nil {
+			return nil, err
+		}
+	}
+	if start.After(end) {
+		return nil, fmt.Errorf("%w: start_period must not be after end_period", ErrInvalidAnalyticsPeriod)
+	}
+	if monthsBetween(start, end) >= financialTrendMaxMonths {
+		return nil, fmt.Errorf("%w: range must not exceed %d months", ErrInvalidAnalyticsPeriod, financialTrendMaxMonths)
+	}
+
+	response := &domain.FinancialTrendResponse{
+		CompanyID:   companyID,
+		StartPeriod: analyticsPeriod(start),
+		EndPeriod:   analyticsPeriod(end),
+		Series:      make([]domain.FinancialTrendSeries, 0, len(fields)),
+	}
+	for _, field := range fields {
+		aggregation := financialFieldAggregation(field)
+		series := domain.FinancialTrendSeries{
+			Metric:      field.Key,
+			Label:       field.Header,
+			Unit:        field.Unit(),
+			Aggregation: aggregation,
+			Points:      []domain.FinancialTrendPoint{},
+		}
+		for month := start; !month.After(end); month = month.AddDate(0, 1, 0) {
+			value := metricAt(byPeriod, analyticsPeriod(month), field)
+			point := domain.FinancialTrendPoint{
+				Period:    analyticsPeriod(month),
+				Value:     value,
+				MoMGrowth: growthPercent(value, metricAt(byPeriod, analyticsPeriod(month.AddDate(0, -1, 0)), field)),
+				YoYGrowth: growthPercent(value, metricAt(byPeriod, analyticsPeriod(month.AddDate(-1, 0, 0)), field)),
+			}
+			if aggregation != domain.FinancialAggregationLast {
+				point.LTM = rollingTwelveMonths(byPeriod, month, field, aggregation)
+			}
+			series.Points = append(series.Points, point)
+		}
+		response.Series = append(response.Series, series)
+	}
+	return response, nil
+}
+
+// rollingTwelveMonths menghitung LTM (12 bulan sampai month); nil jika ada bulan yang belum diisi
+func rollingTwelveMonths(byPeriod map[string]*domain.FinancialReportModel, month time.Time, field financialImportField, aggregation string) *float64 {
+	values := make([]float64, 0, 12)
+	for i := 11; i >= 0; i-- {
+		value := metricAt(byPeriod, analyticsPeriod(month.AddDate(0, -i, 0)), field)
+		if value == nil {
+			return nil
+		}
+		values = append(values, *value)
+	}
+	return roundedPtr(aggregateValues(values, aggregation))
+}
+
+func (uc *financialAnalyticsUseCase) GetForecast(companyID, year string, metrics []string) (*domain.FinancialForecastResponse, error) {
+	if _, err := time.Parse("2006", year); err != nil {
+		return nil, fmt.Errorf("%w: year must be YYYY", ErrInvalidAnalyticsPeriod)
+	}
+	fields, err := resolveFinancialMetrics(metrics)
+	if err != nil {
+		return nil, err
+	}
+	byPeriod, _, err := uc.loadRealisasiByPeriod(companyID)
+	if err != nil {
+		return nil, err
+	}
+	rkap, err := uc.repo.GetRKAPByCompanyIDAndYear(companyID, year)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get RKAP: %w", err)
+	}
+
+	response := &domain.FinancialForecastResponse{
+		CompanyID: companyID,
+		Year:      year,
+		Items:     make([]domain.FinancialForecastItem, 0, len(fields)),
+	}
+	for month := 1; month <= 12; month++ {
+		if period := fmt.Sprintf("%s-%02d", year, month); byPeriod[period] != nil {
+			response.AsOfPeriod = period
+		}
+	}
+
+	for _, field := range fields {
+		item := forecastFinancialMetric(byPeriod, rkap, year, field)
+		response.Items = append(response.Items, item)
+	}
+	return response, nil
+}
+
+// forecastFinancialMetric menghitung run-rate dan proyeksi linear (least squares per bulan) untuk satu metrik
+func forecastFinancialMetric(byPeriod map[string]*domain.FinancialReportModel, rkap *domain.FinancialReportModel, year string, field financialImportField) domain.FinancialForecastItem {
+	aggregation := financialFieldAggregation(field)
+	item := domain.FinancialForecastItem{
+		Metric:      field.Key,
+		Label:       field.Header,
+		Unit:        field.Unit(),
+		Aggregation: aggregation,
+		Points:      make([]domain.FinancialForecastPoint, 12),
+	}
+	if rkap != nil {
+		item.RKAP = roundedPtr(financialMetricValue(rkap, field))
+	}
+
+	actuals := make([]*float64, 12)
+	var months, values []float64
+	for i := range actuals {
+		actuals[i] = metricAt(byPeriod, fmt.Sprintf("%s-%02d", year, i+1), field)
+		item.Points[i] = domain.FinancialForecastPoint{Period: fmt.Sprintf("%s-%02d", year, i+1), Actual: actuals[i]}
+		if actuals[i] != nil {
+			months = append(months, float64(i+1))
+			values = append(values, *actuals[i])
+		}
+	}
+	item.MonthsActual = len(values)
+	if len(values) == 0 {
+		return item
+	}
+
+	ytd := aggregateValues(values, aggregation)
+	item.ActualYTD = roundedPtr(ytd)
+	switch aggregation {
+	case domain.FinancialAggregationSum:
+		item.RunRate = roundedPtr(ytd / float64(len(values)) * 12)
+	default:
+		// Saldo: posisi terakhir dianggap bertahan sampai akhir tahun; rasio: rata-rata berjalan
+		item.RunRate = roundedPtr(ytd)
+	}
+
+	// Proyeksi linear untuk bulan tanpa realisasi, lalu diagregasi setahun penuh
+	intercept, slope := linearFit(months, values)
+	fullYear := make([]float64, 12)
+	for i := range fullYear {
+		if actuals[i] != nil {
+			fullYear[i] = *actuals[i]
+			continue
+		}
+		fullYear[i] = intercept + slope*float64(i+1)
+		item.Points[i].Forecast = roundedPtr(fullYear[i])
+	}
+	item.Linear = roundedPtr(aggregateValues(fullYear, aggregation))
+
+	if item.RKAP != nil && *item.RKAP != 0 {
+		item.RunRateAchievement = roundedPtr(*item.RunRate / *item.RKAP * 100)
+		item.LinearAchievement = roundedPtr(*item.Linear / *item.RKAP * 100)
+	}
+	return item
+}
+
+// linearFit menghitung y = intercept + slope*x; satu titik data menghasilkan garis datar
+func linearFit(xs, ys []float64) (intercept, slope float64) {
+	n := float64(len(xs))
+	var sumX, sumY, sumXY, sumXX float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+		sumXY += xs[i] * ys[i]
+		sumXX += xs[i] * xs[i]
+	}
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return sumY / n, 0
+	}
+	slope = (n*sumXY - sumX*sumY) / denominator
+	return (sumY - slope*sumX) / n, slope
+}
+
+func aggregateValues(values []float64, aggregation string) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	switch aggregation {
+	case domain.FinancialAggregationLast:
+		return values[len(values)-1]
+	case domain.FinancialAggregationAverage:
+		total := 0.0
+		for _, value := range values {
+			total += value
+		}
+		return total / float64(len(values))
+	default:
+		total := 0.0
+		for _, value := range values {
+			total += value
+		}
+		return total
+	}
+}
+
+// growthPercent menghitung (current - previous) / |previous| * 100; nil jika salah satu kosong atau previous 0
+func growthPercent(current, previous *float64) *float64 {
+	if current == nil || previous == nil || *previous == 0 {
+		return nil
+	}
+	return roundedPtr((*current - *previous) / math.Abs(*previous) * 100)
+}
+
+func roundedPtr(value float64) *float64 {
+	rounded := math.Round(value*100) / 100
+	return &rounded
+}
+
+func monthsBetween(start, end time.Time) int {
+	return (end.Year()-start.Year())*12 + int(end.Month()-start.Month())
+}
+
+func (uc *financialAnalyticsUseCase) ExportAnalyticsExcel(companyID string, metrics []string, startPeriod, endPeriod string) ([]byte, error) {
+	trend, err := uc.GetTrend(companyID, metrics, startPeriod, endPeriod)
+	if err != nil {
+		return nil, err
+	}
+	forecast, err := uc.GetForecast(companyID, trend.EndPeriod[:4], metrics)
+	if err != nil {
+		return nil, err
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+
+	headerStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"#E3F2FD"}, Pattern: 1},
+	})
+
+	// Sheet Trend: satu blok kolom per metrik (Value, MoM %, YoY %, LTM)
+	const trendSheet = "Trend"
+	_ = f.SetSheetName("Sheet1", trendSheet)
+	_ = f.SetCellValue(trendSheet, "A1", fmt.Sprintf("Trend Realisasi - Periode %s - %s", trend.StartPeriod, trend.EndPeriod))
+	_ = f.SetCellValue(trendSheet, "A3", "Periode")
+	_ = f.SetCellStyle(trendSheet, "A3", "A4", headerStyle)
+	for s, series := range trend.Series {
+		firstCol := 2 + s*4
+		labelCell, _ := excelize.CoordinatesToCellName(firstCol, 3)
+		_ = f.SetCellValue(trendSheet, labelCell, fmt.Sprintf("%s (%s)", series.Label, series.Unit))
+		for i, header := range []string{"Nilai", "MoM %", "YoY %", "LTM"} {
+			cell, _ := excelize.CoordinatesToCellName(firstCol+i, 4)
+			_ = f.SetCellValue(trendSheet, cell, header)
+		}
+		lastCell, _ := excelize.CoordinatesToCellName(firstCol+3, 4)
+		_ = f.SetCellStyle(trendSheet, labelCell, lastCell, headerStyle)
+
+		for p, point := range series.Points {
+			row := 5 + p
+			if s == 0 {
+				_ = f.SetCellValue(trendSheet, fmt.Sprintf("A%d", row), point.Period)
+			}
+			for i, value := range []*float64{point.Value, point.MoMGrowth, point.YoYGrowth, point.LTM} {
+				if value == nil {
+					continue
+				}
+				cell, _ := excelize.CoordinatesToCellName(firstCol+i, row)
+				_ = f.SetCellValue(trendSheet, cell, *value)
+			}
+		}
+	}
+
+	// Sheet Forecast: satu baris per metrik
+	const forecastSheet = "Forecast"
+	if _, err := f.NewSheet(forecastSheet); err != nil {
+		return nil, err
+	}
+	_ = f.SetCellValue(forecastSheet, "A1", fmt.Sprintf("Forecast Realisasi %s (data sampai %s)", forecast.Year, forecast.AsOfPeriod))
+	headers := []interface{}{"Metrik", "Unit", "Agregasi", "Bulan Realisasi", "Realisasi YTD", "Run-rate", "Linear", "RKAP", "Run-rate vs RKAP %", "Linear vs RKAP %"}
+	_ = f.SetSheetRow(forecastSheet, "A3", &headers)
+	_ = f.SetCellStyle(forecastSheet, "A3", "J3", headerStyle)
+	for i, item := range forecast.Items {
+		row := []interface{}{item.Label, item.Unit, item.Aggregation, item.MonthsActual}
+		for _, value := range []*float64{item.ActualYTD, item.RunRate, item.Linear, item.RKAP, item.RunRateAchievement, item.LinearAchievement} {
+			if value == nil {
+				row = append(row, nil)
+				continue
+			}
+			row = append(row, *value)
+		}
+		_ = f.SetSheetRow(forecastSheet, fmt.Sprintf("A%d", 4+i), &row)
+	}
+	_ = f.SetColWidth(forecastSheet, "A", "A", 28)
+	_ = f.SetColWidth(forecastSheet, "B", "J", 18)
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, fmt.Errorf("failed to write Excel file: %w", err)
+	}
+	return buf.Bytes(), nil
+}
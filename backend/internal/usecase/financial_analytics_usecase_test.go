@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"github.com/repoareta/pedeve-dms-app/backend/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+// TestFinancialAnalytics tests trend (MoM, YoY, LTM) dan forecast realisasi vs RKAP
+func TestFinancialAnalytics(t *testing.T) {
+	db := helpers.SetupTestDB(t)
+	defer helpers.CleanupTestDB(t, db)
+
+	company := createTestCompanyForNotification(t, db, nil)
+	// 2024: revenue 100 per bulan; 2025: Jan-Jun naik 10 per bulan (110..160), equity saldo bulanan
+	for month := 1; month <= 12; month++ {
+		createTestFinancialReportForImport(t, db, company.ID, fmt.Sprintf("2024-%02d", month), 100)
+	}
+	for month := 1; month <= 6; month++ {
+		report := createTestFinancialReportForImport(t, db, company.ID, fmt.Sprintf("2025-%02d", month), int64(100+month*10))
+		report.Equity = int64(1000 + month)
+		require.NoError(t, db.Save(report).Error)
+	}
+	require.NoError(t, db.Create(&domain.FinancialReportModel{
+		ID: uuid.GenerateUUID(), CompanyID: company.ID, Year: "2025", IsRKAP: true, Revenue: 2000,
+	}).Error)
+
+	uc := NewFinancialAnalyticsUseCaseWithDB(db)
+
+	t.Run("Trend growth and LTM", func(t *testing.T) {
+		trend, err := uc.GetTrend(company.ID, []string{"revenue", "pdv:Equity"}, "2024-12", "2025-07")
+		require.NoError(t, err)
+		require.Len(t, trend.Series, 2)
+
+		revenue := trend.Series[0]
+		assert.Equal(t, domain.FinancialAggregationSum, revenue.Aggregation)
+		require.Len(t, revenue.Points, 8)
+
+		feb := revenue.Points[2] // 2025-02 = 120
+		assert.Equal(t, "2025-02", feb.Period)
+		assert.InDelta(t, 9.09, *feb.MoMGrowth, 0.001)
+		assert.InDelta(t, 20, *feb.YoYGrowth, 0.001)
+		// LTM Feb 2025 = Mar-Des 2024 (10 x 100) + 110 + 120
+		assert.InDelta(t, 1230, *feb.LTM, 0.001)
+
+		jul := revenue.Points[7]
+		assert.Nil(t, jul.Value)
+		assert.Nil(t, jul.LTM)
+
+		equity := trend.Series[1]
+		assert.Equal(t, domain.FinancialAggregationLast, equity.Aggregation)
+		assert.Nil(t, equity.Points[2].LTM)
+	})
+
+	t.Run("Trend validation", func(t *testing.T) {
+		_, err := uc.GetTrend(company.ID, []string{"unknown_metric"}, "", "")
+		assert.ErrorIs(t, err, ErrUnknownFinancialMetric)
+		_, err = uc.GetTrend(company.ID, nil, "2025-06", "2025-01")
+		assert.ErrorIs(t, err, ErrInvalidAnalyticsPeriod)
+
+		trend, err := uc.GetTrend(company.ID, []string{"revenue"}, "", "")
+		require.NoError(t, err)
+		assert.Equal(t, "2025-06", trend.EndPeriod)
+		assert.Equal(t, "2023-07", trend.StartPeriod)
+	})
+
+	t.Run("Forecast run-rate and linear", func(t *testing.T) {
+		forecast, err := uc.GetForecast(company.ID, "2025", []string{"revenue", "equity"})
+		require.NoError(t, err)
+		assert.Equal(t, "2025-06", forecast.AsOfPeriod)
+
+		revenue := forecast.Items[0]
+		assert.Equal(t, 6, revenue.MonthsActual)
+		assert.InDelta(t, 810, *revenue.ActualYTD, 0.001)
+		assert.InDelta(t, 1620, *revenue.RunRate, 0.001)
+		// Linear: Jul-Des melanjutkan +10 per bulan (170..220) => 810 + 1170
+		assert.InDelta(t, 1980, *revenue.Linear, 0.001)
+		assert.InDelta(t, 81, *revenue.RunRateAchievement, 0.001)
+		assert.InDelta(t, 99, *revenue.LinearAchievement, 0.001)
+		assert.InDelta(t, 220, *revenue.Points[11].Forecast, 0.001)
+		assert.Nil(t, revenue.Points[0].Forecast)
+
+		equity := forecast.Items[1]
+		assert.InDelta(t, 1006, *equity.RunRate, 0.001)
+		assert.InDelta(t, 1012, *equity.Linear, 0.001)
+		assert.Nil(t, equity.RunRateAchievement) // RKAP equity 0
+	})
+
+	t.Run("Excel export uses the same numbers", func(t *testing.T) {
+		data, err := uc.ExportAnalyticsExcel(company.ID, []string{"revenue"}, "2025-01", "2025-06")
+		require.NoError(t, err)
+		f, err := excelize.OpenReader(bytes.NewReader(data))
+		require.NoError(t, err)
+		defer f.Close()
+
+		value, err := f.GetCellValue("Trend", "B6") // 2025-02 nilai
+		require.NoError(t, err)
+		assert.Equal(t, "120", value)
+		linear, err := f.GetCellValue("Forecast", "G4")
+		require.NoError(t, err)
+		assert.Equal(t, "1980", linear)
+	})
+}
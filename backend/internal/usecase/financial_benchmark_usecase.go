@@ -0,0 +1,364 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidBenchmarkScope = errors.New("invalid benchmark scope")
+
+// Batas jumlah company eksplisit dalam satu request benchmark
+const financialBenchmarkMaxCompanies = 500
+
+// Metrik default benchmark jika metrics kosong
+var financialBenchmarkDefaultMetrics = []string{"roe", "revenue"}
+
+// Metrik yang lebih baik jika nilainya lebih kecil (beban, pajak, liabilitas, leverage)
+var financialBenchmarkLowerIsBetter = map[string]bool{
+	"operating_expenses":     true,
+	"tax":                    true,
+	"short_term_liabilities": true,
+	"long_term_liabilities":  true,
+	"debt_to_equity":         true,
+}
+
+// FinancialBenchmarkUseCase interface untuk ranking dan benchmarking KPI lintas company
+type FinancialBenchmarkUseCase interface {
+	GetBenchmark(req domain.FinancialBenchmarkRequest) (*domain.FinancialBenchmarkResponse, error)
+	ExportBenchmarkExcel(req domain.FinancialBenchmarkRequest) ([]byte, error)
+}
+
+type financialBenchmarkUseCase struct {
+	reportRepo  repository.FinancialReportRepository
+	companyRepo repository.CompanyRepository
+}
+
+// NewFinancialBenchmarkUseCaseWithDB creates a new financial benchmark use case with injected DB
+func NewFinancialBenchmarkUseCaseWithDB(db *gorm.DB) FinancialBenchmarkUseCase {
+	return &financialBenchmarkUseCase{
+		reportRepo:  repository.NewFinancialReportRepositoryWithDB(db),
+		companyRepo: repository.NewCompanyRepositoryWithDB(db),
+	}
+}
+
+// NewFinancialBenchmarkUseCase creates a new financial benchmark use case with default DB
+func NewFinancialBenchmarkUseCase() FinancialBenchmarkUseCase {
+	return NewFinancialBenchmarkUseCaseWithDB(database.GetDB())
+}
+
+// resolveCompanies mengambil company eksplisit atau subtree (root + descendants), lalu filter level
+func (uc *financialBenchmarkUseCase) resolveCompanies(req domain.FinancialBenchmarkRequest) ([]domain.CompanyModel, error) {
+	var companies []domain.CompanyModel
+	switch {
+	case len(req.CompanyIDs) > 0:
+		if len(req.CompanyIDs) > financialBenchmarkMaxCompanies {
+			return nil, fmt.Errorf("%w: maximum %d companies", ErrInvalidBenchmarkScope, financialBenchmarkMaxCompanies)
+		}
+		seen := make(map[string]bool)
+		for _, id := range req.CompanyIDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			company, err := uc.companyRepo.GetByID(id)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("%w: company %s not found", ErrInvalidBenchmarkScope, id)
+			}
+			if err != nil {
+				return nil, err
+			}
+			companies = append(companies, *company)
+		}
+	case req.RootCompanyID != "":
+		root, err := uc.companyRepo.GetByID(req.RootCompanyID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: company %s not found", ErrInvalidBenchmarkScope, req.RootCompanyID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		descendants, err := uc.companyRepo.GetDescendants(root.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get descendants: %w", err)
+		}
+		companies = append([]domain.CompanyModel{*root}, descendants...)
+	default:
+		return nil, fmt.Errorf("%w: company_ids or root_company_id is required", ErrInvalidBenchmarkScope)
+	}
+
+	if req.Level == nil {
+		return companies, nil
+	}
+	filtered := companies[:0]
+	for _, company := range companies {
+		if company.Level == *req.Level {
+			filtered = append(filtered, company)
+		}
+	}
+	return filtered, nil
+}
+
+func (uc *financialBenchmarkUseCase) GetBenchmark(req domain.FinancialBenchmarkRequest) (*domain.FinancialBenchmarkResponse, error) {
+	period, err := parseAnalyticsPeriod(req.Period)
+	if err != nil {
+		return nil, err
+	}
+	if req.RankBy == "" {
+		req.RankBy = domain.FinancialBenchmarkRankByValue
+	}
+	if req.RankBy != domain.FinancialBenchmarkRankByValue && req.RankBy != domain.FinancialBenchmarkRankByAchievement {
+		return nil, fmt.Errorf("%w: rank_by must be value or achievement", ErrInvalidBenchmarkScope)
+	}
+	metrics := req.Metrics
+	if len(metrics) == 0 {
+		metrics = financialBenchmarkDefaultMetrics
+	}
+	fields, err := resolveFinancialMetrics(metrics)
+	if err != nil {
+		return nil, err
+	}
+	companies, err := uc.resolveCompanies(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Satu query untuk RKAP + realisasi tahun berjalan semua company
+	year := period.Format("2006")
+	periodKey := analyticsPeriod(period)
+	companyIDs := make([]string, 0, len(companies))
+	for _, company := range companies {
+		companyIDs = append(companyIDs, company.ID)
+	}
+	reports, err := uc.reportRepo.GetByCompanyIDsAndYear(companyIDs, year)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get financial reports: %w", err)
+	}
+	rkapByCompany := make(map[string]*domain.FinancialReportModel)
+	realisasiByCompany := make(map[string][]*domain.FinancialReportModel)
+	for i := range reports {
+		report := &reports[i]
+		if report.IsRKAP {
+			// Pencapaian dihitung terhadap versi RKAP approved terakhir
+			if current := rkapByCompany[report.CompanyID]; isApprovedRKAP(report) && (current == nil || report.RKAPVersion > current.RKAPVersion) {
+				rkapByCompany[report.CompanyID] = report
+			}
+		} else if isMonthlyRealisasi(report) && report.Period <= periodKey {
+			realisasiByCompany[report.CompanyID] = append(realisasiByCompany[report.CompanyID], report)
+		}
+	}
+
+	response := &domain.FinancialBenchmarkResponse{
+		Period:    periodKey,
+		RankBy:    req.RankBy,
+		Companies: len(companies),
+		Metrics:   make([]domain.FinancialBenchmarkMetric, 0, len(fields)),
+	}
+	for _, field := range fields {
+		metric := domain.FinancialBenchmarkMetric{
+			Metric:         field.Key,
+			Label:          field.Header,
+			Unit:           field.Unit(),
+			Aggregation:    financialFieldAggregation(field),
+			HigherIsBetter: !financialBenchmarkLowerIsBetter[field.Key],
+			Entries:        make([]domain.FinancialBenchmarkEntry, 0, len(companies)),
+		}
+		for _, company := range companies {
+			entry := domain.FinancialBenchmarkEntry{
+				CompanyID:   company.ID,
+				CompanyCode: company.Code,
+				CompanyName: company.Name,
+				Level:       company.Level,
+			}
+			if realisasi := realisasiByCompany[company.ID]; len(realisasi) > 0 {
+				values := make([]float64, 0, len(realisasi))
+				for _, report := range realisasi {
+					values = append(values, financialMetricValue(report, field))
+				}
+				entry.Value = roundedPtr(aggregateValues(values, metric.Aggregation))
+			}
+			if rkap := rkapByCompany[company.ID]; rkap != nil {
+				entry.RKAP = roundedPtr(financialMetricValue(rkap, field))
+				if entry.Value != nil && *entry.RKAP != 0 {
+					entry.Achievement = roundedPtr(*entry.Value / *entry.RKAP * 100)
+				}
+			}
+			metric.Entries = append(metric.Entries, entry)
+		}
+		rankBenchmarkEntries(&metric, req.RankBy)
+		response.Metrics = append(response.Metrics, metric)
+	}
+	return response, nil
+}
+
+// benchmarkBasis mengembalikan nilai dasar ranking entry (value atau achievement)
+func benchmarkBasis(entry domain.FinancialBenchmarkEntry, rankBy string) *float64 {
+	if rankBy == domain.FinancialBenchmarkRankByAchievement {
+		return entry.Achievement
+	}
+	return entry.Value
+}
+
+// rankBenchmarkEntries mengurutkan entry (nilai sama mendapat rank sama), menghitung percentile dan statistik.
+// Entry tanpa nilai diletakkan di akhir tanpa rank.
+func rankBenchmarkEntries(metric *domain.FinancialBenchmarkMetric, rankBy string) {
+	better := func(a, b float64) bool {
+		if metric.HigherIsBetter {
+			return a > b
+		}
+		return a < b
+	}
+	sort.SliceStable(metric.Entries, func(i, j int) bool {
+		a, b := benchmarkBasis(metric.Entries[i], rankBy), benchmarkBasis(metric.Entries[j], rankBy)
+		switch {
+		case a == nil || b == nil:
+			return a != nil && b == nil
+		case *a != *b:
+			return better(*a, *b)
+		default:
+			return metric.Entries[i].CompanyName < metric.Entries[j].CompanyName
+		}
+	})
+
+	var ranked []float64
+	for _, entry := range metric.Entries {
+		if basis := benchmarkBasis(entry, rankBy); basis != nil {
+			ranked = append(ranked, *basis)
+		}
+	}
+	n := len(ranked)
+	for i := 0; i < n; i++ {
+		rank := i + 1
+		if i > 0 && ranked[i] == ranked[i-1] {
+			rank = *metric.Entries[i-1].Rank
+		}
+		metric.Entries[i].Rank = &rank
+
+		// Percentile = porsi company lain yang posisinya lebih buruk
+		worse := 0
+		for _, other := range ranked {
+			if better(ranked[i], other) {
+				worse++
+			}
+		}
+		percentile := 100.0
+		if n > 1 {
+			percentile = float64(worse) / float64(n-1) * 100
+		}
+		metric.Entries[i].Percentile = roundedPtr(percentile)
+	}
+
+	metric.Stats = benchmarkStats(ranked)
+}
+
+// benchmarkStats menghitung min, kuartil (interpolasi linear), max, dan rata-rata
+func benchmarkStats(values []float64) domain.FinancialBenchmarkStats {
+	stats := domain.FinancialBenchmarkStats{Count: len(values)}
+	if len(values) == 0 {
+		return stats
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	total := 0.0
+	for _, value := range sorted {
+		total += value
+	}
+	stats.Min = roundedPtr(sorted[0])
+	stats.P25 = roundedPtr(quantile(sorted, 0.25))
+	stats.Median = roundedPtr(quantile(sorted, 0.5))
+	stats.P75 = roundedPtr(quantile(sorted, 0.75))
+	stats.Max = roundedPtr(sorted[len(sorted)-1])
+	stats.Average = roundedPtr(total / float64(len(sorted)))
+	return stats
+}
+
+func quantile(sorted []float64, q float64) float64 {
+	position := q * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+	if lower == upper {
+		return sorted[lower]
+	}
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(position-float64(lower))
+}
+
+func (uc *financialBenchmarkUseCase) ExportBenchmarkExcel(req domain.FinancialBenchmarkRequest) ([]byte, error) {
+	benchmark, err := uc.GetBenchmark(req)
+	if err != nil {
+		return nil, err
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+
+	headerStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"#E3F2FD"}, Pattern: 1},
+	})
+
+	// Satu sheet per metrik (nama sheet = key metrik supaya aman dari batas 31 karakter)
+	for i, metric := range benchmark.Metrics {
+		sheet := metric.Metric
+		if i == 0 {
+			_ = f.SetSheetName("Sheet1", sheet)
+		} else if _, err := f.NewSheet(sheet); err != nil {
+			return nil, err
+		}
+
+		_ = f.SetCellValue(sheet, "A1", fmt.Sprintf("Benchmark %s (%s) - YTD s.d. %s, ranking berdasarkan %s", metric.Label, metric.Unit, benchmark.Period, benchmark.RankBy))
+		headers := []interface{}{"Rank", "Kode", "Perusahaan", "Level", "Realisasi YTD", "RKAP", "Pencapaian %", "Percentile"}
+		_ = f.SetSheetRow(sheet, "A3", &headers)
+		_ = f.SetCellStyle(sheet, "A3", "H3", headerStyle)
+
+		for r, entry := range metric.Entries {
+			row := []interface{}{nil, entry.CompanyCode, entry.CompanyName, entry.Level}
+			if entry.Rank != nil {
+				row[0] = *entry.Rank
+			}
+			for _, value := range []*float64{entry.Value, entry.RKAP, entry.Achievement, entry.Percentile} {
+				if value == nil {
+					row = append(row, nil)
+					continue
+				}
+				row = append(row, *value)
+			}
+			_ = f.SetSheetRow(sheet, fmt.Sprintf("A%d", 4+r), &row)
+		}
+
+		// Statistik sebaran di bawah tabel, di kolom dasar ranking
+		statsRow := 5 + len(metric.Entries)
+		statsCol := "E"
+		if benchmark.RankBy == domain.FinancialBenchmarkRankByAchievement {
+			statsCol = "G"
+		}
+		stats := []struct {
+			label string
+			value *float64
+		}{
+			{"Min", metric.Stats.Min}, {"P25", metric.Stats.P25}, {"Median", metric.Stats.Median},
+			{"P75", metric.Stats.P75}, {"Max", metric.Stats.Max}, {"Rata-rata", metric.Stats.Average},
+		}
+		for s, stat := range stats {
+			_ = f.SetCellValue(sheet, fmt.Sprintf("C%d", statsRow+s), stat.label)
+			if stat.value != nil {
+				_ = f.SetCellValue(sheet, fmt.Sprintf("%s%d", statsCol, statsRow+s), *stat.value)
+			}
+		}
+		_ = f.SetColWidth(sheet, "C", "C", 32)
+		_ = f.SetColWidth(sheet, "E", "H", 18)
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, fmt.Errorf("failed to write Excel file: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"github.com/repoareta/pedeve-dms-app/backend/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+// TestFinancialBenchmark tests ranking KPI lintas subsidiary beserta percentile dan pencapaian RKAP
+func TestFinancialBenchmark(t *testing.T) {
+	db := helpers.SetupTestDB(t)
+	defer helpers.CleanupTestDB(t, db)
+
+	holding := createTestCompanyForNotification(t, db, nil)
+	subA := createTestCompanyForNotification(t, db, &holding.ID)
+	subB := createTestCompanyForNotification(t, db, &holding.ID)
+	subC := createTestCompanyForNotification(t, db, &holding.ID)
+
+	// Revenue YTD s.d. Februari: A = 300, B = 500, C belum ada realisasi
+	createBenchmarkReport(t, db, subA.ID, "2025-01", 100, 12)
+	createBenchmarkReport(t, db, subA.ID, "2025-02", 200, 14)
+	createBenchmarkReport(t, db, subB.ID, "2025-02", 500, 8)
+	createBenchmarkReport(t, db, subB.ID, "2025-03", 900, 8) // Setelah periode, tidak dihitung
+	createBenchmarkRKAP(t, db, subA.ID, "2025", 600)
+	createBenchmarkRKAP(t, db, subB.ID, "2025", 2000)
+
+	uc := NewFinancialBenchmarkUseCaseWithDB(db)
+	level := 1
+
+	t.Run("Rank subtree by value", func(t *testing.T) {
+		result, err := uc.GetBenchmark(domain.FinancialBenchmarkRequest{
+			Period:        "2025-02",
+			Metrics:       []string{"revenue", "roe"},
+			RootCompanyID: holding.ID,
+			Level:         &level,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.Companies)
+
+		revenue := result.Metrics[0]
+		require.Len(t, revenue.Entries, 3)
+		assert.Equal(t, subB.ID, revenue.Entries[0].CompanyID)
+		assert.Equal(t, 1, *revenue.Entries[0].Rank)
+		assert.InDelta(t, 500, *revenue.Entries[0].Value, 0.001)
+		assert.InDelta(t, 100, *revenue.Entries[0].Percentile, 0.001)
+		assert.Equal(t, subA.ID, revenue.Entries[1].CompanyID)
+		assert.InDelta(t, 0, *revenue.Entries[1].Percentile, 0.001)
+		assert.Equal(t, subC.ID, revenue.Entries[2].CompanyID)
+		assert.Nil(t, revenue.Entries[2].Rank)
+		assert.Equal(t, 2, revenue.Stats.Count)
+		assert.InDelta(t, 400, *revenue.Stats.Median, 0.001)
+
+		// ROE rasio dirata-rata: A = 13, B = 8
+		roe := result.Metrics[1]
+		assert.Equal(t, subA.ID, roe.Entries[0].CompanyID)
+		assert.InDelta(t, 13, *roe.Entries[0].Value, 0.001)
+	})
+
+	t.Run("Rank explicit list by achievement", func(t *testing.T) {
+		result, err := uc.GetBenchmark(domain.FinancialBenchmarkRequest{
+			Period:     "2025-02",
+			Metrics:    []string{"revenue"},
+			CompanyIDs: []string{subA.ID, subB.ID},
+			RankBy:     domain.FinancialBenchmarkRankByAchievement,
+		})
+		require.NoError(t, err)
+		entries := result.Metrics[0].Entries
+		// A: 300/600 = 50%, B: 500/2000 = 25%
+		assert.Equal(t, subA.ID, entries[0].CompanyID)
+		assert.InDelta(t, 50, *entries[0].Achievement, 0.001)
+		assert.InDelta(t, 25, *entries[1].Achievement, 0.001)
+	})
+
+	t.Run("Validation and export", func(t *testing.T) {
+		_, err := uc.GetBenchmark(domain.FinancialBenchmarkRequest{Period: "2025-02"})
+		assert.ErrorIs(t, err, ErrInvalidBenchmarkScope)
+		_, err = uc.GetBenchmark(domain.FinancialBenchmarkRequest{Period: "2025-02", RootCompanyID: holding.ID, RankBy: "size"})
+		assert.ErrorIs(t, err, ErrInvalidBenchmarkScope)
+
+		data, err := uc.ExportBenchmarkExcel(domain.FinancialBenchmarkRequest{Period: "2025-02", RootCompanyID: holding.ID, Level: &level})
+		require.NoError(t, err)
+		f, err := excelize.OpenReader(bytes.NewReader(data))
+		require.NoError(t, err)
+		defer f.Close()
+		assert.Equal(t, []string{"roe", "revenue"}, f.GetSheetList())
+		name, err := f.GetCellValue("revenue", "C4")
+		require.NoError(t, err)
+		assert.Equal(t, subB.Name, name)
+	})
+}
+
+func createBenchmarkReport(t *testing.T, db *gorm.DB, companyID, period string, revenue int64, roe float64) {
+	report := createTestFinancialReportForImport(t, db, companyID, period, revenue)
+	report.ROE = roe
+	require.NoError(t, db.Save(report).Error)
+}
+
+func createBenchmarkRKAP(t *testing.T, db *gorm.DB, companyID, year string, revenue int64) {
+	require.NoError(t, db.Create(&domain.FinancialReportModel{
+		ID: uuid.GenerateUUID(), CompanyID: companyID, Year: year, IsRKAP: true, Revenue: revenue,
+	}).Error)
+}
@@ -0,0 +1,349 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+)
+
+// MaxDecimal10_2 adalah nilai maksimal kolom decimal(10,2) (rasio/persentase)
+const MaxDecimal10_2 = 99999999.99
+
+// financialImportField mendefinisikan satu kolom numerik financial report beserta kode konsep taksonomi-nya
+type financialImportField struct {
+	Header        string // Header kolom di template Excel (Bahasa Indonesia)
+	Field         string // Nama field di CreateFinancialReportRequest & FinancialReportModel
+	Key           string // Nama field JSON API, dipakai juga sebagai nama kolom CSV
+	Concept       string // Kode konsep taksonomi (stabil, dipakai format JSON dan mapping akun)
+	Section       string // neraca, laba_rugi, arus_kas, rasio
+	AllowNegative bool
+	IsFloat       bool // float64 (decimal(10,2)), selain itu int64
+	IsPercentage  bool // Tidak boleh melebihi 100
+}
+
+// Unit nilai konsep taksonomi
+func (f financialImportField) Unit() string {
+	switch {
+	case f.IsPercentage:
+		return "percent"
+	case f.IsFloat:
+		return "ratio"
+	default:
+		return "IDR"
+	}
+}
+
+// Kolom identitas baris sebelum kolom numerik
+const (
+	financialImportColCompanyCode = 0
+	financialImportColCompanyName = 1
+	financialImportColYear        = 2
+	financialImportColMonth       = 3
+	financialImportColFirstValue  = 4
+)
+
+// financialImportFields berurutan sesuai kolom template (setelah Kode Perusahaan, Nama Perusahaan, Tahun, Bulan).
+// Kode konsep tidak boleh diubah karena dipakai sistem ERP anak perusahaan dan mapping akun yang tersimpan.
+var financialImportFields = []financialImportField{
+	// Neraca
+	{Header: "Aset Lancar", Field: "CurrentAssets", Key: "current_assets", Concept: "pdv:CurrentAssets", Section: "neraca", AllowNegative: true},
+	{Header: "Aset Tidak Lancar", Field: "NonCurrentAssets", Key: "non_current_assets", Concept: "pdv:NoncurrentAssets", Section: "neraca", AllowNegative: true},
+	{Header: "Liabilitas Jangka Pendek", Field: "ShortTermLiabilities", Key: "short_term_liabilities", Concept: "pdv:CurrentLiabilities", Section: "neraca", AllowNegative: true},
+	{Header: "Liabilitas Jangka Panjang", Field: "LongTermLiabilities", Key: "long_term_liabilities", Concept: "pdv:NoncurrentLiabilities", Section: "neraca", AllowNegative: true},
+	{Header: "Ekuitas", Field: "Equity", Key: "equity", Concept: "pdv:Equity", Section: "neraca", AllowNegative: true},
+	// Laba Rugi
+	{Header: "Pendapatan", Field: "Revenue", Key: "revenue", Concept: "pdv:Revenue", Section: "laba_rugi"},
+	{Header: "Beban Usaha", Field: "OperatingExpenses", Key: "operating_expenses", Concept: "pdv:OperatingExpenses", Section: "laba_rugi", AllowNegative: true},
+	{Header: "Laba Usaha", Field: "OperatingProfit", Key: "operating_profit", Concept: "pdv:OperatingProfitLoss", Section: "laba_rugi", AllowNegative: true},
+	{Header: "Pendapatan Lain-Lain", Field: "OtherIncome", Key: "other_income", Concept: "pdv:OtherIncome", Section: "laba_rugi", AllowNegative: true},
+	{Header: "Pajak", Field: "Tax", Key: "tax", Concept: "pdv:IncomeTaxExpense", Section: "laba_rugi", AllowNegative: true},
+	{Header: "Laba Bersih", Field: "NetProfit", Key: "net_profit", Concept: "pdv:ProfitLoss", Section: "laba_rugi", AllowNegative: true},
+	// Cashflow
+	{Header: "Arus Kas Operasi", Field: "OperatingCashflow", Key: "operating_cashflow", Concept: "pdv:CashFlowsFromOperatingActivities", Section: "arus_kas", AllowNegative: true},
+	{Header: "Arus Kas Investasi", Field: "InvestingCashflow", Key: "investing_cashflow", Concept: "pdv:CashFlowsFromInvestingActivities", Section: "arus_kas", AllowNegative: true},
+	{Header: "Arus Kas Pendanaan", Field: "FinancingCashflow", Key: "financing_cashflow", Concept: "pdv:CashFlowsFromFinancingActivities", Section: "arus_kas", AllowNegative: true},
+	{Header: "Saldo Akhir", Field: "EndingBalance", Key: "ending_balance", Concept: "pdv:CashAndCashEquivalentsEndOfPeriod", Section: "arus_kas", AllowNegative: true},
+	// Rasio
+	{Header: "ROE (%)", Field: "ROE", Key: "roe", Concept: "pdv:ReturnOnEquity", Section: "rasio", AllowNegative: true, IsFloat: true, IsPercentage: true},
+	{Header: "ROI (%)", Field: "ROI", Key: "roi", Concept: "pdv:ReturnOnInvestment", Section: "rasio", AllowNegative: true, IsFloat: true, IsPercentage: true},
+	{Header: "Rasio Lancar (%)", Field: "CurrentRatio", Key: "current_ratio", Concept: "pdv:CurrentRatio", Section: "rasio", AllowNegative: true, IsFloat: true, IsPercentage: true},
+	{Header: "Rasio Kas (%)", Field: "CashRatio", Key: "cash_ratio", Concept: "pdv:CashRatio", Section: "rasio", AllowNegative: true, IsFloat: true, IsPercentage: true},
+	{Header: "EBITDA", Field: "EBITDA", Key: "ebitda", Concept: "pdv:EBITDA", Section: "rasio", AllowNegative: true},
+	{Header: "EBITDA Margin (%)", Field: "EBITDAMargin", Key: "ebitda_margin", Concept: "pdv:EBITDAMargin", Section: "rasio", AllowNegative: true, IsFloat: true, IsPercentage: true},
+	{Header: "Net Profit Margin (%)", Field: "NetProfitMargin", Key: "net_profit_margin", Concept: "pdv:NetProfitMargin", Section: "rasio", AllowNegative: true, IsFloat: true, IsPercentage: true},
+	{Header: "Operating Profit Margin (%)", Field: "OperatingProfitMargin", Key: "operating_profit_margin", Concept: "pdv:OperatingProfitMargin", Section: "rasio", AllowNegative: true, IsFloat: true, IsPercentage: true},
+	{Header: "Debt to Equity", Field: "DebtToEquity", Key: "debt_to_equity", Concept: "pdv:DebtToEquityRatio", Section: "rasio", AllowNegative: true, IsFloat: true},
+	// Dividen ditambahkan di akhir agar kolom template sebelumnya tidak bergeser
+	{Header: "Dividen", Field: "Dividend", Key: "dividend", Concept: "pdv:DividendsDeclared", Section: "laba_rugi"},
+}
+
+// financialImportRemarkHeader adalah kolom terakhir template (opsional)
+const financialImportRemarkHeader = "Keterangan"
+
+// FinancialImportHeaders mengembalikan header template bulk upload sesuai urutan kolom
+func FinancialImportHeaders() []string {
+	headers := []string{"Kode Perusahaan", "Nama Perusahaan", "Tahun", "Bulan"}
+	for _, field := range financialImportFields {
+		headers = append(headers, field.Header)
+	}
+	return append(headers, financialImportRemarkHeader)
+}
+
+// FinancialWorkbookError menandakan workbook tidak bisa diproses sama sekali (bukan error per baris)
+type FinancialWorkbookError struct {
+	Message string
+}
+
+func (e *FinancialWorkbookError) Error() string {
+	return e.Message
+}
+
+// ParsedFinancialRow adalah hasil parsing satu baris data workbook
+type ParsedFinancialRow struct {
+	RowNumber   int                                 `json:"row"` // Nomor baris Excel/CSV atau nomor entri JSON (1-based)
+	CompanyCode string                              `json:"company_code"`
+	Request     domain.CreateFinancialReportRequest `json:"data"`
+	Errors      []domain.FinancialImportRowError    `json:"errors,omitempty"`
+	RawValues   []string                            `json:"-"`
+}
+
+// Valid mengembalikan true jika baris lolos validasi
+func (r *ParsedFinancialRow) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+func (r *ParsedFinancialRow) addError(column, message string) {
+	r.Errors = append(r.Errors, domain.FinancialImportRowError{Row: r.RowNumber, Column: column, Message: message})
+}
+
+// financialSourceRow adalah satu baris data hasil decode format apa pun, sudah dalam urutan kolom template
+type financialSourceRow struct {
+	RowNumber int
+	Cells     []string                         // Urutan sama dengan FinancialImportHeaders()
+	Problems  []domain.FinancialImportRowError // Masalah saat decode (akun belum dipetakan, konsep tidak dikenal, dll)
+}
+
+// financialCompanyResolver mencari company aktif berdasarkan kode (return nil jika tidak ditemukan)
+type financialCompanyResolver func(code string) *domain.CompanyModel
+
+// decodeXLSXRows membuka workbook dan mengembalikan baris data sheet pertama (baris kosong dilewati)
+func decodeXLSXRows(data []byte) ([]financialSourceRow, error) {
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, &FinancialWorkbookError{Message: "File Excel tidak valid atau corrupt"}
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			logger.GetLogger().Warn("Failed to close Excel file", zap.Error(err))
+		}
+	}()
+
+	sheetName := f.GetSheetName(0)
+	if sheetName == "" {
+		return nil, &FinancialWorkbookError{Message: "File Excel tidak memiliki sheet"}
+	}
+
+	rows, err := f.GetRows(sheetName)
+	if err != nil {
+		return nil, &FinancialWorkbookError{Message: "Gagal membaca data dari Excel"}
+	}
+
+	if len(rows) < 2 {
+		return nil, &FinancialWorkbookError{Message: "File Excel harus memiliki minimal header dan 1 baris data"}
+	}
+
+	expected := len(FinancialImportHeaders())
+	if len(rows[0]) < expected {
+		return nil, &FinancialWorkbookError{
+			Message: fmt.Sprintf("Header tidak lengkap. Diperlukan minimal %d kolom, ditemukan %d", expected, len(rows[0])),
+		}
+	}
+
+	var sourceRows []financialSourceRow
+	for rowIndex := 1; rowIndex < len(rows); rowIndex++ {
+		if isEmptyFinancialRow(rows[rowIndex]) {
+			continue
+		}
+		sourceRows = append(sourceRows, financialSourceRow{RowNumber: rowIndex + 1, Cells: rows[rowIndex]})
+	}
+	return sourceRows, nil
+}
+
+// isEmptyFinancialRow mengecek baris kosong (dilewati, tidak dihitung sebagai error)
+func isEmptyFinancialRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseFinancialRow mem-parse dan memvalidasi satu baris data (urutan kolom template).
+// Semua kolom divalidasi agar error report memuat seluruh masalah pada baris, bukan hanya yang pertama.
+func parseFinancialRow(source financialSourceRow, resolve financialCompanyResolver) ParsedFinancialRow {
+	row := source.Cells
+	parsed := ParsedFinancialRow{
+		RowNumber: source.RowNumber,
+		RawValues: make([]string, len(FinancialImportHeaders())),
+	}
+	copy(parsed.RawValues, row)
+
+	cell := func(col int) string {
+		if col < len(row) {
+			return strings.TrimSpace(row[col])
+		}
+		return ""
+	}
+
+	// Kode Perusahaan (wajib)
+	parsed.CompanyCode = cell(financialImportColCompanyCode)
+	if parsed.CompanyCode == "" {
+		parsed.addError("Kode Perusahaan", "Kode Perusahaan wajib diisi")
+	} else if company := resolve(parsed.CompanyCode); company == nil {
+		parsed.addError("Kode Perusahaan", fmt.Sprintf("Kode Perusahaan '%s' tidak ditemukan atau tidak dapat diakses", parsed.CompanyCode))
+	} else {
+		parsed.Request.CompanyID = company.ID
+	}
+
+	// Nama Perusahaan hanya referensi, tidak divalidasi
+
+	// Tahun (wajib, YYYY)
+	year, err := parseImportYear(cell(financialImportColYear))
+	if err != nil {
+		parsed.addError("Tahun", err.Error())
+	}
+
+	// Bulan (wajib, 1-12)
+	month, err := parseImportMonth(cell(financialImportColMonth))
+	if err != nil {
+		parsed.addError("Bulan", err.Error())
+	}
+
+	// Bulk upload selalu realisasi bulanan (RKAP diinput per tahun lewat form)
+	parsed.Request.IsRKAP = false
+	if year != "" && month > 0 {
+		parsed.Request.Year = year
+		parsed.Request.Period = fmt.Sprintf("%s-%02d", year, month)
+	}
+
+	target := reflect.ValueOf(&parsed.Request).Elem()
+	for i, field := range financialImportFields {
+		valueStr := cell(financialImportColFirstValue + i)
+		if valueStr == "" {
+			continue // Kolom numerik opsional, kosong = 0
+		}
+		if field.IsFloat {
+			value, err := parseImportFloat(valueStr, field.AllowNegative, field.IsPercentage)
+			if err != nil {
+				parsed.addError(field.Header, fmt.Sprintf("%s: %v", field.Header, err))
+				continue
+			}
+			target.FieldByName(field.Field).SetFloat(value)
+		} else {
+			value, err := parseImportInt64(valueStr, field.AllowNegative)
+			if err != nil {
+				parsed.addError(field.Header, fmt.Sprintf("%s: %v", field.Header, err))
+				continue
+			}
+			target.FieldByName(field.Field).SetInt(value)
+		}
+	}
+
+	// Keterangan (opsional)
+	if remark := cell(financialImportColFirstValue + len(financialImportFields)); remark != "" {
+		parsed.Request.Remark = &remark
+	}
+
+	for _, problem := range source.Problems {
+		parsed.addError(problem.Column, problem.Message)
+	}
+
+	return parsed
+}
+
+// parseImportYear mem-parse tahun dengan fleksibel (Excel bisa mengembalikan "2025" atau "2025.0")
+func parseImportYear(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("Tahun wajib diisi dengan format YYYY")
+	}
+
+	yearFloat, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return "", fmt.Errorf("tahun harus berupa 4 digit (format YYYY), nilai yang diterima: '%s'", value)
+	}
+	yearInt := int(yearFloat)
+	if yearFloat != float64(yearInt) {
+		return "", fmt.Errorf("tahun harus berupa bilangan bulat, nilai: '%s'", value)
+	}
+	if yearInt < 1900 || yearInt > 2100 {
+		return "", fmt.Errorf("tahun harus antara 1900-2100, nilai: '%s'", value)
+	}
+	return fmt.Sprintf("%04d", yearInt), nil
+}
+
+// parseImportMonth mem-parse bulan dengan fleksibel (Excel bisa mengembalikan "1" atau "1.0")
+func parseImportMonth(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, fmt.Errorf("Bulan wajib diisi (1-12)")
+	}
+
+	monthFloat, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("bulan harus berupa angka (1-12), nilai yang diterima: '%s'", value)
+	}
+	monthInt := int(monthFloat)
+	if monthFloat != float64(monthInt) {
+		return 0, fmt.Errorf("bulan harus berupa bilangan bulat (1-12), nilai: '%s'", value)
+	}
+	if monthInt < 1 || monthInt > 12 {
+		return 0, fmt.Errorf("bulan harus antara 1-12, nilai: '%s'", value)
+	}
+	return monthInt, nil
+}
+
+// parseImportInt64 mem-parse nilai rupiah (int64, dibatasi range bigint PostgreSQL)
+func parseImportInt64(value string, allowNegative bool) (int64, error) {
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("harus berupa angka")
+	}
+	if !allowNegative && parsed < 0 {
+		return 0, fmt.Errorf("nilai tidak boleh negatif")
+	}
+	if parsed > float64(math.MaxInt64) {
+		return 0, fmt.Errorf("nilai terlalu besar (maksimal %d)", int64(math.MaxInt64))
+	}
+	if parsed < float64(math.MinInt64) {
+		return 0, fmt.Errorf("nilai terlalu kecil (minimal %d)", int64(math.MinInt64))
+	}
+	return int64(parsed), nil
+}
+
+// parseImportFloat mem-parse rasio decimal(10,2); dibulatkan 2 desimal sesuai yang tersimpan di database
+func parseImportFloat(value string, allowNegative, isPercentage bool) (float64, error) {
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("harus berupa angka")
+	}
+	if !allowNegative && parsed < 0 {
+		return 0, fmt.Errorf("nilai tidak boleh negatif")
+	}
+	if parsed > MaxDecimal10_2 {
+		return 0, fmt.Errorf("nilai terlalu besar (maksimal %.2f)", MaxDecimal10_2)
+	}
+	if isPercentage && parsed > 100 {
+		return 0, fmt.Errorf("nilai tidak boleh melebihi 100%%")
+	}
+	return math.Round(parsed*100) / 100, nil
+}
@@ -0,0 +1,746 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/audit"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// financialImportProgressEvery menentukan seberapa sering progress job disimpan (per N baris)
+const financialImportProgressEvery = 50
+
+var (
+	ErrFinancialImportNotReady    = errors.New("job import belum siap di-commit")
+	ErrFinancialImportNoRows      = errors.New("tidak ada baris yang bisa di-commit")
+	ErrFinancialImportNoErrorRows = errors.New("job import tidak memiliki baris error")
+	ErrFinancialFormatUnsupported = errors.New("format file tidak didukung, gunakan xlsx, csv, atau json")
+	ErrInvalidAccountMapping      = errors.New("mapping akun tidak valid")
+)
+
+// FinancialImportActor identitas user yang melakukan import (untuk inputter dan audit trail)
+type FinancialImportActor struct {
+	UserID    string
+	Username  string
+	IPAddress string
+	UserAgent string
+}
+
+// FinancialImportResult ringkasan import langsung (tanpa staging)
+type FinancialImportResult struct {
+	Success int                              `json:"success"`
+	Failed  int                              `json:"failed"`
+	Created int                              `json:"created"`
+	Updated int                              `json:"updated"`
+	Errors  []domain.FinancialImportRowError `json:"errors"`
+}
+
+// FinancialImportUseCase interface untuk parsing workbook bulk upload dan job import dengan staging
+type FinancialImportUseCase interface {
+	// ValidateWorkbook mem-parse workbook tanpa menyimpan apa pun
+	ValidateWorkbook(data []byte, accessibleCodes map[string]bool) ([]ParsedFinancialRow, error)
+	// ImportWorkbook mem-parse dan langsung menyimpan semua baris valid (upsert)
+	ImportWorkbook(data []byte, accessibleCodes map[string]bool, actor FinancialImportActor) (*FinancialImportResult, error)
+
+	// CreateJob membuat job import; format kosong = dideteksi dari ekstensi file
+	CreateJob(fileName, format string, data []byte, accessibleCodes map[string]bool, actor FinancialImportActor) (*domain.FinancialImportJobModel, error)
+	GetJob(id string) (*domain.FinancialImportJobModel, error)
+	ListJobs(filter repository.FinancialImportJobFilter) ([]domain.FinancialImportJobModel, int64, error)
+	GetJobRows(jobID string, filter repository.FinancialImportRowFilter) ([]domain.FinancialImportRowModel, int64, error)
+	CommitJob(jobID string, req *domain.CommitFinancialImportRequest, actor FinancialImportActor) (*domain.FinancialImportJobModel, error)
+	BuildErrorReport(jobID string) ([]byte, error)
+	RecoverInterruptedJobs() (int64, error)
+
+	GetAccountMappings(companyID string) ([]domain.FinancialAccountMappingModel, error)
+	SetAccountMappings(companyID string, req *domain.SetFinancialAccountMappingsRequest, actor FinancialImportActor) ([]domain.FinancialAccountMappingModel, error)
+}
+
+type financialImportUseCase struct {
+	importRepo  repository.FinancialImportRepository
+	reportRepo  repository.FinancialReportRepository
+	companyRepo repository.CompanyRepository
+	mappingRepo repository.FinancialAccountMappingRepository
+	reportUC    FinancialReportUseCase
+	runAsync    func(fn func()) // Bisa diganti di test agar job berjalan sinkron
+	now         func() time.Time
+}
+
+// NewFinancialImportUseCaseWithDB creates a new financial import use case with injected DB
+func NewFinancialImportUseCaseWithDB(db *gorm.DB) FinancialImportUseCase {
+	return &financialImportUseCase{
+		importRepo:  repository.NewFinancialImportRepositoryWithDB(db),
+		reportRepo:  repository.NewFinancialReportRepositoryWithDB(db),
+		companyRepo: repository.NewCompanyRepositoryWithDB(db),
+		mappingRepo: repository.NewFinancialAccountMappingRepositoryWithDB(db),
+		reportUC:    NewFinancialReportUseCaseWithDB(db),
+		runAsync:    func(fn func()) { go fn() },
+		now:         time.Now,
+	}
+}
+
+// NewFinancialImportUseCase creates a new financial import use case with default DB
+func NewFinancialImportUseCase() FinancialImportUseCase {
+	return NewFinancialImportUseCaseWithDB(database.GetDB())
+}
+
+// companyResolver membuat resolver kode company dengan cache per workbook.
+// accessibleCodes nil = semua company aktif boleh diakses (superadmin/administrator).
+func (uc *financialImportUseCase) companyResolver(accessibleCodes map[string]bool) financialCompanyResolver {
+	cache := make(map[string]*domain.CompanyModel)
+	return func(code string) *domain.CompanyModel {
+		if accessibleCodes != nil && !accessibleCodes[code] {
+			return nil
+		}
+		if company, ok := cache[code]; ok {
+			return company
+		}
+		company, err := uc.companyRepo.GetByCode(code)
+		if err != nil || !company.IsActive {
+			company = nil
+		}
+		cache[code] = company
+		return company
+	}
+}
+
+// accountResolver membuat resolver mapping kode akun per company (dimuat sekali per company per file)
+func (uc *financialImportUseCase) accountResolver(resolve financialCompanyResolver) financialAccountResolver {
+	cache := make(map[string]map[string]financialAccountMapping)
+	return func(companyCode, accountCode string) (financialAccountMapping, bool) {
+		mappings, ok := cache[companyCode]
+		if !ok {
+			mappings = make(map[string]financialAccountMapping)
+			if company := resolve(companyCode); company != nil {
+				stored, err := uc.mappingRepo.GetByCompanyID(company.ID)
+				if err != nil {
+					logger.GetLogger().Warn("Failed to load financial account mappings", zap.String("company_id", company.ID), zap.Error(err))
+				}
+				for _, mapping := range stored {
+					if index, found := lookupFinancialField(mapping.Concept); found {
+						mappings[mapping.AccountCode] = financialAccountMapping{FieldIndex: index, Multiplier: mapping.Multiplier}
+					}
+				}
+			}
+			cache[companyCode] = mappings
+		}
+		mapping, ok := mappings[accountCode]
+		return mapping, ok
+	}
+}
+
+// decodeRows membaca file sesuai format dan mengembalikan baris data beserta resolver company-nya
+func (uc *financialImportUseCase) decodeRows(format FinancialReportFormat, data []byte, accessibleCodes map[string]bool) ([]financialSourceRow, financialCompanyResolver, error) {
+	resolve := uc.companyResolver(accessibleCodes)
+	rows, err := format.decode(data, uc.accountResolver(resolve))
+	return rows, resolve, err
+}
+
+// parseWorkbook mem-parse seluruh baris data workbook (baris kosong dilewati)
+func (uc *financialImportUseCase) parseWorkbook(data []byte, accessibleCodes map[string]bool) ([]ParsedFinancialRow, error) {
+	rows, resolve, err := uc.decodeRows(xlsxFinancialFormat{}, data, accessibleCodes)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := make([]ParsedFinancialRow, 0, len(rows))
+	for _, row := range rows {
+		parsed = append(parsed, parseFinancialRow(row, resolve))
+	}
+	return parsed, nil
+}
+
+func (uc *financialImportUseCase) ValidateWorkbook(data []byte, accessibleCodes map[string]bool) ([]ParsedFinancialRow, error) {
+	return uc.parseWorkbook(data, accessibleCodes)
+}
+
+func (uc *financialImportUseCase) ImportWorkbook(data []byte, accessibleCodes map[string]bool, actor FinancialImportActor) (*FinancialImportResult, error) {
+	parsed, err := uc.parseWorkbook(data, accessibleCodes)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &FinancialImportResult{Errors: []domain.FinancialImportRowError{}}
+	for i := range parsed {
+		row := &parsed[i]
+		if !row.Valid() {
+			result.Errors = append(result.Errors, row.Errors...)
+			result.Failed++
+			continue
+		}
+
+		_, created, rowErr := uc.upsertReport(&row.Request, row.RowNumber, actor)
+		if rowErr != nil {
+			result.Errors = append(result.Errors, *rowErr)
+			result.Failed++
+			continue
+		}
+		if created {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+		result.Success++
+	}
+
+	zapLog := logger.GetLogger()
+	zapLog.Info("Bulk upload completed",
+		zap.Int("success", result.Success),
+		zap.Int("failed", result.Failed),
+		zap.Int("created", result.Created),
+		zap.Int("updated", result.Updated),
+		zap.Int("total_errors", len(result.Errors)),
+	)
+	if len(result.Errors) > 0 {
+		zapLog.Warn("Upload errors found", zap.Any("errors", result.Errors))
+	}
+
+	return result, nil
+}
+
+// upsertReport menyimpan satu baris: update jika realisasi periode tersebut sudah ada, create jika belum.
+// Error dikembalikan dalam format per baris (kolom yang bermasalah + pesan).
+func (uc *financialImportUseCase) upsertReport(req *domain.CreateFinancialReportRequest, rowNum int, actor FinancialImportActor) (string, bool, *domain.FinancialImportRowError) {
+	existing, err := uc.reportRepo.GetRealisasiByCompanyIDPeriodAndAudit(req.CompanyID, req.Period, false)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", false, &domain.FinancialImportRowError{
+			Row:     rowNum,
+			Column:  "general",
+			Message: fmt.Sprintf("Error checking existing Realisasi: %v", err),
+		}
+	}
+
+	if existing != nil {
+		updateReq := financialImportUpdateRequest(req)
+		report, err := uc.reportUC.UpdateFinancialReport(existing.ID, updateReq, actor.UserID, actor.Username, actor.IPAddress, actor.UserAgent)
+		if err != nil {
+			return "", false, financialImportCommitError(err, req, rowNum)
+		}
+		return report.ID, false, nil
+	}
+
+	report, err := uc.reportUC.CreateFinancialReport(req, actor.UserID, actor.Username, actor.IPAddress, actor.UserAgent)
+	if err != nil {
+		return "", false, financialImportCommitError(err, req, rowNum)
+	}
+	return report.ID, true, nil
+}
+
+// financialImportUpdateRequest mengubah request create menjadi update yang menimpa semua field
+func financialImportUpdateRequest(req *domain.CreateFinancialReportRequest) *domain.UpdateFinancialReportRequest {
+	updateReq := &domain.UpdateFinancialReportRequest{
+		Year:   &req.Year,
+		Period: &req.Period,
+		IsRKAP: &req.IsRKAP,
+		Remark: req.Remark,
+	}
+	source := reflect.ValueOf(req).Elem()
+	target := reflect.ValueOf(updateReq).Elem()
+	for _, field := range financialImportFields {
+		target.FieldByName(field.Field).Set(source.FieldByName(field.Field).Addr())
+	}
+	return updateReq
+}
+
+// financialImportCommitError menerjemahkan error create/update menjadi error per kolom
+func financialImportCommitError(err error, req *domain.CreateFinancialReportRequest, rowNum int) *domain.FinancialImportRowError {
+	errMsg := err.Error()
+	column := "general"
+
+	if strings.Contains(errMsg, "rasio keuangan tidak boleh melebihi 100%") {
+		source := reflect.ValueOf(req).Elem()
+		for _, field := range financialImportFields {
+			if field.IsPercentage && source.FieldByName(field.Field).Float() > 100 {
+				column = field.Header
+				break
+			}
+		}
+		errMsg = fmt.Sprintf("%s: nilai tidak boleh melebihi 100%%", column)
+	} else if strings.Contains(errMsg, "numeric field overflow") || strings.Contains(errMsg, "SQLSTATE 22003") {
+		column = "Data numerik terlalu besar"
+		errMsg = "Nilai terlalu besar untuk disimpan. Pastikan: int64 tidak melebihi 9,223,372,036,854,775,807 dan persentase/rasio tidak melebihi 99,999,999.99"
+	}
+
+	return &domain.FinancialImportRowError{Row: rowNum, Column: column, Message: errMsg}
+}
+
+// CreateJob membuat job import dan menjalankan parsing + staging di background
+func (uc *financialImportUseCase) CreateJob(fileName, formatName string, data []byte, accessibleCodes map[string]bool, actor FinancialImportActor) (*domain.FinancialImportJobModel, error) {
+	format, ok := GetFinancialReportFormat(formatName)
+	if formatName == "" {
+		format, ok = DetectFinancialReportFormat(fileName)
+	}
+	if !ok {
+		return nil, ErrFinancialFormatUnsupported
+	}
+
+	job := &domain.FinancialImportJobModel{
+		ID:            uuid.GenerateUUID(),
+		FileName:      fileName,
+		Format:        format.Name(),
+		Status:        domain.FinancialImportStatusProcessing,
+		CreatedBy:     actor.UserID,
+		CreatedByName: actor.Username,
+		StartedAt:     uc.now(),
+	}
+	if err := uc.importRepo.CreateJob(job); err != nil {
+		return nil, fmt.Errorf("failed to create import job: %w", err)
+	}
+
+	audit.LogAction(actor.UserID, actor.Username, audit.ActionImportFinancialReports, audit.ResourceFinancialReport, job.ID, actor.IPAddress, actor.UserAgent, "success", map[string]interface{}{
+		"file_name": fileName,
+		"format":    format.Name(),
+	})
+
+	uc.runAsync(func() {
+		uc.processJob(job, format, data, accessibleCodes)
+	})
+
+	return job, nil
+}
+
+// processJob mem-parse file, menghitung diff per baris, dan menyimpan hasilnya ke staging
+func (uc *financialImportUseCase) processJob(job *domain.FinancialImportJobModel, format FinancialReportFormat, data []byte, accessibleCodes map[string]bool) {
+	zapLog := logger.GetLogger()
+	defer func() {
+		if r := recover(); r != nil {
+			zapLog.Error("Financial import job panicked", zap.String("job_id", job.ID), zap.Any("panic", r))
+			uc.failJob(job, "Terjadi kesalahan internal saat memproses file")
+		}
+	}()
+
+	dataRows, resolve, err := uc.decodeRows(format, data, accessibleCodes)
+	if err != nil {
+		uc.failJob(job, err.Error())
+		return
+	}
+
+	job.TotalRows = len(dataRows)
+	if err := uc.importRepo.UpdateJobProgress(job.ID, map[string]interface{}{"total_rows": job.TotalRows}); err != nil {
+		zapLog.Warn("Failed to update import job progress", zap.String("job_id", job.ID), zap.Error(err))
+	}
+
+	seen := make(map[string]int) // company_id|period -> nomor baris pertama
+	batch := make([]domain.FinancialImportRowModel, 0, financialImportProgressEvery)
+	counts := make(map[string]int)
+
+	for i, row := range dataRows {
+		parsed := parseFinancialRow(row, resolve)
+		staged := uc.stageRow(job.ID, &parsed, seen)
+		counts[staged.Status]++
+		batch = append(batch, staged)
+
+		if len(batch) == financialImportProgressEvery || i == len(dataRows)-1 {
+			if err := uc.importRepo.CreateRows(batch); err != nil {
+				zapLog.Error("Failed to stage import rows", zap.String("job_id", job.ID), zap.Error(err))
+				uc.failJob(job, "Gagal menyimpan data staging")
+				return
+			}
+			batch = batch[:0]
+			if err := uc.importRepo.UpdateJobProgress(job.ID, map[string]interface{}{"processed_rows": i + 1}); err != nil {
+				zapLog.Warn("Failed to update import job progress", zap.String("job_id", job.ID), zap.Error(err))
+			}
+		}
+	}
+
+	stagedAt := uc.now()
+	job.ProcessedRows = len(dataRows)
+	job.NewRows = counts[domain.FinancialImportRowStatusNew]
+	job.OverwriteRows = counts[domain.FinancialImportRowStatusOverwrite]
+	job.UnchangedRows = counts[domain.FinancialImportRowStatusUnchanged]
+	job.ErrorRows = counts[domain.FinancialImportRowStatusError]
+	job.Status = domain.FinancialImportStatusReady
+	job.StagedAt = &stagedAt
+	if err := uc.importRepo.UpdateJob(job); err != nil {
+		zapLog.Error("Failed to finish import job", zap.String("job_id", job.ID), zap.Error(err))
+		return
+	}
+
+	zapLog.Info("Financial import job staged",
+		zap.String("job_id", job.ID),
+		zap.Int("total", job.TotalRows),
+		zap.Int("new", job.NewRows),
+		zap.Int("overwrite", job.OverwriteRows),
+		zap.Int("unchanged", job.UnchangedRows),
+		zap.Int("error", job.ErrorRows),
+	)
+}
+
+func (uc *financialImportUseCase) failJob(job *domain.FinancialImportJobModel, message string) {
+	job.Status = domain.FinancialImportStatusFailed
+	job.ErrorMessage = message
+	if err := uc.importRepo.UpdateJob(job); err != nil {
+		logger.GetLogger().Error("Failed to mark import job as failed", zap.String("job_id", job.ID), zap.Error(err))
+	}
+}
+
+// stageRow menentukan status diff baris terhadap financial report yang sudah ada
+func (uc *financialImportUseCase) stageRow(jobID string, parsed *ParsedFinancialRow, seen map[string]int) domain.FinancialImportRowModel {
+	rawValues, _ := json.Marshal(parsed.RawValues)
+	staged := domain.FinancialImportRowModel{
+		ID:          uuid.GenerateUUID(),
+		JobID:       jobID,
+		RowNumber:   parsed.RowNumber,
+		CompanyCode: parsed.CompanyCode,
+		CompanyID:   parsed.Request.CompanyID,
+		Year:        parsed.Request.Year,
+		Period:      parsed.Request.Period,
+		RawValues:   datatypes.JSON(rawValues),
+	}
+
+	if parsed.Valid() {
+		key := parsed.Request.CompanyID + "|" + parsed.Request.Period
+		if firstRow, ok := seen[key]; ok {
+			parsed.addError("Kode Perusahaan", fmt.Sprintf("Duplikat dengan baris %d (perusahaan dan periode sama)", firstRow))
+		} else {
+			seen[key] = parsed.RowNumber
+		}
+	}
+
+	if parsed.Valid() {
+		existing, err := uc.reportRepo.GetRealisasiByCompanyIDPeriodAndAudit(parsed.Request.CompanyID, parsed.Request.Period, false)
+		switch {
+		case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+			parsed.addError("general", fmt.Sprintf("Error checking existing Realisasi: %v", err))
+		case existing == nil:
+			staged.Status = domain.FinancialImportRowStatusNew
+		default:
+			staged.ExistingReportID = &existing.ID
+			proposed := *existing
+			applyFinancialImportRequest(&proposed, &parsed.Request)
+			if changes := audit.DiffModels(existing, &proposed); len(changes) > 0 {
+				staged.Status = domain.FinancialImportRowStatusOverwrite
+				changesJSON, _ := json.Marshal(changes)
+				staged.Changes = datatypes.JSON(changesJSON)
+			} else {
+				staged.Status = domain.FinancialImportRowStatusUnchanged
+			}
+		}
+	}
+
+	if !parsed.Valid() {
+		staged.Status = domain.FinancialImportRowStatusError
+		errorsJSON, _ := json.Marshal(parsed.Errors)
+		staged.Errors = datatypes.JSON(errorsJSON)
+	}
+
+	requestJSON, _ := json.Marshal(parsed.Request)
+	staged.Data = datatypes.JSON(requestJSON)
+	return staged
+}
+
+// applyFinancialImportRequest menerapkan nilai baris import ke salinan report (sama dengan efek update saat commit)
+func applyFinancialImportRequest(report *domain.FinancialReportModel, req *domain.CreateFinancialReportRequest) {
+	source := reflect.ValueOf(req).Elem()
+	target := reflect.ValueOf(report).Elem()
+	for _, field := range financialImportFields {
+		target.FieldByName(field.Field).Set(source.FieldByName(field.Field))
+	}
+	if req.Remark != nil {
+		report.Remark = req.Remark
+	}
+}
+
+func (uc *financialImportUseCase) GetJob(id string) (*domain.FinancialImportJobModel, error) {
+	return uc.importRepo.GetJobByID(id)
+}
+
+func (uc *financialImportUseCase) ListJobs(filter repository.FinancialImportJobFilter) ([]domain.FinancialImportJobModel, int64, error) {
+	return uc.importRepo.ListJobs(filter)
+}
+
+func (uc *financialImportUseCase) GetJobRows(jobID string, filter repository.FinancialImportRowFilter) ([]domain.FinancialImportRowModel, int64, error) {
+	return uc.importRepo.GetRows(jobID, filter)
+}
+
+// CommitJob menyimpan baris staging terpilih ke financial_reports di background.
+// Baris error/unchanged dan baris yang sudah ter-commit dilewati; baris yang gagal boleh di-commit ulang.
+func (uc *financialImportUseCase) CommitJob(jobID string, req *domain.CommitFinancialImportRequest, actor FinancialImportActor) (*domain.FinancialImportJobModel, error) {
+	job, err := uc.importRepo.GetJobByID(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != domain.FinancialImportStatusReady && job.Status != domain.FinancialImportStatusCompleted {
+		return nil, ErrFinancialImportNotReady
+	}
+
+	filter := repository.FinancialImportRowFilter{RowNumbers: req.RowNumbers}
+	candidates, _, err := uc.importRepo.GetRows(jobID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get staged rows: %w", err)
+	}
+
+	var selected []domain.FinancialImportRowModel
+	for _, row := range candidates {
+		if row.CommitStatus == domain.FinancialImportCommitCommitted {
+			continue
+		}
+		switch row.Status {
+		case domain.FinancialImportRowStatusNew:
+			selected = append(selected, row)
+		case domain.FinancialImportRowStatusOverwrite:
+			if len(req.RowNumbers) > 0 || !req.SkipOverwrite {
+				selected = append(selected, row)
+			}
+		}
+	}
+	if len(selected) == 0 {
+		return nil, ErrFinancialImportNoRows
+	}
+
+	claimed, err := uc.importRepo.TransitionJob(jobID,
+		[]string{domain.FinancialImportStatusReady, domain.FinancialImportStatusCompleted},
+		map[string]interface{}{
+			"status":         domain.FinancialImportStatusCommitting,
+			"commit_total":   len(selected),
+			"committed_rows": 0,
+			"failed_rows":    0,
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start commit: %w", err)
+	}
+	if !claimed {
+		return nil, ErrFinancialImportNotReady
+	}
+
+	job.Status = domain.FinancialImportStatusCommitting
+	job.CommitTotal = len(selected)
+	job.CommittedRows = 0
+	job.FailedRows = 0
+
+	uc.runAsync(func() {
+		uc.commitRows(job, selected, actor)
+	})
+
+	return job, nil
+}
+
+func (uc *financialImportUseCase) commitRows(job *domain.FinancialImportJobModel, rows []domain.FinancialImportRowModel, actor FinancialImportActor) {
+	zapLog := logger.GetLogger()
+	defer func() {
+		if r := recover(); r != nil {
+			zapLog.Error("Financial import commit panicked", zap.String("job_id", job.ID), zap.Any("panic", r))
+			uc.failJob(job, "Terjadi kesalahan internal saat menyimpan data")
+		}
+	}()
+
+	for i := range rows {
+		row := &rows[i]
+		var req domain.CreateFinancialReportRequest
+		if err := json.Unmarshal(row.Data, &req); err != nil {
+			row.CommitStatus = domain.FinancialImportCommitFailed
+			row.CommitError = "Data staging tidak valid"
+		} else if reportID, _, rowErr := uc.upsertReport(&req, row.RowNumber, actor); rowErr != nil {
+			row.CommitStatus = domain.FinancialImportCommitFailed
+			row.CommitError = rowErr.Message
+		} else {
+			row.CommitStatus = domain.FinancialImportCommitCommitted
+			row.CommitError = ""
+			row.CommittedReportID = &reportID
+		}
+
+		if row.CommitStatus == domain.FinancialImportCommitCommitted {
+			job.CommittedRows++
+		} else {
+			job.FailedRows++
+		}
+		if err := uc.importRepo.UpdateRow(row); err != nil {
+			zapLog.Warn("Failed to update staged row", zap.String("job_id", job.ID), zap.Int("row", row.RowNumber), zap.Error(err))
+		}
+
+		if (i+1)%financialImportProgressEvery == 0 {
+			if err := uc.importRepo.UpdateJobProgress(job.ID, map[string]interface{}{
+				"committed_rows": job.CommittedRows,
+				"failed_rows":    job.FailedRows,
+			}); err != nil {
+				zapLog.Warn("Failed to update import job progress", zap.String("job_id", job.ID), zap.Error(err))
+			}
+		}
+	}
+
+	committedAt := uc.now()
+	job.Status = domain.FinancialImportStatusCompleted
+	job.CommittedAt = &committedAt
+	if err := uc.importRepo.UpdateJob(job); err != nil {
+		zapLog.Error("Failed to finish import commit", zap.String("job_id", job.ID), zap.Error(err))
+	}
+
+	audit.LogAction(actor.UserID, actor.Username, audit.ActionCommitFinancialImport, audit.ResourceFinancialReport, job.ID, actor.IPAddress, actor.UserAgent, "success", map[string]interface{}{
+		"file_name": job.FileName,
+		"selected":  job.CommitTotal,
+		"committed": job.CommittedRows,
+		"failed":    job.FailedRows,
+	})
+}
+
+// BuildErrorReport membuat workbook berisi baris yang gagal validasi atau gagal di-commit.
+// Kolom sama dengan template + kolom "Error", sehingga file bisa diperbaiki dan di-upload ulang.
+func (uc *financialImportUseCase) BuildErrorReport(jobID string) ([]byte, error) {
+	rows, _, err := uc.importRepo.GetRows(jobID, repository.FinancialImportRowFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	var errorRows []domain.FinancialImportRowModel
+	for _, row := range rows {
+		if row.Status == domain.FinancialImportRowStatusError || row.CommitStatus == domain.FinancialImportCommitFailed {
+			errorRows = append(errorRows, row)
+		}
+	}
+	if len(errorRows) == 0 {
+		return nil, ErrFinancialImportNoErrorRows
+	}
+
+	f := excelize.NewFile()
+	defer func() {
+		if err := f.Close(); err != nil {
+			logger.GetLogger().Warn("Failed to close Excel file", zap.Error(err))
+		}
+	}()
+
+	sheetName := "Errors"
+	if err := f.SetSheetName("Sheet1", sheetName); err != nil {
+		return nil, err
+	}
+
+	headers := append(FinancialImportHeaders(), "Baris Asal", "Error")
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"#E0E0E0"}, Pattern: 1},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		if err := f.SetCellValue(sheetName, cell, header); err != nil {
+			return nil, err
+		}
+		if err := f.SetCellStyle(sheetName, cell, cell, headerStyle); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, row := range errorRows {
+		excelRow := i + 2
+		var rawValues []string
+		_ = json.Unmarshal(row.RawValues, &rawValues)
+		values := make([]interface{}, 0, len(headers))
+		for _, value := range rawValues {
+			values = append(values, value)
+		}
+		for len(values) < len(headers)-2 {
+			values = append(values, "")
+		}
+		values = append(values, row.RowNumber, financialImportRowErrorText(&row))
+
+		cell, _ := excelize.CoordinatesToCellName(1, excelRow)
+		if err := f.SetSheetRow(sheetName, cell, &values); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// financialImportRowErrorText menggabungkan error validasi/commit satu baris menjadi satu teks
+func financialImportRowErrorText(row *domain.FinancialImportRowModel) string {
+	if row.Status != domain.FinancialImportRowStatusError {
+		return row.CommitError
+	}
+	var rowErrors []domain.FinancialImportRowError
+	_ = json.Unmarshal(row.Errors, &rowErrors)
+	messages := make([]string, 0, len(rowErrors))
+	for _, rowErr := range rowErrors {
+		messages = append(messages, rowErr.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// RecoverInterruptedJobs dipanggil saat startup untuk job yang terhenti karena server restart.
+// Parsing tidak bisa dilanjutkan (file tidak disimpan) sehingga job ditandai failed; commit yang terhenti
+// dikembalikan ke completed karena baris staging masih utuh dan sisa baris bisa di-commit ulang.
+func (uc *financialImportUseCase) RecoverInterruptedJobs() (int64, error) {
+	failed, err := uc.importRepo.UpdateJobsByStatus(domain.FinancialImportStatusProcessing, map[string]interface{}{
+		"status":        domain.FinancialImportStatusFailed,
+		"error_message": "Proses terhenti karena server restart, silakan upload ulang file",
+	})
+	if err != nil {
+		return 0, err
+	}
+	reopened, err := uc.importRepo.UpdateJobsByStatus(domain.FinancialImportStatusCommitting, map[string]interface{}{
+		"status":        domain.FinancialImportStatusCompleted,
+		"error_message": "Commit terhenti karena server restart, baris yang belum tersimpan bisa di-commit ulang",
+	})
+	return failed + reopened, err
+}
+
+func (uc *financialImportUseCase) GetAccountMappings(companyID string) ([]domain.FinancialAccountMappingModel, error) {
+	return uc.mappingRepo.GetByCompanyID(companyID)
+}
+
+// SetAccountMappings mengganti seluruh mapping kode akun company ke konsep taksonomi.
+// Konsep boleh ditulis sebagai kode taksonomi (pdv:Revenue) atau key field (revenue), disimpan sebagai kode taksonomi.
+func (uc *financialImportUseCase) SetAccountMappings(companyID string, req *domain.SetFinancialAccountMappingsRequest, actor FinancialImportActor) ([]domain.FinancialAccountMappingModel, error) {
+	mappings := make([]domain.FinancialAccountMappingModel, 0, len(req.Mappings))
+	seen := make(map[string]bool)
+	for i, item := range req.Mappings {
+		accountCode := strings.TrimSpace(item.AccountCode)
+		if accountCode == "" {
+			return nil, fmt.Errorf("%w: mapping ke-%d: account_code wajib diisi", ErrInvalidAccountMapping, i+1)
+		}
+		if seen[accountCode] {
+			return nil, fmt.Errorf("%w: mapping ke-%d: account_code '%s' duplikat", ErrInvalidAccountMapping, i+1, accountCode)
+		}
+		seen[accountCode] = true
+
+		index, ok := lookupFinancialField(item.Concept)
+		if !ok {
+			return nil, fmt.Errorf("%w: mapping ke-%d: konsep '%s' tidak dikenal", ErrInvalidAccountMapping, i+1, item.Concept)
+		}
+		multiplier := 1.0
+		if item.Multiplier != nil {
+			multiplier = *item.Multiplier
+		}
+		if multiplier == 0 {
+			return nil, fmt.Errorf("%w: mapping ke-%d: multiplier tidak boleh 0", ErrInvalidAccountMapping, i+1)
+		}
+
+		mappings = append(mappings, domain.FinancialAccountMappingModel{
+			ID:          uuid.GenerateUUID(),
+			CompanyID:   companyID,
+			AccountCode: accountCode,
+			AccountName: strings.TrimSpace(item.AccountName),
+			Concept:     financialImportFields[index].Concept,
+			Multiplier:  multiplier,
+			CreatedBy:   actor.UserID,
+		})
+	}
+
+	if err := uc.mappingRepo.ReplaceForCompany(companyID, mappings); err != nil {
+		return nil, fmt.Errorf("failed to save account mappings: %w", err)
+	}
+
+	audit.LogAction(actor.UserID, actor.Username, audit.ActionUpdateAccountMapping, audit.ResourceFinancialReport, companyID, actor.IPAddress, actor.UserAgent, "success", map[string]interface{}{
+		"mapping_count": len(mappings),
+	})
+
+	return uc.mappingRepo.GetByCompanyID(companyID)
+}
@@ -0,0 +1,290 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/audit"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+	"github.com/repoareta/pedeve-dms-app/backend/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+// setupTestFinancialImportUseCase creates a financial import use case whose background jobs run synchronously
+func setupTestFinancialImportUseCase(t *testing.T) (*financialImportUseCase, *gorm.DB) {
+	db := helpers.SetupTestDB(t)
+
+	err := db.AutoMigrate(
+		&domain.FinancialReportModel{},
+		&domain.FinancialImportJobModel{},
+		&domain.FinancialImportRowModel{},
+		&domain.FinancialAccountMappingModel{},
+	)
+	require.NoError(t, err)
+
+	uc := NewFinancialImportUseCaseWithDB(db).(*financialImportUseCase)
+	uc.runAsync = func(fn func()) { fn() }
+	return uc, db
+}
+
+// TestFinancialImportUseCase_StagingAndPartialCommit tests diff per baris, commit sebagian, dan error report
+func TestFinancialImportUseCase_StagingAndPartialCommit(t *testing.T) {
+	uc, db := setupTestFinancialImportUseCase(t)
+	defer helpers.CleanupTestDB(t, db)
+
+	company := createTestCompanyForNotification(t, db, nil)
+	otherCompany := createTestCompanyForNotification(t, db, nil)
+	createTestFinancialReportForImport(t, db, company.ID, "2025-01", 100)
+	marchReport := createTestFinancialReportForImport(t, db, company.ID, "2025-03", 50)
+
+	workbook := buildTestFinancialWorkbook(t, [][]interface{}{
+		{company.Code, company.Name, 2025, 1, 100},  // Baris 2: unchanged
+		{company.Code, company.Name, 2025, 2, 200},  // Baris 3: new
+		{company.Code, company.Name, 2025, 3, 75},   // Baris 4: overwrite
+		{otherCompany.Code, "", 2025, 4, 10},        // Baris 5: company tidak dapat diakses
+		{company.Code, company.Name, 2025, 13, 10},  // Baris 6: bulan tidak valid
+		{company.Code, company.Name, "2025", 2, 10}, // Baris 7: duplikat baris 3
+	})
+	actor := FinancialImportActor{UserID: "importer", Username: "importer"}
+
+	job, err := uc.CreateJob("upload.xlsx", "", workbook, map[string]bool{company.Code: true}, actor)
+	require.NoError(t, err)
+
+	t.Run("Job is staged with per-row diff", func(t *testing.T) {
+		staged, err := uc.GetJob(job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.FinancialImportStatusReady, staged.Status)
+		assert.Equal(t, 6, staged.TotalRows)
+		assert.Equal(t, 6, staged.ProcessedRows)
+		assert.Equal(t, 1, staged.NewRows)
+		assert.Equal(t, 1, staged.OverwriteRows)
+		assert.Equal(t, 1, staged.UnchangedRows)
+		assert.Equal(t, 3, staged.ErrorRows)
+
+		rows, _, err := uc.GetJobRows(job.ID, repository.FinancialImportRowFilter{RowNumbers: []int{4}})
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		assert.Equal(t, domain.FinancialImportRowStatusOverwrite, rows[0].Status)
+		require.NotNil(t, rows[0].ExistingReportID)
+		assert.Equal(t, marchReport.ID, *rows[0].ExistingReportID)
+
+		var changes map[string]audit.FieldChange
+		require.NoError(t, json.Unmarshal(rows[0].Changes, &changes))
+		require.Contains(t, changes, "revenue")
+		assert.EqualValues(t, 50, changes["revenue"].Old)
+		assert.EqualValues(t, 75, changes["revenue"].New)
+
+		// Belum ada data yang berubah sebelum commit
+		assertFinancialReportRevenue(t, db, company.ID, "2025-03", 50)
+		assertFinancialReportRevenue(t, db, company.ID, "2025-02", -1)
+	})
+
+	t.Run("Commit only selected rows", func(t *testing.T) {
+		committed, err := uc.CommitJob(job.ID, &domain.CommitFinancialImportRequest{RowNumbers: []int{3, 5}}, actor)
+		require.NoError(t, err)
+		assert.Equal(t, 1, committed.CommitTotal) // Baris error 5 dilewati
+
+		current, err := uc.GetJob(job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.FinancialImportStatusCompleted, current.Status)
+		assert.Equal(t, 1, current.CommittedRows)
+
+		assertFinancialReportRevenue(t, db, company.ID, "2025-02", 200)
+		assertFinancialReportRevenue(t, db, company.ID, "2025-03", 50)
+	})
+
+	t.Run("Commit remaining rows skips already committed rows", func(t *testing.T) {
+		committed, err := uc.CommitJob(job.ID, &domain.CommitFinancialImportRequest{}, actor)
+		require.NoError(t, err)
+		assert.Equal(t, 1, committed.CommitTotal)
+		assertFinancialReportRevenue(t, db, company.ID, "2025-03", 75)
+
+		_, err = uc.CommitJob(job.ID, &domain.CommitFinancialImportRequest{}, actor)
+		assert.ErrorIs(t, err, ErrFinancialImportNoRows)
+	})
+
+	t.Run("Error report contains rejected rows", func(t *testing.T) {
+		content, err := uc.BuildErrorReport(job.ID)
+		require.NoError(t, err)
+
+		f, err := excelize.OpenReader(bytes.NewReader(content))
+		require.NoError(t, err)
+		defer f.Close()
+
+		rows, err := f.GetRows("Errors")
+		require.NoError(t, err)
+		require.Len(t, rows, 4) // Header + 3 baris error
+
+		errorCol := len(FinancialImportHeaders()) + 1
+		assert.Equal(t, "Error", rows[0][errorCol])
+		assert.Equal(t, otherCompany.Code, rows[1][0])
+		assert.Contains(t, rows[1][errorCol], "tidak dapat diakses")
+		assert.Contains(t, rows[3][errorCol], "Duplikat dengan baris 3")
+	})
+}
+
+// TestFinancialImportUseCase_InvalidWorkbook tests workbook-level validation shared by validate and upload endpoints
+func TestFinancialImportUseCase_InvalidWorkbook(t *testing.T) {
+	uc, db := setupTestFinancialImportUseCase(t)
+	defer helpers.CleanupTestDB(t, db)
+
+	_, err := uc.ValidateWorkbook([]byte("bukan excel"), nil)
+	var workbookErr *FinancialWorkbookError
+	require.ErrorAs(t, err, &workbookErr)
+
+	job, err := uc.CreateJob("kosong.xlsx", "", buildTestFinancialWorkbook(t, nil), nil, FinancialImportActor{UserID: "importer"})
+	require.NoError(t, err)
+	failed, err := uc.GetJob(job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.FinancialImportStatusFailed, failed.Status)
+	assert.NotEmpty(t, failed.ErrorMessage)
+}
+
+// TestFinancialImportUseCase_CSVWithAccountMapping tests import CSV dengan kode akun company yang dipetakan ke taksonomi
+func TestFinancialImportUseCase_CSVWithAccountMapping(t *testing.T) {
+	uc, db := setupTestFinancialImportUseCase(t)
+	defer helpers.CleanupTestDB(t, db)
+
+	company := createTestCompanyForNotification(t, db, nil)
+	actor := FinancialImportActor{UserID: "importer", Username: "importer"}
+
+	_, err := uc.SetAccountMappings(company.ID, &domain.SetFinancialAccountMappingsRequest{
+		Mappings: []domain.FinancialAccountMappingItem{{AccountCode: "4100", Concept: "pdv:Revenue"}},
+	}, actor)
+	require.NoError(t, err)
+	_, err = uc.SetAccountMappings(company.ID, &domain.SetFinancialAccountMappingsRequest{
+		Mappings: []domain.FinancialAccountMappingItem{{AccountCode: "4100", Concept: "bukan:Konsep"}},
+	}, actor)
+	assert.ErrorIs(t, err, ErrInvalidAccountMapping)
+
+	minusOne := -1.0
+	_, err = uc.SetAccountMappings(company.ID, &domain.SetFinancialAccountMappingsRequest{
+		Mappings: []domain.FinancialAccountMappingItem{
+			{AccountCode: "4100", Concept: "pdv:Revenue"},
+			{AccountCode: "4200", Concept: "revenue"},
+			{AccountCode: "5100", Concept: "pdv:OperatingExpenses", Multiplier: &minusOne},
+		},
+	}, actor)
+	require.NoError(t, err)
+
+	content := "company_code;period;4100;4200;5100;9999;net_profit\n" +
+		company.Code + ";2025-01;100;50;-30;;20\n" +
+		company.Code + ";2025-02;10;;;5;\n"
+	job, err := uc.CreateJob("erp.csv", "", []byte(content), nil, actor)
+	require.NoError(t, err)
+	assert.Equal(t, domain.FinancialFormatCSV, job.Format)
+
+	staged, err := uc.GetJob(job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.FinancialImportStatusReady, staged.Status)
+	assert.Equal(t, 1, staged.NewRows)
+	assert.Equal(t, 1, staged.ErrorRows) // Akun 9999 belum dipetakan
+
+	rows, _, err := uc.GetJobRows(job.ID, repository.FinancialImportRowFilter{RowNumbers: []int{2}})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	var data domain.CreateFinancialReportRequest
+	require.NoError(t, json.Unmarshal(rows[0].Data, &data))
+	assert.Equal(t, int64(150), data.Revenue)
+	assert.Equal(t, int64(30), data.OperatingExpenses)
+	assert.Equal(t, int64(20), data.NetProfit)
+
+	_, err = uc.CreateJob("erp.txt", "", []byte(content), nil, actor)
+	assert.ErrorIs(t, err, ErrFinancialFormatUnsupported)
+}
+
+// TestFinancialReportFormat_JSONRoundTrip tests export JSON taksonomi yang bisa di-import ulang tanpa perubahan
+func TestFinancialReportFormat_JSONRoundTrip(t *testing.T) {
+	uc, db := setupTestFinancialImportUseCase(t)
+	defer helpers.CleanupTestDB(t, db)
+
+	company := createTestCompanyForNotification(t, db, nil)
+	report := createTestFinancialReportForImport(t, db, company.ID, "2025-01", 1000)
+	report.ROE = 12.5
+	require.NoError(t, db.Save(report).Error)
+	createTestFinancialReportForImport(t, db, company.ID, "2025-02", 2000)
+
+	content, format, err := uc.reportUC.ExportFinancialReports(company.ID, "json", "2025-01", "2025-01")
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", format.ContentType())
+
+	var document FinancialFactDocument
+	require.NoError(t, json.Unmarshal(content, &document))
+	assert.Equal(t, FinancialTaxonomyVersion, document.Taxonomy)
+	require.Len(t, document.Reports, 1)
+	assert.Equal(t, company.Code, document.Reports[0].Entity)
+	assert.Len(t, document.Reports[0].Facts, len(FinancialTaxonomy()))
+
+	job, err := uc.CreateJob("export.json", "", content, nil, FinancialImportActor{UserID: "importer"})
+	require.NoError(t, err)
+	staged, err := uc.GetJob(job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, staged.UnchangedRows)
+	assert.Equal(t, 0, staged.ErrorRows)
+
+	csvContent, _, err := uc.reportUC.ExportFinancialReports(company.ID, "csv", "", "")
+	require.NoError(t, err)
+	job, err = uc.CreateJob("export.csv", "", csvContent, nil, FinancialImportActor{UserID: "importer"})
+	require.NoError(t, err)
+	staged, err = uc.GetJob(job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, staged.UnchangedRows)
+}
+
+func createTestFinancialReportForImport(t *testing.T, db *gorm.DB, companyID, period string, revenue int64) *domain.FinancialReportModel {
+	report := &domain.FinancialReportModel{
+		ID:        uuid.GenerateUUID(),
+		CompanyID: companyID,
+		Year:      period[:4],
+		Period:    period,
+		Revenue:   revenue,
+	}
+	require.NoError(t, db.Create(report).Error)
+	return report
+}
+
+// buildTestFinancialWorkbook membuat workbook sesuai template dengan kolom Kode, Nama, Tahun, Bulan, Pendapatan
+func buildTestFinancialWorkbook(t *testing.T, rows [][]interface{}) []byte {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	headers := FinancialImportHeaders()
+	headerValues := make([]interface{}, len(headers))
+	for i, header := range headers {
+		headerValues[i] = header
+	}
+	require.NoError(t, f.SetSheetRow("Sheet1", "A1", &headerValues))
+
+	revenueCol := financialImportColFirstValue + 5 // Pendapatan
+	for i, row := range rows {
+		values := make([]interface{}, len(headers))
+		copy(values, row[:4])
+		values[revenueCol] = row[4]
+		cell, err := excelize.CoordinatesToCellName(1, i+2)
+		require.NoError(t, err)
+		require.NoError(t, f.SetSheetRow("Sheet1", cell, &values))
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, f.Write(&buf))
+	return buf.Bytes()
+}
+
+// assertFinancialReportRevenue mengecek revenue realisasi (expected -1 = data belum ada)
+func assertFinancialReportRevenue(t *testing.T, db *gorm.DB, companyID, period string, expected int64) {
+	t.Helper()
+	var report domain.FinancialReportModel
+	err := db.Where("company_id = ? AND period = ? AND is_rkap = ?", companyID, period, false).First(&report).Error
+	if expected < 0 {
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		return
+	}
+	require.NoError(t, err)
+	assert.Equal(t, expected, report.Revenue)
+}
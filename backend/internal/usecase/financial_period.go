@@ -0,0 +1,429 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/audit"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidFinancialPeriod = errors.New("invalid financial period")
+
+// financialPeriodRank urutan granularitas dari paling detail, dipakai untuk de-duplikasi realisasi
+var financialPeriodRank = map[string]int{
+	domain.FinancialPeriodMonthly:   0,
+	domain.FinancialPeriodQuarterly: 1,
+	domain.FinancialPeriodSemester:  2,
+	domain.FinancialPeriodAnnual:    3,
+}
+
+// financialPeriodSpan rentang bulan (1-12) yang dicakup satu periode
+type financialPeriodSpan struct {
+	Type  string
+	Year  string
+	Start int
+	End   int
+}
+
+func (s financialPeriodSpan) months() int {
+	return s.End - s.Start + 1
+}
+
+// parseFinancialPeriod mengenali format periode: 2024-03 (monthly), 2024-Q1 (quarterly), 2024-S1 (semester), 2024 (annual)
+func parseFinancialPeriod(period string) (financialPeriodSpan, error) {
+	invalid := fmt.Errorf("%w: %s (format: YYYY-MM, YYYY-Q1..Q4, YYYY-S1..S2, atau YYYY)", ErrInvalidFinancialPeriod, period)
+	if len(period) < 4 {
+		return financialPeriodSpan{}, invalid
+	}
+	year := period[:4]
+	if _, err := strconv.Atoi(year); err != nil {
+		return financialPeriodSpan{}, invalid
+	}
+	if len(period) == 4 {
+		return financialPeriodSpan{Type: domain.FinancialPeriodAnnual, Year: year, Start: 1, End: 12}, nil
+	}
+	if len(period) != 7 || period[4] != '-' {
+		return financialPeriodSpan{}, invalid
+	}
+
+	suffix := strings.ToUpper(period[5:])
+	switch suffix[0] {
+	case 'Q':
+		quarter, err := strconv.Atoi(suffix[1:])
+		if err != nil || quarter < 1 || quarter > 4 {
+			return financialPeriodSpan{}, invalid
+		}
+		return financialPeriodSpan{Type: domain.FinancialPeriodQuarterly, Year: year, Start: quarter*3 - 2, End: quarter * 3}, nil
+	case 'S':
+		semester, err := strconv.Atoi(suffix[1:])
+		if err != nil || semester < 1 || semester > 2 {
+			return financialPeriodSpan{}, invalid
+		}
+		return financialPeriodSpan{Type: domain.FinancialPeriodSemester, Year: year, Start: semester*6 - 5, End: semester * 6}, nil
+	}
+	month, err := strconv.Atoi(suffix)
+	if err != nil || month < 1 || month > 12 {
+		return financialPeriodSpan{}, invalid
+	}
+	return financialPeriodSpan{Type: domain.FinancialPeriodMonthly, Year: year, Start: month, End: month}, nil
+}
+
+// validateFinancialPeriod memastikan format period sesuai period_type realisasi
+func validateFinancialPeriod(periodType, period string) error {
+	if _, ok := financialPeriodRank[periodType]; !ok {
+		return fmt.Errorf("period_type harus salah satu dari monthly, quarterly, semester, annual")
+	}
+	span, err := parseFinancialPeriod(period)
+	if err != nil || span.Type != periodType {
+		examples := map[string]string{
+			domain.FinancialPeriodMonthly:   "YYYY-MM (contoh: 2024-01)",
+			domain.FinancialPeriodQuarterly: "YYYY-Qn (contoh: 2024-Q1)",
+			domain.FinancialPeriodSemester:  "YYYY-Sn (contoh: 2024-S1)",
+			domain.FinancialPeriodAnnual:    "YYYY (contoh: 2024)",
+		}
+		return fmt.Errorf("period untuk realisasi %s harus format %s", periodType, examples[periodType])
+	}
+	return nil
+}
+
+// financialReportPeriodType granularitas report (data lama tanpa period_type = monthly, RKAP = annual)
+func financialReportPeriodType(report *domain.FinancialReportModel) string {
+	if report.IsRKAP {
+		return domain.FinancialPeriodAnnual
+	}
+	if report.PeriodType == "" {
+		return domain.FinancialPeriodMonthly
+	}
+	return report.PeriodType
+}
+
+// isMonthlyRealisasi true untuk realisasi bulanan management account (sumber data modul yang berbasis bulan)
+func isMonthlyRealisasi(report *domain.FinancialReportModel) bool {
+	return !report.IsRKAP && !report.IsAudited && financialReportPeriodType(report) == domain.FinancialPeriodMonthly
+}
+
+// realisasiAggregate realisasi yang dipakai untuk satu rentang bulan setelah de-duplikasi granularitas
+type realisasiAggregate struct {
+	reports []*domain.FinancialReportModel
+	spans   []financialPeriodSpan
+	covered int
+}
+
+// aggregateRealisasi memilih realisasi di dalam span. Granularitas paling detail didahulukan; report yang lebih kasar
+// hanya dipakai jika tidak ada bulannya yang sudah tercakup (misal kuartal diabaikan jika sudah ada data bulanan)
+func aggregateRealisasi(reports []domain.FinancialReportModel, span financialPeriodSpan, audited bool) realisasiAggregate {
+	type candidate struct {
+		report *domain.FinancialReportModel
+		span   financialPeriodSpan
+	}
+	candidates := make([]candidate, 0, len(reports))
+	for i := range reports {
+		report := &reports[i]
+		if report.IsRKAP || report.IsAudited != audited {
+			continue
+		}
+		reportSpan, err := parseFinancialPeriod(report.Period)
+		if err != nil || reportSpan.Type != financialReportPeriodType(report) || reportSpan.Year != span.Year ||
+			reportSpan.Start < span.Start || reportSpan.End > span.End {
+			continue
+		}
+		candidates = append(candidates, candidate{report: report, span: reportSpan})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if rank := financialPeriodRank[candidates[i].span.Type] - financialPeriodRank[candidates[j].span.Type]; rank != 0 {
+			return rank < 0
+		}
+		return candidates[i].span.Start < candidates[j].span.Start
+	})
+
+	var covered [13]bool
+	aggregate := realisasiAggregate{}
+	for _, c := range candidates {
+		overlap := false
+		for month := c.span.Start; month <= c.span.End; month++ {
+			overlap = overlap || covered[month]
+		}
+		if overlap {
+			continue
+		}
+		for month := c.span.Start; month <= c.span.End; month++ {
+			covered[month] = true
+		}
+		aggregate.reports = append(aggregate.reports, c.report)
+		aggregate.spans = append(aggregate.spans, c.span)
+		aggregate.covered += c.span.months()
+	}
+	return aggregate
+}
+
+// value menghitung nilai metrik sesuai aturan agregasi: arus dijumlah, saldo posisi terakhir, rasio rata-rata tertimbang bulan
+func (a realisasiAggregate) value(field financialImportField) *float64 {
+	if len(a.reports) == 0 {
+		return nil
+	}
+	switch financialFieldAggregation(field) {
+	case domain.FinancialAggregationLast:
+		latest := 0
+		for i := range a.spans {
+			if a.spans[i].End > a.spans[latest].End {
+				latest = i
+			}
+		}
+		value := financialMetricValue(a.reports[latest], field)
+		return &value
+	case domain.FinancialAggregationAverage:
+		total := 0.0
+		for i, report := range a.reports {
+			total += financialMetricValue(report, field) * float64(a.spans[i].months())
+		}
+		return roundedPtr(total / float64(a.covered))
+	default:
+		total := 0.0
+		for _, report := range a.reports {
+			total += financialMetricValue(report, field)
+		}
+		return &total
+	}
+}
+
+// rkapPhasingShare porsi RKAP tahunan (0-1) untuk metrik arus pada rentang bulan.
+// Bobot per metrik didahulukan, lalu bobot default (metric kosong); tanpa phasing dibagi rata 1/12 per bulan
+func rkapPhasingShare(phasings []domain.FinancialRKAPPhasingModel, metric string, start, end int) float64 {
+	selected := make([]domain.FinancialRKAPPhasingModel, 0, 12)
+	for _, phasing := range phasings {
+		if phasing.Metric == metric {
+			selected = append(selected, phasing)
+		}
+	}
+	if len(selected) == 0 {
+		for _, phasing := range phasings {
+			if phasing.Metric == "" {
+				selected = append(selected, phasing)
+			}
+		}
+	}
+	if len(selected) == 0 {
+		return float64(end-start+1) / 12
+	}
+
+	share := 0.0
+	for _, phasing := range selected {
+		span, err := parseFinancialPeriod(phasing.Period)
+		if err != nil {
+			continue
+		}
+		// Kuartal yang hanya sebagian masuk rentang dihitung pro-rata per bulan
+		overlap := min(span.End, end) - max(span.Start, start) + 1
+		if overlap > 0 {
+			share += phasing.Weight / 100 * float64(overlap) / float64(span.months())
+		}
+	}
+	return share
+}
+
+func rkapPhasingGranularity(phasings []domain.FinancialRKAPPhasingModel) string {
+	if len(phasings) == 0 {
+		return "even"
+	}
+	if span, err := parseFinancialPeriod(phasings[0].Period); err == nil {
+		return span.Type
+	}
+	return "even"
+}
+
+func (uc *financialReportUseCase) GetRKAPPhasing(rkapID string) (*domain.RKAPPhasingResponse, error) {
+	rkap, err := uc.repo.GetByID(rkapID)
+	if err != nil || !rkap.IsRKAP {
+		return nil, ErrRKAPVersionNotFound
+	}
+	phasings, err := uc.phasingRepo.GetByRKAPID(rkapID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get RKAP phasing: %w", err)
+	}
+	return rkapPhasingResponse(rkap, phasings), nil
+}
+
+func rkapPhasingResponse(rkap *domain.FinancialReportModel, phasings []domain.FinancialRKAPPhasingModel) *domain.RKAPPhasingResponse {
+	response := &domain.RKAPPhasingResponse{
+		RKAPID:      rkap.ID,
+		Year:        rkap.Year,
+		RKAPVersion: rkap.RKAPVersion,
+		Granularity: rkapPhasingGranularity(phasings),
+		Weights:     make([]domain.RKAPPhasingWeight, 0, len(phasings)),
+	}
+	for _, phasing := range phasings {
+		response.Weights = append(response.Weights, domain.RKAPPhasingWeight{
+			Period: phasing.Period,
+			Metric: phasing.Metric,
+			Weight: phasing.Weight,
+		})
+	}
+	return response
+}
+
+func (uc *financialReportUseCase) SetRKAPPhasing(rkapID string, data *domain.SetRKAPPhasingRequest, userID, username, ipAddress, userAgent string) (*domain.RKAPPhasingResponse, error) {
+	rkap, err := uc.repo.GetByID(rkapID)
+	if err != nil || !rkap.IsRKAP {
+		return nil, ErrRKAPVersionNotFound
+	}
+	if rkap.RKAPVersion > 0 && rkap.RKAPStatus != domain.RKAPStatusDraft {
+		return nil, ErrRKAPRevisionNotDraft
+	}
+
+	periods := 12
+	switch data.Granularity {
+	case domain.FinancialPeriodMonthly:
+	case domain.FinancialPeriodQuarterly:
+		periods = 4
+	default:
+		return nil, errors.New("granularity phasing harus monthly atau quarterly")
+	}
+
+	// Kelompokkan per metrik: setiap kelompok harus lengkap satu tahun dan berjumlah 100%
+	phasings := make([]domain.FinancialRKAPPhasingModel, 0, len(data.Weights))
+	seen := make(map[string]map[string]bool)
+	totals := make(map[string]float64)
+	for _, weight := range data.Weights {
+		span, err := parseFinancialPeriod(weight.Period)
+		if err != nil || span.Type != data.Granularity || span.Year != rkap.Year {
+			return nil, fmt.Errorf("period phasing %s tidak sesuai granularity %s tahun %s", weight.Period, data.Granularity, rkap.Year)
+		}
+		metric := ""
+		if weight.Metric != "" {
+			idx, ok := lookupFinancialField(weight.Metric)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", ErrUnknownFinancialMetric, weight.Metric)
+			}
+			field := financialImportFields[idx]
+			if financialFieldAggregation(field) != domain.FinancialAggregationSum {
+				return nil, fmt.Errorf("metrik %s adalah saldo/rasio dan tidak di-phasing", field.Key)
+			}
+			metric = field.Key
+		}
+		if weight.Weight < 0 {
+			return nil, fmt.Errorf("bobot phasing %s tidak boleh negatif", weight.Period)
+		}
+		if seen[metric] == nil {
+			seen[metric] = make(map[string]bool)
+		}
+		period := span.Year + strings.ToUpper(weight.Period[4:])
+		if seen[metric][period] {
+			return nil, fmt.Errorf("period phasing %s duplikat", period)
+		}
+		seen[metric][period] = true
+		totals[metric] += weight.Weight
+		phasings = append(phasings, domain.FinancialRKAPPhasingModel{
+			ID:     uuid.GenerateUUID(),
+			RKAPID: rkap.ID,
+			Period: period,
+			Metric: metric,
+			Weight: weight.Weight,
+		})
+	}
+	for metric, periodsSeen := range seen {
+		label := metric
+		if label == "" {
+			label = "default"
+		}
+		if len(periodsSeen) != periods {
+			return nil, fmt.Errorf("phasing %s harus berisi %d periode", label, periods)
+		}
+		if math.Abs(totals[metric]-100) > 0.01 {
+			return nil, fmt.Errorf("total bobot phasing %s harus 100%%, saat ini %.2f%%", label, totals[metric])
+		}
+	}
+
+	if err := uc.phasingRepo.ReplaceForRKAP(rkap.ID, phasings); err != nil {
+		return nil, fmt.Errorf("failed to save RKAP phasing: %w", err)
+	}
+
+	audit.LogAction(userID, username, audit.ActionUpdate, audit.ResourceFinancialReport, rkap.ID, ipAddress, userAgent, "success", map[string]interface{}{
+		"company_id":   rkap.CompanyID,
+		"year":         rkap.Year,
+		"rkap_version": rkap.RKAPVersion,
+		"phasing":      data.Granularity,
+		"weights":      len(phasings),
+	})
+
+	return rkapPhasingResponse(rkap, phasings), nil
+}
+
+func (uc *financialReportUseCase) GetPeriodComparison(companyID, period string, audited bool, rkapVersion *int) (*domain.FinancialPeriodComparisonResponse, error) {
+	span, err := parseFinancialPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	reports, err := uc.repo.GetRealisasiByCompanyIDAndYear(companyID, span.Year)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get realisasi: %w", err)
+	}
+	rkap, err := uc.getRKAP(companyID, span.Year, rkapVersion)
+	if err != nil {
+		return nil, err
+	}
+	var phasings []domain.FinancialRKAPPhasingModel
+	if rkap != nil {
+		if phasings, err = uc.phasingRepo.GetByRKAPID(rkap.ID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to get RKAP phasing: %w", err)
+		}
+	}
+
+	aggregate := aggregateRealisasi(reports, span, audited)
+	response := &domain.FinancialPeriodComparisonResponse{
+		CompanyID:     companyID,
+		Period:        period,
+		PeriodType:    span.Type,
+		Year:          span.Year,
+		StartMonth:    span.Start,
+		EndMonth:      span.End,
+		Audited:       audited,
+		RKAPPhasing:   rkapPhasingGranularity(phasings),
+		MonthsCovered: aggregate.covered,
+		Complete:      aggregate.covered == span.months(),
+		Sources:       make([]string, 0, len(aggregate.reports)),
+		Lines:         make([]domain.FinancialPeriodComparisonLine, 0, len(financialImportFields)),
+	}
+	if rkap != nil {
+		response.RKAPVersion = &rkap.RKAPVersion
+	}
+	for _, report := range aggregate.reports {
+		response.Sources = append(response.Sources, report.Period)
+	}
+	sort.Strings(response.Sources)
+
+	for _, field := range financialImportFields {
+		line := domain.FinancialPeriodComparisonLine{
+			Key:         field.Key,
+			Label:       field.Header,
+			Section:     field.Section,
+			Unit:        field.Unit(),
+			Aggregation: financialFieldAggregation(field),
+			Realisasi:   aggregate.value(field),
+		}
+		if rkap != nil {
+			annual := financialMetricValue(rkap, field)
+			phased := annual
+			if line.Aggregation == domain.FinancialAggregationSum {
+				phased = annual * rkapPhasingShare(phasings, field.Key, span.Start, span.End)
+			}
+			line.RKAPAnnual = &annual
+			line.RKAPPhased = roundedPtr(phased)
+		}
+		if line.Realisasi != nil && line.RKAPPhased != nil {
+			line.Variance = roundedPtr(*line.Realisasi - *line.RKAPPhased)
+			if *line.RKAPPhased != 0 {
+				line.Achievement = roundedPtr(*line.Realisasi / *line.RKAPPhased * 100)
+			}
+		}
+		response.Lines = append(response.Lines, line)
+	}
+	return response, nil
+}
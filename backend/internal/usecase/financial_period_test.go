@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFinancialPeriodComparison tests realisasi kuartal/tahunan audited dibandingkan dengan RKAP phasing bulanan
+func TestFinancialPeriodComparison(t *testing.T) {
+	db := helpers.SetupTestDB(t)
+	defer helpers.CleanupTestDB(t, db)
+
+	company := createTestCompanyForNotification(t, db, nil)
+	uc := NewFinancialReportUseCaseWithDB(db)
+	create := func(req domain.CreateFinancialReportRequest) (*domain.FinancialReportModel, error) {
+		req.CompanyID = company.ID
+		req.Year = req.Period[:4]
+		return uc.CreateFinancialReport(&req, "user-1", "tester", "", "")
+	}
+
+	rkap, err := create(domain.CreateFinancialReportRequest{Period: "2025", IsRKAP: true, Revenue: 1200, Equity: 5000})
+	require.NoError(t, err)
+	_, err = create(domain.CreateFinancialReportRequest{Period: "2025-Q1", PeriodType: domain.FinancialPeriodQuarterly, Revenue: 300, Equity: 4800})
+	require.NoError(t, err)
+	_, err = create(domain.CreateFinancialReportRequest{Period: "2025", PeriodType: domain.FinancialPeriodAnnual, IsAudited: true, Revenue: 1300})
+	require.NoError(t, err)
+
+	t.Run("Period validation", func(t *testing.T) {
+		_, err := create(domain.CreateFinancialReportRequest{Period: "2025-03", PeriodType: domain.FinancialPeriodQuarterly})
+		assert.Error(t, err)
+		_, err = create(domain.CreateFinancialReportRequest{Period: "2025-Q1", PeriodType: domain.FinancialPeriodQuarterly})
+		assert.Error(t, err) // Sudah ada
+		_, err = create(domain.CreateFinancialReportRequest{Period: "2025-13"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Phasing validation", func(t *testing.T) {
+		_, err := uc.SetRKAPPhasing(rkap.ID, &domain.SetRKAPPhasingRequest{
+			Granularity: domain.FinancialPeriodQuarterly,
+			Weights:     []domain.RKAPPhasingWeight{{Period: "2025-Q1", Weight: 50}, {Period: "2025-Q2", Weight: 50}},
+		}, "", "", "", "")
+		assert.Error(t, err) // Tidak lengkap 4 kuartal
+		_, err = uc.SetRKAPPhasing(rkap.ID, &domain.SetRKAPPhasingRequest{
+			Granularity: domain.FinancialPeriodQuarterly,
+			Weights: []domain.RKAPPhasingWeight{
+				{Period: "2025-Q1", Metric: "equity", Weight: 25}, {Period: "2025-Q2", Metric: "equity", Weight: 25},
+				{Period: "2025-Q3", Metric: "equity", Weight: 25}, {Period: "2025-Q4", Metric: "equity", Weight: 25},
+			},
+		}, "", "", "", "")
+		assert.Error(t, err) // Saldo tidak di-phasing
+	})
+
+	t.Run("Quarterly reporter against monthly phasing", func(t *testing.T) {
+		monthly := []float64{5, 5, 10, 10, 10, 10, 10, 10, 10, 10, 5, 5}
+		weights := make([]domain.RKAPPhasingWeight, 0, 12)
+		for i, weight := range monthly {
+			weights = append(weights, domain.RKAPPhasingWeight{Period: "2025-" + []string{"01", "02", "03", "04", "05", "06", "07", "08", "09", "10", "11", "12"}[i], Weight: weight})
+		}
+		phasing, err := uc.SetRKAPPhasing(rkap.ID, &domain.SetRKAPPhasingRequest{Granularity: domain.FinancialPeriodMonthly, Weights: weights}, "", "", "", "")
+		require.NoError(t, err)
+		assert.Equal(t, domain.FinancialPeriodMonthly, phasing.Granularity)
+
+		comparison, err := uc.GetPeriodComparison(company.ID, "2025-Q1", false, nil)
+		require.NoError(t, err)
+		assert.True(t, comparison.Complete)
+		assert.Equal(t, []string{"2025-Q1"}, comparison.Sources)
+		revenue := comparisonLine(t, comparison, "revenue")
+		assert.InDelta(t, 300, *revenue.Realisasi, 0.001)
+		assert.InDelta(t, 240, *revenue.RKAPPhased, 0.001) // 20% x 1200
+		assert.InDelta(t, 125, *revenue.Achievement, 0.001)
+		equity := comparisonLine(t, comparison, "equity")
+		assert.InDelta(t, 5000, *equity.RKAPPhased, 0.001) // Saldo: target tahunan
+
+		// Semester: Q2 belum ada, RKAP phased Jan-Jun = 50%
+		semester, err := uc.GetPeriodComparison(company.ID, "2025-S1", false, nil)
+		require.NoError(t, err)
+		assert.False(t, semester.Complete)
+		assert.Equal(t, 3, semester.MonthsCovered)
+		assert.InDelta(t, 600, *comparisonLine(t, semester, "revenue").RKAPPhased, 0.001)
+	})
+
+	t.Run("Audited annual figures are separate", func(t *testing.T) {
+		audited, err := uc.GetPeriodComparison(company.ID, "2025", true, nil)
+		require.NoError(t, err)
+		assert.True(t, audited.Complete)
+		assert.InDelta(t, 1300, *comparisonLine(t, audited, "revenue").Realisasi, 0.001)
+		assert.InDelta(t, 1200, *comparisonLine(t, audited, "revenue").RKAPPhased, 0.001)
+
+		unaudited, err := uc.GetPeriodComparison(company.ID, "2025", false, nil)
+		require.NoError(t, err)
+		assert.InDelta(t, 300, *comparisonLine(t, unaudited, "revenue").Realisasi, 0.001)
+	})
+
+	t.Run("Monthly data takes precedence over overlapping quarter", func(t *testing.T) {
+		_, err := create(domain.CreateFinancialReportRequest{Period: "2025-01", Revenue: 90})
+		require.NoError(t, err)
+		comparison, err := uc.GetPeriodComparison(company.ID, "2025-Q1", false, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2025-01"}, comparison.Sources)
+		assert.Equal(t, 1, comparison.MonthsCovered)
+		assert.InDelta(t, 90, *comparisonLine(t, comparison, "revenue").Realisasi, 0.001)
+
+		// Comparison YTD bulanan hanya memakai realisasi bulanan
+		ytd, err := uc.GetComparison(company.ID, "2025", "03", nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(90), ytd.RealisasiYTD.Revenue)
+	})
+}
+
+func comparisonLine(t *testing.T, response *domain.FinancialPeriodComparisonResponse, key string) domain.FinancialPeriodComparisonLine {
+	for _, line := range response.Lines {
+		if line.Key == key {
+			return line
+		}
+	}
+	t.Fatalf("line %s not found", key)
+	return domain.FinancialPeriodComparisonLine{}
+}
@@ -0,0 +1,522 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/xuri/excelize/v2"
+)
+
+// FinancialTaxonomyVersion adalah versi taksonomi konsep yang dipakai format JSON.
+// Naikkan versi jika ada konsep yang dihapus atau berubah arti (menambah konsep baru tidak perlu).
+const FinancialTaxonomyVersion = "pdv-fin-2025"
+
+// FinancialTaxonomy mengembalikan daftar konsep taksonomi sesuai urutan kolom template
+func FinancialTaxonomy() []domain.FinancialConcept {
+	concepts := make([]domain.FinancialConcept, 0, len(financialImportFields))
+	for _, field := range financialImportFields {
+		concepts = append(concepts, domain.FinancialConcept{
+			Concept: field.Concept,
+			Key:     field.Key,
+			Label:   field.Header,
+			Section: field.Section,
+			Unit:    field.Unit(),
+		})
+	}
+	return concepts
+}
+
+// lookupFinancialField mencari field berdasarkan kode konsep, key, atau header template (case-insensitive)
+func lookupFinancialField(name string) (int, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return 0, false
+	}
+	for i, field := range financialImportFields {
+		if name == strings.ToLower(field.Concept) || name == field.Key || name == strings.ToLower(field.Header) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// financialAccountMapping adalah mapping satu kode akun company ke field taksonomi
+type financialAccountMapping struct {
+	FieldIndex int
+	Multiplier float64
+}
+
+// financialAccountResolver mencari mapping kode akun milik company (return false jika belum dipetakan)
+type financialAccountResolver func(companyCode, accountCode string) (financialAccountMapping, bool)
+
+// FinancialReportFormat adalah format file import/export financial report (xlsx, csv, json)
+type FinancialReportFormat interface {
+	Name() string
+	ContentType() string
+	Extension() string
+	// Export menulis laporan realisasi (Company harus sudah di-preload)
+	Export(reports []domain.FinancialReportModel) ([]byte, error)
+	// decode mengubah isi file menjadi baris data dalam urutan kolom template
+	decode(data []byte, accounts financialAccountResolver) ([]financialSourceRow, error)
+}
+
+var financialReportFormats = map[string]FinancialReportFormat{
+	domain.FinancialFormatXLSX: xlsxFinancialFormat{},
+	domain.FinancialFormatCSV:  csvFinancialFormat{},
+	domain.FinancialFormatJSON: jsonFinancialFormat{},
+}
+
+// GetFinancialReportFormat mengembalikan format berdasarkan nama (xlsx, csv, json)
+func GetFinancialReportFormat(name string) (FinancialReportFormat, bool) {
+	format, ok := financialReportFormats[strings.ToLower(strings.TrimSpace(name))]
+	return format, ok
+}
+
+// DetectFinancialReportFormat menentukan format dari ekstensi file (.xlsx, .csv, .json)
+func DetectFinancialReportFormat(fileName string) (FinancialReportFormat, bool) {
+	return GetFinancialReportFormat(strings.TrimPrefix(filepath.Ext(fileName), "."))
+}
+
+// financialRowBuilder menyusun financialSourceRow dari format berbasis kolom bebas (CSV) atau fakta (JSON).
+// Nilai akun yang dipetakan ke konsep yang sama dijumlahkan setelah dikalikan multiplier.
+type financialRowBuilder struct {
+	row      financialSourceRow
+	accounts financialAccountResolver
+	direct   map[int]bool
+	sums     map[int]float64
+}
+
+func newFinancialRowBuilder(rowNumber int, accounts financialAccountResolver) *financialRowBuilder {
+	return &financialRowBuilder{
+		row:      financialSourceRow{RowNumber: rowNumber, Cells: make([]string, len(FinancialImportHeaders()))},
+		accounts: accounts,
+		direct:   make(map[int]bool),
+		sums:     make(map[int]float64),
+	}
+}
+
+func (b *financialRowBuilder) addProblem(column, message string) {
+	b.row.Problems = append(b.row.Problems, domain.FinancialImportRowError{Row: b.row.RowNumber, Column: column, Message: message})
+}
+
+func (b *financialRowBuilder) setCell(col int, value string) {
+	b.row.Cells[col] = strings.TrimSpace(value)
+}
+
+// setPeriod mengisi kolom Tahun dan Bulan dari periode YYYY-MM
+func (b *financialRowBuilder) setPeriod(period string) {
+	year, month, _ := strings.Cut(strings.TrimSpace(period), "-")
+	b.setCell(financialImportColYear, year)
+	b.setCell(financialImportColMonth, month)
+}
+
+// setField mengisi nilai konsep secara langsung (kolom key/konsep di CSV, fakta concept di JSON)
+func (b *financialRowBuilder) setField(index int, value string) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+	b.direct[index] = true
+	b.setCell(financialImportColFirstValue+index, value)
+}
+
+// addAccount menambahkan nilai kode akun company ke konsep yang dipetakan
+func (b *financialRowBuilder) addAccount(accountCode, value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	companyCode := b.row.Cells[financialImportColCompanyCode]
+	mapping, ok := b.accounts(companyCode, accountCode)
+	if !ok {
+		b.addProblem(accountCode, fmt.Sprintf("Kode akun '%s' belum dipetakan ke konsep taksonomi untuk company '%s'", accountCode, companyCode))
+		return
+	}
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		b.addProblem(accountCode, fmt.Sprintf("Nilai akun '%s' harus berupa angka", accountCode))
+		return
+	}
+	b.sums[mapping.FieldIndex] += amount * mapping.Multiplier
+}
+
+func (b *financialRowBuilder) build() financialSourceRow {
+	for index, sum := range b.sums {
+		field := financialImportFields[index]
+		if b.direct[index] {
+			b.addProblem(field.Header, fmt.Sprintf("%s diisi langsung dan juga lewat kode akun", field.Header))
+			continue
+		}
+		b.row.Cells[financialImportColFirstValue+index] = strconv.FormatFloat(sum, 'f', -1, 64)
+	}
+	return b.row
+}
+
+// financialReportValue mengembalikan nilai field taksonomi dari report (int64 atau float64)
+func financialReportValue(report *domain.FinancialReportModel, field financialImportField) interface{} {
+	return reflect.ValueOf(report).Elem().FieldByName(field.Field).Interface()
+}
+
+func financialReportCompany(report *domain.FinancialReportModel) (code, name string) {
+	if report.Company != nil {
+		return report.Company.Code, report.Company.Name
+	}
+	return "", ""
+}
+
+// ---------------------------------------------------------------------------
+// XLSX: layout template bulk upload
+// ---------------------------------------------------------------------------
+
+type xlsxFinancialFormat struct{}
+
+func (xlsxFinancialFormat) Name() string      { return domain.FinancialFormatXLSX }
+func (xlsxFinancialFormat) Extension() string { return ".xlsx" }
+func (xlsxFinancialFormat) ContentType() string {
+	return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+}
+
+func (xlsxFinancialFormat) decode(data []byte, _ financialAccountResolver) ([]financialSourceRow, error) {
+	return decodeXLSXRows(data)
+}
+
+func (xlsxFinancialFormat) Export(reports []domain.FinancialReportModel) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheet := "Financial Reports"
+	if err := f.SetSheetName("Sheet1", sheet); err != nil {
+		return nil, err
+	}
+
+	headers := FinancialImportHeaders()
+	headerRow := make([]interface{}, len(headers))
+	for i, header := range headers {
+		headerRow[i] = header
+	}
+	if err := f.SetSheetRow(sheet, "A1", &headerRow); err != nil {
+		return nil, err
+	}
+
+	for i := range reports {
+		report := &reports[i]
+		code, name := financialReportCompany(report)
+		year, month, _ := strings.Cut(report.Period, "-")
+		values := []interface{}{code, name, year, month}
+		for _, field := range financialImportFields {
+			values = append(values, financialReportValue(report, field))
+		}
+		remark := ""
+		if report.Remark != nil {
+			remark = *report.Remark
+		}
+		values = append(values, remark)
+
+		cell, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return nil, err
+		}
+		if err := f.SetSheetRow(sheet, cell, &values); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ---------------------------------------------------------------------------
+// CSV
+//
+// Mapping kolom (header case-insensitive, urutan bebas, delimiter ',' atau ';'):
+//   company_code / Kode Perusahaan  wajib
+//   company_name / Nama Perusahaan  diabaikan (referensi)
+//   period (YYYY-MM)                atau year/Tahun + month/Bulan
+//   remark / Keterangan             opsional
+//   <key> / <konsep> / <header>     nilai field, contoh: revenue, pdv:Revenue, Pendapatan
+//   kolom lain                      dianggap kode akun company dan dipetakan lewat financial account mapping
+// ---------------------------------------------------------------------------
+
+type csvFinancialFormat struct{}
+
+func (csvFinancialFormat) Name() string        { return domain.FinancialFormatCSV }
+func (csvFinancialFormat) Extension() string   { return ".csv" }
+func (csvFinancialFormat) ContentType() string { return "text/csv" }
+
+// csvIdentityColumns memetakan header identitas ke kolom template (-1 = period, diproses terpisah)
+var csvIdentityColumns = map[string]int{
+	"company_code":    financialImportColCompanyCode,
+	"kode perusahaan": financialImportColCompanyCode,
+	"company_name":    financialImportColCompanyName,
+	"nama perusahaan": financialImportColCompanyName,
+	"year":            financialImportColYear,
+	"tahun":           financialImportColYear,
+	"month":           financialImportColMonth,
+	"bulan":           financialImportColMonth,
+	"period":          -1,
+	"periode":         -1,
+	"remark":          financialImportColFirstValue + len(financialImportFields),
+	"keterangan":      financialImportColFirstValue + len(financialImportFields),
+}
+
+func (csvFinancialFormat) decode(data []byte, accounts financialAccountResolver) ([]financialSourceRow, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // BOM dari Excel
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, &FinancialWorkbookError{Message: "File CSV harus memiliki minimal header dan 1 baris data"}
+	}
+	if err != nil {
+		return nil, &FinancialWorkbookError{Message: "File CSV tidak valid: " + err.Error()}
+	}
+
+	type csvColumn struct {
+		identity int // kolom template untuk identitas, -1 = period
+		field    int // index financialImportFields, -1 jika bukan field
+		account  string
+	}
+	columns := make([]csvColumn, len(header))
+	hasCode, hasPeriod, hasYear, hasMonth := false, false, false, false
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		lower := strings.ToLower(name)
+		if col, ok := csvIdentityColumns[lower]; ok {
+			columns[i] = csvColumn{identity: col, field: -1}
+			switch col {
+			case financialImportColCompanyCode:
+				hasCode = true
+			case financialImportColYear:
+				hasYear = true
+			case financialImportColMonth:
+				hasMonth = true
+			case -1:
+				hasPeriod = true
+			}
+			continue
+		}
+		if index, ok := lookupFinancialField(name); ok {
+			columns[i] = csvColumn{identity: -2, field: index}
+			continue
+		}
+		columns[i] = csvColumn{identity: -2, field: -1, account: name}
+	}
+	if !hasCode || !(hasPeriod || (hasYear && hasMonth)) {
+		return nil, &FinancialWorkbookError{Message: "Header CSV wajib memuat company_code dan period (atau year dan month)"}
+	}
+
+	var rows []financialSourceRow
+	lineNumber := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		lineNumber++
+		if err != nil {
+			return nil, &FinancialWorkbookError{Message: fmt.Sprintf("File CSV tidak valid pada baris %d: %v", lineNumber, err)}
+		}
+		if isEmptyFinancialRow(record) {
+			continue
+		}
+
+		builder := newFinancialRowBuilder(lineNumber, accounts)
+		// Identitas diisi lebih dulu agar kode company tersedia saat resolve kode akun
+		for i, value := range record {
+			if i >= len(columns) {
+				break
+			}
+			switch column := columns[i]; {
+			case column.identity == -1:
+				builder.setPeriod(value)
+			case column.identity >= 0:
+				builder.setCell(column.identity, value)
+			}
+		}
+		for i, value := range record {
+			if i >= len(columns) {
+				break
+			}
+			switch column := columns[i]; {
+			case column.field >= 0:
+				builder.setField(column.field, value)
+			case column.account != "":
+				builder.addAccount(column.account, value)
+			}
+		}
+		rows = append(rows, builder.build())
+	}
+
+	if len(rows) == 0 {
+		return nil, &FinancialWorkbookError{Message: "File CSV harus memiliki minimal header dan 1 baris data"}
+	}
+	return rows, nil
+}
+
+func (csvFinancialFormat) Export(reports []domain.FinancialReportModel) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	header := []string{"company_code", "company_name", "period"}
+	for _, field := range financialImportFields {
+		header = append(header, field.Key)
+	}
+	header = append(header, "remark")
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	for i := range reports {
+		report := &reports[i]
+		code, name := financialReportCompany(report)
+		record := []string{code, name, report.Period}
+		for _, field := range financialImportFields {
+			record = append(record, fmt.Sprint(financialReportValue(report, field)))
+		}
+		remark := ""
+		if report.Remark != nil {
+			remark = *report.Remark
+		}
+		record = append(record, remark)
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+// ---------------------------------------------------------------------------
+// JSON: dokumen fakta bergaya XBRL dengan kode konsep taksonomi
+//
+//	{
+//	  "taxonomy": "pdv-fin-2025",
+//	  "reports": [{
+//	    "entity": "PDV01", "period": "2025-01", "remark": "...",
+//	    "facts": [{"concept": "pdv:Revenue", "value": 1000}, {"account": "4100", "value": 250}]
+//	  }]
+//	}
+//
+// Fakta boleh memakai "concept" (kode taksonomi) atau "account" (kode akun company yang dipetakan).
+// ---------------------------------------------------------------------------
+
+type jsonFinancialFormat struct{}
+
+func (jsonFinancialFormat) Name() string        { return domain.FinancialFormatJSON }
+func (jsonFinancialFormat) Extension() string   { return ".json" }
+func (jsonFinancialFormat) ContentType() string { return "application/json" }
+
+// FinancialFactDocument adalah dokumen import/export format JSON
+type FinancialFactDocument struct {
+	Taxonomy string                `json:"taxonomy"`
+	Reports  []FinancialFactReport `json:"reports"`
+}
+
+// FinancialFactReport adalah kumpulan fakta satu company untuk satu periode
+type FinancialFactReport struct {
+	Entity     string          `json:"entity"` // Kode company
+	EntityName string          `json:"entity_name,omitempty"`
+	Period     string          `json:"period"` // YYYY-MM
+	Remark     string          `json:"remark,omitempty"`
+	Facts      []FinancialFact `json:"facts"`
+}
+
+// FinancialFact adalah satu nilai laporan keuangan
+type FinancialFact struct {
+	Concept string      `json:"concept,omitempty"`
+	Account string      `json:"account,omitempty"`
+	Unit    string      `json:"unit,omitempty"` // Informasi saja, diabaikan saat import
+	Value   json.Number `json:"value"`
+}
+
+func (jsonFinancialFormat) decode(data []byte, accounts financialAccountResolver) ([]financialSourceRow, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document FinancialFactDocument
+	if err := decoder.Decode(&document); err != nil {
+		return nil, &FinancialWorkbookError{Message: "File JSON tidak valid: " + err.Error()}
+	}
+	if document.Taxonomy != "" && document.Taxonomy != FinancialTaxonomyVersion {
+		return nil, &FinancialWorkbookError{
+			Message: fmt.Sprintf("Taksonomi '%s' tidak didukung, gunakan '%s'", document.Taxonomy, FinancialTaxonomyVersion),
+		}
+	}
+	if len(document.Reports) == 0 {
+		return nil, &FinancialWorkbookError{Message: "File JSON tidak memiliki data reports"}
+	}
+
+	rows := make([]financialSourceRow, 0, len(document.Reports))
+	for i, report := range document.Reports {
+		builder := newFinancialRowBuilder(i+1, accounts)
+		builder.setCell(financialImportColCompanyCode, report.Entity)
+		builder.setCell(financialImportColCompanyName, report.EntityName)
+		builder.setPeriod(report.Period)
+		builder.setCell(financialImportColFirstValue+len(financialImportFields), report.Remark)
+
+		for _, fact := range report.Facts {
+			switch {
+			case fact.Concept != "" && fact.Account != "":
+				builder.addProblem(fact.Concept, "Fakta hanya boleh memiliki concept atau account, tidak keduanya")
+			case fact.Concept != "":
+				index, ok := lookupFinancialField(fact.Concept)
+				if !ok {
+					builder.addProblem(fact.Concept, fmt.Sprintf("Konsep '%s' tidak dikenal", fact.Concept))
+					continue
+				}
+				builder.setField(index, fact.Value.String())
+			case fact.Account != "":
+				builder.addAccount(fact.Account, fact.Value.String())
+			default:
+				builder.addProblem("facts", "Fakta wajib memiliki concept atau account")
+			}
+		}
+		rows = append(rows, builder.build())
+	}
+	return rows, nil
+}
+
+func (jsonFinancialFormat) Export(reports []domain.FinancialReportModel) ([]byte, error) {
+	document := FinancialFactDocument{
+		Taxonomy: FinancialTaxonomyVersion,
+		Reports:  make([]FinancialFactReport, 0, len(reports)),
+	}
+	for i := range reports {
+		report := &reports[i]
+		code, name := financialReportCompany(report)
+		entry := FinancialFactReport{
+			Entity:     code,
+			EntityName: name,
+			Period:     report.Period,
+			Facts:      make([]FinancialFact, 0, len(financialImportFields)),
+		}
+		if report.Remark != nil {
+			entry.Remark = *report.Remark
+		}
+		for _, field := range financialImportFields {
+			entry.Facts = append(entry.Facts, FinancialFact{
+				Concept: field.Concept,
+				Unit:    field.Unit(),
+				Value:   json.Number(fmt.Sprint(financialReportValue(report, field))),
+			})
+		}
+		document.Reports = append(document.Reports, entry)
+	}
+	return json.MarshalIndent(document, "", "  ")
+}
@@ -29,20 +29,43 @@ type FinancialReportUseCase interface {
 	GetFinancialReportsByCompanyID(companyID string) ([]domain.FinancialReportModel, error)
 	GetRKAPByCompanyIDAndYear(companyID, year string) (*domain.FinancialReportModel, error)
 	GetRealisasiByCompanyIDAndPeriod(companyID, period string) (*domain.FinancialReportModel, error)
-	GetComparison(companyID, year, month string) (*domain.FinancialReportComparisonResponse, error)
+	// GetComparison membandingkan RKAP dengan realisasi YTD. rkapVersion nil = versi RKAP approved terakhir
+	GetComparison(companyID, year, month string, rkapVersion *int) (*domain.FinancialReportComparisonResponse, error)
 	GetRKAPYearsByCompanyID(companyID string) ([]string, error)
 	DeleteFinancialReport(id string, userID, username, ipAddress, userAgent string) error
-	ExportPerformanceExcel(companyID, startPeriod, endPeriod string) ([]byte, error)
+	ExportPerformanceExcel(companyID, startPeriod, endPeriod string, rkapVersion *int) ([]byte, error)
+	// ExportPerformancePDF menghasilkan PDF RKAP vs Realisasi (beserta variance) dengan definisi section yang sama seperti Excel
+	ExportPerformancePDF(companyID, startPeriod, endPeriod string, rkapVersion *int) ([]byte, error)
+	// ExportFinancialReports menulis realisasi bulanan company dalam format xlsx, csv, atau json (periode kosong = semua)
+	ExportFinancialReports(companyID, format, startPeriod, endPeriod string) ([]byte, FinancialReportFormat, error)
+
+	// RKAP Perubahan: versi RKAP awal + revisi dengan tanggal berlaku dan status approval
+	GetRKAPVersions(companyID, year string) ([]domain.FinancialReportModel, error)
+	CreateRKAPRevision(companyID, year string, data *domain.CreateRKAPRevisionRequest, userID, username, ipAddress, userAgent string) (*domain.FinancialReportModel, error)
+	ApproveRKAPRevision(id string, userID, username, ipAddress, userAgent string) (*domain.FinancialReportModel, error)
+	RejectRKAPRevision(id string, userID, username, ipAddress, userAgent string) (*domain.FinancialReportModel, error)
+	// DiffRKAPVersions membandingkan dua versi RKAP baris per baris. nil = versi terakhir (to) dan versi sebelumnya (from)
+	DiffRKAPVersions(companyID, year string, fromVersion, toVersion *int) (*domain.RKAPRevisionDiffResponse, error)
+
+	// Granularitas periode (bulanan, kuartal, semester, tahunan audited) dan phasing RKAP
+	GetRKAPPhasing(rkapID string) (*domain.RKAPPhasingResponse, error)
+	SetRKAPPhasing(rkapID string, data *domain.SetRKAPPhasingRequest, userID, username, ipAddress, userAgent string) (*domain.RKAPPhasingResponse, error)
+	// GetPeriodComparison membandingkan realisasi satu periode (2024-03, 2024-Q1, 2024-S1, 2024) dengan RKAP phased
+	GetPeriodComparison(companyID, period string, audited bool, rkapVersion *int) (*domain.FinancialPeriodComparisonResponse, error)
 }
 
 type financialReportUseCase struct {
-	repo repository.FinancialReportRepository
+	repo        repository.FinancialReportRepository
+	companyRepo repository.CompanyRepository
+	phasingRepo repository.FinancialRKAPPhasingRepository
 }
 
 // NewFinancialReportUseCaseWithDB creates a new financial report use case with injected DB
 func NewFinancialReportUseCaseWithDB(db *gorm.DB) FinancialReportUseCase {
 	return &financialReportUseCase{
-		repo: repository.NewFinancialReportRepositoryWithDB(db),
+		repo:        repository.NewFinancialReportRepositoryWithDB(db),
+		companyRepo: repository.NewCompanyRepositoryWithDB(db),
+		phasingRepo: repository.NewFinancialRKAPPhasingRepositoryWithDB(db),
 	}
 }
 
@@ -60,6 +83,11 @@ func (uc *financialReportUseCase) CreateFinancialReport(data *domain.CreateFinan
 		return nil, errors.New("nilai rasio keuangan tidak boleh melebihi 100%")
 	}
 
+	// Laporan company yang sudah dibubarkan hanya bisa dibaca
+	if err := ensureCompanyIDNotDissolved(uc.companyRepo, data.CompanyID); err != nil {
+		return nil, err
+	}
+
 	// Validasi: RKAP hanya boleh 1x per tahun per perusahaan
 	if data.IsRKAP {
 		count, err := uc.repo.CountRKAPByCompanyIDAndYear(data.CompanyID, data.Year)
@@ -67,17 +95,22 @@ func (uc *financialReportUseCase) CreateFinancialReport(data *domain.CreateFinan
 			return nil, fmt.Errorf("failed to check existing RKAP: %w", err)
 		}
 		if count > 0 {
-			return nil, errors.New("RKAP untuk tahun ini sudah ada. Hanya boleh ada satu RKAP per tahun per perusahaan")
+			return nil, errors.New("RKAP untuk tahun ini sudah ada. Hanya boleh ada satu RKAP per tahun per perusahaan, gunakan revisi RKAP (RKAP Perubahan) untuk mengubahnya")
 		}
 
 		// Pastikan period untuk RKAP adalah tahun saja (format: "2024")
 		if data.Period != data.Year {
 			data.Period = data.Year
 		}
+		data.PeriodType = domain.FinancialPeriodAnnual
+		data.IsAudited = false
 	} else {
-		// Validasi: Realisasi harus format YYYY-MM
-		if len(data.Period) != 7 || data.Period[4] != '-' {
-			return nil, errors.New("period untuk realisasi harus format YYYY-MM (contoh: 2024-01)")
+		// Validasi: format period mengikuti period_type (default bulanan YYYY-MM)
+		if data.PeriodType == "" {
+			data.PeriodType = domain.FinancialPeriodMonthly
+		}
+		if err := validateFinancialPeriod(data.PeriodType, data.Period); err != nil {
+			return nil, err
 		}
 
 		// Pastikan year sesuai dengan period
@@ -85,8 +118,8 @@ func (uc *financialReportUseCase) CreateFinancialReport(data *domain.CreateFinan
 			return nil, errors.New("year harus sesuai dengan period (4 digit pertama dari period)")
 		}
 
-		// Cek apakah realisasi untuk period ini sudah ada
-		existing, _ := uc.repo.GetRealisasiByCompanyIDAndPeriod(data.CompanyID, data.Period)
+		// Cek apakah realisasi untuk period ini sudah ada (audited dan unaudited disimpan terpisah)
+		existing, _ := uc.repo.GetRealisasiByCompanyIDPeriodAndAudit(data.CompanyID, data.Period, data.IsAudited)
 		if existing != nil {
 			return nil, fmt.Errorf("realisasi untuk periode %s sudah ada", data.Period)
 		}
@@ -98,6 +131,8 @@ func (uc *financialReportUseCase) CreateFinancialReport(data *domain.CreateFinan
 		Year:                  data.Year,
 		Period:                data.Period,
 		IsRKAP:                data.IsRKAP,
+		PeriodType:            data.PeriodType,
+		IsAudited:             data.IsAudited,
 		InputterID:            nil,
 		CurrentAssets:         data.CurrentAssets,
 		NonCurrentAssets:      data.NonCurrentAssets,
@@ -110,6 +145,7 @@ func (uc *financialReportUseCase) CreateFinancialReport(data *domain.CreateFinan
 		OtherIncome:           data.OtherIncome,
 		Tax:                   data.Tax,
 		NetProfit:             data.NetProfit,
+		Dividend:              data.Dividend,
 		OperatingCashflow:     data.OperatingCashflow,
 		InvestingCashflow:     data.InvestingCashflow,
 		FinancingCashflow:     data.FinancingCashflow,
@@ -129,6 +165,9 @@ func (uc *financialReportUseCase) CreateFinancialReport(data *domain.CreateFinan
 	if userID != "" {
 		report.InputterID = &userID
 	}
+	if data.IsRKAP {
+		report.RKAPStatus = domain.RKAPStatusApproved
+	}
 
 	if err := uc.repo.Create(report); err != nil {
 		zapLog.Error("Failed to create financial report", zap.Error(err))
@@ -154,6 +193,7 @@ func (uc *financialReportUseCase) CreateFinancialReport(data *domain.CreateFinan
 	changes["other_income"] = map[string]interface{}{"new": report.OtherIncome}
 	changes["tax"] = map[string]interface{}{"new": report.Tax}
 	changes["net_profit"] = map[string]interface{}{"new": report.NetProfit}
+	changes["dividend"] = map[string]interface{}{"new": report.Dividend}
 	changes["operating_cashflow"] = map[string]interface{}{"new": report.OperatingCashflow}
 	changes["investing_cashflow"] = map[string]interface{}{"new": report.InvestingCashflow}
 	changes["financing_cashflow"] = map[string]interface{}{"new": report.FinancingCashflow}
@@ -189,54 +229,28 @@ func (uc *financialReportUseCase) UpdateFinancialReport(id string, data *domain.
 	if err != nil {
 		return nil, fmt.Errorf("financial report not found: %w", err)
 	}
-
-	// Simpan data lama untuk audit trail - simpan SEMUA field
-	oldData := map[string]interface{}{
-		"year":    report.Year,
-		"period":  report.Period,
-		"is_rkap": report.IsRKAP,
-		// Neraca
-		"current_assets":         report.CurrentAssets,
-		"non_current_assets":     report.NonCurrentAssets,
-		"short_term_liabilities": report.ShortTermLiabilities,
-		"long_term_liabilities":  report.LongTermLiabilities,
-		"equity":                 report.Equity,
-		// Laba Rugi
-		"revenue":            report.Revenue,
-		"operating_expenses": report.OperatingExpenses,
-		"operating_profit":   report.OperatingProfit,
-		"other_income":       report.OtherIncome,
-		"tax":                report.Tax,
-		"net_profit":         report.NetProfit,
-		// Cashflow
-		"operating_cashflow": report.OperatingCashflow,
-		"investing_cashflow": report.InvestingCashflow,
-		"financing_cashflow": report.FinancingCashflow,
-		"ending_balance":     report.EndingBalance,
-		// Rasio
-		"roe":                     report.ROE,
-		"roi":                     report.ROI,
-		"current_ratio":           report.CurrentRatio,
-		"cash_ratio":              report.CashRatio,
-		"ebitda":                  report.EBITDA,
-		"ebitda_margin":           report.EBITDAMargin,
-		"net_profit_margin":       report.NetProfitMargin,
-		"operating_profit_margin": report.OperatingProfitMargin,
-		"debt_to_equity":          report.DebtToEquity,
-		"remark":                  report.Remark,
+	if err := ensureCompanyIDNotDissolved(uc.companyRepo, report.CompanyID); err != nil {
+		return nil, err
 	}
 
+	// Simpan salinan data lama untuk audit trail (diff per field dihitung setelah update)
+	before := *report
+
 	// Validasi: Ratio fields tidak boleh melebihi 100 (untuk persentase)
-	if (data.ROE != nil && *data.ROE > 100) ||
-		(data.ROI != nil && *data.ROI > 100) ||
-		(data.CurrentRatio != nil && *data.CurrentRatio > 100) ||
-		(data.CashRatio != nil && *data.CashRatio > 100) ||
-		(data.EBITDAMargin != nil && *data.EBITDAMargin > 100) ||
-		(data.NetProfitMargin != nil && *data.NetProfitMargin > 100) ||
-		(data.OperatingProfitMargin != nil && *data.OperatingProfitMargin > 100) {
+	if financialRatioUpdateExceedsLimit(data) {
 		return nil, errors.New("nilai rasio keuangan tidak boleh melebihi 100%")
 	}
 
+	// Revisi RKAP hanya bisa diubah selama masih draft, dan tidak bisa dipindah tahun/tipe
+	if report.RKAPVersion > 0 {
+		if report.RKAPStatus != domain.RKAPStatusDraft {
+			return nil, ErrRKAPRevisionNotDraft
+		}
+		if data.Year != nil || data.Period != nil || data.IsRKAP != nil {
+			return nil, errors.New("year, period, dan is_rkap revisi RKAP tidak dapat diubah")
+		}
+	}
+
 	// Update fields
 	if data.Year != nil {
 		report.Year = *data.Year
@@ -247,6 +261,14 @@ func (uc *financialReportUseCase) UpdateFinancialReport(id string, data *domain.
 	if data.IsRKAP != nil {
 		report.IsRKAP = *data.IsRKAP
 	}
+	if data.IsAudited != nil && !report.IsRKAP {
+		report.IsAudited = *data.IsAudited
+	}
+	if data.Period != nil && !report.IsRKAP {
+		if err := validateFinancialPeriod(financialReportPeriodType(report), report.Period); err != nil {
+			return nil, err
+		}
+	}
 
 	// Validasi: Jika update menjadi RKAP, cek apakah sudah ada RKAP untuk tahun tersebut
 	if data.IsRKAP != nil && *data.IsRKAP {
@@ -255,7 +277,7 @@ func (uc *financialReportUseCase) UpdateFinancialReport(id string, data *domain.
 			year = *data.Year
 		}
 		// Jika sudah ada RKAP lain (bukan yang sedang di-update), tolak
-		existingRKAP, err := uc.repo.GetRKAPByCompanyIDAndYear(report.CompanyID, year)
+		existingRKAP, err := uc.repo.GetRKAPVersion(report.CompanyID, year, 0)
 		if err != nil && err != gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("failed to check existing RKAP: %w", err)
 		}
@@ -264,6 +286,43 @@ func (uc *financialReportUseCase) UpdateFinancialReport(id string, data *domain.
 		}
 	}
 
+	applyFinancialReportUpdate(report, data)
+
+	if err := uc.repo.Update(report); err != nil {
+		zapLog.Error("Failed to update financial report", zap.Error(err))
+		return nil, fmt.Errorf("failed to update financial report: %w", err)
+	}
+
+	// Audit trail dengan perubahan
+	reportType := "RKAP"
+	if !report.IsRKAP {
+		reportType = "Realisasi"
+	}
+	changes := audit.DiffModels(&before, report)
+
+	audit.LogChanges(userID, username, audit.ActionUpdate, audit.ResourceFinancialReport, report.ID, ipAddress, userAgent, changes, map[string]interface{}{
+		"company_id": report.CompanyID,
+		"year":       report.Year,
+		"period":     report.Period,
+		"type":       reportType,
+	})
+
+	return report, nil
+}
+
+// financialRatioUpdateExceedsLimit cek rasio persentase pada request update (maksimal 100)
+func financialRatioUpdateExceedsLimit(data *domain.UpdateFinancialReportRequest) bool {
+	return (data.ROE != nil && *data.ROE > 100) ||
+		(data.ROI != nil && *data.ROI > 100) ||
+		(data.CurrentRatio != nil && *data.CurrentRatio > 100) ||
+		(data.CashRatio != nil && *data.CashRatio > 100) ||
+		(data.EBITDAMargin != nil && *data.EBITDAMargin > 100) ||
+		(data.NetProfitMargin != nil && *data.NetProfitMargin > 100) ||
+		(data.OperatingProfitMargin != nil && *data.OperatingProfitMargin > 100)
+}
+
+// applyFinancialReportUpdate menyalin nilai keuangan yang diisi pada request ke report
+func applyFinancialReportUpdate(report *domain.FinancialReportModel, data *domain.UpdateFinancialReportRequest) {
 	// Update Neraca
 	if data.CurrentAssets != nil {
 		report.CurrentAssets = *data.CurrentAssets
@@ -300,6 +359,9 @@ func (uc *financialReportUseCase) UpdateFinancialReport(id string, data *domain.
 	if data.NetProfit != nil {
 		report.NetProfit = *data.NetProfit
 	}
+	if data.Dividend != nil {
+		report.Dividend = *data.Dividend
+	}
 
 	// Update Cashflow
 	if data.OperatingCashflow != nil {
@@ -347,36 +409,6 @@ func (uc *financialReportUseCase) UpdateFinancialReport(id string, data *domain.
 	if data.Remark != nil {
 		report.Remark = data.Remark
 	}
-
-	if err := uc.repo.Update(report); err != nil {
-		zapLog.Error("Failed to update financial report", zap.Error(err))
-		return nil, fmt.Errorf("failed to update financial report: %w", err)
-	}
-
-	// Audit trail dengan perubahan
-	reportType := "RKAP"
-	if !report.IsRKAP {
-		reportType = "Realisasi"
-	}
-	changes := make(map[string]interface{})
-	for key, oldValue := range oldData {
-		if newValue := getFieldValue(report, key); newValue != oldValue {
-			changes[key] = map[string]interface{}{
-				"old": oldValue,
-				"new": newValue,
-			}
-		}
-	}
-
-	audit.LogAction(userID, username, audit.ActionUpdate, audit.ResourceFinancialReport, report.ID, ipAddress, userAgent, "success", map[string]interface{}{
-		"company_id": report.CompanyID,
-		"year":       report.Year,
-		"period":     report.Period,
-		"type":       reportType,
-		"changes":    changes,
-	})
-
-	return report, nil
 }
 
 func (uc *financialReportUseCase) GetFinancialReportByID(id string) (*domain.FinancialReportModel, error) {
@@ -399,11 +431,11 @@ func (uc *financialReportUseCase) GetRKAPYearsByCompanyID(companyID string) ([]s
 	return uc.repo.GetRKAPYearsByCompanyID(companyID)
 }
 
-func (uc *financialReportUseCase) GetComparison(companyID, year, month string) (*domain.FinancialReportComparisonResponse, error) {
-	// Ambil RKAP untuk tahun tersebut
-	rkap, err := uc.repo.GetRKAPByCompanyIDAndYear(companyID, year)
-	if err != nil && err != gorm.ErrRecordNotFound {
-		return nil, fmt.Errorf("failed to get RKAP: %w", err)
+func (uc *financialReportUseCase) GetComparison(companyID, year, month string, rkapVersion *int) (*domain.FinancialReportComparisonResponse, error) {
+	// Ambil RKAP untuk tahun tersebut (versi yang dipilih atau approved terakhir)
+	rkap, err := uc.getRKAP(companyID, year, rkapVersion)
+	if err != nil {
+		return nil, err
 	}
 
 	// Ambil Realisasi YTD sampai bulan yang dipilih
@@ -437,6 +469,7 @@ func (uc *financialReportUseCase) GetComparison(companyID, year, month string) (
 		response.Comparison["other_income"] = createComparisonItem(rkap.OtherIncome, realisasiYTD.OtherIncome)
 		response.Comparison["tax"] = createComparisonItem(rkap.Tax, realisasiYTD.Tax)
 		response.Comparison["net_profit"] = createComparisonItem(rkap.NetProfit, realisasiYTD.NetProfit)
+		response.Comparison["dividend"] = createComparisonItem(rkap.Dividend, realisasiYTD.Dividend)
 
 		// Cashflow
 		response.Comparison["operating_cashflow"] = createComparisonItem(rkap.OperatingCashflow, realisasiYTD.OperatingCashflow)
@@ -466,11 +499,35 @@ func (uc *financialReportUseCase) DeleteFinancialReport(id string, userID, usern
 	if err != nil {
 		return fmt.Errorf("financial report not found: %w", err)
 	}
+	if err := ensureCompanyIDNotDissolved(uc.companyRepo, report.CompanyID); err != nil {
+		return err
+	}
+
+	// Riwayat RKAP Perubahan dijaga: revisi approved tidak bisa dihapus, RKAP awal hanya bisa dihapus jika belum ada revisi
+	if report.IsRKAP {
+		if report.RKAPVersion > 0 && report.RKAPStatus == domain.RKAPStatusApproved {
+			return errors.New("revisi RKAP yang sudah approved tidak dapat dihapus")
+		}
+		if report.RKAPVersion == 0 {
+			versions, err := uc.repo.GetRKAPVersions(report.CompanyID, report.Year)
+			if err != nil {
+				return fmt.Errorf("failed to check RKAP revisions: %w", err)
+			}
+			if len(versions) > 1 {
+				return errors.New("RKAP awal tidak dapat dihapus karena sudah memiliki revisi")
+			}
+		}
+	}
 
 	if err := uc.repo.Delete(id); err != nil {
 		zapLog.Error("Failed to delete financial report", zap.Error(err))
 		return fmt.Errorf("failed to delete financial report: %w", err)
 	}
+	if report.IsRKAP {
+		if err := uc.phasingRepo.ReplaceForRKAP(id, nil); err != nil {
+			zapLog.Warn("Failed to delete RKAP phasing", zap.String("rkap_id", id), zap.Error(err))
+		}
+	}
 
 	// Audit trail
 	reportType := "RKAP"
@@ -523,76 +580,9 @@ func createComparisonItem(rkap, realisasi interface{}) domain.ComparisonItem {
 	}
 }
 
-func getFieldValue(report *domain.FinancialReportModel, field string) interface{} {
-	switch field {
-	case "year":
-		return report.Year
-	case "period":
-		return report.Period
-	case "is_rkap":
-		return report.IsRKAP
-	// Neraca
-	case "current_assets":
-		return report.CurrentAssets
-	case "non_current_assets":
-		return report.NonCurrentAssets
-	case "short_term_liabilities":
-		return report.ShortTermLiabilities
-	case "long_term_liabilities":
-		return report.LongTermLiabilities
-	case "equity":
-		return report.Equity
-	// Laba Rugi
-	case "revenue":
-		return report.Revenue
-	case "operating_expenses":
-		return report.OperatingExpenses
-	case "operating_profit":
-		return report.OperatingProfit
-	case "other_income":
-		return report.OtherIncome
-	case "tax":
-		return report.Tax
-	case "net_profit":
-		return report.NetProfit
-	// Cashflow
-	case "operating_cashflow":
-		return report.OperatingCashflow
-	case "investing_cashflow":
-		return report.InvestingCashflow
-	case "financing_cashflow":
-		return report.FinancingCashflow
-	case "ending_balance":
-		return report.EndingBalance
-	// Rasio
-	case "roe":
-		return report.ROE
-	case "roi":
-		return report.ROI
-	case "current_ratio":
-		return report.CurrentRatio
-	case "cash_ratio":
-		return report.CashRatio
-	case "ebitda":
-		return report.EBITDA
-	case "ebitda_margin":
-		return report.EBITDAMargin
-	case "net_profit_margin":
-		return report.NetProfitMargin
-	case "operating_profit_margin":
-		return report.OperatingProfitMargin
-	case "debt_to_equity":
-		return report.DebtToEquity
-	case "remark":
-		return report.Remark
-	default:
-		return nil
-	}
-}
-
 // ExportPerformanceExcel generates Excel file with 4 sheets (Balance Sheet, Profit & Loss, Cashflow, Ratio)
 // Each sheet contains chart and table data with RKAP vs Realisasi comparison
-func (uc *financialReportUseCase) ExportPerformanceExcel(companyID, startPeriod, endPeriod string) ([]byte, error) {
+func (uc *financialReportUseCase) ExportPerformanceExcel(companyID, startPeriod, endPeriod string, rkapVersion *int) ([]byte, error) {
 	// #region agent log
 	logEntryExport := map[string]interface{}{
 		"sessionId":    "debug-session",
@@ -615,46 +605,12 @@ func (uc *financialReportUseCase) ExportPerformanceExcel(companyID, startPeriod,
 	}
 	// #endregion
 
-	// Validasi format period (YYYY-MM)
-	if len(startPeriod) != 7 || len(endPeriod) != 7 {
-		return nil, fmt.Errorf("invalid period format, expected YYYY-MM")
-	}
-
-	// Ambil semua financial reports untuk company
-	reports, err := uc.repo.GetByCompanyID(companyID)
+	// Data yang sama dipakai export PDF (lihat performance_report_definition.go)
+	filteredReports, rkapReport, monthlyRKAP, err := uc.loadPerformanceData(companyID, startPeriod, endPeriod, rkapVersion)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get financial reports: %w", err)
+		return nil, err
 	}
 
-	// Extract tahun dan bulan dari periods
-	startYear := startPeriod[:4]
-	startMonth := startPeriod[5:7]
-
-	// Filter reports by period range
-	var filteredReports []domain.FinancialReportModel
-	var rkapReport *domain.FinancialReportModel
-
-	for _, report := range reports {
-		// Get RKAP for the year
-		if report.IsRKAP && report.Year == startYear {
-			rkapReport = &report
-		}
-
-		// Ambil realisasi reports dalam range
-		if !report.IsRKAP {
-			reportYear := report.Period[:4]
-			reportMonth := report.Period[5:7]
-			if reportYear == startYear && reportMonth >= startMonth && reportMonth <= endPeriod[5:7] {
-				filteredReports = append(filteredReports, report)
-			}
-		}
-	}
-
-	// Sort berdasarkan period
-	sort.Slice(filteredReports, func(i, j int) bool {
-		return filteredReports[i].Period < filteredReports[j].Period
-	})
-
 	// Create Excel file
 	f := excelize.NewFile()
 	defer f.Close()
@@ -684,19 +640,19 @@ func (uc *financialReportUseCase) ExportPerformanceExcel(companyID, startPeriod,
 	}
 	// #endregion
 
-	if err := uc.generateBalanceSheetSheet(f, filteredReports, rkapReport, startPeriod, endPeriod); err != nil {
+	if err := uc.generateBalanceSheetSheet(f, filteredReports, rkapReport, monthlyRKAP, startPeriod, endPeriod); err != nil {
 		return nil, fmt.Errorf("failed to generate balance sheet sheet: %w", err)
 	}
 
-	if err := uc.generateProfitLossSheet(f, filteredReports, rkapReport, startPeriod, endPeriod); err != nil {
+	if err := uc.generateProfitLossSheet(f, filteredReports, rkapReport, monthlyRKAP, startPeriod, endPeriod); err != nil {
 		return nil, fmt.Errorf("failed to generate profit loss sheet: %w", err)
 	}
 
-	if err := uc.generateCashflowSheet(f, filteredReports, rkapReport, startPeriod, endPeriod); err != nil {
+	if err := uc.generateCashflowSheet(f, filteredReports, rkapReport, monthlyRKAP, startPeriod, endPeriod); err != nil {
 		return nil, fmt.Errorf("failed to generate cashflow sheet: %w", err)
 	}
 
-	if err := uc.generateRatioSheet(f, filteredReports, rkapReport, startPeriod, endPeriod); err != nil {
+	if err := uc.generateRatioSheet(f, filteredReports, rkapReport, monthlyRKAP, startPeriod, endPeriod); err != nil {
 		return nil, fmt.Errorf("failed to generate ratio sheet: %w", err)
 	}
 
@@ -804,6 +760,7 @@ func (uc *financialReportUseCase) writeFinancialTable(
 	items []tableItem,
 	reports []domain.FinancialReportModel,
 	rkap *domain.FinancialReportModel,
+	monthlyRKAP []*domain.FinancialReportModel,
 	startRow int,
 ) (dataStartRow int, chartDataStartRow int) {
 	// Row untuk main header (category names dengan merged cells untuk RKAP/Realisasi)
@@ -941,6 +898,7 @@ func (uc *financialReportUseCase) writeFinancialTable(
 		_ = f.SetCellStyle(sheetName, fmt.Sprintf("A%d", dataRow), fmt.Sprintf("A%d", dataRow), monthCellStyle)
 
 		// Write data for each item - each item has RKAP and Realisasi for this month
+		monthRKAP := performanceRKAPAt(rkap, monthlyRKAP, monthIdx)
 		col = 2
 		for _, item := range items {
 			var rkapVal string
@@ -948,8 +906,8 @@ func (uc *financialReportUseCase) writeFinancialTable(
 
 			if item.isRatio {
 				// For ratio fields
-				if rkap != nil && item.getRkapF != nil {
-					val := item.getRkapF(rkap)
+				if monthRKAP != nil && item.getRkapF != nil {
+					val := item.getRkapF(monthRKAP)
 					rkapVal = formatRatioValue(val)
 				} else {
 					rkapVal = "-"
@@ -962,8 +920,8 @@ func (uc *financialReportUseCase) writeFinancialTable(
 				}
 			} else {
 				// For currency fields
-				if rkap != nil && item.getRkap != nil {
-					val := item.getRkap(rkap)
+				if monthRKAP != nil && item.getRkap != nil {
+					val := item.getRkap(monthRKAP)
 					rkapVal = formatCurrencyValue(val)
 				} else {
 					rkapVal = "-"
@@ -976,7 +934,7 @@ func (uc *financialReportUseCase) writeFinancialTable(
 				}
 			}
 
-			// Write RKAP value (same for all months since it's annual) as string to preserve format
+			// Write RKAP value (target bulan ini sesuai phasing) as string to preserve format
 			rkapCell, _ := excelize.CoordinatesToCellName(col, dataRow)
 			_ = f.SetCellValue(sheetName, rkapCell, rkapVal)
 			_ = f.SetCellStyle(sheetName, rkapCell, rkapCell, dataCellStyle)
@@ -1008,6 +966,7 @@ func (uc *financialReportUseCase) generateBalanceSheetSheet(
 	f *excelize.File,
 	reports []domain.FinancialReportModel,
 	rkap *domain.FinancialReportModel,
+	monthlyRKAP []*domain.FinancialReportModel,
 	startPeriod, endPeriod string,
 ) error {
 	// #region agent log
@@ -1031,97 +990,27 @@ func (uc *financialReportUseCase) generateBalanceSheetSheet(
 	}
 	// #endregion
 
-	sheetName := "Balance Sheet"
+	section := getPerformanceSection(performanceSectionBalanceSheet)
+	sheetName := section.sheetName
 	_, err := f.NewSheet(sheetName)
 	if err != nil {
 		return err
 	}
 
 	// Title
-	_ = f.SetCellValue(sheetName, "A1", fmt.Sprintf("Neraca (Balance Sheet) - Periode %s - %s", startPeriod, endPeriod))
+	_ = f.SetCellValue(sheetName, "A1", section.periodTitle(startPeriod, endPeriod))
 	titleStyle, _ := f.NewStyle(&excelize.Style{
 		Font: &excelize.Font{Bold: true, Size: 14},
 	})
 	_ = f.SetCellStyle(sheetName, "A1", "A1", titleStyle)
 
-	// Define balance sheet items
-	items := []tableItem{
-		{
-			label:   "A. Aset Lancar",
-			field:   "current_assets",
-			isRatio: false,
-			getRkap: func(r *domain.FinancialReportModel) int64 {
-				if r == nil {
-					return 0
-				}
-				return r.CurrentAssets
-			},
-			getReal: func(r domain.FinancialReportModel) int64 {
-				return r.CurrentAssets
-			},
-		},
-		{
-			label:   "B. Aset Tidak Lancar",
-			field:   "non_current_assets",
-			isRatio: false,
-			getRkap: func(r *domain.FinancialReportModel) int64 {
-				if r == nil {
-					return 0
-				}
-				return r.NonCurrentAssets
-			},
-			getReal: func(r domain.FinancialReportModel) int64 {
-				return r.NonCurrentAssets
-			},
-		},
-		{
-			label:   "C. Liabilitas Jangka Pendek",
-			field:   "short_term_liabilities",
-			isRatio: false,
-			getRkap: func(r *domain.FinancialReportModel) int64 {
-				if r == nil {
-					return 0
-				}
-				return r.ShortTermLiabilities
-			},
-			getReal: func(r domain.FinancialReportModel) int64 {
-				return r.ShortTermLiabilities
-			},
-		},
-		{
-			label:   "D. Liabilitas Jangka Panjang",
-			field:   "long_term_liabilities",
-			isRatio: false,
-			getRkap: func(r *domain.FinancialReportModel) int64 {
-				if r == nil {
-					return 0
-				}
-				return r.LongTermLiabilities
-			},
-			getReal: func(r domain.FinancialReportModel) int64 {
-				return r.LongTermLiabilities
-			},
-		},
-		{
-			label:   "E. Ekuitas",
-			field:   "equity",
-			isRatio: false,
-			getRkap: func(r *domain.FinancialReportModel) int64 {
-				if r == nil {
-					return 0
-				}
-				return r.Equity
-			},
-			getReal: func(r domain.FinancialReportModel) int64 {
-				return r.Equity
-			},
-		},
-	}
+	// Items dari definisi bersama (performance_report_definition.go)
+	items := section.items
 
 	// Write table starting from row 3 (after title at row 1, row 2 is empty)
 	// This will write: row 3-4 = headers, row 6+ = data rows
 	// IMPORTANT: Table must be written first before chart data to prevent overlap
-	_, _ = uc.writeFinancialTable(f, sheetName, items, reports, rkap, 3)
+	_, _ = uc.writeFinancialTable(f, sheetName, items, reports, rkap, monthlyRKAP, 3)
 
 	// Calculate where chart data should be placed (after table, with enough spacing)
 	// Count actual number of months for calculation
@@ -1428,6 +1317,7 @@ func (uc *financialReportUseCase) generateProfitLossSheet(
 	f *excelize.File,
 	reports []domain.FinancialReportModel,
 	rkap *domain.FinancialReportModel,
+	monthlyRKAP []*domain.FinancialReportModel,
 	startPeriod, endPeriod string,
 ) error {
 	// #region agent log
@@ -1451,108 +1341,24 @@ func (uc *financialReportUseCase) generateProfitLossSheet(
 	}
 	// #endregion
 
-	sheetName := "Profit & Loss"
+	section := getPerformanceSection(performanceSectionProfitLoss)
+	sheetName := section.sheetName
 	_, err := f.NewSheet(sheetName)
 	if err != nil {
 		return err
 	}
 
-	_ = f.SetCellValue(sheetName, "A1", fmt.Sprintf("Laba Rugi (Profit & Loss) - Periode %s - %s", startPeriod, endPeriod))
+	_ = f.SetCellValue(sheetName, "A1", section.periodTitle(startPeriod, endPeriod))
 	titleStyle, _ := f.NewStyle(&excelize.Style{
 		Font: &excelize.Font{Bold: true, Size: 14},
 	})
 	_ = f.SetCellStyle(sheetName, "A1", "A1", titleStyle)
 
-	// Define profit & loss items
-	items := []tableItem{
-		{
-			label:   "A. Revenue",
-			field:   "revenue",
-			isRatio: false,
-			getRkap: func(r *domain.FinancialReportModel) int64 {
-				if r == nil {
-					return 0
-				}
-				return r.Revenue
-			},
-			getReal: func(r domain.FinancialReportModel) int64 {
-				return r.Revenue
-			},
-		},
-		{
-			label:   "B. Beban Usaha",
-			field:   "operating_expenses",
-			isRatio: false,
-			getRkap: func(r *domain.FinancialReportModel) int64 {
-				if r == nil {
-					return 0
-				}
-				return r.OperatingExpenses
-			},
-			getReal: func(r domain.FinancialReportModel) int64 {
-				return r.OperatingExpenses
-			},
-		},
-		{
-			label:   "C. Laba Usaha",
-			field:   "operating_profit",
-			isRatio: false,
-			getRkap: func(r *domain.FinancialReportModel) int64 {
-				if r == nil {
-					return 0
-				}
-				return r.OperatingProfit
-			},
-			getReal: func(r domain.FinancialReportModel) int64 {
-				return r.OperatingProfit
-			},
-		},
-		{
-			label:   "D. Pendapatan Lain-Lain",
-			field:   "other_income",
-			isRatio: false,
-			getRkap: func(r *domain.FinancialReportModel) int64 {
-				if r == nil {
-					return 0
-				}
-				return r.OtherIncome
-			},
-			getReal: func(r domain.FinancialReportModel) int64 {
-				return r.OtherIncome
-			},
-		},
-		{
-			label:   "E. Tax",
-			field:   "tax",
-			isRatio: false,
-			getRkap: func(r *domain.FinancialReportModel) int64 {
-				if r == nil {
-					return 0
-				}
-				return r.Tax
-			},
-			getReal: func(r domain.FinancialReportModel) int64 {
-				return r.Tax
-			},
-		},
-		{
-			label:   "F. Laba Bersih",
-			field:   "net_profit",
-			isRatio: false,
-			getRkap: func(r *domain.FinancialReportModel) int64 {
-				if r == nil {
-					return 0
-				}
-				return r.NetProfit
-			},
-			getReal: func(r domain.FinancialReportModel) int64 {
-				return r.NetProfit
-			},
-		},
-	}
+	// Items dari definisi bersama (performance_report_definition.go)
+	items := section.items
 
 	// Write table
-	_, _ = uc.writeFinancialTable(f, sheetName, items, reports, rkap, 3)
+	_, _ = uc.writeFinancialTable(f, sheetName, items, reports, rkap, monthlyRKAP, 3)
 
 	numMonths := len(reports)
 	if numMonths == 0 && rkap != nil {
@@ -1593,11 +1399,11 @@ func (uc *financialReportUseCase) generateProfitLossSheet(
 	// Chart data rows: Revenue and Net Profit
 	_ = f.SetCellValue(sheetName, fmt.Sprintf("A%d", chartDataRow), "Revenue (RKAP)")
 	col = 2
-	rkapRev := int64(0)
-	if rkap != nil {
-		rkapRev = rkap.Revenue
-	}
 	for i := 0; i < numMonths; i++ {
+		rkapRev := int64(0)
+		if monthRKAP := performanceRKAPAt(rkap, monthlyRKAP, i); monthRKAP != nil {
+			rkapRev = monthRKAP.Revenue
+		}
 		cell, _ := excelize.CoordinatesToCellName(col, chartDataRow)
 		_ = f.SetCellValue(sheetName, cell, rkapRev)
 		col++
@@ -1618,11 +1424,11 @@ func (uc *financialReportUseCase) generateProfitLossSheet(
 	chartDataRow++
 	_ = f.SetCellValue(sheetName, fmt.Sprintf("A%d", chartDataRow), "Net Profit (RKAP)")
 	col = 2
-	rkapNP := int64(0)
-	if rkap != nil {
-		rkapNP = rkap.NetProfit
-	}
 	for i := 0; i < numMonths; i++ {
+		rkapNP := int64(0)
+		if monthRKAP := performanceRKAPAt(rkap, monthlyRKAP, i); monthRKAP != nil {
+			rkapNP = monthRKAP.NetProfit
+		}
 		cell, _ := excelize.CoordinatesToCellName(col, chartDataRow)
 		_ = f.SetCellValue(sheetName, cell, rkapNP)
 		col++
@@ -1749,6 +1555,7 @@ func (uc *financialReportUseCase) generateCashflowSheet(
 	f *excelize.File,
 	reports []domain.FinancialReportModel,
 	rkap *domain.FinancialReportModel,
+	monthlyRKAP []*domain.FinancialReportModel,
 	startPeriod, endPeriod string,
 ) error {
 	// #region agent log
@@ -1772,80 +1579,24 @@ func (uc *financialReportUseCase) generateCashflowSheet(
 	}
 	// #endregion
 
-	sheetName := "Cashflow"
+	section := getPerformanceSection(performanceSectionCashflow)
+	sheetName := section.sheetName
 	_, err := f.NewSheet(sheetName)
 	if err != nil {
 		return err
 	}
 
-	_ = f.SetCellValue(sheetName, "A1", fmt.Sprintf("Cashflow - Periode %s - %s", startPeriod, endPeriod))
+	_ = f.SetCellValue(sheetName, "A1", section.periodTitle(startPeriod, endPeriod))
 	titleStyle, _ := f.NewStyle(&excelize.Style{
 		Font: &excelize.Font{Bold: true, Size: 14},
 	})
 	_ = f.SetCellStyle(sheetName, "A1", "A1", titleStyle)
 
-	// Define cashflow items
-	items := []tableItem{
-		{
-			label:   "A. Arus kas bersih dari operasi",
-			field:   "operating_cashflow",
-			isRatio: false,
-			getRkap: func(r *domain.FinancialReportModel) int64 {
-				if r == nil {
-					return 0
-				}
-				return r.OperatingCashflow
-			},
-			getReal: func(r domain.FinancialReportModel) int64 {
-				return r.OperatingCashflow
-			},
-		},
-		{
-			label:   "B. Arus kas bersih dari investasi",
-			field:   "investing_cashflow",
-			isRatio: false,
-			getRkap: func(r *domain.FinancialReportModel) int64 {
-				if r == nil {
-					return 0
-				}
-				return r.InvestingCashflow
-			},
-			getReal: func(r domain.FinancialReportModel) int64 {
-				return r.InvestingCashflow
-			},
-		},
-		{
-			label:   "C. Arus kas bersih dari pendanaan",
-			field:   "financing_cashflow",
-			isRatio: false,
-			getRkap: func(r *domain.FinancialReportModel) int64 {
-				if r == nil {
-					return 0
-				}
-				return r.FinancingCashflow
-			},
-			getReal: func(r domain.FinancialReportModel) int64 {
-				return r.FinancingCashflow
-			},
-		},
-		{
-			label:   "D. Saldo Akhir",
-			field:   "ending_balance",
-			isRatio: false,
-			getRkap: func(r *domain.FinancialReportModel) int64 {
-				if r == nil {
-					return 0
-				}
-				return r.EndingBalance
-			},
-			getReal: func(r domain.FinancialReportModel) int64 {
-				return r.EndingBalance
-			},
-		},
-	}
+	// Items dari definisi bersama (performance_report_definition.go)
+	items := section.items
 
 	// Write table
-	tableDataStartRow, _ := uc.writeFinancialTable(f, sheetName, items, reports, rkap, 3)
+	tableDataStartRow, _ := uc.writeFinancialTable(f, sheetName, items, reports, rkap, monthlyRKAP, 3)
 
 	numMonths := len(reports)
 	if numMonths == 0 && rkap != nil {
@@ -1886,11 +1637,11 @@ func (uc *financialReportUseCase) generateCashflowSheet(
 	// Chart data rows - Net Cashflow and Ending Balance
 	_ = f.SetCellValue(sheetName, fmt.Sprintf("A%d", chartDataRow), "Net Cashflow (RKAP)")
 	col = 2
-	rkapNetCF := int64(0)
-	if rkap != nil {
-		rkapNetCF = rkap.OperatingCashflow + rkap.InvestingCashflow + rkap.FinancingCashflow
-	}
 	for i := 0; i < numMonths; i++ {
+		rkapNetCF := int64(0)
+		if monthRKAP := performanceRKAPAt(rkap, monthlyRKAP, i); monthRKAP != nil {
+			rkapNetCF = monthRKAP.OperatingCashflow + monthRKAP.InvestingCashflow + monthRKAP.FinancingCashflow
+		}
 		cell, _ := excelize.CoordinatesToCellName(col, chartDataRow)
 		_ = f.SetCellValue(sheetName, cell, rkapNetCF)
 		col++
@@ -2016,82 +1767,27 @@ func (uc *financialReportUseCase) generateRatioSheet(
 	f *excelize.File,
 	reports []domain.FinancialReportModel,
 	rkap *domain.FinancialReportModel,
+	monthlyRKAP []*domain.FinancialReportModel,
 	startPeriod, endPeriod string,
 ) error {
-	sheetName := "Ratio"
+	section := getPerformanceSection(performanceSectionRatio)
+	sheetName := section.sheetName
 	_, err := f.NewSheet(sheetName)
 	if err != nil {
 		return err
 	}
 
-	_ = f.SetCellValue(sheetName, "A1", fmt.Sprintf("Rasio Keuangan (%%) - Periode %s - %s", startPeriod, endPeriod))
+	_ = f.SetCellValue(sheetName, "A1", section.periodTitle(startPeriod, endPeriod))
 	titleStyle, _ := f.NewStyle(&excelize.Style{
 		Font: &excelize.Font{Bold: true, Size: 14},
 	})
 	_ = f.SetCellStyle(sheetName, "A1", "A1", titleStyle)
 
-	// Define ratio items
-	items := []tableItem{
-		{
-			label:   "ROE (Return on Equity)",
-			field:   "roe",
-			isRatio: true,
-			getRkapF: func(r *domain.FinancialReportModel) float64 {
-				if r == nil {
-					return 0
-				}
-				return r.ROE
-			},
-			getRealF: func(r domain.FinancialReportModel) float64 {
-				return r.ROE
-			},
-		},
-		{
-			label:   "ROI (Return on Investment)",
-			field:   "roi",
-			isRatio: true,
-			getRkapF: func(r *domain.FinancialReportModel) float64 {
-				if r == nil {
-					return 0
-				}
-				return r.ROI
-			},
-			getRealF: func(r domain.FinancialReportModel) float64 {
-				return r.ROI
-			},
-		},
-		{
-			label:   "Rasio Lancar",
-			field:   "current_ratio",
-			isRatio: true,
-			getRkapF: func(r *domain.FinancialReportModel) float64 {
-				if r == nil {
-					return 0
-				}
-				return r.CurrentRatio
-			},
-			getRealF: func(r domain.FinancialReportModel) float64 {
-				return r.CurrentRatio
-			},
-		},
-		{
-			label:   "Rasio Kas",
-			field:   "cash_ratio",
-			isRatio: true,
-			getRkapF: func(r *domain.FinancialReportModel) float64 {
-				if r == nil {
-					return 0
-				}
-				return r.CashRatio
-			},
-			getRealF: func(r domain.FinancialReportModel) float64 {
-				return r.CashRatio
-			},
-		},
-	}
+	// Items dari definisi bersama (performance_report_definition.go)
+	items := section.items
 
 	// Write table
-	tableDataStartRow, _ := uc.writeFinancialTable(f, sheetName, items, reports, rkap, 3)
+	tableDataStartRow, _ := uc.writeFinancialTable(f, sheetName, items, reports, rkap, monthlyRKAP, 3)
 
 	numMonths := len(reports)
 	if numMonths == 0 && rkap != nil {
@@ -2230,3 +1926,36 @@ func (uc *financialReportUseCase) generateRatioSheet(
 
 	return nil
 }
+
+func (uc *financialReportUseCase) ExportFinancialReports(companyID, formatName, startPeriod, endPeriod string) ([]byte, FinancialReportFormat, error) {
+	format, ok := GetFinancialReportFormat(formatName)
+	if !ok {
+		return nil, nil, ErrFinancialFormatUnsupported
+	}
+
+	reports, err := uc.repo.GetByCompanyID(companyID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get financial reports: %w", err)
+	}
+
+	realisasi := make([]domain.FinancialReportModel, 0, len(reports))
+	for i, report := range reports {
+		// Format export berbasis kolom Tahun/Bulan, hanya realisasi bulanan management account
+		if !isMonthlyRealisasi(&reports[i]) {
+			continue
+		}
+		if (startPeriod != "" && report.Period < startPeriod) || (endPeriod != "" && report.Period > endPeriod) {
+			continue
+		}
+		realisasi = append(realisasi, report)
+	}
+	sort.Slice(realisasi, func(i, j int) bool {
+		return realisasi[i].Period < realisasi[j].Period
+	})
+
+	data, err := format.Export(realisasi)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to export financial reports: %w", err)
+	}
+	return data, format, nil
+}
@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/audit"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Strategi konflik saat data reports lama berbeda dengan realisasi yang sudah ada
+const (
+	LegacyReportConflictSkip   = "skip"   // Realisasi tidak diubah, baris dicatat di laporan konflik
+	LegacyReportConflictLegacy = "legacy" // Nilai reports lama menimpa Revenue/Opex/NPAT realisasi
+)
+
+// Alasan konflik migrasi
+const (
+	LegacyReportConflictValueMismatch  = "value_mismatch"
+	LegacyReportConflictInvalidPeriod  = "invalid_period"
+	LegacyReportConflictMissingCompany = "missing_company"
+)
+
+var legacyReportPeriodPattern = regexp.MustCompile(`^\d{4}-(0[1-9]|1[0-2])$`)
+
+// LegacyReportConflict adalah satu baris reports lama yang tidak bisa dimigrasikan otomatis
+type LegacyReportConflict struct {
+	ReportID          string                       `json:"report_id"`
+	CompanyID         string                       `json:"company_id"`
+	Period            string                       `json:"period"`
+	Reason            string                       `json:"reason"`
+	FinancialReportID string                       `json:"financial_report_id,omitempty"`
+	Fields            map[string]audit.FieldChange `json:"fields,omitempty"` // old = realisasi, new = reports lama
+	Resolved          bool                         `json:"resolved"`         // true jika ditimpa dengan strategi legacy
+}
+
+// LegacyReportMigrationResult ringkasan migrasi tabel reports ke financial_reports
+type LegacyReportMigrationResult struct {
+	Total     int                    `json:"total"`
+	Created   int                    `json:"created"`   // Realisasi baru dibuat dari reports lama
+	Merged    int                    `json:"merged"`    // Realisasi sudah ada, field kosong dilengkapi
+	Unchanged int                    `json:"unchanged"` // Sudah pernah dimigrasikan / nilai sama persis
+	Conflicts []LegacyReportConflict `json:"conflicts"`
+}
+
+// LegacyReportMigrationUseCase interface untuk memindahkan tabel reports lama ke financial_reports
+type LegacyReportMigrationUseCase interface {
+	MigrateLegacyReports(onConflict string, dryRun bool) (*LegacyReportMigrationResult, error)
+}
+
+type legacyReportMigrationUseCase struct {
+	db *gorm.DB
+}
+
+// NewLegacyReportMigrationUseCaseWithDB creates a new legacy report migration use case with injected DB
+func NewLegacyReportMigrationUseCaseWithDB(db *gorm.DB) LegacyReportMigrationUseCase {
+	return &legacyReportMigrationUseCase{db: db}
+}
+
+// NewLegacyReportMigrationUseCase creates a new legacy report migration use case with default DB
+func NewLegacyReportMigrationUseCase() LegacyReportMigrationUseCase {
+	return NewLegacyReportMigrationUseCaseWithDB(database.GetDB())
+}
+
+// MigrateLegacyReports membaca seluruh baris tabel reports dan menuliskannya sebagai realisasi bulanan.
+// Aman dijalankan berulang: baris yang sudah dimigrasikan terhitung unchanged.
+func (uc *legacyReportMigrationUseCase) MigrateLegacyReports(onConflict string, dryRun bool) (*LegacyReportMigrationResult, error) {
+	if onConflict == "" {
+		onConflict = LegacyReportConflictSkip
+	}
+	if onConflict != LegacyReportConflictSkip && onConflict != LegacyReportConflictLegacy {
+		return nil, fmt.Errorf("strategi konflik tidak dikenal: %s", onConflict)
+	}
+
+	result := &LegacyReportMigrationResult{Conflicts: []LegacyReportConflict{}}
+	if !uc.db.Migrator().HasTable(&domain.ReportModel{}) {
+		return result, nil
+	}
+
+	var legacyReports []domain.ReportModel
+	if err := uc.db.Order("period ASC, created_at ASC").Find(&legacyReports).Error; err != nil {
+		return nil, fmt.Errorf("failed to read legacy reports: %w", err)
+	}
+	result.Total = len(legacyReports)
+
+	err := uc.db.Transaction(func(tx *gorm.DB) error {
+		for i := range legacyReports {
+			if err := uc.migrateOne(tx, &legacyReports[i], onConflict, result); err != nil {
+				return err
+			}
+		}
+		if dryRun {
+			return errLegacyMigrationDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errLegacyMigrationDryRun) {
+		return nil, err
+	}
+	return result, nil
+}
+
+// errLegacyMigrationDryRun dipakai untuk rollback transaksi pada dry run
+var errLegacyMigrationDryRun = errors.New("dry run")
+
+func (uc *legacyReportMigrationUseCase) migrateOne(tx *gorm.DB, legacy *domain.ReportModel, onConflict string, result *LegacyReportMigrationResult) error {
+	conflict := LegacyReportConflict{ReportID: legacy.ID, CompanyID: legacy.CompanyID, Period: legacy.Period}
+
+	if !legacyReportPeriodPattern.MatchString(legacy.Period) {
+		conflict.Reason = LegacyReportConflictInvalidPeriod
+		result.Conflicts = append(result.Conflicts, conflict)
+		return nil
+	}
+	var companyCount int64
+	if err := tx.Model(&domain.CompanyModel{}).Where("id = ?", legacy.CompanyID).Count(&companyCount).Error; err != nil {
+		return err
+	}
+	if companyCount == 0 {
+		conflict.Reason = LegacyReportConflictMissingCompany
+		result.Conflicts = append(result.Conflicts, conflict)
+		return nil
+	}
+
+	var existing domain.FinancialReportModel
+	err := tx.Where("company_id = ? AND period = ? AND is_rkap = ?", legacy.CompanyID, legacy.Period, false).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		financial := &domain.FinancialReportModel{ID: legacy.ID, CreatedAt: legacy.CreatedAt}
+		var idTaken int64
+		if err := tx.Model(&domain.FinancialReportModel{}).Where("id = ?", legacy.ID).Count(&idTaken).Error; err != nil {
+			return err
+		}
+		if idTaken > 0 {
+			financial.ID = uuid.GenerateUUID()
+		}
+		repository.ApplyReportToFinancialReport(legacy, financial)
+		if err := tx.Create(financial).Error; err != nil {
+			return fmt.Errorf("failed to create financial report for legacy report %s: %w", legacy.ID, err)
+		}
+		result.Created++
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Realisasi sudah ada: nilai yang sudah terisi dan berbeda dianggap konflik, nilai kosong dilengkapi
+	merged := existing
+	mismatch := make(map[string]audit.FieldChange)
+	mergeLegacyValue("revenue", &merged.Revenue, legacy.Revenue, mismatch)
+	mergeLegacyValue("operating_expenses", &merged.OperatingExpenses, legacy.Opex, mismatch)
+	mergeLegacyValue("net_profit", &merged.NetProfit, legacy.NPAT, mismatch)
+	mergeLegacyValue("dividend", &merged.Dividend, legacy.Dividend, mismatch)
+	if merged.FinancialRatio == 0 {
+		merged.FinancialRatio = legacy.FinancialRatio
+	}
+	if merged.Attachment == nil {
+		merged.Attachment = legacy.Attachment
+	}
+	if merged.Remark == nil {
+		merged.Remark = legacy.Remark
+	}
+	if merged.InputterID == nil {
+		merged.InputterID = legacy.InputterID
+	}
+
+	if len(mismatch) > 0 {
+		conflict.Reason = LegacyReportConflictValueMismatch
+		conflict.FinancialReportID = existing.ID
+		conflict.Fields = mismatch
+		if onConflict != LegacyReportConflictLegacy {
+			result.Conflicts = append(result.Conflicts, conflict)
+			return nil
+		}
+		conflict.Resolved = true
+		result.Conflicts = append(result.Conflicts, conflict)
+		merged.Revenue = legacy.Revenue
+		merged.OperatingExpenses = legacy.Opex
+		merged.NetProfit = legacy.NPAT
+		merged.Dividend = legacy.Dividend
+	}
+
+	if len(audit.DiffModels(&existing, &merged)) == 0 && !conflict.Resolved {
+		result.Unchanged++
+		return nil
+	}
+	if err := tx.Save(&merged).Error; err != nil {
+		return fmt.Errorf("failed to merge legacy report %s: %w", legacy.ID, err)
+	}
+	result.Merged++
+	return nil
+}
+
+// mergeLegacyValue mengisi nilai realisasi yang masih 0; nilai berbeda yang sudah terisi dicatat sebagai mismatch
+func mergeLegacyValue(field string, target *int64, legacyValue int64, mismatch map[string]audit.FieldChange) {
+	switch {
+	case *target == legacyValue:
+	case *target == 0:
+		*target = legacyValue
+	default:
+		mismatch[field] = audit.FieldChange{Old: *target, New: legacyValue}
+	}
+}
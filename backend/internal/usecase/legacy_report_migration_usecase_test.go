@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+	"github.com/repoareta/pedeve-dms-app/backend/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// TestLegacyReportMigration tests migrasi tabel reports lama ke realisasi financial_reports beserta laporan konflik
+func TestLegacyReportMigration(t *testing.T) {
+	db := helpers.SetupTestDB(t)
+	defer helpers.CleanupTestDB(t, db)
+
+	company := createTestCompanyForNotification(t, db, nil)
+	uc := NewLegacyReportMigrationUseCaseWithDB(db)
+	reports := repository.NewReportRepositoryWithDB(db)
+
+	newLegacy := createLegacyReport(t, db, company.ID, "2025-01", 100)
+	createLegacyReport(t, db, company.ID, "2025-02", 200)
+	createLegacyReport(t, db, company.ID, "2025-03", 300)
+	createLegacyReport(t, db, company.ID, "2025/04", 400)
+
+	// Realisasi Februari sudah diisi lewat financial report tanpa revenue, Maret dengan revenue berbeda
+	february := createTestFinancialReportForImport(t, db, company.ID, "2025-02", 0)
+	february.Equity = 999
+	require.NoError(t, db.Save(february).Error)
+	createTestFinancialReportForImport(t, db, company.ID, "2025-03", 350)
+
+	t.Run("Dry run does not write", func(t *testing.T) {
+		result, err := uc.MigrateLegacyReports("", true)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Created)
+		_, err = reports.GetByCompanyIDAndPeriod(company.ID, "2025-01")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Migrate with conflict report", func(t *testing.T) {
+		result, err := uc.MigrateLegacyReports(LegacyReportConflictSkip, false)
+		require.NoError(t, err)
+		assert.Equal(t, 4, result.Total)
+		assert.Equal(t, 1, result.Created)
+		assert.Equal(t, 1, result.Merged)
+		require.Len(t, result.Conflicts, 2)
+
+		reasons := map[string]LegacyReportConflict{}
+		for _, conflict := range result.Conflicts {
+			reasons[conflict.Reason] = conflict
+		}
+		assert.Equal(t, "2025/04", reasons[LegacyReportConflictInvalidPeriod].Period)
+		mismatch := reasons[LegacyReportConflictValueMismatch]
+		assert.Equal(t, "2025-03", mismatch.Period)
+		assert.EqualValues(t, 350, mismatch.Fields["revenue"].Old)
+		assert.EqualValues(t, 300, mismatch.Fields["revenue"].New)
+
+		// Report lama dapat diakses lewat facade /reports dengan ID yang sama
+		migrated, err := reports.GetByID(newLegacy.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(100), migrated.Revenue)
+		assert.Equal(t, int64(10), migrated.Dividend)
+
+		// Field neraca realisasi yang sudah ada tetap utuh
+		var merged domain.FinancialReportModel
+		require.NoError(t, db.First(&merged, "id = ?", february.ID).Error)
+		assert.Equal(t, int64(200), merged.Revenue)
+		assert.Equal(t, int64(999), merged.Equity)
+	})
+
+	t.Run("Rerun is idempotent and legacy strategy resolves mismatch", func(t *testing.T) {
+		result, err := uc.MigrateLegacyReports(LegacyReportConflictSkip, false)
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Created)
+		assert.Equal(t, 2, result.Unchanged)
+
+		result, err = uc.MigrateLegacyReports(LegacyReportConflictLegacy, false)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Merged)
+		assertFinancialReportRevenue(t, db, company.ID, "2025-03", 300)
+	})
+}
+
+func createLegacyReport(t *testing.T, db *gorm.DB, companyID, period string, revenue int64) *domain.ReportModel {
+	report := &domain.ReportModel{
+		ID:             uuid.GenerateUUID(),
+		Period:         period,
+		CompanyID:      companyID,
+		Revenue:        revenue,
+		Opex:           revenue / 2,
+		NPAT:           revenue / 4,
+		Dividend:       revenue / 10,
+		FinancialRatio: 1.5,
+	}
+	// Langsung ke tabel reports (bukan lewat facade) untuk mensimulasikan database lama
+	require.NoError(t, db.Create(report).Error)
+	return report
+}
@@ -5,7 +5,9 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/health"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/observability"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
 	"go.uber.org/zap"
 )
@@ -17,6 +19,17 @@ const (
 	DefaultNotificationRetentionDays = 30
 )
 
+// Nama job untuk metric dms_job_*
+const (
+	jobNotificationCleanup        = "notification_cleanup"
+	jobExpiringDocumentsCheck     = "notification_expiring_documents"
+	jobExpiringDirectorTermsCheck = "notification_expiring_director_terms"
+	jobNotificationEscalation     = "notification_escalation"
+)
+
+// Nama heartbeat scheduler notifikasi untuk readiness check
+const notificationSchedulerHeartbeat = "notification_scheduler"
+
 // GetNotificationRetentionDays mengambil periode retention dari environment variable
 func GetNotificationRetentionDays() int {
 	envKey := "NOTIFICATION_RETENTION_DAYS"
@@ -39,13 +52,14 @@ func GetNotificationRetentionDays() int {
 }
 
 // CleanupOldNotifications menghapus notifikasi yang sudah melewati retention period
-func CleanupOldNotifications() error {
+func CleanupOldNotifications() (err error) {
+	defer func(start time.Time) { observability.ObserveJob(jobNotificationCleanup, start, err) }(time.Now())
 	zapLog := logger.GetLogger()
 	notifRepo := repository.NewNotificationRepository()
 
 	retentionDays := GetNotificationRetentionDays()
 
-	err := notifRepo.DeleteOldNotifications(retentionDays)
+	err = notifRepo.DeleteOldNotifications(retentionDays)
 	if err != nil {
 		zapLog.Error("Error cleaning up old notifications", zap.Error(err))
 		return err
@@ -61,23 +75,22 @@ func CleanupOldNotifications() error {
 // StartNotificationCleanup memulai background cleanup job untuk notifikasi
 func StartNotificationCleanup() {
 	zapLog := logger.GetLogger()
+	health.RegisterHeartbeat(jobNotificationCleanup, 24*time.Hour, 1*time.Hour)
 
-	// Jalankan cleanup setiap 24 jam
-	ticker := time.NewTicker(24 * time.Hour)
-	defer ticker.Stop()
-
-	// Jalankan cleanup pertama kali setelah 1 jam
+	// Jalankan cleanup pertama kali setelah 1 jam, lalu setiap 24 jam
+	// Catatan: ticker dibuat di dalam goroutine agar tidak berhenti saat fungsi ini return
 	go func() {
 		time.Sleep(1 * time.Hour)
+		health.Beat(jobNotificationCleanup)
 		zapLog.Info("Starting initial notification cleanup")
 		if err := CleanupOldNotifications(); err != nil {
 			zapLog.Error("Initial notification cleanup failed", zap.Error(err))
 		}
-	}()
 
-	// Jalankan cleanup secara berkala
-	go func() {
+		ticker := time.NewTicker(24 * time.Hour)
+		defer ticker.Stop()
 		for range ticker.C {
+			health.Beat(jobNotificationCleanup)
 			zapLog.Info("Running scheduled notification cleanup")
 			if err := CleanupOldNotifications(); err != nil {
 				zapLog.Error("Scheduled notification cleanup failed", zap.Error(err))
@@ -87,15 +100,18 @@ func StartNotificationCleanup() {
 }
 
 // StartNotificationScheduler memulai background scheduler untuk check expiring documents dan director terms
-// Default threshold: 14 hari (bisa diubah via database settings per user atau environment variable NOTIFICATION_EXPIRY_THRESHOLD_DAYS)
-// Notifikasi pertama kali muncul sesuai threshold yang diatur user (default: 14 hari sebelum expired)
-// Dokumen/jabatan yang kurang dari threshold tapi belum ada notifikasinya akan langsung dibuat notifikasinya
+// Default threshold sistem: 14 hari (bisa diubah via environment variable NOTIFICATION_EXPIRY_THRESHOLD_DAYS)
+// Jadwal reminder per user mengikuti preferensi efektifnya (override user -> default company -> default sistem),
+// termasuk offset reminder (misal 60/30/7/0 hari), opt-in/out per tipe, dan quiet hours.
+// Scheduler berjalan setiap jam (NOTIFICATION_SCHEDULER_INTERVAL_MINUTES) agar reminder yang tertunda karena
+// quiet hours segera terkirim setelah quiet hours berakhir. Setiap tahap reminder hanya dikirim sekali.
+// Resource yang tetap expired dieskalasi ke admin company lalu ke admin parent company (lihat GetEscalationPolicy).
 func StartNotificationScheduler() {
 	zapLog := logger.GetLogger()
 	notificationUC := NewNotificationUseCase()
+	escalationUC := NewNotificationEscalationUseCase()
+	escalationPolicy := GetEscalationPolicy()
 
-	// Get default threshold from environment variable (default: 14 days)
-	// Threshold per user akan dibaca dari database saat check expiring documents
 	defaultThresholdDays := 14
 	thresholdStr := os.Getenv("NOTIFICATION_EXPIRY_THRESHOLD_DAYS")
 	if thresholdStr != "" {
@@ -104,55 +120,64 @@ func StartNotificationScheduler() {
 		}
 	}
 
-	// Note: Helper function getThresholdForUser dan settingsRepo dihapus karena tidak digunakan
-	// Jika diperlukan di masa depan untuk per-user threshold, bisa ditambahkan kembali
-
-	// Jalankan check setiap 24 jam (sekali sehari)
-	ticker := time.NewTicker(24 * time.Hour)
-	defer ticker.Stop()
+	interval := 60 * time.Minute
+	if intervalStr := os.Getenv("NOTIFICATION_SCHEDULER_INTERVAL_MINUTES"); intervalStr != "" {
+		if parsed, err := strconv.Atoi(intervalStr); err == nil && parsed > 0 {
+			interval = time.Duration(parsed) * time.Minute
+		}
+	}
 
-	// Jalankan check pertama kali setelah 5 menit (memberi waktu untuk server startup)
-	go func() {
-		time.Sleep(5 * time.Minute)
-		zapLog.Info("Starting initial notification expiry check", zap.Int("default_threshold_days", defaultThresholdDays))
+	runCheck := func(label string) {
+		health.Beat(notificationSchedulerHeartbeat)
+		zapLog.Info("Running notification expiry check",
+			zap.String("run", label),
+			zap.Int("default_threshold_days", defaultThresholdDays),
+		)
 
-		// Check expiring documents (menggunakan default threshold, threshold per user akan dibaca di dalam fungsi)
+		start := time.Now()
 		docNotifs, docFound, err := notificationUC.CheckExpiringDocuments(defaultThresholdDays)
+		observability.ObserveJob(jobExpiringDocumentsCheck, start, err)
+		observability.AddJobItems(jobExpiringDocumentsCheck, "notifications_created", docNotifs)
 		if err != nil {
-			zapLog.Error("Initial expiring documents check failed", zap.Error(err))
+			zapLog.Error("Expiring documents check failed", zap.String("run", label), zap.Error(err))
 		} else {
-			zapLog.Info("Initial expiring documents check completed", zap.Int("documents_found", docFound), zap.Int("notifications_created", docNotifs))
+			zapLog.Info("Expiring documents check completed", zap.String("run", label), zap.Int("documents_found", docFound), zap.Int("notifications_created", docNotifs))
 		}
 
-		// Check expiring director terms (menggunakan default threshold, threshold per user akan dibaca di dalam fungsi)
+		start = time.Now()
 		dirNotifs, dirFound, err := notificationUC.CheckExpiringDirectorTerms(defaultThresholdDays)
+		observability.ObserveJob(jobExpiringDirectorTermsCheck, start, err)
+		observability.AddJobItems(jobExpiringDirectorTermsCheck, "notifications_created", dirNotifs)
 		if err != nil {
-			zapLog.Error("Initial director term expiry check failed", zap.Error(err))
+			zapLog.Error("Director term expiry check failed", zap.String("run", label), zap.Error(err))
 		} else {
-			zapLog.Info("Initial expiring director terms check completed", zap.Int("directors_found", dirFound), zap.Int("notifications_created", dirNotifs))
+			zapLog.Info("Expiring director terms check completed", zap.String("run", label), zap.Int("directors_found", dirFound), zap.Int("notifications_created", dirNotifs))
 		}
-	}()
 
-	// Jalankan check secara berkala (sekali sehari)
+		// Eskalasi dijalankan setelah check expiry agar rantai yang baru dibuka ikut dievaluasi
+		start = time.Now()
+		escalated, resolved, err := escalationUC.RunEscalations(escalationPolicy)
+		observability.ObserveJob(jobNotificationEscalation, start, err)
+		observability.AddJobItems(jobNotificationEscalation, "levels_escalated", escalated)
+		observability.AddJobItems(jobNotificationEscalation, "auto_resolved", resolved)
+		if err != nil {
+			zapLog.Error("Notification escalation check failed", zap.String("run", label), zap.Error(err))
+		} else {
+			zapLog.Info("Notification escalation check completed", zap.String("run", label), zap.Int("levels_escalated", escalated), zap.Int("auto_resolved", resolved))
+		}
+	}
+
+	// Jalankan check pertama kali setelah 5 menit (memberi waktu untuk server startup), lalu berkala
+	// Catatan: ticker dibuat di dalam goroutine agar tidak berhenti saat fungsi ini return
+	health.RegisterHeartbeat(notificationSchedulerHeartbeat, interval, 5*time.Minute)
 	go func() {
-		for range ticker.C {
-			zapLog.Info("Running scheduled notification expiry check", zap.Int("default_threshold_days", defaultThresholdDays))
-
-			// Check expiring documents (menggunakan default threshold, threshold per user akan dibaca di dalam fungsi)
-			docNotifs, docFound, err := notificationUC.CheckExpiringDocuments(defaultThresholdDays)
-			if err != nil {
-				zapLog.Error("Scheduled expiring documents check failed", zap.Error(err))
-			} else {
-				zapLog.Info("Scheduled expiring documents check completed", zap.Int("documents_found", docFound), zap.Int("notifications_created", docNotifs))
-			}
+		time.Sleep(5 * time.Minute)
+		runCheck("initial")
 
-			// Check expiring director terms (menggunakan default threshold, threshold per user akan dibaca di dalam fungsi)
-			dirNotifs, dirFound, err := notificationUC.CheckExpiringDirectorTerms(defaultThresholdDays)
-			if err != nil {
-				zapLog.Error("Scheduled expiring director terms check failed", zap.Error(err))
-			} else {
-				zapLog.Info("Scheduled expiring director terms check completed", zap.Int("directors_found", dirFound), zap.Int("notifications_created", dirNotifs))
-			}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			runCheck("scheduled")
 		}
 	}()
 }
@@ -0,0 +1,331 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// NotificationTypeExpiryEscalation adalah tipe notifikasi eskalasi ke admin company.
+// Sengaja tidak termasuk KnownNotificationTypes agar tidak bisa di-opt-out oleh admin.
+const NotificationTypeExpiryEscalation = "expiry_escalation"
+
+// Default kebijakan eskalasi
+const (
+	DefaultEscalationAfterDays = 7 // Hari setelah notifikasi expired pertama sebelum eskalasi ke admin company
+)
+
+// EscalationPolicy mengatur kapan rantai eskalasi naik level
+type EscalationPolicy struct {
+	AfterDays    int // Hari setelah notifikasi expired pertama sampai level 1 (admin company asal), 0 = eskalasi nonaktif
+	IntervalDays int // Jeda hari antar level berikutnya (admin parent, grandparent, dst)
+	MaxLevels    int // Batas level, 0 = sampai holding paling atas
+}
+
+// GetEscalationPolicy membaca kebijakan eskalasi dari environment variable
+// NOTIFICATION_ESCALATION_AFTER_DAYS (default 7), NOTIFICATION_ESCALATION_INTERVAL_DAYS (default sama dengan after days),
+// NOTIFICATION_ESCALATION_MAX_LEVELS (default 0 = tanpa batas)
+func GetEscalationPolicy() EscalationPolicy {
+	policy := EscalationPolicy{AfterDays: DefaultEscalationAfterDays}
+	if parsed, err := strconv.Atoi(os.Getenv("NOTIFICATION_ESCALATION_AFTER_DAYS")); err == nil && parsed >= 0 {
+		policy.AfterDays = parsed
+	}
+	policy.IntervalDays = policy.AfterDays
+	if parsed, err := strconv.Atoi(os.Getenv("NOTIFICATION_ESCALATION_INTERVAL_DAYS")); err == nil && parsed > 0 {
+		policy.IntervalDays = parsed
+	}
+	if parsed, err := strconv.Atoi(os.Getenv("NOTIFICATION_ESCALATION_MAX_LEVELS")); err == nil && parsed >= 0 {
+		policy.MaxLevels = parsed
+	}
+	return policy
+}
+
+// Enabled mengembalikan true jika eskalasi aktif
+func (p EscalationPolicy) Enabled() bool {
+	return p.AfterDays > 0
+}
+
+// dueAt menghitung waktu level eskalasi tertentu jatuh tempo
+func (p EscalationPolicy) dueAt(firstNotifiedAt time.Time, level int) time.Time {
+	interval := p.IntervalDays
+	if interval <= 0 {
+		interval = p.AfterDays
+	}
+	return firstNotifiedAt.AddDate(0, 0, p.AfterDays+(level-1)*interval)
+}
+
+// ErrEscalationAlreadyResolved dikembalikan saat resolve rantai yang sudah ditutup
+var ErrEscalationAlreadyResolved = errors.New("escalation already resolved")
+
+// NotificationEscalationUseCase interface untuk eskalasi notifikasi expired yang tidak ditindaklanjuti
+type NotificationEscalationUseCase interface {
+	RunEscalations(policy EscalationPolicy) (escalated int, resolved int, err error)
+	ListEscalations(filter repository.NotificationEscalationFilter) ([]domain.NotificationEscalationModel, int64, error)
+	GetEscalation(id string) (*domain.NotificationEscalationModel, error)
+	Resolve(id, userID, note string) (*domain.NotificationEscalationModel, error)
+	ResolveResource(resourceType, resourceID string, resolvedBy *string, note string) (int, error)
+}
+
+type notificationEscalationUseCase struct {
+	escalationRepo repository.NotificationEscalationRepository
+	notifUC        NotificationUseCase
+	userRepo       repository.UserRepository
+	companyRepo    repository.CompanyRepository
+	docRepo        repository.DocumentRepository
+	db             *gorm.DB
+	now            func() time.Time // Sumber waktu (di-override saat testing)
+}
+
+// NewNotificationEscalationUseCase creates a new notification escalation use case
+func NewNotificationEscalationUseCase() NotificationEscalationUseCase {
+	return NewNotificationEscalationUseCaseWithDB(database.GetDB())
+}
+
+// NewNotificationEscalationUseCaseWithDB creates a new notification escalation use case with injected DB (for testing)
+func NewNotificationEscalationUseCaseWithDB(db *gorm.DB) NotificationEscalationUseCase {
+	return &notificationEscalationUseCase{
+		escalationRepo: repository.NewNotificationEscalationRepositoryWithDB(db),
+		notifUC:        NewNotificationUseCaseWithDB(db),
+		userRepo:       repository.NewUserRepositoryWithDB(db),
+		companyRepo:    repository.NewCompanyRepositoryWithDB(db),
+		docRepo:        repository.NewDocumentRepositoryWithDB(db),
+		db:             db,
+		now:            time.Now,
+	}
+}
+
+// RunEscalations menaikkan level rantai eskalasi yang sudah jatuh tempo (dipanggil oleh scheduler)
+// Rantai untuk resource yang sudah tidak expired (dokumen diperbarui/dihapus, masa jabatan diperpanjang) ditutup otomatis
+func (uc *notificationEscalationUseCase) RunEscalations(policy EscalationPolicy) (escalated int, resolved int, err error) {
+	zapLog := logger.GetLogger()
+
+	now := uc.now()
+	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	openEscalations, err := uc.escalationRepo.GetOpen()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	chains := make(map[string][]domain.CompanyModel)
+	for i := range openEscalations {
+		esc := &openEscalations[i]
+
+		stillExpired, err := uc.resourceStillExpired(esc, todayStart)
+		if err != nil {
+			zapLog.Warn("Failed to check escalation resource", zap.Error(err), zap.String("escalation_id", esc.ID))
+			continue
+		}
+		if !stillExpired {
+			if err := uc.escalationRepo.Resolve(esc.ID, nil, "Resource sudah diperbarui atau dihapus, eskalasi ditutup otomatis", now); err != nil {
+				zapLog.Error("Failed to auto-resolve escalation", zap.Error(err), zap.String("escalation_id", esc.ID))
+				continue
+			}
+			resolved++
+			continue
+		}
+
+		if !policy.Enabled() || esc.CompanyID == nil {
+			continue
+		}
+		chain, ok := chains[*esc.CompanyID]
+		if !ok {
+			chain, err = uc.companyChain(*esc.CompanyID)
+			if err != nil {
+				zapLog.Warn("Failed to build company chain for escalation", zap.Error(err), zap.String("escalation_id", esc.ID))
+				continue
+			}
+			chains[*esc.CompanyID] = chain
+		}
+
+		// Company tanpa admin aktif dilewati langsung ke level berikutnya (jika sudah jatuh tempo),
+		// selain itu maksimal satu level per run agar admin punya waktu menindaklanjuti
+		for {
+			next := esc.CurrentLevel + 1
+			if next > len(chain) || (policy.MaxLevels > 0 && next > policy.MaxLevels) {
+				break
+			}
+			if now.Before(policy.dueAt(esc.FirstNotifiedAt, next)) {
+				break
+			}
+
+			recipients, err := uc.escalate(esc, next, chain[next-1], chain[0], todayStart, now)
+			if err != nil {
+				zapLog.Error("Failed to escalate notification", zap.Error(err),
+					zap.String("escalation_id", esc.ID),
+					zap.Int("level", next))
+				break
+			}
+			escalated++
+			if recipients > 0 {
+				break
+			}
+		}
+	}
+
+	zapLog.Info("Notification escalation check completed",
+		zap.Int("open_escalations", len(openEscalations)),
+		zap.Int("levels_escalated", escalated),
+		zap.Int("auto_resolved", resolved),
+		zap.Int("after_days", policy.AfterDays),
+		zap.Int("interval_days", policy.IntervalDays),
+	)
+
+	return escalated, resolved, nil
+}
+
+// escalate mengirim notifikasi ke admin company target lalu mencatat levelnya
+func (uc *notificationEscalationUseCase) escalate(esc *domain.NotificationEscalationModel, level int, target, origin domain.CompanyModel, todayStart, now time.Time) (int, error) {
+	admins, err := uc.userRepo.GetActiveAdminsByCompanyID(target.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	dueDate, _ := time.ParseInLocation("2006-01-02", esc.DueDate, todayStart.Location())
+	daysOverdue := int(todayStart.Sub(dueDate).Hours() / 24)
+
+	var title, message string
+	if esc.ResourceType == "director" {
+		title = fmt.Sprintf("Eskalasi Level %d: Masa Jabatan '%s' Sudah Berakhir", level, esc.ResourceName)
+		message = fmt.Sprintf("Masa jabatan %s di %s sudah berakhir sejak %s (%d hari) dan belum ditindaklanjuti. Mohon pastikan pengurus diperpanjang atau diganti.",
+			esc.ResourceName, origin.Name, esc.DueDate, daysOverdue)
+	} else {
+		title = fmt.Sprintf("Eskalasi Level %d: Dokumen '%s' Sudah Expired", level, esc.ResourceName)
+		message = fmt.Sprintf("Dokumen '%s' milik %s sudah expired sejak %s (%d hari) dan belum ditindaklanjuti. Mohon pastikan dokumen diperbarui.",
+			esc.ResourceName, origin.Name, esc.DueDate, daysOverdue)
+	}
+
+	recipientIDs := make([]string, 0, len(admins))
+	for _, admin := range admins {
+		resourceID := esc.ResourceID
+		if _, err := uc.notifUC.CreateNotification(admin.ID, NotificationTypeExpiryEscalation, title, message, esc.ResourceType, &resourceID); err != nil {
+			logger.GetLogger().Warn("Failed to create escalation notification", zap.Error(err),
+				zap.String("escalation_id", esc.ID),
+				zap.String("user_id", admin.ID))
+			continue
+		}
+		recipientIDs = append(recipientIDs, admin.ID)
+	}
+
+	recipientJSON, _ := json.Marshal(recipientIDs)
+	err = uc.escalationRepo.RecordLevel(esc, &domain.NotificationEscalationLevelModel{
+		Level:          level,
+		CompanyID:      target.ID,
+		CompanyName:    target.Name,
+		RecipientIDs:   datatypes.JSON(recipientJSON),
+		RecipientCount: len(recipientIDs),
+		EscalatedAt:    now,
+	})
+	return len(recipientIDs), err
+}
+
+// resourceStillExpired mengecek apakah dokumen/masa jabatan masih expired dan belum diperbarui
+func (uc *notificationEscalationUseCase) resourceStillExpired(esc *domain.NotificationEscalationModel, todayStart time.Time) (bool, error) {
+	var expiry *time.Time
+	switch esc.ResourceType {
+	case "document":
+		doc, err := uc.docRepo.GetDocumentByID(esc.ResourceID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return false, nil
+			}
+			return false, err
+		}
+		expiry = parseDocumentExpiryDate(doc.Metadata)
+	case "director":
+		var director domain.DirectorModel
+		if err := uc.db.Where("id = ?", esc.ResourceID).First(&director).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return false, nil
+			}
+			return false, err
+		}
+		expiry = director.EndDate
+	default:
+		return false, nil
+	}
+
+	if expiry == nil {
+		return false, nil
+	}
+	expiryDate := time.Date(expiry.Year(), expiry.Month(), expiry.Day(), 0, 0, 0, 0, todayStart.Location())
+	return expiryDate.Before(todayStart), nil
+}
+
+// companyChain mengembalikan company asal diikuti parent, grandparent, dst (urutan level eskalasi)
+func (uc *notificationEscalationUseCase) companyChain(companyID string) ([]domain.CompanyModel, error) {
+	company, err := uc.companyRepo.GetByID(companyID)
+	if err != nil {
+		return nil, err
+	}
+	ancestors, err := uc.companyRepo.GetAncestors(companyID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]domain.CompanyModel, len(ancestors))
+	for _, a := range ancestors {
+		byID[a.ID] = a
+	}
+
+	chain := []domain.CompanyModel{*company}
+	parentID := company.ParentID
+	for parentID != nil && len(chain) <= len(ancestors) {
+		parent, ok := byID[*parentID]
+		if !ok {
+			break
+		}
+		chain = append(chain, parent)
+		parentID = parent.ParentID
+	}
+	return chain, nil
+}
+
+func (uc *notificationEscalationUseCase) ListEscalations(filter repository.NotificationEscalationFilter) ([]domain.NotificationEscalationModel, int64, error) {
+	return uc.escalationRepo.List(filter)
+}
+
+func (uc *notificationEscalationUseCase) GetEscalation(id string) (*domain.NotificationEscalationModel, error) {
+	return uc.escalationRepo.GetByID(id)
+}
+
+// Resolve menutup rantai eskalasi secara manual (misal setelah tindak lanjut di luar sistem)
+func (uc *notificationEscalationUseCase) Resolve(id, userID, note string) (*domain.NotificationEscalationModel, error) {
+	esc, err := uc.escalationRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if esc.Status == domain.EscalationStatusResolved {
+		return nil, ErrEscalationAlreadyResolved
+	}
+
+	if err := uc.escalationRepo.Resolve(id, &userID, note, uc.now()); err != nil {
+		return nil, err
+	}
+	return uc.escalationRepo.GetByID(id)
+}
+
+// ResolveResource menutup semua rantai eskalasi yang masih terbuka untuk sebuah resource (misal dokumen diperbarui)
+func (uc *notificationEscalationUseCase) ResolveResource(resourceType, resourceID string, resolvedBy *string, note string) (int, error) {
+	openEscalations, err := uc.escalationRepo.GetOpenByResource(resourceType, resourceID)
+	if err != nil {
+		return 0, err
+	}
+	now := uc.now()
+	for _, esc := range openEscalations {
+		if err := uc.escalationRepo.Resolve(esc.ID, resolvedBy, note, now); err != nil {
+			return 0, err
+		}
+	}
+	return len(openEscalations), nil
+}
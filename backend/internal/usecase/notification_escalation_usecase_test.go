@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"github.com/repoareta/pedeve-dms-app/backend/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// TestNotificationEscalationUseCase_RunEscalations tests escalation up the company chain and resolution
+func TestNotificationEscalationUseCase_RunEscalations(t *testing.T) {
+	notifUC, db, now := setupTestNotificationUseCase(t)
+	defer helpers.CleanupTestDB(t, db)
+
+	escUC := NewNotificationEscalationUseCaseWithDB(db).(*notificationEscalationUseCase)
+	escUC.now = func() time.Time { return *now }
+	policy := EscalationPolicy{AfterDays: 3, IntervalDays: 2}
+
+	adminRole := &domain.RoleModel{ID: uuid.GenerateUUID(), Name: "admin", Level: 1}
+	require.NoError(t, db.Create(adminRole).Error)
+
+	holding := createTestCompanyForNotification(t, db, nil)
+	subsidiary := createTestCompanyForNotification(t, db, &holding.ID)
+	holdingAdmin := createTestUserForNotification(t, db, &holding.ID)
+	subsidiaryAdmin := createTestUserForNotification(t, db, &subsidiary.ID)
+	staff := createTestUserForNotification(t, db, &subsidiary.ID)
+	require.NoError(t, db.Model(holdingAdmin).Update("role_id", adminRole.ID).Error)
+	require.NoError(t, db.Model(subsidiaryAdmin).Update("role_id", adminRole.ID).Error)
+
+	expiredDoc := createTestDocumentForNotification(t, db, staff.ID, now.AddDate(0, 0, -2))
+	renewedDoc := createTestDocumentForNotification(t, db, staff.ID, now.AddDate(0, 0, -1))
+
+	_, _, err := notifUC.CheckExpiringDocuments(14)
+	require.NoError(t, err)
+
+	var escalation domain.NotificationEscalationModel
+	require.NoError(t, db.Where("resource_id = ?", expiredDoc.ID).First(&escalation).Error)
+	assert.Equal(t, domain.EscalationStatusOpen, escalation.Status)
+	require.NotNil(t, escalation.CompanyID)
+	assert.Equal(t, subsidiary.ID, *escalation.CompanyID)
+
+	t.Run("No escalation before the policy delay", func(t *testing.T) {
+		escalated, _, err := escUC.RunEscalations(policy)
+		require.NoError(t, err)
+		assert.Equal(t, 0, escalated)
+	})
+
+	t.Run("Level 1 notifies the company admin", func(t *testing.T) {
+		*now = now.AddDate(0, 0, 3)
+		_, _, err := escUC.RunEscalations(policy)
+		require.NoError(t, err)
+
+		assertEscalationNotificationCount(t, db, subsidiaryAdmin.ID, expiredDoc.ID, 1)
+		assertEscalationNotificationCount(t, db, holdingAdmin.ID, expiredDoc.ID, 0)
+		assertEscalationNotificationCount(t, db, staff.ID, expiredDoc.ID, 0)
+	})
+
+	t.Run("Level 2 notifies the parent company admin", func(t *testing.T) {
+		*now = now.AddDate(0, 0, 2)
+		_, _, err := escUC.RunEscalations(policy)
+		require.NoError(t, err)
+		assertEscalationNotificationCount(t, db, holdingAdmin.ID, expiredDoc.ID, 1)
+
+		// Holding adalah puncak rantai, tidak ada level berikutnya
+		*now = now.AddDate(0, 0, 10)
+		_, _, err = escUC.RunEscalations(policy)
+		require.NoError(t, err)
+
+		loaded, err := escUC.GetEscalation(escalation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, loaded.CurrentLevel)
+		require.Len(t, loaded.Levels, 2)
+		assert.Equal(t, subsidiary.ID, loaded.Levels[0].CompanyID)
+		assert.Equal(t, holding.ID, loaded.Levels[1].CompanyID)
+		assert.Equal(t, 1, loaded.Levels[1].RecipientCount)
+	})
+
+	t.Run("Resolve closes the chain", func(t *testing.T) {
+		resolved, err := escUC.Resolve(escalation.ID, holdingAdmin.ID, "Sudah ditindaklanjuti")
+		require.NoError(t, err)
+		assert.Equal(t, domain.EscalationStatusResolved, resolved.Status)
+		require.NotNil(t, resolved.ResolvedBy)
+		assert.Equal(t, holdingAdmin.ID, *resolved.ResolvedBy)
+
+		_, err = escUC.Resolve(escalation.ID, holdingAdmin.ID, "")
+		assert.ErrorIs(t, err, ErrEscalationAlreadyResolved)
+	})
+
+	t.Run("Renewed document resolves its chain automatically", func(t *testing.T) {
+		docUC := NewDocumentUseCaseWithDB(db)
+		_, err := docUC.UpdateDocument(renewedDoc.ID, UpdateDocumentInput{
+			Metadata:  map[string]interface{}{"expired_date": time.Now().AddDate(1, 0, 0).Format("2006-01-02")},
+			UpdatedBy: staff.ID,
+		})
+		require.NoError(t, err)
+
+		var renewed domain.NotificationEscalationModel
+		require.NoError(t, db.Where("resource_id = ?", renewedDoc.ID).First(&renewed).Error)
+		assert.Equal(t, domain.EscalationStatusResolved, renewed.Status)
+		require.NotNil(t, renewed.ResolvedBy)
+		assert.Equal(t, staff.ID, *renewed.ResolvedBy)
+	})
+
+	openEscalation := func(doc *domain.DocumentModel) *domain.NotificationEscalationModel {
+		esc := &domain.NotificationEscalationModel{
+			ID:              uuid.GenerateUUID(),
+			ResourceType:    "document",
+			ResourceID:      doc.ID,
+			DueDate:         now.Format("2006-01-02"),
+			ResourceName:    doc.Name,
+			FirstNotifiedAt: *now,
+			Status:          domain.EscalationStatusOpen,
+		}
+		require.NoError(t, db.Create(esc).Error)
+		return esc
+	}
+	escalationStatus := func(id string) string {
+		var esc domain.NotificationEscalationModel
+		require.NoError(t, db.First(&esc, "id = ?", id).Error)
+		return esc.Status
+	}
+
+	t.Run("Clearing the expiry date keeps the chain open", func(t *testing.T) {
+		doc := createTestDocumentForNotification(t, db, staff.ID, time.Now().AddDate(0, 0, -5))
+		esc := openEscalation(doc)
+
+		_, err := NewDocumentUseCaseWithDB(db).UpdateDocument(doc.ID, UpdateDocumentInput{
+			Metadata:  map[string]interface{}{"expired_date": ""},
+			UpdatedBy: staff.ID,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, domain.EscalationStatusOpen, escalationStatus(esc.ID))
+	})
+
+	t.Run("Replacing the file of a renewed document resolves its chain", func(t *testing.T) {
+		t.Setenv("UPLOAD_BASE_PATH", t.TempDir())
+		doc := createTestDocumentForNotification(t, db, staff.ID, time.Now().AddDate(1, 0, 0))
+		esc := openEscalation(doc)
+
+		fileName := "perpanjangan.pdf"
+		_, err := NewDocumentUseCaseWithDB(db).UpdateDocument(doc.ID, UpdateDocumentInput{
+			FileName:  &fileName,
+			FileData:  []byte("%PDF-1.4"),
+			UpdatedBy: staff.ID,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, domain.EscalationStatusResolved, escalationStatus(esc.ID))
+	})
+}
+
+func assertEscalationNotificationCount(t *testing.T, db *gorm.DB, userID, resourceID string, expected int64) {
+	t.Helper()
+	var count int64
+	require.NoError(t, db.Model(&domain.NotificationModel{}).
+		Where("user_id = ? AND resource_id = ? AND type = ?", userID, resourceID, NotificationTypeExpiryEscalation).
+		Count(&count).Error)
+	assert.Equal(t, expected, count)
+}
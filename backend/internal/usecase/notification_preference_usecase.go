@@ -0,0 +1,511 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Tipe notifikasi yang dibuat oleh scheduler (bisa di-opt-in/out per user atau per company)
+const (
+	NotificationTypeDocumentExpiry     = "document_expiry"
+	NotificationTypeDirectorTermExpiry = "director_term_expiry"
+)
+
+// Scope preferensi notifikasi
+const (
+	PreferenceScopeCompany = "company"
+	PreferenceScopeUser    = "user"
+)
+
+const (
+	// ReminderStageExpired adalah tahap reminder untuk item yang sudah lewat tanggal expired
+	ReminderStageExpired = -1
+
+	defaultNotificationTimezone = "Asia/Jakarta"
+	defaultThresholdDays        = 14 // Default sistem jika scheduler tidak memberikan threshold
+	maxReminderOffsets          = 10
+	maxReminderOffsetDays       = 365
+)
+
+// KnownNotificationTypes adalah daftar tipe notifikasi yang bisa diatur preferensinya
+var KnownNotificationTypes = []string{
+	NotificationTypeDocumentExpiry,
+	NotificationTypeDirectorTermExpiry,
+}
+
+// NotificationPreferenceUseCase interface untuk preferensi notifikasi (default company & override user)
+type NotificationPreferenceUseCase interface {
+	GetUserPreferences(userID string) (*domain.NotificationPreferenceModel, error)
+	UpdateUserPreferences(userID string, req *domain.NotificationPreferenceRequest) (*domain.NotificationPreferenceModel, error)
+	GetCompanyPreferences(companyID string) (*domain.NotificationPreferenceModel, error)
+	UpdateCompanyPreferences(companyID string, req *domain.NotificationPreferenceRequest, updatedBy string) (*domain.NotificationPreferenceModel, error)
+	ResolveForUser(userID string, systemThresholdDays int) (*domain.EffectiveNotificationPreferences, error)
+}
+
+type notificationPreferenceUseCase struct {
+	prefRepo     repository.NotificationPreferenceRepository
+	settingsRepo repository.NotificationSettingsRepository
+	userRepo     repository.UserRepository
+	companyRepo  repository.CompanyRepository
+}
+
+// NewNotificationPreferenceUseCase membuat notification preference use case baru
+func NewNotificationPreferenceUseCase() NotificationPreferenceUseCase {
+	return NewNotificationPreferenceUseCaseWithDB(database.GetDB())
+}
+
+// NewNotificationPreferenceUseCaseWithDB membuat notification preference use case dengan DB yang di-inject (untuk testing)
+func NewNotificationPreferenceUseCaseWithDB(db *gorm.DB) NotificationPreferenceUseCase {
+	return &notificationPreferenceUseCase{
+		prefRepo:     repository.NewNotificationPreferenceRepositoryWithDB(db),
+		settingsRepo: repository.NewNotificationSettingsRepositoryWithDB(db),
+		userRepo:     repository.NewUserRepositoryWithDB(db),
+		companyRepo:  repository.NewCompanyRepositoryWithDB(db),
+	}
+}
+
+// GetUserPreferences mengembalikan override milik user (field nil = mewarisi company/sistem)
+func (uc *notificationPreferenceUseCase) GetUserPreferences(userID string) (*domain.NotificationPreferenceModel, error) {
+	return uc.getOrEmpty(PreferenceScopeUser, userID)
+}
+
+func (uc *notificationPreferenceUseCase) UpdateUserPreferences(userID string, req *domain.NotificationPreferenceRequest) (*domain.NotificationPreferenceModel, error) {
+	pref, err := uc.getOrEmpty(PreferenceScopeUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyPreferenceRequest(pref, req); err != nil {
+		return nil, err
+	}
+	pref.UpdatedBy = userID
+	if err := uc.prefRepo.Save(pref); err != nil {
+		return nil, fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+	return pref, nil
+}
+
+// GetCompanyPreferences mengembalikan default notifikasi company (diwarisi user di company tersebut dan anak-anaknya)
+func (uc *notificationPreferenceUseCase) GetCompanyPreferences(companyID string) (*domain.NotificationPreferenceModel, error) {
+	return uc.getOrEmpty(PreferenceScopeCompany, companyID)
+}
+
+func (uc *notificationPreferenceUseCase) UpdateCompanyPreferences(companyID string, req *domain.NotificationPreferenceRequest, updatedBy string) (*domain.NotificationPreferenceModel, error) {
+	if _, err := uc.companyRepo.GetByID(companyID); err != nil {
+		return nil, fmt.Errorf("company not found")
+	}
+	pref, err := uc.getOrEmpty(PreferenceScopeCompany, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyPreferenceRequest(pref, req); err != nil {
+		return nil, err
+	}
+	pref.UpdatedBy = updatedBy
+	if err := uc.prefRepo.Save(pref); err != nil {
+		return nil, fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+	return pref, nil
+}
+
+// ResolveForUser menghitung preferensi efektif seorang user dengan urutan prioritas (rendah ke tinggi):
+// default sistem -> company paling atas ... company user sendiri -> notification_settings lama -> override user.
+func (uc *notificationPreferenceUseCase) ResolveForUser(userID string, systemThresholdDays int) (*domain.EffectiveNotificationPreferences, error) {
+	if systemThresholdDays <= 0 {
+		systemThresholdDays = defaultThresholdDays
+	}
+
+	eff := &domain.EffectiveNotificationPreferences{
+		UserID:          userID,
+		InAppEnabled:    true,
+		ThresholdDays:   systemThresholdDays,
+		ReminderOffsets: []int{systemThresholdDays},
+		TypeEnabled:     make(map[string]bool),
+		Timezone:        defaultNotificationTimezone,
+		Sources:         make(map[string]string),
+	}
+	for _, t := range KnownNotificationTypes {
+		eff.TypeEnabled[t] = true
+	}
+	for _, field := range []string{"in_app_enabled", "threshold_days", "reminder_offsets", "type_preferences", "quiet_hours", "timezone"} {
+		eff.Sources[field] = "system"
+	}
+
+	user, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Default company: dari company paling atas ke company user sendiri (yang lebih dekat menimpa)
+	if user.CompanyID != nil && *user.CompanyID != "" {
+		chain, err := uc.companyChain(*user.CompanyID)
+		if err != nil {
+			return nil, err
+		}
+		prefs, err := uc.prefRepo.GetByScopes(PreferenceScopeCompany, chain)
+		if err != nil {
+			return nil, err
+		}
+		byCompany := make(map[string]domain.NotificationPreferenceModel, len(prefs))
+		for _, p := range prefs {
+			byCompany[p.ScopeID] = p
+		}
+		for i := len(chain) - 1; i >= 0; i-- {
+			if p, ok := byCompany[chain[i]]; ok {
+				applyPreferenceLayer(eff, &p, "company:"+chain[i])
+			}
+		}
+	}
+
+	// Kompatibilitas notification_settings lama: threshold nil berarti belum pernah diatur user
+	if settings, err := uc.settingsRepo.GetByUserID(userID); err == nil {
+		legacy := &domain.NotificationPreferenceModel{}
+		if !settings.InAppEnabled {
+			disabled := false
+			legacy.InAppEnabled = &disabled
+		}
+		if settings.ExpiryThresholdDays != nil && *settings.ExpiryThresholdDays > 0 {
+			threshold := *settings.ExpiryThresholdDays
+			legacy.ThresholdDays = &threshold
+		}
+		applyPreferenceLayer(eff, legacy, "user")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if userPref, err := uc.prefRepo.GetByScope(PreferenceScopeUser, userID); err == nil {
+		applyPreferenceLayer(eff, userPref, "user")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return eff, nil
+}
+
+// companyChain mengembalikan ID company user diikuti parent, grandparent, dst (terdekat lebih dulu)
+func (uc *notificationPreferenceUseCase) companyChain(companyID string) ([]string, error) {
+	company, err := uc.companyRepo.GetByID(companyID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return []string{companyID}, nil
+		}
+		return nil, err
+	}
+	ancestors, err := uc.companyRepo.GetAncestors(companyID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]domain.CompanyModel, len(ancestors))
+	for _, a := range ancestors {
+		byID[a.ID] = a
+	}
+
+	chain := []string{company.ID}
+	parentID := company.ParentID
+	for parentID != nil && len(chain) <= len(ancestors) {
+		parent, ok := byID[*parentID]
+		if !ok {
+			break
+		}
+		chain = append(chain, parent.ID)
+		parentID = parent.ParentID
+	}
+	return chain, nil
+}
+
+func (uc *notificationPreferenceUseCase) getOrEmpty(scopeType, scopeID string) (*domain.NotificationPreferenceModel, error) {
+	pref, err := uc.prefRepo.GetByScope(scopeType, scopeID)
+	if err == nil {
+		return pref, nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &domain.NotificationPreferenceModel{ScopeType: scopeType, ScopeID: scopeID}, nil
+	}
+	return nil, err
+}
+
+// applyPreferenceLayer menimpa preferensi efektif dengan field yang diisi pada satu layer
+func applyPreferenceLayer(eff *domain.EffectiveNotificationPreferences, pref *domain.NotificationPreferenceModel, source string) {
+	if pref.InAppEnabled != nil {
+		eff.InAppEnabled = *pref.InAppEnabled
+		eff.Sources["in_app_enabled"] = source
+	}
+	// Threshold dan reminder offsets adalah satu jadwal: layer yang lebih spesifik menentukan jadwal secara utuh
+	if pref.ThresholdDays != nil {
+		eff.ThresholdDays = *pref.ThresholdDays
+		eff.Sources["threshold_days"] = source
+		if pref.ReminderOffsets == nil {
+			eff.ReminderOffsets = []int{*pref.ThresholdDays}
+			eff.Sources["reminder_offsets"] = source
+		}
+	}
+	if pref.ReminderOffsets != nil {
+		if offsets := parseReminderOffsets(*pref.ReminderOffsets); len(offsets) > 0 {
+			eff.ReminderOffsets = offsets
+			eff.Sources["reminder_offsets"] = source
+		}
+	}
+	if len(pref.TypePreferences) > 0 {
+		var types map[string]bool
+		if err := json.Unmarshal(pref.TypePreferences, &types); err == nil && len(types) > 0 {
+			for t, enabled := range types {
+				eff.TypeEnabled[t] = enabled
+			}
+			eff.Sources["type_preferences"] = source
+		}
+	}
+	if pref.QuietHoursStart != nil && pref.QuietHoursEnd != nil {
+		eff.QuietHoursStart = *pref.QuietHoursStart
+		eff.QuietHoursEnd = *pref.QuietHoursEnd
+		eff.Sources["quiet_hours"] = source
+	}
+	if pref.Timezone != nil && *pref.Timezone != "" {
+		eff.Timezone = *pref.Timezone
+		eff.Sources["timezone"] = source
+	}
+}
+
+// applyPreferenceRequest memvalidasi request lalu menerapkannya ke model preferensi
+func applyPreferenceRequest(pref *domain.NotificationPreferenceModel, req *domain.NotificationPreferenceRequest) error {
+	for _, field := range req.ResetFields {
+		switch field {
+		case "in_app_enabled":
+			pref.InAppEnabled = nil
+		case "threshold_days":
+			pref.ThresholdDays = nil
+		case "reminder_offsets":
+			pref.ReminderOffsets = nil
+		case "type_preferences":
+			pref.TypePreferences = nil
+		case "quiet_hours":
+			pref.QuietHoursStart = nil
+			pref.QuietHoursEnd = nil
+		case "timezone":
+			pref.Timezone = nil
+		default:
+			return fmt.Errorf("unknown reset field: %s", field)
+		}
+	}
+
+	if req.InAppEnabled != nil {
+		pref.InAppEnabled = req.InAppEnabled
+	}
+
+	if req.ThresholdDays != nil {
+		if *req.ThresholdDays < 1 || *req.ThresholdDays > maxReminderOffsetDays {
+			return fmt.Errorf("threshold_days must be between 1 and %d days", maxReminderOffsetDays)
+		}
+		threshold := *req.ThresholdDays
+		pref.ThresholdDays = &threshold
+	}
+
+	if req.ReminderOffsets != nil {
+		if len(req.ReminderOffsets) == 0 || len(req.ReminderOffsets) > maxReminderOffsets {
+			return fmt.Errorf("reminder_offsets must contain 1 to %d values", maxReminderOffsets)
+		}
+		for _, offset := range req.ReminderOffsets {
+			if offset < 0 || offset > maxReminderOffsetDays {
+				return fmt.Errorf("reminder_offsets values must be between 0 and %d days", maxReminderOffsetDays)
+			}
+		}
+		offsets := formatReminderOffsets(req.ReminderOffsets)
+		pref.ReminderOffsets = &offsets
+	}
+
+	if req.TypePreferences != nil {
+		merged := make(map[string]bool)
+		if len(pref.TypePreferences) > 0 {
+			_ = json.Unmarshal(pref.TypePreferences, &merged)
+		}
+		for t, enabled := range req.TypePreferences {
+			if !isKnownNotificationType(t) {
+				return fmt.Errorf("unknown notification type: %s", t)
+			}
+			merged[t] = enabled
+		}
+		data, err := json.Marshal(merged)
+		if err != nil {
+			return err
+		}
+		pref.TypePreferences = datatypes.JSON(data)
+	}
+
+	if req.QuietHoursStart != nil || req.QuietHoursEnd != nil {
+		start, end := pref.QuietHoursStart, pref.QuietHoursEnd
+		if req.QuietHoursStart != nil {
+			start = req.QuietHoursStart
+		}
+		if req.QuietHoursEnd != nil {
+			end = req.QuietHoursEnd
+		}
+		if start == nil || end == nil {
+			return fmt.Errorf("quiet_hours_start and quiet_hours_end must be set together")
+		}
+		if _, err := parseClock(*start); err != nil {
+			return fmt.Errorf("quiet_hours_start must use HH:MM format")
+		}
+		if _, err := parseClock(*end); err != nil {
+			return fmt.Errorf("quiet_hours_end must use HH:MM format")
+		}
+		pref.QuietHoursStart = start
+		pref.QuietHoursEnd = end
+	}
+
+	if req.Timezone != nil {
+		if _, err := time.LoadLocation(*req.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone: %s", *req.Timezone)
+		}
+		tz := *req.Timezone
+		pref.Timezone = &tz
+	}
+
+	return nil
+}
+
+func isKnownNotificationType(t string) bool {
+	for _, known := range KnownNotificationTypes {
+		if known == t {
+			return true
+		}
+	}
+	return false
+}
+
+// parseReminderOffsets mengubah "60,30,7,0" menjadi []int unik urut menurun
+func parseReminderOffsets(value string) []int {
+	seen := make(map[int]bool)
+	offsets := []int{}
+	for _, part := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 0 || seen[n] {
+			continue
+		}
+		seen[n] = true
+		offsets = append(offsets, n)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(offsets)))
+	return offsets
+}
+
+// formatReminderOffsets menyimpan offsets sebagai string unik urut menurun, contoh: "60,30,7,0"
+func formatReminderOffsets(offsets []int) string {
+	seen := make(map[int]bool)
+	unique := make([]int, 0, len(offsets))
+	for _, o := range offsets {
+		if !seen[o] {
+			seen[o] = true
+			unique = append(unique, o)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(unique)))
+	parts := make([]string, 0, len(unique))
+	for _, o := range unique {
+		parts = append(parts, strconv.Itoa(o))
+	}
+	return strings.Join(parts, ",")
+}
+
+// reminderStage menentukan tahap reminder untuk item yang expired dalam daysUntil hari.
+// Tahap = offset terkecil yang sudah terlewati (misal offsets 60/30/7/0 dan sisa 5 hari -> tahap 7).
+// Item yang sudah lewat tanggal expired selalu masuk tahap ReminderStageExpired.
+func reminderStage(prefs *domain.EffectiveNotificationPreferences, daysUntil int) (int, bool) {
+	if daysUntil < 0 {
+		return ReminderStageExpired, true
+	}
+	stage, found := 0, false
+	for _, offset := range prefs.ReminderOffsets {
+		if daysUntil <= offset {
+			stage, found = offset, true
+		}
+	}
+	return stage, found
+}
+
+// notificationAllowed mengecek master switch in-app dan opt-in/out per tipe notifikasi
+func notificationAllowed(prefs *domain.EffectiveNotificationPreferences, notificationType string) bool {
+	if !prefs.InAppEnabled {
+		return false
+	}
+	enabled, ok := prefs.TypeEnabled[notificationType]
+	return !ok || enabled
+}
+
+// inQuietHours mengecek apakah waktu now (di timezone user) berada dalam quiet hours user
+func inQuietHours(prefs *domain.EffectiveNotificationPreferences, now time.Time) bool {
+	if prefs.QuietHoursStart == "" || prefs.QuietHoursEnd == "" {
+		return false
+	}
+	start, err := parseClock(prefs.QuietHoursStart)
+	if err != nil {
+		return false
+	}
+	end, err := parseClock(prefs.QuietHoursEnd)
+	if err != nil || start == end {
+		return false
+	}
+
+	local := now.In(preferenceLocation(prefs.Timezone))
+	minutes := local.Hour()*60 + local.Minute()
+	if start < end {
+		return minutes >= start && minutes < end
+	}
+	// Quiet hours melewati tengah malam, contoh 22:00-06:00
+	return minutes >= start || minutes < end
+}
+
+// parseClock mengubah "HH:MM" menjadi menit sejak tengah malam
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// preferenceLocation memuat timezone preferensi, fallback ke WIB jika tzdata tidak tersedia
+func preferenceLocation(name string) *time.Location {
+	if name == "" {
+		name = defaultNotificationTimezone
+	}
+	if loc, err := time.LoadLocation(name); err == nil {
+		return loc
+	}
+	return time.FixedZone("WIB", 7*60*60)
+}
+
+// preferenceResolver meng-cache preferensi efektif per user selama satu kali run scheduler
+type preferenceResolver struct {
+	prefUC              NotificationPreferenceUseCase
+	systemThresholdDays int
+	cache               map[string]*domain.EffectiveNotificationPreferences
+}
+
+func newPreferenceResolver(prefUC NotificationPreferenceUseCase, systemThresholdDays int) *preferenceResolver {
+	return &preferenceResolver{
+		prefUC:              prefUC,
+		systemThresholdDays: systemThresholdDays,
+		cache:               make(map[string]*domain.EffectiveNotificationPreferences),
+	}
+}
+
+func (r *preferenceResolver) forUser(userID string) (*domain.EffectiveNotificationPreferences, error) {
+	if prefs, ok := r.cache[userID]; ok {
+		return prefs, nil
+	}
+	prefs, err := r.prefUC.ResolveForUser(userID, r.systemThresholdDays)
+	if err != nil {
+		return nil, err
+	}
+	r.cache[userID] = prefs
+	return prefs, nil
+}
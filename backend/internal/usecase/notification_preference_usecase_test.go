@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"github.com/repoareta/pedeve-dms-app/backend/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNotificationPreferenceUseCase_ResolveForUser tests urutan prioritas sistem -> company induk -> company user -> settings lama -> user
+func TestNotificationPreferenceUseCase_ResolveForUser(t *testing.T) {
+	db := helpers.SetupTestDB(t)
+	defer helpers.CleanupTestDB(t, db)
+	require.NoError(t, db.AutoMigrate(&domain.NotificationPreferenceModel{}))
+	prefUC := NewNotificationPreferenceUseCaseWithDB(db)
+
+	holding := createTestCompanyForNotification(t, db, nil)
+	subsidiary := createTestCompanyForNotification(t, db, &holding.ID)
+	user := createTestUserForNotification(t, db, &subsidiary.ID)
+
+	t.Run("System defaults without any preference", func(t *testing.T) {
+		eff, err := prefUC.ResolveForUser(user.ID, 21)
+		require.NoError(t, err)
+		assert.True(t, eff.InAppEnabled)
+		assert.Equal(t, 21, eff.ThresholdDays)
+		assert.Equal(t, []int{21}, eff.ReminderOffsets)
+		assert.Equal(t, defaultNotificationTimezone, eff.Timezone)
+		assert.Equal(t, "system", eff.Sources["reminder_offsets"])
+	})
+
+	quietStart, quietEnd := "22:00", "06:00"
+	_, err := prefUC.UpdateCompanyPreferences(holding.ID, &domain.NotificationPreferenceRequest{
+		ReminderOffsets: []int{0, 60, 30, 30, 7},
+		QuietHoursStart: &quietStart,
+		QuietHoursEnd:   &quietEnd,
+	}, "admin")
+	require.NoError(t, err)
+	disabled := false
+	_, err = prefUC.UpdateCompanyPreferences(subsidiary.ID, &domain.NotificationPreferenceRequest{
+		TypePreferences: map[string]bool{NotificationTypeDirectorTermExpiry: disabled},
+	}, "admin")
+	require.NoError(t, err)
+
+	t.Run("Company defaults are inherited, nearest company wins", func(t *testing.T) {
+		eff, err := prefUC.ResolveForUser(user.ID, 14)
+		require.NoError(t, err)
+		assert.Equal(t, []int{60, 30, 7, 0}, eff.ReminderOffsets)
+		assert.Equal(t, "company:"+holding.ID, eff.Sources["reminder_offsets"])
+		assert.Equal(t, "22:00", eff.QuietHoursStart)
+		assert.False(t, eff.TypeEnabled[NotificationTypeDirectorTermExpiry])
+		assert.True(t, eff.TypeEnabled[NotificationTypeDocumentExpiry])
+		assert.Equal(t, "company:"+subsidiary.ID, eff.Sources["type_preferences"])
+	})
+
+	t.Run("Legacy settings only override values set by the user", func(t *testing.T) {
+		settings := &domain.NotificationSettingsModel{ID: uuid.GenerateUUID(), UserID: user.ID, InAppEnabled: true}
+		require.NoError(t, db.Create(settings).Error)
+
+		eff, err := prefUC.ResolveForUser(user.ID, 14)
+		require.NoError(t, err)
+		assert.Equal(t, []int{60, 30, 7, 0}, eff.ReminderOffsets)
+
+		// 14 yang dipilih user tetap override walau sama dengan default sistem
+		require.NoError(t, db.Model(settings).Update("expiry_threshold_days", 14).Error)
+		eff, err = prefUC.ResolveForUser(user.ID, 14)
+		require.NoError(t, err)
+		assert.Equal(t, []int{14}, eff.ReminderOffsets)
+		assert.Equal(t, "user", eff.Sources["reminder_offsets"])
+
+		require.NoError(t, db.Model(settings).Update("expiry_threshold_days", 45).Error)
+		eff, err = prefUC.ResolveForUser(user.ID, 14)
+		require.NoError(t, err)
+		assert.Equal(t, 45, eff.ThresholdDays)
+		assert.Equal(t, []int{45}, eff.ReminderOffsets)
+		assert.Equal(t, "user", eff.Sources["reminder_offsets"])
+	})
+
+	t.Run("User override wins and reset returns to inherited value", func(t *testing.T) {
+		_, err := prefUC.UpdateUserPreferences(user.ID, &domain.NotificationPreferenceRequest{
+			ReminderOffsets: []int{3},
+			InAppEnabled:    &disabled,
+		})
+		require.NoError(t, err)
+		eff, err := prefUC.ResolveForUser(user.ID, 14)
+		require.NoError(t, err)
+		assert.Equal(t, []int{3}, eff.ReminderOffsets)
+		assert.False(t, eff.InAppEnabled)
+
+		_, err = prefUC.UpdateUserPreferences(user.ID, &domain.NotificationPreferenceRequest{ResetFields: []string{"reminder_offsets", "in_app_enabled"}})
+		require.NoError(t, err)
+		eff, err = prefUC.ResolveForUser(user.ID, 14)
+		require.NoError(t, err)
+		assert.Equal(t, []int{45}, eff.ReminderOffsets)
+		assert.True(t, eff.InAppEnabled)
+	})
+}
+
+// TestNotificationPreferenceUseCase_UpdateValidation tests validasi request preferensi
+func TestNotificationPreferenceUseCase_UpdateValidation(t *testing.T) {
+	db := helpers.SetupTestDB(t)
+	defer helpers.CleanupTestDB(t, db)
+	require.NoError(t, db.AutoMigrate(&domain.NotificationPreferenceModel{}))
+	prefUC := NewNotificationPreferenceUseCaseWithDB(db)
+	user := createTestUserForNotification(t, db, nil)
+
+	zero, tooLong := 0, maxReminderOffsetDays+1
+	badClock, start := "25:00", "22:00"
+	badTimezone := "Mars/Olympus"
+	cases := map[string]domain.NotificationPreferenceRequest{
+		"threshold zero":          {ThresholdDays: &zero},
+		"threshold too long":      {ThresholdDays: &tooLong},
+		"empty reminder offsets":  {ReminderOffsets: []int{}},
+		"negative reminder":       {ReminderOffsets: []int{7, -1}},
+		"unknown type":            {TypePreferences: map[string]bool{"newsletter": false}},
+		"quiet hours without end": {QuietHoursStart: &start},
+		"invalid quiet hours":     {QuietHoursStart: &badClock, QuietHoursEnd: &start},
+		"invalid timezone":        {Timezone: &badTimezone},
+		"unknown reset field":     {ResetFields: []string{"email"}},
+	}
+	for name, req := range cases {
+		req := req
+		t.Run(name, func(t *testing.T) {
+			_, err := prefUC.UpdateUserPreferences(user.ID, &req)
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("Company preferences require an existing company", func(t *testing.T) {
+		_, err := prefUC.UpdateCompanyPreferences(uuid.GenerateUUID(), &domain.NotificationPreferenceRequest{ThresholdDays: &tooLong}, "admin")
+		assert.Error(t, err)
+	})
+}
+
+// TestReminderStage tests penentuan tahap reminder dari sisa hari
+func TestReminderStage(t *testing.T) {
+	prefs := &domain.EffectiveNotificationPreferences{ReminderOffsets: []int{60, 30, 7, 0}}
+	cases := []struct {
+		daysUntil int
+		stage     int
+		found     bool
+	}{
+		{90, 0, false},
+		{60, 60, true},
+		{45, 60, true},
+		{30, 30, true},
+		{5, 7, true},
+		{0, 0, true},
+		{-1, ReminderStageExpired, true},
+	}
+	for _, tc := range cases {
+		stage, found := reminderStage(prefs, tc.daysUntil)
+		assert.Equal(t, tc.found, found, "daysUntil=%d", tc.daysUntil)
+		if tc.found {
+			assert.Equal(t, tc.stage, stage, "daysUntil=%d", tc.daysUntil)
+		}
+	}
+}
+
+// TestInQuietHours tests quiet hours di timezone user, termasuk yang melewati tengah malam
+func TestInQuietHours(t *testing.T) {
+	jakarta := preferenceLocation("Asia/Jakarta")
+	at := func(hour, minute int) time.Time {
+		return time.Date(2025, 6, 2, hour, minute, 0, 0, jakarta)
+	}
+
+	overnight := &domain.EffectiveNotificationPreferences{QuietHoursStart: "22:00", QuietHoursEnd: "06:00", Timezone: "Asia/Jakarta"}
+	assert.True(t, inQuietHours(overnight, at(23, 30)))
+	assert.True(t, inQuietHours(overnight, at(5, 59)))
+	assert.False(t, inQuietHours(overnight, at(6, 0)))
+	assert.False(t, inQuietHours(overnight, at(12, 0)))
+
+	daytime := &domain.EffectiveNotificationPreferences{QuietHoursStart: "08:00", QuietHoursEnd: "12:00", Timezone: "Asia/Jakarta"}
+	assert.True(t, inQuietHours(daytime, at(8, 0)))
+	assert.False(t, inQuietHours(daytime, at(12, 0)))
+	// Waktu UTC dikonversi ke timezone user: 02:00 UTC = 09:00 WIB
+	assert.True(t, inQuietHours(daytime, time.Date(2025, 6, 2, 2, 0, 0, 0, time.UTC)))
+
+	assert.False(t, inQuietHours(&domain.EffectiveNotificationPreferences{}, at(23, 0)))
+	assert.False(t, inQuietHours(&domain.EffectiveNotificationPreferences{QuietHoursStart: "10:00", QuietHoursEnd: "10:00"}, at(10, 0)))
+}
+
+// TestNotificationAllowed tests master switch in-app dan opt-out per tipe
+func TestNotificationAllowed(t *testing.T) {
+	prefs := &domain.EffectiveNotificationPreferences{
+		InAppEnabled: true,
+		TypeEnabled:  map[string]bool{NotificationTypeDirectorTermExpiry: false},
+	}
+	assert.True(t, notificationAllowed(prefs, NotificationTypeDocumentExpiry))
+	assert.False(t, notificationAllowed(prefs, NotificationTypeDirectorTermExpiry))
+
+	prefs.InAppEnabled = false
+	assert.False(t, notificationAllowed(prefs, NotificationTypeDocumentExpiry))
+}
@@ -15,12 +15,14 @@ type NotificationSettingsUseCase interface {
 
 type notificationSettingsUseCase struct {
 	settingsRepo repository.NotificationSettingsRepository
+	prefRepo     repository.NotificationPreferenceRepository
 }
 
 // NewNotificationSettingsUseCase creates a new notification settings use case
 func NewNotificationSettingsUseCase() NotificationSettingsUseCase {
 	return &notificationSettingsUseCase{
 		settingsRepo: repository.NewNotificationSettingsRepository(),
+		prefRepo:     repository.NewNotificationPreferenceRepository(),
 	}
 }
 
@@ -48,7 +50,8 @@ func (uc *notificationSettingsUseCase) UpdateSettings(userID string, emailEnable
 		if *expiryThresholdDays < 1 || *expiryThresholdDays > 365 {
 			return nil, fmt.Errorf("expiry_threshold_days must be between 1 and 365 days")
 		}
-		settings.ExpiryThresholdDays = *expiryThresholdDays
+		threshold := *expiryThresholdDays
+		settings.ExpiryThresholdDays = &threshold
 	}
 
 	// Update settings (menggunakan Updates dengan where clause untuk menghindari duplicate key)
@@ -56,6 +59,24 @@ func (uc *notificationSettingsUseCase) UpdateSettings(userID string, emailEnable
 		return nil, fmt.Errorf("failed to update settings: %w", err)
 	}
 
+	// Sinkronkan ke override preferensi user agar scheduler memakai nilai yang sama
+	if inAppEnabled != nil || expiryThresholdDays != nil {
+		pref, err := uc.prefRepo.GetByScope(PreferenceScopeUser, userID)
+		if err != nil {
+			pref = &domain.NotificationPreferenceModel{ScopeType: PreferenceScopeUser, ScopeID: userID}
+		}
+		if inAppEnabled != nil {
+			pref.InAppEnabled = inAppEnabled
+		}
+		if expiryThresholdDays != nil {
+			pref.ThresholdDays = expiryThresholdDays
+		}
+		pref.UpdatedBy = userID
+		if err := uc.prefRepo.Save(pref); err != nil {
+			return nil, fmt.Errorf("failed to update notification preferences: %w", err)
+		}
+	}
+
 	// Reload settings untuk memastikan data terbaru
 	return uc.settingsRepo.GetByUserID(userID)
 }
@@ -1,33 +1,34 @@
 package usecase
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/kvstore"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
 	"github.com/repoareta/pedeve-dms-app/backend/internal/utils"
 	"go.uber.org/zap"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
-// Cache entry untuk unread count
-type unreadCountCacheEntry struct {
-	count     int64
-	expiresAt time.Time
-}
-
-// In-memory cache untuk unread count dengan TTL 5 detik
-var (
-	unreadCountCache    = make(map[string]*unreadCountCacheEntry)
-	unreadCountCacheMu  sync.RWMutex
-	unreadCountCacheTTL = 5 * time.Second // Cache selama 5 detik untuk mengurangi query
+// Cache unread count di kvstore (dibagi antar instance jika memakai Redis) dengan TTL 5 detik.
+// Key per user: notif:unread:<userID>:<role>[:<companyID>], dan untuk superadmin/administrator
+// (count global): notif:unread-all:<userID>, sehingga invalidasi cukup dengan hapus prefix.
+const (
+	unreadCountCacheTTL       = 5 * time.Second // Cache selama 5 detik untuk mengurangi query
+	unreadCountKeyPrefix      = "notif:unread:"
+	unreadCountAllKeyPrefix   = "notif:unread-all:"
+	unreadCountCacheOpTimeout = time.Second
 )
 
 // NotificationUseCase interface untuk notification operations
@@ -48,12 +49,16 @@ type NotificationUseCase interface {
 }
 
 type notificationUseCase struct {
-	notifRepo    repository.NotificationRepository
-	docRepo      repository.DocumentRepository
-	userRepo     repository.UserRepository
-	companyRepo  repository.CompanyRepository
-	directorRepo repository.DirectorRepository
-	db           *gorm.DB // For direct queries in CheckExpiringDocuments and CheckExpiringDirectorTerms
+	notifRepo      repository.NotificationRepository
+	docRepo        repository.DocumentRepository
+	userRepo       repository.UserRepository
+	companyRepo    repository.CompanyRepository
+	directorRepo   repository.DirectorRepository
+	prefRepo       repository.NotificationPreferenceRepository
+	escalationRepo repository.NotificationEscalationRepository
+	prefUC         NotificationPreferenceUseCase
+	db             *gorm.DB         // For direct queries in CheckExpiringDocuments and CheckExpiringDirectorTerms
+	now            func() time.Time // Sumber waktu untuk scheduler (di-override saat testing)
 }
 
 // NewNotificationUseCase membuat notification use case baru
@@ -64,12 +69,16 @@ func NewNotificationUseCase() NotificationUseCase {
 // NewNotificationUseCaseWithDB membuat notification use case dengan DB yang di-inject (untuk testing)
 func NewNotificationUseCaseWithDB(db *gorm.DB) NotificationUseCase {
 	return &notificationUseCase{
-		notifRepo:    repository.NewNotificationRepositoryWithDB(db),
-		docRepo:      repository.NewDocumentRepositoryWithDB(db),
-		userRepo:     repository.NewUserRepositoryWithDB(db),
-		companyRepo:  repository.NewCompanyRepositoryWithDB(db),
-		directorRepo: repository.NewDirectorRepositoryWithDB(db),
-		db:           db,
+		notifRepo:      repository.NewNotificationRepositoryWithDB(db),
+		docRepo:        repository.NewDocumentRepositoryWithDB(db),
+		userRepo:       repository.NewUserRepositoryWithDB(db),
+		companyRepo:    repository.NewCompanyRepositoryWithDB(db),
+		directorRepo:   repository.NewDirectorRepositoryWithDB(db),
+		prefRepo:       repository.NewNotificationPreferenceRepositoryWithDB(db),
+		escalationRepo: repository.NewNotificationEscalationRepositoryWithDB(db),
+		prefUC:         NewNotificationPreferenceUseCaseWithDB(db),
+		db:             db,
+		now:            time.Now,
 	}
 }
 
@@ -171,22 +180,17 @@ func (uc *notificationUseCase) GetNotificationsWithRBAC(userID, roleName string,
 // GetUnreadCountWithRBAC mendapatkan unread count dengan RBAC (dengan caching)
 func (uc *notificationUseCase) GetUnreadCountWithRBAC(userID, roleName string, companyID *string) (int64, error) {
 	// Generate cache key berdasarkan role dan context
-	cacheKey := fmt.Sprintf("%s:%s", roleName, userID)
-	if companyID != nil {
-		cacheKey = fmt.Sprintf("%s:%s:%s", roleName, userID, *companyID)
+	cacheKey := fmt.Sprintf("%s%s:%s", unreadCountKeyPrefix, userID, roleName)
+	if utils.IsSuperAdminLike(roleName) {
+		cacheKey = unreadCountAllKeyPrefix + userID
+	} else if companyID != nil {
+		cacheKey = fmt.Sprintf("%s%s:%s:%s", unreadCountKeyPrefix, userID, roleName, *companyID)
 	}
 
 	// Check cache first
-	unreadCountCacheMu.RLock()
-	if entry, exists := unreadCountCache[cacheKey]; exists {
-		if time.Now().Before(entry.expiresAt) {
-			unreadCountCacheMu.RUnlock()
-			return entry.count, nil
-		}
-		// Cache expired, remove it
-		delete(unreadCountCache, cacheKey)
+	if count, ok := getCachedUnreadCount(cacheKey); ok {
+		return count, nil
 	}
-	unreadCountCacheMu.RUnlock()
 
 	// Superadmin/Administrator melihat semua unread count dari semua notifications di sistem
 	if utils.IsSuperAdminLike(roleName) {
@@ -195,12 +199,7 @@ func (uc *notificationUseCase) GetUnreadCountWithRBAC(userID, roleName string, c
 
 		// Cache hasil
 		if err == nil {
-			unreadCountCacheMu.Lock()
-			unreadCountCache[cacheKey] = &unreadCountCacheEntry{
-				count:     total,
-				expiresAt: time.Now().Add(unreadCountCacheTTL),
-			}
-			unreadCountCacheMu.Unlock()
+			setCachedUnreadCount(cacheKey, total)
 		}
 
 		return total, err
@@ -239,12 +238,7 @@ func (uc *notificationUseCase) GetUnreadCountWithRBAC(userID, roleName string, c
 
 		// Cache hasil
 		if err == nil {
-			unreadCountCacheMu.Lock()
-			unreadCountCache[cacheKey] = &unreadCountCacheEntry{
-				count:     count,
-				expiresAt: time.Now().Add(unreadCountCacheTTL),
-			}
-			unreadCountCacheMu.Unlock()
+			setCachedUnreadCount(cacheKey, count)
 		}
 
 		return count, err
@@ -364,73 +358,72 @@ func (uc *notificationUseCase) MarkAllAsRead(userID string) error {
 	return err
 }
 
-// invalidateUnreadCountCache menghapus cache unread count untuk user tertentu
-func invalidateUnreadCountCache(userID string) {
-	unreadCountCacheMu.Lock()
-	defer unreadCountCacheMu.Unlock()
+// getCachedUnreadCount membaca unread count dari cache; error store diperlakukan sebagai cache miss
+func getCachedUnreadCount(cacheKey string) (int64, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), unreadCountCacheOpTimeout)
+	defer cancel()
 
-	// Hapus semua cache entry yang terkait dengan userID
-	keysToDelete := []string{}
-	for key := range unreadCountCache {
-		if key == fmt.Sprintf("user:%s", userID) {
-			keysToDelete = append(keysToDelete, key)
-		} else if len(key) > len(userID) && key[len(key)-len(userID):] == userID {
-			keysToDelete = append(keysToDelete, key)
-		} else if len(key) > len(userID)+1 && key[:len(userID)+1] == userID+":" {
-			keysToDelete = append(keysToDelete, key)
+	value, err := kvstore.GetStore().Get(ctx, cacheKey)
+	if err != nil {
+		if !errors.Is(err, kvstore.ErrNotFound) {
+			logger.GetLogger().Warn("Failed to read unread count cache", zap.String("key", cacheKey), zap.Error(err))
 		}
+		return 0, false
+	}
+	count, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false
 	}
+	return count, true
+}
+
+// setCachedUnreadCount menyimpan unread count ke cache dengan TTL unreadCountCacheTTL
+func setCachedUnreadCount(cacheKey string, count int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), unreadCountCacheOpTimeout)
+	defer cancel()
 
-	for _, key := range keysToDelete {
-		delete(unreadCountCache, key)
+	if err := kvstore.GetStore().Set(ctx, cacheKey, strconv.FormatInt(count, 10), unreadCountCacheTTL); err != nil {
+		logger.GetLogger().Warn("Failed to write unread count cache", zap.String("key", cacheKey), zap.Error(err))
 	}
 }
 
-// invalidateAllUnreadCountCache menghapus semua cache unread count (untuk superadmin/administrator)
-func invalidateAllUnreadCountCache() {
-	unreadCountCacheMu.Lock()
-	defer unreadCountCacheMu.Unlock()
+// invalidateUnreadCountCache menghapus cache unread count untuk user tertentu (semua role/company context)
+func invalidateUnreadCountCache(userID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), unreadCountCacheOpTimeout)
+	defer cancel()
 
-	// Hapus semua cache entry yang terkait dengan superadmin/administrator
-	keysToDelete := []string{}
-	for key := range unreadCountCache {
-		// Cache key untuk superadmin/administrator berbentuk "superadmin:userID" atau "administrator:userID"
-		// atau "superadmin:userID:companyID" atau "administrator:userID:companyID"
-		if len(key) >= 10 && (key[:10] == "superadmin" || key[:13] == "administrator") {
-			keysToDelete = append(keysToDelete, key)
-		}
+	store := kvstore.GetStore()
+	if err := store.DeletePrefix(ctx, unreadCountKeyPrefix+userID+":"); err != nil {
+		logger.GetLogger().Warn("Failed to invalidate unread count cache", zap.String("user_id", userID), zap.Error(err))
 	}
+	if err := store.Delete(ctx, unreadCountAllKeyPrefix+userID); err != nil {
+		logger.GetLogger().Warn("Failed to invalidate unread count cache", zap.String("user_id", userID), zap.Error(err))
+	}
+}
 
-	for _, key := range keysToDelete {
-		delete(unreadCountCache, key)
+// invalidateAllUnreadCountCache menghapus semua cache unread count global (untuk superadmin/administrator)
+func invalidateAllUnreadCountCache() {
+	ctx, cancel := context.WithTimeout(context.Background(), unreadCountCacheOpTimeout)
+	defer cancel()
+
+	if err := kvstore.GetStore().DeletePrefix(ctx, unreadCountAllKeyPrefix); err != nil {
+		logger.GetLogger().Warn("Failed to invalidate global unread count cache", zap.Error(err))
 	}
 }
 
 func (uc *notificationUseCase) GetUnreadCount(userID string) (int64, error) {
 	// Check cache first
-	cacheKey := fmt.Sprintf("user:%s", userID)
-	unreadCountCacheMu.RLock()
-	if entry, exists := unreadCountCache[cacheKey]; exists {
-		if time.Now().Before(entry.expiresAt) {
-			unreadCountCacheMu.RUnlock()
-			return entry.count, nil
-		}
-		// Cache expired, remove it
-		delete(unreadCountCache, cacheKey)
+	cacheKey := fmt.Sprintf("%s%s:user", unreadCountKeyPrefix, userID)
+	if count, ok := getCachedUnreadCount(cacheKey); ok {
+		return count, nil
 	}
-	unreadCountCacheMu.RUnlock()
 
 	// Get from database
 	count, err := uc.notifRepo.GetUnreadCount(userID)
 
 	// Cache hasil
 	if err == nil {
-		unreadCountCacheMu.Lock()
-		unreadCountCache[cacheKey] = &unreadCountCacheEntry{
-			count:     count,
-			expiresAt: time.Now().Add(unreadCountCacheTTL),
-		}
-		unreadCountCacheMu.Unlock()
+		setCachedUnreadCount(cacheKey, count)
 	}
 
 	return count, err
@@ -483,322 +476,84 @@ func (uc *notificationUseCase) DeleteAllWithRBAC(userID, roleName string, compan
 	return uc.notifRepo.DeleteAllByUserID(userID)
 }
 
-// CheckExpiringDocuments adalah helper function untuk check dan create notifications untuk expiring documents
-// Breakdown per folder: dokumen akan di-group berdasarkan folder untuk notifikasi yang lebih terorganisir
-// Ini akan dipanggil oleh scheduler/cron job
-// thresholdDays: jumlah hari sebelum expired untuk membuat notifikasi pertama kali (default: 14 hari)
-// Dokumen yang kurang dari thresholdDays tapi belum ada notifikasinya akan langsung dibuat notifikasinya
+// CheckExpiringDocuments membuat reminder untuk dokumen yang akan/sudah expired (dipanggil oleh scheduler/cron job)
+// Penerima reminder adalah uploader dokumen. Jadwal reminder mengikuti preferensi efektif penerima:
+// offset reminder (misal 60/30/7/0 hari sebelum expired), opt-in/out per tipe, master switch in-app, dan quiet hours.
+// thresholdDays: default sistem untuk user yang tidak punya preferensi user maupun company (default: 14 hari)
+// Setiap tahap reminder hanya dikirim sekali per user per dokumen (dicatat di notification_reminder_logs)
+// documentsFound: jumlah dokumen yang sedang berada di salah satu tahap reminder penerimanya
 func (uc *notificationUseCase) CheckExpiringDocuments(thresholdDays int) (notificationsCreated int, documentsFound int, err error) {
 	zapLog := logger.GetLogger()
 
 	// Gunakan start of day untuk perbandingan tanggal yang konsisten
-	now := time.Now()
-	// Truncate ke start of day untuk memastikan perbandingan hanya berdasarkan tanggal
+	now := uc.currentTime()
 	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	thresholdDate := todayStart.AddDate(0, 0, thresholdDays)
 
-	// Query documents yang akan expired dalam threshold atau sudah expired (termasuk hari ini)
-	// PENTING: Ambil dokumen yang sudah expired juga (untuk reminder sampai ditindaklanjuti)
-	// Cek apakah sudah ada notifikasi yang belum dibaca untuk dokumen tersebut
-	// Jika belum ada notifikasi unread, buat notifikasi baru
-	var documents []domain.DocumentModel
 	db := uc.db
 	if db == nil {
 		db = database.GetDB() // Fallback to default DB if not injected
 	}
 
-	// Query dokumen yang expired (termasuk hari ini) atau akan expired dalam threshold
-	// Gunakan end of day untuk thresholdDate agar semua dokumen yang expired pada hari threshold juga terambil
-	// Ini memastikan dokumen yang expired hari ini tetap terambil meskipun ada perbedaan timezone
-	thresholdDateEnd := thresholdDate.AddDate(0, 0, 1).Add(-time.Nanosecond) // End of threshold day (23:59:59.999999999)
-
-	// Log query parameters untuk debugging
-	zapLog.Info("Querying expiring documents",
-		zap.Int("threshold_days", thresholdDays),
-		zap.Time("today_start", todayStart),
-		zap.Time("threshold_date", thresholdDate),
-		zap.Time("threshold_date_end", thresholdDateEnd),
-		zap.String("threshold_date_end_formatted", thresholdDateEnd.Format("2006-01-02 15:04:05")),
-	)
-
 	// PENTING: expiry_date disimpan di metadata, bukan di kolom expiry_date
-	// Query semua dokumen yang memiliki metadata (karena expiry_date ada di metadata)
-	// PENTING: Query ini TIDAK memfilter berdasarkan company_id - mencakup SEMUA perusahaan
-	// Notifikasi akan dibuat untuk semua dokumen expired dari semua perusahaan dan folder
+	// PENTING: Query ini TIDAK memfilter berdasarkan company_id - mencakup SEMUA perusahaan dan folder
 	// Note: Query kompatibel dengan SQLite (untuk testing) dan PostgreSQL (untuk production)
-	var totalDocsWithMetadata int64
-	query := db.Model(&domain.DocumentModel{}).Where("metadata IS NOT NULL")
-	// Untuk PostgreSQL, tambahkan filter untuk exclude empty JSON
-	if db.Dialector.Name() == "postgres" {
-		query = query.Where("metadata != '{}'::jsonb")
-	} else {
-		// Untuk SQLite, gunakan filter yang kompatibel
-		query = query.Where("metadata != '{}' AND metadata != 'null' AND metadata != ''")
-	}
-	query.Count(&totalDocsWithMetadata)
-
-	zapLog.Info("Total documents with metadata",
-		zap.Int64("with_metadata", totalDocsWithMetadata),
-	)
-
 	var allDocs []domain.DocumentModel
 	queryDocs := db.Preload("Folder").Where("metadata IS NOT NULL")
-	// Untuk PostgreSQL, tambahkan filter untuk exclude empty JSON
 	if db.Dialector.Name() == "postgres" {
 		queryDocs = queryDocs.Where("metadata != '{}'::jsonb")
 	} else {
-		// Untuk SQLite, gunakan filter yang kompatibel
 		queryDocs = queryDocs.Where("metadata != '{}' AND metadata != 'null' AND metadata != ''")
 	}
-	err = queryDocs.Find(&allDocs).Error
-	if err != nil {
+	if err = queryDocs.Find(&allDocs).Error; err != nil {
 		zapLog.Error("Failed to query documents", zap.Error(err))
 		return 0, 0, err
 	}
 
-	// Log untuk memastikan query mencakup semua perusahaan
-	zapLog.Info("Querying documents from ALL companies and folders (no company filter)",
-		zap.Int("total_documents_queried", len(allDocs)),
-	)
+	resolver := newPreferenceResolver(uc.prefUC, thresholdDays)
+	skipped := make(map[string]int)
 
-	// Filter dokumen yang expired atau akan expired
-	// Baca expiry_date dari metadata (field expiry_date di table tidak digunakan lagi)
-	documents = []domain.DocumentModel{}
 	for _, doc := range allDocs {
-		var expiryDate *time.Time
-
-		// Baca expiry_date dari metadata
-		if doc.Metadata != nil {
-			// Jika kolom expiry_date NULL, coba baca dari metadata
-			var metadata map[string]interface{}
-			if err := json.Unmarshal(doc.Metadata, &metadata); err == nil {
-				// Cek berbagai kemungkinan key untuk expiry_date di metadata
-				if expiredDateStr, ok := metadata["expired_date"].(string); ok && expiredDateStr != "" {
-					// Parse expired_date dari metadata (format: "2025-12-20" atau "2025-12-20T00:00:00Z")
-					if parsedDate, err := time.Parse("2006-01-02", expiredDateStr); err == nil {
-						expiryDate = &parsedDate
-					} else if parsedDate, err := time.Parse(time.RFC3339, expiredDateStr); err == nil {
-						expiryDate = &parsedDate
-					} else if parsedDate, err := time.Parse("2006-01-02T15:04:05Z07:00", expiredDateStr); err == nil {
-						expiryDate = &parsedDate
-					}
-				} else if expiryDateStr, ok := metadata["expiry_date"].(string); ok && expiryDateStr != "" {
-					// Coba key "expiry_date" juga
-					if parsedDate, err := time.Parse("2006-01-02", expiryDateStr); err == nil {
-						expiryDate = &parsedDate
-					} else if parsedDate, err := time.Parse(time.RFC3339, expiryDateStr); err == nil {
-						expiryDate = &parsedDate
-					}
-				}
-			}
-		}
-
-		// Jika ada expiry_date (dari metadata), cek apakah expired atau akan expired
-		if expiryDate != nil {
-			// Truncate ke start of day untuk perbandingan yang konsisten
-			expiryDateStart := time.Date(expiryDate.Year(), expiryDate.Month(), expiryDate.Day(), 0, 0, 0, 0, expiryDate.Location())
-			if expiryDateStart.Before(thresholdDate) || expiryDateStart.Equal(thresholdDate) || expiryDateStart.Before(thresholdDateEnd) {
-				documents = append(documents, doc)
-			}
+		expiryDate := parseDocumentExpiryDate(doc.Metadata)
+		if expiryDate == nil || doc.UploaderID == "" {
+			continue
 		}
-	}
 
-	zapLog.Info("Filtered documents with expiry_date (from metadata)",
-		zap.Int("total_docs_checked", len(allDocs)),
-		zap.Int("docs_with_valid_expiry", len(documents)),
-	)
-
-	// Log hasil query dengan detail
-	zapLog.Info("Query expiring documents completed",
-		zap.Int("threshold_days", thresholdDays),
-		zap.Time("threshold_date", thresholdDate),
-		zap.Time("threshold_date_end", thresholdDateEnd),
-		zap.Time("today_start", todayStart),
-		zap.Int("documents_found", len(documents)),
-	)
+		// Hitung hari dengan perbandingan tanggal yang konsisten
+		docExpiryDate := time.Date(expiryDate.Year(), expiryDate.Month(), expiryDate.Day(), 0, 0, 0, 0, todayStart.Location())
+		daysUntilExpiry := int(docExpiryDate.Sub(todayStart).Hours() / 24)
 
-	// Log detail dokumen yang ditemukan untuk debugging
-	if len(documents) > 0 {
-		for i, doc := range documents {
-			if i < 10 { // Log 10 pertama untuk debugging
-				expiryStr := "NULL"
-				daysUntil := 0
-				// Baca expiry_date dari metadata
-				var expiryDate *time.Time
-				if len(doc.Metadata) > 0 {
-					metadata := make(map[string]interface{})
-					if err := json.Unmarshal(doc.Metadata, &metadata); err == nil {
-						if expiredDateStr, ok := metadata["expired_date"].(string); ok && expiredDateStr != "" {
-							if parsedDate, err := time.Parse("2006-01-02", expiredDateStr); err == nil {
-								expiryDate = &parsedDate
-							} else if parsedDate, err := time.Parse(time.RFC3339, expiredDateStr); err == nil {
-								expiryDate = &parsedDate
-							}
-						} else if expiryDateStr, ok := metadata["expiry_date"].(string); ok && expiryDateStr != "" {
-							if parsedDate, err := time.Parse("2006-01-02", expiryDateStr); err == nil {
-								expiryDate = &parsedDate
-							} else if parsedDate, err := time.Parse(time.RFC3339, expiryDateStr); err == nil {
-								expiryDate = &parsedDate
-							}
-						}
-					}
-				}
-				if expiryDate != nil {
-					expiryStr = expiryDate.Format("2006-01-02 15:04:05")
-					docExpiryDate := time.Date(expiryDate.Year(), expiryDate.Month(), expiryDate.Day(), 0, 0, 0, 0, expiryDate.Location())
-					daysUntil = int(docExpiryDate.Sub(todayStart).Hours() / 24)
-				}
-				zapLog.Info("Found expiring document",
-					zap.String("document_id", doc.ID),
-					zap.String("document_name", doc.Name),
-					zap.String("expiry_date", expiryStr),
-					zap.Int("days_until_expiry", daysUntil),
-					zap.String("uploader_id", doc.UploaderID),
-				)
-			}
-		}
-		if len(documents) > 10 {
-			zapLog.Info("... and more documents", zap.Int("total", len(documents)))
-		}
-	} else {
-		zapLog.Warn("No expiring documents found",
-			zap.Time("threshold_date_end", thresholdDateEnd),
-			zap.String("threshold_date_end_str", thresholdDateEnd.Format("2006-01-02 15:04:05")),
-		)
-		// Coba query alternatif untuk debugging - cek dokumen dengan metadata
-		var sampleDocs []domain.DocumentModel
-		querySample := db.Where("metadata IS NOT NULL")
-		// Untuk PostgreSQL, tambahkan filter untuk exclude empty JSON
-		if db.Dialector.Name() == "postgres" {
-			querySample = querySample.Where("metadata != '{}'::jsonb")
-		} else {
-			// Untuk SQLite, gunakan filter yang kompatibel
-			querySample = querySample.Where("metadata != '{}' AND metadata != 'null' AND metadata != ''")
-		}
-		querySample.Limit(5).Find(&sampleDocs)
-		if len(sampleDocs) > 0 {
-			zapLog.Info("Sample documents with metadata (first 5):")
-			for _, doc := range sampleDocs {
-				expiryStr := "NULL"
-				// Baca expiry_date dari metadata
-				if len(doc.Metadata) > 0 {
-					metadata := make(map[string]interface{})
-					if err := json.Unmarshal(doc.Metadata, &metadata); err == nil {
-						if expiredDateStr, ok := metadata["expired_date"].(string); ok && expiredDateStr != "" {
-							expiryStr = expiredDateStr
-						} else if expiryDateStr, ok := metadata["expiry_date"].(string); ok && expiryDateStr != "" {
-							expiryStr = expiryDateStr
-						}
-					}
-				}
-				zapLog.Info("Sample document",
-					zap.String("id", doc.ID),
-					zap.String("name", doc.Name),
-					zap.String("expiry_date_from_metadata", expiryStr),
-				)
-			}
+		// Dokumen expired membuka rantai eskalasi (sekali per tanggal expired), terlepas dari preferensi uploader
+		if daysUntilExpiry < 0 {
+			uc.openEscalation("document", doc.ID, doc.Name, docExpiryDate.Format("2006-01-02"), uc.documentCompanyID(&doc), now)
 		}
-	}
 
-	documentsFound = len(documents)
-	notificationsCreated = 0
-
-	// PENTING: Notifikasi dibuat untuk SEMUA dokumen expired dari SEMUA perusahaan dan folder
-	// Tidak ada filter company_id - semua dokumen dari semua perusahaan akan mendapat notifikasi
-	// PENTING: Buat notifikasi per dokumen (bukan per folder) agar count lebih akurat
-	for _, doc := range documents {
-		// Baca expiry_date dari metadata
-		var expiryDate *time.Time
-		if len(doc.Metadata) > 0 {
-			metadata := make(map[string]interface{})
-			if err := json.Unmarshal(doc.Metadata, &metadata); err == nil {
-				// Cek berbagai kemungkinan key untuk expiry_date di metadata
-				if expiredDateStr, ok := metadata["expired_date"].(string); ok && expiredDateStr != "" {
-					if parsedDate, err := time.Parse("2006-01-02", expiredDateStr); err == nil {
-						expiryDate = &parsedDate
-					} else if parsedDate, err := time.Parse(time.RFC3339, expiredDateStr); err == nil {
-						expiryDate = &parsedDate
-					}
-				} else if expiryDateStr, ok := metadata["expiry_date"].(string); ok && expiryDateStr != "" {
-					if parsedDate, err := time.Parse("2006-01-02", expiryDateStr); err == nil {
-						expiryDate = &parsedDate
-					} else if parsedDate, err := time.Parse(time.RFC3339, expiryDateStr); err == nil {
-						expiryDate = &parsedDate
-					}
-				}
-			}
+		prefs, err := resolver.forUser(doc.UploaderID)
+		if err != nil {
+			zapLog.Warn("Failed to resolve notification preferences", zap.Error(err), zap.String("user_id", doc.UploaderID))
+			continue
 		}
-
-		// Jika tidak ada expiry_date, skip dokumen ini
-		if expiryDate == nil {
+		stage, due := reminderStage(prefs, daysUntilExpiry)
+		if !due {
 			continue
 		}
-
-		// Hitung hari dengan perbandingan tanggal yang konsisten
-		docExpiryDate := time.Date(expiryDate.Year(), expiryDate.Month(), expiryDate.Day(), 0, 0, 0, 0, expiryDate.Location())
-		daysUntilExpiry := int(docExpiryDate.Sub(todayStart).Hours() / 24)
-		isExpired := docExpiryDate.Before(todayStart) || docExpiryDate.Equal(todayStart)
+		documentsFound++
 
 		// Ambil nama folder untuk message
 		folderName := "No Folder"
 		if doc.FolderID != nil && doc.Folder != nil {
 			folderName = doc.Folder.Name
 		} else if doc.FolderID != nil {
-			// Load folder if not loaded
 			folder, err := uc.docRepo.GetFolderByID(*doc.FolderID)
 			if err == nil && folder != nil {
 				folderName = folder.Name
 			}
 		}
 
-		// Cek apakah sudah ada notifikasi unread untuk dokumen ini dengan status yang sama (expired/akan expired)
-		// PENTING: Notifikasi dibuat sekali per dokumen per status (expired vs akan expired)
-		// Jika dokumen sudah expired dan sudah ada notifikasi "Sudah Expired", tidak perlu buat lagi
-		// Tapi jika dokumen akan expired dan belum ada notifikasi "Akan Expired", tetap buat notifikasi
-		hasUnreadNotif := false
-		existingNotifs, err := uc.notifRepo.GetByUserID(doc.UploaderID, true, 100)
-		if err == nil {
-			for _, notif := range existingNotifs {
-				if notif.ResourceType == "document" && notif.ResourceID != nil && *notif.ResourceID == doc.ID {
-					// Cek apakah status notifikasi sama (expired vs akan expired)
-					// Jika dokumen akan expired (daysUntilExpiry > 0) dan sudah ada notifikasi "Akan Expired", skip
-					// Jika dokumen sudah expired (daysUntilExpiry < 0) dan sudah ada notifikasi "Sudah Expired", skip
-					// Tapi jika dokumen akan expired dan hanya ada notifikasi "Sudah Expired", tetap buat notifikasi "Akan Expired"
-					if daysUntilExpiry >= 0 {
-						// Dokumen akan expired - cek apakah sudah ada notifikasi "Akan Expired"
-						if strings.Contains(notif.Title, "Akan Expired") {
-							hasUnreadNotif = true
-							break
-						}
-					} else {
-						// Dokumen sudah expired - cek apakah sudah ada notifikasi "Sudah Expired"
-						if strings.Contains(notif.Title, "Sudah Expired") {
-							hasUnreadNotif = true
-							break
-						}
-					}
-				}
-			}
-		}
-		// Jika sudah ada notifikasi unread dengan status yang sama, skip
-		// Push notification akan muncul berulang di frontend untuk notifikasi yang belum ditindak lanjuti
-		if hasUnreadNotif {
-			continue
-		}
-
-		// Buat notifikasi untuk setiap dokumen
 		var title, message string
 		if daysUntilExpiry < 0 {
-			// Sudah expired
-			daysAgo := -daysUntilExpiry
 			title = fmt.Sprintf("Dokumen '%s' Sudah Expired", doc.Name)
-			if daysAgo == 0 {
-				message = fmt.Sprintf("Dokumen '%s' di folder '%s' sudah expired hari ini. Silakan perbarui atau perpanjang dokumen tersebut.",
-					doc.Name, folderName)
-			} else {
-				message = fmt.Sprintf("Dokumen '%s' di folder '%s' sudah expired %d hari yang lalu. Silakan perbarui atau perpanjang dokumen tersebut.",
-					doc.Name, folderName, daysAgo)
-			}
+			message = fmt.Sprintf("Dokumen '%s' di folder '%s' sudah expired %d hari yang lalu. Silakan perbarui atau perpanjang dokumen tersebut.",
+				doc.Name, folderName, -daysUntilExpiry)
 		} else if daysUntilExpiry == 0 {
 			title = fmt.Sprintf("Dokumen '%s' Akan Expired", doc.Name)
 			message = fmt.Sprintf("Dokumen '%s' di folder '%s' akan expired hari ini. Silakan perbarui atau perpanjang dokumen tersebut.",
@@ -809,139 +564,331 @@ func (uc *notificationUseCase) CheckExpiringDocuments(thresholdDays int) (notifi
 				doc.Name, folderName, daysUntilExpiry)
 		}
 
-		_, err = uc.CreateNotification(
-			doc.UploaderID,
-			"document_expiry",
-			title,
-			message,
-			"document",
-			&doc.ID,
-		)
+		created, reason, err := uc.deliverReminder(prefs, now, reminderDelivery{
+			userID:           doc.UploaderID,
+			notificationType: NotificationTypeDocumentExpiry,
+			resourceType:     "document",
+			resourceID:       doc.ID,
+			dueDate:          docExpiryDate.Format("2006-01-02"),
+			stage:            stage,
+			title:            title,
+			message:          message,
+		})
 		if err != nil {
 			zapLog.Error("Failed to create notification", zap.Error(err), zap.String("document_id", doc.ID))
 			continue
-		} else {
-			notificationsCreated++
-			zapLog.Info("Created notification for document",
-				zap.String("document_id", doc.ID),
-				zap.String("document_name", doc.Name),
-				zap.Int("days_until_expiry", daysUntilExpiry),
-				zap.Bool("is_expired", isExpired),
-			)
 		}
+		if !created {
+			skipped[reason]++
+			continue
+		}
+		notificationsCreated++
 	}
 
+	zapLog.Info("Expiring documents check completed",
+		zap.Int("system_threshold_days", thresholdDays),
+		zap.Int("documents_checked", len(allDocs)),
+		zap.Int("documents_due", documentsFound),
+		zap.Int("notifications_created", notificationsCreated),
+		zap.Any("skipped", skipped),
+	)
+
 	return notificationsCreated, documentsFound, nil
 }
 
-// CheckExpiringDirectorTerms adalah helper function untuk check dan create notifications untuk masa jabatan pengurus yang akan berakhir
+// CheckExpiringDirectorTerms membuat reminder untuk masa jabatan pengurus yang akan/sudah berakhir (dipanggil oleh scheduler/cron job)
 // Hanya akan check directors yang memiliki EndDate (tidak null)
-// Ini akan dipanggil oleh scheduler/cron job
-// thresholdDays: jumlah hari sebelum expired untuk membuat notifikasi pertama kali (default: 14 hari)
-// Masa jabatan yang kurang dari thresholdDays tapi belum ada notifikasinya akan langsung dibuat notifikasinya
+// Penerima reminder adalah user aktif di company pengurus, masing-masing dengan jadwal sesuai preferensi efektifnya
+// thresholdDays: default sistem untuk user yang tidak punya preferensi user maupun company (default: 14 hari)
 func (uc *notificationUseCase) CheckExpiringDirectorTerms(thresholdDays int) (notificationsCreated int, directorsFound int, err error) {
 	zapLog := logger.GetLogger()
 
-	thresholdDate := time.Now().AddDate(0, 0, thresholdDays)
+	now := uc.currentTime()
+	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	// Ambil semua masa jabatan dalam jangkauan offset reminder terbesar yang diizinkan
+	windowEnd := todayStart.AddDate(0, 0, maxReminderOffsetDays+1)
 
-	// Query directors yang akan expired dalam threshold atau sudah expired (hanya yang memiliki EndDate)
 	var directors []domain.DirectorModel
 	db := uc.db
 	if db == nil {
 		db = database.GetDB() // Fallback to default DB if not injected
 	}
 	err = db.
-		Where("end_date IS NOT NULL AND end_date <= ?", thresholdDate).
+		Where("end_date IS NOT NULL AND end_date < ?", windowEnd).
 		Find(&directors).Error
 	if err != nil {
 		zapLog.Error("Failed to query expiring directors", zap.Error(err))
 		return 0, 0, err
 	}
 
-	directorsFound = len(directors)
-	notificationsCreated = 0
+	resolver := newPreferenceResolver(uc.prefUC, thresholdDays)
+	skipped := make(map[string]int)
+	companyUsers := make(map[string][]domain.UserModel)
+	companyNames := make(map[string]string)
 
-	// Get company admins and superadmins who should be notified
 	for _, director := range directors {
-		daysUntilExpiry := int(time.Until(*director.EndDate).Hours() / 24)
+		endDate := time.Date(director.EndDate.Year(), director.EndDate.Month(), director.EndDate.Day(), 0, 0, 0, 0, todayStart.Location())
+		daysUntilExpiry := int(endDate.Sub(todayStart).Hours() / 24)
+
+		// Get company name dan users (di-cache per company)
+		companyName, ok := companyNames[director.CompanyID]
+		if !ok {
+			companyName = director.CompanyID // fallback to ID
+			if company, err := uc.companyRepo.GetByID(director.CompanyID); err == nil && company != nil {
+				companyName = company.Name
+			}
+			companyNames[director.CompanyID] = companyName
+		}
+		users, ok := companyUsers[director.CompanyID]
+		if !ok {
+			users, err = uc.userRepo.GetByCompanyID(director.CompanyID)
+			if err != nil {
+				zapLog.Warn("Failed to get users for company", zap.Error(err), zap.String("company_id", director.CompanyID))
+				continue
+			}
+			companyUsers[director.CompanyID] = users
+		}
 
-		// Get company name
-		company, err := uc.companyRepo.GetByID(director.CompanyID)
-		companyName := director.CompanyID // fallback to ID
-		if err == nil && company != nil {
-			companyName = company.Name
+		if daysUntilExpiry < 0 {
+			companyID := director.CompanyID
+			uc.openEscalation("director", director.ID, director.FullName, endDate.Format("2006-01-02"), &companyID, now)
 		}
 
-		// Get users who have access to this company (admins and superadmins)
-		// For simplicity, we'll notify all users associated with the company
-		users, err := uc.userRepo.GetByCompanyID(director.CompanyID)
-		if err != nil {
-			zapLog.Warn("Failed to get users for company", zap.Error(err), zap.String("company_id", director.CompanyID))
-			continue
+		var title, message string
+		if daysUntilExpiry < 0 {
+			title = fmt.Sprintf("Masa Jabatan '%s' Sudah Berakhir", director.FullName)
+			message = fmt.Sprintf("Masa jabatan %s sebagai %s di %s sudah berakhir %d hari yang lalu. Silakan perpanjang atau ganti pengurus tersebut.",
+				director.FullName, director.Position, companyName, -daysUntilExpiry)
+		} else if daysUntilExpiry == 0 {
+			title = fmt.Sprintf("Masa Jabatan '%s' Akan Berakhir", director.FullName)
+			message = fmt.Sprintf("Masa jabatan %s sebagai %s di %s akan berakhir hari ini. Silakan perpanjang atau ganti pengurus tersebut.",
+				director.FullName, director.Position, companyName)
+		} else {
+			title = fmt.Sprintf("Masa Jabatan '%s' Akan Berakhir", director.FullName)
+			message = fmt.Sprintf("Masa jabatan %s sebagai %s di %s akan berakhir dalam %d hari. Silakan perpanjang atau ganti pengurus tersebut.",
+				director.FullName, director.Position, companyName, daysUntilExpiry)
 		}
 
-		// Create notification for each user in the company
-		// PENTING: Cek apakah sudah ada notifikasi unread untuk director ini
-		// Jika sudah ada, skip (notifikasi akan tetap muncul sampai ditindaklanjuti)
-		directorID := director.ID
+		directorDue := false
 		for _, user := range users {
-			// Cek apakah sudah ada notifikasi unread untuk director ini
-			hasUnreadNotification := false
-			existingNotifs, err := uc.notifRepo.GetByUserID(user.ID, true, 100)
-			if err == nil {
-				for _, notif := range existingNotifs {
-					if notif.ResourceType == "director" && notif.ResourceID != nil && *notif.ResourceID == directorID {
-						hasUnreadNotification = true
-						break
-					}
-				}
+			if !user.IsActive {
+				continue
 			}
-
-			// Jika sudah ada notifikasi unread, skip (notifikasi akan tetap muncul sampai ditindaklanjuti)
-			if hasUnreadNotification {
+			prefs, err := resolver.forUser(user.ID)
+			if err != nil {
+				zapLog.Warn("Failed to resolve notification preferences", zap.Error(err), zap.String("user_id", user.ID))
 				continue
 			}
-
-			var title, message string
-			if daysUntilExpiry < 0 {
-				// Sudah expired
-				daysAgo := -daysUntilExpiry
-				title = fmt.Sprintf("Masa Jabatan '%s' Sudah Berakhir", director.FullName)
-				if daysAgo == 0 {
-					message = fmt.Sprintf("Masa jabatan %s sebagai %s di %s sudah berakhir hari ini. Silakan perpanjang atau ganti pengurus tersebut.",
-						director.FullName, director.Position, companyName)
-				} else {
-					message = fmt.Sprintf("Masa jabatan %s sebagai %s di %s sudah berakhir %d hari yang lalu. Silakan perpanjang atau ganti pengurus tersebut.",
-						director.FullName, director.Position, companyName, daysAgo)
-				}
-			} else if daysUntilExpiry == 0 {
-				title = fmt.Sprintf("Masa Jabatan '%s' Akan Berakhir", director.FullName)
-				message = fmt.Sprintf("Masa jabatan %s sebagai %s di %s akan berakhir hari ini. Silakan perpanjang atau ganti pengurus tersebut.",
-					director.FullName, director.Position, companyName)
-			} else {
-				title = fmt.Sprintf("Masa Jabatan '%s' Akan Berakhir", director.FullName)
-				message = fmt.Sprintf("Masa jabatan %s sebagai %s di %s akan berakhir dalam %d hari. Silakan perpanjang atau ganti pengurus tersebut.",
-					director.FullName, director.Position, companyName, daysUntilExpiry)
+			stage, due := reminderStage(prefs, daysUntilExpiry)
+			if !due {
+				continue
 			}
-
-			// Use director ID as resource ID
-			_, err = uc.CreateNotification(
-				user.ID,
-				"director_term_expiry",
-				title,
-				message,
-				"director",
-				&directorID,
-			)
+			directorDue = true
+
+			created, reason, err := uc.deliverReminder(prefs, now, reminderDelivery{
+				userID:           user.ID,
+				notificationType: NotificationTypeDirectorTermExpiry,
+				resourceType:     "director",
+				resourceID:       director.ID,
+				dueDate:          endDate.Format("2006-01-02"),
+				stage:            stage,
+				title:            title,
+				message:          message,
+			})
 			if err != nil {
 				zapLog.Error("Failed to create notification for director term expiry", zap.Error(err),
 					zap.String("director_id", director.ID),
 					zap.String("user_id", user.ID))
-			} else {
-				notificationsCreated++
+				continue
+			}
+			if !created {
+				skipped[reason]++
+				continue
 			}
+			notificationsCreated++
+		}
+		if directorDue {
+			directorsFound++
 		}
 	}
 
+	zapLog.Info("Expiring director terms check completed",
+		zap.Int("system_threshold_days", thresholdDays),
+		zap.Int("directors_checked", len(directors)),
+		zap.Int("directors_due", directorsFound),
+		zap.Int("notifications_created", notificationsCreated),
+		zap.Any("skipped", skipped),
+	)
+
 	return notificationsCreated, directorsFound, nil
 }
+
+// reminderDelivery berisi data satu reminder untuk satu user
+type reminderDelivery struct {
+	userID           string
+	notificationType string
+	resourceType     string
+	resourceID       string
+	dueDate          string // Tanggal expired (YYYY-MM-DD)
+	stage            int    // Offset reminder, ReminderStageExpired jika sudah lewat
+	title            string
+	message          string
+}
+
+// Alasan reminder tidak dikirim (untuk logging scheduler)
+const (
+	reminderSkipDisabled    = "disabled"     // In-app dimatikan atau tipe notifikasi di-opt-out
+	reminderSkipQuietHours  = "quiet_hours"  // Ditunda, akan dikirim pada run scheduler berikutnya di luar quiet hours
+	reminderSkipAlreadySent = "already_sent" // Tahap ini sudah pernah dikirim
+)
+
+// deliverReminder menerapkan preferensi user lalu membuat notifikasi dan mencatat tahap reminder yang terkirim
+func (uc *notificationUseCase) deliverReminder(prefs *domain.EffectiveNotificationPreferences, now time.Time, r reminderDelivery) (bool, string, error) {
+	if !notificationAllowed(prefs, r.notificationType) {
+		return false, reminderSkipDisabled, nil
+	}
+
+	sent, err := uc.prefRepo.HasReminderLog(r.userID, r.resourceType, r.resourceID, r.dueDate, r.stage)
+	if err != nil {
+		return false, "", err
+	}
+	if sent {
+		return false, reminderSkipAlreadySent, nil
+	}
+
+	// Quiet hours dicek setelah dedup agar hitungan "quiet_hours" hanya untuk reminder yang benar-benar tertunda
+	if inQuietHours(prefs, now) {
+		return false, reminderSkipQuietHours, nil
+	}
+
+	// Kompatibilitas: resource yang belum pernah tercatat tapi sudah punya notifikasi unread dari scheduler lama
+	// dianggap sudah dikirim untuk tahap saat ini, agar tidak muncul duplikat setelah upgrade
+	tracked, err := uc.prefRepo.HasAnyReminderLog(r.userID, r.resourceType, r.resourceID, r.dueDate)
+	if err != nil {
+		return false, "", err
+	}
+	if !tracked && uc.hasLegacyUnreadReminder(r) {
+		if err := uc.prefRepo.CreateReminderLog(&domain.NotificationReminderLogModel{
+			UserID:           r.userID,
+			ResourceType:     r.resourceType,
+			ResourceID:       r.resourceID,
+			DueDate:          r.dueDate,
+			OffsetDays:       r.stage,
+			NotificationType: r.notificationType,
+			SentAt:           now,
+		}); err != nil {
+			return false, "", err
+		}
+		return false, reminderSkipAlreadySent, nil
+	}
+
+	resourceID := r.resourceID
+	notification, err := uc.CreateNotification(r.userID, r.notificationType, r.title, r.message, r.resourceType, &resourceID)
+	if err != nil {
+		return false, "", err
+	}
+
+	if err := uc.prefRepo.CreateReminderLog(&domain.NotificationReminderLogModel{
+		UserID:           r.userID,
+		ResourceType:     r.resourceType,
+		ResourceID:       r.resourceID,
+		DueDate:          r.dueDate,
+		OffsetDays:       r.stage,
+		NotificationType: r.notificationType,
+		NotificationID:   notification.ID,
+		SentAt:           now,
+	}); err != nil {
+		return true, "", err
+	}
+	return true, "", nil
+}
+
+// hasLegacyUnreadReminder mengecek notifikasi unread dengan status yang sama (akan/sudah expired) dari scheduler lama
+func (uc *notificationUseCase) hasLegacyUnreadReminder(r reminderDelivery) bool {
+	existingNotifs, err := uc.notifRepo.GetByUserID(r.userID, true, 100)
+	if err != nil {
+		return false
+	}
+	statusWord := "Akan"
+	if r.stage == ReminderStageExpired {
+		statusWord = "Sudah"
+	}
+	for _, notif := range existingNotifs {
+		if notif.Type == r.notificationType && notif.ResourceType == r.resourceType && notif.ResourceID != nil && *notif.ResourceID == r.resourceID &&
+			strings.Contains(notif.Title, statusWord) {
+			return true
+		}
+	}
+	return false
+}
+
+// openEscalation membuka rantai eskalasi untuk resource yang sudah expired (diabaikan jika sudah ada)
+// Waktu run ini dicatat sebagai notifikasi expired pertama, dasar perhitungan level eskalasi
+func (uc *notificationUseCase) openEscalation(resourceType, resourceID, resourceName, dueDate string, companyID *string, now time.Time) {
+	if uc.escalationRepo == nil {
+		return
+	}
+	_, created, err := uc.escalationRepo.OpenIfAbsent(&domain.NotificationEscalationModel{
+		ResourceType:    resourceType,
+		ResourceID:      resourceID,
+		DueDate:         dueDate,
+		ResourceName:    resourceName,
+		CompanyID:       companyID,
+		FirstNotifiedAt: now,
+	})
+	if err != nil {
+		logger.GetLogger().Warn("Failed to open notification escalation", zap.Error(err),
+			zap.String("resource_type", resourceType),
+			zap.String("resource_id", resourceID))
+		return
+	}
+	if created {
+		logger.GetLogger().Info("Notification escalation opened",
+			zap.String("resource_type", resourceType),
+			zap.String("resource_id", resourceID),
+			zap.String("due_date", dueDate))
+	}
+}
+
+// documentCompanyID menentukan company pemilik dokumen: company folder, atau company uploader jika folder tidak terasosiasi
+func (uc *notificationUseCase) documentCompanyID(doc *domain.DocumentModel) *string {
+	if doc.Folder != nil && doc.Folder.CompanyID != nil {
+		return doc.Folder.CompanyID
+	}
+	if uploader, err := uc.userRepo.GetByID(doc.UploaderID); err == nil && uploader != nil {
+		return uploader.CompanyID
+	}
+	return nil
+}
+
+// currentTime mengembalikan waktu sekarang (bisa di-override untuk testing)
+func (uc *notificationUseCase) currentTime() time.Time {
+	if uc.now != nil {
+		return uc.now()
+	}
+	return time.Now()
+}
+
+// parseDocumentExpiryDate membaca tanggal expired dokumen dari metadata (key expired_date atau expiry_date)
+func parseDocumentExpiryDate(metadata datatypes.JSON) *time.Time {
+	if len(metadata) == 0 {
+		return nil
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(metadata, &data); err != nil {
+		return nil
+	}
+	for _, key := range []string{"expired_date", "expiry_date"} {
+		value, ok := data[key].(string)
+		if !ok || value == "" {
+			continue
+		}
+		for _, layout := range []string{"2006-01-02", time.RFC3339} {
+			if parsed, err := time.Parse(layout, value); err == nil {
+				return &parsed
+			}
+		}
+		return nil
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/kvstore"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+	"github.com/repoareta/pedeve-dms-app/backend/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// setupTestNotificationUseCase creates a notification use case with in-memory database and a fixed clock
+func setupTestNotificationUseCase(t *testing.T) (*notificationUseCase, *gorm.DB, *time.Time) {
+	db := helpers.SetupTestDB(t)
+
+	err := db.AutoMigrate(
+		&domain.NotificationModel{},
+		&domain.NotificationPreferenceModel{},
+		&domain.NotificationReminderLogModel{},
+		&domain.NotificationEscalationModel{},
+		&domain.NotificationEscalationLevelModel{},
+	)
+	require.NoError(t, err)
+
+	uc := NewNotificationUseCaseWithDB(db).(*notificationUseCase)
+	now := time.Date(2025, 6, 2, 10, 0, 0, 0, preferenceLocation(defaultNotificationTimezone))
+	uc.now = func() time.Time { return now }
+
+	return uc, db, &now
+}
+
+// TestNotificationUseCase_CheckExpiringDocuments_Preferences tests which users receive which document reminders
+func TestNotificationUseCase_CheckExpiringDocuments_Preferences(t *testing.T) {
+	uc, db, now := setupTestNotificationUseCase(t)
+	defer helpers.CleanupTestDB(t, db)
+	prefUC := NewNotificationPreferenceUseCaseWithDB(db)
+
+	// Holding punya default 60/30/7/0, anak perusahaan mewarisi
+	holding := createTestCompanyForNotification(t, db, nil)
+	subsidiary := createTestCompanyForNotification(t, db, &holding.ID)
+	_, err := prefUC.UpdateCompanyPreferences(holding.ID, &domain.NotificationPreferenceRequest{
+		ReminderOffsets: []int{60, 30, 7, 0},
+	}, "")
+	require.NoError(t, err)
+
+	inherits := createTestUserForNotification(t, db, &subsidiary.ID)
+	override := createTestUserForNotification(t, db, &subsidiary.ID)
+	optedOut := createTestUserForNotification(t, db, &subsidiary.ID)
+	quiet := createTestUserForNotification(t, db, &subsidiary.ID)
+	legacyDisabled := createTestUserForNotification(t, db, &subsidiary.ID)
+	noCompany := createTestUserForNotification(t, db, nil)
+
+	_, err = prefUC.UpdateUserPreferences(override.ID, &domain.NotificationPreferenceRequest{ReminderOffsets: []int{7}})
+	require.NoError(t, err)
+	_, err = prefUC.UpdateUserPreferences(optedOut.ID, &domain.NotificationPreferenceRequest{
+		TypePreferences: map[string]bool{NotificationTypeDocumentExpiry: false},
+	})
+	require.NoError(t, err)
+	quietStart, quietEnd := "08:00", "12:00"
+	_, err = prefUC.UpdateUserPreferences(quiet.ID, &domain.NotificationPreferenceRequest{
+		QuietHoursStart: &quietStart,
+		QuietHoursEnd:   &quietEnd,
+	})
+	require.NoError(t, err)
+	legacySettings := &domain.NotificationSettingsModel{ID: uuid.GenerateUUID(), UserID: legacyDisabled.ID}
+	require.NoError(t, db.Create(legacySettings).Error)
+	require.NoError(t, db.Model(legacySettings).Update("in_app_enabled", false).Error)
+
+	docInherits := createTestDocumentForNotification(t, db, inherits.ID, now.AddDate(0, 0, 30))
+	docOverride := createTestDocumentForNotification(t, db, override.ID, now.AddDate(0, 0, 30))
+	createTestDocumentForNotification(t, db, optedOut.ID, now.AddDate(0, 0, 30))
+	docQuiet := createTestDocumentForNotification(t, db, quiet.ID, now.AddDate(0, 0, 30))
+	createTestDocumentForNotification(t, db, legacyDisabled.ID, now.AddDate(0, 0, 30))
+	createTestDocumentForNotification(t, db, noCompany.ID, now.AddDate(0, 0, 30))
+	docExpired := createTestDocumentForNotification(t, db, noCompany.ID, now.AddDate(0, 0, -2))
+
+	t.Run("First run applies effective preferences per user", func(t *testing.T) {
+		created, _, err := uc.CheckExpiringDocuments(14)
+		require.NoError(t, err)
+		assert.Equal(t, 2, created)
+
+		assertNotificationCount(t, db, inherits.ID, docInherits.ID, 1)
+		assertNotificationCount(t, db, override.ID, docOverride.ID, 0) // 30 hari belum masuk offset 7
+		assertNotificationCount(t, db, optedOut.ID, "", 0)
+		assertNotificationCount(t, db, quiet.ID, docQuiet.ID, 0) // Ditunda karena quiet hours
+		assertNotificationCount(t, db, legacyDisabled.ID, "", 0)
+		assertNotificationCount(t, db, noCompany.ID, docExpired.ID, 1) // Threshold sistem 14 hari hanya kena dokumen expired
+
+		var notif domain.NotificationModel
+		require.NoError(t, db.Where("user_id = ?", noCompany.ID).First(&notif).Error)
+		assert.Contains(t, notif.Title, "Sudah Expired")
+	})
+
+	t.Run("Rerun does not duplicate sent stages", func(t *testing.T) {
+		created, _, err := uc.CheckExpiringDocuments(14)
+		require.NoError(t, err)
+		assert.Equal(t, 0, created)
+		assertNotificationCount(t, db, inherits.ID, docInherits.ID, 1)
+	})
+
+	t.Run("Deferred reminder is sent after quiet hours", func(t *testing.T) {
+		*now = now.Add(3 * time.Hour)
+		created, _, err := uc.CheckExpiringDocuments(14)
+		require.NoError(t, err)
+		assert.Equal(t, 1, created)
+		assertNotificationCount(t, db, quiet.ID, docQuiet.ID, 1)
+	})
+
+	t.Run("Next stage is sent once the offset is reached", func(t *testing.T) {
+		*now = now.AddDate(0, 0, 25) // Sisa 5 hari: tahap 7
+		_, _, err := uc.CheckExpiringDocuments(14)
+		require.NoError(t, err)
+
+		assertNotificationCount(t, db, inherits.ID, docInherits.ID, 2)
+		assertNotificationCount(t, db, override.ID, docOverride.ID, 1)
+		assertNotificationCount(t, db, optedOut.ID, "", 0)
+
+		var stages []int
+		require.NoError(t, db.Model(&domain.NotificationReminderLogModel{}).
+			Where("user_id = ? AND resource_id = ?", inherits.ID, docInherits.ID).
+			Order("offset_days DESC").Pluck("offset_days", &stages).Error)
+		assert.Equal(t, []int{30, 7}, stages)
+	})
+}
+
+// TestNotificationUseCase_CheckExpiringDirectorTerms_Preferences tests director term reminders per company user
+func TestNotificationUseCase_CheckExpiringDirectorTerms_Preferences(t *testing.T) {
+	uc, db, now := setupTestNotificationUseCase(t)
+	defer helpers.CleanupTestDB(t, db)
+	prefUC := NewNotificationPreferenceUseCaseWithDB(db)
+
+	company := createTestCompanyForNotification(t, db, nil)
+	receiver := createTestUserForNotification(t, db, &company.ID)
+	optedOut := createTestUserForNotification(t, db, &company.ID)
+	_, err := prefUC.UpdateUserPreferences(optedOut.ID, &domain.NotificationPreferenceRequest{
+		TypePreferences: map[string]bool{NotificationTypeDirectorTermExpiry: false},
+	})
+	require.NoError(t, err)
+
+	endDate := now.AddDate(0, 0, 10)
+	director := &domain.DirectorModel{
+		ID:        uuid.GenerateUUID(),
+		CompanyID: company.ID,
+		Position:  "Direktur Utama",
+		FullName:  "Budi Santoso",
+		EndDate:   &endDate,
+	}
+	require.NoError(t, db.Create(director).Error)
+
+	created, directorsFound, err := uc.CheckExpiringDirectorTerms(14)
+	require.NoError(t, err)
+	assert.Equal(t, 1, created)
+	assert.Equal(t, 1, directorsFound)
+	assertNotificationCount(t, db, receiver.ID, director.ID, 1)
+	assertNotificationCount(t, db, optedOut.ID, director.ID, 0)
+}
+
+func createTestCompanyForNotification(t *testing.T, db *gorm.DB, parentID *string) *domain.CompanyModel {
+	uniqueCode := "NTF" + uuid.GenerateUUID()[:8]
+	level := 0
+	if parentID != nil {
+		level = 1
+	}
+	company := &domain.CompanyModel{
+		ID:       uuid.GenerateUUID(),
+		Code:     uniqueCode,
+		Name:     "Test Company " + uniqueCode,
+		ParentID: parentID,
+		Level:    level,
+		IsActive: true,
+	}
+	require.NoError(t, repository.NewCompanyRepositoryWithDB(db).Create(company))
+	return company
+}
+
+func createTestUserForNotification(t *testing.T, db *gorm.DB, companyID *string) *domain.UserModel {
+	uniqueID := uuid.GenerateUUID()[:8]
+	user := &domain.UserModel{
+		ID:        uuid.GenerateUUID(),
+		Username:  "notifuser" + uniqueID,
+		Email:     "notif" + uniqueID + "@example.com",
+		Password:  "hashedpassword",
+		CompanyID: companyID,
+		IsActive:  true,
+	}
+	require.NoError(t, db.Create(user).Error)
+	return user
+}
+
+func createTestDocumentForNotification(t *testing.T, db *gorm.DB, uploaderID string, expiredDate time.Time) *domain.DocumentModel {
+	doc := &domain.DocumentModel{
+		ID:         uuid.GenerateUUID(),
+		Name:       "Dokumen " + uuid.GenerateUUID()[:8],
+		FileName:   "dokumen.pdf",
+		FilePath:   "/uploads/dokumen.pdf",
+		MimeType:   "application/pdf",
+		Size:       1024,
+		Metadata:   datatypes.JSON(fmt.Sprintf(`{"expired_date":"%s"}`, expiredDate.Format("2006-01-02"))),
+		UploaderID: uploaderID,
+	}
+	require.NoError(t, db.Create(doc).Error)
+	return doc
+}
+
+// assertNotificationCount mengecek jumlah notifikasi user (resourceID kosong = semua resource)
+func assertNotificationCount(t *testing.T, db *gorm.DB, userID, resourceID string, expected int64) {
+	t.Helper()
+	query := db.Model(&domain.NotificationModel{}).Where("user_id = ?", userID)
+	if resourceID != "" {
+		query = query.Where("resource_id = ?", resourceID)
+	}
+	var count int64
+	require.NoError(t, query.Count(&count).Error)
+	assert.Equal(t, expected, count)
+}
+
+func TestNotificationUseCase_UnreadCountCacheInStore(t *testing.T) {
+	uc, db, _ := setupTestNotificationUseCase(t)
+	defer helpers.CleanupTestDB(t, db)
+
+	store := kvstore.NewMemoryStore()
+	kvstore.SetStore(store)
+	defer kvstore.SetStore(kvstore.NewMemoryStore())
+
+	company := createTestCompanyForNotification(t, db, nil)
+	user := createTestUserForNotification(t, db, &company.ID)
+
+	_, err := uc.CreateNotification(user.ID, "info", "A", "a", "document", nil)
+	require.NoError(t, err)
+	count, err := uc.GetUnreadCount(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	cached, err := store.Get(context.Background(), unreadCountKeyPrefix+user.ID+":user")
+	require.NoError(t, err)
+	assert.Equal(t, "1", cached)
+
+	allCount, err := uc.GetUnreadCountWithRBAC(user.ID, "superadmin", nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), allCount)
+
+	// Notifikasi baru menghapus cache user tersebut (termasuk cache global miliknya)
+	_, err = uc.CreateNotification(user.ID, "info", "B", "b", "document", nil)
+	require.NoError(t, err)
+	count, err = uc.GetUnreadCount(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	require.NoError(t, uc.MarkAllAsRead(user.ID))
+	count, err = uc.GetUnreadCount(user.ID)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,476 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/database"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// maxOwnershipPaths membatasi jumlah rantai kepemilikan pada response effective ownership
+const maxOwnershipPaths = 50
+
+var ErrOwnershipCycle = errors.New("struktur kepemilikan membentuk siklus")
+
+// ownershipEdge adalah kepemilikan langsung Holder atas Held (persen 0-100)
+type ownershipEdge struct {
+	Holder       string
+	Held         string
+	Percent      float64
+	IsMainParent bool
+}
+
+// ownershipGraph adalah graph kepemilikan antar company; pemegang saham eksternal tidak ikut karena tidak bisa membentuk rantai
+type ownershipGraph struct {
+	holdings map[string][]ownershipEdge // Holder -> company yang dimiliki
+}
+
+func newOwnershipGraph(shareholders []domain.ShareholderModel) *ownershipGraph {
+	graph := &ownershipGraph{holdings: make(map[string][]ownershipEdge)}
+	for _, sh := range shareholders {
+		if sh.ShareholderCompanyID == nil || *sh.ShareholderCompanyID == "" {
+			continue
+		}
+		graph.add(ownershipEdge{Holder: *sh.ShareholderCompanyID, Held: sh.CompanyID, Percent: sh.OwnershipPercent, IsMainParent: sh.IsMainParent})
+	}
+	return graph
+}
+
+func (g *ownershipGraph) add(edge ownershipEdge) {
+	g.holdings[edge.Holder] = append(g.holdings[edge.Holder], edge)
+}
+
+// replaceShareholders mengganti pemegang saham company (dipakai untuk memvalidasi update sebelum disimpan)
+func (g *ownershipGraph) replaceShareholders(companyID string, shareholders []domain.ShareholderRequest) {
+	for holder, edges := range g.holdings {
+		kept := edges[:0]
+		for _, edge := range edges {
+			if edge.Held != companyID {
+				kept = append(kept, edge)
+			}
+		}
+		g.holdings[holder] = kept
+	}
+	for _, sh := range shareholders {
+		if sh.ShareholderCompanyID != nil && *sh.ShareholderCompanyID != "" {
+			g.add(ownershipEdge{Holder: *sh.ShareholderCompanyID, Held: companyID, Percent: sh.OwnershipPercent, IsMainParent: sh.IsMainParent})
+		}
+	}
+}
+
+// reaches true jika from memiliki to secara langsung maupun tidak langsung
+func (g *ownershipGraph) reaches(from, to string) bool {
+	visited := map[string]bool{from: true}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, edge := range g.holdings[current] {
+			if edge.Held == to {
+				return true
+			}
+			if !visited[edge.Held] {
+				visited[edge.Held] = true
+				queue = append(queue, edge.Held)
+			}
+		}
+	}
+	return false
+}
+
+// cycles mengembalikan kelompok company yang saling memiliki (strongly connected component Tarjan)
+func (g *ownershipGraph) cycles() [][]string {
+	index := 0
+	indices := make(map[string]int)
+	lowLinks := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var result [][]string
+
+	var strongConnect func(node string)
+	strongConnect = func(node string) {
+		indices[node] = index
+		lowLinks[node] = index
+		index++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		selfLoop := false
+		for _, edge := range g.holdings[node] {
+			if edge.Held == node {
+				selfLoop = true
+			}
+			if _, visited := indices[edge.Held]; !visited {
+				strongConnect(edge.Held)
+				lowLinks[node] = min(lowLinks[node], lowLinks[edge.Held])
+			} else if onStack[edge.Held] {
+				lowLinks[node] = min(lowLinks[node], indices[edge.Held])
+			}
+		}
+
+		if lowLinks[node] == indices[node] {
+			var component []string
+			for {
+				last := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[last] = false
+				component = append(component, last)
+				if last == node {
+					break
+				}
+			}
+			if len(component) > 1 || selfLoop {
+				sort.Strings(component)
+				result = append(result, component)
+			}
+		}
+	}
+
+	holders := make([]string, 0, len(g.holdings))
+	for holder := range g.holdings {
+		holders = append(holders, holder)
+	}
+	sort.Strings(holders)
+	for _, holder := range holders {
+		if _, visited := indices[holder]; !visited {
+			strongConnect(holder)
+		}
+	}
+	return result
+}
+
+// walk menelusuri setiap rantai kepemilikan sederhana dari root. visit dipanggil untuk setiap langkah
+// dengan rantai edge sejauh ini dan porsi efektif rantai tersebut (0-1). Rantai yang kembali ke company
+// yang sudah dilewati (siklus) dihentikan.
+func (g *ownershipGraph) walk(root string, visit func(path []ownershipEdge, share float64)) {
+	onPath := map[string]bool{root: true}
+	var path []ownershipEdge
+	var step func(node string, share float64)
+	step = func(node string, share float64) {
+		for _, edge := range g.holdings[node] {
+			if onPath[edge.Held] {
+				continue
+			}
+			path = append(path, edge)
+			next := share * edge.Percent / 100
+			visit(path, next)
+			onPath[edge.Held] = true
+			step(edge.Held, next)
+			onPath[edge.Held] = false
+			path = path[:len(path)-1]
+		}
+	}
+	step(root, 1)
+}
+
+func roundOwnership(value float64) float64 {
+	return math.Round(value*10000) / 10000
+}
+
+// OwnershipGraphUseCase interface untuk struktur kepemilikan grup (graph, siklus, kepemilikan efektif)
+type OwnershipGraphUseCase interface {
+	GetOwnershipGraph(rootCompanyID string, includeExternal bool) (*domain.OwnershipGraphResponse, error)
+	GetEffectiveOwnership(fromCompanyID, toCompanyID string) (*domain.EffectiveOwnershipResponse, error)
+}
+
+type ownershipGraphUseCase struct {
+	companyRepo     repository.CompanyRepository
+	shareholderRepo repository.ShareholderRepository
+}
+
+// NewOwnershipGraphUseCaseWithDB membuat ownership graph use case dengan DB yang di-inject (untuk testing)
+func NewOwnershipGraphUseCaseWithDB(db *gorm.DB) OwnershipGraphUseCase {
+	return &ownershipGraphUseCase{
+		companyRepo:     repository.NewCompanyRepositoryWithDB(db),
+		shareholderRepo: repository.NewShareholderRepositoryWithDB(db),
+	}
+}
+
+// NewOwnershipGraphUseCase membuat ownership graph use case dengan default DB
+func NewOwnershipGraphUseCase() OwnershipGraphUseCase {
+	return NewOwnershipGraphUseCaseWithDB(database.GetDB())
+}
+
+// externalShareholderNodeID menggabungkan pemegang saham eksternal yang sama (identitas, atau nama jika identitas kosong)
+func externalShareholderNodeID(sh domain.ShareholderModel) string {
+	key := strings.TrimSpace(sh.IdentityNumber)
+	if key == "" {
+		key = strings.ToLower(strings.TrimSpace(sh.Name))
+	}
+	return "external:" + key
+}
+
+func (uc *ownershipGraphUseCase) GetOwnershipGraph(rootCompanyID string, includeExternal bool) (*domain.OwnershipGraphResponse, error) {
+	companies, err := uc.companyRepo.GetAll(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get companies: %w", err)
+	}
+	byID := make(map[string]*domain.CompanyModel, len(companies))
+	for i := range companies {
+		byID[companies[i].ID] = &companies[i]
+	}
+
+	// Scope: root dan seluruh turunannya (hierarki parent), atau semua company aktif
+	var scope []domain.CompanyModel
+	if rootCompanyID != "" {
+		root := byID[rootCompanyID]
+		if root == nil {
+			return nil, fmt.Errorf("root company: %w", gorm.ErrRecordNotFound)
+		}
+		descendants, err := uc.companyRepo.GetDescendants(rootCompanyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get company descendants: %w", err)
+		}
+		scope = append([]domain.CompanyModel{*root}, descendants...)
+	} else {
+		for _, company := range companies {
+			if company.IsActive {
+				scope = append(scope, company)
+			}
+		}
+	}
+	sort.SliceStable(scope, func(i, j int) bool {
+		if scope[i].Level != scope[j].Level {
+			return scope[i].Level < scope[j].Level
+		}
+		return scope[i].Code < scope[j].Code
+	})
+	inScope := make(map[string]bool, len(scope))
+	for _, company := range scope {
+		inScope[company.ID] = true
+	}
+
+	shareholders, err := uc.shareholderRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shareholders: %w", err)
+	}
+	graph := newOwnershipGraph(shareholders)
+
+	response := &domain.OwnershipGraphResponse{
+		RootCompanyID: rootCompanyID,
+		Nodes:         make([]domain.OwnershipGraphNode, 0, len(scope)),
+		Edges:         []domain.OwnershipGraphEdge{},
+		Cycles:        [][]string{},
+		Issues:        []domain.OwnershipIssue{},
+	}
+	nodeIndex := make(map[string]int)
+	addNode := func(node domain.OwnershipGraphNode) {
+		if _, exists := nodeIndex[node.ID]; !exists {
+			nodeIndex[node.ID] = len(response.Nodes)
+			response.Nodes = append(response.Nodes, node)
+		}
+	}
+	for _, company := range scope {
+		addNode(domain.OwnershipGraphNode{ID: company.ID, Type: domain.OwnershipNodeCompany, Code: company.Code, Name: company.Name, Level: company.Level, InScope: true})
+	}
+
+	totals := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, sh := range shareholders {
+		if !inScope[sh.CompanyID] {
+			continue
+		}
+		totals[sh.CompanyID] += sh.OwnershipPercent
+		counts[sh.CompanyID]++
+
+		var from string
+		switch {
+		case sh.ShareholderCompanyID != nil && *sh.ShareholderCompanyID != "":
+			from = *sh.ShareholderCompanyID
+			holder := byID[from]
+			if holder == nil {
+				continue // Referensi ke company yang sudah dihapus
+			}
+			addNode(domain.OwnershipGraphNode{ID: holder.ID, Type: domain.OwnershipNodeCompany, Code: holder.Code, Name: holder.Name, Level: holder.Level, InScope: inScope[holder.ID]})
+		case includeExternal:
+			from = externalShareholderNodeID(sh)
+			addNode(domain.OwnershipGraphNode{ID: from, Type: domain.OwnershipNodeExternal, Name: sh.Name})
+		default:
+			continue
+		}
+		response.Edges = append(response.Edges, domain.OwnershipGraphEdge{
+			From:         from,
+			To:           sh.CompanyID,
+			Percent:      roundOwnership(sh.OwnershipPercent),
+			IsMainParent: sh.IsMainParent,
+		})
+	}
+
+	for _, company := range scope {
+		if counts[company.ID] == 0 {
+			continue
+		}
+		total := roundOwnership(totals[company.ID])
+		response.Nodes[nodeIndex[company.ID]].ShareholderTotal = &total
+		if total != 0 && math.Abs(total-100) > ownershipTolerance {
+			response.Issues = append(response.Issues, domain.OwnershipIssue{
+				CompanyID: company.ID,
+				Code:      company.Code,
+				Message:   fmt.Sprintf("total kepemilikan pemegang saham %.4f%%, seharusnya 100%%", total),
+			})
+		}
+	}
+
+	// Kepemilikan efektif root atas setiap company di scope
+	if rootCompanyID != "" {
+		effective := map[string]float64{rootCompanyID: 1}
+		graph.walk(rootCompanyID, func(path []ownershipEdge, share float64) {
+			effective[path[len(path)-1].Held] += share
+		})
+		for _, company := range scope {
+			value := roundOwnership(effective[company.ID] * 100)
+			response.Nodes[nodeIndex[company.ID]].EffectiveOwnership = &value
+		}
+	}
+
+	for _, cycle := range graph.cycles() {
+		touchesScope := false
+		codes := make([]string, 0, len(cycle))
+		for _, id := range cycle {
+			touchesScope = touchesScope || inScope[id]
+			if company := byID[id]; company != nil {
+				codes = append(codes, company.Code)
+			} else {
+				codes = append(codes, id)
+			}
+		}
+		if touchesScope {
+			response.Cycles = append(response.Cycles, codes)
+		}
+	}
+
+	return response, nil
+}
+
+func (uc *ownershipGraphUseCase) GetEffectiveOwnership(fromCompanyID, toCompanyID string) (*domain.EffectiveOwnershipResponse, error) {
+	from, err := uc.companyRepo.GetByID(fromCompanyID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := uc.companyRepo.GetByID(toCompanyID)
+	if err != nil {
+		return nil, err
+	}
+	companies, err := uc.companyRepo.GetAll(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get companies: %w", err)
+	}
+	codes := make(map[string]string, len(companies))
+	for _, company := range companies {
+		codes[company.ID] = company.Code
+	}
+	shareholders, err := uc.shareholderRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shareholders: %w", err)
+	}
+
+	response := &domain.EffectiveOwnershipResponse{
+		FromCompanyID: from.ID,
+		FromCode:      from.Code,
+		ToCompanyID:   to.ID,
+		ToCode:        to.Code,
+		Paths:         []domain.EffectiveOwnershipPath{},
+	}
+	if from.ID == to.ID {
+		response.DirectPercent = 100
+		response.EffectivePercent = 100
+		return response, nil
+	}
+
+	total := 0.0
+	newOwnershipGraph(shareholders).walk(from.ID, func(path []ownershipEdge, share float64) {
+		if path[len(path)-1].Held != to.ID {
+			return
+		}
+		total += share
+		if len(path) == 1 {
+			response.DirectPercent += path[0].Percent
+		}
+		item := domain.EffectiveOwnershipPath{Codes: []string{codes[from.ID]}, Percent: roundOwnership(share * 100)}
+		for _, edge := range path {
+			item.Codes = append(item.Codes, codes[edge.Held])
+			item.Percents = append(item.Percents, roundOwnership(edge.Percent))
+		}
+		response.Paths = append(response.Paths, item)
+	})
+	response.DirectPercent = roundOwnership(response.DirectPercent)
+	response.EffectivePercent = roundOwnership(total * 100)
+
+	sort.SliceStable(response.Paths, func(i, j int) bool {
+		return response.Paths[i].Percent > response.Paths[j].Percent
+	})
+	if len(response.Paths) > maxOwnershipPaths {
+		response.Paths = response.Paths[:maxOwnershipPaths]
+		response.PathsTruncated = true
+	}
+	return response, nil
+}
+
+// RenderOwnershipGraphDOT merender graph kepemilikan ke format Graphviz DOT
+func RenderOwnershipGraphDOT(graph *domain.OwnershipGraphResponse) string {
+	quote := func(value string) string {
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph ownership {\n")
+	b.WriteString("  rankdir=TB;\n")
+	b.WriteString("  node [shape=box, style=rounded, fontname=\"Helvetica\"];\n")
+	b.WriteString("  edge [fontname=\"Helvetica\", fontsize=10];\n")
+	for _, node := range graph.Nodes {
+		label := node.Name
+		if node.Code != "" {
+			label = node.Code + "\n" + node.Name
+		}
+		if node.EffectiveOwnership != nil {
+			label += fmt.Sprintf("\nefektif %.2f%%", *node.EffectiveOwnership)
+		}
+		attrs := []string{"label=" + quote(label)}
+		if node.Type == domain.OwnershipNodeExternal {
+			attrs = append(attrs, "shape=ellipse")
+		}
+		if !node.InScope {
+			attrs = append(attrs, "style=\"rounded,dashed\"")
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", quote(node.ID), strings.Join(attrs, ", "))
+	}
+	for _, edge := range graph.Edges {
+		attrs := []string{"label=" + quote(fmt.Sprintf("%.2f%%", edge.Percent))}
+		if edge.IsMainParent {
+			attrs = append(attrs, "penwidth=2")
+		}
+		fmt.Fprintf(&b, "  %s -> %s [%s];\n", quote(edge.From), quote(edge.To), strings.Join(attrs, ", "))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// validateOwnershipStructure memvalidasi pemegang saham baru sebuah company: total 100% dan tidak membentuk siklus
+func validateOwnershipStructure(shareholderRepo repository.ShareholderRepository, companyID string, shareholders []domain.ShareholderRequest) error {
+	if err := joinValidationIssues(validateShareholderRequests(shareholders)); err != nil {
+		return err
+	}
+
+	existing, err := shareholderRepo.GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to get shareholders: %w", err)
+	}
+	graph := newOwnershipGraph(existing)
+	graph.replaceShareholders(companyID, shareholders)
+	for _, sh := range shareholders {
+		if sh.ShareholderCompanyID == nil || *sh.ShareholderCompanyID == "" {
+			continue
+		}
+		holder := *sh.ShareholderCompanyID
+		if holder == companyID || graph.reaches(companyID, holder) {
+			return fmt.Errorf("%w: %s sudah dimiliki (langsung atau tidak langsung) oleh company ini", ErrOwnershipCycle, sh.Name)
+		}
+	}
+	return nil
+}
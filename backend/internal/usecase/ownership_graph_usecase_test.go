@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"github.com/repoareta/pedeve-dms-app/backend/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func createTestShareholding(t *testing.T, db *gorm.DB, holder *domain.CompanyModel, held *domain.CompanyModel, name string, percent float64) {
+	shareholder := &domain.ShareholderModel{
+		ID:               uuid.GenerateUUID(),
+		CompanyID:        held.ID,
+		Type:             "Individu",
+		Name:             name,
+		OwnershipPercent: percent,
+	}
+	if holder != nil {
+		shareholder.ShareholderCompanyID = &holder.ID
+		shareholder.Type = "Badan Hukum"
+		shareholder.Name = holder.Name
+	}
+	require.NoError(t, db.Create(shareholder).Error)
+}
+
+func TestOwnershipGraph(t *testing.T) {
+	db := helpers.SetupTestDB(t)
+	defer helpers.CleanupTestDB(t, db)
+
+	holding := createTestCompanyForNotification(t, db, nil)
+	a := createTestCompanyForNotification(t, db, &holding.ID)
+	b := createTestCompanyForNotification(t, db, &a.ID)
+
+	// Holding 60% A; A 50% B, Holding 20% B langsung, individu 30% B
+	createTestShareholding(t, db, holding, a, "", 60)
+	createTestShareholding(t, db, nil, a, "Publik", 40)
+	createTestShareholding(t, db, a, b, "", 50)
+	createTestShareholding(t, db, holding, b, "", 20)
+	createTestShareholding(t, db, nil, b, "Budi", 30)
+
+	uc := NewOwnershipGraphUseCaseWithDB(db)
+
+	t.Run("Effective ownership", func(t *testing.T) {
+		ownership, err := uc.GetEffectiveOwnership(holding.ID, b.ID)
+		require.NoError(t, err)
+		assert.InDelta(t, 20, ownership.DirectPercent, 0.0001)
+		assert.InDelta(t, 50, ownership.EffectivePercent, 0.0001)
+		require.Len(t, ownership.Paths, 2)
+		assert.Equal(t, []string{holding.Code, a.Code, b.Code}, ownership.Paths[0].Codes)
+		assert.InDelta(t, 30, ownership.Paths[0].Percent, 0.0001)
+
+		none, err := uc.GetEffectiveOwnership(b.ID, holding.ID)
+		require.NoError(t, err)
+		assert.Zero(t, none.EffectivePercent)
+	})
+
+	t.Run("Graph", func(t *testing.T) {
+		graph, err := uc.GetOwnershipGraph(holding.ID, true)
+		require.NoError(t, err)
+		assert.Len(t, graph.Nodes, 5) // 3 company + 2 eksternal
+		assert.Len(t, graph.Edges, 5)
+		assert.Empty(t, graph.Cycles)
+		assert.Empty(t, graph.Issues)
+		for _, node := range graph.Nodes {
+			if node.ID == b.ID {
+				assert.InDelta(t, 50, *node.EffectiveOwnership, 0.0001)
+			}
+		}
+
+		withoutExternal, err := uc.GetOwnershipGraph(holding.ID, false)
+		require.NoError(t, err)
+		assert.Len(t, withoutExternal.Nodes, 3)
+
+		dot := RenderOwnershipGraphDOT(graph)
+		assert.True(t, strings.HasPrefix(dot, "digraph ownership {"))
+		assert.Contains(t, dot, `"`+holding.ID+`" -> "`+a.ID+`" [label="60.00%"`)
+	})
+
+	t.Run("Update validation", func(t *testing.T) {
+		companyUC := NewCompanyUseCaseWithDB(db)
+		update := func(shareholders []domain.ShareholderRequest) error {
+			_, err := companyUC.UpdateCompanyFull(holding.ID, &domain.CompanyUpdateRequest{Name: holding.Name, Status: "Aktif", Shareholders: shareholders})
+			return err
+		}
+
+		err := update([]domain.ShareholderRequest{{Name: "B", Type: "Badan Hukum", ShareholderCompanyID: &b.ID, OwnershipPercent: 100}})
+		assert.ErrorIs(t, err, ErrOwnershipCycle)
+		err = update([]domain.ShareholderRequest{{Name: "X", Type: "Individu", OwnershipPercent: 60}})
+		assert.ErrorContains(t, err, "harus 100%")
+		assert.NoError(t, update([]domain.ShareholderRequest{{Name: "X", Type: "Individu", OwnershipPercent: 100}}))
+	})
+
+	t.Run("Legacy cycle detected", func(t *testing.T) {
+		createTestShareholding(t, db, b, a, "", 0)
+		graph, err := uc.GetOwnershipGraph("", false)
+		require.NoError(t, err)
+		require.Len(t, graph.Cycles, 1)
+		assert.ElementsMatch(t, []string{a.Code, b.Code}, graph.Cycles[0])
+
+		// Rantai siklus tidak menghasilkan loop tak hingga
+		ownership, err := uc.GetEffectiveOwnership(holding.ID, b.ID)
+		require.NoError(t, err)
+		assert.InDelta(t, 50, ownership.EffectivePercent, 0.0001)
+	})
+}
@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"gorm.io/gorm"
+)
+
+// Definisi laporan performa (RKAP vs Realisasi) yang dipakai bersama oleh export Excel dan PDF.
+// Menambah/mengubah baris cukup di sini supaya kedua format selalu sinkron.
+
+// Key section laporan performa
+const (
+	performanceSectionBalanceSheet = "balance_sheet"
+	performanceSectionProfitLoss   = "profit_loss"
+	performanceSectionCashflow     = "cashflow"
+	performanceSectionRatio        = "ratio"
+)
+
+// performanceSection adalah satu sheet Excel / satu halaman PDF
+type performanceSection struct {
+	key       string
+	sheetName string
+	title     string // Judul tanpa periode, contoh: "Neraca (Balance Sheet)"
+	items     []tableItem
+}
+
+// periodTitle menghasilkan judul lengkap dengan periode export
+func (s performanceSection) periodTitle(startPeriod, endPeriod string) string {
+	return fmt.Sprintf("%s - Periode %s - %s", s.title, startPeriod, endPeriod)
+}
+
+// currencyItem membuat baris bernilai rupiah; RKAP nil dianggap 0
+func currencyItem(label, field string, value func(*domain.FinancialReportModel) int64) tableItem {
+	return tableItem{
+		label: label,
+		field: field,
+		getRkap: func(r *domain.FinancialReportModel) int64 {
+			if r == nil {
+				return 0
+			}
+			return value(r)
+		},
+		getReal: func(r domain.FinancialReportModel) int64 {
+			return value(&r)
+		},
+	}
+}
+
+// ratioItem membuat baris rasio (persen / kali)
+func ratioItem(label, field string, value func(*domain.FinancialReportModel) float64) tableItem {
+	return tableItem{
+		label:   label,
+		field:   field,
+		isRatio: true,
+		getRkapF: func(r *domain.FinancialReportModel) float64 {
+			if r == nil {
+				return 0
+			}
+			return value(r)
+		},
+		getRealF: func(r domain.FinancialReportModel) float64 {
+			return value(&r)
+		},
+	}
+}
+
+// performanceSections mengembalikan seluruh section sesuai urutan sheet/halaman
+func performanceSections() []performanceSection {
+	return []performanceSection{
+		{
+			key:       performanceSectionBalanceSheet,
+			sheetName: "Balance Sheet",
+			title:     "Neraca (Balance Sheet)",
+			items: []tableItem{
+				currencyItem("A. Aset Lancar", "current_assets", func(r *domain.FinancialReportModel) int64 { return r.CurrentAssets }),
+				currencyItem("B. Aset Tidak Lancar", "non_current_assets", func(r *domain.FinancialReportModel) int64 { return r.NonCurrentAssets }),
+				currencyItem("C. Liabilitas Jangka Pendek", "short_term_liabilities", func(r *domain.FinancialReportModel) int64 { return r.ShortTermLiabilities }),
+				currencyItem("D. Liabilitas Jangka Panjang", "long_term_liabilities", func(r *domain.FinancialReportModel) int64 { return r.LongTermLiabilities }),
+				currencyItem("E. Ekuitas", "equity", func(r *domain.FinancialReportModel) int64 { return r.Equity }),
+			},
+		},
+		{
+			key:       performanceSectionProfitLoss,
+			sheetName: "Profit & Loss",
+			title:     "Laba Rugi (Profit & Loss)",
+			items: []tableItem{
+				currencyItem("A. Revenue", "revenue", func(r *domain.FinancialReportModel) int64 { return r.Revenue }),
+				currencyItem("B. Beban Usaha", "operating_expenses", func(r *domain.FinancialReportModel) int64 { return r.OperatingExpenses }),
+				currencyItem("C. Laba Usaha", "operating_profit", func(r *domain.FinancialReportModel) int64 { return r.OperatingProfit }),
+				currencyItem("D. Pendapatan Lain-Lain", "other_income", func(r *domain.FinancialReportModel) int64 { return r.OtherIncome }),
+				currencyItem("E. Tax", "tax", func(r *domain.FinancialReportModel) int64 { return r.Tax }),
+				currencyItem("F. Laba Bersih", "net_profit", func(r *domain.FinancialReportModel) int64 { return r.NetProfit }),
+			},
+		},
+		{
+			key:       performanceSectionCashflow,
+			sheetName: "Cashflow",
+			title:     "Cashflow",
+			items: []tableItem{
+				currencyItem("A. Arus kas bersih dari operasi", "operating_cashflow", func(r *domain.FinancialReportModel) int64 { return r.OperatingCashflow }),
+				currencyItem("B. Arus kas bersih dari investasi", "investing_cashflow", func(r *domain.FinancialReportModel) int64 { return r.InvestingCashflow }),
+				currencyItem("C. Arus kas bersih dari pendanaan", "financing_cashflow", func(r *domain.FinancialReportModel) int64 { return r.FinancingCashflow }),
+				currencyItem("D. Saldo Akhir", "ending_balance", func(r *domain.FinancialReportModel) int64 { return r.EndingBalance }),
+			},
+		},
+		{
+			key:       performanceSectionRatio,
+			sheetName: "Ratio",
+			title:     "Rasio Keuangan (%)",
+			items: []tableItem{
+				ratioItem("ROE (Return on Equity)", "roe", func(r *domain.FinancialReportModel) float64 { return r.ROE }),
+				ratioItem("ROI (Return on Investment)", "roi", func(r *domain.FinancialReportModel) float64 { return r.ROI }),
+				ratioItem("Rasio Lancar", "current_ratio", func(r *domain.FinancialReportModel) float64 { return r.CurrentRatio }),
+				ratioItem("Rasio Kas", "cash_ratio", func(r *domain.FinancialReportModel) float64 { return r.CashRatio }),
+			},
+		},
+	}
+}
+
+// getPerformanceSection mengambil section berdasarkan key (panic jika key tidak terdaftar, kesalahan programmer)
+func getPerformanceSection(key string) performanceSection {
+	for _, section := range performanceSections() {
+		if section.key == key {
+			return section
+		}
+	}
+	panic(fmt.Sprintf("performance section %q is not defined", key))
+}
+
+// performanceVariance menghitung selisih Realisasi - RKAP dan persentasenya terhadap RKAP.
+// rkap adalah target bulan yang sama dengan report (lihat performanceMonthlyRKAP).
+// ok=false jika salah satu sisi tidak tersedia; persen nil jika RKAP 0.
+func performanceVariance(item tableItem, rkap, report *domain.FinancialReportModel) (diff float64, percent *float64, ok bool) {
+	if rkap == nil || report == nil {
+		return 0, nil, false
+	}
+	var rkapValue, realValue float64
+	if item.isRatio {
+		if item.getRkapF == nil || item.getRealF == nil {
+			return 0, nil, false
+		}
+		rkapValue, realValue = item.getRkapF(rkap), item.getRealF(*report)
+	} else {
+		if item.getRkap == nil || item.getReal == nil {
+			return 0, nil, false
+		}
+		rkapValue, realValue = float64(item.getRkap(rkap)), float64(item.getReal(*report))
+	}
+	diff = realValue - rkapValue
+	if rkapValue != 0 {
+		p := diff / absFloat(rkapValue) * 100
+		percent = &p
+	}
+	return diff, percent, true
+}
+
+func absFloat(value float64) float64 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+// performanceMonthlyRKAP menurunkan target RKAP satu bulan dari RKAP tahunan: metrik arus (laba rugi, arus kas)
+// dikali porsi phasing bulan tersebut, sedangkan saldo neraca, saldo akhir, dan rasio tetap memakai nilai tahunan
+func performanceMonthlyRKAP(rkap *domain.FinancialReportModel, phasings []domain.FinancialRKAPPhasingModel, period string) *domain.FinancialReportModel {
+	if rkap == nil {
+		return nil
+	}
+	span, err := parseFinancialPeriod(period)
+	if err != nil || span.Type != domain.FinancialPeriodMonthly {
+		return rkap
+	}
+	monthly := *rkap
+	target := reflect.ValueOf(&monthly).Elem()
+	for _, field := range financialImportFields {
+		if financialFieldAggregation(field) != domain.FinancialAggregationSum {
+			continue
+		}
+		value := target.FieldByName(field.Field)
+		share := rkapPhasingShare(phasings, field.Key, span.Start, span.End)
+		value.SetInt(int64(math.Round(float64(value.Int()) * share)))
+	}
+	return &monthly
+}
+
+// performanceRKAPAt mengambil target RKAP baris ke-i; baris placeholder tanpa realisasi memakai RKAP tahunan
+func performanceRKAPAt(rkap *domain.FinancialReportModel, monthlyRKAP []*domain.FinancialReportModel, i int) *domain.FinancialReportModel {
+	if i < len(monthlyRKAP) {
+		return monthlyRKAP[i]
+	}
+	return rkap
+}
+
+// loadPerformanceData mengambil realisasi dalam rentang periode (tahun startPeriod) beserta RKAP tahun tersebut
+// (versi rkapVersion, atau versi approved terakhir jika nil). monthlyRKAP berindeks sama dengan reports dan
+// berisi target RKAP bulan tersebut sesuai phasing
+func (uc *financialReportUseCase) loadPerformanceData(companyID, startPeriod, endPeriod string, rkapVersion *int) ([]domain.FinancialReportModel, *domain.FinancialReportModel, []*domain.FinancialReportModel, error) {
+	// Validasi format period (YYYY-MM)
+	if len(startPeriod) != 7 || len(endPeriod) != 7 {
+		return nil, nil, nil, fmt.Errorf("invalid period format, expected YYYY-MM")
+	}
+
+	// Ambil semua financial reports untuk company
+	reports, err := uc.repo.GetByCompanyID(companyID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get financial reports: %w", err)
+	}
+
+	// Extract tahun dan bulan dari periods
+	startYear := startPeriod[:4]
+	startMonth := startPeriod[5:7]
+	endMonth := endPeriod[5:7]
+
+	rkapReport, err := uc.getRKAP(companyID, startYear, rkapVersion)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var phasings []domain.FinancialRKAPPhasingModel
+	if rkapReport != nil {
+		if phasings, err = uc.phasingRepo.GetByRKAPID(rkapReport.ID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, nil, fmt.Errorf("failed to get RKAP phasing: %w", err)
+		}
+	}
+
+	var filteredReports []domain.FinancialReportModel
+	for i := range reports {
+		report := reports[i]
+		if !isMonthlyRealisasi(&report) {
+			continue
+		}
+		// Ambil realisasi reports dalam range
+		if len(report.Period) < 7 {
+			continue
+		}
+		reportMonth := report.Period[5:7]
+		if report.Period[:4] == startYear && reportMonth >= startMonth && reportMonth <= endMonth {
+			filteredReports = append(filteredReports, report)
+		}
+	}
+
+	// Sort berdasarkan period
+	sort.Slice(filteredReports, func(i, j int) bool {
+		return filteredReports[i].Period < filteredReports[j].Period
+	})
+
+	var monthlyRKAP []*domain.FinancialReportModel
+	if rkapReport != nil {
+		monthlyRKAP = make([]*domain.FinancialReportModel, len(filteredReports))
+		for i := range filteredReports {
+			monthlyRKAP[i] = performanceMonthlyRKAP(rkapReport, phasings, filteredReports[i].Period)
+		}
+	}
+	return filteredReports, rkapReport, monthlyRKAP, nil
+}
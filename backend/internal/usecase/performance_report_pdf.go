@@ -0,0 +1,334 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/storage"
+	"go.uber.org/zap"
+)
+
+// Layout PDF performa (A4 landscape, satuan mm)
+const (
+	performancePDFMargin     = 10.0
+	performancePDFLogoSize   = 18.0
+	performancePDFRowHeight  = 6.0
+	performancePDFLogoImage  = "company-logo"
+	performancePDFSignHeight = 40.0
+)
+
+// Lebar kolom tabel: Bulan, Uraian, RKAP, Realisasi, Variance, Variance %
+var performancePDFColumns = []struct {
+	header string
+	width  float64
+	align  string
+}{
+	{"Bulan", 30, "L"},
+	{"Uraian", 77, "L"},
+	{"RKAP", 42, "R"},
+	{"Realisasi", 42, "R"},
+	{"Variance", 42, "R"},
+	{"Variance %", 44, "R"},
+}
+
+var performancePDFMonthNames = []string{"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"}
+
+// ExportPerformancePDF generates PDF dengan satu halaman per section (Balance Sheet, Profit & Loss, Cashflow, Ratio).
+// Setiap bulan menampilkan RKAP bulan tersebut (sesuai phasing), Realisasi, dan variance (Realisasi - RKAP) per item.
+func (uc *financialReportUseCase) ExportPerformancePDF(companyID, startPeriod, endPeriod string, rkapVersion *int) ([]byte, error) {
+	reports, rkap, monthlyRKAP, err := uc.loadPerformanceData(companyID, startPeriod, endPeriod, rkapVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	company, err := uc.companyRepo.GetByID(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get company: %w", err)
+	}
+
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	pdf.SetMargins(performancePDFMargin, performancePDFMargin, performancePDFMargin)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AliasNbPages("")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	hasLogo := registerPerformanceLogo(pdf, company.Logo)
+	printedAt := time.Now()
+
+	pdf.SetHeaderFunc(func() {
+		writePerformancePDFHeader(pdf, tr, company, hasLogo, startPeriod, endPeriod)
+	})
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont("Arial", "I", 8)
+		pdf.SetTextColor(110, 110, 110)
+		pdf.CellFormat(0, 5, tr(fmt.Sprintf("Dicetak %s", printedAt.Format("02-01-2006 15:04"))), "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 5, fmt.Sprintf("Halaman %d dari {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+
+	for _, section := range performanceSections() {
+		pdf.AddPage()
+		pdf.SetFont("Arial", "B", 12)
+		pdf.CellFormat(0, 8, tr(section.periodTitle(startPeriod, endPeriod)), "", 1, "L", false, 0, "")
+		if rkap == nil {
+			pdf.SetFont("Arial", "I", 9)
+			pdf.CellFormat(0, 5, tr(fmt.Sprintf("RKAP tahun %s belum tersedia, variance tidak dihitung", startPeriod[:4])), "", 1, "L", false, 0, "")
+		}
+		pdf.Ln(2)
+		writePerformancePDFTable(pdf, tr, section, reports, rkap, monthlyRKAP)
+	}
+
+	writePerformancePDFSignature(pdf, tr, company, printedAt)
+
+	if err := pdf.Error(); err != nil {
+		return nil, fmt.Errorf("failed to generate PDF: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to write PDF file: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// registerPerformanceLogo memuat logo company dari storage; logo yang gagal dibaca tidak menggagalkan export
+func registerPerformanceLogo(pdf *gofpdf.Fpdf, logoURL string) bool {
+	if logoURL == "" {
+		return false
+	}
+	zapLog := logger.GetLogger()
+
+	manager, err := storage.GetStorageManager()
+	if err != nil {
+		zapLog.Warn("Failed to initialize storage for company logo", zap.Error(err))
+		return false
+	}
+	data, err := storage.ReadFileByURL(manager, logoURL)
+	if err != nil {
+		zapLog.Warn("Failed to read company logo, exporting PDF without logo", zap.String("logo", logoURL), zap.Error(err))
+		return false
+	}
+
+	var imageType string
+	switch http.DetectContentType(data) {
+	case "image/png":
+		imageType = "PNG"
+	case "image/jpeg":
+		imageType = "JPG"
+	default:
+		zapLog.Warn("Unsupported company logo format for PDF", zap.String("logo", logoURL))
+		return false
+	}
+
+	pdf.RegisterImageOptionsReader(performancePDFLogoImage, gofpdf.ImageOptions{ImageType: imageType}, bytes.NewReader(data))
+	if err := pdf.Error(); err != nil {
+		// Gambar rusak membuat fpdf masuk error state, reset supaya PDF tetap bisa dibuat
+		zapLog.Warn("Failed to register company logo", zap.String("logo", logoURL), zap.Error(err))
+		pdf.ClearError()
+		return false
+	}
+	return true
+}
+
+// writePerformancePDFHeader menulis kop laporan di setiap halaman
+func writePerformancePDFHeader(pdf *gofpdf.Fpdf, tr func(string) string, company *domain.CompanyModel, hasLogo bool, startPeriod, endPeriod string) {
+	textX := performancePDFMargin
+	if hasLogo {
+		pdf.ImageOptions(performancePDFLogoImage, performancePDFMargin, performancePDFMargin, 0, performancePDFLogoSize, false, gofpdf.ImageOptions{}, 0, "")
+		textX += performancePDFLogoSize + 4
+	}
+
+	pdf.SetXY(textX, performancePDFMargin)
+	pdf.SetFont("Arial", "B", 14)
+	pdf.CellFormat(0, 7, tr(company.Name), "", 2, "L", false, 0, "")
+	pdf.SetFont("Arial", "", 9)
+	if company.Address != "" {
+		pdf.CellFormat(0, 5, tr(company.Address), "", 2, "L", false, 0, "")
+	}
+	pdf.CellFormat(0, 5, tr(fmt.Sprintf("Laporan Performa RKAP vs Realisasi | Periode %s s/d %s", startPeriod, endPeriod)), "", 2, "L", false, 0, "")
+
+	lineY := performancePDFMargin + performancePDFLogoSize + 2
+	if y := pdf.GetY() + 2; y > lineY {
+		lineY = y
+	}
+	pageWidth, _ := pdf.GetPageSize()
+	pdf.SetLineWidth(0.4)
+	pdf.Line(performancePDFMargin, lineY, pageWidth-performancePDFMargin, lineY)
+	pdf.SetLineWidth(0.2)
+	pdf.SetXY(performancePDFMargin, lineY+3)
+}
+
+// writePerformancePDFTable menulis tabel satu section: satu grup baris per bulan, satu baris per item
+func writePerformancePDFTable(pdf *gofpdf.Fpdf, tr func(string) string, section performanceSection, reports []domain.FinancialReportModel, rkap *domain.FinancialReportModel, monthlyRKAP []*domain.FinancialReportModel) {
+	writeHeader := func() {
+		pdf.SetFont("Arial", "B", 9)
+		pdf.SetFillColor(227, 242, 253)
+		for _, column := range performancePDFColumns {
+			pdf.CellFormat(column.width, 7, column.header, "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	writeHeader()
+
+	if len(reports) == 0 {
+		pdf.SetFont("Arial", "I", 9)
+		pdf.CellFormat(performancePDFTableWidth(), performancePDFRowHeight, tr("Belum ada data realisasi pada periode ini"), "1", 1, "C", false, 0, "")
+		return
+	}
+
+	_, pageHeight := pdf.GetPageSize()
+	_, _, _, bottomMargin := pdf.GetMargins()
+	for i := range reports {
+		report := &reports[i]
+		monthRKAP := performanceRKAPAt(rkap, monthlyRKAP, i)
+		// Grup bulan tidak dipecah ke dua halaman
+		groupHeight := float64(len(section.items)) * performancePDFRowHeight
+		if pdf.GetY()+groupHeight > pageHeight-bottomMargin-5 {
+			pdf.AddPage()
+			writeHeader()
+		}
+
+		for itemIdx, item := range section.items {
+			monthLabel := ""
+			border := "LR"
+			if itemIdx == 0 {
+				monthLabel = performancePDFMonthName(report.Period)
+				border = "LTR"
+			}
+			if itemIdx == len(section.items)-1 {
+				border += "B"
+			}
+
+			rkapText, realText, diffText, percentText := performancePDFValues(item, monthRKAP, report)
+			values := []string{monthLabel, item.label, rkapText, realText, diffText, percentText}
+
+			diff, _, ok := performanceVariance(item, monthRKAP, report)
+			for colIdx, column := range performancePDFColumns {
+				cellBorder := "1"
+				style := ""
+				if colIdx == 0 {
+					cellBorder = border
+					style = "B"
+				}
+				pdf.SetFont("Arial", style, 8)
+				if colIdx >= 4 && ok && diff < 0 {
+					pdf.SetTextColor(198, 40, 40)
+				}
+				pdf.CellFormat(column.width, performancePDFRowHeight, tr(values[colIdx]), cellBorder, 0, column.align, false, 0, "")
+				pdf.SetTextColor(0, 0, 0)
+			}
+			pdf.Ln(-1)
+		}
+	}
+}
+
+// performancePDFValues memformat nilai RKAP, Realisasi, dan variance dengan formatter yang sama seperti Excel
+func performancePDFValues(item tableItem, rkap, report *domain.FinancialReportModel) (rkapText, realText, diffText, percentText string) {
+	rkapText, realText, diffText, percentText = "-", "-", "-", "-"
+	if item.isRatio {
+		if rkap != nil && item.getRkapF != nil {
+			rkapText = formatRatioValue(item.getRkapF(rkap))
+		}
+		if report != nil && item.getRealF != nil {
+			realText = formatRatioValue(item.getRealF(*report))
+		}
+	} else {
+		if rkap != nil && item.getRkap != nil {
+			rkapText = formatCurrencyValue(item.getRkap(rkap))
+		}
+		if report != nil && item.getReal != nil {
+			realText = formatCurrencyValue(item.getReal(*report))
+		}
+	}
+
+	diff, percent, ok := performanceVariance(item, rkap, report)
+	if !ok {
+		return
+	}
+	if item.isRatio {
+		diffText = formatRatioValue(diff)
+	} else {
+		diffText = formatCurrencyValue(int64(diff))
+	}
+	if percent != nil {
+		percentText = fmt.Sprintf("%.2f%%", *percent)
+	}
+	return
+}
+
+// writePerformancePDFSignature menulis blok tanda tangan di akhir dokumen (pindah halaman jika tidak cukup)
+func writePerformancePDFSignature(pdf *gofpdf.Fpdf, tr func(string) string, company *domain.CompanyModel, printedAt time.Time) {
+	_, pageHeight := pdf.GetPageSize()
+	_, _, _, bottomMargin := pdf.GetMargins()
+	if pdf.GetY()+performancePDFSignHeight > pageHeight-bottomMargin {
+		pdf.AddPage()
+	}
+	pdf.Ln(8)
+
+	blockWidth := 80.0
+	leftX := performancePDFMargin + 10
+	rightX := performancePDFMargin + performancePDFTableWidth() - blockWidth - 10
+	startY := pdf.GetY()
+
+	pdf.SetFont("Arial", "", 9)
+	pdf.SetXY(rightX, startY)
+	pdf.CellFormat(blockWidth, 5, tr(fmt.Sprintf("Tanggal %d %s %d", printedAt.Day(), performancePDFMonthNames[printedAt.Month()-1], printedAt.Year())), "", 0, "C", false, 0, "")
+
+	signers := []struct {
+		x        float64
+		caption  string
+		position string
+	}{
+		{leftX, "Disiapkan oleh,", "Direktur Keuangan"},
+		{rightX, "Disetujui oleh,", "Direktur Utama"},
+	}
+	for _, signer := range signers {
+		name := findDirectorName(company, signer.position)
+		if name == "" {
+			name = "(..............................)"
+		}
+		pdf.SetXY(signer.x, startY+6)
+		pdf.SetFont("Arial", "", 9)
+		pdf.CellFormat(blockWidth, 5, tr(signer.caption), "", 2, "C", false, 0, "")
+		pdf.CellFormat(blockWidth, 5, tr(signer.position), "", 2, "C", false, 0, "")
+		pdf.SetXY(signer.x, startY+32)
+		pdf.SetFont("Arial", "BU", 9)
+		pdf.CellFormat(blockWidth, 5, tr(name), "", 0, "C", false, 0, "")
+	}
+}
+
+// findDirectorName mencari nama pengurus berdasarkan jabatan (case-insensitive)
+func findDirectorName(company *domain.CompanyModel, position string) string {
+	for _, director := range company.Directors {
+		if strings.EqualFold(strings.TrimSpace(director.Position), position) {
+			return director.FullName
+		}
+	}
+	return ""
+}
+
+func performancePDFMonthName(period string) string {
+	if len(period) < 7 {
+		return period
+	}
+	var month int
+	_, _ = fmt.Sscanf(period[5:7], "%d", &month)
+	if month < 1 || month > 12 {
+		return period
+	}
+	return fmt.Sprintf("%s %s", performancePDFMonthNames[month-1], period[:4])
+}
+
+func performancePDFTableWidth() float64 {
+	width := 0.0
+	for _, column := range performancePDFColumns {
+		width += column.width
+	}
+	return width
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"github.com/repoareta/pedeve-dms-app/backend/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+// TestExportPerformance tests export performa Excel dan PDF dari definisi section yang sama
+func TestExportPerformance(t *testing.T) {
+	db := helpers.SetupTestDB(t)
+	defer helpers.CleanupTestDB(t, db)
+
+	company := createTestCompanyForNotification(t, db, nil)
+	require.NoError(t, db.Create(&domain.DirectorModel{
+		ID:        uuid.GenerateUUID(),
+		CompanyID: company.ID,
+		Position:  "Direktur Utama",
+		FullName:  "Budi Santoso",
+	}).Error)
+
+	rkap := &domain.FinancialReportModel{ID: uuid.GenerateUUID(), CompanyID: company.ID, Year: "2025", IsRKAP: true, Revenue: 12000, Equity: 5000, ROE: 10}
+	require.NoError(t, db.Create(rkap).Error)
+	createTestFinancialReportForImport(t, db, company.ID, "2025-01", 800)
+	createTestFinancialReportForImport(t, db, company.ID, "2025-02", 1200)
+	createTestFinancialReportForImport(t, db, company.ID, "2025-04", 500) // Di luar periode
+
+	uc := NewFinancialReportUseCaseWithDB(db)
+
+	t.Run("Excel sheets follow shared definitions", func(t *testing.T) {
+		data, err := uc.ExportPerformanceExcel(company.ID, "2025-01", "2025-02", nil)
+		require.NoError(t, err)
+
+		f, err := excelize.OpenReader(bytes.NewReader(data))
+		require.NoError(t, err)
+		defer f.Close()
+
+		for _, section := range performanceSections() {
+			assert.Contains(t, f.GetSheetList(), section.sheetName)
+			title, err := f.GetCellValue(section.sheetName, "A1")
+			require.NoError(t, err)
+			assert.Equal(t, section.periodTitle("2025-01", "2025-02"), title)
+			header, err := f.GetCellValue(section.sheetName, "B3")
+			require.NoError(t, err)
+			assert.Equal(t, section.items[0].label, header)
+		}
+	})
+
+	t.Run("PDF export", func(t *testing.T) {
+		data, err := uc.ExportPerformancePDF(company.ID, "2025-01", "2025-02", nil)
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(data, []byte("%PDF")))
+
+		_, err = uc.ExportPerformancePDF(company.ID, "2025-1", "2025-02", nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("Variance", func(t *testing.T) {
+		reports, loadedRKAP, monthlyRKAP, err := uc.(*financialReportUseCase).loadPerformanceData(company.ID, "2025-01", "2025-02", nil)
+		require.NoError(t, err)
+		require.Len(t, reports, 2)
+		require.NotNil(t, loadedRKAP)
+		require.Len(t, monthlyRKAP, 2)
+
+		// Tanpa phasing RKAP arus dibagi rata per bulan: target Februari 12000/12 = 1000
+		revenue := getPerformanceSection(performanceSectionProfitLoss).items[0]
+		rkapText, _, diffText, percentText := performancePDFValues(revenue, monthlyRKAP[1], &reports[1])
+		assert.Equal(t, formatCurrencyValue(1000), rkapText)
+		assert.Equal(t, formatCurrencyValue(200), diffText)
+		assert.Equal(t, "20.00%", percentText)
+
+		// Saldo neraca dan rasio tetap dibandingkan dengan nilai tahunan
+		equity := getPerformanceSection(performanceSectionBalanceSheet).items[4]
+		rkapText, _, _, _ = performancePDFValues(equity, monthlyRKAP[0], &reports[0])
+		assert.Equal(t, formatCurrencyValue(5000), rkapText)
+		roe := getPerformanceSection(performanceSectionRatio).items[0]
+		rkapText, _, _, _ = performancePDFValues(roe, monthlyRKAP[0], &reports[0])
+		assert.Equal(t, formatRatioValue(10), rkapText)
+
+		// Tanpa RKAP variance tidak dihitung
+		_, _, diffText, _ = performancePDFValues(revenue, nil, &reports[0])
+		assert.Equal(t, "-", diffText)
+	})
+
+	t.Run("Variance follows RKAP phasing", func(t *testing.T) {
+		_, err := uc.SetRKAPPhasing(rkap.ID, &domain.SetRKAPPhasingRequest{
+			Granularity: domain.FinancialPeriodQuarterly,
+			Weights: []domain.RKAPPhasingWeight{
+				{Period: "2025-Q1", Weight: 15}, {Period: "2025-Q2", Weight: 25},
+				{Period: "2025-Q3", Weight: 25}, {Period: "2025-Q4", Weight: 35},
+			},
+		}, "", "", "", "")
+		require.NoError(t, err)
+
+		reports, _, monthlyRKAP, err := uc.(*financialReportUseCase).loadPerformanceData(company.ID, "2025-01", "2025-02", nil)
+		require.NoError(t, err)
+		require.Len(t, monthlyRKAP, 2)
+
+		// Target Januari = 15% x 12000 / 3 bulan = 600
+		revenue := getPerformanceSection(performanceSectionProfitLoss).items[0]
+		rkapText, _, diffText, percentText := performancePDFValues(revenue, monthlyRKAP[0], &reports[0])
+		assert.Equal(t, formatCurrencyValue(600), rkapText)
+		assert.Equal(t, formatCurrencyValue(200), diffText)
+		assert.Equal(t, "33.33%", percentText)
+	})
+}
@@ -45,10 +45,13 @@ func NewReportUseCase() ReportUseCase {
 
 func (uc *reportUseCase) CreateReport(data *domain.CreateReportRequest) (*domain.ReportModel, error) {
 	// Validate company exists
-	_, err := uc.companyRepo.GetByID(data.CompanyID)
+	company, err := uc.companyRepo.GetByID(data.CompanyID)
 	if err != nil {
 		return nil, errors.New("company not found")
 	}
+	if err := ensureCompanyNotDissolved(company); err != nil {
+		return nil, err
+	}
 
 	// Validasi inputter kalau diisi
 	if data.InputterID != nil && *data.InputterID != "" {
@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/audit"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/logger"
+	"github.com/repoareta/pedeve-dms-app/backend/internal/infrastructure/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRKAPVersionNotFound  = errors.New("versi RKAP tidak ditemukan")
+	ErrRKAPRevisionNotDraft = errors.New("revisi RKAP yang sudah approved/rejected tidak dapat diubah")
+)
+
+// isApprovedRKAP true untuk RKAP yang boleh dipakai sebagai pembanding default (RKAP awal atau revisi approved)
+func isApprovedRKAP(report *domain.FinancialReportModel) bool {
+	return report.IsRKAP && (report.RKAPVersion == 0 || report.RKAPStatus == domain.RKAPStatusApproved)
+}
+
+// getRKAP mengambil RKAP versi tertentu, atau versi approved terakhir jika version nil.
+// RKAP yang belum ada dikembalikan nil tanpa error, kecuali versi yang diminta eksplisit tidak ditemukan.
+func (uc *financialReportUseCase) getRKAP(companyID, year string, version *int) (*domain.FinancialReportModel, error) {
+	if version != nil {
+		rkap, err := uc.repo.GetRKAPVersion(companyID, year, *version)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: versi %d tahun %s", ErrRKAPVersionNotFound, *version, year)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get RKAP: %w", err)
+		}
+		return rkap, nil
+	}
+
+	rkap, err := uc.repo.GetRKAPByCompanyIDAndYear(companyID, year)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get RKAP: %w", err)
+	}
+	return rkap, nil
+}
+
+func (uc *financialReportUseCase) GetRKAPVersions(companyID, year string) ([]domain.FinancialReportModel, error) {
+	return uc.repo.GetRKAPVersions(companyID, year)
+}
+
+func (uc *financialReportUseCase) CreateRKAPRevision(companyID, year string, data *domain.CreateRKAPRevisionRequest, userID, username, ipAddress, userAgent string) (*domain.FinancialReportModel, error) {
+	zapLog := logger.GetLogger()
+
+	if financialRatioUpdateExceedsLimit(&data.UpdateFinancialReportRequest) {
+		return nil, errors.New("nilai rasio keuangan tidak boleh melebihi 100%")
+	}
+
+	if err := ensureCompanyIDNotDissolved(uc.companyRepo, companyID); err != nil {
+		return nil, err
+	}
+
+	effectiveDate, err := time.Parse("2006-01-02", data.EffectiveDate)
+	if err != nil {
+		return nil, errors.New("effective_date harus format YYYY-MM-DD")
+	}
+	if effectiveDate.Format("2006") != year {
+		return nil, fmt.Errorf("effective_date harus berada di tahun RKAP %s", year)
+	}
+
+	versions, err := uc.repo.GetRKAPVersions(companyID, year)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get RKAP versions: %w", err)
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("%w: RKAP awal tahun %s belum ada", ErrRKAPVersionNotFound, year)
+	}
+
+	// Satu revisi draft per tahun, revisi baru dibuat dari versi approved terakhir
+	var base *domain.FinancialReportModel
+	for i := range versions {
+		if versions[i].RKAPStatus == domain.RKAPStatusDraft {
+			return nil, fmt.Errorf("revisi RKAP versi %d masih draft, approve atau reject terlebih dahulu", versions[i].RKAPVersion)
+		}
+		if isApprovedRKAP(&versions[i]) {
+			base = &versions[i]
+		}
+	}
+	if base == nil {
+		base = &versions[0]
+	}
+	last := versions[len(versions)-1]
+
+	revision := *base
+	revision.ID = uuid.GenerateUUID()
+	revision.RKAPVersion = last.RKAPVersion + 1
+	revision.RKAPStatus = domain.RKAPStatusDraft
+	revision.EffectiveDate = &effectiveDate
+	revision.RevisionReason = data.Reason
+	revision.ApprovedBy = nil
+	revision.ApprovedAt = nil
+	revision.InputterID = nil
+	revision.CreatedAt = time.Time{}
+	revision.UpdatedAt = time.Time{}
+	revision.Company = nil
+	revision.Inputter = nil
+	if userID != "" {
+		revision.InputterID = &userID
+	}
+	applyFinancialReportUpdate(&revision, &data.UpdateFinancialReportRequest)
+
+	if err := uc.repo.Create(&revision); err != nil {
+		zapLog.Error("Failed to create RKAP revision", zap.Error(err))
+		return nil, fmt.Errorf("failed to create RKAP revision: %w", err)
+	}
+
+	// Phasing versi dasar ikut disalin, bisa diubah selama revisi masih draft
+	phasings, err := uc.phasingRepo.GetByRKAPID(base.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get RKAP phasing: %w", err)
+	}
+	for i := range phasings {
+		phasings[i].ID = uuid.GenerateUUID()
+		phasings[i].RKAPID = revision.ID
+		phasings[i].CreatedAt = time.Time{}
+	}
+	if err := uc.phasingRepo.ReplaceForRKAP(revision.ID, phasings); err != nil {
+		return nil, fmt.Errorf("failed to copy RKAP phasing: %w", err)
+	}
+
+	audit.LogChanges(userID, username, audit.ActionCreateRKAPRevision, audit.ResourceFinancialReport, revision.ID, ipAddress, userAgent, audit.DiffModels(base, &revision), map[string]interface{}{
+		"company_id":     companyID,
+		"year":           year,
+		"rkap_version":   revision.RKAPVersion,
+		"base_version":   base.RKAPVersion,
+		"effective_date": data.EffectiveDate,
+	})
+
+	return &revision, nil
+}
+
+func (uc *financialReportUseCase) ApproveRKAPRevision(id string, userID, username, ipAddress, userAgent string) (*domain.FinancialReportModel, error) {
+	return uc.reviewRKAPRevision(id, domain.RKAPStatusApproved, audit.ActionApproveRKAPRevision, userID, username, ipAddress, userAgent)
+}
+
+func (uc *financialReportUseCase) RejectRKAPRevision(id string, userID, username, ipAddress, userAgent string) (*domain.FinancialReportModel, error) {
+	return uc.reviewRKAPRevision(id, domain.RKAPStatusRejected, audit.ActionRejectRKAPRevision, userID, username, ipAddress, userAgent)
+}
+
+// reviewRKAPRevision mengubah status revisi draft menjadi approved/rejected
+func (uc *financialReportUseCase) reviewRKAPRevision(id, status, action string, userID, username, ipAddress, userAgent string) (*domain.FinancialReportModel, error) {
+	report, err := uc.repo.GetByID(id)
+	if err != nil || !report.IsRKAP || report.RKAPVersion == 0 {
+		return nil, ErrRKAPVersionNotFound
+	}
+	if report.RKAPStatus != domain.RKAPStatusDraft {
+		return nil, ErrRKAPRevisionNotDraft
+	}
+
+	before := *report
+	now := time.Now()
+	report.RKAPStatus = status
+	report.ApprovedAt = &now
+	report.ApprovedBy = nil
+	if userID != "" {
+		report.ApprovedBy = &userID
+	}
+	if err := uc.repo.Update(report); err != nil {
+		return nil, fmt.Errorf("failed to update RKAP revision: %w", err)
+	}
+
+	audit.LogChanges(userID, username, action, audit.ResourceFinancialReport, report.ID, ipAddress, userAgent, audit.DiffModels(&before, report), map[string]interface{}{
+		"company_id":   report.CompanyID,
+		"year":         report.Year,
+		"rkap_version": report.RKAPVersion,
+	})
+
+	return report, nil
+}
+
+func (uc *financialReportUseCase) DiffRKAPVersions(companyID, year string, fromVersion, toVersion *int) (*domain.RKAPRevisionDiffResponse, error) {
+	versions, err := uc.repo.GetRKAPVersions(companyID, year)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get RKAP versions: %w", err)
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("%w: RKAP tahun %s belum ada", ErrRKAPVersionNotFound, year)
+	}
+
+	byVersion := make(map[int]*domain.FinancialReportModel, len(versions))
+	for i := range versions {
+		byVersion[versions[i].RKAPVersion] = &versions[i]
+	}
+
+	// Default: versi terakhir dibandingkan dengan versi tepat sebelumnya
+	to := versions[len(versions)-1].RKAPVersion
+	if toVersion != nil {
+		to = *toVersion
+	}
+	from := to - 1
+	if fromVersion != nil {
+		from = *fromVersion
+	}
+	if to == 0 && fromVersion == nil {
+		from = 0
+	}
+	fromReport, toReport := byVersion[from], byVersion[to]
+	if fromReport == nil {
+		return nil, fmt.Errorf("%w: versi %d tahun %s", ErrRKAPVersionNotFound, from, year)
+	}
+	if toReport == nil {
+		return nil, fmt.Errorf("%w: versi %d tahun %s", ErrRKAPVersionNotFound, to, year)
+	}
+
+	response := &domain.RKAPRevisionDiffResponse{
+		CompanyID: companyID,
+		Year:      year,
+		From:      fromReport,
+		To:        toReport,
+		Lines:     make([]domain.RKAPRevisionDiffLine, 0, len(financialImportFields)),
+	}
+	for _, field := range financialImportFields {
+		fromValue := financialMetricValue(fromReport, field)
+		toValue := financialMetricValue(toReport, field)
+		line := domain.RKAPRevisionDiffLine{
+			Key:     field.Key,
+			Label:   field.Header,
+			Section: field.Section,
+			Unit:    field.Unit(),
+			From:    fromValue,
+			To:      toValue,
+			Change:  toValue - fromValue,
+			Changed: toValue != fromValue,
+		}
+		if fromValue != 0 {
+			line.ChangePercent = roundedPtr((toValue - fromValue) / math.Abs(fromValue) * 100)
+		}
+		if line.Changed {
+			response.ChangedLines++
+		}
+		response.Lines = append(response.Lines, line)
+	}
+	return response, nil
+}
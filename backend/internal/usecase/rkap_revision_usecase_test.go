@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/repoareta/pedeve-dms-app/backend/internal/domain"
+	"github.com/repoareta/pedeve-dms-app/backend/test/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRKAPRevision tests RKAP Perubahan: versi draft/approved, pemilihan versi pembanding, dan diff antar versi
+func TestRKAPRevision(t *testing.T) {
+	db := helpers.SetupTestDB(t)
+	defer helpers.CleanupTestDB(t, db)
+
+	company := createTestCompanyForNotification(t, db, nil)
+	uc := NewFinancialReportUseCaseWithDB(db)
+
+	original, err := uc.CreateFinancialReport(&domain.CreateFinancialReportRequest{
+		CompanyID: company.ID, Year: "2025", Period: "2025", IsRKAP: true, Revenue: 1200, NetProfit: 300,
+	}, "user-1", "tester", "127.0.0.1", "test")
+	require.NoError(t, err)
+	assert.Equal(t, domain.RKAPStatusApproved, original.RKAPStatus)
+	createTestFinancialReportForImport(t, db, company.ID, "2025-01", 150)
+
+	revenue := int64(1500)
+	revision, err := uc.CreateRKAPRevision(company.ID, "2025", &domain.CreateRKAPRevisionRequest{
+		EffectiveDate:                "2025-07-01",
+		Reason:                       stringPtr("Penyesuaian target semester II"),
+		UpdateFinancialReportRequest: domain.UpdateFinancialReportRequest{Revenue: &revenue},
+	}, "user-1", "tester", "127.0.0.1", "test")
+	require.NoError(t, err)
+	assert.Equal(t, 1, revision.RKAPVersion)
+	assert.Equal(t, domain.RKAPStatusDraft, revision.RKAPStatus)
+	assert.Equal(t, int64(300), revision.NetProfit) // Disalin dari versi sebelumnya
+
+	t.Run("Draft is not the default comparison", func(t *testing.T) {
+		comparison, err := uc.GetComparison(company.ID, "2025", "01", nil)
+		require.NoError(t, err)
+		assert.Equal(t, original.ID, comparison.RKAP.ID)
+
+		version := 1
+		comparison, err = uc.GetComparison(company.ID, "2025", "01", &version)
+		require.NoError(t, err)
+		assert.Equal(t, revision.ID, comparison.RKAP.ID)
+
+		missing := 9
+		_, err = uc.GetComparison(company.ID, "2025", "01", &missing)
+		assert.ErrorIs(t, err, ErrRKAPVersionNotFound)
+
+		_, err = uc.CreateRKAPRevision(company.ID, "2025", &domain.CreateRKAPRevisionRequest{EffectiveDate: "2025-08-01"}, "user-1", "tester", "", "")
+		assert.Error(t, err) // Masih ada draft
+	})
+
+	t.Run("Approved revision becomes the default", func(t *testing.T) {
+		approved, err := uc.ApproveRKAPRevision(revision.ID, "approver", "approver", "", "")
+		require.NoError(t, err)
+		assert.Equal(t, domain.RKAPStatusApproved, approved.RKAPStatus)
+		require.NotNil(t, approved.ApprovedBy)
+
+		comparison, err := uc.GetComparison(company.ID, "2025", "01", nil)
+		require.NoError(t, err)
+		assert.Equal(t, revision.ID, comparison.RKAP.ID)
+		assert.InDelta(t, 10, comparison.Comparison["revenue"].Percentage, 0.001)
+
+		_, loadedRKAP, _, err := uc.(*financialReportUseCase).loadPerformanceData(company.ID, "2025-01", "2025-01", nil)
+		require.NoError(t, err)
+		assert.Equal(t, revision.ID, loadedRKAP.ID)
+
+		// Revisi approved tidak bisa diubah atau dihapus, RKAP awal tidak bisa dihapus
+		_, err = uc.RejectRKAPRevision(revision.ID, "approver", "approver", "", "")
+		assert.ErrorIs(t, err, ErrRKAPRevisionNotDraft)
+		_, err = uc.UpdateFinancialReport(revision.ID, &domain.UpdateFinancialReportRequest{Revenue: &revenue}, "user-1", "tester", "", "")
+		assert.ErrorIs(t, err, ErrRKAPRevisionNotDraft)
+		assert.Error(t, uc.DeleteFinancialReport(revision.ID, "user-1", "tester", "", ""))
+		assert.Error(t, uc.DeleteFinancialReport(original.ID, "user-1", "tester", "", ""))
+
+		// Daftar report company tetap hanya berisi RKAP awal
+		reports, err := uc.GetFinancialReportsByCompanyID(company.ID)
+		require.NoError(t, err)
+		assert.Len(t, reports, 2)
+	})
+
+	t.Run("Diff line by line", func(t *testing.T) {
+		diff, err := uc.DiffRKAPVersions(company.ID, "2025", nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 0, diff.From.RKAPVersion)
+		assert.Equal(t, 1, diff.To.RKAPVersion)
+		assert.Equal(t, 1, diff.ChangedLines)
+		for _, line := range diff.Lines {
+			if line.Key == "revenue" {
+				assert.True(t, line.Changed)
+				assert.InDelta(t, 300, line.Change, 0.001)
+				assert.InDelta(t, 25, *line.ChangePercent, 0.001)
+			} else {
+				assert.False(t, line.Changed, line.Key)
+			}
+		}
+
+		missing := 5
+		_, err = uc.DiffRKAPVersions(company.ID, "2025", nil, &missing)
+		assert.ErrorIs(t, err, ErrRKAPVersionNotFound)
+	})
+}
@@ -1,3 +1,5 @@
+// Entrypoint lama (legacy). Server yang di-build Dockerfile, air, dan docker-compose adalah cmd/api,
+// jadi route baru hanya didaftarkan di cmd/api/main.go.
 package main
 
 import (
@@ -35,10 +35,15 @@ func SetupTestDB(t *testing.T) *gorm.DB {
 		&domain.UserCompanyAssignmentModel{},
 		&domain.AuditLog{},
 		&domain.UserActivityLog{},
-		&domain.ReportModel{},
+		&domain.ReportModel{},          // Tabel lama, hanya untuk test migrasi legacy reports
+		&domain.FinancialReportModel{}, // Sumber data /reports
+		&domain.FinancialRKAPPhasingModel{},
 		&domain.DocumentFolderModel{},
 		&domain.DocumentModel{},
 		&domain.NotificationSettingsModel{},
+		&domain.DirectorTermModel{},
+		&domain.CompanyRestructuringEventModel{},
+		&domain.CompanyHierarchyModel{},
 	)
 	if err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
@@ -76,6 +81,8 @@ func SetupTestDBPostgres(t *testing.T) *gorm.DB {
 		&domain.AuditLog{},
 		&domain.UserActivityLog{},
 		&domain.ReportModel{},
+		&domain.FinancialReportModel{},
+		&domain.CompanyHierarchyModel{},
 	)
 	if err != nil {
 		t.Fatalf("Failed to migrate test PostgreSQL database: %v", err)
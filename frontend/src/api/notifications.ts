@@ -110,7 +110,7 @@ export interface NotificationSettings {
   user_id: string
   email_enabled: boolean
   in_app_enabled: boolean
-  expiry_threshold_days: number
+  expiry_threshold_days: number | null // null = belum diatur user, ikut default company/sistem
   created_at: string
   updated_at: string
 }